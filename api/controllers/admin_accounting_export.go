@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+)
+
+type accountingExportRepository interface {
+	StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(internalorders.AccountingExportLine) error) error
+}
+
+// accountingExportColumns follows the QuickBooks invoice import layout: one row per line item, with
+// the invoice-level fields repeated on every row of the same invoice.
+var accountingExportColumns = []string{
+	"InvoiceNo",
+	"Customer",
+	"InvoiceDate",
+	"Vendor",
+	"Item(Product/Service)",
+	"ItemDescription",
+	"ItemQuantity",
+	"ItemRate",
+	"ItemAmount",
+	"Discount",
+	"TaxAmount",
+	"Shipping",
+	"InvoiceTotal",
+	"Currency",
+}
+
+// accountingExportFlushEvery bounds how many rows are buffered before they are sent to the client.
+const accountingExportFlushEvery = 100
+
+// AdminOrdersAccountingExport streams the orders created in [from, to) as a QuickBooks-compatible
+// CSV. Both bounds accept RFC3339 timestamps or YYYY-MM-DD dates (midnight UTC).
+func AdminOrdersAccountingExport(repo accountingExportRepository, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if repo == nil {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders repository unavailable"))
+			return
+		}
+
+		from, err := parseDateBound(r.URL.Query().Get("from"), "from")
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+		to, err := parseDateBound(r.URL.Query().Get("to"), "to")
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+		if !to.After(from) {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeValidation, "to must be after from"))
+			return
+		}
+
+		logFailure := func(msg string, err error) {
+			if logg != nil {
+				logg.Error(ctx, msg, err)
+			}
+		}
+
+		// Headers are only committed once there is something to send, so a failing query still
+		// returns a regular JSON error.
+		writer := csv.NewWriter(w)
+		started := false
+		rows := 0
+		start := func() error {
+			started = true
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="orders-%s-%s.csv"`, from.Format("20060102"), to.Format("20060102")))
+			w.WriteHeader(http.StatusOK)
+			return writer.Write(accountingExportColumns)
+		}
+
+		err = repo.StreamAccountingExport(ctx, from, to, func(line internalorders.AccountingExportLine) error {
+			if !started {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			if err := writer.Write(accountingExportRow(line)); err != nil {
+				return err
+			}
+			rows++
+			if rows%accountingExportFlushEvery == 0 {
+				writer.Flush()
+				if flusher, ok := w.(http.Flusher); ok {
+					flusher.Flush()
+				}
+			}
+			return writer.Error()
+		})
+		if err != nil && !started {
+			responses.WriteError(ctx, logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "export orders"))
+			return
+		}
+		if err != nil {
+			// The status line is already out; the truncated file is the only signal the client gets.
+			logFailure("orders.accounting_export.stream_failed", err)
+			writer.Flush()
+			return
+		}
+		if !started {
+			if err := start(); err != nil {
+				logFailure("orders.accounting_export.write_failed", err)
+				return
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			logFailure("orders.accounting_export.write_failed", err)
+		}
+	}
+}
+
+func accountingExportRow(line internalorders.AccountingExportLine) []string {
+	return []string{
+		strconv.FormatInt(line.OrderNumber, 10),
+		line.BuyerName,
+		line.OrderCreatedAt.UTC().Format("01/02/2006"),
+		line.VendorName,
+		line.ItemName,
+		line.ItemCategory,
+		strconv.Itoa(line.Qty),
+		formatExportCents(line.UnitPriceCents),
+		formatExportCents(line.LineTotalCents),
+		formatExportCents(line.DiscountsCents),
+		formatExportCents(line.TaxCents),
+		formatExportCents(line.TransportFeeCents),
+		formatExportCents(line.OrderTotalCents),
+		string(line.Currency),
+	}
+}
+
+// formatExportCents renders cents as a plain decimal amount (1234 -> "12.34"), which spreadsheet
+// and accounting imports read without locale surprises.
+func formatExportCents(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// parseDateBound reads an RFC3339 timestamp or a YYYY-MM-DD date (midnight UTC) for a required
+// range bound.
+func parseDateBound(value, field string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("%s is required", field))
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, pkgerrors.Wrap(pkgerrors.CodeValidation, err, fmt.Sprintf("invalid %s", field))
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+type stubAccountingExportRepo struct {
+	lines    []internalorders.AccountingExportLine
+	err      error
+	from, to time.Time
+}
+
+func (s *stubAccountingExportRepo) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(internalorders.AccountingExportLine) error) error {
+	s.from, s.to = from, to
+	if s.err != nil {
+		return s.err
+	}
+	for _, line := range s.lines {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAdminOrdersAccountingExportWritesQuickBooksColumns(t *testing.T) {
+	reference := "PO-7"
+	repo := &stubAccountingExportRepo{lines: []internalorders.AccountingExportLine{{
+		OrderID:           uuid.New(),
+		OrderNumber:       1042,
+		OrderReference:    &reference,
+		OrderCreatedAt:    time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC),
+		Currency:          enums.CurrencyUSD,
+		BuyerName:         "Green Leaf, LLC",
+		VendorName:        "Farm Co",
+		ItemName:          "Blue Dream 3.5g",
+		ItemCategory:      "flower",
+		Qty:               3,
+		UnitPriceCents:    1250,
+		LineTotalCents:    3750,
+		DiscountsCents:    50,
+		TaxCents:          309,
+		TransportFeeCents: 500,
+		OrderTotalCents:   4509,
+	}}}
+
+	handler := AdminOrdersAccountingExport(repo, nil)
+	req := httptest.NewRequest(http.MethodGet, "/?from=2026-03-01&to=2026-04-01T00:00:00Z", nil)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	if got := resp.Header().Get("Content-Disposition"); got != `attachment; filename="orders-20260301-20260401.csv"` {
+		t.Fatalf("unexpected content disposition %q", got)
+	}
+	if !repo.from.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !repo.to.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window %s - %s", repo.from, repo.to)
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	wantHeader := []string{"InvoiceNo", "Customer", "InvoiceDate", "Vendor", "Item(Product/Service)", "ItemDescription", "ItemQuantity", "ItemRate", "ItemAmount", "Discount", "TaxAmount", "Shipping", "InvoiceTotal", "Currency"}
+	wantRow := []string{"1042", "Green Leaf, LLC", "03/04/2026", "Farm Co", "Blue Dream 3.5g", "flower", "3", "12.50", "37.50", "0.50", "3.09", "5.00", "45.09", "USD"}
+	if len(records) != 2 || !reflect.DeepEqual(records[0], wantHeader) || !reflect.DeepEqual(records[1], wantRow) {
+		t.Fatalf("unexpected csv %q", records)
+	}
+}
+
+func TestAdminOrdersAccountingExportWritesHeaderForEmptyWindow(t *testing.T) {
+	handler := AdminOrdersAccountingExport(&stubAccountingExportRepo{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/?from=2026-03-01&to=2026-03-02", nil)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if resp.Code != http.StatusOK || len(records) != 1 || len(records[0]) != len(accountingExportColumns) {
+		t.Fatalf("expected header-only csv, got %d %q", resp.Code, records)
+	}
+}
+
+func TestAdminOrdersAccountingExportValidatesWindow(t *testing.T) {
+	for _, query := range []string{"", "?from=2026-03-01", "?from=yesterday&to=2026-03-02", "?from=2026-03-02&to=2026-03-01"} {
+		resp := httptest.NewRecorder()
+		AdminOrdersAccountingExport(&stubAccountingExportRepo{}, nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/"+query, nil))
+		if resp.Code != http.StatusBadRequest {
+			t.Fatalf("%q: expected 400 got %d", query, resp.Code)
+		}
+	}
+}
+
+func TestAdminOrdersAccountingExportReportsQueryFailure(t *testing.T) {
+	resp := httptest.NewRecorder()
+	repo := &stubAccountingExportRepo{err: errors.New("db down")}
+	AdminOrdersAccountingExport(repo, nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?from=2026-03-01&to=2026-03-02", nil))
+	if resp.Code == http.StatusOK || resp.Header().Get("Content-Type") == "text/csv; charset=utf-8" {
+		t.Fatalf("expected an error response, got %d %q", resp.Code, resp.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/internal/cron"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// CronJobRuns queues cron jobs for the cron worker to run on demand and reports their progress.
+type CronJobRuns interface {
+	Request(ctx context.Context, job string, requestedBy uuid.UUID) (cron.JobRun, error)
+	Get(ctx context.Context, id uuid.UUID) (cron.JobRun, error)
+}
+
+// AdminCronJobRun queues one cron job for the cron worker and returns the run to poll.
+func AdminCronJobRun(runs CronJobRuns, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if runs == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "cron job runs unavailable"))
+			return
+		}
+
+		name := strings.TrimSpace(chi.URLParam(r, "job"))
+		if name == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "job name is required"))
+			return
+		}
+
+		var requestedBy uuid.UUID
+		if raw := strings.TrimSpace(middleware.UserIDFromContext(r.Context())); raw != "" {
+			parsed, err := uuid.Parse(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+				return
+			}
+			requestedBy = parsed
+		}
+
+		run, err := runs.Request(r.Context(), name, requestedBy)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccessStatus(w, http.StatusAccepted, run)
+	}
+}
+
+// AdminCronJobRunStatus returns a queued run's status and, once finished, its outcome.
+func AdminCronJobRunStatus(runs CronJobRuns, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if runs == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "cron job runs unavailable"))
+			return
+		}
+
+		runID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "runId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid run id"))
+			return
+		}
+
+		run, err := runs.Get(r.Context(), runID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, run)
+	}
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/cron"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+type stubCronJobRuns struct {
+	requested []string
+	runs      map[uuid.UUID]cron.JobRun
+}
+
+func (s *stubCronJobRuns) Request(_ context.Context, job string, _ uuid.UUID) (cron.JobRun, error) {
+	s.requested = append(s.requested, job)
+	run := cron.JobRun{ID: uuid.New(), Job: job, Status: enums.CronJobRunStatusQueued}
+	if s.runs == nil {
+		s.runs = map[uuid.UUID]cron.JobRun{}
+	}
+	s.runs[run.ID] = run
+	return run, nil
+}
+
+func (s *stubCronJobRuns) Get(_ context.Context, id uuid.UUID) (cron.JobRun, error) {
+	run, ok := s.runs[id]
+	if !ok {
+		return cron.JobRun{}, pkgerrors.New(pkgerrors.CodeNotFound, "cron job run not found")
+	}
+	return run, nil
+}
+
+func withCronURLParam(req *http.Request, key, value string) *http.Request {
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+}
+
+func TestAdminCronJobRunQueuesRun(t *testing.T) {
+	runs := &stubCronJobRuns{}
+	handler := AdminCronJobRun(runs, nil)
+
+	req := withCronURLParam(httptest.NewRequest(http.MethodPost, "/", nil), "job", "outbox-retention")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 got %d: %s", resp.Code, resp.Body.String())
+	}
+	if len(runs.requested) != 1 || runs.requested[0] != "outbox-retention" {
+		t.Fatalf("expected one queued run, got %v", runs.requested)
+	}
+
+	var body struct {
+		Data cron.JobRun `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data.ID == uuid.Nil || body.Data.Status != enums.CronJobRunStatusQueued {
+		t.Fatalf("unexpected run %+v", body.Data)
+	}
+
+	statusReq := withCronURLParam(httptest.NewRequest(http.MethodGet, "/", nil), "runId", body.Data.ID.String())
+	statusResp := httptest.NewRecorder()
+	AdminCronJobRunStatus(runs, nil).ServeHTTP(statusResp, statusReq)
+	if statusResp.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", statusResp.Code, statusResp.Body.String())
+	}
+}
+
+func TestAdminCronJobRunStatusUnknownRun(t *testing.T) {
+	handler := AdminCronJobRunStatus(&stubCronJobRuns{}, nil)
+
+	req := withCronURLParam(httptest.NewRequest(http.MethodGet, "/", nil), "runId", uuid.NewString())
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d", resp.Code)
+	}
+
+	req = withCronURLParam(httptest.NewRequest(http.MethodGet, "/", nil), "runId", "not-a-uuid")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", resp.Code)
+	}
+}
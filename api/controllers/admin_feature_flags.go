@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	"github.com/angelmondragon/packfinderz-backend/internal/featureflags"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type adminFeatureFlagRequest struct {
+	Enabled *bool      `json:"enabled" validate:"required"`
+	StoreID *uuid.UUID `json:"store_id,omitempty"`
+}
+
+// AdminFeatureFlagList returns every known flag with its default and runtime overrides.
+func AdminFeatureFlagList(svc featureflags.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "feature flag service unavailable"))
+			return
+		}
+
+		flags, err := svc.List(r.Context())
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, flags)
+	}
+}
+
+// AdminFeatureFlagSet overrides a flag for the environment, or for one store when store_id is set.
+func AdminFeatureFlagSet(svc featureflags.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "feature flag service unavailable"))
+			return
+		}
+
+		var payload adminFeatureFlagRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		if err := svc.Set(r.Context(), featureflags.SetInput{
+			Key:     chi.URLParam(r, "key"),
+			StoreID: payload.StoreID,
+			Enabled: *payload.Enabled,
+		}); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, map[string]bool{"updated": true})
+	}
+}
+
+// AdminFeatureFlagClear removes a runtime override so the flag falls back to the next level.
+func AdminFeatureFlagClear(svc featureflags.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "feature flag service unavailable"))
+			return
+		}
+
+		var storeID *uuid.UUID
+		if raw := strings.TrimSpace(r.URL.Query().Get("store_id")); raw != "" {
+			parsed, err := uuid.Parse(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+				return
+			}
+			storeID = &parsed
+		}
+
+		if err := svc.Clear(r.Context(), chi.URLParam(r, "key"), storeID); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, map[string]bool{"cleared": true})
+	}
+}
@@ -107,6 +107,50 @@ func AgentAssignedOrderDetail(repo internalorders.Repository, logg *logger.Logge
 	}
 }
 
+// AgentClaimOrder assigns an order from the dispatch queue to the calling agent. A 409 means
+// another agent claimed it first.
+func AgentClaimOrder(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+		agentID, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		rawOrderID := strings.TrimSpace(chi.URLParam(r, "orderId"))
+		if rawOrderID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "order id is required"))
+			return
+		}
+		orderID, err := uuid.Parse(rawOrderID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid order id"))
+			return
+		}
+
+		assignment, err := svc.ClaimOrder(r.Context(), internalorders.AgentClaimInput{
+			OrderID:     orderID,
+			AgentUserID: agentID,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, assignment)
+	}
+}
+
 func AgentPickupOrder(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if svc == nil {
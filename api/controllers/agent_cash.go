@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type agentCashService interface {
+	AgentCashReconciliation(ctx context.Context, agentUserID uuid.UUID) (*ledger.AgentCashReconciliation, error)
+	RecordRemittance(ctx context.Context, input ledger.RecordRemittanceInput) ([]ledger.RemittedCash, error)
+}
+
+type cashRemittanceRequest struct {
+	OrderIDs []uuid.UUID `json:"order_ids" validate:"required,min=1"`
+}
+
+// AgentCashReconciliation shows the calling agent the cash they still hold per order.
+func AgentCashReconciliation(svc agentCashService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "ledger service unavailable"))
+			return
+		}
+
+		agentID, err := uuid.Parse(middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		report, err := svc.AgentCashReconciliation(r.Context(), agentID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, report)
+	}
+}
+
+// AdminAgentCashReconciliation reports an agent's collected-but-unremitted cash for admins.
+func AdminAgentCashReconciliation(svc agentCashService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "ledger service unavailable"))
+			return
+		}
+
+		agentID, err := agentIDFromPath(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		report, err := svc.AgentCashReconciliation(r.Context(), agentID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, report)
+	}
+}
+
+// AdminRecordCashRemittance marks the agent's cash for the given orders as handed in.
+func AdminRecordCashRemittance(svc agentCashService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "ledger service unavailable"))
+			return
+		}
+
+		agentID, err := agentIDFromPath(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		userIDRaw := strings.TrimSpace(middleware.UserIDFromContext(r.Context()))
+		if userIDRaw == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing"))
+			return
+		}
+		actorID, err := uuid.Parse(userIDRaw)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		var req cashRemittanceRequest
+		if err := validators.DecodeJSONBody(r, &req); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		remittances, err := svc.RecordRemittance(r.Context(), ledger.RecordRemittanceInput{
+			AgentUserID:      agentID,
+			OrderIDs:         req.OrderIDs,
+			RecordedByUserID: actorID,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, map[string]any{"remittances": remittances})
+	}
+}
+
+func agentIDFromPath(r *http.Request) (uuid.UUID, error) {
+	raw := strings.TrimSpace(chi.URLParam(r, "agentId"))
+	if raw == "" {
+		return uuid.Nil, pkgerrors.New(pkgerrors.CodeValidation, "agent id is required")
+	}
+	agentID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid agent id")
+	}
+	return agentID, nil
+}
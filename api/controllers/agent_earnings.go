@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type agentEarningsService interface {
+	AgentEarnings(ctx context.Context, agentUserID uuid.UUID, from, to time.Time) (*ledger.AgentEarningsSummary, error)
+}
+
+// AgentEarnings shows the calling agent the delivery fees they earned in [from, to). Both bounds
+// accept RFC3339 timestamps or YYYY-MM-DD dates (midnight UTC).
+func AgentEarnings(svc agentEarningsService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "ledger service unavailable"))
+			return
+		}
+
+		agentID, err := uuid.Parse(middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		from, err := parseDateBound(r.URL.Query().Get("from"), "from")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		to, err := parseDateBound(r.URL.Query().Get("to"), "to")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		summary, err := svc.AgentEarnings(r.Context(), agentID, from, to)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, summary)
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
+)
+
+type stubAgentEarningsService struct {
+	agentID  uuid.UUID
+	from, to time.Time
+}
+
+func (s *stubAgentEarningsService) AgentEarnings(_ context.Context, agentUserID uuid.UUID, from, to time.Time) (*ledger.AgentEarningsSummary, error) {
+	s.agentID, s.from, s.to = agentUserID, from, to
+	return &ledger.AgentEarningsSummary{AgentUserID: agentUserID, From: from, To: to}, nil
+}
+
+func TestAgentEarnings(t *testing.T) {
+	agentID := uuid.New()
+	svc := &stubAgentEarningsService{}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/agent/earnings?from=2026-01-01&to=2026-02-01T00:00:00Z", nil)
+	req = req.WithContext(middleware.WithUserID(req.Context(), agentID.String()))
+	rec := httptest.NewRecorder()
+
+	AgentEarnings(svc, nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if svc.agentID != agentID {
+		t.Fatalf("expected earnings for the calling agent, got %s", svc.agentID)
+	}
+	if !svc.from.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !svc.to.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window %s - %s", svc.from, svc.to)
+	}
+}
+
+func TestAgentEarningsRequiresWindow(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/agent/earnings?from=2026-01-01", nil)
+	req = req.WithContext(middleware.WithUserID(req.Context(), uuid.NewString()))
+	rec := httptest.NewRecorder()
+
+	AgentEarnings(&stubAgentEarningsService{}, nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
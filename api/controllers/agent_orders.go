@@ -1,18 +1,24 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/api/responses"
 	"github.com/angelmondragon/packfinderz-backend/api/validators"
 	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 )
 
 // AgentOrderQueue returns the paginated list of unassigned hold orders for agents.
+// Optional filters: vendor_state, min_total_cents, max_total_cents, min_age_minutes and
+// max_age_minutes. Passing lat and lng sorts the queue by distance to the vendor instead.
 func AgentOrderQueue(repo internalorders.Repository, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if repo == nil {
@@ -26,18 +32,99 @@ func AgentOrderQueue(repo internalorders.Repository, logg *logger.Logger) http.H
 			return
 		}
 
+		filters, err := parseAgentQueueFilters(r, time.Now().UTC())
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
 		cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
 		params := pagination.Params{
 			Limit:  limit,
 			Cursor: cursor,
 		}
 
-		list, err := repo.ListUnassignedHoldOrders(r.Context(), params)
+		list, err := repo.ListUnassignedHoldOrders(r.Context(), params, filters)
 		if err != nil {
-			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list agent queue"))
+			if pkgerrors.As(err) == nil {
+				err = pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list agent queue")
+			}
+			responses.WriteError(r.Context(), logg, w, err)
 			return
 		}
 
 		responses.WriteSuccess(w, list)
 	}
 }
+
+func parseAgentQueueFilters(r *http.Request, now time.Time) (internalorders.AgentQueueFilters, error) {
+	filters := internalorders.AgentQueueFilters{
+		VendorState: strings.TrimSpace(r.URL.Query().Get("vendor_state")),
+	}
+
+	minTotal, err := parseOptionalInt(r, "min_total_cents")
+	if err != nil {
+		return filters, err
+	}
+	maxTotal, err := parseOptionalInt(r, "max_total_cents")
+	if err != nil {
+		return filters, err
+	}
+	if minTotal != nil && maxTotal != nil && *minTotal > *maxTotal {
+		return filters, pkgerrors.New(pkgerrors.CodeValidation, "min_total_cents must not exceed max_total_cents")
+	}
+	filters.MinTotalCents = minTotal
+	filters.MaxTotalCents = maxTotal
+
+	minAge, err := parseOptionalInt(r, "min_age_minutes")
+	if err != nil {
+		return filters, err
+	}
+	maxAge, err := parseOptionalInt(r, "max_age_minutes")
+	if err != nil {
+		return filters, err
+	}
+	if minAge != nil && maxAge != nil && *minAge > *maxAge {
+		return filters, pkgerrors.New(pkgerrors.CodeValidation, "min_age_minutes must not exceed max_age_minutes")
+	}
+	if minAge != nil {
+		before := now.Add(-time.Duration(*minAge) * time.Minute)
+		filters.CreatedBefore = &before
+	}
+	if maxAge != nil {
+		after := now.Add(-time.Duration(*maxAge) * time.Minute)
+		filters.CreatedAfter = &after
+	}
+
+	lat, err := parseCoordinate(r, "lat", 90)
+	if err != nil {
+		return filters, err
+	}
+	lng, err := parseCoordinate(r, "lng", 180)
+	if err != nil {
+		return filters, err
+	}
+	if (lat == nil) != (lng == nil) {
+		return filters, pkgerrors.New(pkgerrors.CodeValidation, "lat and lng must be provided together")
+	}
+	if lat != nil {
+		filters.Near = &maps.LatLng{Latitude: *lat, Longitude: *lng}
+	}
+
+	return filters, nil
+}
+
+func parseCoordinate(r *http.Request, key string, bound float64) (*float64, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("query parameter %s must be numeric", key))
+	}
+	if value < -bound || value > bound {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("query parameter %s must be between %g and %g", key, -bound, bound))
+	}
+	return &value, nil
+}
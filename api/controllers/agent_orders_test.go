@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+func TestParseAgentQueueFilters(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	req := httptest.NewRequest(http.MethodGet, "/?vendor_state=ok&min_total_cents=1000&max_total_cents=5000&min_age_minutes=30&max_age_minutes=120&lat=35.2&lng=-97.4", nil)
+
+	filters, err := parseAgentQueueFilters(req, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filters.VendorState != "ok" {
+		t.Fatalf("unexpected vendor state %q", filters.VendorState)
+	}
+	if filters.MinTotalCents == nil || *filters.MinTotalCents != 1000 || filters.MaxTotalCents == nil || *filters.MaxTotalCents != 5000 {
+		t.Fatalf("unexpected total bounds %v %v", filters.MinTotalCents, filters.MaxTotalCents)
+	}
+	if filters.CreatedBefore == nil || !filters.CreatedBefore.Equal(now.Add(-30*time.Minute)) {
+		t.Fatalf("unexpected created before %v", filters.CreatedBefore)
+	}
+	if filters.CreatedAfter == nil || !filters.CreatedAfter.Equal(now.Add(-120*time.Minute)) {
+		t.Fatalf("unexpected created after %v", filters.CreatedAfter)
+	}
+	if filters.Near == nil || filters.Near.Latitude != 35.2 || filters.Near.Longitude != -97.4 {
+		t.Fatalf("unexpected near %v", filters.Near)
+	}
+}
+
+func TestParseAgentQueueFiltersRejectsInvalidInput(t *testing.T) {
+	cases := map[string]string{
+		"lat without lng":   "/?lat=35.2",
+		"latitude range":    "/?lat=91&lng=0",
+		"inverted totals":   "/?min_total_cents=5000&max_total_cents=1000",
+		"inverted ages":     "/?min_age_minutes=60&max_age_minutes=30",
+		"non-numeric total": "/?min_total_cents=abc",
+	}
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseAgentQueueFilters(httptest.NewRequest(http.MethodGet, target, nil), time.Now())
+			typed := pkgerrors.As(err)
+			if typed == nil || typed.Code() != pkgerrors.CodeValidation {
+				t.Fatalf("expected validation error got %v", err)
+			}
+		})
+	}
+}
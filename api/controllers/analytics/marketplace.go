@@ -2,6 +2,7 @@ package analytics
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/angelmondragon/packfinderz-backend/api/middleware"
 	"github.com/angelmondragon/packfinderz-backend/api/responses"
@@ -37,6 +38,7 @@ func MarketplaceAnalytics(service analytics.Service, logg *logger.Logger) http.H
 			StoreType: storeType,
 			Start:     start,
 			End:       end,
+			TimeZone:  strings.TrimSpace(r.URL.Query().Get("tz")),
 		}
 
 		result, err := service.Query(ctx, req)
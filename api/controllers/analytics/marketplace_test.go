@@ -76,3 +76,21 @@ func TestMarketplaceAnalyticsUsesPreset(t *testing.T) {
 		t.Fatalf("unexpected revenue blob: %+v", envelope.Data.GrossRevenue)
 	}
 }
+
+func TestMarketplaceAnalyticsPassesTimeZone(t *testing.T) {
+	stub := &testAnalyticsService{}
+	handler := MarketplaceAnalytics(stub, logger.New(logger.Options{ServiceName: "test"}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/marketplace?preset=30d&tz=America/Denver", nil)
+	ctx := middleware.WithStoreID(req.Context(), "store-1")
+	ctx = middleware.WithStoreType(ctx, enums.StoreTypeVendor)
+	req = req.WithContext(ctx)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.Code)
+	}
+	if stub.last.TimeZone != "America/Denver" {
+		t.Fatalf("expected time zone passed through, got %q", stub.last.TimeZone)
+	}
+}
@@ -8,9 +8,10 @@ import (
 )
 
 type testAnalyticsService struct {
-	last     types.MarketplaceQueryRequest
-	response *types.MarketplaceQueryResponse
-	err      error
+	last          types.MarketplaceQueryRequest
+	vendorStoreID string
+	response      *types.MarketplaceQueryResponse
+	err           error
 }
 
 // QueryAd implements [analytics.Service].
@@ -18,6 +19,12 @@ func (s *testAnalyticsService) QueryAd(ctx context.Context, req types.AdQueryReq
 	panic("unimplemented")
 }
 
+// QueryVendor implements [analytics.Service].
+func (s *testAnalyticsService) QueryVendor(ctx context.Context, vendorStoreID string, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
+	s.vendorStoreID = vendorStoreID
+	return s.Query(ctx, req)
+}
+
 func (s *testAnalyticsService) Query(ctx context.Context, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
 	s.last = req
 	if s.err != nil {
@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/internal/analytics"
+	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// VendorAnalytics returns marketplace KPIs for the active vendor store. An optional `store_id`
+// query param is passed through so the service can reject attempts to read another store.
+func VendorAnalytics(service analytics.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		storeID := middleware.StoreIDFromContext(ctx)
+		if storeID == "" {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context required"))
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(ctx)
+		if !ok || storeType != enums.StoreTypeVendor {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "vendor store context required"))
+			return
+		}
+
+		start, end, err := resolveAnalyticsRange(r, timeNowUTC())
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		req := types.MarketplaceQueryRequest{
+			StoreID:  strings.TrimSpace(r.URL.Query().Get("store_id")),
+			Start:    start,
+			End:      end,
+			TimeZone: strings.TrimSpace(r.URL.Query().Get("tz")),
+		}
+
+		result, err := service.QueryVendor(ctx, storeID, req)
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, result)
+	}
+}
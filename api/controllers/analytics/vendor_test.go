@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+func TestVendorAnalyticsRequiresVendorStore(t *testing.T) {
+	stub := &testAnalyticsService{}
+	handler := VendorAnalytics(stub, logger.New(logger.Options{ServiceName: "test"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/vendor/analytics", nil)
+	ctx := middleware.WithStoreID(req.Context(), "buyer-1")
+	ctx = middleware.WithStoreType(ctx, enums.StoreTypeBuyer)
+	req = req.WithContext(ctx)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for buyer store, got %d", resp.Code)
+	}
+	if stub.called() {
+		t.Fatal("service should not be invoked for buyer stores")
+	}
+}
+
+func TestVendorAnalyticsScopesToActiveStore(t *testing.T) {
+	stub := &testAnalyticsService{}
+	handler := VendorAnalytics(stub, logger.New(logger.Options{ServiceName: "test"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/vendor/analytics?preset=7d&store_id=vendor-2", nil)
+	ctx := middleware.WithStoreID(req.Context(), "vendor-1")
+	ctx = middleware.WithStoreType(ctx, enums.StoreTypeVendor)
+	req = req.WithContext(ctx)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.Code)
+	}
+	if stub.vendorStoreID != "vendor-1" {
+		t.Fatalf("expected scope from store context, got %q", stub.vendorStoreID)
+	}
+	if stub.last.StoreID != "vendor-2" {
+		t.Fatalf("expected requested store id forwarded for the service to reject, got %q", stub.last.StoreID)
+	}
+}
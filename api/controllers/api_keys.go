@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	"github.com/angelmondragon/packfinderz-backend/internal/apikeys"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type apiKeyCreateRequest struct {
+	Name   string   `json:"name" validate:"required"`
+	Scopes []string `json:"scopes" validate:"required"`
+}
+
+// APIKeyList returns the active store's API keys without their secrets.
+func APIKeyList(svc apikeys.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "api key service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		keys, err := svc.ListAPIKeys(r.Context(), storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, keys)
+	}
+}
+
+// APIKeyCreate issues a scoped API key for the active store. The plaintext key is only returned here.
+func APIKeyCreate(svc apikeys.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "api key service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		userID, err := uuid.Parse(strings.TrimSpace(middleware.UserIDFromContext(r.Context())))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeUnauthorized, err, "user identity missing"))
+			return
+		}
+
+		var payload apiKeyCreateRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		scopes := make([]enums.APIKeyScope, 0, len(payload.Scopes))
+		for _, raw := range payload.Scopes {
+			scope, err := enums.ParseAPIKeyScope(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid scope"))
+				return
+			}
+			scopes = append(scopes, scope)
+		}
+
+		created, err := svc.CreateAPIKey(r.Context(), apikeys.CreateAPIKeyInput{
+			StoreID: storeID,
+			UserID:  userID,
+			Name:    payload.Name,
+			Scopes:  scopes,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccessStatus(w, http.StatusCreated, created)
+	}
+}
+
+// APIKeyRevoke revokes one of the active store's API keys.
+func APIKeyRevoke(svc apikeys.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "api key service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		keyID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "keyId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid api key id"))
+			return
+		}
+
+		if err := svc.RevokeAPIKey(r.Context(), storeID, keyID); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, map[string]bool{"revoked": true})
+	}
+}
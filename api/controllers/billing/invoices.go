@@ -0,0 +1,148 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/controllers/vendorcontext"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// InvoicesService exposes the vendor's subscription invoice history.
+type InvoicesService interface {
+	ListInvoices(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error)
+	GetInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error)
+}
+
+type vendorBillingInvoice struct {
+	ID              string     `json:"id"`
+	SquareInvoiceID string     `json:"square_invoice_id"`
+	InvoiceNumber   *string    `json:"invoice_number,omitempty"`
+	AmountCents     int64      `json:"amount_cents"`
+	Currency        string     `json:"currency"`
+	Status          string     `json:"status"`
+	PeriodStart     *time.Time `json:"period_start,omitempty"`
+	PeriodEnd       *time.Time `json:"period_end,omitempty"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type vendorBillingInvoicesResponse struct {
+	Invoices []vendorBillingInvoice `json:"invoices"`
+}
+
+// VendorBillingInvoices lists the active vendor store's subscription invoices, newest first.
+func VendorBillingInvoices(svc InvoicesService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if svc == nil {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeInternal, "billing service unavailable"))
+			return
+		}
+
+		storeID, err := vendorcontext.ResolveVendorStoreID(r)
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		invoices, err := svc.ListInvoices(ctx, storeID)
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		payload := vendorBillingInvoicesResponse{Invoices: make([]vendorBillingInvoice, len(invoices))}
+		for i, invoice := range invoices {
+			payload.Invoices[i] = toVendorBillingInvoice(invoice)
+		}
+		responses.WriteSuccess(w, payload)
+	}
+}
+
+// VendorBillingInvoiceDownload serves one invoice as a file. format=json (the default) returns the
+// invoice record as an attachment; format=pdf redirects to the PDF Square renders for the invoice.
+func VendorBillingInvoiceDownload(svc InvoicesService, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if svc == nil {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeInternal, "billing service unavailable"))
+			return
+		}
+
+		storeID, err := vendorcontext.ResolveVendorStoreID(r)
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		invoiceID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "invoiceId")))
+		if err != nil {
+			responses.WriteError(ctx, logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid invoice id"))
+			return
+		}
+
+		format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+		if format != "" && format != "json" && format != "pdf" {
+			responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeValidation, "format must be json or pdf"))
+			return
+		}
+
+		invoice, err := svc.GetInvoice(ctx, storeID, invoiceID)
+		if err != nil {
+			responses.WriteError(ctx, logg, w, err)
+			return
+		}
+
+		if format == "pdf" {
+			if invoice.PublicURL == nil {
+				responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeNotFound, "invoice pdf unavailable"))
+				return
+			}
+			http.Redirect(w, r, *invoice.PublicURL, http.StatusFound)
+			return
+		}
+
+		body, err := json.MarshalIndent(toVendorBillingInvoice(*invoice), "", "  ")
+		if err != nil {
+			responses.WriteError(ctx, logg, w, pkgerrors.Wrap(pkgerrors.CodeInternal, err, "encode invoice"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, invoiceFileName(invoice)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}
+}
+
+func invoiceFileName(invoice *models.Invoice) string {
+	if invoice.InvoiceNumber != nil && *invoice.InvoiceNumber != "" {
+		return "invoice-" + *invoice.InvoiceNumber
+	}
+	return "invoice-" + invoice.ID.String()
+}
+
+func toVendorBillingInvoice(invoice models.Invoice) vendorBillingInvoice {
+	return vendorBillingInvoice{
+		ID:              invoice.ID.String(),
+		SquareInvoiceID: invoice.SquareInvoiceID,
+		InvoiceNumber:   invoice.InvoiceNumber,
+		AmountCents:     invoice.AmountCents,
+		Currency:        invoice.Currency,
+		Status:          string(invoice.Status),
+		PeriodStart:     invoice.PeriodStart,
+		PeriodEnd:       invoice.PeriodEnd,
+		PaidAt:          invoice.PaidAt,
+		CreatedAt:       invoice.CreatedAt.UTC(),
+	}
+}
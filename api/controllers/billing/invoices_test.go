@@ -0,0 +1,70 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+type testInvoicesService struct {
+	invoice *models.Invoice
+}
+
+func (s *testInvoicesService) ListInvoices(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	return []models.Invoice{*s.invoice}, nil
+}
+
+func (s *testInvoicesService) GetInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	return s.invoice, nil
+}
+
+func invoiceDownloadRequest(invoiceID uuid.UUID, query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/vendor/billing/invoices/"+invoiceID.String()+"/download"+query, nil)
+	ctx := middleware.WithStoreID(req.Context(), uuid.NewString())
+	ctx = middleware.WithStoreType(ctx, enums.StoreTypeVendor)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("invoiceId", invoiceID.String())
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, routeCtx)
+	return req.WithContext(ctx)
+}
+
+func TestVendorBillingInvoiceDownloadJSON(t *testing.T) {
+	number := "000042"
+	invoice := &models.Invoice{ID: uuid.New(), SquareInvoiceID: "inv-1", InvoiceNumber: &number, AmountCents: 9900, Currency: "USD", Status: enums.InvoiceStatusPaid}
+	resp := httptest.NewRecorder()
+	VendorBillingInvoiceDownload(&testInvoicesService{invoice: invoice}, nil)(resp, invoiceDownloadRequest(invoice.ID, ""))
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("Content-Disposition"); got != `attachment; filename="invoice-000042.json"` {
+		t.Fatalf("unexpected content disposition %q", got)
+	}
+	var body vendorBillingInvoice
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.SquareInvoiceID != "inv-1" || body.AmountCents != 9900 {
+		t.Fatalf("unexpected invoice body %+v", body)
+	}
+}
+
+func TestVendorBillingInvoiceDownloadPDFRedirectsToSquare(t *testing.T) {
+	publicURL := "https://squareup.com/pay-invoice/inv-1"
+	invoice := &models.Invoice{ID: uuid.New(), SquareInvoiceID: "inv-1", PublicURL: &publicURL, Status: enums.InvoiceStatusPaid}
+	resp := httptest.NewRecorder()
+	VendorBillingInvoiceDownload(&testInvoicesService{invoice: invoice}, nil)(resp, invoiceDownloadRequest(invoice.ID, "?format=pdf"))
+
+	if resp.Code != http.StatusFound || resp.Header().Get("Location") != publicURL {
+		t.Fatalf("expected redirect to square invoice, got %d %q", resp.Code, resp.Header().Get("Location"))
+	}
+}
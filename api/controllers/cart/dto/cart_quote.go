@@ -21,7 +21,9 @@ type CartQuote struct {
 	ValidUntil      time.Time              `json:"valid_until"`
 	SubtotalCents   int                    `json:"subtotal_cents"`
 	DiscountsCents  int                    `json:"discounts_cents"`
+	TaxCents        int                    `json:"tax_cents"`
 	TotalCents      int                    `json:"total_cents"`
+	QuoteHash       string                 `json:"quote_hash"`
 	AdTokens        []string               `json:"ad_tokens,omitempty"`
 	VendorGroups    []CartQuoteVendorGroup `json:"vendor_groups,omitempty"`
 	Items           []CartQuoteItem        `json:"items"`
@@ -41,6 +43,9 @@ type CartQuoteVendorGroup struct {
 	LineDiscountsCents int `json:"line_discounts_cents"`
 	PromoDiscountCents int `json:"promo_discount_cents"`
 	DiscountsCents     int `json:"discounts_cents"`
+	TaxCents           int `json:"tax_cents"`
+
+	ShippingLine *types.ShippingLine `json:"shipping_line,omitempty"`
 
 	TotalCents int `json:"total_cents"`
 }
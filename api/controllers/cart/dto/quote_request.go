@@ -1,13 +1,19 @@
 package cartdto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
 
 // QuoteCartRequest captures the minimal intent payload for cart quoting.
 type QuoteCartRequest struct {
-	BuyerStoreID uuid.UUID          `json:"buyer_store_id" validate:"required"`
-	Items        []QuoteCartItem    `json:"items" validate:"required,min=1,dive"`
-	VendorPromos []QuoteVendorPromo `json:"vendor_promos,omitempty" validate:"omitempty,dive"`
-	AdTokens     []string           `json:"ad_tokens,omitempty"`
+	BuyerStoreID    uuid.UUID           `json:"buyer_store_id" validate:"required"`
+	Items           []QuoteCartItem     `json:"items" validate:"required,min=1,dive"`
+	VendorPromos    []QuoteVendorPromo  `json:"vendor_promos,omitempty" validate:"omitempty,dive"`
+	AdTokens        []string            `json:"ad_tokens,omitempty"`
+	ShippingAddress *types.Address      `json:"shipping_address,omitempty"`
+	ShippingLine    *types.ShippingLine `json:"shipping_line,omitempty"`
 }
 
 // QuoteCartItem describes a requested product/quantity tuple.
@@ -24,8 +24,10 @@ func toQuoteCartInput(payload cartdto.QuoteCartRequest) cart.QuoteCartInput {
 	}
 
 	return cart.QuoteCartInput{
-		Items:        items,
-		VendorPromos: promos,
-		AdTokens:     payload.AdTokens,
+		Items:           items,
+		VendorPromos:    promos,
+		AdTokens:        payload.AdTokens,
+		ShippingAddress: payload.ShippingAddress,
+		ShippingLine:    payload.ShippingLine,
 	}
 }
@@ -47,6 +47,9 @@ func newCartQuote(record *models.CartRecord) cartdto.CartQuote {
 			LineDiscountsCents: group.LineDiscountsCents,
 			PromoDiscountCents: group.PromoDiscountCents,
 			DiscountsCents:     group.DiscountsCents,
+			TaxCents:           group.TaxCents,
+
+			ShippingLine: group.ShippingLine,
 
 			TotalCents: group.TotalCents,
 		})
@@ -63,7 +66,9 @@ func newCartQuote(record *models.CartRecord) cartdto.CartQuote {
 		ValidUntil:      record.ValidUntil,
 		SubtotalCents:   record.SubtotalCents,
 		DiscountsCents:  record.DiscountsCents,
+		TaxCents:        record.TaxCents,
 		TotalCents:      record.TotalCents,
+		QuoteHash:       record.QuoteHash,
 		AdTokens:        []string(record.AdTokens),
 		VendorGroups:    vendorGroups,
 		Items:           items,
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -61,12 +62,16 @@ func Checkout(svc checkoutsvc.Service, storeSvc stores.Service, logg *logger.Log
 		}
 
 		group, err := svc.Execute(r.Context(), buyerStoreID, payload.CartID, checkoutsvc.CheckoutInput{
-			IdempotencyKey:  idempotencyKey,
-			ShippingAddress: payload.ShippingAddress,
-			BillingAddress:  payload.BillingAddress,
-			Tip:             payload.Tip,
-			PaymentMethod:   payload.PaymentMethod,
-			ShippingLine:    payload.ShippingLine,
+			IdempotencyKey:        idempotencyKey,
+			ShippingAddress:       payload.ShippingAddress,
+			BillingAddress:        payload.BillingAddress,
+			Tip:                   payload.Tip,
+			PaymentMethod:         payload.PaymentMethod,
+			ShippingLine:          payload.ShippingLine,
+			QuoteHash:             payload.QuoteHash,
+			ExpectedVendorTotals:  payload.expectedVendorTotals(),
+			VendorStoreIDs:        payload.VendorStoreIDs,
+			PaymentMethodByVendor: payload.vendorPaymentMethods(),
 		})
 		if err != nil {
 			responses.WriteError(r.Context(), logg, w, err)
@@ -93,12 +98,48 @@ func buyerStoreIDFromContext(r *http.Request) (uuid.UUID, error) {
 }
 
 type checkoutRequest struct {
-	CartID          uuid.UUID           `json:"cart_id" validate:"required,uuid4"`
-	ShippingAddress *types.Address      `json:"shipping_address" validate:"required"`
-	BillingAddress  *types.Address      `json:"billing_address"`
-	Tip             float32             `json:"tip" validate:"gte=0"`
-	PaymentMethod   enums.PaymentMethod `json:"payment_method" validate:"required,oneof=cash ach"`
-	ShippingLine    *types.ShippingLine `json:"shipping_line,omitempty"`
+	CartID               uuid.UUID             `json:"cart_id" validate:"required,uuid4"`
+	ShippingAddress      *types.Address        `json:"shipping_address" validate:"required"`
+	BillingAddress       *types.Address        `json:"billing_address"`
+	Tip                  float32               `json:"tip" validate:"gte=0"`
+	PaymentMethod        enums.PaymentMethod   `json:"payment_method" validate:"required,oneof=cash ach"`
+	ShippingLine         *types.ShippingLine   `json:"shipping_line,omitempty"`
+	QuoteHash            string                `json:"quote_hash,omitempty"`
+	ExpectedVendorTotals []expectedVendorTotal `json:"expected_vendor_totals,omitempty" validate:"omitempty,dive"`
+	VendorStoreIDs       []uuid.UUID           `json:"vendor_store_ids,omitempty"`
+	VendorPaymentMethods []vendorPaymentMethod `json:"vendor_payment_methods,omitempty" validate:"omitempty,dive"`
+}
+
+type expectedVendorTotal struct {
+	VendorStoreID uuid.UUID `json:"vendor_store_id" validate:"required"`
+	TotalCents    int       `json:"total_cents" validate:"gte=0"`
+}
+
+func (r checkoutRequest) expectedVendorTotals() map[uuid.UUID]int {
+	if len(r.ExpectedVendorTotals) == 0 {
+		return nil
+	}
+	totals := make(map[uuid.UUID]int, len(r.ExpectedVendorTotals))
+	for _, entry := range r.ExpectedVendorTotals {
+		totals[entry.VendorStoreID] = entry.TotalCents
+	}
+	return totals
+}
+
+type vendorPaymentMethod struct {
+	VendorStoreID uuid.UUID           `json:"vendor_store_id" validate:"required"`
+	PaymentMethod enums.PaymentMethod `json:"payment_method" validate:"required,oneof=cash ach"`
+}
+
+func (r checkoutRequest) vendorPaymentMethods() map[uuid.UUID]enums.PaymentMethod {
+	if len(r.VendorPaymentMethods) == 0 {
+		return nil
+	}
+	methods := make(map[uuid.UUID]enums.PaymentMethod, len(r.VendorPaymentMethods))
+	for _, entry := range r.VendorPaymentMethods {
+		methods[entry.VendorStoreID] = entry.PaymentMethod
+	}
+	return methods
 }
 
 type checkoutResponse struct {
@@ -122,6 +163,7 @@ type vendorOrderResponse struct {
 	TransportFeeCents int                `json:"transport_fee_cents"`
 	TotalCents        int                `json:"total_cents"`
 	BalanceDueCents   int                `json:"balance_due_cents"`
+	OpensAt           *time.Time         `json:"opens_at,omitempty"`
 	Items             []lineItemResponse `json:"items"`
 }
 
@@ -175,6 +217,7 @@ func newCheckoutResponse(group *models.CheckoutGroup) checkoutResponse {
 			TransportFeeCents: order.TransportFeeCents,
 			TotalCents:        order.TotalCents,
 			BalanceDueCents:   order.BalanceDueCents,
+			OpensAt:           order.OpensAt,
 			Items:             items,
 		})
 	}
@@ -193,6 +193,18 @@ func (s stubCheckoutStoreService) RemoveUser(ctx context.Context, actorID, store
 	panic("not implemented")
 }
 
+func (s stubCheckoutStoreService) UpdateKYCStatus(ctx context.Context, adminID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*stores.StoreDTO, error) {
+	panic("not implemented")
+}
+
+func (s stubCheckoutStoreService) VerifyTaxExemption(ctx context.Context, adminID, storeID uuid.UUID, review stores.TaxExemptionReview) (*stores.StoreDTO, error) {
+	panic("not implemented")
+}
+
+func (s stubCheckoutStoreService) SearchVendors(ctx context.Context, input stores.SearchVendorsInput) (*stores.SearchVendorsResult, error) {
+	panic("not implemented")
+}
+
 func ptrUUID(id uuid.UUID) *uuid.UUID {
 	return &id
 }
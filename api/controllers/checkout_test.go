@@ -62,6 +62,18 @@ func (checkoutStubStoreService) RemoveUser(ctx context.Context, actorID, storeID
 	return pkgerrors.New(pkgerrors.CodeInternal, "not implemented")
 }
 
+func (checkoutStubStoreService) UpdateKYCStatus(ctx context.Context, adminID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*stores.StoreDTO, error) {
+	return nil, pkgerrors.New(pkgerrors.CodeInternal, "not implemented")
+}
+
+func (checkoutStubStoreService) VerifyTaxExemption(ctx context.Context, adminID, storeID uuid.UUID, review stores.TaxExemptionReview) (*stores.StoreDTO, error) {
+	return nil, pkgerrors.New(pkgerrors.CodeInternal, "not implemented")
+}
+
+func (checkoutStubStoreService) SearchVendors(ctx context.Context, input stores.SearchVendorsInput) (*stores.SearchVendorsResult, error) {
+	return nil, pkgerrors.New(pkgerrors.CodeInternal, "not implemented")
+}
+
 func TestCheckoutSuccess(t *testing.T) {
 	t.Parallel()
 
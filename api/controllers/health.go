@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -14,6 +15,17 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
 )
 
+// dependencyOK marks a dependency that answered its ping.
+const dependencyOK = "ok"
+
+type dependencyCheck struct {
+	name   string
+	pinger interface {
+		Ping(ctx context.Context) error
+	}
+}
+
+// HealthLive reports that the process is serving requests; it never touches dependencies.
 func HealthLive(cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-PackFinderz-Env", cfg.App.Env)
@@ -21,51 +33,44 @@ func HealthLive(cfg *config.Config) http.HandlerFunc {
 	}
 }
 
+// HealthReady pings every backing dependency and reports a per-dependency status map,
+// answering 503 when any of them is unavailable.
 func HealthReady(cfg *config.Config, logg *logger.Logger, dbP db.Pinger, redisP redis.Pinger, gcsP gcs.Pinger, bigqueryP bigquery.Pinger) http.HandlerFunc {
+	checks := []dependencyCheck{
+		{name: "postgres", pinger: dbP},
+		{name: "redis", pinger: redisP},
+		{name: "gcs", pinger: gcsP},
+		{name: "bigquery", pinger: bigqueryP},
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		w.Header().Set("X-PackFinderz-Env", cfg.App.Env)
 
-		failures := map[string]string{}
-
-		// ---- Postgres
-		if dbP == nil {
-			if cfg.App.Env != "test" {
-				failures["postgres"] = "not configured"
+		statuses := make(map[string]string, len(checks))
+		healthy := true
+		for _, check := range checks {
+			if check.pinger == nil {
+				// Tests run without external dependencies wired.
+				if cfg.App.Env == "test" {
+					continue
+				}
+				statuses[check.name] = "not configured"
+				healthy = false
+				continue
 			}
-		} else if err := dbP.Ping(ctx); err != nil {
-			failures["postgres"] = err.Error()
-		}
-
-		// ---- Redis
-		if redisP == nil {
-			if cfg.App.Env != "test" {
-				failures["redis"] = "not configured"
-			}
-		} else if err := redisP.Ping(ctx); err != nil {
-			failures["redis"] = err.Error()
-		}
-
-		if gcsP == nil {
-			if cfg.App.Env != "test" {
-				failures["gcs"] = "not configured"
-			}
-		} else if err := gcsP.Ping(ctx); err != nil {
-			failures["gcs"] = err.Error()
-		}
-
-		if bigqueryP == nil {
-			if cfg.App.Env != "test" {
-				failures["bigquery"] = "not configured"
+			if err := check.pinger.Ping(ctx); err != nil {
+				statuses[check.name] = err.Error()
+				healthy = false
+				continue
 			}
-		} else if err := bigqueryP.Ping(ctx); err != nil {
-			failures["bigquery"] = err.Error()
+			statuses[check.name] = dependencyOK
 		}
 
-		if len(failures) > 0 {
+		if !healthy {
 			err := pkgerrors.
 				New(pkgerrors.CodeDependency, "dependencies unavailable").
-				WithDetails(failures)
+				WithDetails(statuses)
 
 			if logg != nil {
 				logg.Error(ctx, "readiness failed", errors.New("dependency check failed"))
@@ -75,6 +80,6 @@ func HealthReady(cfg *config.Config, logg *logger.Logger, dbP db.Pinger, redisP
 			return
 		}
 
-		responses.WriteSuccess(w, map[string]string{"status": "ready"})
+		responses.WriteSuccess(w, map[string]any{"status": "ready", "dependencies": statuses})
 	}
 }
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type stubPinger struct {
+	err error
+}
+
+func (s stubPinger) Ping(context.Context) error {
+	return s.err
+}
+
+func TestHealthReadyReportsFailingDependency(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Env: "dev"}}
+	logg := logger.New(logger.Options{ServiceName: "test", Output: io.Discard})
+	handler := HealthReady(cfg, logg, stubPinger{}, stubPinger{err: errors.New("connection refused")}, stubPinger{}, stubPinger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+
+	var body struct {
+		Error struct {
+			Code    string            `json:"code"`
+			Details map[string]string `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Error.Code != string(pkgerrors.CodeDependency) {
+		t.Fatalf("unexpected code %s", body.Error.Code)
+	}
+	want := map[string]string{
+		"postgres": "ok",
+		"redis":    "connection refused",
+		"gcs":      "ok",
+		"bigquery": "ok",
+	}
+	for name, status := range want {
+		if body.Error.Details[name] != status {
+			t.Fatalf("dependency %s: expected %q got %q", name, status, body.Error.Details[name])
+		}
+	}
+}
+
+func TestHealthReadyAllDependenciesUp(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Env: "dev"}}
+	handler := HealthReady(cfg, nil, stubPinger{}, stubPinger{}, stubPinger{}, stubPinger{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var body struct {
+		Data struct {
+			Status       string            `json:"status"`
+			Dependencies map[string]string `json:"dependencies"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data.Status != "ready" || len(body.Data.Dependencies) != 4 {
+		t.Fatalf("unexpected body %+v", body.Data)
+	}
+}
+
+func TestHealthLiveSkipsDependencies(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{Env: "dev"}}
+
+	rec := httptest.NewRecorder()
+	HealthLive(cfg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
@@ -92,6 +92,94 @@ func MediaPresign(svc media.Service, logg *logger.Logger) http.HandlerFunc {
 	}
 }
 
+type mediaPresignBatchRequest struct {
+	Uploads []mediaPresignRequest `json:"uploads" validate:"required,min=1,dive"`
+}
+
+// MediaPresignBatch creates several media records in one request, returning a signed PUT URL for
+// each upload in request order.
+func MediaPresignBatch(svc media.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "media service unavailable"))
+			return
+		}
+
+		sid, err := uuid.Parse(middleware.StoreIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+		uid, err := uuid.Parse(middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		var payload mediaPresignBatchRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		inputs := make([]media.PresignInput, 0, len(payload.Uploads))
+		for i, upload := range payload.Uploads {
+			input, err := upload.toInput()
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.NewValidationError("invalid uploads", map[string]string{
+					"uploads[" + strconv.Itoa(i) + "].media_kind": "is invalid",
+				}))
+				return
+			}
+			inputs = append(inputs, input)
+		}
+
+		resp, err := svc.PresignUploadBatch(r.Context(), uid, sid, inputs)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, resp)
+	}
+}
+
+// MediaConfirmUpload marks a presigned upload as complete once the object is present in storage,
+// returning the ready media item.
+func MediaConfirmUpload(svc media.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "media service unavailable"))
+			return
+		}
+
+		sid, err := uuid.Parse(middleware.StoreIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+		uid, err := uuid.Parse(middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		mediaID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "mediaId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid media id"))
+			return
+		}
+
+		item, err := svc.ConfirmUpload(r.Context(), uid, sid, mediaID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, item)
+	}
+}
+
 // MediaDelete deletes a media row if it belongs to the active store and is unreferenced.
 func MediaDelete(svc media.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -132,6 +220,38 @@ func MediaDelete(svc media.Service, logg *logger.Logger) http.HandlerFunc {
 	}
 }
 
+// MediaUndelete restores media deleted by the active store while its deletion grace period runs.
+func MediaUndelete(svc media.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "media service unavailable"))
+			return
+		}
+
+		sid, err := uuid.Parse(middleware.StoreIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		mediaID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "mediaId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid media id"))
+			return
+		}
+
+		if err := svc.UndeleteMedia(r.Context(), media.DeleteMediaParams{
+			StoreID: sid,
+			MediaID: mediaID,
+		}); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 // MediaList handles listing store-scoped media metadata.
 func MediaList(svc media.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -209,3 +329,93 @@ func MediaList(svc media.Service, logg *logger.Logger) http.HandlerFunc {
 		responses.WriteSuccess(w, resp)
 	}
 }
+
+// MediaReadURL returns a download URL for media owned by the active store. Protected media (license
+// documents, manifests) gets a short-lived signed URL and an access log entry; `purpose` is
+// recorded with it.
+func MediaReadURL(svc media.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "media service unavailable"))
+			return
+		}
+
+		sid, err := uuid.Parse(middleware.StoreIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+		uid, err := uuid.Parse(middleware.UserIDFromContext(r.Context()))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		mediaID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "mediaId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid media id"))
+			return
+		}
+
+		resp, err := svc.GenerateReadURL(r.Context(), media.ReadURLParams{
+			StoreID:      sid,
+			MediaID:      mediaID,
+			ActorUserID:  uid,
+			ActorStoreID: sid,
+			Purpose:      strings.TrimSpace(r.URL.Query().Get("purpose")),
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, resp)
+	}
+}
+
+// AdminMediaAccessLogs lists protected media download URLs issued, newest first, optionally
+// filtered by `media_id` or `store_id`.
+func AdminMediaAccessLogs(svc media.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "media service unavailable"))
+			return
+		}
+
+		limit, err := validators.ParseQueryInt(r, "limit", pkgpagination.DefaultLimit, 1, pkgpagination.MaxLimit)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		q := r.URL.Query()
+		query := media.AccessLogQuery{
+			Params: pkgpagination.Params{
+				Limit:  limit,
+				Cursor: strings.TrimSpace(q.Get("cursor")),
+			},
+		}
+		if raw := strings.TrimSpace(q.Get("media_id")); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid media_id"))
+				return
+			}
+			query.MediaID = &id
+		}
+		if raw := strings.TrimSpace(q.Get("store_id")); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store_id"))
+				return
+			}
+			query.StoreID = &id
+		}
+
+		list, err := svc.ListAccessLogs(r.Context(), query)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, list)
+	}
+}
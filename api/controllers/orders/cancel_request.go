@@ -0,0 +1,81 @@
+package orders
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// VendorCancelRequestDecision lets the vendor approve or deny a buyer's cancel request made after
+// the cancel window closed.
+func VendorCancelRequestDecision(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(r.Context())
+		if !ok || storeType != enums.StoreTypeVendor {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "vendor store context required"))
+			return
+		}
+
+		storeID, err := parseStoreID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		actorID, err := parseActorID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload vendorCancelRequestDecisionRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		orderID, err := parseUUIDParam(r, "orderId", "order id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		input := internalorders.CancelRequestDecisionInput{
+			OrderID:      orderID,
+			ActorUserID:  actorID,
+			ActorStoreID: storeID,
+			ActorRole:    middleware.RoleFromContext(r.Context()),
+		}
+
+		switch strings.ToLower(strings.TrimSpace(payload.Decision)) {
+		case "approve":
+			err = svc.ApproveCancelRequest(r.Context(), input)
+		case "deny":
+			err = svc.DenyCancelRequest(r.Context(), input)
+		default:
+			err = pkgerrors.New(pkgerrors.CodeValidation, "decision must be approve or deny")
+		}
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, nil)
+	}
+}
+
+type vendorCancelRequestDecisionRequest struct {
+	Decision string `json:"decision" validate:"required"`
+}
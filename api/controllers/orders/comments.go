@@ -0,0 +1,182 @@
+package orders
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// commentActor resolves which side of the order the caller comments from and their store, if any.
+type commentActor func(r *http.Request) (internalorders.CommentRole, uuid.UUID, error)
+
+// StoreOrderComments lists the comments a buyer or vendor store may see on one of its orders.
+func StoreOrderComments(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return listOrderComments(svc, logg, storeCommentActor)
+}
+
+// StoreAddOrderComment adds a comment from the buyer or vendor store on the order.
+func StoreAddOrderComment(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return addOrderComment(svc, logg, storeCommentActor)
+}
+
+// AgentOrderComments lists every comment on an order assigned to the calling agent.
+func AgentOrderComments(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return listOrderComments(svc, logg, staffCommentActor(internalorders.CommentRoleAgent))
+}
+
+// AgentAddOrderComment adds a comment from the agent assigned to the order.
+func AgentAddOrderComment(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return addOrderComment(svc, logg, staffCommentActor(internalorders.CommentRoleAgent))
+}
+
+// AdminOrderComments lists every comment on an order, internal ones included.
+func AdminOrderComments(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return listOrderComments(svc, logg, staffCommentActor(internalorders.CommentRoleAdmin))
+}
+
+// AdminAddOrderComment adds a comment to any order as an admin.
+func AdminAddOrderComment(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return addOrderComment(svc, logg, staffCommentActor(internalorders.CommentRoleAdmin))
+}
+
+func listOrderComments(svc internalorders.Service, logg *logger.Logger, actor commentActor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		role, storeID, err := actor(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		actorID, err := parseActorID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		orderID, err := parseUUIDParam(r, "orderId", "order id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		comments, err := svc.ListComments(r.Context(), internalorders.ListCommentsInput{
+			OrderID:      orderID,
+			Role:         role,
+			ActorUserID:  actorID,
+			ActorStoreID: storeID,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, comments)
+	}
+}
+
+func addOrderComment(svc internalorders.Service, logg *logger.Logger, actor commentActor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		role, storeID, err := actor(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		actorID, err := parseActorID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		orderID, err := parseUUIDParam(r, "orderId", "order id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload orderCommentRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		var visibility enums.OrderCommentVisibility
+		if raw := strings.ToLower(strings.TrimSpace(payload.Visibility)); raw != "" {
+			visibility, err = enums.ParseOrderCommentVisibility(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid visibility"))
+				return
+			}
+		}
+
+		comment, err := svc.AddComment(r.Context(), internalorders.AddCommentInput{
+			OrderID:      orderID,
+			Body:         payload.Body,
+			Visibility:   visibility,
+			Role:         role,
+			ActorUserID:  actorID,
+			ActorStoreID: storeID,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccessStatus(w, http.StatusCreated, comment)
+	}
+}
+
+// storeCommentActor maps the active store's type to the buyer or vendor side of the order.
+func storeCommentActor(r *http.Request) (internalorders.CommentRole, uuid.UUID, error) {
+	storeID, err := parseStoreID(r)
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+	storeType, ok := middleware.StoreTypeFromContext(r.Context())
+	if !ok {
+		return "", uuid.Nil, pkgerrors.New(pkgerrors.CodeForbidden, "store type missing")
+	}
+	switch storeType {
+	case enums.StoreTypeBuyer:
+		return internalorders.CommentRoleBuyer, storeID, nil
+	case enums.StoreTypeVendor:
+		return internalorders.CommentRoleVendor, storeID, nil
+	default:
+		return "", uuid.Nil, pkgerrors.New(pkgerrors.CodeForbidden, "unsupported store type")
+	}
+}
+
+// staffCommentActor fixes the role for routes already gated to agents or admins. The store is
+// optional there and only recorded on the comment when present.
+func staffCommentActor(role internalorders.CommentRole) commentActor {
+	return func(r *http.Request) (internalorders.CommentRole, uuid.UUID, error) {
+		raw := strings.TrimSpace(middleware.StoreIDFromContext(r.Context()))
+		if raw == "" {
+			return role, uuid.Nil, nil
+		}
+		storeID, err := uuid.Parse(raw)
+		if err != nil {
+			return "", uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id")
+		}
+		return role, storeID, nil
+	}
+}
+
+type orderCommentRequest struct {
+	Body       string `json:"body" validate:"required"`
+	Visibility string `json:"visibility"`
+}
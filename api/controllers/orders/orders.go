@@ -130,10 +130,16 @@ func List(repo internalorders.Repository, logg *logger.Logger) http.HandlerFunc
 			return
 		}
 		cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
+		includeTotal, err := validators.ParseQueryBool(r, "include_total", false)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
 
 		params := pagination.Params{
-			Limit:  limit,
-			Cursor: cursor,
+			Limit:        limit,
+			Cursor:       cursor,
+			IncludeTotal: includeTotal,
 		}
 		input := internalorders.ListOrdersInput{
 			Pagination: params,
@@ -356,11 +362,16 @@ func CancelOrder(svc internalorders.Service, logg *logger.Logger) http.HandlerFu
 			ActorRole:    role,
 		}
 
-		if err := svc.CancelOrder(r.Context(), input); err != nil {
+		result, err := svc.CancelOrder(r.Context(), input)
+		if err != nil {
 			responses.WriteError(r.Context(), logg, w, err)
 			return
 		}
-		responses.WriteSuccess(w, nil)
+		if result != nil && result.Outcome == internalorders.BuyerCancelOutcomeRequested {
+			responses.WriteSuccessStatus(w, http.StatusAccepted, result)
+			return
+		}
+		responses.WriteSuccess(w, result)
 	}
 }
 
@@ -561,6 +572,75 @@ func VendorLineItemDecision(svc internalorders.Service, logg *logger.Logger) htt
 	}
 }
 
+// VendorSchedulePickup lets the vendor publish when an order will be ready for agent pickup.
+func VendorSchedulePickup(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(r.Context())
+		if !ok || storeType != enums.StoreTypeVendor {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "vendor store context required"))
+			return
+		}
+
+		storeID, err := parseStoreID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+		actorID, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		var payload vendorPickupWindowRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		rawOrderID := strings.TrimSpace(chi.URLParam(r, "orderId"))
+		if rawOrderID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "order id is required"))
+			return
+		}
+		orderID, err := uuid.Parse(rawOrderID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid order id"))
+			return
+		}
+
+		input := internalorders.SchedulePickupInput{
+			OrderID:      orderID,
+			Window:       internalorders.PickupWindow{Start: payload.Start, End: payload.End},
+			ActorUserID:  actorID,
+			ActorStoreID: storeID,
+		}
+
+		if err := svc.SchedulePickup(r.Context(), input); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, nil)
+	}
+}
+
+type vendorPickupWindowRequest struct {
+	Start time.Time `json:"start" validate:"required"`
+	End   time.Time `json:"end" validate:"required"`
+}
+
 type vendorOrderDecisionRequest struct {
 	Decision string `json:"decision" validate:"required"`
 }
@@ -607,11 +687,11 @@ func parseStoreID(r *http.Request) (uuid.UUID, error) {
 
 func buildBuyerFilters(r *http.Request) (internalorders.BuyerOrderFilters, error) {
 	var filters internalorders.BuyerOrderFilters
-	orderStatus, err := parseVendorOrderStatusParam(r.URL.Query().Get("order_status"))
+	orderStatuses, err := parseStatusListParam(r, "order_status")
 	if err != nil {
 		return filters, err
 	}
-	filters.OrderStatus = orderStatus
+	filters.OrderStatuses = orderStatuses
 
 	fulfillmentStatus, err := parseFulfillmentStatusParam(r.URL.Query().Get("fulfillment_status"))
 	if err != nil {
@@ -631,17 +711,10 @@ func buildBuyerFilters(r *http.Request) (internalorders.BuyerOrderFilters, error
 	}
 	filters.PaymentStatus = paymentStatus
 
-	dateFrom, err := parseDateParam(r.URL.Query().Get("date_from"), "date_from")
-	if err != nil {
-		return filters, err
-	}
-	filters.DateFrom = dateFrom
-
-	dateTo, err := parseDateParam(r.URL.Query().Get("date_to"), "date_to")
+	filters.DateFrom, filters.DateTo, err = parseDateRange(r)
 	if err != nil {
 		return filters, err
 	}
-	filters.DateTo = dateTo
 
 	if q := strings.TrimSpace(r.URL.Query().Get("q")); q != "" {
 		filters.Query = q
@@ -652,11 +725,11 @@ func buildBuyerFilters(r *http.Request) (internalorders.BuyerOrderFilters, error
 
 func buildVendorFilters(r *http.Request) (internalorders.VendorOrderFilters, error) {
 	var filters internalorders.VendorOrderFilters
-	orderStatus, err := parseVendorOrderStatusParam(r.URL.Query().Get("order_status"))
+	orderStatuses, err := parseStatusListParam(r, "order_status")
 	if err != nil {
 		return filters, err
 	}
-	filters.OrderStatus = orderStatus
+	filters.OrderStatuses = orderStatuses
 
 	fulfillmentStatus, err := parseFulfillmentStatusParam(r.URL.Query().Get("fulfillment_status"))
 	if err != nil {
@@ -676,17 +749,10 @@ func buildVendorFilters(r *http.Request) (internalorders.VendorOrderFilters, err
 	}
 	filters.PaymentStatus = paymentStatus
 
-	dateFrom, err := parseDateParam(r.URL.Query().Get("date_from"), "date_from")
-	if err != nil {
-		return filters, err
-	}
-	filters.DateFrom = dateFrom
-
-	dateTo, err := parseDateParam(r.URL.Query().Get("date_to"), "date_to")
+	filters.DateFrom, filters.DateTo, err = parseDateRange(r)
 	if err != nil {
 		return filters, err
 	}
-	filters.DateTo = dateTo
 
 	actionable, err := parseActionableStatuses(r)
 	if err != nil {
@@ -703,17 +769,6 @@ func buildVendorFilters(r *http.Request) (internalorders.VendorOrderFilters, err
 	return filters, nil
 }
 
-func parseVendorOrderStatusParam(raw string) (*enums.VendorOrderStatus, error) {
-	if strings.TrimSpace(raw) == "" {
-		return nil, nil
-	}
-	status, err := enums.ParseVendorOrderStatus(raw)
-	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, fmt.Sprintf("invalid order_status %q", raw))
-	}
-	return &status, nil
-}
-
 func parseFulfillmentStatusParam(raw string) (*enums.VendorOrderFulfillmentStatus, error) {
 	if strings.TrimSpace(raw) == "" {
 		return nil, nil
@@ -762,9 +817,29 @@ func parseDateParam(value, field string) (*time.Time, error) {
 	return &t, nil
 }
 
+// parseDateRange reads the inclusive date_from/date_to bounds.
+func parseDateRange(r *http.Request) (*time.Time, *time.Time, error) {
+	from, err := parseDateParam(r.URL.Query().Get("date_from"), "date_from")
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDateParam(r.URL.Query().Get("date_to"), "date_to")
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, pkgerrors.New(pkgerrors.CodeValidation, "date_from must not be after date_to")
+	}
+	return from, to, nil
+}
+
 func parseActionableStatuses(r *http.Request) ([]enums.VendorOrderStatus, error) {
+	return parseStatusListParam(r, "actionable_statuses")
+}
+
+func parseStatusListParam(r *http.Request, key string) ([]enums.VendorOrderStatus, error) {
 	var statuses []enums.VendorOrderStatus
-	for _, raw := range r.URL.Query()["actionable_statuses"] {
+	for _, raw := range r.URL.Query()[key] {
 		for _, token := range strings.Split(raw, ",") {
 			token = strings.TrimSpace(token)
 			if token == "" {
@@ -772,7 +847,7 @@ func parseActionableStatuses(r *http.Request) ([]enums.VendorOrderStatus, error)
 			}
 			status, err := enums.ParseVendorOrderStatus(token)
 			if err != nil {
-				return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, fmt.Sprintf("invalid actionable_statuses %q", token))
+				return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, fmt.Sprintf("invalid %s %q", key, token))
 			}
 			statuses = append(statuses, status)
 		}
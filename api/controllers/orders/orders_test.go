@@ -50,7 +50,7 @@ func (s *stubControllerOrdersRepo) ListAssignedOrders(ctx context.Context, agent
 }
 
 // ListUnassignedHoldOrders implements [orders.Repository].
-func (s *stubControllerOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*internalorders.AgentOrderQueueList, error) {
+func (s *stubControllerOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters internalorders.AgentQueueFilters) (*internalorders.AgentOrderQueueList, error) {
 	panic("unimplemented")
 }
 
@@ -98,6 +98,10 @@ func (s *stubControllerOrdersRepo) UpdateOrderAssignment(ctx context.Context, as
 	return nil
 }
 
+func (s *stubControllerOrdersRepo) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	panic("unimplemented")
+}
+
 func (s *stubControllerOrdersRepo) FindPaymentIntentByOrder(ctx context.Context, orderID uuid.UUID) (*models.PaymentIntent, error) {
 	panic("not implemented")
 }
@@ -130,6 +134,10 @@ func (s *stubControllerOrdersRepo) ListPayoutOrders(ctx context.Context, params
 	return &internalorders.PayoutOrderList{}, nil
 }
 
+func (s *stubControllerOrdersRepo) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(internalorders.AccountingExportLine) error) error {
+	panic("unimplemented")
+}
+
 func (s *stubControllerOrdersRepo) FindOrderDetail(ctx context.Context, orderID uuid.UUID) (*internalorders.OrderDetail, error) {
 	if s.detail != nil {
 		return s.detail(ctx, orderID)
@@ -145,13 +153,41 @@ func (s *stubControllerOrdersRepo) UpdateVendorOrderStatus(ctx context.Context,
 	return nil
 }
 
+func (s *stubControllerOrdersRepo) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	return false, nil
+}
+
+func (s *stubControllerOrdersRepo) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	return nil
+}
+
+func (s *stubControllerOrdersRepo) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *stubControllerOrdersRepo) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
+	return nil
+}
+
+func (s *stubControllerOrdersRepo) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	return nil, nil
+}
+
 type stubControllerOrdersService struct {
 	decision         func(ctx context.Context, input internalorders.VendorDecisionInput) error
 	lineItemDecision func(ctx context.Context, input internalorders.LineItemDecisionInput) error
-	cancel           func(ctx context.Context, input internalorders.BuyerCancelInput) error
+	cancel           func(ctx context.Context, input internalorders.BuyerCancelInput) (*internalorders.BuyerCancelResult, error)
 	nudge            func(ctx context.Context, input internalorders.BuyerNudgeInput) error
 	retry            func(ctx context.Context, input internalorders.BuyerRetryInput) (*internalorders.BuyerRetryResult, error)
+	schedulePickup   func(ctx context.Context, input internalorders.SchedulePickupInput) error
 	confirmPayout    func(ctx context.Context, input internalorders.ConfirmPayoutInput) error
+	propose          func(ctx context.Context, input internalorders.ProposeSubstitutionInput) error
+	acceptSub        func(ctx context.Context, input internalorders.SubstitutionDecisionInput) error
+	rejectSub        func(ctx context.Context, input internalorders.SubstitutionDecisionInput) error
+	approveCancel    func(ctx context.Context, input internalorders.CancelRequestDecisionInput) error
+	denyCancel       func(ctx context.Context, input internalorders.CancelRequestDecisionInput) error
+	addComment       func(ctx context.Context, input internalorders.AddCommentInput) (*internalorders.OrderComment, error)
+	listComments     func(ctx context.Context, input internalorders.ListCommentsInput) ([]internalorders.OrderComment, error)
 }
 
 func (s *stubControllerOrdersService) VendorDecision(ctx context.Context, input internalorders.VendorDecisionInput) error {
@@ -168,11 +204,11 @@ func (s *stubControllerOrdersService) LineItemDecision(ctx context.Context, inpu
 	return nil
 }
 
-func (s *stubControllerOrdersService) CancelOrder(ctx context.Context, input internalorders.BuyerCancelInput) error {
+func (s *stubControllerOrdersService) CancelOrder(ctx context.Context, input internalorders.BuyerCancelInput) (*internalorders.BuyerCancelResult, error) {
 	if s.cancel != nil {
 		return s.cancel(ctx, input)
 	}
-	return nil
+	return nil, nil
 }
 
 func (s *stubControllerOrdersService) NudgeVendor(ctx context.Context, input internalorders.BuyerNudgeInput) error {
@@ -189,6 +225,17 @@ func (s *stubControllerOrdersService) RetryOrder(ctx context.Context, input inte
 	return nil, nil
 }
 
+func (s *stubControllerOrdersService) SchedulePickup(ctx context.Context, input internalorders.SchedulePickupInput) error {
+	if s.schedulePickup != nil {
+		return s.schedulePickup(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) ClaimOrder(ctx context.Context, input internalorders.AgentClaimInput) (*internalorders.OrderAssignmentSummary, error) {
+	return nil, nil
+}
+
 func (s *stubControllerOrdersService) AgentPickup(ctx context.Context, input internalorders.AgentPickupInput) error {
 	return nil
 }
@@ -208,6 +255,55 @@ func (s *stubControllerOrdersService) ConfirmPayout(ctx context.Context, input i
 	return nil
 }
 
+func (s *stubControllerOrdersService) ProposeSubstitution(ctx context.Context, input internalorders.ProposeSubstitutionInput) error {
+	if s.propose != nil {
+		return s.propose(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) AcceptSubstitution(ctx context.Context, input internalorders.SubstitutionDecisionInput) error {
+	if s.acceptSub != nil {
+		return s.acceptSub(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) RejectSubstitution(ctx context.Context, input internalorders.SubstitutionDecisionInput) error {
+	if s.rejectSub != nil {
+		return s.rejectSub(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) ApproveCancelRequest(ctx context.Context, input internalorders.CancelRequestDecisionInput) error {
+	if s.approveCancel != nil {
+		return s.approveCancel(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) DenyCancelRequest(ctx context.Context, input internalorders.CancelRequestDecisionInput) error {
+	if s.denyCancel != nil {
+		return s.denyCancel(ctx, input)
+	}
+	return nil
+}
+
+func (s *stubControllerOrdersService) AddComment(ctx context.Context, input internalorders.AddCommentInput) (*internalorders.OrderComment, error) {
+	if s.addComment != nil {
+		return s.addComment(ctx, input)
+	}
+	return &internalorders.OrderComment{}, nil
+}
+
+func (s *stubControllerOrdersService) ListComments(ctx context.Context, input internalorders.ListCommentsInput) ([]internalorders.OrderComment, error) {
+	if s.listComments != nil {
+		return s.listComments(ctx, input)
+	}
+	return nil, nil
+}
+
 type stubStoreFetcher struct {
 	store *stores.StoreDTO
 	err   error
@@ -238,7 +334,7 @@ func TestListBuyerPerspective(t *testing.T) {
 			if filters.Query != "tap" {
 				t.Fatalf("unexpected query %q", filters.Query)
 			}
-			if filters.OrderStatus == nil || *filters.OrderStatus != enums.VendorOrderStatusCreatedPending {
+			if len(filters.OrderStatuses) != 2 || filters.OrderStatuses[0] != enums.VendorOrderStatusCreatedPending || filters.OrderStatuses[1] != enums.VendorOrderStatusAccepted {
 				t.Fatalf("order status not parsed")
 			}
 			if input.Page != 1 {
@@ -249,7 +345,7 @@ func TestListBuyerPerspective(t *testing.T) {
 	}
 
 	handler := List(repo, nil)
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=5&q=tap&order_status=created_pending", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=5&q=tap&order_status=created_pending,accepted", nil)
 	req = req.WithContext(middleware.WithStoreID(req.Context(), storeID.String()))
 	req = req.WithContext(middleware.WithStoreType(req.Context(), enums.StoreTypeBuyer))
 
@@ -314,6 +410,26 @@ func TestListVendorPerspectiveActionable(t *testing.T) {
 	}
 }
 
+func TestListRejectsInvertedDateRange(t *testing.T) {
+	repo := &stubControllerOrdersRepo{
+		listBuyer: func(ctx context.Context, buyerStoreID uuid.UUID, input internalorders.ListOrdersInput, filters internalorders.BuyerOrderFilters) (*internalorders.BuyerOrderListResult, error) {
+			t.Fatalf("repository should not be called for an invalid window")
+			return nil, nil
+		},
+	}
+
+	handler := List(repo, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?date_from=2026-02-01T00:00:00Z&date_to=2026-01-01T00:00:00Z", nil)
+	req = req.WithContext(middleware.WithStoreID(req.Context(), uuid.NewString()))
+	req = req.WithContext(middleware.WithStoreType(req.Context(), enums.StoreTypeBuyer))
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", resp.Code)
+	}
+}
+
 func TestStorefrontOrdersSuccess(t *testing.T) {
 	buyerStoreID := uuid.New()
 	vendorStoreID := uuid.New()
@@ -584,7 +700,7 @@ func TestCancelOrderSuccess(t *testing.T) {
 	orderID := uuid.New()
 	called := false
 	svc := &stubControllerOrdersService{
-		cancel: func(ctx context.Context, input internalorders.BuyerCancelInput) error {
+		cancel: func(ctx context.Context, input internalorders.BuyerCancelInput) (*internalorders.BuyerCancelResult, error) {
 			if input.OrderID != orderID {
 				t.Fatalf("unexpected order id %s", input.OrderID)
 			}
@@ -592,7 +708,7 @@ func TestCancelOrderSuccess(t *testing.T) {
 				t.Fatalf("unexpected store id %s", input.ActorStoreID)
 			}
 			called = true
-			return nil
+			return &internalorders.BuyerCancelResult{Outcome: internalorders.BuyerCancelOutcomeCanceled}, nil
 		},
 	}
 
@@ -615,6 +731,34 @@ func TestCancelOrderSuccess(t *testing.T) {
 	}
 }
 
+func TestCancelOrderOutsideWindowReturnsAccepted(t *testing.T) {
+	storeID := uuid.New()
+	orderID := uuid.New()
+	svc := &stubControllerOrdersService{
+		cancel: func(ctx context.Context, input internalorders.BuyerCancelInput) (*internalorders.BuyerCancelResult, error) {
+			return &internalorders.BuyerCancelResult{Outcome: internalorders.BuyerCancelOutcomeRequested}, nil
+		},
+	}
+
+	handler := CancelOrder(svc, nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+orderID.String()+"/cancel", nil)
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("orderId", orderID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+	req = req.WithContext(middleware.WithStoreID(req.Context(), storeID.String()))
+	req = req.WithContext(middleware.WithStoreType(req.Context(), enums.StoreTypeBuyer))
+	req = req.WithContext(middleware.WithUserID(req.Context(), uuid.New().String()))
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 got %d", resp.Code)
+	}
+	if !strings.Contains(resp.Body.String(), `"outcome":"cancel_requested"`) {
+		t.Fatalf("expected cancel_requested outcome, got %s", resp.Body.String())
+	}
+}
+
 func TestNudgeVendorSuccess(t *testing.T) {
 	storeID := uuid.New()
 	orderID := uuid.New()
@@ -766,3 +910,38 @@ func TestVendorLineItemDecisionInvalidDecision(t *testing.T) {
 		t.Fatalf("expected 400 got %d", resp.Code)
 	}
 }
+
+func TestStoreAddOrderCommentMapsStoreTypeToRole(t *testing.T) {
+	storeID := uuid.New()
+	orderID := uuid.New()
+	var got internalorders.AddCommentInput
+	svc := &stubControllerOrdersService{
+		addComment: func(ctx context.Context, input internalorders.AddCommentInput) (*internalorders.OrderComment, error) {
+			got = input
+			return &internalorders.OrderComment{ID: uuid.New(), OrderID: input.OrderID, Body: input.Body}, nil
+		},
+	}
+
+	handler := StoreAddOrderComment(svc, nil)
+	body := strings.NewReader(`{"body":"Gate code is 4411","visibility":"Internal"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+orderID.String()+"/comments", body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add("orderId", orderID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+	req = req.WithContext(middleware.WithStoreID(req.Context(), storeID.String()))
+	req = req.WithContext(middleware.WithStoreType(req.Context(), enums.StoreTypeVendor))
+	req = req.WithContext(middleware.WithUserID(req.Context(), uuid.New().String()))
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got.Role != internalorders.CommentRoleVendor || got.ActorStoreID != storeID || got.OrderID != orderID {
+		t.Fatalf("unexpected input %+v", got)
+	}
+	if got.Visibility != enums.OrderCommentVisibilityInternal {
+		t.Fatalf("expected internal visibility, got %q", got.Visibility)
+	}
+}
@@ -0,0 +1,189 @@
+package orders
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	internalorders "github.com/angelmondragon/packfinderz-backend/internal/orders"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// VendorProposeSubstitution lets the vendor offer a different product for a pending line item.
+func VendorProposeSubstitution(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(r.Context())
+		if !ok || storeType != enums.StoreTypeVendor {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "vendor store context required"))
+			return
+		}
+
+		storeID, err := parseStoreID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		actorID, err := parseActorID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload vendorSubstitutionRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		lineItemID, err := uuid.Parse(strings.TrimSpace(payload.LineItemID))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid line item id"))
+			return
+		}
+		productID, err := uuid.Parse(strings.TrimSpace(payload.ProductID))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id"))
+			return
+		}
+
+		orderID, err := parseUUIDParam(r, "orderId", "order id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		input := internalorders.ProposeSubstitutionInput{
+			OrderID:             orderID,
+			LineItemID:          lineItemID,
+			SubstituteProductID: productID,
+			Qty:                 payload.Qty,
+			UnitPriceCents:      *payload.UnitPriceCents,
+			ActorUserID:         actorID,
+			ActorStoreID:        storeID,
+			ActorRole:           middleware.RoleFromContext(r.Context()),
+		}
+
+		if err := svc.ProposeSubstitution(r.Context(), input); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, nil)
+	}
+}
+
+// BuyerSubstitutionDecision lets the buyer accept or reject the vendor's substitution offer.
+func BuyerSubstitutionDecision(svc internalorders.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "orders service unavailable"))
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(r.Context())
+		if !ok || storeType != enums.StoreTypeBuyer {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "buyer store context required"))
+			return
+		}
+
+		storeID, err := parseStoreID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		actorID, err := parseActorID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload buyerSubstitutionDecisionRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		orderID, err := parseUUIDParam(r, "orderId", "order id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		lineItemID, err := parseUUIDParam(r, "lineItemId", "line item id")
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		input := internalorders.SubstitutionDecisionInput{
+			OrderID:      orderID,
+			LineItemID:   lineItemID,
+			ActorUserID:  actorID,
+			ActorStoreID: storeID,
+			ActorRole:    middleware.RoleFromContext(r.Context()),
+		}
+
+		switch strings.ToLower(strings.TrimSpace(payload.Decision)) {
+		case "accept":
+			err = svc.AcceptSubstitution(r.Context(), input)
+		case "reject":
+			err = svc.RejectSubstitution(r.Context(), input)
+		default:
+			err = pkgerrors.New(pkgerrors.CodeValidation, "decision must be accept or reject")
+		}
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, nil)
+	}
+}
+
+type vendorSubstitutionRequest struct {
+	LineItemID     string `json:"line_item_id" validate:"required,uuid4"`
+	ProductID      string `json:"product_id" validate:"required,uuid4"`
+	Qty            int    `json:"qty" validate:"required,min=1"`
+	UnitPriceCents *int   `json:"unit_price_cents" validate:"required,min=0"`
+}
+
+type buyerSubstitutionDecisionRequest struct {
+	Decision string `json:"decision" validate:"required"`
+}
+
+func parseActorID(r *http.Request) (uuid.UUID, error) {
+	userID := middleware.UserIDFromContext(r.Context())
+	if userID == "" {
+		return uuid.Nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing")
+	}
+	actorID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id")
+	}
+	return actorID, nil
+}
+
+func parseUUIDParam(r *http.Request, param, label string) (uuid.UUID, error) {
+	raw := strings.TrimSpace(chi.URLParam(r, param))
+	if raw == "" {
+		return uuid.Nil, pkgerrors.New(pkgerrors.CodeValidation, label+" is required")
+	}
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid "+label)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	productsvc "github.com/angelmondragon/packfinderz-backend/internal/products"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type buyerPriceRequest struct {
+	PriceCents *int `json:"price_cents" validate:"required,gte=0"`
+}
+
+// VendorProductBuyerPrices lists the buyer-specific prices on a vendor product.
+func VendorProductBuyerPrices(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		userID, storeID, productID, err := vendorProductRequestIDs(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		prices, err := svc.ListBuyerPrices(r.Context(), userID, storeID, productID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, prices)
+	}
+}
+
+// VendorSetProductBuyerPrice creates or replaces the price one buyer store pays for a vendor product.
+func VendorSetProductBuyerPrice(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		userID, storeID, productID, err := vendorProductRequestIDs(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		buyerStoreID, err := buyerStoreIDParam(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload buyerPriceRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		price, err := svc.SetBuyerPrice(r.Context(), userID, storeID, productID, buyerStoreID, *payload.PriceCents)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, price)
+	}
+}
+
+// VendorDeleteProductBuyerPrice removes a buyer-specific price so the buyer pays the base price again.
+func VendorDeleteProductBuyerPrice(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		userID, storeID, productID, err := vendorProductRequestIDs(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		buyerStoreID, err := buyerStoreIDParam(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		if err := svc.DeleteBuyerPrice(r.Context(), userID, storeID, productID, buyerStoreID); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// vendorProductRequestIDs reads the caller, active store, and {productId} route param.
+func vendorProductRequestIDs(r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, error) {
+	storeID, err := parseStoreID(r)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, err
+	}
+
+	rawUserID := middleware.UserIDFromContext(r.Context())
+	if rawUserID == "" {
+		return uuid.Nil, uuid.Nil, uuid.Nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing")
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id")
+	}
+
+	rawProductID := strings.TrimSpace(chi.URLParam(r, "productId"))
+	if rawProductID == "" {
+		return uuid.Nil, uuid.Nil, uuid.Nil, pkgerrors.New(pkgerrors.CodeValidation, "product id is required")
+	}
+	productID, err := uuid.Parse(rawProductID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id")
+	}
+	return userID, storeID, productID, nil
+}
+
+func buyerStoreIDParam(r *http.Request) (uuid.UUID, error) {
+	raw := strings.TrimSpace(chi.URLParam(r, "buyerStoreId"))
+	if raw == "" {
+		return uuid.Nil, pkgerrors.New(pkgerrors.CodeValidation, "buyer store id is required")
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid buyer store id")
+	}
+	return id, nil
+}
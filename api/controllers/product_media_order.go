@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	productsvc "github.com/angelmondragon/packfinderz-backend/internal/products"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type mediaOrderRequest struct {
+	Media []mediaOrderItemRequest `json:"media" validate:"required,min=1,dive"`
+}
+
+type mediaOrderItemRequest struct {
+	ID        uuid.UUID `json:"id" validate:"required"`
+	IsPrimary bool      `json:"is_primary"`
+}
+
+// VendorReorderProductMedia sets the display order and primary image of a vendor product's media.
+func VendorReorderProductMedia(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		userID, storeID, productID, err := vendorProductRequestIDs(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		var payload mediaOrderRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		order := make([]productsvc.MediaOrderItem, 0, len(payload.Media))
+		for _, item := range payload.Media {
+			order = append(order, productsvc.MediaOrderItem{ID: item.ID, IsPrimary: item.IsPrimary})
+		}
+
+		product, err := svc.ReorderMedia(r.Context(), userID, storeID, productID, order)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, product)
+	}
+}
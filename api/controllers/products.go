@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
@@ -103,6 +104,7 @@ type createProductRequest struct {
 	MediaIDs            []string                      `json:"media_ids,omitempty"`
 	VolumeDiscounts     []createVolumeDiscountRequest `json:"volume_discounts,omitempty" validate:"omitempty,min=1,dive"`
 	CoaMediaID          *string                       `json:"coa_media_id,omitempty"`
+	ExcludedStates      []string                      `json:"excluded_states,omitempty"`
 }
 
 type createInventoryRequest struct {
@@ -203,6 +205,7 @@ type updateProductRequest struct {
 	VolumeDiscounts     *[]updateVolumeDiscountRequest `json:"volume_discounts,omitempty"`
 	CoaMediaID          *string                        `json:"coa_media_id,omitempty"`
 	MaxQty              *int                           `json:"max_qty,omitempty" validate:"omitempty,min=0"`
+	ExcludedStates      *[]string                      `json:"excluded_states,omitempty"`
 }
 
 type updateInventoryRequest struct {
@@ -367,9 +370,146 @@ func (r updateProductRequest) toUpdateInput() (productsvc.UpdateProductInput, er
 		input.MaxQty = &val
 	}
 
+	input.ExcludedStates = r.ExcludedStates
+
 	return input, nil
 }
 
+// VendorBulkImportProducts creates products for the active vendor store from an uploaded CSV.
+// The file may be sent as the "file" field of a multipart form or as a raw text/csv body.
+func VendorBulkImportProducts(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		storeID := middleware.StoreIDFromContext(r.Context())
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		// The upload size is capped by middleware.BodyLimit (PACKFINDERZ_HTTP_BULK_IMPORT_MAX_BODY_BYTES).
+		reader := io.Reader(r.Body)
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				if tooLarge, ok := validators.BodyTooLarge(err); ok {
+					responses.WriteError(r.Context(), logg, w, tooLarge)
+					return
+				}
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "csv file is required"))
+				return
+			}
+			defer file.Close()
+			reader = file
+		}
+
+		result, err := svc.BulkImport(r.Context(), uid, sid, reader)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, result)
+	}
+}
+
+// VendorBulkUpdateInventory sets available stock for many products of the active vendor store at
+// once. The batch is applied atomically: one invalid item fails the whole request.
+func VendorBulkUpdateInventory(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		storeID := middleware.StoreIDFromContext(r.Context())
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		var payload bulkInventoryRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		items, err := payload.toItems()
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		result, err := svc.BulkUpdateInventory(r.Context(), uid, sid, items)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, result)
+	}
+}
+
+type bulkInventoryRequest struct {
+	Items []bulkInventoryItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type bulkInventoryItemRequest struct {
+	ProductID    string `json:"product_id" validate:"required"`
+	AvailableQty *int   `json:"available_qty" validate:"required,min=0"`
+}
+
+func (r bulkInventoryRequest) toItems() ([]productsvc.BulkInventoryItem, error) {
+	items := make([]productsvc.BulkInventoryItem, len(r.Items))
+	for i, item := range r.Items {
+		productID, err := uuid.Parse(strings.TrimSpace(item.ProductID))
+		if err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id")
+		}
+		items[i] = productsvc.BulkInventoryItem{ProductID: productID, AvailableQty: *item.AvailableQty}
+	}
+	return items, nil
+}
+
 // VendorDeleteProduct removes an existing product owned by the active vendor store.
 func VendorDeleteProduct(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -423,6 +563,177 @@ func VendorDeleteProduct(svc productsvc.Service, logg *logger.Logger) http.Handl
 	}
 }
 
+// VendorDuplicateProduct clones a product owned by the active vendor store into an inactive draft.
+func VendorDuplicateProduct(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		storeID := middleware.StoreIDFromContext(r.Context())
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		productIDParam := strings.TrimSpace(chi.URLParam(r, "productId"))
+		if productIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "product id is required"))
+			return
+		}
+
+		productID, err := uuid.Parse(productIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		product, err := svc.DuplicateProduct(r.Context(), uid, sid, productID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, product)
+	}
+}
+
+// VendorRestoreProduct restores a soft-deleted product owned by the active vendor store.
+func VendorRestoreProduct(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		storeID := middleware.StoreIDFromContext(r.Context())
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		productIDParam := strings.TrimSpace(chi.URLParam(r, "productId"))
+		if productIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "product id is required"))
+			return
+		}
+
+		productID, err := uuid.Parse(productIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		product, err := svc.RestoreProduct(r.Context(), uid, sid, productID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, product)
+	}
+}
+
+// VendorInventoryAdjustments lists the inventory audit history for a product owned by the active vendor store.
+func VendorInventoryAdjustments(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		storeID := middleware.StoreIDFromContext(r.Context())
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		productIDParam := strings.TrimSpace(chi.URLParam(r, "productId"))
+		if productIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "product id is required"))
+			return
+		}
+
+		productID, err := uuid.Parse(productIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		params := pagination.Params{Cursor: strings.TrimSpace(r.URL.Query().Get("cursor"))}
+		if limitStr := strings.TrimSpace(r.URL.Query().Get("limit")); limitStr != "" {
+			value, err := strconv.Atoi(limitStr)
+			if err != nil || value <= 0 {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "limit must be a positive integer"))
+				return
+			}
+			params.Limit = value
+		}
+
+		history, err := svc.ListInventoryAdjustments(r.Context(), uid, sid, productID, params)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, history)
+	}
+}
+
 func parseStoreID(r *http.Request) (uuid.UUID, error) {
 	storeID := middleware.StoreIDFromContext(r.Context())
 	if storeID == "" {
@@ -469,6 +780,11 @@ func BrowseProducts(svc productsvc.Service, storeSvc stores.Service, logg *logge
 			return
 		}
 		cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
+		includeTotal, err := validators.ParseQueryBool(r, "include_total", false)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
 
 		filters, err := decodeProductFilters(r)
 		if err != nil {
@@ -510,8 +826,9 @@ func BrowseProducts(svc productsvc.Service, storeSvc stores.Service, logg *logge
 			RequestedState: requestedState,
 			Filters:        filters,
 			Pagination: pagination.Params{
-				Limit:  limit,
-				Cursor: cursor,
+				Limit:        limit,
+				Cursor:       cursor,
+				IncludeTotal: includeTotal,
 			},
 			Page: page,
 		}
@@ -577,6 +894,11 @@ func StorefrontProducts(svc productsvc.Service, storeSvc stores.Service, logg *l
 			return
 		}
 		cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
+		includeTotal, err := validators.ParseQueryBool(r, "include_total", false)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
 
 		filters, err := decodeProductFilters(r)
 		if err != nil {
@@ -589,8 +911,9 @@ func StorefrontProducts(svc productsvc.Service, storeSvc stores.Service, logg *l
 			StoreType: enums.StoreTypeVendor,
 			Filters:   filters,
 			Pagination: pagination.Params{
-				Limit:  limit,
-				Cursor: cursor,
+				Limit:        limit,
+				Cursor:       cursor,
+				IncludeTotal: includeTotal,
 			},
 			Page: page,
 		})
@@ -641,10 +964,79 @@ func ProductDetail(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc
 			return
 		}
 
-		responses.WriteSuccess(w, product)
+		responses.WriteSuccessWithETag(w, r, productETag(product), product)
 	}
 }
 
+// productETag fingerprints a product without its signed media URLs, which change on every read.
+// The earliest URL expiry rides along in the tag, so a cached copy stops revalidating before its
+// URLs stop working.
+func productETag(product *productsvc.ProductDTO) string {
+	if product == nil {
+		return ""
+	}
+	unsigned := *product
+	unsigned.COAReadURL = nil
+	unsigned.Media = make([]productsvc.ProductMediaDTO, len(product.Media))
+	for i, media := range product.Media {
+		media.URL = nil
+		unsigned.Media[i] = media
+	}
+	return responses.ExpiringETag(unsigned, product.UpdatedAt, product.SignedURLsExpireAt)
+}
+
+// RelatedProducts returns the products most often bought together with the given product.
+func RelatedProducts(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "product service unavailable"))
+			return
+		}
+
+		productIDParam := strings.TrimSpace(chi.URLParam(r, "productId"))
+		if productIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "product id is required"))
+			return
+		}
+
+		productID, err := uuid.Parse(productIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid product id"))
+			return
+		}
+
+		storeID, err := parseStoreID(r)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		storeType, ok := middleware.StoreTypeFromContext(r.Context())
+		if !ok {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store type missing"))
+			return
+		}
+
+		limit, err := validators.ParseQueryInt(r, "limit", productsvc.DefaultRelatedProductsLimit, 1, productsvc.MaxRelatedProductsLimit)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		related, err := svc.RelatedProducts(r.Context(), storeID, storeType, productID, limit)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, relatedProductsResponse{Products: related})
+	}
+}
+
+type relatedProductsResponse struct {
+	Products []productsvc.ProductSummary `json:"products"`
+}
+
 func VendorProductList(svc productsvc.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if svc == nil {
@@ -675,6 +1067,11 @@ func VendorProductList(svc productsvc.Service, logg *logger.Logger) http.Handler
 			return
 		}
 		cursor := strings.TrimSpace(r.URL.Query().Get("cursor"))
+		includeTotal, err := validators.ParseQueryBool(r, "include_total", false)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
 
 		filters, err := decodeProductFilters(r)
 		if err != nil {
@@ -687,8 +1084,9 @@ func VendorProductList(svc productsvc.Service, logg *logger.Logger) http.Handler
 			StoreType: enums.StoreTypeVendor,
 			Filters:   filters,
 			Pagination: pagination.Params{
-				Limit:  limit,
-				Cursor: cursor,
+				Limit:        limit,
+				Cursor:       cursor,
+				IncludeTotal: includeTotal,
 			},
 			Page: page,
 		})
@@ -808,6 +1206,7 @@ func (r createProductRequest) toCreateInput() (productsvc.CreateProductInput, er
 		VolumeDiscounts: discounts,
 		COAMediaID:      coaID,
 		MaxQty:          intValue(r.MaxQty),
+		ExcludedStates:  r.ExcludedStates,
 		PackagingType:   packagingTypeCopy,
 	}, nil
 }
@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	productsvc "github.com/angelmondragon/packfinderz-backend/internal/products"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -114,6 +116,46 @@ func (*stubDeleteProductService) ListProducts(ctx context.Context, input product
 	return nil, nil
 }
 
+func (*stubDeleteProductService) BulkImport(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, reader io.Reader) (*productsvc.BulkImportResult, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) DuplicateProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*productsvc.ProductDTO, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) RestoreProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*productsvc.ProductDTO, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) RelatedProducts(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID, limit int) ([]productsvc.ProductSummary, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) BulkUpdateInventory(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, items []productsvc.BulkInventoryItem) (*productsvc.BulkInventoryResult, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) ListInventoryAdjustments(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, params pagination.Params) (*productsvc.InventoryAdjustmentList, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) ListBuyerPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) ([]productsvc.BuyerPriceDTO, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) SetBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID, priceCents int) (*productsvc.BuyerPriceDTO, error) {
+	panic("unimplemented")
+}
+
+func (*stubDeleteProductService) ReorderMedia(ctx context.Context, userID, storeID, productID uuid.UUID, order []productsvc.MediaOrderItem) (*productsvc.ProductDTO, error) {
+	return nil, nil
+}
+
+func (*stubDeleteProductService) DeleteBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID) error {
+	panic("unimplemented")
+}
+
 func TestBrowseProducts(t *testing.T) {
 	logg := logger.New(logger.Options{ServiceName: "test", Level: logger.ParseLevel("debug"), Output: io.Discard})
 	storeID := uuid.New()
@@ -193,7 +235,7 @@ func TestBrowseProducts(t *testing.T) {
 				},
 				Pagination: productsvc.ProductPagination{
 					Page:  1,
-					Total: 1,
+					Total: ptrInt(1),
 					Next:  "next-cursor",
 				},
 			},
@@ -223,6 +265,9 @@ func TestBrowseProducts(t *testing.T) {
 		if stubSvc.lastInput.Filters.Query != "grid" {
 			t.Fatalf("expected query trimmed to %q, got %q", "grid", stubSvc.lastInput.Filters.Query)
 		}
+		if stubSvc.lastInput.Pagination.IncludeTotal {
+			t.Fatalf("expected totals to be skipped by default")
+		}
 	})
 }
 
@@ -249,7 +294,7 @@ func TestVendorProductList(t *testing.T) {
 		ctx := middleware.WithStoreID(context.Background(), storeID.String())
 		ctx = middleware.WithUserID(ctx, userID.String())
 		ctx = middleware.WithStoreType(ctx, enums.StoreTypeVendor)
-		req := httptest.NewRequest(http.MethodGet, "/api/v1/vendor/products?limit=2&q=table&has_promo=false", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/vendor/products?limit=2&q=table&has_promo=false&include_total=true", nil)
 		req = req.WithContext(ctx)
 		rec := httptest.NewRecorder()
 
@@ -282,6 +327,9 @@ func TestVendorProductList(t *testing.T) {
 		if stubSvc.lastInput.StoreType != enums.StoreTypeVendor {
 			t.Fatalf("expected vendor store type input")
 		}
+		if !stubSvc.lastInput.Pagination.IncludeTotal {
+			t.Fatalf("expected include_total=true to request the count")
+		}
 	})
 }
 
@@ -344,7 +392,7 @@ func TestStorefrontProducts(t *testing.T) {
 				Products: []productsvc.ProductSummary{{ID: uuid.New()}},
 				Pagination: productsvc.ProductPagination{
 					Page:  3,
-					Total: 1,
+					Total: ptrInt(1),
 					Next:  "next",
 				},
 			},
@@ -492,6 +540,80 @@ func TestProductDetail(t *testing.T) {
 			t.Fatalf("expected product id %s in service call, got %s", productID, stub.lastProductID)
 		}
 	})
+
+	t.Run("conditional get", func(t *testing.T) {
+		signedURL := func(sig string) *string {
+			url := "https://storage.googleapis.com/bucket/products/flower.png?X-Goog-Signature=" + sig
+			return &url
+		}
+		product := &productsvc.ProductDTO{
+			ID:         productID,
+			SKU:        "sku",
+			Title:      "title",
+			PriceCents: 1000,
+			Media:      []productsvc.ProductMediaDTO{{ID: uuid.New(), URL: signedURL("first"), GCSKey: "products/flower.png"}},
+			UpdatedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+		stub := &stubProductDetailService{result: product}
+		get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+			ctx := middleware.WithStoreID(context.Background(), storeID.String())
+			ctx = middleware.WithStoreType(ctx, enums.StoreTypeBuyer)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("productId", productID.String())
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, routeCtx)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String(), nil).WithContext(ctx)
+			if ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			ProductDetail(stub, logg).ServeHTTP(rec, req)
+			return rec
+		}
+
+		first := get("")
+		etag := first.Header().Get("ETag")
+		if first.Code != http.StatusOK || etag == "" {
+			t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+		}
+
+		// Re-signing the media URL alone must not invalidate the cached copy.
+		product.Media[0].URL = signedURL("second")
+		notModified := get(etag)
+		if notModified.Code != http.StatusNotModified {
+			t.Fatalf("expected 304 for a matching If-None-Match, got %d", notModified.Code)
+		}
+		if notModified.Body.Len() != 0 || notModified.Header().Get("ETag") != etag {
+			t.Fatalf("expected an empty 304 echoing the ETag, got %q %q", notModified.Body.String(), notModified.Header().Get("ETag"))
+		}
+
+		product.PriceCents = 1200
+		product.UpdatedAt = product.UpdatedAt.Add(time.Minute)
+		changed := get(etag)
+		if changed.Code != http.StatusOK {
+			t.Fatalf("expected 200 for a stale If-None-Match, got %d", changed.Code)
+		}
+		if newETag := changed.Header().Get("ETag"); newETag == "" || newETag == etag {
+			t.Fatalf("expected a new ETag, got %q", newETag)
+		}
+		var envelope struct {
+			Data productsvc.ProductDTO `json:"data"`
+		}
+		if err := json.NewDecoder(changed.Body).Decode(&envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if envelope.Data.PriceCents != 1200 {
+			t.Fatalf("expected the updated product, got %+v", envelope.Data)
+		}
+
+		// Once the cached copy's signed URLs are about to lapse, the product comes back in full.
+		product.SignedURLsExpireAt = time.Now().Add(30 * time.Second)
+		expiring := get("").Header().Get("ETag")
+		product.Media[0].URL = signedURL("third")
+		product.SignedURLsExpireAt = time.Now().Add(15 * time.Minute)
+		if refreshed := get(expiring); refreshed.Code != http.StatusOK {
+			t.Fatalf("expected 200 once the cached URLs expire, got %d", refreshed.Code)
+		}
+	})
 }
 
 type stubProductListService struct {
@@ -524,6 +646,46 @@ func (s *stubProductListService) GetProductDetail(ctx context.Context, storeID u
 	return nil, nil
 }
 
+func (s *stubProductListService) BulkImport(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, reader io.Reader) (*productsvc.BulkImportResult, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) DuplicateProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*productsvc.ProductDTO, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) RestoreProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*productsvc.ProductDTO, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) RelatedProducts(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID, limit int) ([]productsvc.ProductSummary, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) BulkUpdateInventory(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, items []productsvc.BulkInventoryItem) (*productsvc.BulkInventoryResult, error) {
+	panic("unimplemented")
+}
+
+func (s *stubProductListService) ListInventoryAdjustments(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, params pagination.Params) (*productsvc.InventoryAdjustmentList, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) ListBuyerPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) ([]productsvc.BuyerPriceDTO, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) SetBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID, priceCents int) (*productsvc.BuyerPriceDTO, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) ReorderMedia(ctx context.Context, userID, storeID, productID uuid.UUID, order []productsvc.MediaOrderItem) (*productsvc.ProductDTO, error) {
+	return nil, nil
+}
+
+func (s *stubProductListService) DeleteBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID) error {
+	return nil
+}
+
 type stubProductDetailService struct {
 	stubProductListService
 	lastStoreID   uuid.UUID
@@ -542,3 +704,7 @@ func (s *stubProductDetailService) GetProductDetail(ctx context.Context, storeID
 	}
 	return s.result, nil
 }
+
+func ptrInt(value int) *int {
+	return &value
+}
@@ -112,6 +112,36 @@ func ListReviews(svc reviews.Service, logg *logger.Logger) http.HandlerFunc {
 	}
 }
 
+// VendorRatingSummary returns the average rating and review count for a vendor store.
+func VendorRatingSummary(svc reviews.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "reviews service unavailable"))
+			return
+		}
+
+		storeID := chi.URLParam(r, "storeId")
+		if storeID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "store id required"))
+			return
+		}
+
+		vendorID, err := uuid.Parse(storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		summary, err := svc.VendorRatingSummary(r.Context(), vendorID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, summary)
+	}
+}
+
 // DeleteReview removes a review when the buyer user created it.
 func DeleteReview(svc reviews.Service, logg *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
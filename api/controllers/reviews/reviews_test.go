@@ -21,6 +21,7 @@ type stubReviewsService struct {
 	createFn func(ctx context.Context, input reviews.CreateReviewInput) (*reviews.Review, error)
 	listFn   func(ctx context.Context, vendorStoreID uuid.UUID, params pagination.Params) (reviews.ReviewListResult, error)
 	deleteFn func(ctx context.Context, reviewID, storeID, userID uuid.UUID) error
+	ratingFn func(ctx context.Context, vendorStoreID uuid.UUID) (reviews.RatingSummary, error)
 }
 
 func (s *stubReviewsService) CreateReview(ctx context.Context, input reviews.CreateReviewInput) (*reviews.Review, error) {
@@ -44,6 +45,13 @@ func (s *stubReviewsService) DeleteReview(ctx context.Context, reviewID, storeID
 	return nil
 }
 
+func (s *stubReviewsService) VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (reviews.RatingSummary, error) {
+	if s.ratingFn != nil {
+		return s.ratingFn(ctx, vendorStoreID)
+	}
+	return reviews.RatingSummary{}, nil
+}
+
 func TestCreateReviewController(t *testing.T) {
 	storeID := uuid.New()
 	userID := uuid.New()
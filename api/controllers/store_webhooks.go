@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/api/middleware"
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	"github.com/angelmondragon/packfinderz-backend/internal/storewebhooks"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type storeWebhookCreateRequest struct {
+	URL    string `json:"url" validate:"required"`
+	Secret string `json:"secret" validate:"required"`
+}
+
+// StoreWebhookList returns the webhook endpoints registered by the active store.
+func StoreWebhookList(svc storewebhooks.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store webhook service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		webhooks, err := svc.List(r.Context(), storeID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, webhooks)
+	}
+}
+
+// StoreWebhookCreate registers an HTTPS endpoint and signing secret for order events.
+func StoreWebhookCreate(svc storewebhooks.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store webhook service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		var payload storeWebhookCreateRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		created, err := svc.Register(r.Context(), storeID, storewebhooks.RegisterInput{
+			URL:    payload.URL,
+			Secret: payload.Secret,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccessStatus(w, http.StatusCreated, created)
+	}
+}
+
+// StoreWebhookDelete removes one of the active store's webhook endpoints.
+func StoreWebhookDelete(svc storewebhooks.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store webhook service unavailable"))
+			return
+		}
+
+		rawStoreID := middleware.StoreIDFromContext(r.Context())
+		if rawStoreID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing"))
+			return
+		}
+
+		storeID, err := uuid.Parse(rawStoreID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		webhookID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "webhookId")))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid webhook id"))
+			return
+		}
+
+		if err := svc.Delete(r.Context(), storeID, webhookID); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+		responses.WriteSuccess(w, map[string]bool{"deleted": true})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -16,6 +17,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 )
 
@@ -45,7 +47,7 @@ func StoreProfile(svc stores.Service, logg *logger.Logger) http.HandlerFunc {
 			return
 		}
 
-		responses.WriteSuccess(w, profile)
+		responses.WriteSuccessWithETag(w, r, responses.ETag(profile, profile.UpdatedAt), profile)
 	}
 }
 
@@ -75,53 +77,70 @@ func StorePublicProfile(svc stores.Service, logg *logger.Logger) http.HandlerFun
 			return
 		}
 
-		responses.WriteSuccess(w, stores.StoreDTO{
-			ID:                   profile.ID,
-			Type:                 profile.Type,
-			CompanyName:          profile.CompanyName,
-			DBAName:              profile.DBAName,
-			Description:          profile.Description,
-			Phone:                profile.Phone,
-			Email:                profile.Email,
-			KYCStatus:            profile.KYCStatus,
-			DeliveryRadiusMeters: profile.DeliveryRadiusMeters,
-			Address:              profile.Address,
-			Social:               profile.Social,
-			BannerURL:            profile.BannerURL,
-			LogoURL:              profile.LogoURL,
-			Ratings:              profile.Ratings,
-			Categories:           profile.Categories,
-			Badge:                profile.Badge,
-			LastActiveAt:         profile.LastActiveAt,
-			Licenses:             profile.Licenses,
-			CreatedAt:            profile.CreatedAt,
-			UpdatedAt:            profile.UpdatedAt,
-		})
+		public := stores.StoreDTO{
+			ID:                     profile.ID,
+			Type:                   profile.Type,
+			CompanyName:            profile.CompanyName,
+			DBAName:                profile.DBAName,
+			Description:            profile.Description,
+			Phone:                  profile.Phone,
+			Email:                  profile.Email,
+			KYCStatus:              profile.KYCStatus,
+			DeliveryRadiusMeters:   profile.DeliveryRadiusMeters,
+			DeliveryZones:          profile.DeliveryZones,
+			BusinessHours:          profile.BusinessHours,
+			AutoAccept:             profile.AutoAccept,
+			VolumeDiscountStrategy: profile.VolumeDiscountStrategy,
+			Address:                profile.Address,
+			Social:                 profile.Social,
+			BannerURL:              profile.BannerURL,
+			LogoURL:                profile.LogoURL,
+			Ratings:                profile.Ratings,
+			Categories:             profile.Categories,
+			Badge:                  profile.Badge,
+			LastActiveAt:           profile.LastActiveAt,
+			Licenses:               profile.Licenses,
+			CreatedAt:              profile.CreatedAt,
+			UpdatedAt:              profile.UpdatedAt,
+		}
+		responses.WriteSuccessWithETag(w, r, responses.ETag(public, public.UpdatedAt), public)
 	}
 }
 
 // StoreUpdateRequest contains the payload for updating store fields.
 type storeUpdateRequest struct {
-	CompanyName   *string            `json:"company_name,omitempty" validate:"omitempty,min=1"`
-	Description   *string            `json:"description,omitempty"`
-	Phone         *string            `json:"phone,omitempty"`
-	Email         *string            `json:"email,omitempty" validate:"omitempty,email"`
-	Social        *types.Social      `json:"social,omitempty"`
-	BannerMediaID types.NullableUUID `json:"banner_media_id,omitempty"`
-	LogoMediaID   types.NullableUUID `json:"logo_media_id,omitempty"`
-	Categories    *[]string          `json:"categories,omitempty"`
+	CompanyName                 *string                       `json:"company_name,omitempty" validate:"omitempty,min=1"`
+	Description                 *string                       `json:"description,omitempty"`
+	Phone                       *string                       `json:"phone,omitempty"`
+	Email                       *string                       `json:"email,omitempty" validate:"omitempty,email"`
+	Social                      *types.Social                 `json:"social,omitempty"`
+	BannerMediaID               types.NullableUUID            `json:"banner_media_id,omitempty"`
+	LogoMediaID                 types.NullableUUID            `json:"logo_media_id,omitempty"`
+	Categories                  *[]string                     `json:"categories,omitempty"`
+	DeliveryZones               *types.DeliveryZones          `json:"delivery_zones,omitempty"`
+	BusinessHours               *types.BusinessHours          `json:"business_hours,omitempty"`
+	AutoAccept                  *bool                         `json:"auto_accept,omitempty"`
+	VolumeDiscountStrategy      *enums.VolumeDiscountStrategy `json:"volume_discount_strategy,omitempty"`
+	TaxExempt                   *bool                         `json:"tax_exempt,omitempty"`
+	TaxExemptCertificateMediaID types.NullableUUID            `json:"tax_exempt_certificate_media_id,omitempty"`
 }
 
 func (r storeUpdateRequest) toInput() (stores.UpdateStoreInput, error) {
 	return stores.UpdateStoreInput{
-		CompanyName:   r.CompanyName,
-		Description:   r.Description,
-		Phone:         r.Phone,
-		Email:         r.Email,
-		Social:        r.Social,
-		BannerMediaID: r.BannerMediaID,
-		LogoMediaID:   r.LogoMediaID,
-		Categories:    r.Categories,
+		CompanyName:                 r.CompanyName,
+		Description:                 r.Description,
+		Phone:                       r.Phone,
+		Email:                       r.Email,
+		Social:                      r.Social,
+		BannerMediaID:               r.BannerMediaID,
+		LogoMediaID:                 r.LogoMediaID,
+		Categories:                  r.Categories,
+		DeliveryZones:               r.DeliveryZones,
+		BusinessHours:               r.BusinessHours,
+		AutoAccept:                  r.AutoAccept,
+		VolumeDiscountStrategy:      r.VolumeDiscountStrategy,
+		TaxExempt:                   r.TaxExempt,
+		TaxExemptCertificateMediaID: r.TaxExemptCertificateMediaID,
 	}, nil
 }
 
@@ -395,3 +414,167 @@ func StoreInvite(svc stores.Service, logg *logger.Logger) http.HandlerFunc {
 		responses.WriteSuccess(w, resp)
 	}
 }
+
+type adminStoreKYCRequest struct {
+	Status string `json:"status" validate:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AdminStoreKYCUpdate moves a store through the KYC workflow on behalf of an admin.
+func AdminStoreKYCUpdate(svc stores.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store service unavailable"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		adminID, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		storeIDParam := strings.TrimSpace(chi.URLParam(r, "storeId"))
+		if storeIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "store id is required"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		var payload adminStoreKYCRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		status, err := enums.ParseKYCStatus(strings.TrimSpace(payload.Status))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid kyc status"))
+			return
+		}
+
+		updated, err := svc.UpdateKYCStatus(r.Context(), adminID, sid, status, payload.Reason)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, updated)
+	}
+}
+
+// StoreSearch lets buyers discover verified vendors by state, name prefix, and carried category.
+func StoreSearch(svc stores.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store service unavailable"))
+			return
+		}
+
+		limit, err := validators.ParseQueryInt(r, "limit", pagination.DefaultLimit, 1, pagination.MaxLimit)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		query := r.URL.Query()
+		input := stores.SearchVendorsInput{
+			State:      strings.ToUpper(strings.TrimSpace(query.Get("state"))),
+			NamePrefix: strings.TrimSpace(query.Get("name")),
+			Pagination: pagination.Params{
+				Limit:  limit,
+				Cursor: strings.TrimSpace(query.Get("cursor")),
+			},
+		}
+		if raw := strings.TrimSpace(query.Get("category")); raw != "" {
+			category, err := enums.ParseProductCategory(raw)
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid category"))
+				return
+			}
+			input.Category = &category
+		}
+
+		result, err := svc.SearchVendors(r.Context(), input)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, result)
+	}
+}
+
+type adminStoreTaxExemptionRequest struct {
+	Decision  string     `json:"decision" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AdminStoreTaxExemptionVerify records an admin's review of a buyer store's tax exemption
+// certificate.
+func AdminStoreTaxExemptionVerify(svc stores.Service, logg *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if svc == nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "store service unavailable"))
+			return
+		}
+
+		userID := middleware.UserIDFromContext(r.Context())
+		if userID == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "user context missing"))
+			return
+		}
+
+		adminID, err := uuid.Parse(userID)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid user id"))
+			return
+		}
+
+		storeIDParam := strings.TrimSpace(chi.URLParam(r, "storeId"))
+		if storeIDParam == "" {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeValidation, "store id is required"))
+			return
+		}
+
+		sid, err := uuid.Parse(storeIDParam)
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid store id"))
+			return
+		}
+
+		var payload adminStoreTaxExemptionRequest
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		decision, err := enums.ParseLicenseStatus(strings.TrimSpace(payload.Decision))
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid decision"))
+			return
+		}
+
+		updated, err := svc.VerifyTaxExemption(r.Context(), adminID, sid, stores.TaxExemptionReview{
+			Decision:  decision,
+			ExpiresAt: payload.ExpiresAt,
+		})
+		if err != nil {
+			responses.WriteError(r.Context(), logg, w, err)
+			return
+		}
+
+		responses.WriteSuccess(w, updated)
+	}
+}
@@ -138,6 +138,52 @@ func TestStorePublicProfileSuccess(t *testing.T) {
 	}
 }
 
+func TestStorePublicProfileConditionalGet(t *testing.T) {
+	storeID := uuid.New()
+	dto := &stores.StoreDTO{
+		ID:          storeID,
+		Type:        enums.StoreTypeVendor,
+		CompanyName: "Vendor HQ",
+		KYCStatus:   enums.KYCStatusVerified,
+		UpdatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	handler := StorePublicProfile(stubStoreService{dto: dto}, nil)
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/"+storeID.String(), nil)
+		req = withRouteParam(req, "storeId", storeID.String())
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+	}
+
+	notModified := get(etag)
+	if notModified.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a matching If-None-Match, got %d", notModified.Code)
+	}
+	if notModified.Body.Len() != 0 {
+		t.Fatalf("expected an empty 304 body, got %q", notModified.Body.String())
+	}
+
+	dto.CompanyName = "Vendor HQ West"
+	dto.UpdatedAt = dto.UpdatedAt.Add(time.Minute)
+	changed := get(etag)
+	if changed.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a stale If-None-Match, got %d", changed.Code)
+	}
+	if newETag := changed.Header().Get("ETag"); newETag == "" || newETag == etag {
+		t.Fatalf("expected a new ETag, got %q", newETag)
+	}
+}
+
 func TestStorePublicProfileNotFound(t *testing.T) {
 	storeID := uuid.New()
 	handler := StorePublicProfile(stubStoreService{
@@ -556,6 +602,10 @@ type stubStoreService struct {
 	inviteErr      error
 	invitePassword string
 	removeErr      error
+	kycResp        *stores.StoreDTO
+	kycErr         error
+	searchResp     *stores.SearchVendorsResult
+	searchErr      error
 }
 
 func (s stubStoreService) GetByID(_ context.Context, _ uuid.UUID) (*stores.StoreDTO, error) {
@@ -586,6 +636,18 @@ func (s stubStoreService) RemoveUser(_ context.Context, _ uuid.UUID, _ uuid.UUID
 	return s.removeErr
 }
 
+func (s stubStoreService) UpdateKYCStatus(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ enums.KYCStatus, _ string) (*stores.StoreDTO, error) {
+	return s.kycResp, s.kycErr
+}
+
+func (s stubStoreService) VerifyTaxExemption(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ stores.TaxExemptionReview) (*stores.StoreDTO, error) {
+	return s.kycResp, s.kycErr
+}
+
+func (s stubStoreService) SearchVendors(_ context.Context, _ stores.SearchVendorsInput) (*stores.SearchVendorsResult, error) {
+	return s.searchResp, s.searchErr
+}
+
 func stringPtr(s string) *string { return &s }
 
 func withRouteParam(req *http.Request, key, value string) *http.Request {
@@ -2,6 +2,7 @@ package subscriptions
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +20,7 @@ type vendorSubscriptionCreateRequest struct {
 	SquareCustomerID      string `json:"square_customer_id" validate:"required"`
 	SquarePaymentMethodID string `json:"square_payment_method_id" validate:"required"`
 	PriceID               string `json:"price_id,omitempty"`
+	CouponCode            string `json:"coupon_code,omitempty"`
 }
 
 type vendorSubscriptionResponse struct {
@@ -55,6 +57,8 @@ func VendorSubscriptionCreate(svc subsvc.Service, logg *logger.Logger) http.Hand
 			SquareCustomerID:      payload.SquareCustomerID,
 			SquarePaymentMethodID: payload.SquarePaymentMethodID,
 			PriceID:               payload.PriceID,
+			IdempotencyKey:        strings.TrimSpace(r.Header.Get("Idempotency-Key")),
+			CouponCode:            payload.CouponCode,
 		})
 		if err != nil {
 			responses.WriteError(r.Context(), logg, w, err)
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	pkgAuth "github.com/angelmondragon/packfinderz-backend/pkg/auth"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// APIKeyRole is the actor role set for API key requests. It matches no member role, so
+// routes gated on admin or agent roles reject it.
+const APIKeyRole = "api_key"
+
+const ctxAPIKey contextKey = "api_key"
+
+// APIKeyAuthenticator resolves a raw API key into the store principal it represents.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*pkgAuth.APIKeyPrincipal, error)
+}
+
+// APIKeyScopeRule grants keys holding Scope access to Method on Pattern (chi route syntax).
+type APIKeyScopeRule struct {
+	Method  string
+	Pattern string
+	Scope   enums.APIKeyScope
+}
+
+// APIKeyPrincipalFromContext returns the API key principal when the request was
+// authenticated with an API key.
+func APIKeyPrincipalFromContext(ctx context.Context) (*pkgAuth.APIKeyPrincipal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(ctxAPIKey).(*pkgAuth.APIKeyPrincipal)
+	return principal, ok && principal != nil
+}
+
+// APIKeyAuth authenticates `Authorization: ApiKey <key>` requests and seeds the store
+// context. Keys may only reach routes listed in rules, and only with the matching scope.
+// Requests using any other scheme pass through to Auth unchanged.
+func APIKeyAuth(authenticator APIKeyAuthenticator, rules []APIKeyScopeRule, logg *logger.Logger) func(http.Handler) http.Handler {
+	matcher := chi.NewRouter()
+	scopes := make(map[string]enums.APIKeyScope, len(rules))
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	for _, rule := range rules {
+		matcher.Method(rule.Method, rule.Pattern, noop)
+		scopes[rule.Method+" "+rule.Pattern] = rule.Scope
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, found := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+			if !found || !strings.EqualFold(scheme, "ApiKey") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if authenticator == nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeInternal, "api key authentication unavailable"))
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				responses.WriteError(r.Context(), logg, w, err)
+				return
+			}
+
+			path := r.URL.Path
+			if len(path) > 1 {
+				path = strings.TrimSuffix(path, "/")
+			}
+			pattern := matcher.Find(chi.NewRouteContext(), r.Method, path)
+			scope, ok := scopes[r.Method+" "+pattern]
+			if pattern == "" || !ok {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "endpoint not available to api keys"))
+				return
+			}
+			if !principal.HasScope(scope) {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeForbidden, "api key missing scope "+string(scope)))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ctxAPIKey, principal)
+			ctx = context.WithValue(ctx, ctxUserID, principal.UserID.String())
+			ctx = context.WithValue(ctx, ctxRole, APIKeyRole)
+			ctx = context.WithValue(ctx, ctxStoreID, principal.StoreID.String())
+			ctx = context.WithValue(ctx, ctxStoreType, principal.StoreType)
+			if logg != nil {
+				ctx = logg.WithFields(ctx, map[string]any{
+					"user_id":    principal.UserID.String(),
+					"actor_role": APIKeyRole,
+					"store_id":   principal.StoreID.String(),
+					"api_key_id": principal.KeyID.String(),
+				})
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
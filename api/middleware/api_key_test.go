@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgAuth "github.com/angelmondragon/packfinderz-backend/pkg/auth"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type stubAPIKeyAuthenticator struct {
+	principals map[string]*pkgAuth.APIKeyPrincipal
+	revoked    map[string]bool
+}
+
+func (s stubAPIKeyAuthenticator) Authenticate(_ context.Context, token string) (*pkgAuth.APIKeyPrincipal, error) {
+	if s.revoked[token] {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "api key revoked")
+	}
+	principal, ok := s.principals[token]
+	if !ok {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "invalid api key")
+	}
+	return principal, nil
+}
+
+var testAPIKeyRules = []APIKeyScopeRule{
+	{Method: http.MethodGet, Pattern: "/api/v1/orders", Scope: enums.APIKeyScopeOrdersRead},
+	{Method: http.MethodGet, Pattern: "/api/v1/orders/{orderId}", Scope: enums.APIKeyScopeOrdersRead},
+	{Method: http.MethodPost, Pattern: "/api/v1/vendor/orders/{orderId}/decision", Scope: enums.APIKeyScopeOrdersWrite},
+}
+
+func newAPIKeyTestHandler(t *testing.T, seen *context.Context) http.Handler {
+	t.Helper()
+	storeID := uuid.New()
+	authenticator := stubAPIKeyAuthenticator{
+		principals: map[string]*pkgAuth.APIKeyPrincipal{
+			"pfz_valid_secret": {
+				KeyID:     uuid.New(),
+				StoreID:   storeID,
+				StoreType: enums.StoreTypeVendor,
+				UserID:    uuid.New(),
+				Scopes:    []enums.APIKeyScope{enums.APIKeyScopeOrdersRead},
+			},
+		},
+		revoked: map[string]bool{"pfz_revoked_secret": true},
+	}
+	cfg := config.JWTConfig{Secret: "secret", Issuer: "issuer", ExpirationMinutes: 10}
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = r.Context()
+		w.WriteHeader(http.StatusOK)
+	})
+	return APIKeyAuth(authenticator, testAPIKeyRules, nil)(Auth(cfg, stubSessionVerifier{ok: true}, nil)(inner))
+}
+
+func TestAPIKeyAuthAcceptsValidKey(t *testing.T) {
+	var seen context.Context
+	handler := newAPIKeyTestHandler(t, &seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "ApiKey pfz_valid_secret")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", resp.Code, resp.Body.String())
+	}
+	if RoleFromContext(seen) != APIKeyRole {
+		t.Fatalf("expected restricted role, got %q", RoleFromContext(seen))
+	}
+	if StoreIDFromContext(seen) == "" || UserIDFromContext(seen) == "" {
+		t.Fatalf("expected store and user context to be set")
+	}
+	if storeType, ok := StoreTypeFromContext(seen); !ok || storeType != enums.StoreTypeVendor {
+		t.Fatalf("expected vendor store type, got %q", storeType)
+	}
+	if _, ok := APIKeyPrincipalFromContext(seen); !ok {
+		t.Fatalf("expected api key principal in context")
+	}
+}
+
+func TestAPIKeyAuthRejectsRevokedKey(t *testing.T) {
+	var seen context.Context
+	handler := newAPIKeyTestHandler(t, &seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("Authorization", "ApiKey pfz_revoked_secret")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	assertErrorResponse(t, resp, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "api key revoked")
+	if seen != nil {
+		t.Fatalf("handler should not run for a revoked key")
+	}
+}
+
+func TestAPIKeyAuthEnforcesScopes(t *testing.T) {
+	var seen context.Context
+	handler := newAPIKeyTestHandler(t, &seen)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/vendor/orders/"+uuid.NewString()+"/decision", nil)
+	req.Header.Set("Authorization", "ApiKey pfz_valid_secret")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assertErrorResponse(t, resp, http.StatusForbidden, pkgerrors.CodeForbidden, "api key missing scope orders:write")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/stores/me", nil)
+	req.Header.Set("Authorization", "ApiKey pfz_valid_secret")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assertErrorResponse(t, resp, http.StatusForbidden, pkgerrors.CodeForbidden, "endpoint not available to api keys")
+
+	if seen != nil {
+		t.Fatalf("handler should not run without the required scope")
+	}
+}
+
+func TestAPIKeyAuthLeavesBearerTokensToAuth(t *testing.T) {
+	var seen context.Context
+	handler := newAPIKeyTestHandler(t, &seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/me", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assertErrorResponse(t, resp, http.StatusUnauthorized, pkgerrors.CodeUnauthorized, "invalid token")
+}
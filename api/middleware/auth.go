@@ -13,10 +13,16 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 )
 
-// Auth validates a bearer token and seeds the request context with the claims.
+// Auth validates a bearer token and seeds the request context with the claims. Requests
+// already authenticated by APIKeyAuth pass through.
 func Auth(cfg config.JWTConfig, verifier session.AccessSessionChecker, logg *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := APIKeyPrincipalFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			raw := strings.TrimSpace(r.Header.Get("Authorization"))
 			if raw == "" {
 				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "missing credentials"))
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 )
@@ -93,6 +94,10 @@ func AuthRateLimit(policy AuthRateLimitPolicy, store rateLimiterStore, logg *log
 				var err error
 				body, err = io.ReadAll(r.Body)
 				if err != nil {
+					if tooLarge, ok := validators.BodyTooLarge(err); ok {
+						responses.WriteError(ctx, nil, w, tooLarge)
+						return
+					}
 					responses.WriteError(ctx, nil, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "read request"))
 					return
 				}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// BodyLimits configures BodyLimit. Routes overrides Default for exact "METHOD /path" keys,
+// e.g. "POST /api/v1/vendor/products/import".
+type BodyLimits struct {
+	Default int64
+	Routes  map[string]int64
+}
+
+func (l BodyLimits) limitFor(r *http.Request) int64 {
+	if limit, ok := l.Routes[r.Method+" "+r.URL.Path]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// BodyLimit caps request bodies with http.MaxBytesReader. It runs before routing so that
+// middleware which buffers the body (idempotency, auth rate limits) sees the same cap as
+// the handler; overrides are therefore matched on the raw path rather than chi patterns.
+func BodyLimit(limits BodyLimits, logg *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limits.limitFor(r)
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeTooLarge, "request body too large").
+					WithDetails(map[string]any{"limit_bytes": limit}))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+type bodyLimitPayload struct {
+	Name string `json:"name"`
+}
+
+func decodingHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload bodyLimitPayload
+		if err := validators.DecodeJSONBody(r, &payload); err != nil {
+			responses.WriteError(r.Context(), nil, w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// streamingBody hides its length so the Content-Length precheck is bypassed.
+type streamingBody struct {
+	io.Reader
+}
+
+func TestBodyLimitRejectsOversizeBody(t *testing.T) {
+	handler := BodyLimit(BodyLimits{Default: 64}, nil)(decodingHandler(t))
+	oversize := `{"name":"` + strings.Repeat("x", 128) + `"}`
+
+	cases := map[string]*http.Request{
+		"declared length": httptest.NewRequest(http.MethodPost, "/api/v1/stores/me", strings.NewReader(oversize)),
+		"streamed body":   httptest.NewRequest(http.MethodPost, "/api/v1/stores/me", streamingBody{strings.NewReader(oversize)}),
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Fatalf("expected 413, got %d", rec.Code)
+			}
+			var body types.ErrorEnvelope
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error envelope: %v", err)
+			}
+			if body.Error.Code != string(pkgerrors.CodeTooLarge) {
+				t.Fatalf("unexpected code %s", body.Error.Code)
+			}
+		})
+	}
+}
+
+func TestBodyLimitRouteOverrideAllowsLargerBody(t *testing.T) {
+	limits := BodyLimits{
+		Default: 64,
+		Routes:  map[string]int64{http.MethodPost + " /api/v1/vendor/products/import": 1024},
+	}
+	handler := BodyLimit(limits, nil)(decodingHandler(t))
+	payload := `{"name":"` + strings.Repeat("x", 128) + `"}`
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/vendor/products/import", strings.NewReader(payload)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected override to admit body, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/media/presign", strings.NewReader(payload)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected default limit on other routes, got %d", rec.Code)
+	}
+}
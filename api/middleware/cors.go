@@ -19,8 +19,8 @@ func CORS() func(http.Handler) http.Handler {
 	return cors.New(cors.Options{
 		AllowedOrigins:   defaultCORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-PF-Token", "Idempotency-Key", "X-Requested-With"},
-		ExposedHeaders:   []string{"X-PF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-PF-Token", "Idempotency-Key", "X-Requested-With", "X-Request-ID", "If-None-Match"},
+		ExposedHeaders:   []string{"X-PF-Token", "X-Request-ID", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}).Handler
@@ -16,6 +16,7 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	"github.com/angelmondragon/packfinderz-backend/api/validators"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	pkgredis "github.com/angelmondragon/packfinderz-backend/pkg/redis"
@@ -24,6 +25,9 @@ import (
 const (
 	defaultIdempotencyTTL  = 24 * time.Hour
 	criticalIdempotencyTTL = 7 * 24 * time.Hour
+	// idempotencyLockTTL bounds how long an in-flight request holds its key, so a key whose
+	// holder died without releasing it frees itself. It must outlive the longest route timeout.
+	idempotencyLockTTL = 2 * time.Minute
 )
 
 type routeMatcher func(string) bool
@@ -40,6 +44,7 @@ var idempotencyRules = []idempotencyRule{
 	{method: http.MethodPost, matcher: matchExact("/api/v1/stores/me/users/invite"), ttl: defaultIdempotencyTTL},
 	{method: http.MethodPost, matcher: matchExact("/api/v1/licenses"), ttl: defaultIdempotencyTTL},
 	{method: http.MethodPost, matcher: matchExact("/api/v1/media/presign"), ttl: defaultIdempotencyTTL},
+	{method: http.MethodPost, matcher: matchExact("/api/v1/media/presign/batch"), ttl: defaultIdempotencyTTL},
 	{method: http.MethodPost, matcher: matchExact("/api/v1/media/finalize"), ttl: defaultIdempotencyTTL},
 	{method: http.MethodPost, matcher: matchPrefixSuffix("/api/v1/products/", "/media/attach"), ttl: defaultIdempotencyTTL},
 	{method: http.MethodPut, matcher: matchExact("/api/v1/cart"), ttl: defaultIdempotencyTTL},
@@ -86,6 +91,10 @@ func Idempotency(store pkgredis.IdempotencyStore, logg *logger.Logger) func(http
 
 			body, err := io.ReadAll(r.Body)
 			if err != nil {
+				if tooLarge, ok := validators.BodyTooLarge(err); ok {
+					responses.WriteError(r.Context(), logg, w, tooLarge)
+					return
+				}
 				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "read request"))
 				return
 			}
@@ -95,26 +104,42 @@ func Idempotency(store pkgredis.IdempotencyStore, logg *logger.Logger) func(http
 			scope := buildScope(r)
 			key := store.IdempotencyKey(scope, idempotencyKey)
 
-			if stored, getErr := store.Get(r.Context(), key); getErr != nil && !errors.Is(getErr, redis.Nil) {
-				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, getErr, "check idempotency"))
+			if replayed := replayStored(w, r, store, key, requestHash, logg); replayed {
 				return
-			} else if stored != "" {
-				record, decodeErr := decodeRecord(stored)
-				if decodeErr != nil {
-					responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, decodeErr, "decode idempotency record"))
-					return
-				}
-				if record.RequestHash != requestHash {
-					responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeIdempotency, "idempotency key reused with different request body"))
-					return
+			}
+
+			// Claim the key so a concurrent duplicate cannot run the handler a second time.
+			lockKey := key + ":lock"
+			acquired, lockErr := store.SetNX(r.Context(), lockKey, requestHash, idempotencyLockTTL)
+			if lockErr != nil {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, lockErr, "lock idempotency key"))
+				return
+			}
+			if !acquired {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeIdempotency, "request with this idempotency key is still in progress"))
+				return
+			}
+			// Deferred so the key is freed however the request ends, including a panic.
+			defer func() {
+				if delErr := store.Del(context.WithoutCancel(r.Context()), lockKey); delErr != nil {
+					logError(r.Context(), logg, "release idempotency lock", delErr)
 				}
-				writeStoredResponse(w, record)
+			}()
+
+			// The first request may have finished between the lookup and the lock.
+			if replayed := replayStored(w, r, store, key, requestHash, logg); replayed {
 				return
 			}
 
 			rec := &responseCapture{ResponseWriter: w}
 			next.ServeHTTP(rec, r)
 
+			// Server errors and timeouts are not recorded, so a retry with the same key runs the
+			// handler again instead of replaying the failure.
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+
 			record := idempotencyRecord{
 				Status:      defaultStatus(rec.status),
 				Body:        base64.StdEncoding.EncodeToString(rec.body.Bytes()),
@@ -137,6 +162,33 @@ func Idempotency(store pkgredis.IdempotencyStore, logg *logger.Logger) func(http
 	}
 }
 
+// replayStored writes the cached response (or a conflict for a different body) and reports
+// whether the request was answered.
+func replayStored(w http.ResponseWriter, r *http.Request, store pkgredis.IdempotencyStore, key, requestHash string, logg *logger.Logger) bool {
+	stored, err := store.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false
+		}
+		responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check idempotency"))
+		return true
+	}
+	if stored == "" {
+		return false
+	}
+	record, err := decodeRecord(stored)
+	if err != nil {
+		responses.WriteError(r.Context(), logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "decode idempotency record"))
+		return true
+	}
+	if record.RequestHash != requestHash {
+		responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeIdempotency, "idempotency key reused with different request body"))
+		return true
+	}
+	writeStoredResponse(w, record)
+	return true
+}
+
 func buildScope(r *http.Request) string {
 	parts := []string{
 		UserIDFromContext(r.Context()),
@@ -175,3 +175,91 @@ func TestIdempotencyMiddlewareDetectsBodyChange(t *testing.T) {
 		t.Fatalf("expected error code %s got %s", pkgerrors.CodeIdempotency, payload.Error.Code)
 	}
 }
+
+func TestIdempotencyMiddlewareRejectsConcurrentDuplicate(t *testing.T) {
+	store := newFakeStore()
+	mw := Idempotency(store, nil)
+	var calls int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := requestWithPattern(http.MethodPost, "/api/v1/checkout", "/api/v1/checkout", strings.NewReader(`{"cart_id":"c1"}`))
+	req.Header.Set("Idempotency-Key", "dup")
+	lockKey := store.IdempotencyKey(buildScope(req), "dup") + ":lock"
+	store.data[lockKey] = "in-flight"
+
+	resp := httptest.NewRecorder()
+	mw(handler).ServeHTTP(resp, req)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while the first request is in flight, got %d", resp.Code)
+	}
+	if calls != 0 {
+		t.Fatalf("handler should not run for an in-flight duplicate")
+	}
+
+	delete(store.data, lockKey)
+	retry := requestWithPattern(http.MethodPost, "/api/v1/checkout", "/api/v1/checkout", strings.NewReader(`{"cart_id":"c1"}`))
+	retry.Header.Set("Idempotency-Key", "dup")
+	mw(handler).ServeHTTP(httptest.NewRecorder(), retry)
+	if calls != 1 {
+		t.Fatalf("expected handler to run once the key is free, ran %d times", calls)
+	}
+	if _, held := store.data[lockKey]; held {
+		t.Fatalf("expected lock to be released after the response")
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesLockAfterServerError(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusGatewayTimeout} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			store := newFakeStore()
+			mw := Idempotency(store, nil)
+			var calls int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(status)
+			})
+
+			req := requestWithPattern(http.MethodPost, "/api/v1/checkout", "/api/v1/checkout", strings.NewReader(`{"cart_id":"c1"}`))
+			req.Header.Set("Idempotency-Key", "failed")
+			key := store.IdempotencyKey(buildScope(req), "failed")
+
+			mw(handler).ServeHTTP(httptest.NewRecorder(), req)
+			if _, stored := store.data[key]; stored {
+				t.Fatalf("expected no response to be recorded for a %d", status)
+			}
+			if _, held := store.data[key+":lock"]; held {
+				t.Fatalf("expected the lock to be released after a %d", status)
+			}
+
+			retry := requestWithPattern(http.MethodPost, "/api/v1/checkout", "/api/v1/checkout", strings.NewReader(`{"cart_id":"c1"}`))
+			retry.Header.Set("Idempotency-Key", "failed")
+			mw(handler).ServeHTTP(httptest.NewRecorder(), retry)
+			if calls != 2 {
+				t.Fatalf("expected the retry to run the handler again, ran %d times", calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesLockOnPanic(t *testing.T) {
+	store := newFakeStore()
+	mw := Idempotency(store, nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := requestWithPattern(http.MethodPost, "/api/v1/checkout", "/api/v1/checkout", strings.NewReader(`{"cart_id":"c1"}`))
+	req.Header.Set("Idempotency-Key", "panicked")
+	lockKey := store.IdempotencyKey(buildScope(req), "panicked") + ":lock"
+
+	func() {
+		defer func() { _ = recover() }()
+		mw(handler).ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	if _, held := store.data[lockKey]; held {
+		t.Fatalf("expected the lock to be released when the handler panics")
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
+)
+
+// Metrics records request count, latency, and in-flight requests. Routes are labeled with
+// the matched chi pattern (e.g. /api/v1/orders/{orderId}) rather than the raw path.
+func Metrics(m *metrics.HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			m.RequestStarted()
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				route := ""
+				if rctx := chi.RouteContext(r.Context()); rctx != nil {
+					route = rctx.RoutePattern()
+				}
+				m.RequestFinished(route, r.Method, defaultStatus(rec.status), time.Since(start))
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
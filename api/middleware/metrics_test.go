@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
+)
+
+func TestMetricsCountsRequestsByRoutePattern(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(Metrics(metrics.NewHTTPMetrics(reg)))
+	router.Get("/api/v1/orders/{orderId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, id := range []string{"a", "b"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id, nil))
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	var got float64
+	var found bool
+	for _, mf := range mfs {
+		if mf.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			if labels["route"] == "/api/v1/orders/{orderId}" && labels["method"] == http.MethodGet && labels["status"] == "2xx" {
+				got = metric.GetCounter().GetValue()
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected counter for matched route pattern, got %v", mfs)
+	}
+	if got != 2 {
+		t.Fatalf("expected 2 requests recorded for pattern, got %f", got)
+	}
+}
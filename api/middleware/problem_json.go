@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+)
+
+// ProblemJSON switches error responses to RFC 7807 problem+json when the client lists
+// application/problem+json in Accept. Other clients keep the legacy error envelope.
+func ProblemJSON() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if responses.AcceptsProblemJSON(r.Header.Get("Accept")) {
+				r = r.WithContext(responses.ContextWithProblemJSON(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
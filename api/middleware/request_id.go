@@ -2,25 +2,31 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 )
 
-const requestIDHeader = "X-Request-Id"
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
 
+// RequestID accepts a caller-supplied X-Request-ID (or generates one), stores it on the
+// request context for logs and outbox events, and echoes it on the response.
 func RequestID(logg *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			reqID := r.Header.Get(requestIDHeader)
+			reqID := sanitizeRequestID(r.Header.Get(requestIDHeader))
 			if reqID == "" {
 				reqID = uuid.NewString()
 			}
 
 			w.Header().Set(requestIDHeader, reqID)
 
-			ctx := r.Context()
+			ctx := logger.ContextWithRequestID(r.Context(), reqID)
 			if logg != nil {
 				ctx = logg.WithRequestID(ctx, reqID)
 			}
@@ -29,3 +35,18 @@ func RequestID(logg *logger.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// sanitizeRequestID drops client IDs that are too long or contain characters outside
+// printable ASCII so they cannot forge log lines.
+func sanitizeRequestID(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > maxRequestIDLength {
+		return ""
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x21 || value[i] > 0x7e {
+			return ""
+		}
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+func TestRequestIDEchoesHeaderAndTagsLogs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logg := logger.New(logger.Options{ServiceName: "test", Output: buf})
+
+	var ctxRequestID string
+	handler := RequestID(logg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = logger.RequestIDFromContext(r.Context())
+		ctx := logg.WithFields(r.Context(), map[string]any{"step": "handler"})
+		logg.Info(ctx, "handled")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-abc-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("X-Request-ID"); got != "req-abc-123" {
+		t.Fatalf("expected echoed request id, got %q", got)
+	}
+	if ctxRequestID != "req-abc-123" {
+		t.Fatalf("expected request id on context, got %q", ctxRequestID)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("decode log entry: %v (%s)", err, buf.String())
+	}
+	if entry["request_id"] != "req-abc-123" {
+		t.Fatalf("expected request_id in log fields, got %v", entry)
+	}
+	if entry["step"] != "handler" {
+		t.Fatalf("expected handler fields preserved, got %v", entry)
+	}
+}
+
+func TestRequestIDGeneratesWhenMissingOrUnsafe(t *testing.T) {
+	for name, header := range map[string]string{
+		"missing":  "",
+		"unsafe":   "bad id\ninjected",
+		"too long": strings.Repeat("a", maxRequestIDLength+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := RequestID(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if logger.RequestIDFromContext(r.Context()) == "" {
+					t.Fatalf("expected generated request id on context")
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if header != "" {
+				req.Header.Set("X-Request-ID", header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("X-Request-ID")
+			if got == "" || got == header {
+				t.Fatalf("expected freshly generated request id, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// ScrapeToken admits requests carrying the static bearer token shared with the metrics scraper.
+// It is not tied to a user session, so the scraper needs no admin account.
+func ScrapeToken(token string, logg *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := strings.TrimSpace(r.Header.Get("Authorization"))
+			if len(raw) < 7 || !strings.EqualFold(raw[:7], "bearer ") {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "missing credentials"))
+				return
+			}
+			provided := strings.TrimSpace(raw[7:])
+			if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeUnauthorized, "invalid scrape token"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type tokenBucketStore interface {
+	TokenBucketAllow(ctx context.Context, scope string, rate float64, burst int64) (bool, time.Duration, error)
+}
+
+// StoreRateLimitPolicy allows a store limit requests per window on one route, refilled evenly
+// so a quiet store can burst up to the full limit.
+type StoreRateLimitPolicy struct {
+	name   string
+	window time.Duration
+	limit  int
+}
+
+// NewStoreRateLimitPolicy builds a per-store policy; a zero limit or window disables it.
+func NewStoreRateLimitPolicy(name string, window time.Duration, limit int) StoreRateLimitPolicy {
+	return StoreRateLimitPolicy{
+		name:   strings.ToLower(strings.TrimSpace(name)),
+		window: window,
+		limit:  limit,
+	}
+}
+
+func (p StoreRateLimitPolicy) enabled() bool {
+	return p.window > 0 && p.limit > 0
+}
+
+func (p StoreRateLimitPolicy) rate() float64 {
+	return float64(p.limit) / p.window.Seconds()
+}
+
+func (p StoreRateLimitPolicy) scope(storeID string) string {
+	return fmt.Sprintf("store:%s:%s", p.name, storeID)
+}
+
+// StoreRateLimit throttles the active store with a Redis token bucket. Requests without a store
+// context pass through; a store over its limit gets 429 with Retry-After set to the wait in seconds.
+func StoreRateLimit(policy StoreRateLimitPolicy, store tokenBucketStore, logg *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !policy.enabled() || store == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			storeID := StoreIDFromContext(ctx)
+			if storeID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, wait, err := store.TokenBucketAllow(ctx, policy.scope(storeID), policy.rate(), int64(policy.limit))
+			if err != nil {
+				responses.WriteError(ctx, logg, w, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "rate limiting"))
+				return
+			}
+			if !allowed {
+				retryAfter := int(math.Max(1, math.Ceil(wait.Seconds())))
+				if logg != nil {
+					logCtx := logg.WithFields(ctx, map[string]any{
+						"policy":              policy.name,
+						"store_id":            storeID,
+						"limit":               policy.limit,
+						"window_seconds":      int(policy.window.Seconds()),
+						"retry_after_seconds": retryAfter,
+					})
+					logg.Warn(logCtx, "store.rate_limit.blocked")
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				responses.WriteError(ctx, logg, w, pkgerrors.New(pkgerrors.CodeRateLimit, "rate limit exceeded"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
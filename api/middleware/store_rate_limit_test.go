@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+func TestStoreRateLimit_BlocksStorePastLimit(t *testing.T) {
+	store := newFakeTokenBucket()
+	policy := NewStoreRateLimitPolicy("checkout", time.Minute, 2)
+	handler := StoreRateLimit(policy, store, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(storeID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/checkout", nil)
+		req = req.WithContext(WithStoreID(req.Context(), storeID))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := send("store-a"); rec.Code != http.StatusOK {
+			t.Fatalf("expected success before limit, got %d", rec.Code)
+		}
+	}
+
+	rec := send("store-a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Fatalf("expected Retry-After 30, got %q", got)
+	}
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if payload.Error.Code != string(pkgerrors.CodeRateLimit) {
+		t.Fatalf("unexpected code: %s", payload.Error.Code)
+	}
+
+	if rec := send("store-b"); rec.Code != http.StatusOK {
+		t.Fatalf("expected other stores to keep their own bucket, got %d", rec.Code)
+	}
+	if store.lastRate != 2.0/60 {
+		t.Fatalf("expected refill of 2 tokens per minute, got %v", store.lastRate)
+	}
+}
+
+func TestStoreRateLimit_SkipsRequestsWithoutStore(t *testing.T) {
+	store := newFakeTokenBucket()
+	handler := StoreRateLimit(NewStoreRateLimitPolicy("quote", time.Minute, 1), store, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/cart", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected success without store context, got %d", rec.Code)
+		}
+	}
+}
+
+// fakeTokenBucket hands out burst tokens per scope and never refills, reporting a fixed wait.
+type fakeTokenBucket struct {
+	mu       sync.Mutex
+	taken    map[string]int64
+	lastRate float64
+}
+
+func newFakeTokenBucket() *fakeTokenBucket {
+	return &fakeTokenBucket{taken: map[string]int64{}}
+}
+
+func (f *fakeTokenBucket) TokenBucketAllow(ctx context.Context, scope string, rate float64, burst int64) (bool, time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastRate = rate
+	if f.taken[scope] >= burst {
+		return false, time.Duration(float64(time.Second)/rate) - 500*time.Millisecond, nil
+	}
+	f.taken[scope]++
+	return true, 0, nil
+}
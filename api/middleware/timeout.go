@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/api/responses"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+// Timeout cancels the request context after d and answers 504 with the standard error
+// envelope when the handler has not finished by then. The handler's response is buffered
+// so nothing it writes after the deadline reaches the client. A non-positive d disables it.
+//
+// The handler keeps running after the 504 until it notices the cancelled context, so a 504
+// does not mean its writes were rolled back. Handlers that commit should check ctx.Err()
+// right before committing.
+//
+// A handler that calls Flush sends what it has buffered so far and writes straight through from
+// then on. Once the response has started, a timeout can no longer turn it into a 504, so the
+// client just sees the stream end when the context is cancelled.
+func Timeout(d time.Duration, logg *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				// Re-raise on the serving goroutine so Recoverer handles it.
+				panic(p)
+			case <-done:
+				tw.finish()
+			case <-ctx.Done():
+				started := tw.expire()
+				if !started && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					responses.WriteError(r.Context(), logg, w, pkgerrors.New(pkgerrors.CodeTimeout, "request timed out"))
+				}
+			}
+		})
+	}
+}
+
+// MethodTimeout applies read to GET/HEAD/OPTIONS requests and write to every other method.
+func MethodTimeout(read, write time.Duration, logg *logger.Logger) func(http.Handler) http.Handler {
+	readTimeout := Timeout(read, logg)
+	writeTimeout := Timeout(write, logg)
+	return func(next http.Handler) http.Handler {
+		reads := readTimeout(next)
+		writes := writeTimeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				reads.ServeHTTP(w, r)
+			default:
+				writes.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+type timeoutWriter struct {
+	mu       sync.Mutex
+	w        http.ResponseWriter
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+	// flushed is set once the handler flushed, after which writes go straight to w.
+	flushed bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.status != 0 {
+		return
+	}
+	tw.status = code
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	if tw.flushed {
+		return tw.w.Write(b)
+	}
+	return tw.body.Write(b)
+}
+
+// Flush commits the buffered response to the client and flushes it.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.commit()
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// expire stops the handler from writing and reports whether the response had already started.
+func (tw *timeoutWriter) expire() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+	return tw.flushed
+}
+
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.commit()
+}
+
+// commit writes the header and buffered body to w, once. Callers hold mu.
+func (tw *timeoutWriter) commit() {
+	if !tw.flushed {
+		dst := tw.w.Header()
+		for key, values := range tw.header {
+			dst[key] = values
+		}
+		if tw.status == 0 {
+			tw.status = http.StatusOK
+		}
+		tw.w.WriteHeader(tw.status)
+		tw.flushed = true
+	}
+	if tw.body.Len() > 0 {
+		_, _ = tw.w.Write(tw.body.Bytes())
+		tw.body.Reset()
+	}
+}
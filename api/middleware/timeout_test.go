@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+func TestTimeoutReturns504WhenHandlerOverruns(t *testing.T) {
+	logg := logger.New(logger.Options{ServiceName: "test", Output: io.Discard})
+	released := make(chan struct{})
+	handler := Timeout(20*time.Millisecond, logg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(released)
+		time.Sleep(100 * time.Millisecond)
+		if r.Context().Err() == nil {
+			t.Errorf("expected request context to be cancelled")
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("late")); err != http.ErrHandlerTimeout {
+			t.Errorf("expected late write to fail with ErrHandlerTimeout, got %v", err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	<-released
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+	var body types.ErrorEnvelope
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error envelope: %v", err)
+	}
+	if body.Error.Code != string(pkgerrors.CodeTimeout) {
+		t.Fatalf("unexpected code %s", body.Error.Code)
+	}
+}
+
+func TestTimeoutPassesThroughFastResponses(t *testing.T) {
+	handler := Timeout(time.Second, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Errorf("expected deadline on request context")
+		}
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fast", nil))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "ok" || rec.Header().Get("X-Test") != "yes" {
+		t.Fatalf("unexpected response %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestMethodTimeoutAppliesReadAndWriteBudgets(t *testing.T) {
+	var deadlines = map[string]time.Duration{}
+	handler := MethodTimeout(time.Second, time.Minute, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ := r.Context().Deadline()
+		deadlines[r.Method] = time.Until(deadline)
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/", nil))
+	}
+
+	if deadlines[http.MethodGet] > time.Second {
+		t.Fatalf("expected read budget for GET, got %s", deadlines[http.MethodGet])
+	}
+	if deadlines[http.MethodPost] <= time.Second {
+		t.Fatalf("expected write budget for POST, got %s", deadlines[http.MethodPost])
+	}
+}
+
+func TestTimeoutStreamsFlushedResponses(t *testing.T) {
+	flushed := make(chan struct{})
+	resume := make(chan struct{})
+	rec := httptest.NewRecorder()
+	handler := Timeout(time.Second, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Errorf("expected the timeout writer to implement http.Flusher")
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("a,b\n"))
+		flusher.Flush()
+		close(flushed)
+		<-resume
+		_, _ = w.Write([]byte("1,2\n"))
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	}()
+	<-flushed
+	if !rec.Flushed || rec.Body.String() != "a,b\n" {
+		t.Fatalf("expected the first chunk to reach the client before the handler finished, got %q", rec.Body.String())
+	}
+	close(resume)
+	<-done
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "a,b\n1,2\n" || rec.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("unexpected response %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestTimeoutAfterFlushEndsStreamWithout504(t *testing.T) {
+	released := make(chan struct{})
+	handler := Timeout(20*time.Millisecond, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(released)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	<-released
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Fatalf("expected the started response to be left as is, got %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,106 @@
+package responses
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+// minCachedURLLifetime is how long the signed URLs in a cached copy must still be valid for a
+// conditional GET to answer 304, leaving the client time to use them.
+const minCachedURLLifetime = time.Minute
+
+// ETag fingerprints a resource from its JSON encoding and last update time. It returns "" when the
+// resource cannot be encoded, which makes WriteSuccessWithETag fall back to an unconditional 200.
+func ETag(resource any, updatedAt time.Time) string {
+	hash := etagHash(resource, updatedAt)
+	if hash == "" {
+		return ""
+	}
+	return `"` + hash + `"`
+}
+
+// ExpiringETag is ETag for a resource carrying signed URLs that stop working at expiresAt. The
+// expiry is part of the tag, so a cached copy only revalidates while its own URLs are still good.
+// A zero expiresAt gives a plain ETag.
+func ExpiringETag(resource any, updatedAt, expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return ETag(resource, updatedAt)
+	}
+	hash := etagHash(resource, updatedAt)
+	if hash == "" {
+		return ""
+	}
+	return `"` + hash + "-" + strconv.FormatInt(expiresAt.Unix(), 10) + `"`
+}
+
+func etagHash(resource any, updatedAt time.Time) string {
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.New()
+	sum.Write(raw)
+	sum.Write([]byte(updatedAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum.Sum(nil)[:16])
+}
+
+// WriteSuccessWithETag writes data with the given ETag, or an empty 304 when the request's
+// If-None-Match already names it. For an ExpiringETag the 304 echoes the client's own tag, so the
+// expiry it holds keeps describing the URLs in its cached body.
+func WriteSuccessWithETag(w http.ResponseWriter, r *http.Request, etag string, data any) {
+	if etag == "" {
+		WriteSuccess(w, data)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if matched, ok := matchETag(r.Header.Get("If-None-Match"), etag, time.Now()); ok {
+		w.Header().Set("ETag", matched)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	writeJSON(w, http.StatusOK, types.SuccessEnvelope{Data: data})
+}
+
+// matchETag applies the weak comparison If-None-Match calls for, so W/ prefixes are ignored. An
+// expiring candidate matches when its hash is current and its URLs outlive minCachedURLLifetime.
+// It returns the tag to send with the 304.
+func matchETag(ifNoneMatch, etag string, now time.Time) (string, bool) {
+	etag = strings.TrimPrefix(etag, "W/")
+	hash, _, expiring := splitETag(etag)
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return etag, true
+		}
+		if !expiring {
+			continue
+		}
+		candidateHash, expiresAt, ok := splitETag(candidate)
+		if ok && candidateHash == hash && expiresAt.After(now.Add(minCachedURLLifetime)) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// splitETag takes an ExpiringETag apart; ok is false for any other tag.
+func splitETag(etag string) (hash string, expiresAt time.Time, ok bool) {
+	inner := strings.Trim(etag, `"`)
+	idx := strings.LastIndex(inner, "-")
+	if idx < 0 {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(inner[idx+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return inner[:idx], time.Unix(unix, 0), true
+}
@@ -0,0 +1,73 @@
+package responses
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+// ProblemJSONContentType is the RFC 7807 media type clients send in Accept to opt in to problem
+// details error bodies.
+const ProblemJSONContentType = "application/problem+json"
+
+// problemTypeBase prefixes the problem type URIs. They identify the error code and are not
+// expected to resolve.
+const problemTypeBase = "https://api.packfinderz.com/problems/"
+
+type problemJSONKey struct{}
+
+// ContextWithProblemJSON marks the request as preferring problem+json error bodies.
+func ContextWithProblemJSON(ctx context.Context) context.Context {
+	return context.WithValue(ctx, problemJSONKey{}, true)
+}
+
+// WantsProblemJSON reports whether WriteError should render problem+json for this request.
+func WantsProblemJSON(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(problemJSONKey{}).(bool)
+	return v
+}
+
+// AcceptsProblemJSON reports whether an Accept header explicitly lists application/problem+json.
+// Wildcards do not count, so clients that never asked keep the legacy envelope.
+func AcceptsProblemJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemJSONContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// problemType maps an error code to its type URI, e.g. NOT_FOUND to .../problems/not-found.
+func problemType(code pkgerrors.Code) string {
+	return problemTypeBase + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+func problemTitle(publicMessage string) string {
+	if publicMessage == "" {
+		return ""
+	}
+	return strings.ToUpper(publicMessage[:1]) + publicMessage[1:]
+}
+
+func writeProblem(w http.ResponseWriter, problem types.Problem) {
+	w.Header().Set("Content-Type", ProblemJSONContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf(`{"level":"error","msg":"failed to encode response","err":"%v"}`, err)
+	}
+}
@@ -41,23 +41,18 @@ func WriteError(ctx context.Context, logg *logger.Logger, w http.ResponseWriter,
 		pkgerrors.CodeConflict,
 		pkgerrors.CodeStateConflict,
 		pkgerrors.CodeIdempotency,
-		pkgerrors.CodeRateLimit:
+		pkgerrors.CodeRateLimit,
+		pkgerrors.CodeTooLarge:
 		if m := typed.Message(); m != "" {
 			msg = m
 		}
 	}
 
-	payload := types.ErrorEnvelope{
-		Error: types.APIError{
-			Code:    string(typed.Code()),
-			Message: msg,
-		},
-	}
-
+	var details any
+	var fields map[string]string
 	if meta.DetailsAllowed {
-		if details := typed.Details(); details != nil {
-			payload.Error.Details = details
-		}
+		details = typed.Details()
+		fields = typed.FieldErrors()
 	}
 
 	if logg != nil {
@@ -87,6 +82,29 @@ func WriteError(ctx context.Context, logg *logger.Logger, w http.ResponseWriter,
 		logg.Error(ctx, "request.error", err)
 	}
 
+	if WantsProblemJSON(ctx) {
+		writeProblem(w, types.Problem{
+			Type:    problemType(typed.Code()),
+			Title:   problemTitle(meta.PublicMessage),
+			Status:  meta.HTTPStatus,
+			Detail:  msg,
+			Code:    string(typed.Code()),
+			Details: details,
+			Errors:  fields,
+		})
+		return
+	}
+
+	payload := types.ErrorEnvelope{
+		Error: types.APIError{
+			Code:    string(typed.Code()),
+			Message: msg,
+			Details: details,
+		},
+	}
+	if len(fields) > 0 {
+		payload.Error.Errors = fields
+	}
 	writeJSON(w, meta.HTTPStatus, payload)
 }
 
@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
@@ -72,3 +74,166 @@ func TestWriteErrorDefaultsToInternalForUntrustedErrors(t *testing.T) {
 		t.Fatalf("details should be omitted for internal errors")
 	}
 }
+
+func TestWriteErrorSerializesFieldErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := pkgerrors.NewValidationError("validation failed", map[string]string{
+		"email":    "is required",
+		"password": "must be at least 8",
+		"store_id": "is invalid",
+	})
+	WriteError(context.Background(), logger.New(logger.Options{ServiceName: "test", Output: io.Discard}), w, err)
+
+	if got := w.Code; got != http.StatusBadRequest {
+		t.Fatalf("expected status 400 but got %d", got)
+	}
+
+	var body types.ErrorEnvelope
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode error envelope: %v", err)
+	}
+	want := map[string]string{
+		"email":    "is required",
+		"password": "must be at least 8",
+		"store_id": "is invalid",
+	}
+	if len(body.Error.Errors) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), body.Error.Errors)
+	}
+	for field, msg := range want {
+		if body.Error.Errors[field] != msg {
+			t.Fatalf("field %s: expected %q got %q", field, msg, body.Error.Errors[field])
+		}
+	}
+}
+
+func TestWriteErrorOmitsFieldErrorsForNonValidationCodes(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := pkgerrors.New(pkgerrors.CodeForbidden, "nope").
+		WithFieldErrors(map[string]string{"role": "is invalid"})
+	WriteError(context.Background(), logger.New(logger.Options{ServiceName: "test", Output: io.Discard}), w, err)
+
+	var body map[string]map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode error envelope: %v", err)
+	}
+	if _, ok := body["error"]["errors"]; ok {
+		t.Fatalf("field errors should be omitted when details are not allowed")
+	}
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) types.Problem {
+	t.Helper()
+	if got := w.Header().Get("Content-Type"); got != ProblemJSONContentType {
+		t.Fatalf("expected problem+json content type, got %q", got)
+	}
+	var body types.Problem
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	return body
+}
+
+func TestWriteErrorRendersValidationProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := pkgerrors.NewValidationError("email is invalid", map[string]string{"email": "is invalid"})
+	ctx := ContextWithProblemJSON(context.Background())
+	WriteError(ctx, logger.New(logger.Options{ServiceName: "test", Output: io.Discard}), w, err)
+
+	if got := w.Code; got != http.StatusBadRequest {
+		t.Fatalf("expected status 400 but got %d", got)
+	}
+	body := decodeProblem(t, w)
+	want := types.Problem{
+		Type:   "https://api.packfinderz.com/problems/validation-error",
+		Title:  "Validation failed",
+		Status: http.StatusBadRequest,
+		Detail: "email is invalid",
+		Code:   string(pkgerrors.CodeValidation),
+		Errors: map[string]string{"email": "is invalid"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected problem %+v", body)
+	}
+}
+
+func TestWriteErrorRendersNotFoundProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := pkgerrors.New(pkgerrors.CodeNotFound, "order not found").WithDetails(map[string]string{"order_id": "abc"})
+	ctx := ContextWithProblemJSON(context.Background())
+	WriteError(ctx, logger.New(logger.Options{ServiceName: "test", Output: io.Discard}), w, err)
+
+	if got := w.Code; got != http.StatusNotFound {
+		t.Fatalf("expected status 404 but got %d", got)
+	}
+	body := decodeProblem(t, w)
+	want := types.Problem{
+		Type:   "https://api.packfinderz.com/problems/not-found",
+		Title:  "Resource not found",
+		Status: http.StatusNotFound,
+		Detail: "order not found",
+		Code:   string(pkgerrors.CodeNotFound),
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected problem %+v", body)
+	}
+}
+
+func TestAcceptsProblemJSON(t *testing.T) {
+	cases := map[string]bool{
+		"":                         false,
+		"application/json":         false,
+		"*/*":                      false,
+		"application/problem+json": true,
+		"application/json, application/problem+json;q=0.9": true,
+		"application/problem+json;q=0":                     false,
+	}
+	for accept, want := range cases {
+		if got := AcceptsProblemJSON(accept); got != want {
+			t.Fatalf("AcceptsProblemJSON(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
+
+func TestWriteSuccessWithExpiringETag(t *testing.T) {
+	resource := map[string]string{"sku": "sku"}
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	write := func(etag, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		WriteSuccessWithETag(w, req, etag, resource)
+		return w
+	}
+
+	cached := ExpiringETag(resource, updatedAt, time.Now().Add(10*time.Minute))
+	resigned := ExpiringETag(resource, updatedAt, time.Now().Add(20*time.Minute))
+	if cached == resigned {
+		t.Fatalf("expected the expiry to change the tag, got %q twice", cached)
+	}
+
+	// Re-signing alone revalidates, and the 304 keeps the expiry the client holds.
+	w := write(resigned, "W/"+cached)
+	if w.Code != http.StatusNotModified || w.Header().Get("ETag") != cached {
+		t.Fatalf("expected a 304 echoing the cached tag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// A cached copy whose URLs are about to expire is refreshed.
+	expiring := ExpiringETag(resource, updatedAt, time.Now().Add(30*time.Second))
+	w = write(resigned, expiring)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != resigned {
+		t.Fatalf("expected a 200 with the new tag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// A changed resource never matches, whatever the expiry.
+	changed := ExpiringETag(map[string]string{"sku": "other"}, updatedAt, time.Now().Add(20*time.Minute))
+	if w := write(changed, cached); w.Code != http.StatusOK {
+		t.Fatalf("expected a 200 for a changed resource, got %d", w.Code)
+	}
+
+	if plain := ExpiringETag(resource, updatedAt, time.Time{}); plain != ETag(resource, updatedAt) {
+		t.Fatalf("expected a zero expiry to give a plain tag, got %q", plain)
+	}
+}
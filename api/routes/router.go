@@ -3,8 +3,10 @@ package routes
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/angelmondragon/packfinderz-backend/api/controllers"
 	analysiscontrollers "github.com/angelmondragon/packfinderz-backend/api/controllers/analytics"
@@ -19,9 +21,12 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/internal/address"
 	"github.com/angelmondragon/packfinderz-backend/internal/ads"
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics"
+	"github.com/angelmondragon/packfinderz-backend/internal/apikeys"
 	"github.com/angelmondragon/packfinderz-backend/internal/auth"
 	"github.com/angelmondragon/packfinderz-backend/internal/cart"
 	checkoutsvc "github.com/angelmondragon/packfinderz-backend/internal/checkout"
+	"github.com/angelmondragon/packfinderz-backend/internal/featureflags"
+	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
 	"github.com/angelmondragon/packfinderz-backend/internal/licenses"
 	"github.com/angelmondragon/packfinderz-backend/internal/media"
 	"github.com/angelmondragon/packfinderz-backend/internal/notifications"
@@ -31,6 +36,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/internal/reviews"
 	"github.com/angelmondragon/packfinderz-backend/internal/squarecustomers"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/internal/storewebhooks"
 	subscriptionsvc "github.com/angelmondragon/packfinderz-backend/internal/subscriptions"
 	squarewebhook "github.com/angelmondragon/packfinderz-backend/internal/webhooks/square"
 	"github.com/angelmondragon/packfinderz-backend/internal/wishlist"
@@ -40,6 +46,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 	"github.com/angelmondragon/packfinderz-backend/pkg/square"
 	gcs "github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
@@ -57,6 +64,26 @@ var vendorBillingRoles = []enums.MemberRole{
 	enums.MemberRoleManager,
 }
 
+// apiKeyScopeRules lists every route an API key may call. Anything else is rejected
+// before JWT auth runs, so new routes stay closed to integrations until added here.
+var apiKeyScopeRules = []middleware.APIKeyScopeRule{
+	{Method: http.MethodGet, Pattern: "/api/v1/orders", Scope: enums.APIKeyScopeOrdersRead},
+	{Method: http.MethodGet, Pattern: "/api/v1/orders/{orderId}", Scope: enums.APIKeyScopeOrdersRead},
+	{Method: http.MethodPost, Pattern: "/api/v1/vendor/orders/{orderId}/decision", Scope: enums.APIKeyScopeOrdersWrite},
+	{Method: http.MethodPost, Pattern: "/api/v1/vendor/orders/{orderId}/line-items/decision", Scope: enums.APIKeyScopeOrdersWrite},
+	{Method: http.MethodPut, Pattern: "/api/v1/vendor/orders/{orderId}/pickup-window", Scope: enums.APIKeyScopeOrdersWrite},
+	{Method: http.MethodGet, Pattern: "/api/v1/vendor/products", Scope: enums.APIKeyScopeProductsRead},
+	{Method: http.MethodGet, Pattern: "/api/v1/vendor/products/{productId}/inventory/adjustments", Scope: enums.APIKeyScopeProductsRead},
+	{Method: http.MethodPost, Pattern: "/api/v1/vendor/products", Scope: enums.APIKeyScopeProductsWrite},
+	{Method: http.MethodPost, Pattern: "/api/v1/vendor/products/import", Scope: enums.APIKeyScopeProductsWrite},
+	{Method: http.MethodPatch, Pattern: "/api/v1/vendor/products/{productId}", Scope: enums.APIKeyScopeProductsWrite},
+}
+
+// dbPoolSampler is implemented by *db.Client; the sampler runs for the life of the process.
+type dbPoolSampler interface {
+	SamplePoolStats(ctx context.Context, interval time.Duration, observer db.PoolStatsObserver)
+}
+
 func NewRouter(
 	cfg *config.Config,
 	logg *logger.Logger,
@@ -89,23 +116,46 @@ func NewRouter(
 	subscriptionsService subscriptionsvc.Service,
 	paymentMethodService paymentsvc.Service,
 	billingService billingcontrollers.ChargesService,
+	billingInvoicesService billingcontrollers.InvoicesService,
 	billingPaymentMethodsService billingcontrollers.PaymentMethodsService,
 	billingPlanService billingcontrollers.BillingPlanService,
 	squareClient *square.Client,
 	squareWebhookService *squarewebhook.Service,
 	squareWebhookGuard *squarewebhook.IdempotencyGuard,
 	addressService address.Service,
+	ledgerService ledger.Service,
+	storeWebhookService storewebhooks.Service,
+	apiKeyService apikeys.Service,
+	featureFlagService featureflags.Service,
+	cronJobRuns controllers.CronJobRuns,
 ) http.Handler {
 	r := chi.NewRouter()
 	// if squareClient != nil && logg != nil {
 	// 	ctx := logg.WithField(context.Background(), "square_env", squareClient.Environment())
 	// 	logg.Info(ctx, "square client wired to API routes")
 	// }
+	metricsRegistry := metrics.NewRegistry()
+	httpMetrics := metrics.NewHTTPMetrics(metricsRegistry)
+	if pool, ok := dbP.(dbPoolSampler); ok {
+		go pool.SamplePoolStats(context.Background(), cfg.DB.PoolStatsInterval, metrics.NewDBPoolMetrics(metricsRegistry))
+	}
+
 	r.Use(
 		middleware.CORS(),
+		middleware.ProblemJSON(),
+		// Metrics wraps Recoverer so panics are counted with the 500 it writes.
+		middleware.Metrics(httpMetrics),
 		middleware.Recoverer(logg),
 		middleware.RequestID(logg),
 		middleware.Logging(logg),
+		middleware.BodyLimit(middleware.BodyLimits{
+			Default: cfg.HTTP.MaxBodyBytes,
+			Routes: map[string]int64{
+				http.MethodPost + " /api/v1/vendor/products/import": cfg.HTTP.BulkImportMaxBodyBytes,
+				http.MethodPost + " /api/v1/media/presign":          cfg.HTTP.MediaMaxBodyBytes,
+				http.MethodPost + " /api/v1/media/presign/batch":    cfg.HTTP.MediaMaxBodyBytes,
+			},
+		}, logg),
 	)
 
 	loginPolicy := middleware.NewAuthRateLimitPolicy(
@@ -121,27 +171,49 @@ func NewRouter(
 		cfg.AuthRateLimit.RegisterEmailLimit,
 	)
 
+	storeLimits := cfg.StoreRateLimit
+	quoteLimit := middleware.StoreRateLimit(middleware.NewStoreRateLimitPolicy("quote", storeLimits.Window, storeLimits.QuoteLimit), redisClient, logg)
+	checkoutLimit := middleware.StoreRateLimit(middleware.NewStoreRateLimitPolicy("checkout", storeLimits.Window, storeLimits.CheckoutLimit), redisClient, logg)
+	analyticsLimit := middleware.StoreRateLimit(middleware.NewStoreRateLimitPolicy("analytics", storeLimits.Window, storeLimits.AnalyticsLimit), redisClient, logg)
+
+	requestTimeout := middleware.MethodTimeout(cfg.HTTP.ReadTimeout, cfg.HTTP.WriteTimeout, logg)
+
+	healthLive := controllers.HealthLive(cfg)
+	healthReady := controllers.HealthReady(cfg, logg, dbP, redisClient, gcsClient, bigqueryClient)
+	r.Get("/healthz", healthLive)
+	r.Get("/readyz", healthReady)
 	r.Route("/health", func(r chi.Router) {
-		r.Get("/live", controllers.HealthLive(cfg))
-		r.Get("/ready", controllers.HealthReady(cfg, logg, dbP, redisClient, gcsClient, bigqueryClient))
+		r.Get("/live", healthLive)
+		r.Get("/ready", healthReady)
 	})
 
+	// Metrics expose route patterns and traffic volume, so they are only served to a scraper holding
+	// the configured token; without one the endpoint is not mounted.
+	if cfg.HTTP.MetricsScrapeToken != "" {
+		r.With(middleware.ScrapeToken(cfg.HTTP.MetricsScrapeToken, logg)).
+			Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	}
+
 	r.Route("/api/public", func(r chi.Router) {
+		r.Use(requestTimeout)
 		r.Get("/ping", controllers.PublicPing())
 		r.Post("/validate", controllers.PublicValidate(logg))
 	})
 
 	r.Route("/api/v1/address", func(r chi.Router) {
+		r.Use(requestTimeout)
 		r.Use(middleware.RateLimit())
 		r.Get("/suggest", controllers.AddressSuggest(addressService, logg))
 		r.Post("/resolve", controllers.AddressResolve(addressService, logg))
 	})
 
 	r.Route("/api/v1/webhooks", func(r chi.Router) {
+		r.Use(requestTimeout)
 		r.Post("/square", webhookcontrollers.SquareWebhook(squareWebhookService, squareClient, squareWebhookGuard, logg))
 	})
 
 	r.Route("/api/v1/auth", func(r chi.Router) {
+		r.Use(requestTimeout)
 		r.With(middleware.AuthRateLimit(loginPolicy, redisClient, logg)).Post("/login", authcontrollers.AuthLogin(authService, logg))
 		r.With(middleware.AuthRateLimit(registerPolicy, redisClient, logg)).Post("/register", authcontrollers.AuthRegister(registerService, authService, logg))
 		r.Post("/logout", authcontrollers.AuthLogout(sessionManager, cfg.JWT, logg))
@@ -150,6 +222,7 @@ func NewRouter(
 	})
 
 	r.Route("/api/admin/v1/auth", func(r chi.Router) {
+		r.Use(requestTimeout)
 		if !cfg.App.IsProd() {
 			r.Post("/register", authcontrollers.AdminAuthRegister(adminRegisterService, authService, cfg, logg))
 		}
@@ -157,20 +230,33 @@ func NewRouter(
 	})
 
 	r.Route("/api", func(r chi.Router) {
+		r.Use(middleware.APIKeyAuth(apiKeyService, apiKeyScopeRules, logg))
 		r.Use(middleware.Auth(cfg.JWT, sessionManager, logg))
 		r.Use(middleware.Idempotency(redisClient, logg))
 		r.Use(middleware.RateLimit())
 
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.StoreContext(logg))
+			r.Use(requestTimeout)
 			r.Get("/ping", controllers.PrivatePing())
 			r.Route("/v1/vendor", func(r chi.Router) {
 				r.Get("/products", controllers.VendorProductList(productService, logg))
 				r.Post("/products", controllers.VendorCreateProduct(productService, logg))
+				r.Post("/products/import", controllers.VendorBulkImportProducts(productService, logg))
+				r.Post("/products/inventory/bulk", controllers.VendorBulkUpdateInventory(productService, logg))
 				r.Patch("/products/{productId}", controllers.VendorUpdateProduct(productService, logg))
+				r.Post("/products/{productId}/duplicate", controllers.VendorDuplicateProduct(productService, logg))
+				r.Post("/products/{productId}/restore", controllers.VendorRestoreProduct(productService, logg))
+				r.Put("/products/{productId}/media/order", controllers.VendorReorderProductMedia(productService, logg))
+				r.Get("/products/{productId}/inventory/adjustments", controllers.VendorInventoryAdjustments(productService, logg))
+				r.Get("/products/{productId}/buyer-prices", controllers.VendorProductBuyerPrices(productService, logg))
+				r.Put("/products/{productId}/buyer-prices/{buyerStoreId}", controllers.VendorSetProductBuyerPrice(productService, logg))
+				r.Delete("/products/{productId}/buyer-prices/{buyerStoreId}", controllers.VendorDeleteProductBuyerPrice(productService, logg))
 				r.Delete("/products/{productId}", controllers.VendorDeleteProduct(productService, logg))
 
 				r.Get("/billing/charges", billingcontrollers.VendorBillingCharges(billingService, logg))
+				r.Get("/billing/invoices", billingcontrollers.VendorBillingInvoices(billingInvoicesService, logg))
+				r.Get("/billing/invoices/{invoiceId}/download", billingcontrollers.VendorBillingInvoiceDownload(billingInvoicesService, logg))
 				r.Route("/payment-methods", func(r chi.Router) {
 					r.Use(middleware.RequireStoreRoles(membershipChecker, logg, vendorBillingRoles...))
 					r.Get("/", billingcontrollers.VendorPaymentMethodsList(billingPaymentMethodsService, logg))
@@ -186,6 +272,9 @@ func NewRouter(
 
 				r.Post("/orders/{orderId}/decision", ordercontrollers.VendorOrderDecision(ordersSvc, logg))
 				r.Post("/orders/{orderId}/line-items/decision", ordercontrollers.VendorLineItemDecision(ordersSvc, logg))
+				r.Post("/orders/{orderId}/line-items/substitution", ordercontrollers.VendorProposeSubstitution(ordersSvc, logg))
+				r.Put("/orders/{orderId}/pickup-window", ordercontrollers.VendorSchedulePickup(ordersSvc, logg))
+				r.Post("/orders/{orderId}/cancel-request/decision", ordercontrollers.VendorCancelRequestDecision(ordersSvc, logg))
 
 				r.Route("/subscriptions", func(r chi.Router) {
 					r.Use(middleware.RequireStoreRoles(membershipChecker, logg, vendorBillingRoles...))
@@ -195,6 +284,7 @@ func NewRouter(
 					r.Post("/resume", subscriptionControllers.VendorSubscriptionResume(subscriptionsService, logg))
 					r.Get("/", subscriptionControllers.VendorSubscriptionFetch(subscriptionsService, logg))
 				})
+				r.With(analyticsLimit).Get("/analytics", analysiscontrollers.VendorAnalytics(analyticsService, logg))
 				r.Route("/ads", func(r chi.Router) {
 					r.Post("/", controllers.VendorCreateAd(adsService, logg))
 					r.Get("/", controllers.VendorListAds(adsService, logg))
@@ -209,16 +299,30 @@ func NewRouter(
 			})
 
 			r.Route("/v1/analytics", func(r chi.Router) {
-				r.Get("/marketplace", analysiscontrollers.MarketplaceAnalytics(analyticsService, logg))
+				r.With(analyticsLimit).Get("/marketplace", analysiscontrollers.MarketplaceAnalytics(analyticsService, logg))
 			})
 
 			r.Route("/v1/stores", func(r chi.Router) {
 				r.Get("/me", controllers.StoreProfile(storeService, logg))
+				r.Get("/search", controllers.StoreSearch(storeService, logg))
 				r.Put("/me", controllers.StoreUpdate(storeService, logg))
 				r.Get("/me/users", controllers.StoreUsers(storeService, logg))
 				r.Post("/me/users/invite", controllers.StoreInvite(storeService, logg))
 				r.Delete("/me/users/{userId}", controllers.StoreRemoveUser(storeService, logg))
+				r.Route("/me/webhooks", func(r chi.Router) {
+					r.Use(middleware.RequireStoreRoles(membershipChecker, logg, vendorBillingRoles...))
+					r.Get("/", controllers.StoreWebhookList(storeWebhookService, logg))
+					r.Post("/", controllers.StoreWebhookCreate(storeWebhookService, logg))
+					r.Delete("/{webhookId}", controllers.StoreWebhookDelete(storeWebhookService, logg))
+				})
+				r.Route("/me/api-keys", func(r chi.Router) {
+					r.Use(middleware.RequireStoreRoles(membershipChecker, logg, apikeys.ManagerRoles...))
+					r.Get("/", controllers.APIKeyList(apiKeyService, logg))
+					r.Post("/", controllers.APIKeyCreate(apiKeyService, logg))
+					r.Delete("/{keyId}", controllers.APIKeyRevoke(apiKeyService, logg))
+				})
 				r.Get("/{storeId}/reviews", reviewcontrollers.ListReviews(reviewsService, logg))
+				r.Get("/{storeId}/reviews/summary", reviewcontrollers.VendorRatingSummary(reviewsService, logg))
 				r.Get("/{storeId}/orders", ordercontrollers.StorefrontOrders(ordersRepo, storeService, logg))
 				r.Get("/{storeId}/products", controllers.StorefrontProducts(productService, storeService, logg))
 				r.Get("/{storeId}", controllers.StorePublicProfile(storeService, logg))
@@ -227,7 +331,11 @@ func NewRouter(
 			r.Route("/v1/media", func(r chi.Router) {
 				r.Get("/", controllers.MediaList(mediaService, logg))
 				r.Post("/presign", controllers.MediaPresign(mediaService, logg))
+				r.Post("/presign/batch", controllers.MediaPresignBatch(mediaService, logg))
+				r.Post("/{mediaId}/confirm", controllers.MediaConfirmUpload(mediaService, logg))
 				r.Delete("/{mediaId}", controllers.MediaDelete(mediaService, logg))
+				r.Post("/{mediaId}/undelete", controllers.MediaUndelete(mediaService, logg))
+				r.Get("/{mediaId}/read-url", controllers.MediaReadURL(mediaService, logg))
 			})
 
 			r.Route("/v1/licenses", func(r chi.Router) {
@@ -256,10 +364,11 @@ func NewRouter(
 
 			r.Get("/v1/products", controllers.BrowseProducts(productService, storeService, logg))
 			r.Get("/v1/products/{productId}", controllers.ProductDetail(productService, logg))
+			r.Get("/v1/products/{productId}/related", controllers.RelatedProducts(productService, logg))
 
 			r.Route("/v1/cart", func(r chi.Router) {
 				r.Get("/", cartcontrollers.CartFetch(cartService, logg))
-				r.Post("/", cartcontrollers.CartQuote(cartService, logg))
+				r.With(quoteLimit).Post("/", cartcontrollers.CartQuote(cartService, logg))
 			})
 
 			r.Route("/v1/orders", func(r chi.Router) {
@@ -268,23 +377,41 @@ func NewRouter(
 				r.Post("/{orderId}/cancel", ordercontrollers.CancelOrder(ordersSvc, logg))
 				r.Post("/{orderId}/nudge", ordercontrollers.NudgeVendor(ordersSvc, logg))
 				r.Post("/{orderId}/retry", ordercontrollers.RetryOrder(ordersSvc, logg))
+				r.Post("/{orderId}/line-items/{lineItemId}/substitution", ordercontrollers.BuyerSubstitutionDecision(ordersSvc, logg))
+				r.Get("/{orderId}/comments", ordercontrollers.StoreOrderComments(ordersSvc, logg))
+				r.Post("/{orderId}/comments", ordercontrollers.StoreAddOrderComment(ordersSvc, logg))
 			})
 
-			r.Post("/v1/checkout", controllers.Checkout(checkoutService, storeService, logg))
 			r.Get("/v1/checkout/{identifier}/confirmation", controllers.CheckoutConfirmation(checkoutRepo, storeService, logg))
 		})
 
+		// Checkout reserves inventory across vendors, so it gets a longer budget than other writes.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.StoreContext(logg))
+			r.Use(middleware.Timeout(cfg.HTTP.CheckoutTimeout, logg))
+			r.Use(checkoutLimit)
+			r.Post("/v1/checkout", controllers.Checkout(checkoutService, storeService, logg))
+		})
+
 		r.Route("/v1/agent", func(r chi.Router) {
 			r.Use(middleware.RequireRole("agent", logg))
+			r.Use(requestTimeout)
 			r.Get("/ping", controllers.AgentPing())
 			r.Route("/orders", func(r chi.Router) {
 				r.Get("/", controllers.AgentAssignedOrders(ordersRepo, logg))
 				r.Get("/queue", controllers.AgentOrderQueue(ordersRepo, logg))
 				r.Get("/{orderId}", controllers.AgentAssignedOrderDetail(ordersRepo, logg))
+				r.Post("/{orderId}/claim", controllers.AgentClaimOrder(ordersSvc, logg))
 				r.Post("/{orderId}/pickup", controllers.AgentPickupOrder(ordersSvc, logg))
 				r.Post("/{orderId}/deliver", controllers.AgentDeliverOrder(ordersSvc, logg))
 				r.Post("/{orderId}/cash-collected", controllers.AgentCashCollectedOrder(ordersSvc, logg))
+				r.Get("/{orderId}/comments", ordercontrollers.AgentOrderComments(ordersSvc, logg))
+				r.Post("/{orderId}/comments", ordercontrollers.AgentAddOrderComment(ordersSvc, logg))
 			})
+			if ledgerService != nil {
+				r.Get("/cash", controllers.AgentCashReconciliation(ledgerService, logg))
+				r.Get("/earnings", controllers.AgentEarnings(ledgerService, logg))
+			}
 		})
 	})
 
@@ -293,27 +420,54 @@ func NewRouter(
 		r.Use(middleware.RequireRole("admin", logg))
 		r.Use(middleware.Idempotency(redisClient, logg))
 		r.Use(middleware.RateLimit())
-		r.Get("/ping", controllers.AdminPing())
-		r.Route("/v1/square/customers", func(r chi.Router) {
-			if squareCustomerService != nil && storeRepo != nil {
-				r.Post("/", controllers.AdminSquareCustomerEnsure(squareCustomerService, storeRepo, logg))
+		// The accounting export streams its CSV for as long as the window takes, so it is mounted
+		// outside the request timeout and runs until it finishes or the client disconnects.
+		r.Get("/v1/orders/accounting-export", controllers.AdminOrdersAccountingExport(ordersRepo, logg))
+
+		r.Group(func(r chi.Router) {
+			r.Use(requestTimeout)
+			r.Get("/ping", controllers.AdminPing())
+			r.Route("/v1/square/customers", func(r chi.Router) {
+				if squareCustomerService != nil && storeRepo != nil {
+					r.Post("/", controllers.AdminSquareCustomerEnsure(squareCustomerService, storeRepo, logg))
+				}
+			})
+			r.Route("/v1/licenses", func(r chi.Router) {
+				r.Post("/{licenseId}/verify", controllers.AdminLicenseVerify(licenseService, logg))
+			})
+			r.Get("/v1/media/access-logs", controllers.AdminMediaAccessLogs(mediaService, logg))
+			r.Route("/v1/stores", func(r chi.Router) {
+				r.Post("/{storeId}/kyc", controllers.AdminStoreKYCUpdate(storeService, logg))
+				r.Post("/{storeId}/tax-exemption/verify", controllers.AdminStoreTaxExemptionVerify(storeService, logg))
+			})
+			r.Route("/v1/orders", func(r chi.Router) {
+				r.Route("/payouts", func(r chi.Router) {
+					r.Get("/", controllers.AdminPayoutOrders(ordersRepo, logg))
+					r.Get("/{orderId}", controllers.AdminPayoutOrderDetail(ordersRepo, logg))
+				})
+				r.Post("/{orderId}/confirm-payout", controllers.AdminConfirmPayout(ordersSvc, logg))
+				r.Get("/{orderId}/comments", ordercontrollers.AdminOrderComments(ordersSvc, logg))
+				r.Post("/{orderId}/comments", ordercontrollers.AdminAddOrderComment(ordersSvc, logg))
+			})
+			if ledgerService != nil {
+				r.Route("/v1/agents/{agentId}/cash", func(r chi.Router) {
+					r.Get("/", controllers.AdminAgentCashReconciliation(ledgerService, logg))
+					r.Post("/remittances", controllers.AdminRecordCashRemittance(ledgerService, logg))
+				})
 			}
-		})
-		r.Route("/v1/licenses", func(r chi.Router) {
-			r.Post("/{licenseId}/verify", controllers.AdminLicenseVerify(licenseService, logg))
-		})
-		r.Route("/v1/orders", func(r chi.Router) {
-			r.Route("/payouts", func(r chi.Router) {
-				r.Get("/", controllers.AdminPayoutOrders(ordersRepo, logg))
-				r.Get("/{orderId}", controllers.AdminPayoutOrderDetail(ordersRepo, logg))
+			r.Route("/v1/feature-flags", func(r chi.Router) {
+				r.Get("/", controllers.AdminFeatureFlagList(featureFlagService, logg))
+				r.Put("/{key}", controllers.AdminFeatureFlagSet(featureFlagService, logg))
+				r.Delete("/{key}", controllers.AdminFeatureFlagClear(featureFlagService, logg))
+			})
+			r.Post("/v1/cron/jobs/{job}/run", controllers.AdminCronJobRun(cronJobRuns, logg))
+			r.Get("/v1/cron/runs/{runId}", controllers.AdminCronJobRunStatus(cronJobRuns, logg))
+			r.Route("/v1/billing/plans", func(r chi.Router) {
+				r.Get("/", billingcontrollers.AdminBillingPlansList(billingPlanService, logg))
+				r.Post("/", billingcontrollers.AdminBillingPlanCreate(billingPlanService, logg))
+				r.Patch("/{planId}", billingcontrollers.AdminBillingPlanUpdate(billingPlanService, logg))
+				r.Delete("/{planId}", billingcontrollers.AdminBillingPlanDelete(billingPlanService, logg))
 			})
-			r.Post("/{orderId}/confirm-payout", controllers.AdminConfirmPayout(ordersSvc, logg))
-		})
-		r.Route("/v1/billing/plans", func(r chi.Router) {
-			r.Get("/", billingcontrollers.AdminBillingPlansList(billingPlanService, logg))
-			r.Post("/", billingcontrollers.AdminBillingPlanCreate(billingPlanService, logg))
-			r.Patch("/{planId}", billingcontrollers.AdminBillingPlanUpdate(billingPlanService, logg))
-			r.Delete("/{planId}", billingcontrollers.AdminBillingPlanDelete(billingPlanService, logg))
 		})
 	})
 
@@ -96,16 +96,31 @@ func (stubSwitchService) Switch(ctx context.Context, input auth.SwitchStoreInput
 
 type stubMediaService struct{}
 
+// ConfirmUpload implements [media.Service].
+func (s stubMediaService) ConfirmUpload(ctx context.Context, userID, storeID, mediaID uuid.UUID) (*media.ListItem, error) {
+	panic("unimplemented")
+}
+
 // DeleteMedia implements [media.Service].
 func (s stubMediaService) DeleteMedia(ctx context.Context, params media.DeleteMediaParams) error {
 	panic("unimplemented")
 }
 
+// UndeleteMedia implements [media.Service].
+func (s stubMediaService) UndeleteMedia(ctx context.Context, params media.DeleteMediaParams) error {
+	panic("unimplemented")
+}
+
 // GenerateReadURL implements [media.Service].
 func (s stubMediaService) GenerateReadURL(ctx context.Context, params media.ReadURLParams) (*media.ReadURLOutput, error) {
 	panic("unimplemented")
 }
 
+// ListAccessLogs implements [media.Service].
+func (s stubMediaService) ListAccessLogs(ctx context.Context, query media.AccessLogQuery) (*media.AccessLogList, error) {
+	panic("unimplemented")
+}
+
 // ListMedia implements [media.Service].
 func (s stubMediaService) ListMedia(ctx context.Context, params media.ListParams) (*media.MediaListResult, error) {
 	panic("unimplemented")
@@ -115,6 +130,10 @@ func (stubMediaService) PresignUpload(ctx context.Context, userID, storeID uuid.
 	return &media.PresignOutput{}, nil
 }
 
+func (stubMediaService) PresignUploadBatch(ctx context.Context, userID, storeID uuid.UUID, inputs []media.PresignInput) ([]media.PresignOutput, error) {
+	return make([]media.PresignOutput, len(inputs)), nil
+}
+
 type stubStoreService struct{}
 
 // GetByID implements [stores.Service].
@@ -147,6 +166,19 @@ func (s stubStoreService) RemoveUser(ctx context.Context, actorID uuid.UUID, sto
 	panic("unimplemented")
 }
 
+func (s stubStoreService) UpdateKYCStatus(ctx context.Context, adminID uuid.UUID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*stores.StoreDTO, error) {
+	panic("unimplemented")
+}
+
+// VerifyTaxExemption implements [stores.Service].
+func (s stubStoreService) VerifyTaxExemption(ctx context.Context, adminID uuid.UUID, storeID uuid.UUID, review stores.TaxExemptionReview) (*stores.StoreDTO, error) {
+	panic("unimplemented")
+}
+
+func (s stubStoreService) SearchVendors(ctx context.Context, input stores.SearchVendorsInput) (*stores.SearchVendorsResult, error) {
+	panic("unimplemented")
+}
+
 // Update implements [stores.Service].
 func (s stubStoreService) Update(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, input stores.UpdateStoreInput) (*stores.StoreDTO, error) {
 	panic("unimplemented")
@@ -175,6 +207,12 @@ func (s *stubAnalyticsService) QueryAd(ctx context.Context, req types.AdQueryReq
 	panic("unimplemented")
 }
 
+// QueryVendor implements [analytics.Service].
+func (s *stubAnalyticsService) QueryVendor(ctx context.Context, vendorStoreID string, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
+	req.StoreID = vendorStoreID
+	return s.Query(ctx, req)
+}
+
 func (s *stubAnalyticsService) Query(ctx context.Context, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
 	s.last = req
 	if s.response == nil {
@@ -255,7 +293,7 @@ func (s stubSubscriptionsService) AgentPickup(ctx context.Context, input ordersr
 }
 
 // CancelOrder implements [orders.Service].
-func (s stubSubscriptionsService) CancelOrder(ctx context.Context, input ordersrepo.BuyerCancelInput) error {
+func (s stubSubscriptionsService) CancelOrder(ctx context.Context, input ordersrepo.BuyerCancelInput) (*ordersrepo.BuyerCancelResult, error) {
 	panic("unimplemented")
 }
 
@@ -264,6 +302,31 @@ func (s stubSubscriptionsService) ConfirmPayout(ctx context.Context, input order
 	panic("unimplemented")
 }
 
+// ProposeSubstitution implements [orders.Service].
+func (s stubSubscriptionsService) ProposeSubstitution(ctx context.Context, input ordersrepo.ProposeSubstitutionInput) error {
+	panic("unimplemented")
+}
+
+// AcceptSubstitution implements [orders.Service].
+func (s stubSubscriptionsService) AcceptSubstitution(ctx context.Context, input ordersrepo.SubstitutionDecisionInput) error {
+	panic("unimplemented")
+}
+
+// RejectSubstitution implements [orders.Service].
+func (s stubSubscriptionsService) RejectSubstitution(ctx context.Context, input ordersrepo.SubstitutionDecisionInput) error {
+	panic("unimplemented")
+}
+
+// ApproveCancelRequest implements [orders.Service].
+func (s stubSubscriptionsService) ApproveCancelRequest(ctx context.Context, input ordersrepo.CancelRequestDecisionInput) error {
+	panic("unimplemented")
+}
+
+// DenyCancelRequest implements [orders.Service].
+func (s stubSubscriptionsService) DenyCancelRequest(ctx context.Context, input ordersrepo.CancelRequestDecisionInput) error {
+	panic("unimplemented")
+}
+
 // LineItemDecision implements [orders.Service].
 func (s stubSubscriptionsService) LineItemDecision(ctx context.Context, input ordersrepo.LineItemDecisionInput) error {
 	panic("unimplemented")
@@ -279,6 +342,10 @@ func (s stubSubscriptionsService) RetryOrder(ctx context.Context, input ordersre
 	panic("unimplemented")
 }
 
+func (s stubSubscriptionsService) SchedulePickup(ctx context.Context, input ordersrepo.SchedulePickupInput) error {
+	panic("unimplemented")
+}
+
 // VendorDecision implements [orders.Service].
 func (s stubSubscriptionsService) VendorDecision(ctx context.Context, input ordersrepo.VendorDecisionInput) error {
 	panic("unimplemented")
@@ -308,6 +375,10 @@ func (stubReviewsService) DeleteReview(ctx context.Context, reviewID, storeID, u
 	return nil
 }
 
+func (stubReviewsService) VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (reviews.RatingSummary, error) {
+	return reviews.RatingSummary{}, nil
+}
+
 type stubProductService struct{}
 
 // GetProductDetail implements [product.Service].
@@ -320,6 +391,55 @@ func (s stubProductService) ListProducts(ctx context.Context, input product.List
 	panic("unimplemented")
 }
 
+// BulkImport implements [product.Service].
+func (s stubProductService) BulkImport(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, reader io.Reader) (*product.BulkImportResult, error) {
+	panic("unimplemented")
+}
+
+// BulkUpdateInventory implements [product.Service].
+func (s stubProductService) BulkUpdateInventory(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, items []product.BulkInventoryItem) (*product.BulkInventoryResult, error) {
+	panic("unimplemented")
+}
+
+// ListInventoryAdjustments implements [product.Service].
+func (s stubProductService) ListInventoryAdjustments(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, params pagination.Params) (*product.InventoryAdjustmentList, error) {
+	panic("unimplemented")
+}
+
+// ListBuyerPrices implements [product.Service].
+func (s stubProductService) ListBuyerPrices(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) ([]product.BuyerPriceDTO, error) {
+	panic("unimplemented")
+}
+
+// SetBuyerPrice implements [product.Service].
+func (s stubProductService) SetBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID, priceCents int) (*product.BuyerPriceDTO, error) {
+	panic("unimplemented")
+}
+
+// DeleteBuyerPrice implements [product.Service].
+func (s stubProductService) ReorderMedia(ctx context.Context, userID, storeID, productID uuid.UUID, order []product.MediaOrderItem) (*product.ProductDTO, error) {
+	return nil, nil
+}
+
+func (s stubProductService) DeleteBuyerPrice(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID, buyerStoreID uuid.UUID) error {
+	panic("unimplemented")
+}
+
+// DuplicateProduct implements [product.Service].
+func (s stubProductService) DuplicateProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*product.ProductDTO, error) {
+	panic("unimplemented")
+}
+
+// RestoreProduct implements [product.Service].
+func (s stubProductService) RestoreProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, productID uuid.UUID) (*product.ProductDTO, error) {
+	panic("unimplemented")
+}
+
+// RelatedProducts implements [product.Service].
+func (s stubProductService) RelatedProducts(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID, limit int) ([]product.ProductSummary, error) {
+	panic("unimplemented")
+}
+
 // CreateProduct implements [product.Service].
 func (s stubProductService) CreateProduct(ctx context.Context, userID uuid.UUID, storeID uuid.UUID, input product.CreateProductInput) (*product.ProductDTO, error) {
 	panic("unimplemented")
@@ -351,9 +471,10 @@ type stubOrdersRepo struct {
 	listBuyer     func(ctx context.Context, buyerStoreID uuid.UUID, input ordersrepo.ListOrdersInput, filters ordersrepo.BuyerOrderFilters) (*ordersrepo.BuyerOrderListResult, error)
 	listVendor    func(ctx context.Context, vendorStoreID uuid.UUID, input ordersrepo.ListOrdersInput, filters ordersrepo.VendorOrderFilters) (*ordersrepo.VendorOrderListResult, error)
 	payoutList    func(ctx context.Context, params pagination.Params) (*ordersrepo.PayoutOrderList, error)
-	queue         func(ctx context.Context, params pagination.Params) (*ordersrepo.AgentOrderQueueList, error)
+	queue         func(ctx context.Context, params pagination.Params, filters ordersrepo.AgentQueueFilters) (*ordersrepo.AgentOrderQueueList, error)
 	assignedQueue func(ctx context.Context, agentID uuid.UUID, params pagination.Params) (*ordersrepo.AgentOrderQueueList, error)
 	detail        func(ctx context.Context, orderID uuid.UUID) (*ordersrepo.OrderDetail, error)
+	export        func(ctx context.Context, from, to time.Time, fn func(ordersrepo.AccountingExportLine) error) error
 }
 
 // ListOrdersBetweenStores implements [orders.Repository].
@@ -402,6 +523,10 @@ func (s *stubOrdersRepo) UpdatePaymentIntent(ctx context.Context, orderID uuid.U
 func (s *stubOrdersRepo) UpdateOrderAssignment(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error {
 	panic("unimplemented")
 }
+
+func (s *stubOrdersRepo) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	return true, nil
+}
 func (s *stubOrdersRepo) WithTx(tx *gorm.DB) ordersrepo.Repository { return s }
 func (s *stubOrdersRepo) CreateVendorOrder(ctx context.Context, order *models.VendorOrder) (*models.VendorOrder, error) {
 	panic("unimplemented")
@@ -443,9 +568,16 @@ func (s *stubOrdersRepo) ListPayoutOrders(ctx context.Context, params pagination
 	return &ordersrepo.PayoutOrderList{}, nil
 }
 
-func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*ordersrepo.AgentOrderQueueList, error) {
+func (s *stubOrdersRepo) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(ordersrepo.AccountingExportLine) error) error {
+	if s.export != nil {
+		return s.export(ctx, from, to, fn)
+	}
+	panic("unimplemented")
+}
+
+func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters ordersrepo.AgentQueueFilters) (*ordersrepo.AgentOrderQueueList, error) {
 	if s.queue != nil {
-		return s.queue(ctx, params)
+		return s.queue(ctx, params, filters)
 	}
 	return &ordersrepo.AgentOrderQueueList{}, nil
 }
@@ -472,8 +604,29 @@ func (s *stubOrdersRepo) UpdateVendorOrderStatus(ctx context.Context, orderID uu
 	return nil
 }
 
+func (s *stubOrdersRepo) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	return false, nil
+}
+
+func (s *stubOrdersRepo) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	panic("unimplemented")
+}
+
+func (s *stubOrdersRepo) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	panic("unimplemented")
+}
+
+func (s *stubOrdersRepo) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
+	panic("unimplemented")
+}
+
+func (s *stubOrdersRepo) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	panic("unimplemented")
+}
+
 type stubOrdersService struct {
 	decision     func(ctx context.Context, input ordersrepo.VendorDecisionInput) error
+	agentClaim   func(ctx context.Context, input ordersrepo.AgentClaimInput) (*ordersrepo.OrderAssignmentSummary, error)
 	agentPickup  func(ctx context.Context, input ordersrepo.AgentPickupInput) error
 	agentDeliver func(ctx context.Context, input ordersrepo.AgentDeliverInput) error
 }
@@ -554,7 +707,7 @@ func (s stubOrdersService) ListPayoutOrders(ctx context.Context, params paginati
 }
 
 // ListUnassignedHoldOrders implements [orders.Repository].
-func (s stubOrdersService) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*ordersrepo.AgentOrderQueueList, error) {
+func (s stubOrdersService) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters ordersrepo.AgentQueueFilters) (*ordersrepo.AgentOrderQueueList, error) {
 	panic("unimplemented")
 }
 
@@ -563,6 +716,11 @@ func (s stubOrdersService) ListVendorOrders(ctx context.Context, vendorStoreID u
 	panic("unimplemented")
 }
 
+// CreateOrderAssignmentIfUnassigned implements [orders.Repository].
+func (s stubOrdersService) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	panic("unimplemented")
+}
+
 // UpdateOrderAssignment implements [orders.Repository].
 func (s stubOrdersService) UpdateOrderAssignment(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error {
 	panic("unimplemented")
@@ -588,12 +746,16 @@ func (s stubOrdersService) UpdateVendorOrderStatus(ctx context.Context, orderID
 	panic("unimplemented")
 }
 
+func (s stubOrdersService) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	panic("unimplemented")
+}
+
 // WithTx implements [orders.Repository].
 func (s stubOrdersService) WithTx(tx *gorm.DB) ordersrepo.Repository {
 	panic("unimplemented")
 }
 
-func (s stubOrdersService) CancelOrder(ctx context.Context, input ordersrepo.BuyerCancelInput) error {
+func (s stubOrdersService) CancelOrder(ctx context.Context, input ordersrepo.BuyerCancelInput) (*ordersrepo.BuyerCancelResult, error) {
 	panic("unimplemented")
 }
 func (s stubOrdersService) NudgeVendor(ctx context.Context, input ordersrepo.BuyerNudgeInput) error {
@@ -602,6 +764,10 @@ func (s stubOrdersService) NudgeVendor(ctx context.Context, input ordersrepo.Buy
 func (s stubOrdersService) RetryOrder(ctx context.Context, input ordersrepo.BuyerRetryInput) (*ordersrepo.BuyerRetryResult, error) {
 	panic("unimplemented")
 }
+
+func (s stubOrdersService) SchedulePickup(ctx context.Context, input ordersrepo.SchedulePickupInput) error {
+	panic("unimplemented")
+}
 func (s stubOrdersService) LineItemDecision(ctx context.Context, input ordersrepo.LineItemDecisionInput) error {
 	panic("unimplemented")
 }
@@ -612,6 +778,12 @@ func (s stubOrdersService) VendorDecision(ctx context.Context, input ordersrepo.
 	}
 	return nil
 }
+func (s stubOrdersService) ClaimOrder(ctx context.Context, input ordersrepo.AgentClaimInput) (*ordersrepo.OrderAssignmentSummary, error) {
+	if s.agentClaim != nil {
+		return s.agentClaim(ctx, input)
+	}
+	return &ordersrepo.OrderAssignmentSummary{AgentUserID: input.AgentUserID}, nil
+}
 func (s stubOrdersService) AgentPickup(ctx context.Context, input ordersrepo.AgentPickupInput) error {
 	if s.agentPickup != nil {
 		return s.agentPickup(ctx, input)
@@ -631,6 +803,34 @@ func (s stubOrdersService) ConfirmPayout(ctx context.Context, input ordersrepo.C
 	return nil
 }
 
+func (s stubOrdersService) ProposeSubstitution(ctx context.Context, input ordersrepo.ProposeSubstitutionInput) error {
+	return nil
+}
+
+func (s stubOrdersService) AcceptSubstitution(ctx context.Context, input ordersrepo.SubstitutionDecisionInput) error {
+	return nil
+}
+
+func (s stubOrdersService) RejectSubstitution(ctx context.Context, input ordersrepo.SubstitutionDecisionInput) error {
+	return nil
+}
+
+func (s stubOrdersService) ApproveCancelRequest(ctx context.Context, input ordersrepo.CancelRequestDecisionInput) error {
+	return nil
+}
+
+func (s stubOrdersService) DenyCancelRequest(ctx context.Context, input ordersrepo.CancelRequestDecisionInput) error {
+	return nil
+}
+
+func (s stubOrdersService) AddComment(ctx context.Context, input ordersrepo.AddCommentInput) (*ordersrepo.OrderComment, error) {
+	return &ordersrepo.OrderComment{}, nil
+}
+
+func (s stubOrdersService) ListComments(ctx context.Context, input ordersrepo.ListCommentsInput) ([]ordersrepo.OrderComment, error) {
+	return nil, nil
+}
+
 type stubCheckoutService struct{}
 
 func (s stubCheckoutService) Execute(ctx context.Context, buyerStoreID uuid.UUID, cartID uuid.UUID, input checkout.CheckoutInput) (*models.CheckoutGroup, error) {
@@ -722,12 +922,18 @@ func newTestRouter(cfg *config.Config) http.Handler {
 		stubSubscriptionsService{},
 		nil, // paymentmethods.Service
 		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
 		nil, // billingcontrollers.PaymentMethodsService
 		nil, // billingcontrollers.BillingPlanService
 		nil, // *square.Client
 		nil, // *squarewebhook.Service
 		nil, // *squarewebhook.IdempotencyGuard
 		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
 	)
 }
 
@@ -843,6 +1049,54 @@ func TestAdminRoutesRequireAdminRole(t *testing.T) {
 	}
 }
 
+func TestMetricsNotServedPublicly(t *testing.T) {
+	router := newTestRouter(testConfig())
+
+	public := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, public)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected /metrics to be unmounted without a scrape token, got %d", resp.Code)
+	}
+}
+
+func TestMetricsRequireScrapeToken(t *testing.T) {
+	cfg := testConfig()
+	cfg.HTTP.MetricsScrapeToken = "scrape-secret"
+	router := newTestRouter(cfg)
+
+	cases := map[string]struct {
+		header string
+		want   int
+	}{
+		"anonymous":   {want: http.StatusUnauthorized},
+		"admin jwt":   {header: "Bearer " + buildToken(t, cfg, enums.MemberRoleAdmin), want: http.StatusUnauthorized},
+		"wrong token": {header: "Bearer nope", want: http.StatusUnauthorized},
+		"scraper":     {header: "Bearer scrape-secret", want: http.StatusOK},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			if resp.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, resp.Code)
+			}
+		})
+	}
+
+	admin := httptest.NewRequest(http.MethodGet, "/api/admin/metrics", nil)
+	admin.Header.Set("Authorization", "Bearer "+buildToken(t, cfg, enums.MemberRoleAdmin))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, admin)
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected metrics to be gone from the admin group, got %d", resp.Code)
+	}
+}
+
 func TestAgentGroupRequiresAgentRole(t *testing.T) {
 	cfg := testConfig()
 	router := newTestRouter(cfg)
@@ -905,6 +1159,11 @@ func TestAgentRoutesRequireAgentRole(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/api/v1/agent/orders/queue",
 		},
+		{
+			name:   "claim",
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/v1/agent/orders/%s/claim", orderID.String()),
+		},
 		{
 			name:   "pickup",
 			method: http.MethodPost,
@@ -999,12 +1258,18 @@ func TestAgentAssignedOrdersRequiresAgentRole(t *testing.T) {
 		stubSubscriptionsService{},
 		nil, // paymentmethods.Service
 		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
 		nil, // billingcontrollers.PaymentMethodsService
 		nil, // billingcontrollers.BillingPlanService
 		nil, // *square.Client
 		nil, // *squarewebhook.Service
 		nil, // *squarewebhook.IdempotencyGuard
 		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/agent/orders", nil)
@@ -1031,6 +1296,80 @@ func TestAgentAssignedOrdersRequiresAgentRole(t *testing.T) {
 	}
 }
 
+func TestAccountingExportStreamsOutsideRequestTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.HTTP.ReadTimeout = time.Minute
+	repo := &stubOrdersRepo{
+		export: func(ctx context.Context, from, to time.Time, fn func(ordersrepo.AccountingExportLine) error) error {
+			for i := 0; i < 100; i++ {
+				if err := fn(ordersrepo.AccountingExportLine{OrderNumber: int64(i + 1)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	logg := logger.New(logger.Options{ServiceName: "test", Level: logger.ParseLevel("debug"), Output: io.Discard})
+	router := NewRouter(
+		cfg,
+		logg,
+		stubPinger{},         // db.Pinger
+		(*redis.Client)(nil), // *redis.Client
+		stubPinger{},         // gcs.Pinger
+		stubPinger{},         // bigquery.Pinger
+		stubSessionManager{},
+		&stubAnalyticsService{},
+		stubAdService{}, // auth.Ad
+		stubAuthService{},
+		stubRegisterService{},
+		stubAdminRegisterService{},
+		stubSwitchService{},
+		stubStoreService{},
+		stubSquareCustomerUpdater{},
+		stubMembershipsRepo{},
+		stubSquareCustomerService{},
+		stubMediaService{},
+		stubLicensesService{},
+		stubProductService{},
+		stubCheckoutService{},
+		stubCheckoutRepo{},
+		stubCartService{},
+		stubNotificationsService{},
+		(wishlist.Service)(nil),
+		stubReviewsService{},
+		repo,
+		stubOrdersService{},
+		stubSubscriptionsService{},
+		nil, // paymentmethods.Service
+		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
+		nil, // billingcontrollers.PaymentMethodsService
+		nil, // billingcontrollers.BillingPlanService
+		nil, // *square.Client
+		nil, // *squarewebhook.Service
+		nil, // *squarewebhook.IdempotencyGuard
+		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/v1/orders/accounting-export?from=2026-01-01&to=2026-02-01", nil)
+	req.Header.Set("Authorization", "Bearer "+buildToken(t, cfg, enums.MemberRoleAdmin))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 for accounting export got %d", resp.Code)
+	}
+	// The request timeout buffers responses, so a flush only reaches the recorder when the export
+	// is mounted outside it.
+	if !resp.Flushed {
+		t.Fatalf("expected accounting export to flush while streaming")
+	}
+}
+
 func TestAgentAssignedOrderDetailRequiresAgentRole(t *testing.T) {
 	cfg := testConfig()
 	expectedAgent := uuid.New()
@@ -1076,12 +1415,18 @@ func TestAgentAssignedOrderDetailRequiresAgentRole(t *testing.T) {
 		stubSubscriptionsService{},
 		nil, // paymentmethods.Service
 		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
 		nil, // billingcontrollers.PaymentMethodsService
 		nil, // billingcontrollers.BillingPlanService
 		nil, // *square.Client
 		nil, // *squarewebhook.Service
 		nil, // *squarewebhook.IdempotencyGuard
 		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/agent/orders/"+uuid.NewString(), nil)
@@ -1129,12 +1474,18 @@ func TestAgentPickupRequiresAgentRole(t *testing.T) {
 		stubSubscriptionsService{},
 		nil, // paymentmethods.Service
 		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
 		nil, // billingcontrollers.PaymentMethodsService
 		nil, // billingcontrollers.BillingPlanService
 		nil, // *square.Client
 		nil, // *squarewebhook.Service
 		nil, // *squarewebhook.IdempotencyGuard
 		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
 	)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/agent/orders/"+uuid.NewString()+"/pickup", nil)
@@ -1197,12 +1548,18 @@ func TestAgentDeliverRequiresAgentRole(t *testing.T) {
 		stubSubscriptionsService{},
 		nil, // paymentmethods.Service
 		nil, // billingcontrollers.ChargesService
+		nil, // billingcontrollers.InvoicesService
 		nil, // billingcontrollers.PaymentMethodsService
 		nil, // billingcontrollers.BillingPlanService
 		nil, // *square.Client
 		nil, // *squarewebhook.Service
 		nil, // *squarewebhook.IdempotencyGuard
 		nil, // address.Service
+		nil, // ledger.Service
+		nil, // storewebhooks.Service
+		nil, // apikeys.Service
+		nil, // featureflags.Service
+		nil, // controllers.CronJobRuns
 	)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/agent/orders/"+uuid.NewString()+"/deliver", nil)
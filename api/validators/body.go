@@ -2,6 +2,7 @@ package validators
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -122,8 +123,12 @@ func DecodeJSONBody(r *http.Request, dest any) error {
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(dest); err != nil {
+		if tooLarge, ok := BodyTooLarge(err); ok {
+			return tooLarge
+		}
 		return pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid request body").
-			WithDetails(map[string]any{"error": err.Error()})
+			WithDetails(map[string]any{"error": err.Error()}).
+			WithFieldErrors(decodeFieldErrors(err))
 	}
 	if err := safeValidateStruct(dest); err != nil {
 		return formatValidationErrors(err)
@@ -131,13 +136,36 @@ func DecodeJSONBody(r *http.Request, dest any) error {
 	return nil
 }
 
+// BodyTooLarge reports whether err came from an http.MaxBytesReader overflow and, if so,
+// returns the 413 error to send back.
+func BodyTooLarge(err error) (*pkgerrors.Error, bool) {
+	var maxErr *http.MaxBytesError
+	if !errors.As(err, &maxErr) {
+		return nil, false
+	}
+	return pkgerrors.Wrap(pkgerrors.CodeTooLarge, err, "request body too large").
+		WithDetails(map[string]any{"limit_bytes": maxErr.Limit}), true
+}
+
+// decodeFieldErrors attributes JSON decode failures to a field when the decoder reports one.
+func decodeFieldErrors(err error) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string]string{typeErr.Field: fmt.Sprintf("must be of type %s", typeErr.Type.String())}
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return map[string]string{strings.Trim(field, `"`): "is not allowed"}
+	}
+	return nil
+}
+
 func formatValidationErrors(err error) *pkgerrors.Error {
 	if errs, ok := err.(validator.ValidationErrors); ok {
 		details := map[string]string{}
 		for _, fieldErr := range errs {
 			details[fieldErr.Field()] = validationMessage(fieldErr)
 		}
-		return pkgerrors.New(pkgerrors.CodeValidation, "validation failed").WithDetails(details)
+		return pkgerrors.NewValidationError("validation failed", details).WithDetails(details)
 	}
 	return pkgerrors.Wrap(pkgerrors.CodeValidation, err, "validation failed")
 }
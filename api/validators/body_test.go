@@ -0,0 +1,65 @@
+package validators
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+type signupRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Name     string `json:"name" validate:"required"`
+}
+
+func TestDecodeJSONBodyReportsEveryFailingField(t *testing.T) {
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email":"nope","password":"short"}`))
+
+	var dest signupRequest
+	err := DecodeJSONBody(req, &dest)
+	typed := pkgerrors.As(err)
+	if typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	want := map[string]string{
+		"email":    "must be a valid email",
+		"password": "must be at least 8",
+		"name":     "is required",
+	}
+	fields := typed.FieldErrors()
+	if len(fields) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), fields)
+	}
+	for field, msg := range want {
+		if fields[field] != msg {
+			t.Fatalf("field %s: expected %q got %q", field, msg, fields[field])
+		}
+	}
+}
+
+func TestDecodeJSONBodyAttributesDecodeErrorsToFields(t *testing.T) {
+	cases := map[string]struct {
+		body  string
+		field string
+	}{
+		"wrong type":    {body: `{"email":42}`, field: "email"},
+		"unknown field": {body: `{"nickname":"x"}`, field: "nickname"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/signup", strings.NewReader(tc.body))
+			var dest signupRequest
+			typed := pkgerrors.As(DecodeJSONBody(req, &dest))
+			if typed == nil {
+				t.Fatalf("expected typed error")
+			}
+			if _, ok := typed.FieldErrors()[tc.field]; !ok {
+				t.Fatalf("expected field error for %s, got %v", tc.field, typed.FieldErrors())
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,6 +9,21 @@ import (
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 )
 
+// ParseQueryBool parses an optional boolean query parameter, falling back to defaultVal when absent.
+func ParseQueryBool(r *http.Request, key string, defaultVal bool) (bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return defaultVal, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, pkgerrors.New(pkgerrors.CodeValidation, "query parameter must be a boolean").
+			WithDetails(map[string]any{"field": key}).
+			WithFieldErrors(map[string]string{key: "must be a boolean"})
+	}
+	return value, nil
+}
+
 func ParseQueryInt(r *http.Request, key string, defaultVal, min, max int) (int, error) {
 	raw := strings.TrimSpace(r.URL.Query().Get(key))
 	if raw == "" {
@@ -15,10 +31,14 @@ func ParseQueryInt(r *http.Request, key string, defaultVal, min, max int) (int,
 	}
 	value, err := strconv.Atoi(raw)
 	if err != nil {
-		return 0, pkgerrors.New(pkgerrors.CodeValidation, "query parameter must be numeric").WithDetails(map[string]any{"field": key})
+		return 0, pkgerrors.New(pkgerrors.CodeValidation, "query parameter must be numeric").
+			WithDetails(map[string]any{"field": key}).
+			WithFieldErrors(map[string]string{key: "must be numeric"})
 	}
 	if value < min || value > max {
-		return 0, pkgerrors.New(pkgerrors.CodeValidation, "query parameter out of range").WithDetails(map[string]any{"field": key, "min": min, "max": max})
+		return 0, pkgerrors.New(pkgerrors.CodeValidation, "query parameter out of range").
+			WithDetails(map[string]any{"field": key, "min": min, "max": max}).
+			WithFieldErrors(map[string]string{key: fmt.Sprintf("must be between %d and %d", min, max)})
 	}
 	return value, nil
 }
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics/router"
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics/worker"
@@ -15,8 +16,10 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/bigquery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/idempotency"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 )
 
@@ -29,12 +32,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
-	cfg.Service.Kind = "analytics-worker"
+	cfg.Service.Kind = config.ServiceKindAnalyticsWorker
+	requireResource(ctx, logg, "config", cfg.Validate())
 
 	logg = logger.New(logger.Options{
-		ServiceName: "analytics-worker",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "analytics-worker",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	redisClient, err := redis.New(context.Background(), cfg.Redis, logg)
@@ -79,7 +84,10 @@ func main() {
 	routingHandler, err := router.NewRouter(analyticsWriter, logg, nil)
 	requireResource(ctx, logg, "analytics router", err)
 
-	service, err := worker.NewService(subscription, routingHandler, manager, logg)
+	guard, err := redelivery.NewGuard("analytics", logg, pubsubClient.RedeliveryOptions(metrics.NewConsumerMetrics(prometheus.DefaultRegisterer)))
+	requireResource(ctx, logg, "analytics redelivery guard", err)
+
+	service, err := worker.NewService(subscription, routingHandler, manager, logg, guard)
 	requireResource(ctx, logg, "analytics worker service", err)
 
 	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
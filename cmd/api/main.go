@@ -10,10 +10,13 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/internal/address"
 	"github.com/angelmondragon/packfinderz-backend/internal/ads"
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics"
+	"github.com/angelmondragon/packfinderz-backend/internal/apikeys"
 	"github.com/angelmondragon/packfinderz-backend/internal/auth"
 	"github.com/angelmondragon/packfinderz-backend/internal/billing"
 	"github.com/angelmondragon/packfinderz-backend/internal/cart"
 	checkoutsvc "github.com/angelmondragon/packfinderz-backend/internal/checkout"
+	"github.com/angelmondragon/packfinderz-backend/internal/cron"
+	"github.com/angelmondragon/packfinderz-backend/internal/featureflags"
 	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
 	"github.com/angelmondragon/packfinderz-backend/internal/licenses"
 	"github.com/angelmondragon/packfinderz-backend/internal/media"
@@ -25,6 +28,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/internal/reviews"
 	"github.com/angelmondragon/packfinderz-backend/internal/squarecustomers"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/internal/storewebhooks"
 	"github.com/angelmondragon/packfinderz-backend/internal/subscriptions"
 	"github.com/angelmondragon/packfinderz-backend/internal/users"
 	squarewebhook "github.com/angelmondragon/packfinderz-backend/internal/webhooks/square"
@@ -37,6 +41,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
 	"github.com/angelmondragon/packfinderz-backend/pkg/migrate"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 	"github.com/angelmondragon/packfinderz-backend/pkg/square"
@@ -53,10 +58,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
+	cfg.Service.Kind = config.ServiceKindAPI
+	requireResource(ctx, logg, "config", cfg.Validate())
+
 	logg = logger.New(logger.Options{
-		ServiceName: "api",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "api",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	squareClient, err := square.NewClient(context.Background(), cfg.Square, logg)
@@ -64,7 +73,6 @@ func main() {
 
 	mapsClient, err := maps.NewClient(cfg.GoogleMaps.APIKey)
 	requireResource(ctx, logg, "google maps client", err)
-	addressService := address.NewService(mapsClient)
 
 	squareCustomerService := squarecustomers.NewService(squareClient)
 
@@ -89,6 +97,8 @@ func main() {
 		}
 	}()
 
+	addressService := address.NewService(mapsClient, redisClient, cfg.GoogleMaps.GeocodeCacheTTL)
+
 	sessionManager, err := session.NewManager(redisClient, cfg.JWT)
 	requireResource(ctx, logg, "session manager", err)
 
@@ -99,6 +109,7 @@ func main() {
 			logg.Error(ctx, "failed to close gcs client", err)
 		}
 	}()
+	requireResource(ctx, logg, "gcs preflight", gcs.Preflight(ctx, gcsClient, cfg.GCS.BucketName))
 
 	bqClient, err := bigquery.NewClient(context.Background(), cfg.GCP, cfg.BigQuery, logg)
 	requireResource(ctx, logg, "bigquery", err)
@@ -167,6 +178,7 @@ func main() {
 		StoreRepo:         storeRepo,
 		SquareClient:      squareSubsClient,
 		TransactionRunner: dbClient,
+		CouponRepo:        billing.NewCouponRepository(dbClient.DB()),
 	})
 	requireResource(ctx, logg, "subscription service", err)
 
@@ -175,6 +187,8 @@ func main() {
 		StoreRepo:         storeRepo,
 		SquareClient:      squareSubsClient,
 		TransactionRunner: dbClient,
+		Notifications:     notifications.NewRepository(dbClient.DB()),
+		GracePeriod:       cfg.Square.SubscriptionGracePeriod,
 	})
 	requireResource(ctx, logg, "square webhook service", err)
 
@@ -183,6 +197,8 @@ func main() {
 
 	mediaRepo := media.NewRepository(dbClient.DB())
 	mediaAttachmentRepo := media.NewMediaAttachmentRepository(dbClient.DB())
+	mediaURLExpiry, err := media.NewURLExpiryOption(cfg.GCS)
+	requireResource(ctx, logg, "media url expiry", err)
 	mediaService, err := media.NewService(
 		mediaRepo,
 		membershipsRepo,
@@ -191,6 +207,9 @@ func main() {
 		cfg.GCS.BucketName,
 		cfg.GCS.UploadURLExpiry,
 		cfg.GCS.DownloadURLExpiry,
+		media.WithAccessLog(media.NewAccessLogRepository(dbClient.DB())),
+		media.WithDeletionGracePeriod(cfg.Media.DeletionGracePeriod),
+		mediaURLExpiry,
 	)
 	requireResource(ctx, logg, "media service", err)
 	attachmentReconciler, err := media.NewAttachmentReconciler(mediaAttachmentRepo, mediaRepo)
@@ -207,6 +226,8 @@ func main() {
 	})
 	requireResource(ctx, logg, "ads service", err)
 	licenseRepo := licenses.NewRepository(dbClient.DB())
+	outboxRepo := outbox.NewRepository(dbClient.DB())
+	outboxPublisher := outbox.NewService(outboxRepo, logg)
 	storeService, err := stores.NewService(stores.ServiceParams{
 		Repo:                 storeRepo,
 		Memberships:          membershipsRepo,
@@ -216,12 +237,13 @@ func main() {
 		AttachmentReconciler: attachmentReconciler,
 		MediaRepo:            mediaRepo,
 		LicenseRepo:          licenseRepo,
+		Publisher:            outboxPublisher,
 		Logg:                 logg,
 	})
 	requireResource(ctx, logg, "store service", err)
 
 	productRepo := products.NewRepository(dbClient.DB())
-	productService, err := products.NewService(productRepo, dbClient, storeRepo, membershipsRepo, mediaRepo, attachmentReconciler, mediaService)
+	productService, err := products.NewService(productRepo, dbClient, storeRepo, membershipsRepo, mediaRepo, attachmentReconciler, mediaService, redisClient, outboxPublisher)
 	requireResource(ctx, logg, "product service", err)
 
 	wishlistRepo := wishlist.NewRepository(dbClient.DB())
@@ -235,6 +257,14 @@ func main() {
 	cartRepo := cart.NewRepository(dbClient.DB())
 	adsTokenParser, err := token.NewParser(cfg.Ads.TokenSecret)
 	requireResource(ctx, logg, "ads token parser", err)
+	quoteTTL, err := cart.NewQuoteTTLPolicy(cfg.Cart)
+	requireResource(ctx, logg, "cart quote ttl", err)
+	rounding, err := money.ParseRoundingMode(cfg.Money.RoundingMode)
+	requireResource(ctx, logg, "money rounding mode", err)
+	taxCalculator, err := checkoutsvc.NewTaxCalculator(cfg.Tax, rounding)
+	requireResource(ctx, logg, "tax rate", err)
+	shippingRater, err := checkoutsvc.NewShippingRater(cfg.Shipping)
+	requireResource(ctx, logg, "shipping rater", err)
 	cartService, err := cart.NewService(
 		cartRepo,
 		dbClient,
@@ -242,18 +272,39 @@ func main() {
 		productRepo,
 		cart.NoopPromoLoader(),
 		adsTokenParser,
+		quoteTTL,
+		rounding,
+		cart.WithTaxCalculator(taxCalculator),
+		cart.WithShippingQuoter(checkoutsvc.NewShippingQuoter(shippingRater)),
 	)
 	requireResource(ctx, logg, "cart service", err)
 
-	outboxRepo := outbox.NewRepository(dbClient.DB())
-	outboxPublisher := outbox.NewService(outboxRepo, logg)
-
 	ledgerRepo := ledger.NewRepository(dbClient.DB())
-	ledgerService, err := ledger.NewService(ledgerRepo)
+	deliveryFees, err := ledger.NewDeliveryFeePolicy(cfg.Agent)
+	requireResource(ctx, logg, "agent delivery fee policy", err)
+	ledgerService, err := ledger.NewService(ledgerRepo, deliveryFees)
 	requireResource(ctx, logg, "ledger service", err)
 
+	storeWebhookService, err := storewebhooks.NewService(storewebhooks.NewRepository(dbClient.DB()))
+	requireResource(ctx, logg, "store webhook service", err)
+
+	apiKeyService, err := apikeys.NewService(apikeys.NewRepository(dbClient.DB()), membershipsRepo)
+	requireResource(ctx, logg, "api key service", err)
+
 	ordersRepo := orders.NewRepository(dbClient.DB())
-	ordersService, err := orders.NewService(ordersRepo, dbClient, outboxPublisher, orders.NewInventoryReleaser(), orders.NewInventoryReserver(), ledgerService)
+	retryableStatuses, err := orders.ParseRetryableStatuses(cfg.Orders.RetryableStatuses)
+	requireResource(ctx, logg, "order retryable statuses", err)
+	ordersService, err := orders.NewService(
+		ordersRepo,
+		dbClient,
+		outboxPublisher,
+		orders.NewInventoryReleaser(outboxPublisher),
+		orders.NewInventoryReserver(outboxPublisher),
+		ledgerService,
+		orders.WithCancelWindow(cfg.Orders.CancelWindow),
+		orders.WithNudgeCooldown(redisClient, cfg.Orders.NudgeCooldown),
+		orders.WithRetryableStatuses(retryableStatuses),
+	)
 	requireResource(ctx, logg, "orders service", err)
 
 	reviewsRepo := reviews.NewRepository(dbClient.DB())
@@ -262,6 +313,17 @@ func main() {
 	notificationsRepo := notifications.NewRepository(dbClient.DB())
 	notificationsService, err := notifications.NewService(notificationsRepo)
 	requireResource(ctx, logg, "notifications service", err)
+	featureFlagService, err := featureflags.NewService(
+		featureflags.NewRepository(dbClient.DB()),
+		cfg.App.Env,
+		featureflags.Defaults(cfg.FeatureFlags),
+		cfg.FeatureFlags.RefreshInterval,
+	)
+	requireResource(ctx, logg, "feature flag service", err)
+	addressValidation, err := checkoutsvc.NewAddressValidationOption(cfg.Shipping, addressService)
+	requireResource(ctx, logg, "checkout address validation", err)
+	afterHours, err := checkoutsvc.NewAfterHoursOption(cfg.Orders)
+	requireResource(ctx, logg, "checkout after-hours mode", err)
 	checkoutService, err := checkoutsvc.NewService(
 		dbClient,
 		cartRepo,
@@ -271,7 +333,12 @@ func main() {
 		nil,
 		outboxPublisher,
 		adsTokenParser,
-		cfg.FeatureFlags.AllowACH,
+		featureFlagService,
+		shippingRater,
+		checkoutsvc.NewCachedRouteEstimator(mapsClient, redisClient, cfg.GoogleMaps.RouteCacheTTL),
+		addressValidation,
+		afterHours,
+		checkoutsvc.WithTaxCalculator(taxCalculator),
 	)
 	requireResource(ctx, logg, "checkout service", err)
 	checkoutRepo := checkoutsvc.NewRepository(dbClient.DB(), ordersRepo)
@@ -288,6 +355,9 @@ func main() {
 	)
 	requireResource(ctx, logg, "license service", err)
 
+	cronRuns, err := cron.NewRunQueue(cron.NewRunRepository(dbClient.DB()))
+	requireResource(ctx, logg, "cron job runs", err)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = cfg.App.Port
@@ -340,10 +410,16 @@ func main() {
 			billingService,
 			billingService,
 			billingService,
+			billingService,
 			squareClient,
 			squareWebhookService,
 			squareWebhookGuard,
 			addressService,
+			ledgerService,
+			storeWebhookService,
+			apiKeyService,
+			featureFlagService,
+			cronRuns,
 		),
 	}
 
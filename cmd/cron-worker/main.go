@@ -6,31 +6,23 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
 
-	"github.com/angelmondragon/packfinderz-backend/internal/billing"
 	"github.com/angelmondragon/packfinderz-backend/internal/cron"
-	"github.com/angelmondragon/packfinderz-backend/internal/licenses"
-	"github.com/angelmondragon/packfinderz-backend/internal/media"
-	"github.com/angelmondragon/packfinderz-backend/internal/notifications"
-	"github.com/angelmondragon/packfinderz-backend/internal/orders"
-	"github.com/angelmondragon/packfinderz-backend/internal/stores"
-	"github.com/angelmondragon/packfinderz-backend/internal/subscriptions"
+	"github.com/angelmondragon/packfinderz-backend/pkg/bigquery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 	"github.com/angelmondragon/packfinderz-backend/pkg/migrate"
-	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 	"github.com/angelmondragon/packfinderz-backend/pkg/square"
 	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
 )
 
-const lockKeyFormat = "pf:cron-worker:lock:%s"
-
 func main() {
 	ctx := context.Background()
 	logg := logger.New(logger.Options{ServiceName: "cron-worker"})
@@ -40,12 +32,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
-	cfg.Service.Kind = "cron-worker"
+	cfg.Service.Kind = config.ServiceKindCronWorker
+	requireResource(ctx, logg, "config", cfg.Validate())
 
 	logg = logger.New(logger.Options{
-		ServiceName: "cron-worker",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "cron-worker",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	squareClient, err := square.NewClient(context.Background(), cfg.Square, logg)
@@ -69,12 +63,6 @@ func main() {
 		}
 	}()
 
-	licenseRepo := licenses.NewRepository(dbClient.DB())
-	storeRepo := stores.NewRepository(dbClient.DB())
-	mediaRepo := media.NewRepository(dbClient.DB())
-	attachmentRepo := media.NewMediaAttachmentRepository(dbClient.DB())
-	outboxRepo := outbox.NewRepository(dbClient.DB())
-	outboxSvc := outbox.NewService(outboxRepo, logg)
 	gcsClient, err := gcs.NewClient(context.Background(), cfg.GCS, cfg.GCP, logg)
 	requireResource(ctx, logg, "gcs", err)
 	defer func() {
@@ -83,78 +71,39 @@ func main() {
 		}
 	}()
 
+	registryParams := cron.DefaultRegistryParams{
+		Logger:              logg,
+		DB:                  dbClient,
+		Config:              cfg,
+		GCS:                 gcsClient,
+		Square:              squareClient,
+		MediaCleanupMetrics: metrics.NewMediaCleanupMetrics(prometheus.DefaultRegisterer),
+	}
+	if strings.TrimSpace(cfg.BigQuery.OutboxExportTable) != "" {
+		bqClient, err := bigquery.NewClient(context.Background(), cfg.GCP, cfg.BigQuery, logg)
+		requireResource(ctx, logg, "bigquery", err)
+		defer func() {
+			if err := bqClient.Close(); err != nil {
+				logg.Error(ctx, "failed to close bigquery client", err)
+			}
+		}()
+		registryParams.BigQuery = bqClient
+	}
+
 	metricsCollector := metrics.NewCronJobMetrics(prometheus.DefaultRegisterer)
-	lock, err := cron.NewRedisLock(redisClient, lockKey(cfg.App.Env), 0)
+	lock, err := cron.NewRedisLock(redisClient, cron.LockKey(cfg.App.Env), 0)
 	requireResource(ctx, logg, "cron lock", err)
 
-	registry := cron.NewRegistry()
-	licenseJob, err := cron.NewLicenseLifecycleJob(cron.LicenseLifecycleJobParams{
-		Logger:         logg,
-		DB:             dbClient,
-		LicenseRepo:    licenseRepo,
-		StoreRepo:      storeRepo,
-		MediaRepo:      mediaRepo,
-		AttachmentRepo: attachmentRepo,
-		Outbox:         outboxSvc,
-		OutboxRepo:     outboxRepo,
-		GCS:            gcsClient,
-		GCSBucket:      cfg.GCS.BucketName,
-	})
-	requireResource(ctx, logg, "license job", err)
-	registry.Register(licenseJob)
-
-	ordersRepo := orders.NewRepository(dbClient.DB())
-	orderTTLJob, err := cron.NewOrderTTLJob(cron.OrderTTLJobParams{
-		Logger:        logg,
-		DB:            dbClient,
-		PendingReader: ordersRepo,
-		Inventory:     orders.NewInventoryReleaser(),
-		Outbox:        outboxSvc,
-		OutboxRepo:    outboxRepo,
-	})
-	requireResource(ctx, logg, "order ttl job", err)
-	registry.Register(orderTTLJob)
-	notificationRepo := notifications.NewRepository(dbClient.DB())
-	notificationCleanupJob, err := cron.NewNotificationCleanupJob(cron.NotificationCleanupJobParams{
-		Logger:     logg,
-		DB:         dbClient,
-		Repository: notificationRepo,
-	})
-	requireResource(ctx, logg, "notification cleanup job", err)
-	registry.Register(notificationCleanupJob)
-
-	pendingMediaCleanupJob, err := cron.NewPendingMediaCleanupJob(cron.PendingMediaCleanupJobParams{
-		Logger:         logg,
-		DB:             dbClient,
-		MediaRepo:      mediaRepo,
-		AttachmentRepo: attachmentRepo,
-	})
-	requireResource(ctx, logg, "pending media cleanup job", err)
-	registry.Register(pendingMediaCleanupJob)
+	registry, err := cron.NewDefaultRegistry(registryParams)
+	requireResource(ctx, logg, "cron jobs", err)
 
-	outboxRetentionJob, err := cron.NewOutboxRetentionJob(cron.OutboxRetentionJobParams{
-		Logger:     logg,
-		DB:         dbClient,
-		Repository: outboxRepo,
-	})
-	requireResource(ctx, logg, "outbox retention job", err)
-	registry.Register(outboxRetentionJob)
-
-	billingRepo := billing.NewRepository(dbClient.DB())
-	subscriptionJob, err := cron.NewSubscriptionReconcileJob(cron.SubscriptionReconcileJobParams{
-		Logger:       logg,
-		DB:           dbClient,
-		BillingRepo:  billingRepo,
-		StoreRepo:    storeRepo,
-		SquareClient: subscriptions.NewSquareClient(squareClient, cfg.Square.LocationID),
-	})
-	requireResource(ctx, logg, "subscription reconcile job", err)
-	registry.Register(subscriptionJob)
 	service, err := cron.NewService(cron.ServiceParams{
 		Logger:   logg,
 		Registry: registry,
 		Lock:     lock,
 		Metrics:  metricsCollector,
+		DryRun:   cfg.Cron.DryRun,
+		Runs:     cron.NewRunRepository(dbClient.DB()),
 	})
 	requireResource(ctx, logg, "cron service", err)
 
@@ -172,13 +121,6 @@ func main() {
 	}
 }
 
-func lockKey(env string) string {
-	if env == "" {
-		env = "local"
-	}
-	return fmt.Sprintf(lockKeyFormat, env)
-}
-
 func requireResource(ctx context.Context, logg *logger.Logger, resource string, err error) {
 	if err == nil {
 		return
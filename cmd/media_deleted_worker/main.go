@@ -8,13 +8,16 @@ import (
 	"os/signal"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/media"
 	"github.com/angelmondragon/packfinderz-backend/internal/media/consumer"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 )
 
 func main() {
@@ -26,12 +29,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
-	cfg.Service.Kind = "media-deletion-worker"
+	cfg.Service.Kind = config.ServiceKindMediaDeletionWorker
+	requireResource(ctx, logg, "config", cfg.Validate())
 
 	logg = logger.New(logger.Options{
-		ServiceName: "media-deletion-worker",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "media-deletion-worker",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	dbClient, err := db.New(context.Background(), cfg.DB, logg)
@@ -51,14 +56,15 @@ func main() {
 	}()
 
 	mediaRepo := media.NewRepository(dbClient.DB())
-	attachmentRepo := media.NewMediaAttachmentRepository(dbClient.DB())
-	detacher := consumer.NewNoopDetacher(logg)
+	redeliveryOpts := pubsubClient.RedeliveryOptions(metrics.NewConsumerMetrics(prometheus.DefaultRegisterer))
+	deletionGuard, err := redelivery.NewGuard("media-deletion", logg, redeliveryOpts)
+	requireResource(ctx, logg, "media deletion redelivery guard", err)
 	deletionConsumer, err := consumer.NewDeletionConsumer(
 		mediaRepo,
-		attachmentRepo,
-		detacher,
 		pubsubClient.MediaDeletionSubscription(),
 		logg,
+		deletionGuard,
+		cfg.Media.DeletionGracePeriod,
 	)
 	requireResource(ctx, logg, "media deletion consumer", err)
 
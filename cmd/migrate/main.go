@@ -21,22 +21,27 @@ func main() {
 	_ = godotenv.Load()
 
 	// Flags
-	cmd := flag.String("cmd", "up", "migration command: up|down|status|version|create|validate")
+	cmd := flag.String("cmd", "up", "migration command: up|down|redo|status|version|create|validate")
 	dir := flag.String("dir", migrate.DefaultDir, "goose migrations directory")
 
 	// Command-specific flags
 	name := flag.String("name", "", "migration name (for create)")
 	version := flag.String("version", "", "target version (YYYYMMDDHHMMSS) for -cmd=version")
+	format := flag.String("format", "text", "output format for -cmd=status: text|json")
 
 	flag.Parse()
 
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
+	cfg.Service.Kind = config.ServiceKindMigrate
+	requireResource(ctx, logg, "config", cfg.Validate())
+
 	logg = logger.New(logger.Options{
-		ServiceName: "migrate",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "migrate",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	ctx = logg.WithFields(context.Background(), map[string]any{
@@ -98,9 +103,31 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "redo":
+		if err := migrate.Redo(ctx, sqlDB, *dir, cfg.App); err != nil {
+			fmt.Fprintf(os.Stderr, "goose redo failed: %v\n", err)
+			os.Exit(1)
+		}
+
 	case "status":
-		if err := migrate.Run(ctx, sqlDB, *dir, "status"); err != nil {
-			fmt.Fprintf(os.Stderr, "goose status failed: %v\n", err)
+		switch *format {
+		case "text":
+			if err := migrate.Run(ctx, sqlDB, *dir, "status"); err != nil {
+				fmt.Fprintf(os.Stderr, "goose status failed: %v\n", err)
+				os.Exit(1)
+			}
+		case "json":
+			report, err := migrate.Status(ctx, sqlDB, *dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "goose status failed: %v\n", err)
+				os.Exit(1)
+			}
+			if err := migrate.WriteStatusJSON(os.Stdout, report); err != nil {
+				fmt.Fprintf(os.Stderr, "write status failed: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintln(os.Stderr, "unknown -format value:", *format)
 			os.Exit(1)
 		}
 
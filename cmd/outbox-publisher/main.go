@@ -27,12 +27,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
-	cfg.Service.Kind = "outbox-publisher"
+	cfg.Service.Kind = config.ServiceKindOutboxPublisher
+	requireResource(ctx, logg, "config", cfg.Validate())
 
 	logg = logger.New(logger.Options{
-		ServiceName: "outbox-publisher",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "outbox-publisher",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	dbClient, err := db.New(context.Background(), cfg.DB, logg)
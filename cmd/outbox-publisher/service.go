@@ -374,6 +374,9 @@ func (s *Service) eventFields(event models.OutboxEvent, envelope outbox.PayloadE
 		fields["event_id"] = envelope.EventID
 		fields["occurred_at"] = envelope.OccurredAt.Format(time.RFC3339Nano)
 	}
+	if envelope.RequestID != "" {
+		fields["request_id"] = envelope.RequestID
+	}
 	if topic != "" {
 		fields["topic"] = topic
 	}
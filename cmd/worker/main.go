@@ -8,6 +8,7 @@ import (
 	"os/signal"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/licenses"
 	"github.com/angelmondragon/packfinderz-backend/internal/media"
@@ -15,14 +16,17 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/internal/notifications"
 	schedulers "github.com/angelmondragon/packfinderz-backend/internal/schedulers/licenses"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/internal/storewebhooks"
 	"github.com/angelmondragon/packfinderz-backend/pkg/bigquery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 	"github.com/angelmondragon/packfinderz-backend/pkg/migrate"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/idempotency"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 	"github.com/angelmondragon/packfinderz-backend/pkg/square"
 	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
@@ -37,12 +41,14 @@ func main() {
 	cfg, err := config.Load()
 	requireResource(ctx, logg, "config", err)
 
-	cfg.Service.Kind = "worker"
+	cfg.Service.Kind = config.ServiceKindWorker
+	requireResource(ctx, logg, "config", cfg.Validate())
 
 	logg = logger.New(logger.Options{
-		ServiceName: "worker",
-		Level:       logger.ParseLevel(cfg.App.LogLevel),
-		WarnStack:   cfg.App.LogWarnStack,
+		ServiceName:      "worker",
+		Level:            logger.ParseLevel(cfg.App.LogLevel),
+		WarnStack:        cfg.App.LogWarnStack,
+		DebugSampleEvery: cfg.App.LogDebugSampleEvery,
 	})
 
 	squareClient, err := square.NewClient(context.Background(), cfg.Square, logg)
@@ -89,17 +95,40 @@ func main() {
 		}
 	}()
 
+	redeliveryOpts := pubsubClient.RedeliveryOptions(metrics.NewConsumerMetrics(prometheus.DefaultRegisterer))
+	mediaGuard, err := redelivery.NewGuard("media", logg, redeliveryOpts)
+	requireResource(ctx, logg, "media redelivery guard", err)
+	notificationGuard, err := redelivery.NewGuard("notifications", logg, redeliveryOpts)
+	requireResource(ctx, logg, "notifications redelivery guard", err)
+	storeWebhookGuard, err := redelivery.NewGuard("store-webhooks", logg, redeliveryOpts)
+	requireResource(ctx, logg, "store webhook redelivery guard", err)
+
 	mediaRepo := media.NewRepository(dbClient.DB())
-	mediaConsumer, err := consumer.NewConsumer(mediaRepo, pubsubClient.MediaSubscription(), logg)
+	mediaConsumer, err := consumer.NewConsumer(mediaRepo, pubsubClient.MediaSubscription(), logg, mediaGuard)
 	requireResource(ctx, logg, "media consumer", err)
 
 	idempotencyManager, err := idempotency.NewManager(redisClient, cfg.Eventing.OutboxIdempotencyTTL)
 	requireResource(ctx, logg, "idempotency manager", err)
 
 	notificationRepo := notifications.NewRepository(dbClient.DB())
-	notificationConsumer, err := notifications.NewConsumer(notificationRepo, pubsubClient.NotificationSubscription(), idempotencyManager, logg)
+	notificationConsumer, err := notifications.NewConsumer(notificationRepo, pubsubClient.NotificationSubscription(), idempotencyManager, logg, notificationGuard)
 	requireResource(ctx, logg, "notifications consumer", err)
 
+	storeWebhookRepo := storewebhooks.NewRepository(dbClient.DB())
+	storeWebhookDispatcher, err := storewebhooks.NewDispatcher(storeWebhookRepo, nil, logg)
+	requireResource(ctx, logg, "store webhook dispatcher", err)
+	storeWebhookRetryWorker, err := storewebhooks.NewRetryWorker(storeWebhookRepo, storeWebhookDispatcher, logg, storewebhooks.RetryWorkerOptions{})
+	requireResource(ctx, logg, "store webhook retry worker", err)
+
+	var storeWebhookConsumer *storewebhooks.Consumer
+	storeWebhookSubscription := pubsubClient.StoreWebhookSubscription()
+	if storeWebhookSubscription != nil {
+		storeWebhookQueue, err := storewebhooks.NewQueue(storeWebhookRepo)
+		requireResource(ctx, logg, "store webhook queue", err)
+		storeWebhookConsumer, err = storewebhooks.NewConsumer(storeWebhookRepo, storeWebhookQueue, idempotencyManager, logg)
+		requireResource(ctx, logg, "store webhook consumer", err)
+	}
+
 	licenseRepo := licenses.NewRepository(dbClient.DB())
 	storeRepo := stores.NewRepository(dbClient.DB())
 	outboxRepo := outbox.NewRepository(dbClient.DB())
@@ -114,17 +143,21 @@ func main() {
 	requireResource(ctx, logg, "license scheduler", err)
 
 	service, err := NewService(ServiceParams{
-		Config:               cfg,
-		Logger:               logg,
-		DB:                   dbClient,
-		Redis:                redisClient,
-		PubSub:               pubsubClient,
-		MediaConsumer:        mediaConsumer,
-		NotificationConsumer: notificationConsumer,
-		LicenseScheduler:     licenseScheduler,
-		GCS:                  gcsClient,
-		BigQuery:             bqClient,
-		Square:               squareClient,
+		Config:                   cfg,
+		Logger:                   logg,
+		DB:                       dbClient,
+		Redis:                    redisClient,
+		PubSub:                   pubsubClient,
+		MediaConsumer:            mediaConsumer,
+		NotificationConsumer:     notificationConsumer,
+		StoreWebhookConsumer:     storeWebhookConsumer,
+		StoreWebhookSubscription: storeWebhookSubscription,
+		StoreWebhookGuard:        storeWebhookGuard,
+		StoreWebhookRetryWorker:  storeWebhookRetryWorker,
+		LicenseScheduler:         licenseScheduler,
+		GCS:                      gcsClient,
+		BigQuery:                 bqClient,
+		Square:                   squareClient,
 	})
 	requireResource(ctx, logg, "worker service", err)
 
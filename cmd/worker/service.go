@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	gcppubsub "cloud.google.com/go/pubsub/v2"
 	"github.com/angelmondragon/packfinderz-backend/internal/media/consumer"
 	"github.com/angelmondragon/packfinderz-backend/internal/notifications"
 	schedulers "github.com/angelmondragon/packfinderz-backend/internal/schedulers/licenses"
+	"github.com/angelmondragon/packfinderz-backend/internal/storewebhooks"
 	"github.com/angelmondragon/packfinderz-backend/pkg/bigquery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/angelmondragon/packfinderz-backend/pkg/redis"
 	"github.com/angelmondragon/packfinderz-backend/pkg/square"
 	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
@@ -28,9 +34,15 @@ type ServiceParams struct {
 	MediaConsumer        *consumer.Consumer
 	LicenseScheduler     *schedulers.Service
 	NotificationConsumer *notifications.Consumer
-	GCS                  *gcs.Client
-	BigQuery             *bigquery.Client
-	Square               *square.Client
+	// StoreWebhookConsumer and StoreWebhookSubscription are optional; queueing runs only when both are set.
+	// StoreWebhookRetryWorker sends queued deliveries and runs whenever set.
+	StoreWebhookConsumer     *storewebhooks.Consumer
+	StoreWebhookSubscription *gcppubsub.Subscriber
+	StoreWebhookGuard        *redelivery.Guard
+	StoreWebhookRetryWorker  *storewebhooks.RetryWorker
+	GCS                      *gcs.Client
+	BigQuery                 *bigquery.Client
+	Square                   *square.Client
 }
 
 type Service struct {
@@ -41,6 +53,10 @@ type Service struct {
 	pubsub               *pubsub.Client
 	consumer             *consumer.Consumer
 	notificationConsumer *notifications.Consumer
+	storeWebhooks        *storewebhooks.Consumer
+	storeWebhookSub      *gcppubsub.Subscriber
+	storeWebhookGuard    *redelivery.Guard
+	storeWebhookRetry    *storewebhooks.RetryWorker
 	gcs                  *gcs.Client
 	bigquery             *bigquery.Client
 	square               *square.Client
@@ -86,6 +102,10 @@ func NewService(params ServiceParams) (*Service, error) {
 		pubsub:               params.PubSub,
 		consumer:             params.MediaConsumer,
 		notificationConsumer: params.NotificationConsumer,
+		storeWebhooks:        params.StoreWebhookConsumer,
+		storeWebhookSub:      params.StoreWebhookSubscription,
+		storeWebhookGuard:    params.StoreWebhookGuard,
+		storeWebhookRetry:    params.StoreWebhookRetryWorker,
 		gcs:                  params.GCS,
 		bigquery:             params.BigQuery,
 		square:               params.Square,
@@ -142,13 +162,23 @@ func (s *Service) Run(ctx context.Context) error {
 
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
-	errCh := make(chan error, 2)
+	errCh := make(chan error, 4)
 	go func() {
 		errCh <- s.consumer.Run(ctx)
 	}()
 	go func() {
 		errCh <- s.notificationConsumer.Run(ctx)
 	}()
+	if s.storeWebhooks != nil && s.storeWebhookSub != nil {
+		go func() {
+			errCh <- s.runStoreWebhooks(ctx)
+		}()
+	}
+	if s.storeWebhookRetry != nil {
+		go func() {
+			errCh <- s.storeWebhookRetry.Run(ctx)
+		}()
+	}
 
 	for {
 		select {
@@ -166,3 +196,27 @@ func (s *Service) Run(ctx context.Context) error {
 		}
 	}
 }
+
+// runStoreWebhooks feeds order events from the store webhook subscription into the consumer.
+func (s *Service) runStoreWebhooks(ctx context.Context) error {
+	err := s.storeWebhookSub.Receive(ctx, func(ctx context.Context, msg *gcppubsub.Message) {
+		var envelope outbox.PayloadEnvelope
+		if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+			s.logg.Error(s.logg.WithField(ctx, "message_id", msg.ID), "failed to decode store webhook envelope", err)
+			msg.Ack()
+			return
+		}
+		eventType := enums.OutboxEventType(msg.Attributes["event_type"])
+		delivery := redelivery.Message{ID: msg.ID, Data: msg.Data, Attributes: msg.Attributes, DeliveryAttempt: msg.DeliveryAttempt}
+		decision := s.storeWebhookGuard.Handle(ctx, delivery, func(ctx context.Context) error {
+			return s.storeWebhooks.Process(ctx, eventType, envelope)
+		})
+		if decision == redelivery.Nack {
+			msg.Nack()
+			return
+		}
+		msg.Ack()
+	})
+	s.storeWebhookGuard.Drain()
+	return err
+}
@@ -0,0 +1,49 @@
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+const defaultGeocodeCacheTTL = 30 * 24 * time.Hour
+
+// redisStore defines the Redis operations used to cache geocode results.
+type redisStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+}
+
+// cachedAddress returns a previously resolved address. Cache errors are treated as misses so Redis
+// outages only cost an extra Maps call.
+func (s *service) cachedAddress(ctx context.Context, placeID string) (types.Address, bool) {
+	if s.cache == nil {
+		return types.Address{}, false
+	}
+	raw, err := s.cache.Get(ctx, geocodeCacheKey(placeID))
+	if err != nil || raw == "" {
+		return types.Address{}, false
+	}
+	var addr types.Address
+	if err := json.Unmarshal([]byte(raw), &addr); err != nil {
+		return types.Address{}, false
+	}
+	return addr, true
+}
+
+func (s *service) storeAddress(ctx context.Context, placeID string, addr types.Address) {
+	if s.cache == nil {
+		return
+	}
+	payload, err := json.Marshal(addr)
+	if err != nil {
+		return
+	}
+	_ = s.cache.Set(ctx, geocodeCacheKey(placeID), string(payload), s.cacheTTL)
+}
+
+func geocodeCacheKey(placeID string) string {
+	return "pf:geocode:" + placeID
+}
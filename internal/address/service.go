@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
@@ -13,14 +14,27 @@ import (
 type Service interface {
 	Suggest(ctx context.Context, req SuggestRequest) ([]Suggestion, error)
 	Resolve(ctx context.Context, req ResolveRequest) (types.Address, error)
+	Normalize(ctx context.Context, addr types.Address) (types.Address, error)
+}
+
+// placesClient exposes the Maps operations used by the address service.
+type placesClient interface {
+	Autocomplete(ctx context.Context, req maps.AutocompleteRequest) ([]maps.AutocompleteSuggestion, error)
+	ResolvePlace(ctx context.Context, placeID string) (*maps.PlaceDetails, error)
 }
 
 type service struct {
-	maps *maps.Client
+	maps     placesClient
+	cache    redisStore
+	cacheTTL time.Duration
 }
 
-func NewService(client *maps.Client) Service {
-	return &service{maps: client}
+// NewService builds the address service. A nil cache disables geocode caching.
+func NewService(client placesClient, cache redisStore, cacheTTL time.Duration) Service {
+	if cacheTTL <= 0 {
+		cacheTTL = defaultGeocodeCacheTTL
+	}
+	return &service{maps: client, cache: cache, cacheTTL: cacheTTL}
 }
 
 func (s *service) Suggest(ctx context.Context, req SuggestRequest) ([]Suggestion, error) {
@@ -60,16 +74,108 @@ func (s *service) Resolve(ctx context.Context, req ResolveRequest) (types.Addres
 	if s == nil || s.maps == nil {
 		return types.Address{}, errors.New(errors.CodeDependency, "maps client unavailable")
 	}
-	if strings.TrimSpace(req.PlaceID) == "" {
+	placeID := strings.TrimSpace(req.PlaceID)
+	if placeID == "" {
 		return types.Address{}, errors.New(errors.CodeValidation, "place_id is required")
 	}
 
-	details, err := s.maps.ResolvePlace(ctx, req.PlaceID)
+	if cached, ok := s.cachedAddress(ctx, placeID); ok {
+		return cached, nil
+	}
+
+	details, err := s.maps.ResolvePlace(ctx, placeID)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	addr, err := mapPlaceDetails(details)
+	if err != nil {
+		return types.Address{}, err
+	}
+	s.storeAddress(ctx, placeID, addr)
+	return addr, nil
+}
+
+// Normalize verifies a typed-in address by geocoding it and returns the Maps version with lat/lng
+// filled. The caller's state and country are kept so the codes used for zone and license checks do
+// not turn into Maps' long names. An address Maps cannot find, cannot fully resolve, or places in a
+// different postal code fails with CodeValidation; Maps outages surface as their own errors.
+func (s *service) Normalize(ctx context.Context, addr types.Address) (types.Address, error) {
+	if s == nil || s.maps == nil {
+		return types.Address{}, errors.New(errors.CodeDependency, "maps client unavailable")
+	}
+	query := singleLineAddress(addr)
+	if query == "" {
+		return types.Address{}, errors.New(errors.CodeValidation, "address is required")
+	}
+
+	payload := maps.AutocompleteRequest{Input: query}
+	if country := strings.TrimSpace(addr.Country); len(country) == 2 {
+		payload.IncludedRegionCodes = []string{strings.ToUpper(country)}
+	}
+	suggestions, err := s.maps.Autocomplete(ctx, payload)
 	if err != nil {
 		return types.Address{}, err
 	}
+	if len(suggestions) == 0 || strings.TrimSpace(suggestions[0].PlaceID) == "" {
+		return types.Address{}, unverifiableAddress(addr)
+	}
+
+	placeID := strings.TrimSpace(suggestions[0].PlaceID)
+	resolved, ok := s.cachedAddress(ctx, placeID)
+	if !ok {
+		details, err := s.maps.ResolvePlace(ctx, placeID)
+		if err != nil {
+			return types.Address{}, err
+		}
+		// A place without a street, city, state, or postal code is not a deliverable address.
+		if resolved, err = mapPlaceDetails(details); err != nil {
+			return types.Address{}, unverifiableAddress(addr)
+		}
+		s.storeAddress(ctx, placeID, resolved)
+	}
+	if want := postalPrefix(addr.PostalCode); want != "" && want != postalPrefix(resolved.PostalCode) {
+		return types.Address{}, unverifiableAddress(addr)
+	}
+
+	if strings.TrimSpace(addr.State) != "" {
+		resolved.State = addr.State
+	}
+	if strings.TrimSpace(addr.Country) != "" {
+		resolved.Country = addr.Country
+	}
+	if resolved.Line2 == nil {
+		resolved.Line2 = addr.Line2
+	}
+	return resolved, nil
+}
+
+func unverifiableAddress(addr types.Address) error {
+	return errors.New(errors.CodeValidation, "address could not be verified").WithDetails(map[string]any{
+		"line1":       addr.Line1,
+		"city":        addr.City,
+		"state":       addr.State,
+		"postal_code": addr.PostalCode,
+	})
+}
 
-	return mapPlaceDetails(details)
+func singleLineAddress(addr types.Address) string {
+	parts := make([]string, 0, 4)
+	for _, part := range []string{addr.Line1, addr.City, strings.TrimSpace(addr.State + " " + addr.PostalCode), addr.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// postalPrefix compares ZIP codes on their first five characters so ZIP+4 matches the plain ZIP.
+func postalPrefix(postal string) string {
+	postal = strings.TrimSpace(postal)
+	if len(postal) > 5 {
+		return postal[:5]
+	}
+	return postal
 }
 
 func mapPlaceDetails(details *maps.PlaceDetails) (types.Address, error) {
@@ -1,9 +1,15 @@
 package address
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+	"github.com/redis/go-redis/v9"
 )
 
 func TestMapPlaceDetails(t *testing.T) {
@@ -70,3 +76,152 @@ func TestMapPlaceDetailsMissingCity(t *testing.T) {
 		t.Fatal("expected error when city missing")
 	}
 }
+
+type stubPlacesClient struct {
+	details      *maps.PlaceDetails
+	suggestions  []maps.AutocompleteSuggestion
+	resolveCalls int
+}
+
+func (s *stubPlacesClient) Autocomplete(ctx context.Context, req maps.AutocompleteRequest) ([]maps.AutocompleteSuggestion, error) {
+	return s.suggestions, nil
+}
+
+func (s *stubPlacesClient) ResolvePlace(ctx context.Context, placeID string) (*maps.PlaceDetails, error) {
+	s.resolveCalls++
+	return s.details, nil
+}
+
+type fakeRedis struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) (string, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	f.values[key] = fmt.Sprint(value)
+	f.ttls[key] = ttl
+	return nil
+}
+
+func TestResolveCachesGeocodeResults(t *testing.T) {
+	client := &stubPlacesClient{details: &maps.PlaceDetails{
+		Location: maps.LatLng{Latitude: 35.4676, Longitude: -97.5164},
+		AddressComponents: []maps.AddressComponent{
+			{LongName: "123", Types: []string{"street_number"}},
+			{LongName: "Demo St", Types: []string{"route"}},
+			{LongName: "Example City", Types: []string{"locality"}},
+			{LongName: "Oklahoma", Types: []string{"administrative_area_level_1"}},
+			{LongName: "73106", Types: []string{"postal_code"}},
+		},
+	}}
+	cache := newFakeRedis()
+	svc := NewService(client, cache, time.Hour)
+
+	first, err := svc.Resolve(context.Background(), ResolveRequest{PlaceID: "place_123"})
+	if err != nil {
+		t.Fatalf("first resolve: %v", err)
+	}
+	second, err := svc.Resolve(context.Background(), ResolveRequest{PlaceID: " place_123 "})
+	if err != nil {
+		t.Fatalf("second resolve: %v", err)
+	}
+
+	if client.resolveCalls != 1 {
+		t.Fatalf("expected a single maps call, got %d", client.resolveCalls)
+	}
+	if second.Line1 != first.Line1 || second.Lat != first.Lat || second.Lng != first.Lng {
+		t.Fatalf("cached address mismatch: %+v vs %+v", second, first)
+	}
+	if ttl := cache.ttls[geocodeCacheKey("place_123")]; ttl != time.Hour {
+		t.Fatalf("unexpected cache ttl %s", ttl)
+	}
+}
+
+func TestResolveWithoutCacheCallsMaps(t *testing.T) {
+	client := &stubPlacesClient{details: &maps.PlaceDetails{
+		Location: maps.LatLng{Latitude: 35.4676, Longitude: -97.5164},
+		AddressComponents: []maps.AddressComponent{
+			{LongName: "Demo St", Types: []string{"route"}},
+			{LongName: "Example City", Types: []string{"locality"}},
+			{LongName: "Oklahoma", Types: []string{"administrative_area_level_1"}},
+			{LongName: "73106", Types: []string{"postal_code"}},
+		},
+	}}
+	svc := NewService(client, nil, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Resolve(context.Background(), ResolveRequest{PlaceID: "place_123"}); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+	}
+	if client.resolveCalls != 2 {
+		t.Fatalf("expected maps call per resolve without cache, got %d", client.resolveCalls)
+	}
+}
+
+func TestNormalizeFillsCoordinates(t *testing.T) {
+	client := &stubPlacesClient{
+		suggestions: []maps.AutocompleteSuggestion{{PlaceID: "place_123"}},
+		details: &maps.PlaceDetails{
+			Location: maps.LatLng{Latitude: 35.4676, Longitude: -97.5164},
+			AddressComponents: []maps.AddressComponent{
+				{LongName: "123", Types: []string{"street_number"}},
+				{LongName: "Demo St", Types: []string{"route"}},
+				{LongName: "Example City", Types: []string{"locality"}},
+				{LongName: "Oklahoma", Types: []string{"administrative_area_level_1"}},
+				{LongName: "73106", Types: []string{"postal_code"}},
+			},
+		},
+	}
+	svc := NewService(client, nil, 0)
+
+	result, err := svc.Normalize(context.Background(), types.Address{Line1: "123 demo street", City: "example city", State: "OK", PostalCode: "73106-1234", Country: "US"})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if result.Line1 != "123 Demo St" || result.City != "Example City" {
+		t.Fatalf("expected normalized street and city, got %+v", result)
+	}
+	if result.State != "OK" || result.Country != "US" {
+		t.Fatalf("expected caller state and country to be kept, got %+v", result)
+	}
+	if result.Lat != 35.4676 || result.Lng != -97.5164 {
+		t.Fatalf("unexpected location %+v", result)
+	}
+}
+
+func TestNormalizeRejectsUnverifiableAddress(t *testing.T) {
+	details := &maps.PlaceDetails{
+		Location: maps.LatLng{Latitude: 35.4676, Longitude: -97.5164},
+		AddressComponents: []maps.AddressComponent{
+			{LongName: "Demo St", Types: []string{"route"}},
+			{LongName: "Example City", Types: []string{"locality"}},
+			{LongName: "Oklahoma", Types: []string{"administrative_area_level_1"}},
+			{LongName: "73106", Types: []string{"postal_code"}},
+		},
+	}
+	cases := map[string]*stubPlacesClient{
+		"no suggestions":  {details: details},
+		"postal mismatch": {details: details, suggestions: []maps.AutocompleteSuggestion{{PlaceID: "place_123"}}},
+	}
+	for name, client := range cases {
+		svc := NewService(client, nil, 0)
+		_, err := svc.Normalize(context.Background(), types.Address{Line1: "1 Nowhere Rd", City: "Example City", State: "OK", PostalCode: "74104"})
+		typed := errors.As(err)
+		if typed == nil || typed.Code() != errors.CodeValidation {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+}
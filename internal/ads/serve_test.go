@@ -266,6 +266,10 @@ func (stubAnalytics) Query(_ context.Context, _ types.MarketplaceQueryRequest) (
 	return &types.MarketplaceQueryResponse{}, nil
 }
 
+func (stubAnalytics) QueryVendor(_ context.Context, _ string, _ types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
+	return &types.MarketplaceQueryResponse{}, nil
+}
+
 func (stubAnalytics) QueryAd(_ context.Context, _ types.AdQueryRequest) (*types.AdQueryResponse, error) {
 	return &types.AdQueryResponse{}, nil
 }
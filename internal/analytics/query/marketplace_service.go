@@ -214,6 +214,7 @@ func (s *marketplaceService) Query(ctx context.Context, req types.MarketplaceQue
 		req.End,
 		req.StoreID,
 		req.StoreType,
+		req.TimeZone,
 	)
 	if err != nil {
 		return nil, err
@@ -279,6 +280,9 @@ func validateRequest(req types.MarketplaceQueryRequest) error {
 	if req.End.Before(req.Start) {
 		return pkgerrors.New(pkgerrors.CodeValidation, "end must be after start")
 	}
+	if _, err := resolveTimeZone(req.TimeZone); err != nil {
+		return err
+	}
 	return nil
 }
 
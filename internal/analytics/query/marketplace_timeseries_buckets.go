@@ -11,7 +11,7 @@
 // and adds only an OPTIONAL `bucket_interval` string if you choose to include it
 // at the response layer.
 //
-// Timezone: fixed to America/Chicago (US Central) for now, per your request.
+// Timezone: buckets use the request's IANA zone, defaulting to America/Chicago (US Central).
 //
 // How to wire (minimal change):
 // - In marketplaceService.Query(...), replace the 4 separate s.querySeries(...) calls
@@ -21,7 +21,7 @@
 // Notes:
 // - Buckets are zero-filled using GENERATE_TIMESTAMP_ARRAY / GENERATE_DATE_ARRAY.
 // - The `date` string returned is an ISO-ish bucket key suitable for sorting.
-//   hour: "YYYY-MM-DDTHH:00:00-06:00" (or -05:00 in DST) because we format in tz
+//   hour: "YYYY-MM-DDTHH:00:00-06:00" (offset of the bucketing zone) because we format in tz
 //   day/week: "YYYY-MM-DD"
 //   month: "YYYY-MM"
 //   year: "YYYY"
@@ -31,6 +31,8 @@ package query
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	cloudbigquery "cloud.google.com/go/bigquery"
@@ -42,8 +44,31 @@ import (
 
 const (
 	centralTZ = "America/Chicago"
+
+	// timeZonePlaceholder marks where the SQL templates take the bucketing zone as a string literal.
+	timeZonePlaceholder = "{{time_zone}}"
 )
 
+// timeZoneNamePattern limits zone names to the characters IANA names use, so a name that
+// time.LoadLocation accepts can still be inlined safely as a SQL string literal.
+var timeZoneNamePattern = regexp.MustCompile(`^[A-Za-z0-9_+\-/]+$`)
+
+// resolveTimeZone validates name against the IANA database and returns the zone to bucket in,
+// falling back to America/Chicago when name is empty.
+func resolveTimeZone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return centralTZ, nil
+	}
+	if !timeZoneNamePattern.MatchString(name) {
+		return "", errors.New(errors.CodeValidation, "invalid time zone")
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", errors.Wrap(errors.CodeValidation, err, "invalid time zone")
+	}
+	return name, nil
+}
+
 // returns one of: "hour","day","week","month","year"
 func selectGranularity(start, end time.Time) string {
 	d := end.Sub(start)
@@ -97,6 +122,7 @@ func (s *marketplaceService) queryBucketedSeries(
 	end time.Time,
 	storeID string,
 	storeType enums.StoreType,
+	timeZone string,
 ) (*bucketedSeriesResult, error) {
 	if s == nil || s.client == nil {
 		return nil, fmt.Errorf("bigquery client required")
@@ -115,8 +141,12 @@ func (s *marketplaceService) queryBucketedSeries(
 	if err := validateGranularity(g); err != nil {
 		return nil, err
 	}
+	tz, err := resolveTimeZone(timeZone)
+	if err != nil {
+		return nil, err
+	}
 
-	sql := fmt.Sprintf(bucketedMarketplaceSeriesSQL(g), tableRef, storeClause)
+	sql := fmt.Sprintf(bucketedMarketplaceSeriesSQL(g, tz), tableRef, storeClause)
 
 	params := []cloudbigquery.QueryParameter{
 		{Name: "storeID", Value: storeID},
@@ -161,22 +191,26 @@ func (s *marketplaceService) queryBucketedSeries(
 	return out, nil
 }
 
-func bucketedMarketplaceSeriesSQL(granularity string) string {
+// bucketedMarketplaceSeriesSQL returns the template for granularity with timeZone (already
+// validated by resolveTimeZone) inlined wherever the buckets are truncated or formatted.
+func bucketedMarketplaceSeriesSQL(granularity, timeZone string) string {
+	var template string
 	switch granularity {
 	case "hour":
-		return bucketedHourlySQL
+		template = bucketedHourlySQL
 	case "day":
-		return bucketedDailySQL
+		template = bucketedDailySQL
 	case "week":
-		return bucketedWeeklySQL
+		template = bucketedWeeklySQL
 	case "month":
-		return bucketedMonthlySQL
+		template = bucketedMonthlySQL
 	case "year":
-		return bucketedYearlySQL
+		template = bucketedYearlySQL
 	default:
 		// validateGranularity should prevent this
-		return bucketedDailySQL
+		template = bucketedDailySQL
 	}
+	return strings.ReplaceAll(template, timeZonePlaceholder, `"`+timeZone+`"`)
 }
 
 // =========================
@@ -184,7 +218,7 @@ func bucketedMarketplaceSeriesSQL(granularity string) string {
 // =========================
 //
 // Each query returns rows with columns:
-// - date (string key formatted in America/Chicago)
+// - date (string key formatted in the bucketing zone)
 // - orders (int64)
 // - gross_revenue_cents (int64)
 // - net_revenue_cents (int64)
@@ -193,6 +227,7 @@ func bucketedMarketplaceSeriesSQL(granularity string) string {
 // Replace placeholders:
 // - %s = tableRef (already wrapped in backticks upstream)
 // - %s = storeClause (uses @storeID param)
+// - {{time_zone}} = quoted IANA zone (see bucketedMarketplaceSeriesSQL)
 
 const bucketedHourlySQL = `
 WITH buckets AS (
@@ -203,7 +238,7 @@ WITH buckets AS (
 ),
 agg AS (
   SELECT
-    TIMESTAMP_TRUNC(occurred_at, HOUR, "` + centralTZ + `") AS bucket_ts,
+    TIMESTAMP_TRUNC(occurred_at, HOUR, ` + timeZonePlaceholder + `) AS bucket_ts,
 
     COUNTIF(event_type = 'order_created') AS orders,
 
@@ -234,8 +269,8 @@ agg AS (
   GROUP BY bucket_ts
 )
 SELECT
-  -- RFC3339-ish local timestamp for sorting + labeling (includes CST/CDT offset)
-  FORMAT_TIMESTAMP('%%Y-%%m-%%dT%%H:00:00%%Ez', b.bucket_ts, "America/Chicago") AS date,
+  -- RFC3339-ish local timestamp for sorting + labeling (includes the zone's offset)
+  FORMAT_TIMESTAMP('%%Y-%%m-%%dT%%H:00:00%%Ez', b.bucket_ts, ` + timeZonePlaceholder + `) AS date,
   COALESCE(a.orders, 0) AS orders,
   COALESCE(a.gross_revenue_cents, 0) AS gross_revenue_cents,
   COALESCE(a.net_revenue_cents, 0) AS net_revenue_cents,
@@ -250,15 +285,15 @@ WITH buckets AS (
   SELECT d AS bucket_day
   FROM UNNEST(
     GENERATE_DATE_ARRAY(
-      DATE(@start, "` + centralTZ + `"),
-      DATE(@end, "` + centralTZ + `"),
+      DATE(@start, ` + timeZonePlaceholder + `),
+      DATE(@end, ` + timeZonePlaceholder + `),
       INTERVAL 1 DAY
     )
   ) AS d
 ),
 agg AS (
   SELECT
-    DATE(TIMESTAMP_TRUNC(occurred_at, DAY, "` + centralTZ + `")) AS bucket_day,
+    DATE(TIMESTAMP_TRUNC(occurred_at, DAY, ` + timeZonePlaceholder + `)) AS bucket_day,
 
     COUNTIF(event_type = 'order_created') AS orders,
 
@@ -304,8 +339,8 @@ WITH buckets AS (
   SELECT d AS bucket_day
   FROM UNNEST(
     GENERATE_DATE_ARRAY(
-      DATE(@start, "` + centralTZ + `"),
-      DATE(@end, "` + centralTZ + `"),
+      DATE(@start, ` + timeZonePlaceholder + `),
+      DATE(@end, ` + timeZonePlaceholder + `),
       INTERVAL 1 WEEK
     )
   ) AS d
@@ -313,7 +348,7 @@ WITH buckets AS (
 agg AS (
   SELECT
     -- Week bucket = Monday-start (local)
-    DATE_TRUNC(DATE(occurred_at, "` + centralTZ + `"), WEEK(MONDAY)) AS bucket_day,
+    DATE_TRUNC(DATE(occurred_at, ` + timeZonePlaceholder + `), WEEK(MONDAY)) AS bucket_day,
 
     COUNTIF(event_type = 'order_created') AS orders,
 
@@ -363,15 +398,15 @@ WITH buckets AS (
   SELECT d AS bucket_day
   FROM UNNEST(
     GENERATE_DATE_ARRAY(
-      DATE(@start, "` + centralTZ + `"),
-      DATE(@end, "` + centralTZ + `"),
+      DATE(@start, ` + timeZonePlaceholder + `),
+      DATE(@end, ` + timeZonePlaceholder + `),
       INTERVAL 1 MONTH
     )
   ) AS d
 ),
 agg AS (
   SELECT
-    DATE_TRUNC(DATE(occurred_at, "` + centralTZ + `"), MONTH) AS bucket_day,
+    DATE_TRUNC(DATE(occurred_at, ` + timeZonePlaceholder + `), MONTH) AS bucket_day,
 
     COUNTIF(event_type = 'order_created') AS orders,
 
@@ -421,15 +456,15 @@ WITH buckets AS (
   SELECT d AS bucket_day
   FROM UNNEST(
     GENERATE_DATE_ARRAY(
-      DATE(@start, "` + centralTZ + `"),
-      DATE(@end, "` + centralTZ + `"),
+      DATE(@start, ` + timeZonePlaceholder + `),
+      DATE(@end, ` + timeZonePlaceholder + `),
       INTERVAL 1 YEAR
     )
   ) AS d
 ),
 agg AS (
   SELECT
-    DATE_TRUNC(DATE(occurred_at, "` + centralTZ + `"), YEAR) AS bucket_day,
+    DATE_TRUNC(DATE(occurred_at, ` + timeZonePlaceholder + `), YEAR) AS bucket_day,
 
     COUNTIF(event_type = 'order_created') AS orders,
 
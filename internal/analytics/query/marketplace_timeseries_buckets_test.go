@@ -0,0 +1,74 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+func TestBucketedSeriesSQLAppliesTimeZone(t *testing.T) {
+	tz, err := resolveTimeZone(" America/New_York ")
+	if err != nil {
+		t.Fatalf("resolve time zone: %v", err)
+	}
+
+	daily := bucketedMarketplaceSeriesSQL("day", tz)
+	for _, want := range []string{
+		`DATE(@start, "America/New_York")`,
+		`DATE(@end, "America/New_York")`,
+		`TIMESTAMP_TRUNC(occurred_at, DAY, "America/New_York")`,
+	} {
+		if !strings.Contains(daily, want) {
+			t.Fatalf("expected daily sql to contain %s:\n%s", want, daily)
+		}
+	}
+
+	weekly := bucketedMarketplaceSeriesSQL("week", tz)
+	if !strings.Contains(weekly, `DATE_TRUNC(DATE(occurred_at, "America/New_York"), WEEK(MONDAY))`) {
+		t.Fatalf("expected weekly buckets in the requested zone:\n%s", weekly)
+	}
+
+	hourly := bucketedMarketplaceSeriesSQL("hour", tz)
+	if !strings.Contains(hourly, `b.bucket_ts, "America/New_York")`) {
+		t.Fatalf("expected hourly labels formatted in the requested zone:\n%s", hourly)
+	}
+
+	for _, sql := range []string{daily, weekly, hourly} {
+		if strings.Contains(sql, centralTZ) || strings.Contains(sql, timeZonePlaceholder) {
+			t.Fatalf("expected only the requested zone in sql:\n%s", sql)
+		}
+	}
+}
+
+func TestResolveTimeZoneDefaultsToCentral(t *testing.T) {
+	tz, err := resolveTimeZone("")
+	if err != nil {
+		t.Fatalf("resolve time zone: %v", err)
+	}
+	if tz != centralTZ {
+		t.Fatalf("expected %s, got %s", centralTZ, tz)
+	}
+	if sql := bucketedMarketplaceSeriesSQL("month", tz); !strings.Contains(sql, `DATE(occurred_at, "America/Chicago")`) {
+		t.Fatalf("expected monthly buckets in central time:\n%s", sql)
+	}
+}
+
+func TestValidateRequestRejectsInvalidTimeZone(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, zone := range []string{"Mars/Olympus_Mons", `America/Chicago") OR ("1`, "../etc/passwd"} {
+		err := validateRequest(types.MarketplaceQueryRequest{
+			StoreID:   "store-1",
+			StoreType: enums.StoreTypeVendor,
+			Start:     start,
+			End:       start.Add(30 * 24 * time.Hour),
+			TimeZone:  zone,
+		})
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+			t.Fatalf("expected validation error for %q, got %v", zone, err)
+		}
+	}
+}
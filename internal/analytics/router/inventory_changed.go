@@ -0,0 +1,61 @@
+package router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
+	analyticswriter "github.com/angelmondragon/packfinderz-backend/internal/analytics/writer"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+)
+
+type inventoryChangedHandler struct {
+	writer Writer
+	logg   *logger.Logger
+}
+
+func newInventoryChangedHandler(writer Writer, logg *logger.Logger) Handler {
+	return &inventoryChangedHandler{writer: writer, logg: logg}
+}
+
+// Handle stores the stock snapshot as a marketplace_events row scoped to the vendor, with the
+// counters, deltas, and reason in the payload column.
+func (h *inventoryChangedHandler) Handle(ctx context.Context, envelope types.Envelope, payload any) error {
+	event, ok := payload.(*payloads.InventoryChangedEvent)
+	if !ok {
+		return fmt.Errorf("invalid payload for inventory_changed")
+	}
+	fields := map[string]any{
+		"event_type":   envelope.EventType,
+		"product_id":   event.ProductID,
+		"vendor_store": event.VendorStoreID,
+		"reason":       event.Reason,
+	}
+	logCtx := h.logg.WithFields(ctx, fields)
+
+	payloadJSON, err := analyticswriter.EncodeJSON(event)
+	if err != nil {
+		h.logg.Error(logCtx, "failed to encode inventory payload", err)
+		return fmt.Errorf("encode payload json: %w", err)
+	}
+	occurred := event.ChangedAt
+	if occurred.IsZero() {
+		occurred = envelope.OccurredAt
+	}
+	row := types.MarketplaceEventRow{
+		EventID:       envelope.EventID,
+		EventType:     string(envelope.EventType),
+		OccurredAt:    occurred.UTC(),
+		VendorStoreID: stringPtr(event.VendorStoreID.String()),
+		Payload:       payloadJSON,
+	}
+
+	if err := h.writer.InsertMarketplace(logCtx, row); err != nil {
+		h.logg.Error(logCtx, "failed to insert marketplace row", err)
+		return err
+	}
+
+	h.logg.Info(logCtx, "inventory_changed handler inserted marketplace row")
+	return nil
+}
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+)
+
+func TestRouterInsertsInventoryChangedRow(t *testing.T) {
+	writer := &fakeWriter{}
+	router, err := NewRouter(writer, logger.New(logger.Options{ServiceName: "router-inventory-test"}), nil)
+	if err != nil {
+		t.Fatalf("construct router: %v", err)
+	}
+	changedAt := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	event := payloads.InventoryChangedEvent{
+		ProductID:      uuid.New(),
+		VendorStoreID:  uuid.New(),
+		AvailableQty:   3,
+		ReservedQty:    2,
+		AvailableDelta: -2,
+		ReservedDelta:  2,
+		Reason:         enums.InventoryAdjustmentReasonCheckoutReserve,
+		ChangedAt:      changedAt,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+
+	envelope := types.Envelope{
+		EventID:    "inventory-event",
+		EventType:  enums.AnalyticsEventInventoryChanged,
+		OccurredAt: changedAt.Add(time.Minute),
+		Payload:    data,
+	}
+	if err := router.Handle(context.Background(), envelope); err != nil {
+		t.Fatalf("handle inventory_changed: %v", err)
+	}
+
+	if len(writer.inserted) != 1 {
+		t.Fatalf("expected 1 insert, got %d", len(writer.inserted))
+	}
+	row := writer.inserted[0]
+	if row.EventID != "inventory-event" || row.EventType != "inventory_changed" {
+		t.Fatalf("unexpected row identity: %s %s", row.EventID, row.EventType)
+	}
+	if !row.OccurredAt.Equal(changedAt) {
+		t.Fatalf("expected occurred_at from changed_at, got %s", row.OccurredAt)
+	}
+	if row.VendorStoreID == nil || *row.VendorStoreID != event.VendorStoreID.String() {
+		t.Fatalf("unexpected vendor store: %v", row.VendorStoreID)
+	}
+	if row.OrderID != nil || row.GrossRevenueCents != nil {
+		t.Fatalf("expected no order or revenue columns, got %+v", row)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(row.Payload.JSONVal), &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	for key, want := range map[string]any{
+		"product_id":      event.ProductID.String(),
+		"available_qty":   float64(3),
+		"reserved_qty":    float64(2),
+		"available_delta": float64(-2),
+		"reserved_delta":  float64(2),
+		"reason":          "checkout_reserve",
+	} {
+		if payload[key] != want {
+			t.Fatalf("payload %s = %v, want %v", key, payload[key], want)
+		}
+	}
+}
@@ -79,6 +79,10 @@ func NewRouter(writer Writer, logg *logger.Logger, overrides map[enums.Analytics
 			factory: func() any { return &analyticspayloads.AdDailyChargeRecordedEvent{} },
 			handler: newAdDailyChargeHandler(writer, logg),
 		},
+		enums.AnalyticsEventInventoryChanged: {
+			factory: func() any { return &outboxpayloads.InventoryChangedEvent{} },
+			handler: newInventoryChangedHandler(writer, logg),
+		},
 	}
 
 	for event, custom := range overrides {
@@ -3,16 +3,21 @@ package analytics
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics/query"
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
 	"github.com/angelmondragon/packfinderz-backend/pkg/bigquery"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 )
 
 // Service provides analytics reports based on marketplace events.
 type Service interface {
 	// Query returns marketplace KPIs for the provided request.
 	Query(ctx context.Context, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error)
+	// QueryVendor returns marketplace KPIs scoped to the authenticated vendor store.
+	QueryVendor(ctx context.Context, vendorStoreID string, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error)
 	// QueryAd returns ad analytics for a store scoped to the provided ad ID.
 	QueryAd(ctx context.Context, req types.AdQueryRequest) (*types.AdQueryResponse, error)
 }
@@ -48,6 +53,26 @@ func (s *service) Query(ctx context.Context, req types.MarketplaceQueryRequest)
 	return s.marketplace.Query(ctx, req)
 }
 
+// QueryVendor forces the query onto vendorStoreID, which callers take from the authenticated store
+// context. A request naming another store or the buyer side is rejected rather than narrowed, so a
+// vendor can never widen the scope through request fields.
+func (s *service) QueryVendor(ctx context.Context, vendorStoreID string, req types.MarketplaceQueryRequest) (*types.MarketplaceQueryResponse, error) {
+	vendorStoreID = strings.TrimSpace(vendorStoreID)
+	if vendorStoreID == "" {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "vendor store context required")
+	}
+	if requested := strings.TrimSpace(req.StoreID); requested != "" && requested != vendorStoreID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "analytics limited to the active vendor store")
+	}
+	if req.StoreType != "" && req.StoreType != enums.StoreTypeVendor {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "analytics limited to the active vendor store")
+	}
+
+	req.StoreID = vendorStoreID
+	req.StoreType = enums.StoreTypeVendor
+	return s.marketplace.Query(ctx, req)
+}
+
 func (s *service) QueryAd(ctx context.Context, req types.AdQueryRequest) (*types.AdQueryResponse, error) {
 	return s.ad.Query(ctx, req)
 }
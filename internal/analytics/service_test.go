@@ -8,6 +8,7 @@ import (
 
 	"github.com/angelmondragon/packfinderz-backend/internal/analytics/types"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 )
 
 type fakeMarketplaceService struct {
@@ -77,6 +78,55 @@ func TestServiceQueryPropagatesError(t *testing.T) {
 	}
 }
 
+func TestServiceQueryVendorForcesVendorScope(t *testing.T) {
+	fake := &fakeMarketplaceService{}
+	srv := &service{marketplace: fake}
+	now := time.Now().UTC()
+
+	if _, err := srv.QueryVendor(context.Background(), "vendor-1", types.MarketplaceQueryRequest{Start: now, End: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.lastReq.StoreID != "vendor-1" || fake.lastReq.StoreType != enums.StoreTypeVendor {
+		t.Fatalf("expected vendor-1 vendor scope, got %s/%s", fake.lastReq.StoreID, fake.lastReq.StoreType)
+	}
+
+	fake.lastReq = types.MarketplaceQueryRequest{}
+	if _, err := srv.QueryVendor(context.Background(), "vendor-1", types.MarketplaceQueryRequest{StoreID: "vendor-1", StoreType: enums.StoreTypeVendor, Start: now, End: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("expected matching scope to be accepted: %v", err)
+	}
+	if fake.lastReq.StoreID != "vendor-1" {
+		t.Fatalf("expected vendor-1 scope, got %s", fake.lastReq.StoreID)
+	}
+}
+
+func TestServiceQueryVendorRejectsWidenedScope(t *testing.T) {
+	now := time.Now().UTC()
+	cases := map[string]struct {
+		vendorStoreID string
+		req           types.MarketplaceQueryRequest
+		code          pkgerrors.Code
+	}{
+		"other store":     {vendorStoreID: "vendor-1", req: types.MarketplaceQueryRequest{StoreID: "vendor-2"}, code: pkgerrors.CodeForbidden},
+		"buyer side":      {vendorStoreID: "vendor-1", req: types.MarketplaceQueryRequest{StoreType: enums.StoreTypeBuyer}, code: pkgerrors.CodeForbidden},
+		"missing context": {vendorStoreID: " ", req: types.MarketplaceQueryRequest{StoreID: "vendor-2", StoreType: enums.StoreTypeVendor}, code: pkgerrors.CodeForbidden},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeMarketplaceService{}
+			srv := &service{marketplace: fake}
+			tc.req.Start, tc.req.End = now, now.Add(time.Hour)
+
+			_, err := srv.QueryVendor(context.Background(), tc.vendorStoreID, tc.req)
+			if typed := pkgerrors.As(err); typed == nil || typed.Code() != tc.code {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+			if fake.lastReq.StoreID != "" {
+				t.Fatalf("expected no query to run, got %+v", fake.lastReq)
+			}
+		})
+	}
+}
+
 type fakeAdService struct {
 	lastReq  types.AdQueryRequest
 	response *types.AdQueryResponse
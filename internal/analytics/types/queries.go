@@ -12,6 +12,9 @@ type MarketplaceQueryRequest struct {
 	StoreType enums.StoreType
 	Start     time.Time
 	End       time.Time
+	// TimeZone is the IANA zone the time series are bucketed in. Empty keeps the default
+	// (America/Chicago).
+	TimeZone string
 }
 
 // TimeSeriesPoint describes a single date/value pair returned by the query service.
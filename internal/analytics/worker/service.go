@@ -14,6 +14,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/google/uuid"
 )
 
@@ -45,11 +46,12 @@ type Service struct {
 	subscription *gcppubsub.Subscriber
 	handler      Handler
 	manager      idempotencyChecker
+	guard        *redelivery.Guard
 	logg         *logger.Logger
 }
 
-// NewService creates a new analytics worker service.
-func NewService(subscription *gcppubsub.Subscriber, handler Handler, manager idempotencyChecker, logg *logger.Logger) (*Service, error) {
+// NewService creates a new analytics worker service. A nil guard retries failing messages without a cap.
+func NewService(subscription *gcppubsub.Subscriber, handler Handler, manager idempotencyChecker, logg *logger.Logger, guard *redelivery.Guard) (*Service, error) {
 	if subscription == nil {
 		return nil, errors.New("analytics subscription is required")
 	}
@@ -67,6 +69,7 @@ func NewService(subscription *gcppubsub.Subscriber, handler Handler, manager ide
 		subscription: subscription,
 		handler:      handler,
 		manager:      manager,
+		guard:        guard,
 		logg:         logg,
 	}, nil
 }
@@ -80,13 +83,22 @@ func (s *Service) Run(ctx context.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return s.subscription.Receive(ctx, func(innerCtx context.Context, msg *gcppubsub.Message) {
-		if s.process(innerCtx, msg).nack {
+	err := s.subscription.Receive(ctx, func(innerCtx context.Context, msg *gcppubsub.Message) {
+		delivery := redelivery.Message{ID: msg.ID, Data: msg.Data, Attributes: msg.Attributes, DeliveryAttempt: msg.DeliveryAttempt}
+		decision := s.guard.Handle(innerCtx, delivery, func(ctx context.Context) error {
+			if s.process(ctx, msg).nack {
+				return redelivery.ErrRetry
+			}
+			return nil
+		})
+		if decision == redelivery.Nack {
 			msg.Nack()
 			return
 		}
 		msg.Ack()
 	})
+	s.guard.Drain()
+	return err
 }
 
 func (s *Service) process(ctx context.Context, msg *gcppubsub.Message) processResult {
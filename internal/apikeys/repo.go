@@ -0,0 +1,92 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository persists store API keys.
+type Repository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	ListByStore(ctx context.Context, storeID uuid.UUID) ([]models.APIKey, error)
+	FindByID(ctx context.Context, storeID, keyID uuid.UUID) (*models.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*KeyRecord, error)
+	Revoke(ctx context.Context, keyID uuid.UUID, now time.Time) error
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, now time.Time) error
+}
+
+// KeyRecord pairs a key with the type of the store it belongs to.
+type KeyRecord struct {
+	models.APIKey
+	StoreType enums.StoreType `gorm:"column:store_type"`
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository binds the API key repository to the database.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *repository) ListByStore(ctx context.Context, storeID uuid.UUID) ([]models.APIKey, error) {
+	var rows []models.APIKey
+	err := r.db.WithContext(ctx).
+		Where("store_id = ?", storeID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *repository) FindByID(ctx context.Context, storeID, keyID uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).Where("id = ? AND store_id = ?", keyID, storeID).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *repository) FindByPrefix(ctx context.Context, prefix string) (*KeyRecord, error) {
+	var record KeyRecord
+	err := r.db.WithContext(ctx).
+		Table("api_keys AS k").
+		Select("k.*, s.type AS store_type").
+		Joins("JOIN stores s ON s.id = k.store_id").
+		Where("k.prefix = ?", prefix).
+		Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *repository) Revoke(ctx context.Context, keyID uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", now).Error
+}
+
+func (r *repository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", keyID).
+		Update("last_used_at", now).Error
+}
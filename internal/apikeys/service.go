@@ -0,0 +1,239 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	pkgAuth "github.com/angelmondragon/packfinderz-backend/pkg/auth"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	maxNameLength = 100
+	// lastUsedResolution throttles last_used_at writes so busy keys do not write on every call.
+	lastUsedResolution = time.Minute
+)
+
+// ManagerRoles may create and revoke a store's API keys. A key only works while the member who
+// created it still holds one of these roles in the store.
+var ManagerRoles = []enums.MemberRole{
+	enums.MemberRoleOwner,
+	enums.MemberRoleAdmin,
+}
+
+type membershipsRepository interface {
+	UserHasRole(ctx context.Context, userID, storeID uuid.UUID, roles ...enums.MemberRole) (bool, error)
+}
+
+// Service manages store API keys and resolves them for the API key middleware.
+type Service interface {
+	CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (*CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, storeID uuid.UUID) ([]APIKey, error)
+	RevokeAPIKey(ctx context.Context, storeID, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, token string) (*pkgAuth.APIKeyPrincipal, error)
+}
+
+// CreateAPIKeyInput describes a new key for the active store.
+type CreateAPIKeyInput struct {
+	StoreID uuid.UUID
+	UserID  uuid.UUID
+	Name    string
+	Scopes  []enums.APIKeyScope
+}
+
+// APIKey is the API view of a key. The secret is never included.
+type APIKey struct {
+	ID         uuid.UUID           `json:"id"`
+	Name       string              `json:"name"`
+	Prefix     string              `json:"prefix"`
+	Scopes     []enums.APIKeyScope `json:"scopes"`
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time          `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// CreatedAPIKey returns the plaintext key exactly once.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type service struct {
+	repo        Repository
+	memberships membershipsRepository
+	now         func() time.Time
+}
+
+// NewService wires the API key service.
+func NewService(repo Repository, memberships membershipsRepository) (Service, error) {
+	if repo == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeDependency, "api keys repository required")
+	}
+	if memberships == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeDependency, "memberships repository required")
+	}
+	return &service{repo: repo, memberships: memberships, now: time.Now}, nil
+}
+
+func (s *service) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (*CreatedAPIKey, error) {
+	if input.StoreID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	if input.UserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "user id required")
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "name must be between 1 and 100 characters")
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	token, prefix, hash, err := pkgAuth.GenerateAPIKey()
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeInternal, err, "generate api key")
+	}
+	record := &models.APIKey{
+		StoreID:         input.StoreID,
+		CreatedByUserID: input.UserID,
+		Name:            name,
+		Prefix:          prefix,
+		SecretHash:      hash,
+		Scopes:          scopeStrings(scopes),
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create api key")
+	}
+	return &CreatedAPIKey{APIKey: viewFromModel(*record), Key: token}, nil
+}
+
+func (s *service) ListAPIKeys(ctx context.Context, storeID uuid.UUID) ([]APIKey, error) {
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	rows, err := s.repo.ListByStore(ctx, storeID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list api keys")
+	}
+	views := make([]APIKey, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, viewFromModel(row))
+	}
+	return views, nil
+}
+
+// RevokeAPIKey is idempotent; revoking an already revoked key succeeds.
+func (s *service) RevokeAPIKey(ctx context.Context, storeID, keyID uuid.UUID) error {
+	if storeID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	key, err := s.repo.FindByID(ctx, storeID, keyID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load api key")
+	}
+	if key == nil {
+		return pkgerrors.New(pkgerrors.CodeNotFound, "api key not found")
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	if err := s.repo.Revoke(ctx, key.ID, s.now().UTC()); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "revoke api key")
+	}
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, token string) (*pkgAuth.APIKeyPrincipal, error) {
+	prefix, secret, err := pkgAuth.ParseAPIKey(token)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeUnauthorized, err, "invalid api key")
+	}
+	record, err := s.repo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load api key")
+	}
+	if record == nil || subtle.ConstantTimeCompare([]byte(record.SecretHash), []byte(pkgAuth.HashAPIKeySecret(secret))) != 1 {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "invalid api key")
+	}
+	if record.RevokedAt != nil {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "api key revoked")
+	}
+	// The key acts for its creator, so it stops working once they leave the store or lose the role.
+	ok, err := s.memberships.UserHasRole(ctx, record.CreatedByUserID, record.StoreID, ManagerRoles...)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check api key creator membership")
+	}
+	if !ok {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "api key creator no longer manages the store")
+	}
+
+	now := s.now().UTC()
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= lastUsedResolution {
+		// Best effort: a failed bookkeeping write should not block the request.
+		_ = s.repo.TouchLastUsed(ctx, record.ID, now)
+	}
+
+	scopes := make([]enums.APIKeyScope, 0, len(record.Scopes))
+	for _, raw := range record.Scopes {
+		if scope, err := enums.ParseAPIKeyScope(raw); err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &pkgAuth.APIKeyPrincipal{
+		KeyID:     record.ID,
+		StoreID:   record.StoreID,
+		StoreType: record.StoreType,
+		UserID:    record.CreatedByUserID,
+		Scopes:    scopes,
+	}, nil
+}
+
+func normalizeScopes(scopes []enums.APIKeyScope) ([]enums.APIKeyScope, error) {
+	if len(scopes) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "at least one scope is required")
+	}
+	seen := make(map[enums.APIKeyScope]struct{}, len(scopes))
+	out := make([]enums.APIKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid scope "+string(scope))
+		}
+		if _, dup := seen[scope]; dup {
+			continue
+		}
+		seen[scope] = struct{}{}
+		out = append(out, scope)
+	}
+	return out, nil
+}
+
+func scopeStrings(scopes []enums.APIKeyScope) []string {
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		out = append(out, string(scope))
+	}
+	return out
+}
+
+func viewFromModel(row models.APIKey) APIKey {
+	scopes := make([]enums.APIKeyScope, 0, len(row.Scopes))
+	for _, raw := range row.Scopes {
+		scopes = append(scopes, enums.APIKeyScope(raw))
+	}
+	return APIKey{
+		ID:         row.ID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -0,0 +1,197 @@
+package apikeys
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type memoryRepository struct {
+	keys    map[uuid.UUID]*models.APIKey
+	touches int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{keys: map[uuid.UUID]*models.APIKey{}}
+}
+
+func (m *memoryRepository) Create(_ context.Context, key *models.APIKey) error {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	copied := *key
+	m.keys[key.ID] = &copied
+	return nil
+}
+
+func (m *memoryRepository) ListByStore(_ context.Context, storeID uuid.UUID) ([]models.APIKey, error) {
+	var rows []models.APIKey
+	for _, key := range m.keys {
+		if key.StoreID == storeID {
+			rows = append(rows, *key)
+		}
+	}
+	return rows, nil
+}
+
+func (m *memoryRepository) FindByID(_ context.Context, storeID, keyID uuid.UUID) (*models.APIKey, error) {
+	key, ok := m.keys[keyID]
+	if !ok || key.StoreID != storeID {
+		return nil, nil
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (m *memoryRepository) FindByPrefix(_ context.Context, prefix string) (*KeyRecord, error) {
+	for _, key := range m.keys {
+		if key.Prefix == prefix {
+			return &KeyRecord{APIKey: *key, StoreType: enums.StoreTypeVendor}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryRepository) Revoke(_ context.Context, keyID uuid.UUID, now time.Time) error {
+	m.keys[keyID].RevokedAt = &now
+	return nil
+}
+
+func (m *memoryRepository) TouchLastUsed(_ context.Context, keyID uuid.UUID, now time.Time) error {
+	m.touches++
+	m.keys[keyID].LastUsedAt = &now
+	return nil
+}
+
+type stubMemberships struct {
+	managers map[uuid.UUID]uuid.UUID
+}
+
+func (s *stubMemberships) UserHasRole(_ context.Context, userID, storeID uuid.UUID, roles ...enums.MemberRole) (bool, error) {
+	for _, role := range roles {
+		if role == enums.MemberRoleOwner || role == enums.MemberRoleAdmin {
+			return s.managers[userID] == storeID, nil
+		}
+	}
+	return false, nil
+}
+
+func TestServiceCreateAuthenticateAndRevoke(t *testing.T) {
+	repo := newMemoryRepository()
+	storeID := uuid.New()
+	userID := uuid.New()
+	svc, err := NewService(repo, &stubMemberships{managers: map[uuid.UUID]uuid.UUID{userID: storeID}})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, CreateAPIKeyInput{
+		StoreID: storeID,
+		UserID:  userID,
+		Name:    " ERP sync ",
+		Scopes:  []enums.APIKeyScope{enums.APIKeyScopeOrdersRead, enums.APIKeyScopeOrdersRead},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, "pfz_"+created.Prefix+"_") || created.Name != "ERP sync" || len(created.Scopes) != 1 {
+		t.Fatalf("unexpected created key %+v", created)
+	}
+	if stored := repo.keys[created.ID]; stored.SecretHash == "" || strings.Contains(created.Key, stored.SecretHash) {
+		t.Fatalf("expected only a hash of the secret to be stored")
+	}
+
+	principal, err := svc.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if principal.StoreID != storeID || principal.UserID != userID || principal.StoreType != enums.StoreTypeVendor {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+	if !principal.HasScope(enums.APIKeyScopeOrdersRead) || principal.HasScope(enums.APIKeyScopeOrdersWrite) {
+		t.Fatalf("unexpected scopes %v", principal.Scopes)
+	}
+	if _, err := svc.Authenticate(ctx, created.Key); err != nil {
+		t.Fatalf("authenticate again: %v", err)
+	}
+	if repo.touches != 1 {
+		t.Fatalf("expected last_used_at to be written once per minute, got %d writes", repo.touches)
+	}
+
+	tampered := created.Key[:len(created.Key)-1] + "x"
+	if created.Key[len(created.Key)-1] == 'x' {
+		tampered = created.Key[:len(created.Key)-1] + "y"
+	}
+	if _, err := svc.Authenticate(ctx, tampered); !isCode(err, pkgerrors.CodeUnauthorized) {
+		t.Fatalf("expected unauthorized for a wrong secret, got %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, uuid.New(), created.ID); !isCode(err, pkgerrors.CodeNotFound) {
+		t.Fatalf("expected another store's revoke to be not found, got %v", err)
+	}
+	if err := svc.RevokeAPIKey(ctx, storeID, created.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := svc.RevokeAPIKey(ctx, storeID, created.ID); err != nil {
+		t.Fatalf("revoke again: %v", err)
+	}
+	_, err = svc.Authenticate(ctx, created.Key)
+	if !isCode(err, pkgerrors.CodeUnauthorized) || !strings.Contains(err.Error(), "revoked") {
+		t.Fatalf("expected revoked key to be rejected, got %v", err)
+	}
+}
+
+func TestServiceCreateRejectsUnknownScopes(t *testing.T) {
+	svc, err := NewService(newMemoryRepository(), &stubMemberships{})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	input := CreateAPIKeyInput{StoreID: uuid.New(), UserID: uuid.New(), Name: "sync"}
+	if _, err := svc.CreateAPIKey(context.Background(), input); !isCode(err, pkgerrors.CodeValidation) {
+		t.Fatalf("expected validation error without scopes, got %v", err)
+	}
+	input.Scopes = []enums.APIKeyScope{"admin:all"}
+	if _, err := svc.CreateAPIKey(context.Background(), input); !isCode(err, pkgerrors.CodeValidation) {
+		t.Fatalf("expected validation error for unknown scope, got %v", err)
+	}
+}
+
+func TestServiceAuthenticateRequiresCreatorToManageStore(t *testing.T) {
+	storeID := uuid.New()
+	userID := uuid.New()
+	memberships := &stubMemberships{managers: map[uuid.UUID]uuid.UUID{userID: storeID}}
+	svc, err := NewService(newMemoryRepository(), memberships)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	ctx := context.Background()
+	created, err := svc.CreateAPIKey(ctx, CreateAPIKeyInput{
+		StoreID: storeID,
+		UserID:  userID,
+		Name:    "sync",
+		Scopes:  []enums.APIKeyScope{enums.APIKeyScopeOrdersRead},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Key); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	// Removing the creator from the store, or demoting them, disables the key.
+	delete(memberships.managers, userID)
+	if _, err := svc.Authenticate(ctx, created.Key); !isCode(err, pkgerrors.CodeUnauthorized) {
+		t.Fatalf("expected unauthorized once the creator left the store, got %v", err)
+	}
+}
+
+func isCode(err error, code pkgerrors.Code) bool {
+	typed := pkgerrors.As(err)
+	return typed != nil && typed.Code() == code
+}
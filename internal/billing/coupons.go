@@ -0,0 +1,60 @@
+package billing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+)
+
+// CouponRepository persists subscription coupons and their redemptions.
+type CouponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository binds the coupon repository to the provided database.
+func NewCouponRepository(db *gorm.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// FindByCode looks a coupon up by its case-insensitive code, returning nil when none matches.
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*models.SubscriptionCoupon, error) {
+	var coupon models.SubscriptionCoupon
+	if err := r.db.WithContext(ctx).
+		Where("UPPER(code) = ?", strings.ToUpper(strings.TrimSpace(code))).
+		First(&coupon).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// RedeemWithTx claims one redemption and records it. The claim is conditional on the coupon still
+// being active, unexpired and under its cap, so concurrent checkouts cannot overshoot the limit;
+// it reports false when the coupon can no longer be redeemed.
+func (r *CouponRepository) RedeemWithTx(ctx context.Context, tx *gorm.DB, redemption *models.SubscriptionCouponRedemption, now time.Time) (bool, error) {
+	res := tx.WithContext(ctx).
+		Model(&models.SubscriptionCoupon{}).
+		Where("id = ? AND active", redemption.CouponID).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("max_redemptions IS NULL OR redemption_count < max_redemptions").
+		Updates(map[string]any{
+			"redemption_count": gorm.Expr("redemption_count + 1"),
+			"updated_at":       now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	if err := tx.WithContext(ctx).Create(redemption).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository handles billing persistence.
@@ -20,6 +21,7 @@ type Repository interface {
 	ListSubscriptionsByStore(ctx context.Context, storeID uuid.UUID) ([]models.Subscription, error)
 	FindSubscription(ctx context.Context, storeID uuid.UUID) (*models.Subscription, error)
 	FindSubscriptionBySquareID(ctx context.Context, squareSubscriptionID string) (*models.Subscription, error)
+	FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error)
 	CreateBillingPlan(ctx context.Context, plan *models.BillingPlan) error
 	UpdateBillingPlan(ctx context.Context, plan *models.BillingPlan) error
 	ListBillingPlans(ctx context.Context, params ListBillingPlansQuery) ([]models.BillingPlan, error)
@@ -35,6 +37,9 @@ type Repository interface {
 	ListCharges(ctx context.Context, params ListChargesQuery) ([]models.Charge, *pagination.Cursor, error)
 	CreateUsageCharge(ctx context.Context, usage *models.UsageCharge) error
 	ListUsageChargesByStore(ctx context.Context, storeID uuid.UUID) ([]models.UsageCharge, error)
+	UpsertInvoice(ctx context.Context, invoice *models.Invoice) error
+	ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error)
+	FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error)
 }
 
 type repository struct {
@@ -138,6 +143,22 @@ func (r *repository) FindSubscriptionBySquareID(ctx context.Context, squareSubsc
 	return &sub, nil
 }
 
+func (r *repository) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
+	if key == "" {
+		return nil, nil
+	}
+	var sub models.Subscription
+	if err := r.db.WithContext(ctx).
+		Where("store_id = ? AND idempotency_key = ?", storeID, key).
+		First(&sub).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
 func (r *repository) CreateBillingPlan(ctx context.Context, plan *models.BillingPlan) error {
 	return r.db.WithContext(ctx).Create(plan).Error
 }
@@ -320,3 +341,38 @@ func (r *repository) ListUsageChargesByStore(ctx context.Context, storeID uuid.U
 	}
 	return usages, nil
 }
+
+// UpsertInvoice records the invoice. When Square redelivers it or reports a refund only the status and
+// amounts change; the billed period and paid time stay as first recorded.
+func (r *repository) UpsertInvoice(ctx context.Context, invoice *models.Invoice) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "square_invoice_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "amount_cents", "currency", "updated_at"}),
+		}).
+		Create(invoice).Error
+}
+
+func (r *repository) ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	if err := r.db.WithContext(ctx).
+		Where("store_id = ?", storeID).
+		Order("created_at DESC, id DESC").
+		Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *repository) FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.db.WithContext(ctx).
+		Where("store_id = ? AND id = ?", storeID, invoiceID).
+		First(&invoice).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
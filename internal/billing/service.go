@@ -121,6 +121,36 @@ func (s *Service) ListCharges(ctx context.Context, params ListChargesParams) (*L
 	return result, nil
 }
 
+// ListInvoices returns the store's subscription invoices, newest first.
+func (s *Service) ListInvoices(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store id is required")
+	}
+	invoices, err := s.repo.ListInvoicesByStore(ctx, storeID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list invoices")
+	}
+	return invoices, nil
+}
+
+// GetInvoice loads one of the store's invoices for download.
+func (s *Service) GetInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store id is required")
+	}
+	if invoiceID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "invoice id is required")
+	}
+	invoice, err := s.repo.FindInvoice(ctx, storeID, invoiceID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "find invoice")
+	}
+	if invoice == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeNotFound, "invoice not found")
+	}
+	return invoice, nil
+}
+
 func (s *Service) CreateBillingPlan(ctx context.Context, plan *models.BillingPlan) error {
 	if err := s.repo.CreateBillingPlan(ctx, plan); err != nil {
 		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create billing plan")
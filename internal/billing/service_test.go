@@ -17,6 +17,7 @@ type stubRepo struct {
 	listFn                   func(ctx context.Context, params ListChargesQuery) ([]models.Charge, *pagination.Cursor, error)
 	listBillingPlansFn       func(ctx context.Context, params ListBillingPlansQuery) ([]models.BillingPlan, error)
 	findDefaultBillingPlanFn func(ctx context.Context) (*models.BillingPlan, error)
+	invoices                 []models.Invoice
 }
 
 // DeletePaymentMethod implements [Repository].
@@ -43,6 +44,9 @@ func (s *stubRepo) FindSubscription(ctx context.Context, storeID uuid.UUID) (*mo
 func (s *stubRepo) FindSubscriptionBySquareID(ctx context.Context, squareSubscriptionID string) (*models.Subscription, error) {
 	return nil, nil
 }
+func (s *stubRepo) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
+	return nil, nil
+}
 func (s *stubRepo) CreateBillingPlan(ctx context.Context, plan *models.BillingPlan) error {
 	return nil
 }
@@ -95,6 +99,27 @@ func (s *stubRepo) CreateUsageCharge(ctx context.Context, usage *models.UsageCha
 func (s *stubRepo) ListUsageChargesByStore(ctx context.Context, storeID uuid.UUID) ([]models.UsageCharge, error) {
 	return nil, nil
 }
+func (s *stubRepo) UpsertInvoice(ctx context.Context, invoice *models.Invoice) error {
+	s.invoices = append(s.invoices, *invoice)
+	return nil
+}
+func (s *stubRepo) ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	var out []models.Invoice
+	for _, invoice := range s.invoices {
+		if invoice.StoreID == storeID {
+			out = append(out, invoice)
+		}
+	}
+	return out, nil
+}
+func (s *stubRepo) FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	for i := range s.invoices {
+		if s.invoices[i].StoreID == storeID && s.invoices[i].ID == invoiceID {
+			return &s.invoices[i], nil
+		}
+	}
+	return nil, nil
+}
 
 func TestServiceListChargesRequiresStore(t *testing.T) {
 	svc, _ := NewService(ServiceParams{Repo: &stubRepo{}})
@@ -170,6 +195,38 @@ func TestServiceListChargesReturnsCursor(t *testing.T) {
 	}
 }
 
+func TestServiceListInvoicesScopesToStore(t *testing.T) {
+	storeID := uuid.New()
+	repo := &stubRepo{invoices: []models.Invoice{
+		{ID: uuid.New(), StoreID: storeID, SquareInvoiceID: "inv-1", AmountCents: 9900, Status: enums.InvoiceStatusPaid},
+		{ID: uuid.New(), StoreID: uuid.New(), SquareInvoiceID: "inv-2", AmountCents: 4900, Status: enums.InvoiceStatusPaid},
+	}}
+	svc, err := NewService(ServiceParams{Repo: repo})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	invoices, err := svc.ListInvoices(context.Background(), storeID)
+	if err != nil {
+		t.Fatalf("list invoices: %v", err)
+	}
+	if len(invoices) != 1 || invoices[0].SquareInvoiceID != "inv-1" {
+		t.Fatalf("expected only the store's invoice, got %+v", invoices)
+	}
+}
+
+func TestServiceGetInvoiceNotFound(t *testing.T) {
+	svc, err := NewService(ServiceParams{Repo: &stubRepo{}})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	_, err = svc.GetInvoice(context.Background(), uuid.New(), uuid.New())
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
 func TestServiceListBillingPlansForwardsFilters(t *testing.T) {
 	status := enums.PlanStatusActive
 	isDefault := true
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
@@ -74,6 +75,20 @@ func (r *CartRecordRepository) FindByIDAndBuyerStore(ctx context.Context, id, bu
 	return &record, nil
 }
 
+// FindSplitsFrom returns the carts a partial checkout split off the source cart, with their vendor groups.
+func (r *CartRecordRepository) FindSplitsFrom(ctx context.Context, sourceCartID uuid.UUID) ([]models.CartRecord, error) {
+	var records []models.CartRecord
+	err := r.db.WithContext(ctx).
+		Preload("VendorGroups").
+		Where("split_from_cart_id = ?", sourceCartID).
+		Order("created_at DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 // Create inserts the provided cart record.
 func (r *CartRecordRepository) Create(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
 	if record.Status == "" {
@@ -85,6 +100,42 @@ func (r *CartRecordRepository) Create(ctx context.Context, record *models.CartRe
 	return record, nil
 }
 
+// CreateOrReuseActive inserts record as the buyer's active cart, or returns the active cart another request
+// created first. The partial unique index on active carts makes the insert a no-op on conflict; if that cart
+// stops being active before it can be read back, the insert is retried once. The boolean reports whether
+// record was inserted.
+func (r *CartRecordRepository) CreateOrReuseActive(ctx context.Context, record *models.CartRecord) (*models.CartRecord, bool, error) {
+	record.Status = enums.CartStatusActive
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		result := r.db.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "buyer_store_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'active'"}}},
+				DoNothing:   true,
+			}).
+			Create(record)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return record, true, nil
+		}
+
+		existing, err := r.FindActiveByBuyerStore(ctx, record.BuyerStoreID)
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+	}
+	return nil, false, errors.New("active cart changed concurrently")
+}
+
 // Update saves the provided cart record.
 func (r *CartRecordRepository) Update(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
 	if err := r.db.WithContext(ctx).Save(record).Error; err != nil {
@@ -0,0 +1,150 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+func setupCartRecordTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	// A file-backed database lets every pooled connection see the same rows, so concurrent quotes
+	// really insert from separate connections; the busy timeout queues writers instead of failing.
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", filepath.Join(t.TempDir(), "carts.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	statements := []string{
+		`CREATE TABLE cart_records (
+  id TEXT PRIMARY KEY,
+  buyer_store_id TEXT NOT NULL,
+  checkout_group_id TEXT,
+  status TEXT NOT NULL DEFAULT 'active',
+  shipping_address TEXT,
+  billing_address TEXT,
+  tip REAL NOT NULL DEFAULT 0,
+  payment_method TEXT,
+  shipping_line TEXT,
+  currency TEXT NOT NULL DEFAULT 'USD',
+  valid_until DATETIME NOT NULL,
+  subtotal_cents INTEGER NOT NULL DEFAULT 0,
+  discounts_cents INTEGER NOT NULL DEFAULT 0,
+  tax_cents INTEGER NOT NULL DEFAULT 0,
+  total_cents INTEGER NOT NULL DEFAULT 0,
+  quote_hash TEXT NOT NULL DEFAULT '',
+  converted_at DATETIME,
+  split_from_cart_id TEXT,
+  ad_tokens TEXT,
+  created_at DATETIME,
+  updated_at DATETIME
+);`,
+		`CREATE UNIQUE INDEX ux_cart_records_buyer_active ON cart_records (buyer_store_id) WHERE status = 'active';`,
+		`CREATE TABLE cart_items (id TEXT PRIMARY KEY, cart_id TEXT NOT NULL);`,
+		`CREATE TABLE cart_vendor_groups (id TEXT PRIMARY KEY, cart_id TEXT NOT NULL);`,
+	}
+	for _, stmt := range statements {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+func TestCreateOrReuseActiveResolvesConcurrentQuotesToOneCart(t *testing.T) {
+	db := setupCartRecordTestDB(t)
+	repo := NewCartRecordRepository(db)
+	buyerID := uuid.New()
+
+	type outcome struct {
+		id       uuid.UUID
+		inserted bool
+		err      error
+	}
+	results := make([]outcome, 2)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			record, inserted, err := repo.CreateOrReuseActive(context.Background(), &models.CartRecord{
+				BuyerStoreID: buyerID,
+				Currency:     enums.CurrencyUSD,
+				ValidUntil:   time.Now().Add(15 * time.Minute),
+			})
+			if err != nil {
+				results[i] = outcome{err: err}
+				return
+			}
+			results[i] = outcome{id: record.ID, inserted: inserted}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	require.NoError(t, results[0].err)
+	require.NoError(t, results[1].err)
+	require.Equal(t, results[0].id, results[1].id)
+	require.NotEqual(t, results[0].inserted, results[1].inserted, "exactly one quote should insert the cart")
+
+	var count int64
+	require.NoError(t, db.Model(&models.CartRecord{}).
+		Where("buyer_store_id = ? AND status = ?", buyerID, enums.CartStatusActive).
+		Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
+
+func TestCreateOrReuseActiveCreatesAfterConversion(t *testing.T) {
+	db := setupCartRecordTestDB(t)
+	repo := NewCartRecordRepository(db)
+	buyerID := uuid.New()
+
+	first, inserted, err := repo.CreateOrReuseActive(context.Background(), &models.CartRecord{
+		BuyerStoreID: buyerID,
+		ValidUntil:   time.Now().Add(15 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.True(t, inserted)
+	require.NoError(t, repo.UpdateStatus(context.Background(), first.ID, buyerID, enums.CartStatusConverted))
+
+	second, inserted, err := repo.CreateOrReuseActive(context.Background(), &models.CartRecord{
+		BuyerStoreID: buyerID,
+		ValidUntil:   time.Now().Add(15 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.True(t, inserted)
+	require.NotEqual(t, first.ID, second.ID)
+}
+
+func TestFindSplitsFromReturnsOnlyCartsSplitFromSource(t *testing.T) {
+	db := setupCartRecordTestDB(t)
+	repo := NewCartRecordRepository(db)
+	ctx := context.Background()
+	buyerID := uuid.New()
+
+	source, err := repo.Create(ctx, &models.CartRecord{ID: uuid.New(), BuyerStoreID: buyerID, Currency: enums.CurrencyUSD, ValidUntil: time.Now().Add(15 * time.Minute)})
+	require.NoError(t, err)
+	split, err := repo.Create(ctx, &models.CartRecord{ID: uuid.New(), BuyerStoreID: buyerID, Status: enums.CartStatusConverted, SplitFromCartID: &source.ID, Currency: enums.CurrencyUSD, ValidUntil: time.Now().Add(15 * time.Minute)})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.CartRecord{ID: uuid.New(), BuyerStoreID: buyerID, Status: enums.CartStatusConverted, Currency: enums.CurrencyUSD, ValidUntil: time.Now().Add(15 * time.Minute)})
+	require.NoError(t, err)
+
+	splits, err := repo.FindSplitsFrom(ctx, source.ID)
+	require.NoError(t, err)
+	require.Len(t, splits, 1)
+	require.Equal(t, split.ID, splits[0].ID)
+}
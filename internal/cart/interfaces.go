@@ -14,7 +14,9 @@ type CartRepository interface {
 	WithTx(tx *gorm.DB) CartRepository
 	FindActiveByBuyerStore(ctx context.Context, buyerStoreID uuid.UUID) (*models.CartRecord, error)
 	FindByIDAndBuyerStore(ctx context.Context, id, buyerStoreID uuid.UUID) (*models.CartRecord, error)
+	FindSplitsFrom(ctx context.Context, sourceCartID uuid.UUID) ([]models.CartRecord, error)
 	Create(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error)
+	CreateOrReuseActive(ctx context.Context, record *models.CartRecord) (*models.CartRecord, bool, error)
 	Update(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error)
 	ReplaceItems(ctx context.Context, cartID uuid.UUID, items []models.CartItem) error
 	ReplaceVendorGroups(ctx context.Context, cartID uuid.UUID, groups []models.CartVendorGroup) error
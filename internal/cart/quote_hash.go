@@ -0,0 +1,124 @@
+package cart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+type quoteHashItem struct {
+	ProductID               uuid.UUID                    `json:"product_id"`
+	VendorStoreID           uuid.UUID                    `json:"vendor_store_id"`
+	Quantity                int                          `json:"quantity"`
+	UnitPriceCents          int                          `json:"unit_price_cents"`
+	EffectiveUnitPriceCents int                          `json:"effective_unit_price_cents"`
+	LineSubtotalCents       int                          `json:"line_subtotal_cents"`
+	LineDiscountsCents      int                          `json:"line_discounts_cents"`
+	LineTotalCents          int                          `json:"line_total_cents"`
+	AppliedVolumeDiscount   *types.AppliedVolumeDiscount `json:"applied_volume_discount"`
+	Status                  enums.CartItemStatus         `json:"status"`
+	Warnings                types.CartItemWarnings       `json:"warnings"`
+}
+
+type quoteHashGroup struct {
+	VendorStoreID      uuid.UUID                 `json:"vendor_store_id"`
+	Status             enums.VendorGroupStatus   `json:"status"`
+	Warnings           types.VendorGroupWarnings `json:"warnings"`
+	Promo              *types.VendorGroupPromo   `json:"promo"`
+	SubtotalCents      int                       `json:"subtotal_cents"`
+	LineDiscountsCents int                       `json:"line_discounts_cents"`
+	PromoDiscountCents int                       `json:"promo_discount_cents"`
+	DiscountsCents     int                       `json:"discounts_cents"`
+	TaxCents           int                       `json:"tax_cents"`
+	ShippingLine       *types.ShippingLine       `json:"shipping_line"`
+	TotalCents         int                       `json:"total_cents"`
+}
+
+type quoteHashDocument struct {
+	Currency       enums.Currency   `json:"currency"`
+	SubtotalCents  int              `json:"subtotal_cents"`
+	DiscountsCents int              `json:"discounts_cents"`
+	TaxCents       int              `json:"tax_cents"`
+	TotalCents     int              `json:"total_cents"`
+	Items          []quoteHashItem  `json:"items"`
+	VendorGroups   []quoteHashGroup `json:"vendor_groups"`
+}
+
+// computeQuoteHash fingerprints the priced content of a quote: items, vendor groups, and totals.
+// Row ids, timestamps, and the quote expiry are left out, and items and groups are sorted, so
+// re-quoting an unchanged cart yields the same hash.
+func computeQuoteHash(payload cartRecordPayload) string {
+	doc := quoteHashDocument{
+		Currency:       payload.Currency,
+		SubtotalCents:  payload.SubtotalCents,
+		DiscountsCents: payload.DiscountsCents,
+		TaxCents:       payload.TaxCents,
+		TotalCents:     payload.TotalCents,
+		Items:          make([]quoteHashItem, 0, len(payload.Items)),
+		VendorGroups:   make([]quoteHashGroup, 0, len(payload.VendorGroups)),
+	}
+
+	for _, item := range payload.Items {
+		doc.Items = append(doc.Items, quoteHashItemFrom(item))
+	}
+	sort.SliceStable(doc.Items, func(i, j int) bool {
+		a, b := doc.Items[i], doc.Items[j]
+		if a.VendorStoreID != b.VendorStoreID {
+			return a.VendorStoreID.String() < b.VendorStoreID.String()
+		}
+		if a.ProductID != b.ProductID {
+			return a.ProductID.String() < b.ProductID.String()
+		}
+		return a.Quantity < b.Quantity
+	})
+
+	for _, group := range payload.VendorGroups {
+		doc.VendorGroups = append(doc.VendorGroups, quoteHashGroupFrom(group))
+	}
+	sort.SliceStable(doc.VendorGroups, func(i, j int) bool {
+		return doc.VendorGroups[i].VendorStoreID.String() < doc.VendorGroups[j].VendorStoreID.String()
+	})
+
+	// The document only holds plain values, so marshaling cannot fail.
+	encoded, _ := json.Marshal(doc)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+func quoteHashItemFrom(item models.CartItem) quoteHashItem {
+	return quoteHashItem{
+		ProductID:               item.ProductID,
+		VendorStoreID:           item.VendorStoreID,
+		Quantity:                item.Quantity,
+		UnitPriceCents:          item.UnitPriceCents,
+		EffectiveUnitPriceCents: item.EffectiveUnitPriceCents,
+		LineSubtotalCents:       item.LineSubtotalCents,
+		LineDiscountsCents:      item.LineDiscountsCents,
+		LineTotalCents:          item.LineTotalCents,
+		AppliedVolumeDiscount:   item.AppliedVolumeDiscount,
+		Status:                  item.Status,
+		Warnings:                item.Warnings,
+	}
+}
+
+func quoteHashGroupFrom(group models.CartVendorGroup) quoteHashGroup {
+	return quoteHashGroup{
+		VendorStoreID:      group.VendorStoreID,
+		Status:             group.Status,
+		Warnings:           group.Warnings,
+		Promo:              group.Promo,
+		SubtotalCents:      group.SubtotalCents,
+		LineDiscountsCents: group.LineDiscountsCents,
+		PromoDiscountCents: group.PromoDiscountCents,
+		DiscountsCents:     group.DiscountsCents,
+		TaxCents:           group.TaxCents,
+		ShippingLine:       group.ShippingLine,
+		TotalCents:         group.TotalCents,
+	}
+}
@@ -1,12 +1,22 @@
 package cart
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
 
 // QuoteCartInput represents the server-driven quote intent derived from cartdto.QuoteCartRequest.
 type QuoteCartInput struct {
 	Items        []QuoteCartItem
 	VendorPromos []QuoteVendorPromo
 	AdTokens     []string
+	// ShippingAddress is where the buyer expects delivery; delivery zones are checked against it. It
+	// defaults to the buyer store address.
+	ShippingAddress *types.Address
+	// ShippingLine names the shipping option the buyer wants; only its code is read. Without one each
+	// vendor group is quoted its cheapest line.
+	ShippingLine *types.ShippingLine
 }
 
 // QuoteCartItem captures each intent line from the client.
@@ -4,14 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"strings"
 	"time"
 
+	checkouthelpers "github.com/angelmondragon/packfinderz-backend/internal/checkout/helpers"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -44,11 +45,18 @@ type quotePipelineResult struct {
 	ItemsByVendor  map[uuid.UUID][]*quotePipelineItem
 	VendorWarnings map[uuid.UUID]types.VendorGroupWarnings
 	VendorPromos   map[uuid.UUID]*types.VendorGroupPromo
+	PromoStacks    map[uuid.UUID]bool
+	OutOfZone      map[uuid.UUID]bool
 }
 
-const invalidPromoWarningMessage = "Promo code is not valid for this vendor"
+const (
+	invalidPromoWarningMessage  = "Promo code is not valid for this vendor"
+	outOfZoneWarningMessage     = "Vendor does not deliver to this address"
+	excludedStateWarningMessage = "product is not available in the buyer's state"
+	promoNotCombinedMessage     = "Promo code and volume discounts do not combine; the larger discount was applied"
+)
 
-func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, input QuoteCartInput, previousPrices map[string]int) (*quotePipelineResult, error) {
+func (s *service) preprocessQuoteInput(ctx context.Context, buyerStoreID uuid.UUID, buyerState string, shippingAddress types.Address, currency enums.Currency, input QuoteCartInput, previousPrices map[string]int) (*quotePipelineResult, error) {
 	vendorIDs := map[uuid.UUID]struct{}{}
 	for _, payload := range input.Items {
 		if payload.Quantity <= 0 {
@@ -78,6 +86,18 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 	now := time.Now()
 	vendorWarnings := make(map[uuid.UUID]types.VendorGroupWarnings, len(vendorIDs))
 	vendorPromos := make(map[uuid.UUID]*types.VendorGroupPromo, len(vendorIDs))
+	promoStacks := make(map[uuid.UUID]bool, len(vendorIDs))
+	outOfZone := make(map[uuid.UUID]bool, len(vendorIDs))
+	for vendorID, vendor := range vendorCache {
+		if checkouthelpers.InDeliveryZone(vendor, shippingAddress) {
+			continue
+		}
+		outOfZone[vendorID] = true
+		vendorWarnings[vendorID] = append(vendorWarnings[vendorID], types.VendorGroupWarning{
+			Type:    enums.VendorGroupWarningTypeOutOfZone,
+			Message: outOfZoneWarningMessage,
+		})
+	}
 	for vendorID, promo := range promoRequests {
 		promoRecord, err := s.promo.GetVendorPromo(ctx, vendorID, promo.Code)
 		if err != nil {
@@ -100,6 +120,7 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 			Code:        promoRecord.Code,
 			AmountCents: amount,
 		}
+		promoStacks[vendorID] = promoRecord.StacksWithVolumeDiscounts
 	}
 
 	result := &quotePipelineResult{
@@ -107,6 +128,8 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 		ItemsByVendor:  make(map[uuid.UUID][]*quotePipelineItem, len(vendorIDs)),
 		VendorWarnings: vendorWarnings,
 		VendorPromos:   vendorPromos,
+		PromoStacks:    promoStacks,
+		OutOfZone:      outOfZone,
 	}
 
 	for _, payload := range input.Items {
@@ -122,6 +145,13 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 			}
 			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
 		}
+		if productCurrency := currencyOrDefault(product.Currency); productCurrency != currency {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "cart items must share the buyer store currency").WithDetails(map[string]any{
+				"product_id":       product.ID,
+				"product_currency": productCurrency,
+				"cart_currency":    currency,
+			})
+		}
 
 		vendorMatch := product.StoreID == payload.VendorStoreID
 		maxQty := productMaxQty(product)
@@ -131,6 +161,9 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 		if !vendorMatch {
 			status = enums.CartItemStatusInvalid
 			warnings = appendWarning(warnings, enums.CartItemWarningTypeVendorMismatch, "product does not belong to the requested vendor")
+		} else if product.IsExcludedIn(buyerState) {
+			status = enums.CartItemStatusNotAvailable
+			warnings = appendWarning(warnings, enums.CartItemWarningTypeNotAvailable, excludedStateWarningMessage)
 		} else if !product.IsActive || !hasSufficientInventory(product, normalizedQty) {
 			status = enums.CartItemStatusNotAvailable
 			reason := "product is not active"
@@ -144,29 +177,30 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 			warnings = appendWarning(warnings, enums.CartItemWarningTypeNotAvailable, reason)
 		}
 
-		selectedTier := selectVolumeDiscount(normalizedQty, product.VolumeDiscounts)
+		if outOfZone[payload.VendorStoreID] && status == enums.CartItemStatusOK {
+			status = enums.CartItemStatusInvalid
+		}
+
+		basePriceCents, err := s.buyerBasePrice(ctx, product, buyerStoreID)
+		if err != nil {
+			return nil, err
+		}
 
-		baseUnitPriceCents, _, effectiveUnitPriceCents, applied :=
-			resolvePricing(product, normalizedQty, selectedTier)
+		selectedTier := selectVolumeDiscount(normalizedQty, product.VolumeDiscounts, vendorStore.VolumeDiscountStrategy)
+
+		baseUnitPriceCents, lineDiscountsCents, effectiveUnitPriceCents, applied :=
+			resolvePricing(basePriceCents, normalizedQty, selectedTier, s.rounding)
 
 		lineSubtotalCents := baseUnitPriceCents * normalizedQty
 		if lineSubtotalCents < 0 {
 			lineSubtotalCents = 0
 		}
 
-		lineTotalCents := effectiveUnitPriceCents * normalizedQty
+		lineTotalCents := lineSubtotalCents - lineDiscountsCents
 		if lineTotalCents < 0 {
 			lineTotalCents = 0
 		}
 
-		lineDiscountsCents := lineSubtotalCents - lineTotalCents
-		if lineDiscountsCents < 0 {
-			lineDiscountsCents = 0
-		}
-		if applied != nil {
-			applied.AmountCents = lineDiscountsCents
-		}
-
 		key := priceKey(product.ID, payload.VendorStoreID)
 		if prevPrice, ok := previousPrices[key]; ok && prevPrice != baseUnitPriceCents {
 			warnings = appendWarning(
@@ -207,9 +241,73 @@ func (s *service) preprocessQuoteInput(ctx context.Context, buyerState string, i
 		result.ItemsByVendor[payload.VendorStoreID] = append(result.ItemsByVendor[payload.VendorStoreID], item)
 	}
 
+	resolvePromoStacking(result)
+
 	return result, nil
 }
 
+// resolvePromoStacking keeps a vendor promo and volume tier discounts from both applying unless
+// the promo allows stacking. The larger of the two discounts is kept: either the promo is dropped
+// or the vendor's lines fall back to their undiscounted prices. Ties keep the volume discounts.
+func resolvePromoStacking(pipeline *quotePipelineResult) {
+	for vendorID, promo := range pipeline.VendorPromos {
+		if promo == nil || promo.AmountCents <= 0 || pipeline.PromoStacks[vendorID] {
+			continue
+		}
+
+		subtotal := 0
+		lineDiscounts := 0
+		for _, item := range pipeline.ItemsByVendor[vendorID] {
+			if item.Status != enums.CartItemStatusOK {
+				continue
+			}
+			subtotal += item.LineSubtotalCents
+			lineDiscounts += item.LineDiscountsCents
+		}
+		if lineDiscounts <= 0 {
+			continue
+		}
+
+		promoDiscount := promo.AmountCents
+		if promoDiscount > subtotal {
+			promoDiscount = subtotal
+		}
+
+		if promoDiscount > lineDiscounts {
+			for _, item := range pipeline.ItemsByVendor[vendorID] {
+				item.EffectiveUnitPriceCents = item.UnitPriceCents
+				item.LineDiscountsCents = 0
+				item.LineTotalCents = item.LineSubtotalCents
+				item.AppliedVolumeDiscount = nil
+				item.SelectedTier = nil
+			}
+		} else {
+			delete(pipeline.VendorPromos, vendorID)
+		}
+
+		pipeline.VendorWarnings[vendorID] = append(pipeline.VendorWarnings[vendorID], types.VendorGroupWarning{
+			Type:    enums.VendorGroupWarningTypePromoNotCombined,
+			Message: promoNotCombinedMessage,
+		})
+	}
+}
+
+// buyerBasePrice returns the vendor's price override for the buyer when one exists, falling back to
+// the product base price.
+func (s *service) buyerBasePrice(ctx context.Context, product *models.Product, buyerStoreID uuid.UUID) (int, error) {
+	override, err := s.productRepo.FindBuyerPrice(ctx, product.ID, buyerStoreID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return product.PriceCents, nil
+		}
+		return 0, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load buyer price")
+	}
+	if override == nil || override.StoreID != product.StoreID {
+		return product.PriceCents, nil
+	}
+	return override.PriceCents, nil
+}
+
 func firstMediaURL(product *models.Product) *string {
 	if product == nil || len(product.Media) == 0 || product.Media[0].URL == nil {
 		return nil
@@ -259,28 +357,33 @@ func hasSufficientInventory(product *models.Product, qty int) bool {
 }
 
 // productMaxQty returns the configured product max quantity when available.
+// A zero value means the vendor has not capped the quantity.
 func productMaxQty(product *models.Product) *int {
-	// TODO: return actual max quantity once the field exists on products or inventory.
-	return nil
+	if product == nil || product.MaxQty <= 0 {
+		return nil
+	}
+	maxQty := product.MaxQty
+	return &maxQty
 }
 
 func priceKey(productID, vendorID uuid.UUID) string {
 	return fmt.Sprintf("%s:%s", productID, vendorID)
 }
 
+// resolvePricing applies the volume tier to a line priced at base, which is the product price or
+// the buyer's override. The discount is rounded once on the line subtotal so line totals stay
+// exact; the effective unit price is rounded separately for display and may not multiply back to
+// the line total.
 func resolvePricing(
-	product *models.Product,
+	base int,
 	qty int,
 	tier *models.ProductVolumeDiscount,
+	rounding money.RoundingMode,
 ) (baseUnitPriceCents int, lineDiscountsCents int, effectiveUnitPriceCents int, applied *types.AppliedVolumeDiscount) {
-	if product == nil {
-		return 0, 0, 0, nil
-	}
 	if qty < 0 {
 		qty = 0
 	}
 
-	base := product.PriceCents
 	if base < 0 {
 		base = 0
 	}
@@ -290,7 +393,7 @@ func resolvePricing(
 	var appliedDiscount *types.AppliedVolumeDiscount
 
 	if tier != nil && tier.DiscountPercent > 0 {
-		discountPerUnit := int(math.Round(float64(base) * float64(tier.DiscountPercent) / 100.0))
+		discountPerUnit := rounding.Percent(base, tier.DiscountPercent)
 		if discountPerUnit < 0 {
 			discountPerUnit = 0
 		}
@@ -303,10 +406,13 @@ func resolvePricing(
 			effective = 0
 		}
 
-		lineDiscounts = discountPerUnit * qty
+		lineDiscounts = rounding.Percent(base*qty, tier.DiscountPercent)
 		if lineDiscounts < 0 {
 			lineDiscounts = 0
 		}
+		if lineDiscounts > base*qty {
+			lineDiscounts = base * qty
+		}
 
 		appliedDiscount = &types.AppliedVolumeDiscount{
 			Label:       fmt.Sprintf("volume tier %d+", tier.MinQty),
@@ -349,7 +455,7 @@ func aggregateVendorGroups(pipeline *quotePipelineResult) []models.CartVendorGro
 
 		if hasOK {
 			status = enums.VendorGroupStatusOK
-		} else {
+		} else if !pipeline.OutOfZone[vendorID] {
 			warnings = append(warnings, types.VendorGroupWarning{
 				Type:    enums.VendorGroupWarningTypeVendorInvalid,
 				Message: "no valid items for vendor",
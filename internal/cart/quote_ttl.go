@@ -0,0 +1,61 @@
+package cart
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+const defaultQuoteTTL = 15 * time.Minute
+
+// QuoteTTLPolicy decides how long a cart quote stays valid before checkout rejects it.
+type QuoteTTLPolicy struct {
+	Default    time.Duration
+	Categories map[enums.ProductCategory]time.Duration
+}
+
+// NewQuoteTTLPolicy builds the policy from config, rejecting unknown categories and non-positive windows.
+func NewQuoteTTLPolicy(cfg config.CartConfig) (QuoteTTLPolicy, error) {
+	if cfg.QuoteTTL <= 0 {
+		return QuoteTTLPolicy{}, fmt.Errorf("cart quote ttl must be positive")
+	}
+	policy := QuoteTTLPolicy{
+		Default:    cfg.QuoteTTL,
+		Categories: make(map[enums.ProductCategory]time.Duration, len(cfg.CategoryQuoteTTLs)),
+	}
+	for raw, ttl := range cfg.CategoryQuoteTTLs {
+		category, err := enums.ParseProductCategory(strings.ToLower(strings.TrimSpace(raw)))
+		if err != nil {
+			return QuoteTTLPolicy{}, fmt.Errorf("cart category quote ttl: %w", err)
+		}
+		if ttl <= 0 {
+			return QuoteTTLPolicy{}, fmt.Errorf("cart quote ttl for %s must be positive", category)
+		}
+		policy.Categories[category] = ttl
+	}
+	return policy, nil
+}
+
+// TTLFor returns the shortest window that applies to any quoted product, so one volatile item
+// shortens the whole quote.
+func (p QuoteTTLPolicy) TTLFor(pipeline *quotePipelineResult) time.Duration {
+	ttl := p.Default
+	if ttl <= 0 {
+		ttl = defaultQuoteTTL
+	}
+	if pipeline == nil || len(p.Categories) == 0 {
+		return ttl
+	}
+	for _, item := range pipeline.Items {
+		if item.Product == nil {
+			continue
+		}
+		if categoryTTL, ok := p.Categories[item.Product.Category]; ok && categoryTTL < ttl {
+			ttl = categoryTTL
+		}
+	}
+	return ttl
+}
@@ -0,0 +1,37 @@
+package cart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+func TestNewQuoteTTLPolicy(t *testing.T) {
+	policy, err := NewQuoteTTLPolicy(config.CartConfig{
+		QuoteTTL:          20 * time.Minute,
+		CategoryQuoteTTLs: map[string]time.Duration{" Flower ": 5 * time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Default != 20*time.Minute {
+		t.Fatalf("expected default 20m, got %s", policy.Default)
+	}
+	if got := policy.Categories[enums.ProductCategoryFlower]; got != 5*time.Minute {
+		t.Fatalf("expected flower override 5m, got %s", got)
+	}
+
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		if _, err := NewQuoteTTLPolicy(config.CartConfig{QuoteTTL: ttl}); err == nil {
+			t.Fatalf("expected default ttl %s to be rejected", ttl)
+		}
+	}
+	if _, err := NewQuoteTTLPolicy(config.CartConfig{QuoteTTL: time.Minute, CategoryQuoteTTLs: map[string]time.Duration{"snacks": time.Minute}}); err == nil {
+		t.Fatal("expected unknown category to be rejected")
+	}
+	if _, err := NewQuoteTTLPolicy(config.CartConfig{QuoteTTL: time.Minute, CategoryQuoteTTLs: map[string]time.Duration{"flower": 0}}); err == nil {
+		t.Fatal("expected non-positive category ttl to be rejected")
+	}
+}
@@ -45,6 +45,11 @@ func (r *Repository) Create(ctx context.Context, record *models.CartRecord) (*mo
 	return r.cartRepo.Create(ctx, record)
 }
 
+// CreateOrReuseActive inserts the buyer's active cart or returns the one created concurrently.
+func (r *Repository) CreateOrReuseActive(ctx context.Context, record *models.CartRecord) (*models.CartRecord, bool, error) {
+	return r.cartRepo.CreateOrReuseActive(ctx, record)
+}
+
 // Update saves the provided cart record.
 func (r *Repository) Update(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
 	return r.cartRepo.Update(ctx, record)
@@ -60,6 +65,11 @@ func (r *Repository) FindByIDAndBuyerStore(ctx context.Context, id, buyerStoreID
 	return r.cartRepo.FindByIDAndBuyerStore(ctx, id, buyerStoreID)
 }
 
+// FindSplitsFrom returns the carts a partial checkout split off the source cart.
+func (r *Repository) FindSplitsFrom(ctx context.Context, sourceCartID uuid.UUID) ([]models.CartRecord, error) {
+	return r.cartRepo.FindSplitsFrom(ctx, sourceCartID)
+}
+
 // UpdateStatus updates the status of a CartRecord owned by the buyer store.
 func (r *Repository) UpdateStatus(ctx context.Context, id, buyerStoreID uuid.UUID, status enums.CartStatus) error {
 	return r.cartRepo.UpdateStatus(ctx, id, buyerStoreID, status)
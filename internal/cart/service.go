@@ -14,6 +14,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -30,6 +31,7 @@ type storeLoader interface {
 
 type productLoader interface {
 	GetProductDetail(ctx context.Context, id uuid.UUID) (*models.Product, *product.VendorSummary, error)
+	FindBuyerPrice(ctx context.Context, productID, buyerStoreID uuid.UUID) (*models.ProductBuyerPrice, error)
 }
 
 // Service exposes cart persistence operations.
@@ -45,10 +47,44 @@ type service struct {
 	productRepo productLoader
 	promo       promoLoader
 	tokenParser token.Parser
+	quoteTTL    QuoteTTLPolicy
+	rounding    money.RoundingMode
+	tax         TaxCalculator
+	shipping    ShippingQuoter
+}
+
+// TaxCalculator prices the sales tax owed on a vendor group's merchandise total. Checkout's
+// calculator satisfies it, so a quote shows the tax checkout will charge.
+type TaxCalculator interface {
+	TaxCents(buyer *stores.StoreDTO, taxableCents int, now time.Time) int
+}
+
+// ShippingQuoter prices the shipping line for a vendor group. Checkout's quoter satisfies it, and
+// checkout charges the quoted line unless the buyer changes the destination or line.
+type ShippingQuoter interface {
+	QuoteShipping(ctx context.Context, vendor *stores.StoreDTO, destination types.Address, subtotalCents int, requested *types.ShippingLine) (*types.ShippingLine, error)
+}
+
+// ServiceOption customizes the cart service.
+type ServiceOption func(*service)
+
+// WithTaxCalculator prices tax on each vendor group of a quote. Without one quotes carry no tax.
+func WithTaxCalculator(calc TaxCalculator) ServiceOption {
+	return func(s *service) {
+		s.tax = calc
+	}
+}
+
+// WithShippingQuoter prices shipping on each vendor group of a quote. Without one quotes carry no
+// shipping and checkout rates it.
+func WithShippingQuoter(quoter ShippingQuoter) ServiceOption {
+	return func(s *service) {
+		s.shipping = quoter
+	}
 }
 
 // NewService builds a cart service backed by the provided stack.
-func NewService(repo CartRepository, tx txRunner, store storeLoader, productRepo productLoader, promo promoLoader, tokenParser token.Parser) (Service, error) {
+func NewService(repo CartRepository, tx txRunner, store storeLoader, productRepo productLoader, promo promoLoader, tokenParser token.Parser, quoteTTL QuoteTTLPolicy, rounding money.RoundingMode, opts ...ServiceOption) (Service, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("cart repository required")
 	}
@@ -67,14 +103,22 @@ func NewService(repo CartRepository, tx txRunner, store storeLoader, productRepo
 	if tokenParser == nil {
 		return nil, fmt.Errorf("token parser required")
 	}
-	return &service{
+	svc := &service{
 		repo:        repo,
 		tx:          tx,
 		store:       store,
 		productRepo: productRepo,
 		promo:       promo,
 		tokenParser: tokenParser,
-	}, nil
+		quoteTTL:    quoteTTL,
+		rounding:    rounding,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(svc)
+		}
+	}
+	return svc, nil
 }
 func (s *service) QuoteCart(ctx context.Context, buyerStoreID uuid.UUID, input QuoteCartInput) (*models.CartRecord, error) {
 	if buyerStoreID == uuid.Nil {
@@ -94,7 +138,14 @@ func (s *service) QuoteCart(ctx context.Context, buyerStoreID uuid.UUID, input Q
 		return nil, err
 	}
 
-	pipeline, err := s.preprocessQuoteInput(ctx, buyerState, input, existingPrices)
+	currency := currencyOrDefault(store.Currency)
+
+	shippingAddress := store.Address
+	if input.ShippingAddress != nil {
+		shippingAddress = *input.ShippingAddress
+	}
+
+	pipeline, err := s.preprocessQuoteInput(ctx, buyerStoreID, buyerState, shippingAddress, currency, input, existingPrices)
 	if err != nil {
 		return nil, err
 	}
@@ -105,14 +156,20 @@ func (s *service) QuoteCart(ctx context.Context, buyerStoreID uuid.UUID, input Q
 	}
 
 	vendorGroups := aggregateVendorGroups(pipeline)
+	s.applyTax(store, vendorGroups, time.Now())
+	if err := s.applyShipping(ctx, pipeline, vendorGroups, shippingAddress, input.ShippingLine); err != nil {
+		return nil, err
+	}
 
 	subtotalCents := 0
 	discountsCents := 0
+	taxCents := 0
 	totalCents := 0
 
 	for _, group := range vendorGroups {
 		subtotalCents += group.SubtotalCents
 		discountsCents += group.DiscountsCents
+		taxCents += group.TaxCents
 		totalCents += group.TotalCents
 	}
 
@@ -129,27 +186,79 @@ func (s *service) QuoteCart(ctx context.Context, buyerStoreID uuid.UUID, input Q
 		totalCents = 0
 	}
 
-	shippingAddress := store.Address
-	validUntil := time.Now().Add(15 * time.Minute)
-	currency := enums.CurrencyUSD
+	validUntil := time.Now().Add(s.quoteTTL.TTLFor(pipeline))
 
 	adTokens := s.normalizeAdTokens(input.AdTokens, buyerStoreID)
 
 	payload := cartRecordPayload{
 		ShippingAddress: &shippingAddress,
+		ShippingLine:    input.ShippingLine,
 		Currency:        currency,
 		ValidUntil:      validUntil,
 		DiscountsCents:  discountsCents,
 		SubtotalCents:   subtotalCents,
+		TaxCents:        taxCents,
 		TotalCents:      totalCents,
 		AdTokens:        adTokens,
 		Items:           items,
 		VendorGroups:    vendorGroups,
 	}
+	payload.QuoteHash = computeQuoteHash(payload)
 
 	return s.persistQuote(ctx, buyerStoreID, payload)
 }
 
+// applyTax prices tax on each valid vendor group and adds it to the group total. It taxes the same
+// merchandise total checkout does (after line discounts), and the calculator skips buyers holding a
+// verified, unexpired exemption, so quote and checkout agree on tax.
+func (s *service) applyTax(buyer *stores.StoreDTO, groups []models.CartVendorGroup, now time.Time) {
+	if s.tax == nil {
+		return
+	}
+	for i := range groups {
+		group := &groups[i]
+		if group.Status != enums.VendorGroupStatusOK {
+			continue
+		}
+		group.TaxCents = s.tax.TaxCents(buyer, group.SubtotalCents-group.LineDiscountsCents, now)
+		group.TotalCents += group.TaxCents
+	}
+}
+
+// applyShipping prices the shipping line of each valid vendor group and adds it to the group total,
+// using the same quoter and merchandise subtotal checkout would.
+func (s *service) applyShipping(ctx context.Context, pipeline *quotePipelineResult, groups []models.CartVendorGroup, destination types.Address, requested *types.ShippingLine) error {
+	if s.shipping == nil {
+		return nil
+	}
+	for i := range groups {
+		group := &groups[i]
+		if group.Status != enums.VendorGroupStatusOK {
+			continue
+		}
+		vendor := pipelineVendor(pipeline.ItemsByVendor[group.VendorStoreID])
+		if vendor == nil {
+			continue
+		}
+		line, err := s.shipping.QuoteShipping(ctx, vendor, destination, group.SubtotalCents, requested)
+		if err != nil {
+			return err
+		}
+		group.ShippingLine = line
+		group.TotalCents += line.PriceCents
+	}
+	return nil
+}
+
+func pipelineVendor(items []*quotePipelineItem) *stores.StoreDTO {
+	for _, item := range items {
+		if item.VendorStore != nil {
+			return item.VendorStore
+		}
+	}
+	return nil
+}
+
 func (s *service) validateBuyerStore(ctx context.Context, buyerStoreID uuid.UUID) (*stores.StoreDTO, string, error) {
 	store, err := s.store.GetByID(ctx, buyerStoreID)
 	if err != nil {
@@ -225,14 +334,17 @@ func buildCartItemFromPipeline(item *quotePipelineItem) models.CartItem {
 
 type cartRecordPayload struct {
 	ShippingAddress *types.Address
+	ShippingLine    *types.ShippingLine
 	Currency        enums.Currency
 	ValidUntil      time.Time
 	DiscountsCents  int
 	SubtotalCents   int
+	TaxCents        int
 	TotalCents      int
 	AdTokens        []string
 	Items           []models.CartItem
 	VendorGroups    []models.CartVendorGroup
+	QuoteHash       string
 }
 
 func (s *service) persistQuote(ctx context.Context, buyerStoreID uuid.UUID, payload cartRecordPayload) (*models.CartRecord, error) {
@@ -287,76 +399,56 @@ func (s *service) persistQuote(ctx context.Context, buyerStoreID uuid.UUID, payl
 			)
 		}
 
-		var targetID uuid.UUID
-		if record != nil && record.ID != uuid.Nil {
-			fmt.Printf("[cart.persistQuote.tx] update_cart start cart_id=%s buyer_store_id=%s\n", record.ID.String(), buyerStoreID.String())
-			record.ShippingAddress = payload.ShippingAddress
-			record.ValidUntil = payload.ValidUntil
-			record.Currency = currency
-			record.DiscountsCents = payload.DiscountsCents
-			record.SubtotalCents = payload.SubtotalCents
-			record.TotalCents = payload.TotalCents
-			record.AdTokens = pq.StringArray(payload.AdTokens)
-
-			if _, err := txRepo.Update(ctx, record); err != nil {
-				fmt.Printf("[cart.persistQuote.tx] update_cart error=%v cart_id=%s\n", err, record.ID.String())
-				return err
-			}
-			fmt.Printf("[cart.persistQuote.tx] update_cart ok cart_id=%s\n", record.ID.String())
-			targetID = record.ID
-		} else {
+		if record == nil || record.ID == uuid.Nil {
 			fmt.Printf("[cart.persistQuote.tx] create_cart start buyer_store_id=%s\n", buyerStoreID.String())
-			record = &models.CartRecord{
+			candidate := &models.CartRecord{
 				BuyerStoreID:    buyerStoreID,
 				ShippingAddress: payload.ShippingAddress,
+				ShippingLine:    payload.ShippingLine,
 				Currency:        currency,
 				ValidUntil:      payload.ValidUntil,
 				SubtotalCents:   payload.SubtotalCents,
 				DiscountsCents:  payload.DiscountsCents,
+				TaxCents:        payload.TaxCents,
 				TotalCents:      payload.TotalCents,
+				QuoteHash:       payload.QuoteHash,
 				AdTokens:        pq.StringArray(payload.AdTokens),
 			}
-			created, err := txRepo.Create(ctx, record)
+			created, inserted, err := txRepo.CreateOrReuseActive(ctx, candidate)
 			if err != nil {
 				fmt.Printf("[cart.persistQuote.tx] create_cart error=%v buyer_store_id=%s\n", err, buyerStoreID.String())
 				return err
 			}
-			targetID = created.ID
-			fmt.Printf("[cart.persistQuote.tx] create_cart ok cart_id=%s buyer_store_id=%s\n", targetID.String(), buyerStoreID.String())
-		}
-
-		for i := range payload.Items {
-			payload.Items[i].CartID = targetID
-		}
-		for i := range payload.VendorGroups {
-			payload.VendorGroups[i].CartID = targetID
-		}
-
-		fmt.Printf("[cart.persistQuote.tx] replace_items start cart_id=%s items=%d\n", targetID.String(), len(payload.Items))
-		if err := txRepo.ReplaceItems(ctx, targetID, payload.Items); err != nil {
-			fmt.Printf("[cart.persistQuote.tx] replace_items error=%v cart_id=%s items=%d\n", err, targetID.String(), len(payload.Items))
-			return err
-		}
-		fmt.Printf("[cart.persistQuote.tx] replace_items ok cart_id=%s\n", targetID.String())
-
-		fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups start cart_id=%s vendor_groups=%d\n", targetID.String(), len(payload.VendorGroups))
-		if err := txRepo.ReplaceVendorGroups(ctx, targetID, payload.VendorGroups); err != nil {
-			fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups error=%v cart_id=%s vendor_groups=%d\n", err, targetID.String(), len(payload.VendorGroups))
-			return err
+			if inserted {
+				fmt.Printf("[cart.persistQuote.tx] create_cart ok cart_id=%s buyer_store_id=%s\n", created.ID.String(), buyerStoreID.String())
+				saved, err = s.replaceQuoteContents(ctx, txRepo, created.ID, buyerStoreID, payload)
+				return err
+			}
+			// A concurrent quote created the active cart first; overwrite it with this quote.
+			fmt.Printf("[cart.persistQuote.tx] create_cart reused cart_id=%s buyer_store_id=%s\n", created.ID.String(), buyerStoreID.String())
+			record = created
 		}
-		fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups ok cart_id=%s\n", targetID.String())
 
-		fmt.Printf("[cart.persistQuote.tx] find_saved start cart_id=%s buyer_store_id=%s\n", targetID.String(), buyerStoreID.String())
-		saved, err = txRepo.FindByIDAndBuyerStore(ctx, targetID, buyerStoreID)
-		if err != nil {
-			fmt.Printf("[cart.persistQuote.tx] find_saved error=%v cart_id=%s buyer_store_id=%s\n", err, targetID.String(), buyerStoreID.String())
+		fmt.Printf("[cart.persistQuote.tx] update_cart start cart_id=%s buyer_store_id=%s\n", record.ID.String(), buyerStoreID.String())
+		record.ShippingAddress = payload.ShippingAddress
+		record.ShippingLine = payload.ShippingLine
+		record.ValidUntil = payload.ValidUntil
+		record.Currency = currency
+		record.DiscountsCents = payload.DiscountsCents
+		record.SubtotalCents = payload.SubtotalCents
+		record.TaxCents = payload.TaxCents
+		record.TotalCents = payload.TotalCents
+		record.QuoteHash = payload.QuoteHash
+		record.AdTokens = pq.StringArray(payload.AdTokens)
+
+		if _, err := txRepo.Update(ctx, record); err != nil {
+			fmt.Printf("[cart.persistQuote.tx] update_cart error=%v cart_id=%s\n", err, record.ID.String())
 			return err
 		}
-		fmt.Printf("[cart.persistQuote.tx] find_saved ok cart_id=%s items=%d vendor_groups=%d\n",
-			saved.ID.String(), len(saved.Items), len(saved.VendorGroups),
-		)
+		fmt.Printf("[cart.persistQuote.tx] update_cart ok cart_id=%s\n", record.ID.String())
 
-		return nil
+		saved, err = s.replaceQuoteContents(ctx, txRepo, record.ID, buyerStoreID, payload)
+		return err
 	}); err != nil {
 		fmt.Printf("[cart.persistQuote] tx failed error=%v buyer_store_id=%s duration_ms=%d\n",
 			err, buyerStoreID.String(), time.Since(start).Milliseconds(),
@@ -370,6 +462,41 @@ func (s *service) persistQuote(ctx context.Context, buyerStoreID uuid.UUID, payl
 	return saved, nil
 }
 
+// replaceQuoteContents swaps in the quoted items and vendor groups for the cart and reloads it.
+func (s *service) replaceQuoteContents(ctx context.Context, txRepo CartRepository, cartID, buyerStoreID uuid.UUID, payload cartRecordPayload) (*models.CartRecord, error) {
+	for i := range payload.Items {
+		payload.Items[i].CartID = cartID
+	}
+	for i := range payload.VendorGroups {
+		payload.VendorGroups[i].CartID = cartID
+	}
+
+	fmt.Printf("[cart.persistQuote.tx] replace_items start cart_id=%s items=%d\n", cartID.String(), len(payload.Items))
+	if err := txRepo.ReplaceItems(ctx, cartID, payload.Items); err != nil {
+		fmt.Printf("[cart.persistQuote.tx] replace_items error=%v cart_id=%s items=%d\n", err, cartID.String(), len(payload.Items))
+		return nil, err
+	}
+	fmt.Printf("[cart.persistQuote.tx] replace_items ok cart_id=%s\n", cartID.String())
+
+	fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups start cart_id=%s vendor_groups=%d\n", cartID.String(), len(payload.VendorGroups))
+	if err := txRepo.ReplaceVendorGroups(ctx, cartID, payload.VendorGroups); err != nil {
+		fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups error=%v cart_id=%s vendor_groups=%d\n", err, cartID.String(), len(payload.VendorGroups))
+		return nil, err
+	}
+	fmt.Printf("[cart.persistQuote.tx] replace_vendor_groups ok cart_id=%s\n", cartID.String())
+
+	fmt.Printf("[cart.persistQuote.tx] find_saved start cart_id=%s buyer_store_id=%s\n", cartID.String(), buyerStoreID.String())
+	loaded, err := txRepo.FindByIDAndBuyerStore(ctx, cartID, buyerStoreID)
+	if err != nil {
+		fmt.Printf("[cart.persistQuote.tx] find_saved error=%v cart_id=%s buyer_store_id=%s\n", err, cartID.String(), buyerStoreID.String())
+		return nil, err
+	}
+	fmt.Printf("[cart.persistQuote.tx] find_saved ok cart_id=%s items=%d vendor_groups=%d\n",
+		loaded.ID.String(), len(loaded.Items), len(loaded.VendorGroups),
+	)
+	return loaded, nil
+}
+
 // GetActiveCart returns the active cart for the buyer, or not-found.
 func (s *service) GetActiveCart(ctx context.Context, buyerStoreID uuid.UUID) (*models.CartRecord, error) {
 	if buyerStoreID == uuid.Nil {
@@ -390,19 +517,30 @@ func (s *service) GetActiveCart(ctx context.Context, buyerStoreID uuid.UUID) (*m
 	return record, nil
 }
 
-func selectVolumeDiscount(qty int, tiers []models.ProductVolumeDiscount) *models.ProductVolumeDiscount {
+// selectVolumeDiscount picks the tier applied to qty. Under the default strategy the qualifying
+// tier with the largest min_qty wins; under lowest_price the largest discount wins, with ties
+// going to the larger min_qty so overlapping tiers resolve deterministically.
+func selectVolumeDiscount(qty int, tiers []models.ProductVolumeDiscount, strategy enums.VolumeDiscountStrategy) *models.ProductVolumeDiscount {
 	var selected *models.ProductVolumeDiscount
 	for _, tier := range tiers {
-		if tier.MinQty <= qty {
-			if selected == nil || tier.MinQty > selected.MinQty {
-				copy := tier
-				selected = &copy
-			}
+		if tier.MinQty > qty {
+			continue
+		}
+		if selected == nil || tierPreferred(tier, *selected, strategy) {
+			copy := tier
+			selected = &copy
 		}
 	}
 	return selected
 }
 
+func tierPreferred(candidate, current models.ProductVolumeDiscount, strategy enums.VolumeDiscountStrategy) bool {
+	if strategy == enums.VolumeDiscountStrategyLowestPrice && candidate.DiscountPercent != current.DiscountPercent {
+		return candidate.DiscountPercent > current.DiscountPercent
+	}
+	return candidate.MinQty > current.MinQty
+}
+
 func normalizeState(value string) string {
 	return strings.ToUpper(strings.TrimSpace(value))
 }
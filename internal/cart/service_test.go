@@ -12,6 +12,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -26,18 +27,76 @@ func TestSelectVolumeDiscount(t *testing.T) {
 		{MinQty: 20, DiscountPercent: 30},
 	}
 
-	if res := selectVolumeDiscount(12, tiers); res == nil || res.MinQty != 10 {
+	if res := selectVolumeDiscount(12, tiers, enums.VolumeDiscountStrategyHighestMinQty); res == nil || res.MinQty != 10 {
 		t.Fatalf("expected tier with min qty 10, got %+v", res)
 	}
 
-	if res := selectVolumeDiscount(4, tiers); res != nil {
+	if res := selectVolumeDiscount(4, tiers, enums.VolumeDiscountStrategyHighestMinQty); res != nil {
 		t.Fatalf("expected no tier for qty 4, got %+v", res)
 	}
 
-	if res := selectVolumeDiscount(25, tiers); res == nil || res.MinQty != 20 {
+	if res := selectVolumeDiscount(25, tiers, enums.VolumeDiscountStrategyHighestMinQty); res == nil || res.MinQty != 20 {
 		t.Fatalf("expected highest tier for qty 25, got %+v", res)
 	}
 }
+
+func TestSelectVolumeDiscountOverlappingTiers(t *testing.T) {
+	t.Parallel()
+
+	// The 20+ tier is shallower than the 10+ tier, so the strategies disagree once both qualify.
+	tiers := []models.ProductVolumeDiscount{
+		{MinQty: 5, DiscountPercent: 15},
+		{MinQty: 20, DiscountPercent: 5},
+		{MinQty: 10, DiscountPercent: 15},
+	}
+
+	cases := []struct {
+		name         string
+		strategy     enums.VolumeDiscountStrategy
+		qty          int
+		expectMinQty int
+	}{
+		{name: "highest min qty", strategy: enums.VolumeDiscountStrategyHighestMinQty, qty: 25, expectMinQty: 20},
+		{name: "unset defaults to highest min qty", strategy: "", qty: 25, expectMinQty: 20},
+		{name: "lowest price breaks ties on min qty", strategy: enums.VolumeDiscountStrategyLowestPrice, qty: 25, expectMinQty: 10},
+		{name: "lowest price below overlap", strategy: enums.VolumeDiscountStrategyLowestPrice, qty: 7, expectMinQty: 5},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res := selectVolumeDiscount(tc.qty, tiers, tc.strategy)
+			if res == nil || res.MinQty != tc.expectMinQty {
+				t.Fatalf("expected tier with min qty %d, got %+v", tc.expectMinQty, res)
+			}
+		})
+	}
+}
+
+func TestResolvePricingRoundsTheLineDiscountOnce(t *testing.T) {
+	t.Parallel()
+
+	tier := &models.ProductVolumeDiscount{MinQty: 3, DiscountPercent: 10}
+
+	// 10% of 999 is 99.9; rounding 33.3 per unit and multiplying used to give 99.
+	base, discount, effective, applied := resolvePricing(333, 3, tier, money.RoundHalfUp)
+	if base != 333 || discount != 100 || effective != 300 {
+		t.Fatalf("unexpected pricing base=%d discount=%d effective=%d", base, discount, effective)
+	}
+	if applied == nil || applied.AmountCents != 100 {
+		t.Fatalf("expected applied discount of 100, got %+v", applied)
+	}
+
+	// 15% of 7 x 250 is 262.5, so the mode decides the last cent.
+	tier.DiscountPercent = 15
+	if _, discount, _, _ := resolvePricing(250, 7, tier, money.RoundHalfUp); discount != 263 {
+		t.Fatalf("expected half up discount 263, got %d", discount)
+	}
+	if _, discount, _, _ := resolvePricing(250, 7, tier, money.RoundHalfEven); discount != 262 {
+		t.Fatalf("expected half even discount 262, got %d", discount)
+	}
+}
 func (s *stubCartRepo) UpdateStatus(ctx context.Context, id, buyerStoreID uuid.UUID, status enums.CartStatus) error {
 	return nil
 }
@@ -88,7 +147,7 @@ func TestServiceGetActiveCartSuccess(t *testing.T) {
 func newTestService(repo CartRepository, store *stores.StoreDTO) Service {
 	svc, err := NewService(repo, stubTxRunner{}, storeLoaderFunc(func(ctx context.Context, id uuid.UUID) (*stores.StoreDTO, error) {
 		return store, nil
-	}), stubProductLoader{products: map[uuid.UUID]*models.Product{}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	}), stubProductLoader{products: map[uuid.UUID]*models.Product{}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		panic(err)
 	}
@@ -136,7 +195,7 @@ func TestQuoteCartIgnoresInventoryShortage(t *testing.T) {
 		default:
 			return nil, fmt.Errorf("store %s not found", id)
 		}
-	}), stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	}), stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -208,7 +267,7 @@ func TestQuoteCartVendorPreloadsOncePerVendor(t *testing.T) {
 	})
 
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: products}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: products}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -284,7 +343,7 @@ func TestQuoteCartFiltersInvalidAdTokens(t *testing.T) {
 			DestinationURL: "https://pfz.io",
 		},
 	}}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), validator)
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), validator, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -342,7 +401,7 @@ func TestQuoteCartDetectsProductVendorMismatch(t *testing.T) {
 	})
 
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{otherProduct.ID: otherProduct}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{otherProduct.ID: otherProduct}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -379,6 +438,63 @@ func TestQuoteCartDetectsProductVendorMismatch(t *testing.T) {
 	}
 }
 
+func TestQuoteCartRejectsMixedCurrencyProducts(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+		Currency:  enums.CurrencyUSD,
+	}
+	vendorID := uuid.New()
+	vendorStore := &stores.StoreDTO{
+		ID:                 vendorID,
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	product := &models.Product{
+		ID:         uuid.New(),
+		StoreID:    vendorID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		Currency:   enums.CurrencyBTC,
+		IsActive:   true,
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorID,
+			Quantity:      1,
+		}},
+	}
+
+	_, err = service.QuoteCart(context.Background(), buyerStore.ID, input)
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(repo.replaced) != 0 {
+		t.Fatalf("expected no items persisted, got %d", len(repo.replaced))
+	}
+}
+
 func TestQuoteCartClampsQuantityToMOQ(t *testing.T) {
 	t.Parallel()
 
@@ -416,7 +532,7 @@ func TestQuoteCartClampsQuantityToMOQ(t *testing.T) {
 	})
 
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -452,6 +568,156 @@ func TestQuoteCartClampsQuantityToMOQ(t *testing.T) {
 	}
 }
 
+func TestQuoteCartClampsQuantityToMaxQty(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		MaxQty:     4,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 10,
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorStore.ID,
+			Quantity:      9,
+		}},
+	}
+
+	if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.replaced) != 1 {
+		t.Fatalf("expected 1 item persisted, got %d", len(repo.replaced))
+	}
+
+	item := repo.replaced[0]
+	if item.Quantity != product.MaxQty {
+		t.Fatalf("expected quantity clamped to max (%d), got %d", product.MaxQty, item.Quantity)
+	}
+	if item.MaxQty == nil || *item.MaxQty != product.MaxQty {
+		t.Fatalf("expected saved max qty %d, got %v", product.MaxQty, item.MaxQty)
+	}
+	if item.LineSubtotalCents != product.MaxQty*product.PriceCents {
+		t.Fatalf("expected subtotal priced at clamped quantity, got %d", item.LineSubtotalCents)
+	}
+	if len(item.Warnings) == 0 || item.Warnings[0].Type != enums.CartItemWarningTypeClampedToMax {
+		t.Fatalf("expected clamp warning, got %+v", item.Warnings)
+	}
+}
+
+func TestQuoteCartUsesConfiguredQuoteTTL(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Category:   enums.ProductCategoryFlower,
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 10,
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorStore.ID,
+			Quantity:      1,
+		}},
+	}
+
+	cases := []struct {
+		name   string
+		policy QuoteTTLPolicy
+		want   time.Duration
+	}{
+		{name: "default", policy: QuoteTTLPolicy{Default: 30 * time.Minute}, want: 30 * time.Minute},
+		{name: "category override", policy: QuoteTTLPolicy{
+			Default:    30 * time.Minute,
+			Categories: map[enums.ProductCategory]time.Duration{enums.ProductCategoryFlower: 5 * time.Minute},
+		}, want: 5 * time.Minute},
+		{name: "unset", policy: QuoteTTLPolicy{}, want: defaultQuoteTTL},
+	}
+	for _, tc := range cases {
+		service, err := NewService(&stubCartRepo{}, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, tc.policy, money.RoundHalfUp)
+		if err != nil {
+			t.Fatalf("%s: failed to build service: %v", tc.name, err)
+		}
+
+		before := time.Now()
+		record, err := service.QuoteCart(context.Background(), buyerStore.ID, input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		after := time.Now()
+
+		if record.ValidUntil.Before(before.Add(tc.want)) || record.ValidUntil.After(after.Add(tc.want)) {
+			t.Fatalf("%s: expected valid_until ~%s from now, got %s", tc.name, tc.want, record.ValidUntil.Sub(before))
+		}
+	}
+}
+
 func TestQuoteCartMarksNotAvailableWhenInventoryInsufficient(t *testing.T) {
 	t.Parallel()
 
@@ -489,7 +755,7 @@ func TestQuoteCartMarksNotAvailableWhenInventoryInsufficient(t *testing.T) {
 	})
 
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -580,7 +846,7 @@ func TestQuoteCartPersistsVendorGroups(t *testing.T) {
 	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{
 		product1.ID: product1,
 		product2.ID: product2,
-	}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -637,7 +903,93 @@ func TestQuoteCartPersistsVendorGroups(t *testing.T) {
 	}
 }
 
-func TestQuoteCartWarnsOnInvalidVendorPromo(t *testing.T) {
+func TestQuoteCartAppliesTax(t *testing.T) {
+	t.Parallel()
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	certificateID := uuid.New()
+	cases := []struct {
+		name    string
+		buyer   func(*stores.StoreDTO)
+		wantTax int
+	}{
+		{name: "taxable buyer", buyer: func(*stores.StoreDTO) {}, wantTax: 200},
+		{name: "verified exempt buyer", buyer: func(store *stores.StoreDTO) {
+			store.TaxExempt = true
+			store.TaxExemptCertificateMediaID = &certificateID
+			store.TaxExemptCertificateVerifiedAt = &verifiedAt
+		}, wantTax: 0},
+		{name: "unverified exempt buyer", buyer: func(store *stores.StoreDTO) {
+			store.TaxExempt = true
+			store.TaxExemptCertificateMediaID = &certificateID
+		}, wantTax: 200},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buyerStore := &stores.StoreDTO{
+				ID:        uuid.New(),
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+			}
+			tc.buyer(buyerStore)
+			vendorStore := &stores.StoreDTO{
+				ID:                 uuid.New(),
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+			}
+			productID := uuid.New()
+			product := &models.Product{
+				ID:         productID,
+				StoreID:    vendorStore.ID,
+				SKU:        "SKU",
+				Unit:       enums.ProductUnitUnit,
+				MOQ:        1,
+				PriceCents: 1000,
+				IsActive:   true,
+				Inventory: &models.InventoryItem{
+					ProductID:    productID,
+					AvailableQty: 10,
+				},
+			}
+
+			loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+				buyerStore.ID:  buyerStore,
+				vendorStore.ID: vendorStore,
+			})
+			repo := &stubCartRepo{}
+			service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp, WithTaxCalculator(stubTaxCalculator{ratePercent: 10}))
+			if err != nil {
+				t.Fatalf("failed to build service: %v", err)
+			}
+
+			record, err := service.QuoteCart(context.Background(), buyerStore.ID, QuoteCartInput{
+				Items: []QuoteCartItem{{ProductID: product.ID, VendorStoreID: vendorStore.ID, Quantity: 2}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if record.TaxCents != tc.wantTax || record.TotalCents != 2000+tc.wantTax {
+				t.Fatalf("expected tax %d and total %d, got %d and %d", tc.wantTax, 2000+tc.wantTax, record.TaxCents, record.TotalCents)
+			}
+			if len(repo.replacedGroups) != 1 {
+				t.Fatalf("expected 1 vendor group, got %d", len(repo.replacedGroups))
+			}
+			group := repo.replacedGroups[0]
+			if group.TaxCents != tc.wantTax || group.TotalCents != 2000+tc.wantTax {
+				t.Fatalf("unexpected group totals %+v", group)
+			}
+		})
+	}
+}
+
+func TestQuoteCartPricesShipping(t *testing.T) {
 	t.Parallel()
 
 	buyerStore := &stores.StoreDTO{
@@ -660,7 +1012,7 @@ func TestQuoteCartWarnsOnInvalidVendorPromo(t *testing.T) {
 		SKU:        "SKU",
 		Unit:       enums.ProductUnitUnit,
 		MOQ:        1,
-		PriceCents: 1500,
+		PriceCents: 1000,
 		IsActive:   true,
 		Inventory: &models.InventoryItem{
 			ProductID:    productID,
@@ -672,89 +1024,278 @@ func TestQuoteCartWarnsOnInvalidVendorPromo(t *testing.T) {
 		buyerStore.ID:  buyerStore,
 		vendorStore.ID: vendorStore,
 	})
-
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	quoter := &stubShippingQuoter{line: types.ShippingLine{Code: "standard", Title: "Standard", PriceCents: 750}}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp, WithShippingQuoter(quoter))
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
 
-	input := QuoteCartInput{
-		Items: []QuoteCartItem{{
-			ProductID:     product.ID,
-			VendorStoreID: vendorStore.ID,
-			Quantity:      1,
-		}},
-		VendorPromos: []QuoteVendorPromo{{
-			VendorStoreID: vendorStore.ID,
-			Code:          "SAVE20",
-		}},
-	}
-	if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+	shipTo := types.Address{Line1: "9 Dock", City: "City", State: "OK", PostalCode: "00000", Country: "US", Lat: 36.1, Lng: -95.9}
+	requested := &types.ShippingLine{Code: "standard"}
+	record, err := service.QuoteCart(context.Background(), buyerStore.ID, QuoteCartInput{
+		Items:           []QuoteCartItem{{ProductID: product.ID, VendorStoreID: vendorStore.ID, Quantity: 2}},
+		ShippingAddress: &shipTo,
+		ShippingLine:    requested,
+	})
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if record.TotalCents != 2750 {
+		t.Fatalf("expected the shipping fee in the cart total, got %d", record.TotalCents)
+	}
+	if record.ShippingLine == nil || record.ShippingLine.Code != "standard" {
+		t.Fatalf("expected the requested shipping line on the cart, got %+v", record.ShippingLine)
+	}
 	if len(repo.replacedGroups) != 1 {
 		t.Fatalf("expected 1 vendor group, got %d", len(repo.replacedGroups))
 	}
 	group := repo.replacedGroups[0]
-	if len(group.Warnings) == 0 {
-		t.Fatalf("expected warnings for invalid promo, got %+v", group.Warnings)
-	}
-	if group.Warnings[0].Type != enums.VendorGroupWarningTypeInvalidPromo {
-		t.Fatalf("unexpected warning type %s", group.Warnings[0].Type)
+	if group.ShippingLine == nil || group.ShippingLine.PriceCents != 750 || group.TotalCents != 2750 {
+		t.Fatalf("unexpected group shipping %+v", group)
 	}
-	if group.Warnings[0].Message != invalidPromoWarningMessage {
-		t.Fatalf("unexpected warning message %q", group.Warnings[0].Message)
+	if quoter.vendorID != vendorStore.ID || quoter.destination.Line1 != "9 Dock" || quoter.subtotalCents != 2000 || quoter.requested != requested {
+		t.Fatalf("unexpected shipping quote request %+v", quoter)
 	}
 }
 
-func TestQuoteCartPersistsVolumeDiscount(t *testing.T) {
+func TestQuoteCartFlagsOutOfZoneVendors(t *testing.T) {
 	t.Parallel()
 
 	buyerStore := &stores.StoreDTO{
 		ID:        uuid.New(),
 		Type:      enums.StoreTypeBuyer,
 		KYCStatus: enums.KYCStatusVerified,
-		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
-	}
-	vendorStore := &stores.StoreDTO{
-		ID:                 uuid.New(),
-		Type:               enums.StoreTypeVendor,
-		KYCStatus:          enums.KYCStatusVerified,
-		SubscriptionActive: true,
-		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
-	}
-	productID := uuid.New()
-	product := &models.Product{
-		ID:         productID,
-		StoreID:    vendorStore.ID,
-		SKU:        "SKU",
-		Unit:       enums.ProductUnitUnit,
-		MOQ:        1,
-		PriceCents: 1000,
-		IsActive:   true,
-		Inventory: &models.InventoryItem{
-			ProductID:    productID,
-			AvailableQty: 20,
-		},
-		VolumeDiscounts: []models.ProductVolumeDiscount{
-			{MinQty: 5, DiscountPercent: 20},
-		},
+		Address:   types.Address{Line1: "1", City: "Tulsa", State: "OK", PostalCode: "74103", Country: "US", Lat: 36.1540, Lng: -95.9928},
+	}
+	// Broken Arrow is roughly 14 miles from the buyer; Oklahoma City is roughly 100.
+	inZone := &stores.StoreDTO{
+		ID:                   uuid.New(),
+		Type:                 enums.StoreTypeVendor,
+		KYCStatus:            enums.KYCStatusVerified,
+		SubscriptionActive:   true,
+		Address:              types.Address{Line1: "2", City: "Broken Arrow", State: "OK", PostalCode: "74012", Country: "US", Lat: 36.0526, Lng: -95.7908},
+		DeliveryRadiusMeters: 40234,
+	}
+	outOfZone := &stores.StoreDTO{
+		ID:                   uuid.New(),
+		Type:                 enums.StoreTypeVendor,
+		KYCStatus:            enums.KYCStatusVerified,
+		SubscriptionActive:   true,
+		Address:              types.Address{Line1: "3", City: "Oklahoma City", State: "OK", PostalCode: "73102", Country: "US", Lat: 35.4676, Lng: -97.5164},
+		DeliveryRadiusMeters: 40234,
+	}
+
+	newProduct := func(storeID uuid.UUID) *models.Product {
+		return &models.Product{
+			ID:         uuid.New(),
+			StoreID:    storeID,
+			SKU:        "SKU",
+			Unit:       enums.ProductUnitUnit,
+			MOQ:        1,
+			PriceCents: 1000,
+			IsActive:   true,
+			Inventory: &models.InventoryItem{
+				ProductID:    uuid.New(),
+				AvailableQty: 10,
+			},
+		}
 	}
+	inZoneProduct := newProduct(inZone.ID)
+	outOfZoneProduct := newProduct(outOfZone.ID)
 
 	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
-		buyerStore.ID:  buyerStore,
-		vendorStore.ID: vendorStore,
+		buyerStore.ID: buyerStore,
+		inZone.ID:     inZone,
+		outOfZone.ID:  outOfZone,
 	})
 
 	repo := &stubCartRepo{}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{
+		inZoneProduct.ID:    inZoneProduct,
+		outOfZoneProduct.ID: outOfZoneProduct,
+	}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
 
-	input := QuoteCartInput{
-		Items: []QuoteCartItem{{
+	_, err = service.QuoteCart(context.Background(), buyerStore.ID, QuoteCartInput{
+		Items: []QuoteCartItem{
+			{ProductID: inZoneProduct.ID, VendorStoreID: inZone.ID, Quantity: 1},
+			{ProductID: outOfZoneProduct.ID, VendorStoreID: outOfZone.ID, Quantity: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, group := range repo.replacedGroups {
+		switch group.VendorStoreID {
+		case inZone.ID:
+			if group.Status != enums.VendorGroupStatusOK || len(group.Warnings) != 0 {
+				t.Fatalf("expected in-zone vendor to be orderable, got %+v", group)
+			}
+		case outOfZone.ID:
+			if group.Status != enums.VendorGroupStatusInvalid {
+				t.Fatalf("expected out-of-zone vendor invalid, got %s", group.Status)
+			}
+			if len(group.Warnings) != 1 || group.Warnings[0].Type != enums.VendorGroupWarningTypeOutOfZone {
+				t.Fatalf("expected out_of_zone warning, got %+v", group.Warnings)
+			}
+		}
+	}
+	for _, item := range repo.replaced {
+		if item.VendorStoreID == outOfZone.ID && item.Status == enums.CartItemStatusOK {
+			t.Fatalf("out-of-zone item should not be orderable")
+		}
+		if item.VendorStoreID == inZone.ID && item.Status != enums.CartItemStatusOK {
+			t.Fatalf("in-zone item should be orderable, got %s", item.Status)
+		}
+	}
+
+	// Zones follow the shipping address the buyer quotes, which checkout checks again.
+	okc := types.Address{Line1: "4", City: "Oklahoma City", State: "OK", PostalCode: "73102", Country: "US", Lat: 35.4676, Lng: -97.5164}
+	record, err := service.QuoteCart(context.Background(), buyerStore.ID, QuoteCartInput{
+		Items: []QuoteCartItem{
+			{ProductID: inZoneProduct.ID, VendorStoreID: inZone.ID, Quantity: 1},
+			{ProductID: outOfZoneProduct.ID, VendorStoreID: outOfZone.ID, Quantity: 1},
+		},
+		ShippingAddress: &okc,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ShippingAddress == nil || record.ShippingAddress.City != "Oklahoma City" {
+		t.Fatalf("expected quoted shipping address persisted, got %+v", record.ShippingAddress)
+	}
+	for _, group := range repo.replacedGroups {
+		if group.VendorStoreID == outOfZone.ID && group.Status != enums.VendorGroupStatusOK {
+			t.Fatalf("expected vendor near the shipping address to be orderable, got %+v", group)
+		}
+		if group.VendorStoreID == inZone.ID && group.Status != enums.VendorGroupStatusInvalid {
+			t.Fatalf("expected vendor far from the shipping address invalid, got %s", group.Status)
+		}
+	}
+}
+
+func TestQuoteCartWarnsOnInvalidVendorPromo(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1500,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 10,
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorStore.ID,
+			Quantity:      1,
+		}},
+		VendorPromos: []QuoteVendorPromo{{
+			VendorStoreID: vendorStore.ID,
+			Code:          "SAVE20",
+		}},
+	}
+	if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.replacedGroups) != 1 {
+		t.Fatalf("expected 1 vendor group, got %d", len(repo.replacedGroups))
+	}
+	group := repo.replacedGroups[0]
+	if len(group.Warnings) == 0 {
+		t.Fatalf("expected warnings for invalid promo, got %+v", group.Warnings)
+	}
+	if group.Warnings[0].Type != enums.VendorGroupWarningTypeInvalidPromo {
+		t.Fatalf("unexpected warning type %s", group.Warnings[0].Type)
+	}
+	if group.Warnings[0].Message != invalidPromoWarningMessage {
+		t.Fatalf("unexpected warning message %q", group.Warnings[0].Message)
+	}
+}
+
+func TestQuoteCartPersistsVolumeDiscount(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 20,
+		},
+		VolumeDiscounts: []models.ProductVolumeDiscount{
+			{MinQty: 5, DiscountPercent: 20},
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
 			ProductID:     product.ID,
 			VendorStoreID: vendorStore.ID,
 			Quantity:      5,
@@ -797,6 +1338,475 @@ func TestQuoteCartPersistsVolumeDiscount(t *testing.T) {
 	}
 }
 
+func TestQuoteCartUsesVendorVolumeDiscountStrategy(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                     uuid.New(),
+		Type:                   enums.StoreTypeVendor,
+		KYCStatus:              enums.KYCStatusVerified,
+		SubscriptionActive:     true,
+		VolumeDiscountStrategy: enums.VolumeDiscountStrategyLowestPrice,
+		Address:                types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 50,
+		},
+		VolumeDiscounts: []models.ProductVolumeDiscount{
+			{MinQty: 10, DiscountPercent: 20},
+			{MinQty: 20, DiscountPercent: 10},
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorStore.ID,
+			Quantity:      25,
+		}},
+	}
+	if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.replaced) != 1 {
+		t.Fatalf("expected 1 item persisted, got %d", len(repo.replaced))
+	}
+	item := repo.replaced[0]
+	if item.EffectiveUnitPriceCents != 800 {
+		t.Fatalf("expected lowest effective price 800, got %d", item.EffectiveUnitPriceCents)
+	}
+	if item.AppliedVolumeDiscount == nil || item.AppliedVolumeDiscount.Label != "volume tier 10+" {
+		t.Fatalf("expected volume tier 10+ to apply, got %+v", item.AppliedVolumeDiscount)
+	}
+}
+
+func TestQuoteCartPromoAndVolumeDiscountStacking(t *testing.T) {
+	t.Parallel()
+
+	// 5 units at 1000 with a 20% tier gives 1000 of volume discounts on a 5000 subtotal.
+	cases := []struct {
+		name               string
+		promoAmount        int
+		stacks             bool
+		expectPromo        bool
+		expectLineDiscount int
+		expectPromoAmount  int
+		expectWarning      bool
+	}{
+		{name: "stacking promo applies on top", promoAmount: 500, stacks: true, expectPromo: true, expectLineDiscount: 1000, expectPromoAmount: 500},
+		{name: "smaller promo is dropped", promoAmount: 500, expectLineDiscount: 1000, expectWarning: true},
+		{name: "equal promo keeps volume discounts", promoAmount: 1000, expectLineDiscount: 1000, expectWarning: true},
+		{name: "larger promo replaces volume discounts", promoAmount: 1500, expectPromo: true, expectPromoAmount: 1500, expectWarning: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buyerStore := &stores.StoreDTO{
+				ID:        uuid.New(),
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+			}
+			vendorStore := &stores.StoreDTO{
+				ID:                 uuid.New(),
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+			}
+			productID := uuid.New()
+			product := &models.Product{
+				ID:         productID,
+				StoreID:    vendorStore.ID,
+				SKU:        "SKU",
+				Unit:       enums.ProductUnitUnit,
+				MOQ:        1,
+				PriceCents: 1000,
+				IsActive:   true,
+				Inventory: &models.InventoryItem{
+					ProductID:    productID,
+					AvailableQty: 20,
+				},
+				VolumeDiscounts: []models.ProductVolumeDiscount{
+					{MinQty: 5, DiscountPercent: 20},
+				},
+			}
+
+			promos := promoLoaderFunc(func(ctx context.Context, vendorID uuid.UUID, code string) (*VendorPromo, error) {
+				return &VendorPromo{
+					VendorStoreID:             vendorID,
+					Code:                      code,
+					AmountCents:               tc.promoAmount,
+					Active:                    true,
+					StacksWithVolumeDiscounts: tc.stacks,
+				}, nil
+			})
+
+			loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+				buyerStore.ID:  buyerStore,
+				vendorStore.ID: vendorStore,
+			})
+
+			repo := &stubCartRepo{}
+			service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, promos, stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+			if err != nil {
+				t.Fatalf("failed to build service: %v", err)
+			}
+
+			input := QuoteCartInput{
+				Items: []QuoteCartItem{{
+					ProductID:     product.ID,
+					VendorStoreID: vendorStore.ID,
+					Quantity:      5,
+				}},
+				VendorPromos: []QuoteVendorPromo{{
+					VendorStoreID: vendorStore.ID,
+					Code:          "SAVE",
+				}},
+			}
+			if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(repo.replaced) != 1 || len(repo.replacedGroups) != 1 {
+				t.Fatalf("expected 1 item and 1 group, got %d and %d", len(repo.replaced), len(repo.replacedGroups))
+			}
+			item := repo.replaced[0]
+			group := repo.replacedGroups[0]
+
+			if item.LineDiscountsCents != tc.expectLineDiscount {
+				t.Fatalf("expected line discounts %d, got %d", tc.expectLineDiscount, item.LineDiscountsCents)
+			}
+			if item.LineTotalCents != item.LineSubtotalCents-tc.expectLineDiscount {
+				t.Fatalf("unexpected line total %d", item.LineTotalCents)
+			}
+			if (item.AppliedVolumeDiscount != nil) != (tc.expectLineDiscount > 0) {
+				t.Fatalf("unexpected applied volume discount %+v", item.AppliedVolumeDiscount)
+			}
+			if (group.Promo != nil) != tc.expectPromo {
+				t.Fatalf("expected promo present=%v, got %+v", tc.expectPromo, group.Promo)
+			}
+			if group.PromoDiscountCents != tc.expectPromoAmount {
+				t.Fatalf("expected promo discount %d, got %d", tc.expectPromoAmount, group.PromoDiscountCents)
+			}
+			if group.DiscountsCents != tc.expectLineDiscount+tc.expectPromoAmount {
+				t.Fatalf("unexpected group discounts %d", group.DiscountsCents)
+			}
+
+			warned := false
+			for _, warning := range group.Warnings {
+				if warning.Type == enums.VendorGroupWarningTypePromoNotCombined {
+					warned = true
+				}
+			}
+			if warned != tc.expectWarning {
+				t.Fatalf("expected promo_not_combined warning=%v, got %+v", tc.expectWarning, group.Warnings)
+			}
+		})
+	}
+}
+
+func TestQuoteCartQuoteHash(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 20,
+		},
+	}
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+
+	repo := &stubCartRepo{}
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{{
+			ProductID:     product.ID,
+			VendorStoreID: vendorStore.ID,
+			Quantity:      3,
+		}},
+	}
+
+	quote := func() string {
+		record, err := service.QuoteCart(context.Background(), buyerStore.ID, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if record.QuoteHash == "" {
+			t.Fatalf("expected quote hash to be set")
+		}
+		return record.QuoteHash
+	}
+
+	first := quote()
+	if second := quote(); second != first {
+		t.Fatalf("expected identical quotes to share a hash, got %s and %s", first, second)
+	}
+
+	product.PriceCents = 1100
+	if changed := quote(); changed == first {
+		t.Fatalf("expected hash to change after a price change")
+	}
+}
+
+func TestComputeQuoteHashIgnoresOrderingAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	vendorA, vendorB := uuid.New(), uuid.New()
+	itemA := models.CartItem{ID: uuid.New(), ProductID: uuid.New(), VendorStoreID: vendorA, Quantity: 1, UnitPriceCents: 500, Status: enums.CartItemStatusOK}
+	itemB := models.CartItem{ID: uuid.New(), ProductID: uuid.New(), VendorStoreID: vendorB, Quantity: 2, UnitPriceCents: 700, Status: enums.CartItemStatusOK}
+	groupA := models.CartVendorGroup{VendorStoreID: vendorA, Status: enums.VendorGroupStatusOK, SubtotalCents: 500, TotalCents: 500}
+	groupB := models.CartVendorGroup{VendorStoreID: vendorB, Status: enums.VendorGroupStatusOK, SubtotalCents: 1400, TotalCents: 1400}
+
+	base := cartRecordPayload{
+		Currency:      enums.CurrencyUSD,
+		ValidUntil:    time.Now(),
+		SubtotalCents: 1900,
+		TotalCents:    1900,
+		Items:         []models.CartItem{itemA, itemB},
+		VendorGroups:  []models.CartVendorGroup{groupA, groupB},
+	}
+
+	reordered := base
+	reordered.ValidUntil = base.ValidUntil.Add(time.Hour)
+	reordered.Items = []models.CartItem{itemB, itemA}
+	reordered.VendorGroups = []models.CartVendorGroup{groupB, groupA}
+	reordered.Items[0].ID = uuid.New()
+
+	if computeQuoteHash(base) != computeQuoteHash(reordered) {
+		t.Fatalf("expected ordering, row ids, and expiry to be ignored")
+	}
+
+	repriced := base
+	repriced.TotalCents = 1800
+	if computeQuoteHash(base) == computeQuoteHash(repriced) {
+		t.Fatalf("expected total change to alter the hash")
+	}
+}
+
+func TestQuoteCartAppliesBuyerPriceOverride(t *testing.T) {
+	t.Parallel()
+
+	newBuyer := func() *stores.StoreDTO {
+		return &stores.StoreDTO{
+			ID:        uuid.New(),
+			Type:      enums.StoreTypeBuyer,
+			KYCStatus: enums.KYCStatusVerified,
+			Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+		}
+	}
+	preferredBuyer := newBuyer()
+	regularBuyer := newBuyer()
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	productID := uuid.New()
+	product := &models.Product{
+		ID:         productID,
+		StoreID:    vendorStore.ID,
+		SKU:        "SKU",
+		Unit:       enums.ProductUnitUnit,
+		MOQ:        1,
+		PriceCents: 1000,
+		IsActive:   true,
+		Inventory: &models.InventoryItem{
+			ProductID:    productID,
+			AvailableQty: 20,
+		},
+		VolumeDiscounts: []models.ProductVolumeDiscount{
+			{MinQty: 5, DiscountPercent: 10},
+		},
+	}
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{product.ID: product},
+		buyerPrices: map[uuid.UUID]*models.ProductBuyerPrice{
+			preferredBuyer.ID: {StoreID: vendorStore.ID, ProductID: product.ID, BuyerStoreID: preferredBuyer.ID, PriceCents: 900},
+		},
+	}
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		preferredBuyer.ID: preferredBuyer,
+		regularBuyer.ID:   regularBuyer,
+		vendorStore.ID:    vendorStore,
+	})
+
+	quote := func(buyerID uuid.UUID) models.CartItem {
+		t.Helper()
+		repo := &stubCartRepo{}
+		service, err := NewService(repo, stubTxRunner{}, loader, productLoader, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+		if err != nil {
+			t.Fatalf("failed to build service: %v", err)
+		}
+		input := QuoteCartInput{
+			Items: []QuoteCartItem{{ProductID: product.ID, VendorStoreID: vendorStore.ID, Quantity: 5}},
+		}
+		if _, err := service.QuoteCart(context.Background(), buyerID, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repo.replaced) != 1 {
+			t.Fatalf("expected 1 item persisted, got %d", len(repo.replaced))
+		}
+		return repo.replaced[0]
+	}
+
+	preferred := quote(preferredBuyer.ID)
+	if preferred.UnitPriceCents != 900 {
+		t.Fatalf("expected override unit price 900, got %d", preferred.UnitPriceCents)
+	}
+	if preferred.EffectiveUnitPriceCents != 810 || preferred.LineSubtotalCents != 4500 || preferred.LineTotalCents != 4050 {
+		t.Fatalf("expected volume discount on top of override, got effective=%d subtotal=%d total=%d",
+			preferred.EffectiveUnitPriceCents, preferred.LineSubtotalCents, preferred.LineTotalCents)
+	}
+
+	regular := quote(regularBuyer.ID)
+	if regular.UnitPriceCents != product.PriceCents {
+		t.Fatalf("expected base unit price %d, got %d", product.PriceCents, regular.UnitPriceCents)
+	}
+	if regular.EffectiveUnitPriceCents != 900 || regular.LineTotalCents != 4500 {
+		t.Fatalf("expected default tier pricing, got effective=%d total=%d", regular.EffectiveUnitPriceCents, regular.LineTotalCents)
+	}
+}
+
+func TestQuoteCartBlocksProductsExcludedFromBuyerState(t *testing.T) {
+	t.Parallel()
+
+	buyerStore := &stores.StoreDTO{
+		ID:        uuid.New(),
+		Type:      enums.StoreTypeBuyer,
+		KYCStatus: enums.KYCStatusVerified,
+		Address:   types.Address{Line1: "1", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	vendorStore := &stores.StoreDTO{
+		ID:                 uuid.New(),
+		Type:               enums.StoreTypeVendor,
+		KYCStatus:          enums.KYCStatusVerified,
+		SubscriptionActive: true,
+		Address:            types.Address{Line1: "2", City: "City", State: "OK", PostalCode: "00000", Country: "US"},
+	}
+	newProduct := func(sku string, excluded ...string) *models.Product {
+		id := uuid.New()
+		return &models.Product{
+			ID:             id,
+			StoreID:        vendorStore.ID,
+			SKU:            sku,
+			Unit:           enums.ProductUnitUnit,
+			MOQ:            1,
+			PriceCents:     1000,
+			IsActive:       true,
+			ExcludedStates: excluded,
+			Inventory:      &models.InventoryItem{ProductID: id, AvailableQty: 10},
+		}
+	}
+	hidden := newProduct("HIDDEN", "ok")
+	elsewhere := newProduct("ELSEWHERE", "TX")
+
+	loader := newCountingStoreLoader(map[uuid.UUID]*stores.StoreDTO{
+		buyerStore.ID:  buyerStore,
+		vendorStore.ID: vendorStore,
+	})
+	repo := &stubCartRepo{}
+	productLoader := stubProductLoader{products: map[uuid.UUID]*models.Product{hidden.ID: hidden, elsewhere.ID: elsewhere}}
+	service, err := NewService(repo, stubTxRunner{}, loader, productLoader, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
+	if err != nil {
+		t.Fatalf("failed to build service: %v", err)
+	}
+
+	input := QuoteCartInput{
+		Items: []QuoteCartItem{
+			{ProductID: hidden.ID, VendorStoreID: vendorStore.ID, Quantity: 1},
+			{ProductID: elsewhere.ID, VendorStoreID: vendorStore.ID, Quantity: 1},
+		},
+	}
+	if _, err := service.QuoteCart(context.Background(), buyerStore.ID, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.replaced) != 2 {
+		t.Fatalf("expected 2 items persisted, got %d", len(repo.replaced))
+	}
+
+	for _, item := range repo.replaced {
+		switch item.ProductID {
+		case hidden.ID:
+			if item.Status != enums.CartItemStatusNotAvailable {
+				t.Fatalf("expected excluded product not available, got %s", item.Status)
+			}
+			if len(item.Warnings) != 1 || item.Warnings[0].Type != enums.CartItemWarningTypeNotAvailable {
+				t.Fatalf("expected not_available warning, got %+v", item.Warnings)
+			}
+		case elsewhere.ID:
+			if item.Status != enums.CartItemStatusOK {
+				t.Fatalf("expected product excluded only from TX to be available, got %s", item.Status)
+			}
+		}
+	}
+	if len(repo.replacedGroups) != 1 || repo.replacedGroups[0].SubtotalCents != 1000 {
+		t.Fatalf("expected only the available item in the vendor subtotal, got %+v", repo.replacedGroups)
+	}
+}
+
 func TestQuoteCartAddsPriceChangedWarning(t *testing.T) {
 	t.Parallel()
 
@@ -849,7 +1859,7 @@ func TestQuoteCartAddsPriceChangedWarning(t *testing.T) {
 			},
 		},
 	}
-	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}})
+	service, err := NewService(repo, stubTxRunner{}, loader, stubProductLoader{products: map[uuid.UUID]*models.Product{product.ID: product}}, NoopPromoLoader(), stubTokenParser{parsed: map[string]token.Payload{}}, QuoteTTLPolicy{}, money.RoundHalfUp)
 	if err != nil {
 		t.Fatalf("failed to build service: %v", err)
 	}
@@ -883,6 +1893,34 @@ func TestQuoteCartAddsPriceChangedWarning(t *testing.T) {
 	}
 }
 
+type stubTaxCalculator struct {
+	ratePercent int
+}
+
+func (s stubTaxCalculator) TaxCents(buyer *stores.StoreDTO, taxableCents int, now time.Time) int {
+	if buyer.TaxExemptAt(now) {
+		return 0
+	}
+	return taxableCents * s.ratePercent / 100
+}
+
+type stubShippingQuoter struct {
+	line          types.ShippingLine
+	vendorID      uuid.UUID
+	destination   types.Address
+	subtotalCents int
+	requested     *types.ShippingLine
+}
+
+func (s *stubShippingQuoter) QuoteShipping(_ context.Context, vendor *stores.StoreDTO, destination types.Address, subtotalCents int, requested *types.ShippingLine) (*types.ShippingLine, error) {
+	s.vendorID = vendor.ID
+	s.destination = destination
+	s.subtotalCents = subtotalCents
+	s.requested = requested
+	line := s.line
+	return &line, nil
+}
+
 type stubCartRepo struct {
 	record         *models.CartRecord
 	findErr        error
@@ -907,10 +1945,21 @@ func (s *stubCartRepo) FindByIDAndBuyerStore(ctx context.Context, id, buyerStore
 	s.record.Items = append([]models.CartItem(nil), s.replaced...)
 	return s.record, nil
 }
+func (s *stubCartRepo) FindSplitsFrom(ctx context.Context, sourceCartID uuid.UUID) ([]models.CartRecord, error) {
+	return nil, nil
+}
 func (s *stubCartRepo) Create(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
 	s.record = record
 	return record, nil
 }
+func (s *stubCartRepo) CreateOrReuseActive(ctx context.Context, record *models.CartRecord) (*models.CartRecord, bool, error) {
+	if s.record != nil && s.record.Status == enums.CartStatusActive {
+		return s.record, false, nil
+	}
+	record.Status = enums.CartStatusActive
+	s.record = record
+	return record, true, nil
+}
 func (s *stubCartRepo) Update(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
 	s.record = record
 	return record, nil
@@ -973,8 +2022,9 @@ func (s stubTokenParser) Parse(value string) (token.Payload, error) {
 }
 
 type stubProductLoader struct {
-	products map[uuid.UUID]*models.Product
-	err      error
+	products    map[uuid.UUID]*models.Product
+	buyerPrices map[uuid.UUID]*models.ProductBuyerPrice
+	err         error
 }
 
 func (s stubProductLoader) GetProductDetail(ctx context.Context, id uuid.UUID) (*models.Product, *products.VendorSummary, error) {
@@ -986,3 +2036,10 @@ func (s stubProductLoader) GetProductDetail(ctx context.Context, id uuid.UUID) (
 	}
 	return nil, nil, gorm.ErrRecordNotFound
 }
+
+func (s stubProductLoader) FindBuyerPrice(ctx context.Context, productID, buyerStoreID uuid.UUID) (*models.ProductBuyerPrice, error) {
+	if price, ok := s.buyerPrices[buyerStoreID]; ok && price.ProductID == productID {
+		return price, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
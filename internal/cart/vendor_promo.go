@@ -14,6 +14,9 @@ type VendorPromo struct {
 	AmountCents   int
 	Active        bool
 	ExpiresAt     time.Time
+	// StacksWithVolumeDiscounts lets the promo apply on top of volume tier discounts. When false,
+	// the quote keeps whichever of the two saves the buyer more.
+	StacksWithVolumeDiscounts bool
 }
 
 // IsValid reports whether the promo is active and not expired.
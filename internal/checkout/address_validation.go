@@ -0,0 +1,73 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+const (
+	addressValidationOff     = "off"
+	addressValidationLenient = "lenient"
+	addressValidationStrict  = "strict"
+)
+
+// AddressNormalizer geocodes a typed-in address and returns its normalized form with lat/lng.
+type AddressNormalizer interface {
+	Normalize(ctx context.Context, addr types.Address) (types.Address, error)
+}
+
+// ServiceOption customizes the checkout service.
+type ServiceOption func(*service)
+
+// WithAddressValidation normalizes the shipping address sent with checkout before it is stored on
+// vendor orders. In strict mode an address that cannot be verified fails checkout with
+// CodeValidation; otherwise the buyer's address is used as entered.
+func WithAddressValidation(normalizer AddressNormalizer, strict bool) ServiceOption {
+	return func(s *service) {
+		s.addresses = normalizer
+		s.strictAddresses = strict
+	}
+}
+
+// NewAddressValidationOption maps the configured validation mode (off, lenient, or strict) to a
+// service option.
+func NewAddressValidationOption(cfg config.ShippingConfig, normalizer AddressNormalizer) (ServiceOption, error) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.AddressValidation))
+	switch mode {
+	case "", addressValidationOff:
+		return func(*service) {}, nil
+	case addressValidationLenient, addressValidationStrict:
+		if normalizer == nil {
+			return nil, fmt.Errorf("address normalizer required for %s address validation", mode)
+		}
+		return WithAddressValidation(normalizer, mode == addressValidationStrict), nil
+	default:
+		return nil, fmt.Errorf("unsupported address validation mode %q", cfg.AddressValidation)
+	}
+}
+
+// normalizeShippingAddress replaces the checkout shipping address with its geocoded form. Only
+// strict mode turns a failure into an error: an unverifiable address fails with CodeValidation and
+// a Maps outage surfaces as is.
+func (s *service) normalizeShippingAddress(ctx context.Context, input *CheckoutInput) error {
+	if s.addresses == nil || input.ShippingAddress == nil {
+		return nil
+	}
+	normalized, err := s.addresses.Normalize(ctx, *input.ShippingAddress)
+	if err != nil {
+		if !s.strictAddresses {
+			return nil
+		}
+		if typed := pkgerrors.As(err); typed != nil && typed.Code() == pkgerrors.CodeValidation {
+			return pkgerrors.New(pkgerrors.CodeValidation, "shipping address could not be verified").WithDetails(typed.Details())
+		}
+		return err
+	}
+	input.ShippingAddress = &normalized
+	return nil
+}
@@ -0,0 +1,59 @@
+package checkout
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+const (
+	afterHoursQueue = "queue"
+	afterHoursBlock = "block"
+)
+
+// WithAfterHoursBlocking rejects checkout for vendors that are outside their business hours instead
+// of queueing the order until they open.
+func WithAfterHoursBlocking(block bool) ServiceOption {
+	return func(s *service) {
+		s.blockAfterHours = block
+	}
+}
+
+// NewAfterHoursOption maps the configured after-hours mode (queue or block) to a service option.
+func NewAfterHoursOption(cfg config.OrdersConfig) (ServiceOption, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(cfg.AfterHours)); mode {
+	case "", afterHoursQueue:
+		return WithAfterHoursBlocking(false), nil
+	case afterHoursBlock:
+		return WithAfterHoursBlocking(true), nil
+	default:
+		return nil, fmt.Errorf("unsupported after-hours mode %q", cfg.AfterHours)
+	}
+}
+
+// vendorOpensAt returns when a closed vendor next opens, or nil while it is within business hours.
+// In block mode a closed vendor fails checkout with the opening time in the error details.
+func (s *service) vendorOpensAt(vendor *stores.StoreDTO, now time.Time) (*time.Time, error) {
+	if vendor == nil || vendor.BusinessHours.IsEmpty() {
+		return nil, nil
+	}
+	opensAt, err := vendor.BusinessHours.NextOpening(now)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeInternal, err, "evaluate vendor business hours")
+	}
+	if opensAt.Equal(now) {
+		return nil, nil
+	}
+	opensAt = opensAt.UTC()
+	if s.blockAfterHours {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "vendor is closed").WithDetails(map[string]any{
+			"vendor_store_id": vendor.ID,
+			"opens_at":        opensAt,
+		})
+	}
+	return &opensAt, nil
+}
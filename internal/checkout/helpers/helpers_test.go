@@ -149,6 +149,36 @@ func TestValidateVendorStore(t *testing.T) {
 	}
 }
 
+func TestInDeliveryZone(t *testing.T) {
+	t.Parallel()
+	vendorAddress := types.Address{State: "OK", Lat: 36.1540, Lng: -95.9928}
+	nearby := types.Address{State: "OK", Lat: 36.0526, Lng: -95.7908}
+	farAway := types.Address{State: "OK", Lat: 35.4676, Lng: -97.5164}
+
+	cases := []struct {
+		name        string
+		zones       *types.DeliveryZones
+		radius      int
+		destination types.Address
+		want        bool
+	}{
+		{name: "no zones", destination: farAway, want: true},
+		{name: "within radius", radius: 40234, destination: nearby, want: true},
+		{name: "outside radius", radius: 40234, destination: farAway, want: false},
+		{name: "listed state", zones: &types.DeliveryZones{States: []string{"ok"}}, destination: farAway, want: true},
+		{name: "unlisted state", zones: &types.DeliveryZones{States: []string{"CA"}}, destination: types.Address{State: "OK"}, want: false},
+		{name: "listed state outside radius", zones: &types.DeliveryZones{States: []string{"OK"}}, radius: 40234, destination: farAway, want: true},
+		{name: "radius without coordinates", radius: 40234, destination: types.Address{State: "OK"}, want: true},
+		{name: "unlisted state without coordinates", zones: &types.DeliveryZones{States: []string{"CA"}}, radius: 40234, destination: types.Address{State: "OK"}, want: true},
+	}
+	for _, tc := range cases {
+		vendor := &stores.StoreDTO{Address: vendorAddress, DeliveryZones: tc.zones, DeliveryRadiusMeters: tc.radius}
+		if got := InDeliveryZone(vendor, tc.destination); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
 func TestValidateMOQ(t *testing.T) {
 	t.Parallel()
 	items := []checkout.MOQValidationInput{
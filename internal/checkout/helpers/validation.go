@@ -8,6 +8,8 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/angelmondragon/packfinderz-backend/pkg/visibility"
 )
 
@@ -47,11 +49,53 @@ func ValidateVendorStore(vendor *stores.StoreDTO, buyerState string) error {
 	})
 }
 
+// InDeliveryZone reports whether the destination falls inside the vendor's delivery area. A buyer is in zone
+// when their state is listed in the vendor's zones or they sit within the store's delivery radius. With
+// neither rule set the vendor serves every buyer. A radius cannot be checked without coordinates on both
+// addresses, so an ungeocoded address counts as unknown and is not treated as out of zone.
+func InDeliveryZone(vendor *stores.StoreDTO, destination types.Address) bool {
+	if vendor == nil {
+		return true
+	}
+	hasStates := !vendor.DeliveryZones.IsEmpty()
+	hasRadius := vendor.DeliveryRadiusMeters > 0
+	if !hasStates && !hasRadius {
+		return true
+	}
+
+	if hasStates {
+		state := normalizeState(destination.State)
+		for _, allowed := range vendor.DeliveryZones.States {
+			if normalizeState(allowed) == state {
+				return true
+			}
+		}
+	}
+
+	if hasRadius {
+		if !hasCoordinates(vendor.Address) || !hasCoordinates(destination) {
+			return true
+		}
+		meters := maps.DistanceMeters(
+			maps.LatLng{Latitude: vendor.Address.Lat, Longitude: vendor.Address.Lng},
+			maps.LatLng{Latitude: destination.Lat, Longitude: destination.Lng},
+		)
+		if meters <= float64(vendor.DeliveryRadiusMeters) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateMOQ enforces minimum order quantity compliance.
 func ValidateMOQ(items []checkout.MOQValidationInput) error {
 	return checkout.ValidateMOQ(items)
 }
 
+func hasCoordinates(addr types.Address) bool {
+	return addr.Lat != 0 || addr.Lng != 0
+}
+
 func normalizeState(value string) string {
 	return strings.ToUpper(strings.TrimSpace(value))
 }
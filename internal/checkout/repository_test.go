@@ -175,7 +175,7 @@ func (s *stubOrdersRepo) ListVendorOrders(ctx context.Context, vendorStoreID uui
 	panic("not implemented")
 }
 
-func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*orders.AgentOrderQueueList, error) {
+func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters orders.AgentQueueFilters) (*orders.AgentOrderQueueList, error) {
 	panic("not implemented")
 }
 
@@ -187,6 +187,10 @@ func (s *stubOrdersRepo) ListPayoutOrders(ctx context.Context, params pagination
 	panic("not implemented")
 }
 
+func (s *stubOrdersRepo) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(orders.AccountingExportLine) error) error {
+	panic("not implemented")
+}
+
 func (s *stubOrdersRepo) FindOrderDetail(ctx context.Context, orderID uuid.UUID) (*orders.OrderDetail, error) {
 	panic("not implemented")
 }
@@ -203,6 +207,10 @@ func (s *stubOrdersRepo) UpdateVendorOrderStatus(ctx context.Context, orderID uu
 	panic("not implemented")
 }
 
+func (s *stubOrdersRepo) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	panic("not implemented")
+}
+
 func (s *stubOrdersRepo) UpdateOrderLineItemStatus(ctx context.Context, lineItemID uuid.UUID, status enums.LineItemStatus, notes *string) error {
 	panic("not implemented")
 }
@@ -211,6 +219,22 @@ func (s *stubOrdersRepo) UpdateVendorOrder(ctx context.Context, orderID uuid.UUI
 	panic("not implemented")
 }
 
+func (s *stubOrdersRepo) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	panic("not implemented")
+}
+
+func (s *stubOrdersRepo) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	panic("not implemented")
+}
+
+func (s *stubOrdersRepo) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
+	panic("not implemented")
+}
+
+func (s *stubOrdersRepo) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	panic("not implemented")
+}
+
 func (s *stubOrdersRepo) UpdatePaymentIntent(ctx context.Context, orderID uuid.UUID, updates map[string]any) error {
 	panic("not implemented")
 }
@@ -219,6 +243,10 @@ func (s *stubOrdersRepo) UpdateOrderAssignment(ctx context.Context, assignmentID
 	panic("not implemented")
 }
 
+func (s *stubOrdersRepo) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	return false, nil
+}
+
 type stubCartLoader struct {
 	byCheckout map[uuid.UUID]*models.CartRecord
 	byID       map[uuid.UUID]*models.CartRecord
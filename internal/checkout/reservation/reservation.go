@@ -2,7 +2,11 @@ package reservation
 
 import (
 	"context"
+	"errors"
 
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -13,6 +17,7 @@ type InventoryReservationRequest struct {
 	CartItemID uuid.UUID
 	ProductID  uuid.UUID
 	Qty        int
+	Actor      inventory.AdjustmentActor
 }
 
 // InventoryReservationResult reports whether a reservation succeeded per line item.
@@ -24,8 +29,10 @@ type InventoryReservationResult struct {
 	Reason     string
 }
 
-// ReserveInventory atomically decrements available inventory and increments reserved qty per request.
-func ReserveInventory(ctx context.Context, db *gorm.DB, requests []InventoryReservationRequest) ([]InventoryReservationResult, error) {
+// ReserveInventory decrements available inventory and increments reserved qty per request, recording an
+// inventory adjustment for every successful reservation. Each row is compare-and-swapped on its version;
+// a concurrent change returns an error for which inventory.IsVersionConflict is true.
+func ReserveInventory(ctx context.Context, db *gorm.DB, publisher inventory.OutboxPublisher, requests []InventoryReservationRequest) ([]InventoryReservationResult, error) {
 	if db == nil {
 		return nil, pkgerrors.New(pkgerrors.CodeDependency, "database required for reservation")
 	}
@@ -36,31 +43,64 @@ func ReserveInventory(ctx context.Context, db *gorm.DB, requests []InventoryRese
 			return nil, pkgerrors.New(pkgerrors.CodeValidation, "reservation quantity must be positive")
 		}
 
-		res := tx.Exec(
-			`UPDATE inventory_items
-       SET available_qty = available_qty - ?, reserved_qty = reserved_qty + ?, updated_at = CURRENT_TIMESTAMP
-       WHERE product_id = ? AND available_qty >= ?`,
-			req.Qty,
-			req.Qty,
-			req.ProductID,
-			req.Qty,
-		)
-		if res.Error != nil {
-			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, res.Error, "reserve inventory")
-		}
-
 		result := InventoryReservationResult{
 			CartItemID: req.CartItemID,
 			ProductID:  req.ProductID,
 			Qty:        req.Qty,
 		}
-		if res.RowsAffected == 0 {
-			result.Reserved = false
+
+		item, err := LoadInventory(tx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil || item.AvailableQty < req.Qty {
 			result.Reason = "insufficient_inventory"
-		} else {
-			result.Reserved = true
+			results[i] = result
+			continue
 		}
+
+		if err := reserveAtVersion(tx, req.ProductID, req.Qty, item.Version); err != nil {
+			return nil, err
+		}
+		if err := inventory.RecordAdjustment(ctx, tx, publisher, req.ProductID, -req.Qty, req.Qty, enums.InventoryAdjustmentReasonCheckoutReserve, req.Actor); err != nil {
+			return nil, err
+		}
+		result.Reserved = true
 		results[i] = result
 	}
 	return results, nil
 }
+
+// LoadInventory reads the counters and version for a product, returning nil when no row exists.
+func LoadInventory(tx *gorm.DB, productID uuid.UUID) (*models.InventoryItem, error) {
+	var item models.InventoryItem
+	err := tx.Select("product_id", "available_qty", "reserved_qty", "version").
+		Take(&item, "product_id = ?", productID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load inventory")
+	}
+	return &item, nil
+}
+
+func reserveAtVersion(tx *gorm.DB, productID uuid.UUID, qty, version int) error {
+	res := tx.Exec(
+		`UPDATE inventory_items
+       SET available_qty = available_qty - ?, reserved_qty = reserved_qty + ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+       WHERE product_id = ? AND version = ? AND available_qty >= ?`,
+		qty,
+		qty,
+		productID,
+		version,
+		qty,
+	)
+	if res.Error != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, res.Error, "reserve inventory")
+	}
+	if res.RowsAffected == 0 {
+		return inventory.NewVersionConflictError(productID)
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package reservation
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
 	"github.com/google/uuid"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -35,7 +40,7 @@ func TestReserveInventory(t *testing.T) {
 	}
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		results, terr := ReserveInventory(ctx, tx, requests)
+		results, terr := ReserveInventory(ctx, tx, testPublisher(tx), requests)
 		if terr != nil {
 			return terr
 		}
@@ -72,6 +77,151 @@ func TestReserveInventory(t *testing.T) {
 	}
 }
 
+func TestReserveInventoryRecordsAdjustments(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	ctx := context.Background()
+	product := uuid.New()
+	buyerStore := uuid.New()
+	if err := db.Create(&models.InventoryItem{ProductID: product, AvailableQty: 5}).Error; err != nil {
+		t.Fatalf("seed inventory: %v", err)
+	}
+
+	requests := []InventoryReservationRequest{
+		{CartItemID: uuid.New(), ProductID: product, Qty: 2, Actor: inventory.NewAdjustmentActor(uuid.Nil, buyerStore)},
+		{CartItemID: uuid.New(), ProductID: product, Qty: 9, Actor: inventory.NewAdjustmentActor(uuid.Nil, buyerStore)},
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := ReserveInventory(ctx, tx, testPublisher(tx), requests)
+		return err
+	}); err != nil {
+		t.Fatalf("reserve transaction: %v", err)
+	}
+
+	var adjustments []models.InventoryAdjustment
+	if err := db.Where("product_id = ?", product).Find(&adjustments).Error; err != nil {
+		t.Fatalf("load adjustments: %v", err)
+	}
+	if len(adjustments) != 1 {
+		t.Fatalf("expected one adjustment for the successful reservation, got %d", len(adjustments))
+	}
+	adj := adjustments[0]
+	if adj.AvailableDelta != -2 || adj.ReservedDelta != 2 {
+		t.Fatalf("unexpected deltas: available=%d reserved=%d", adj.AvailableDelta, adj.ReservedDelta)
+	}
+	if adj.Reason != enums.InventoryAdjustmentReasonCheckoutReserve {
+		t.Fatalf("unexpected reason %s", adj.Reason)
+	}
+	if adj.ActorUserID != nil || adj.ActorStoreID == nil || *adj.ActorStoreID != buyerStore {
+		t.Fatalf("unexpected actor user=%v store=%v", adj.ActorUserID, adj.ActorStoreID)
+	}
+}
+
+func TestReserveInventoryEmitsInventoryChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	ctx := context.Background()
+	product := uuid.New()
+	vendorStore := uuid.New()
+	if err := db.Exec(`INSERT INTO products (id, store_id) VALUES (?, ?)`, product, vendorStore).Error; err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+	if err := db.Create(&models.InventoryItem{ProductID: product, AvailableQty: 5, ReservedQty: 1}).Error; err != nil {
+		t.Fatalf("seed inventory: %v", err)
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := ReserveInventory(ctx, tx, testPublisher(tx), []InventoryReservationRequest{{CartItemID: uuid.New(), ProductID: product, Qty: 2}})
+		return err
+	}); err != nil {
+		t.Fatalf("reserve transaction: %v", err)
+	}
+
+	var events []models.OutboxEvent
+	if err := db.Where("event_type = ?", enums.EventInventoryChanged).Find(&events).Error; err != nil {
+		t.Fatalf("load outbox events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one inventory_changed event, got %d", len(events))
+	}
+	if events[0].AggregateType != enums.AggregateProduct || events[0].AggregateID != product {
+		t.Fatalf("unexpected aggregate %s/%s", events[0].AggregateType, events[0].AggregateID)
+	}
+
+	var envelope outbox.PayloadEnvelope
+	if err := json.Unmarshal(events[0].Payload, &envelope); err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	var payload payloads.InventoryChangedEvent
+	if err := json.Unmarshal(envelope.Data, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	want := payloads.InventoryChangedEvent{
+		ProductID:      product,
+		VendorStoreID:  vendorStore,
+		AvailableQty:   3,
+		ReservedQty:    3,
+		AvailableDelta: -2,
+		ReservedDelta:  2,
+		Reason:         enums.InventoryAdjustmentReasonCheckoutReserve,
+		ChangedAt:      payload.ChangedAt,
+	}
+	if payload != want {
+		t.Fatalf("unexpected payload:\n got %+v\nwant %+v", payload, want)
+	}
+	if payload.ChangedAt.IsZero() {
+		t.Fatal("expected changed_at to be set")
+	}
+}
+
+func TestReserveAtVersionRejectsStaleVersion(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	product := uuid.New()
+	if err := db.Create(&models.InventoryItem{ProductID: product, AvailableQty: 5}).Error; err != nil {
+		t.Fatalf("seed inventory: %v", err)
+	}
+
+	stale, err := LoadInventory(db, product)
+	if err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+
+	// A concurrent checkout commits after our read.
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return reserveAtVersion(tx, product, 1, stale.Version)
+	}); err != nil {
+		t.Fatalf("concurrent reserve: %v", err)
+	}
+
+	err = reserveAtVersion(db, product, 2, stale.Version)
+	if !inventory.IsVersionConflict(err) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	fresh, err := LoadInventory(db, product)
+	if err != nil {
+		t.Fatalf("reload inventory: %v", err)
+	}
+	if err := reserveAtVersion(db, product, 2, fresh.Version); err != nil {
+		t.Fatalf("retry with fresh version: %v", err)
+	}
+
+	var inv models.InventoryItem
+	if err := db.First(&inv, "product_id = ?", product).Error; err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+	if inv.AvailableQty != 2 || inv.ReservedQty != 3 || inv.Version != stale.Version+2 {
+		t.Fatalf("unexpected inventory state: %+v", inv)
+	}
+}
+
 func TestReserveInventoryInvalidQty(t *testing.T) {
 	t.Parallel()
 
@@ -82,7 +232,7 @@ func TestReserveInventoryInvalidQty(t *testing.T) {
 		t.Fatalf("seed inventory: %v", err)
 	}
 
-	_, err := ReserveInventory(ctx, db, []InventoryReservationRequest{{ProductID: product, Qty: 0}})
+	_, err := ReserveInventory(ctx, db, testPublisher(db), []InventoryReservationRequest{{ProductID: product, Qty: 0}})
 	if err == nil {
 		t.Fatal("expected validation error")
 	}
@@ -91,6 +241,10 @@ func TestReserveInventoryInvalidQty(t *testing.T) {
 	}
 }
 
+func testPublisher(db *gorm.DB) *outbox.Service {
+	return outbox.NewService(outbox.NewRepository(db), nil)
+}
+
 func newTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	dsn := "file:reservation_" + uuid.NewString() + "?mode=memory&cache=shared"
@@ -101,5 +255,38 @@ func newTestDB(t *testing.T) *gorm.DB {
 	if err := db.AutoMigrate(&models.InventoryItem{}); err != nil {
 		t.Fatalf("migrate inventory: %v", err)
 	}
+	for _, stmt := range []string{inventoryAdjustmentsDDL, productsDDL, outboxEventsDDL} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
 	return db
 }
+
+const productsDDL = `CREATE TABLE products (id TEXT PRIMARY KEY, store_id TEXT NOT NULL)`
+
+const outboxEventsDDL = `
+CREATE TABLE outbox_events (
+  id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  event_type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at DATETIME,
+  published_at DATETIME,
+  attempt_count INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  dedup_key TEXT
+)`
+
+const inventoryAdjustmentsDDL = `
+CREATE TABLE inventory_adjustments (
+  id TEXT PRIMARY KEY,
+  product_id TEXT NOT NULL,
+  available_delta INTEGER NOT NULL,
+  reserved_delta INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  actor_user_id TEXT,
+  actor_store_id TEXT,
+  created_at DATETIME
+)`
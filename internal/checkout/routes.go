@@ -0,0 +1,172 @@
+package checkout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+// RouteEstimator computes the driving route between two coordinates.
+type RouteEstimator interface {
+	ComputeRoute(ctx context.Context, origin, destination maps.LatLng) (*maps.Route, error)
+}
+
+const defaultRouteCacheTTL = 24 * time.Hour
+
+// routeCache defines the Redis operations used to cache computed routes.
+type routeCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+}
+
+type cachedRouteEstimator struct {
+	next  RouteEstimator
+	cache routeCache
+	ttl   time.Duration
+}
+
+// NewCachedRouteEstimator caches routes per origin/destination pair in Redis so repeat checkouts between
+// the same vendor and buyer do not hit the Maps API again until the entry expires. A nil cache disables
+// caching.
+func NewCachedRouteEstimator(next RouteEstimator, cache routeCache, ttl time.Duration) RouteEstimator {
+	if next == nil {
+		return nil
+	}
+	if cache == nil {
+		return next
+	}
+	if ttl <= 0 {
+		ttl = defaultRouteCacheTTL
+	}
+	return &cachedRouteEstimator{next: next, cache: cache, ttl: ttl}
+}
+
+// ComputeRoute serves cached routes first. Cache errors are treated as misses so Redis outages only
+// cost an extra Maps call.
+func (c *cachedRouteEstimator) ComputeRoute(ctx context.Context, origin, destination maps.LatLng) (*maps.Route, error) {
+	key := routeCacheKey(origin, destination)
+	if raw, err := c.cache.Get(ctx, key); err == nil && raw != "" {
+		var cached maps.Route
+		if err := json.Unmarshal([]byte(raw), &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	route, err := c.next.ComputeRoute(ctx, origin, destination)
+	if err != nil {
+		return nil, err
+	}
+	if payload, err := json.Marshal(route); err == nil {
+		_ = c.cache.Set(ctx, key, string(payload), c.ttl)
+	}
+	return route, nil
+}
+
+// routeCacheKey rounds coordinates to roughly one meter so equivalent addresses share an entry.
+func routeCacheKey(origin, destination maps.LatLng) string {
+	return fmt.Sprintf("pf:route:%.5f,%.5f|%.5f,%.5f", origin.Latitude, origin.Longitude, destination.Latitude, destination.Longitude)
+}
+
+// estimateDelivery returns the driving distance and duration between the vendor and destination. It is
+// best-effort: missing coordinates or a Maps failure leave the estimate empty rather than failing checkout.
+func (s *service) estimateDelivery(ctx context.Context, origin, destination types.Address) (*int, *int) {
+	if s.routes == nil || !hasCoordinates(origin) || !hasCoordinates(destination) {
+		return nil, nil
+	}
+	route, err := s.routes.ComputeRoute(ctx,
+		maps.LatLng{Latitude: origin.Lat, Longitude: origin.Lng},
+		maps.LatLng{Latitude: destination.Lat, Longitude: destination.Lng},
+	)
+	if err != nil || route == nil {
+		return nil, nil
+	}
+	distance := route.DistanceMeters
+	seconds := int(route.Duration.Seconds())
+	return &distance, &seconds
+}
+
+// deliveryEstimate is a route looked up before the checkout transaction for one vendor.
+type deliveryEstimate struct {
+	origin          types.Address
+	destination     types.Address
+	distanceMeters  *int
+	durationSeconds *int
+}
+
+// planDeliveryEstimates computes the vendor-to-destination routes for the cart before checkout opens its
+// transaction, so Maps calls never run while inventory rows are locked and are not repeated when a
+// reservation retry reruns the transaction. Like estimateDelivery it is best-effort: lookup failures
+// leave the estimates empty and the transaction reports any real validation error.
+func (s *service) planDeliveryEstimates(ctx context.Context, buyerStoreID, cartID uuid.UUID, input CheckoutInput) map[uuid.UUID]deliveryEstimate {
+	if s.routes == nil {
+		return nil
+	}
+	record, err := s.cartRepo.FindByIDAndBuyerStore(ctx, cartID, buyerStoreID)
+	if err != nil || record.Status == enums.CartStatusConverted {
+		return nil
+	}
+	buyerStore, err := s.storeSvc.GetByID(ctx, buyerStoreID)
+	if err != nil {
+		return nil
+	}
+	shippingAddress := input.ShippingAddress
+	if shippingAddress == nil {
+		shippingAddress = record.ShippingAddress
+	}
+	destination := checkoutDestination(buyerStore.Address, shippingAddress)
+
+	estimates := map[uuid.UUID]deliveryEstimate{}
+	for _, item := range record.Items {
+		if ctx.Err() != nil {
+			// Out of time; checkout will fail anyway, so stop calling Maps.
+			break
+		}
+		if item.Status != enums.CartItemStatusOK {
+			continue
+		}
+		if _, ok := estimates[item.VendorStoreID]; ok {
+			continue
+		}
+		vendor, err := s.storeSvc.GetByID(ctx, item.VendorStoreID)
+		if err != nil {
+			continue
+		}
+		distance, seconds := s.estimateDelivery(ctx, vendor.Address, destination)
+		estimates[item.VendorStoreID] = deliveryEstimate{
+			origin:          vendor.Address,
+			destination:     destination,
+			distanceMeters:  distance,
+			durationSeconds: seconds,
+		}
+	}
+	return estimates
+}
+
+// deliveryEstimateFor returns the planned route for the vendor, or nothing when the vendor or destination
+// changed after planning.
+func deliveryEstimateFor(estimates map[uuid.UUID]deliveryEstimate, vendorID uuid.UUID, origin, destination types.Address) (*int, *int) {
+	estimate, ok := estimates[vendorID]
+	if !ok || !sameCoordinates(estimate.origin, origin) || !sameCoordinates(estimate.destination, destination) {
+		return nil, nil
+	}
+	return estimate.distanceMeters, estimate.durationSeconds
+}
+
+// checkoutDestination is where the order ships: the checkout or cart shipping address, falling back to
+// the buyer store address.
+func checkoutDestination(buyerAddress types.Address, shippingAddress *types.Address) types.Address {
+	if shippingAddress != nil {
+		return *shippingAddress
+	}
+	return buyerAddress
+}
+
+func sameCoordinates(a, b types.Address) bool {
+	return a.Lat == b.Lat && a.Lng == b.Lng
+}
@@ -0,0 +1,253 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type stubRouteEstimator struct {
+	route maps.Route
+	calls int
+}
+
+func (s *stubRouteEstimator) ComputeRoute(ctx context.Context, origin, destination maps.LatLng) (*maps.Route, error) {
+	s.calls++
+	route := s.route
+	return &route, nil
+}
+
+type fakeRouteCache struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newFakeRouteCache() *fakeRouteCache {
+	return &fakeRouteCache{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeRouteCache) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value, ok := f.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, nil
+}
+
+func (f *fakeRouteCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = fmt.Sprint(value)
+	f.ttls[key] = ttl
+	return nil
+}
+
+// txTrackingRunner records whether a transaction is open so tests can assert work happens outside it.
+type txTrackingRunner struct {
+	open *bool
+}
+
+func (r txTrackingRunner) WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	*r.open = true
+	defer func() { *r.open = false }()
+	return fn(nil)
+}
+
+type txAssertingRouteEstimator struct {
+	stubRouteEstimator
+	txOpen    *bool
+	callsInTx int
+}
+
+func (s *txAssertingRouteEstimator) ComputeRoute(ctx context.Context, origin, destination maps.LatLng) (*maps.Route, error) {
+	if *s.txOpen {
+		s.callsInTx++
+	}
+	return s.stubRouteEstimator.ComputeRoute(ctx, origin, destination)
+}
+
+func TestCachedRouteEstimatorReusesIdenticalPairs(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubRouteEstimator{route: maps.Route{DistanceMeters: 1200, Duration: 4 * time.Minute}}
+	cache := newFakeRouteCache()
+	estimator := NewCachedRouteEstimator(stub, cache, time.Hour)
+	origin := maps.LatLng{Latitude: 36.1540, Longitude: -95.9928}
+	destination := maps.LatLng{Latitude: 36.1000, Longitude: -95.9000}
+
+	for i := 0; i < 2; i++ {
+		route, err := estimator.ComputeRoute(context.Background(), origin, destination)
+		if err != nil {
+			t.Fatalf("compute route: %v", err)
+		}
+		if route.DistanceMeters != 1200 {
+			t.Fatalf("unexpected distance %d", route.DistanceMeters)
+		}
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected a single maps call, got %d", stub.calls)
+	}
+
+	if _, err := estimator.ComputeRoute(context.Background(), destination, origin); err != nil {
+		t.Fatalf("compute reverse route: %v", err)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected reversed pair to miss the cache, got %d calls", stub.calls)
+	}
+	for key, ttl := range cache.ttls {
+		if ttl != time.Hour {
+			t.Fatalf("expected configured ttl for %s, got %s", key, ttl)
+		}
+	}
+}
+
+func TestServicePersistsDeliveryEstimate(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	productID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productID,
+				VendorStoreID:     vendorID,
+				Quantity:          1,
+				UnitPriceCents:    1000,
+				LineSubtotalCents: 1000,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{
+				VendorStoreID: vendorID,
+				Status:        enums.VendorGroupStatusOK,
+				SubtotalCents: 1000,
+				TotalCents:    1000,
+			},
+		},
+	}
+
+	vendorAddress := types.Address{State: "OK", Lat: 36.1540, Lng: -95.9928}
+	buyerAddress := types.Address{Line1: "1 Main", City: "Tulsa", State: "OK", PostalCode: "74103", Country: "US", Lat: 36.1000, Lng: -95.9000}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   buyerAddress,
+			},
+			vendorID: {
+				ID:                 vendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            vendorAddress,
+			},
+		},
+	}
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productID: {
+				ID:       productID,
+				StoreID:  vendorID,
+				SKU:      "SKU123",
+				Category: enums.ProductCategoryFlower,
+				Unit:     enums.ProductUnitGram,
+			},
+		},
+	}
+	reserver := stubReservationRunner{
+		results: map[uuid.UUID]reservation.InventoryReservationResult{
+			cartRecord.Items[0].ID: {
+				CartItemID: cartRecord.Items[0].ID,
+				ProductID:  cartRecord.Items[0].ProductID,
+				Qty:        cartRecord.Items[0].Quantity,
+				Reserved:   true,
+			},
+		},
+	}
+	orderRepo := newStubOrdersRepository()
+	txOpen := false
+	routes := &txAssertingRouteEstimator{
+		stubRouteEstimator: stubRouteEstimator{route: maps.Route{DistanceMeters: 10500, Duration: 17 * time.Minute}},
+		txOpen:             &txOpen,
+	}
+	estimator := NewCachedRouteEstimator(routes, newFakeRouteCache(), time.Hour)
+
+	service, err := NewService(
+		txTrackingRunner{open: &txOpen},
+		cartRepo,
+		orderRepo,
+		storeSvc,
+		productLoader,
+		reserver,
+		&stubOutboxPublisher{},
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		estimator,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	result, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey: "key",
+		PaymentMethod:  enums.PaymentMethodCash,
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(result.VendorOrders) != 1 {
+		t.Fatalf("expected 1 vendor order, got %d", len(result.VendorOrders))
+	}
+
+	order := result.VendorOrders[0]
+	if order.DeliveryDistanceMeters == nil || *order.DeliveryDistanceMeters != 10500 {
+		t.Fatalf("delivery distance not persisted: %v", order.DeliveryDistanceMeters)
+	}
+	if order.DeliveryDurationSeconds == nil || *order.DeliveryDurationSeconds != 1020 {
+		t.Fatalf("delivery duration not persisted: %v", order.DeliveryDurationSeconds)
+	}
+	if routes.calls != 1 {
+		t.Fatalf("expected a single maps call, got %d", routes.calls)
+	}
+	if routes.callsInTx != 0 {
+		t.Fatalf("expected routes to be computed before the transaction, got %d calls inside it", routes.callsInTx)
+	}
+
+	origin := maps.LatLng{Latitude: vendorAddress.Lat, Longitude: vendorAddress.Lng}
+	destination := maps.LatLng{Latitude: buyerAddress.Lat, Longitude: buyerAddress.Lng}
+	if _, err := estimator.ComputeRoute(context.Background(), origin, destination); err != nil {
+		t.Fatalf("compute cached route: %v", err)
+	}
+	if routes.calls != 1 {
+		t.Fatalf("expected cached route to skip maps call, got %d calls", routes.calls)
+	}
+}
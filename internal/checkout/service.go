@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/cart"
 	"github.com/angelmondragon/packfinderz-backend/internal/checkout/helpers"
 	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/featureflags"
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/orders"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
 	"github.com/angelmondragon/packfinderz-backend/pkg/ads/token"
@@ -16,6 +19,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
@@ -40,12 +44,25 @@ type outboxPublisher interface {
 	EmitIfNotExists(ctx context.Context, tx *gorm.DB, event outbox.DomainEvent) error
 }
 
-type reservationEngine struct{}
+// featureFlags resolves runtime flags; a nil checker leaves every flag off.
+type featureFlags interface {
+	Enabled(ctx context.Context, key string, storeID *uuid.UUID) bool
+}
+
+type reservationEngine struct {
+	outbox outboxPublisher
+}
 
-func (reservationEngine) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
-	return reservation.ReserveInventory(ctx, tx, requests)
+func (e reservationEngine) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
+	return reservation.ReserveInventory(ctx, tx, e.outbox, requests)
 }
 
+// maxReservationAttempts bounds how many times checkout reruns its transaction after an inventory
+// reservation lost a version race to a concurrent checkout.
+const maxReservationAttempts = 3
+
+const outOfZoneWarningMessage = "Vendor does not deliver to this address"
+
 // Service executes checkout orchestration.
 type Service interface {
 	Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, input CheckoutInput) (*models.CheckoutGroup, error)
@@ -59,6 +76,18 @@ type CheckoutInput struct {
 	PaymentMethod   enums.PaymentMethod
 	ShippingLine    *types.ShippingLine
 	Tip             float32
+	// QuoteHash is the cart quote hash the client last saw. When set, checkout is rejected if the
+	// cart has been re-quoted to different prices since.
+	QuoteHash string
+	// ExpectedVendorTotals maps vendor store ids to the group total (in cents) the buyer confirmed.
+	// Checkout is rejected if any listed vendor group no longer totals that amount.
+	ExpectedVendorTotals map[uuid.UUID]int
+	// VendorStoreIDs limits checkout to these vendor groups. The remaining groups stay on the
+	// buyer's active cart. Empty checks out every vendor group.
+	VendorStoreIDs []uuid.UUID
+	// PaymentMethodByVendor overrides PaymentMethod for individual vendor groups, so a buyer can
+	// pay one vendor cash and another ACH. Vendors not listed use PaymentMethod.
+	PaymentMethodByVendor map[uuid.UUID]enums.PaymentMethod
 }
 
 type service struct {
@@ -70,7 +99,15 @@ type service struct {
 	reservation reservationRunner
 	outbox      outboxPublisher
 	tokenParser token.Parser
-	allowACH    bool
+	flags       featureFlags
+	shipping    ShippingRater
+	routes      RouteEstimator
+	tax         TaxCalculator
+
+	addresses       AddressNormalizer
+	strictAddresses bool
+	blockAfterHours bool
+	now             func() time.Time
 }
 
 // NewService builds the checkout service.
@@ -83,7 +120,10 @@ func NewService(
 	reservation reservationRunner,
 	publisher outboxPublisher,
 	tokenParser token.Parser,
-	allowACH bool,
+	flags featureFlags,
+	shippingRater ShippingRater,
+	routes RouteEstimator,
+	opts ...ServiceOption,
 ) (Service, error) {
 	if tx == nil {
 		return nil, fmt.Errorf("tx runner required")
@@ -100,16 +140,19 @@ func NewService(
 	if productRepo == nil {
 		return nil, fmt.Errorf("product loader required")
 	}
-	if reservation == nil {
-		reservation = reservationEngine{}
-	}
 	if publisher == nil {
 		return nil, fmt.Errorf("outbox publisher required")
 	}
+	if reservation == nil {
+		reservation = reservationEngine{outbox: publisher}
+	}
 	if tokenParser == nil {
 		return nil, fmt.Errorf("token parser required")
 	}
-	return &service{
+	if shippingRater == nil {
+		shippingRater = NewFlatRateRater(0, 0)
+	}
+	svc := &service{
 		tx:          tx,
 		cartRepo:    cartRepo,
 		ordersRepo:  ordersRepo,
@@ -118,8 +161,38 @@ func NewService(
 		reservation: reservation,
 		outbox:      publisher,
 		tokenParser: tokenParser,
-		allowACH:    allowACH,
-	}, nil
+		flags:       flags,
+		shipping:    shippingRater,
+		routes:      routes,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc, nil
+}
+
+func (s *service) achEnabled(ctx context.Context, buyerStoreID uuid.UUID) bool {
+	if s.flags == nil {
+		return false
+	}
+	return s.flags.Enabled(ctx, featureflags.FlagAllowACH, &buyerStoreID)
+}
+
+// withReservationRetry runs fn in a fresh transaction, starting over when inventory reservation hit a
+// version conflict so the retry re-reads the latest counts. Serialization failures and deadlocks are
+// retried with backoff as well.
+func (s *service) withReservationRetry(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	var err error
+	for attempt := 1; attempt <= maxReservationAttempts; attempt++ {
+		err = dbpkg.RetryTransient(ctx, func() error {
+			return s.tx.WithTx(ctx, fn)
+		})
+		if !inventory.IsVersionConflict(err) {
+			return err
+		}
+	}
+	return err
 }
 
 func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, input CheckoutInput) (*models.CheckoutGroup, error) {
@@ -134,7 +207,11 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 		result               *models.CheckoutGroup
 		vendorGroupSnapshots []models.CartVendorGroup
 	)
-	err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+	if err := s.normalizeShippingAddress(ctx, &input); err != nil {
+		return nil, err
+	}
+	estimates := s.planDeliveryEstimates(ctx, buyerStoreID, cartID, input)
+	err := s.withReservationRetry(ctx, func(tx *gorm.DB) error {
 		cartRepo := s.cartRepo.WithTx(tx)
 		ordersRepo := s.ordersRepo.WithTx(tx)
 
@@ -149,9 +226,27 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 			result, err = s.buildConvertedCheckout(ctx, buyerStoreID, record, ordersRepo)
 			return err
 		}
+		split, err := findCheckedOutSplit(ctx, cartRepo, record, input.VendorStoreIDs)
+		if err != nil {
+			return err
+		}
+		if split != nil {
+			result, err = s.buildConvertedCheckout(ctx, buyerStoreID, split, ordersRepo)
+			return err
+		}
 		if err := validateCartForCheckout(record); err != nil {
 			return err
 		}
+		if err := validateQuoteHash(record, input.QuoteHash); err != nil {
+			return err
+		}
+		if err := validateExpectedVendorTotals(record, input.ExpectedVendorTotals); err != nil {
+			return err
+		}
+		record, err = splitCartForVendors(ctx, cartRepo, record, input.VendorStoreIDs)
+		if err != nil {
+			return err
+		}
 
 		buyerStore, err := s.storeSvc.GetByID(ctx, buyerStoreID)
 		if err != nil {
@@ -172,12 +267,54 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 			return pkgerrors.New(pkgerrors.CodeConflict, "cart contains no orderable items")
 		}
 
+		productCache := map[uuid.UUID]*models.Product{}
+		if err := s.validateCurrency(ctx, record, buyerStore, eligibleItems, productCache); err != nil {
+			return err
+		}
+
+		appliedShippingAddress := input.ShippingAddress
+		if appliedShippingAddress == nil {
+			appliedShippingAddress = record.ShippingAddress
+		}
+		destination := checkoutDestination(buyerStore.Address, appliedShippingAddress)
+
+		// Drop out-of-zone vendors and reject (in block mode) closed ones before any inventory is reserved.
+		vendorCache := map[uuid.UUID]*stores.StoreDTO{}
+		vendorOpensAt := map[uuid.UUID]*time.Time{}
+		placedAt := s.now().UTC()
+		grouped := helpers.GroupCartItemsByVendor(eligibleItems)
+		for vendorID := range grouped {
+			vendor, err := s.loadVendorStore(ctx, vendorID, buyerState, vendorCache)
+			if err != nil {
+				return err
+			}
+			if !helpers.InDeliveryZone(vendor, destination) {
+				excludeOutOfZoneGroup(record, vendorID)
+				delete(grouped, vendorID)
+				continue
+			}
+			if vendorOpensAt[vendorID], err = s.vendorOpensAt(vendor, placedAt); err != nil {
+				return err
+			}
+		}
+		if len(grouped) == 0 {
+			return pkgerrors.New(pkgerrors.CodeValidation, "shipping address is outside every vendor delivery zone")
+		}
+		inZoneItems := eligibleItems[:0:0]
+		for _, item := range eligibleItems {
+			if _, ok := grouped[item.VendorStoreID]; ok {
+				inZoneItems = append(inZoneItems, item)
+			}
+		}
+		eligibleItems = inZoneItems
+
 		requests := make([]reservation.InventoryReservationRequest, len(eligibleItems))
 		for i, item := range eligibleItems {
 			requests[i] = reservation.InventoryReservationRequest{
 				CartItemID: item.ID,
 				ProductID:  item.ProductID,
 				Qty:        item.Quantity,
+				Actor:      inventory.NewAdjustmentActor(uuid.Nil, buyerStoreID),
 			}
 		}
 
@@ -190,9 +327,6 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 			reservationMap[res.CartItemID] = res
 		}
 
-		productCache := map[uuid.UUID]*models.Product{}
-		vendorCache := map[uuid.UUID]*stores.StoreDTO{}
-		grouped := helpers.GroupCartItemsByVendor(eligibleItems)
 		vendorGroups := map[uuid.UUID]models.CartVendorGroup{}
 		for _, group := range record.VendorGroups {
 			vendorGroups[group.VendorStoreID] = group
@@ -202,22 +336,24 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 		vendorOrderIDs := make([]uuid.UUID, 0, len(grouped))
 		vendorStoreIDs := make(map[uuid.UUID]struct{}, len(grouped))
 
-		appliedShippingAddress := input.ShippingAddress
-		if appliedShippingAddress == nil {
-			appliedShippingAddress = record.ShippingAddress
-		}
 		appliedPaymentMethod := input.PaymentMethod
 		if appliedPaymentMethod == "" {
 			appliedPaymentMethod = enums.PaymentMethodCash
 		}
-		if appliedPaymentMethod == enums.PaymentMethodACH && !s.allowACH {
+		if appliedPaymentMethod == enums.PaymentMethodACH && !s.achEnabled(ctx, buyerStoreID) {
 			return pkgerrors.New(pkgerrors.CodeValidation, "ach payments are disabled")
 		}
-		intentStatus := enums.PaymentStatusUnpaid
-		if appliedPaymentMethod == enums.PaymentMethodACH {
-			intentStatus = enums.PaymentStatusPending
+		vendorPaymentMethods, err := resolveVendorPaymentMethods(record, appliedPaymentMethod, input.PaymentMethodByVendor)
+		if err != nil {
+			return err
+		}
+		if err := s.validateVendorPaymentMethods(ctx, buyerStoreID, vendorPaymentMethods); err != nil {
+			return err
 		}
 		appliedShippingLine := input.ShippingLine
+		if appliedShippingLine == nil {
+			appliedShippingLine = record.ShippingLine
+		}
 
 		appliedBillingAddress := input.BillingAddress
 		if appliedBillingAddress == nil {
@@ -247,7 +383,8 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 		}
 
 		for vendorID, items := range grouped {
-			if _, err := s.loadVendorStore(ctx, vendorID, buyerState, vendorCache); err != nil {
+			vendor, err := s.loadVendorStore(ctx, vendorID, buyerState, vendorCache)
+			if err != nil {
 				return err
 			}
 
@@ -256,29 +393,47 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 				return pkgerrors.New(pkgerrors.CodeInternal, fmt.Sprintf("missing vendor group for vendor %s", vendorID))
 			}
 			orderTotals := computeVendorOrderTotals(items, reservationMap)
+			vendorPaymentMethod := vendorPaymentMethods[vendorID]
+			vendorShippingLine := appliedShippingLine
+			transportFeeCents := 0
+			taxCents := 0
+			if orderTotals.HasReserved {
+				taxCents = s.taxCents(buyerStore, orderTotals.TotalCents, placedAt)
+				vendorShippingLine, err = s.vendorShippingLine(ctx, cartGroup, record.ShippingAddress, vendor, destination, orderTotals.SubtotalCents, appliedShippingLine)
+				if err != nil {
+					return err
+				}
+				transportFeeCents = vendorShippingLine.PriceCents
+				orderTotals.TotalCents += transportFeeCents + taxCents
+			}
 			storeToken := storeTokens[vendorID]
 
 			var createdOrder *models.VendorOrder
 			if existingOrder, ok := existingOrdersByVendor[vendorID]; ok {
 				createdOrder = existingOrder
 			} else {
+				distanceMeters, durationSeconds := deliveryEstimateFor(estimates, vendorID, vendor.Address, destination)
 				newOrder := &models.VendorOrder{
-					CartID:            record.ID,
-					CheckoutGroupID:   *checkoutGroupID,
-					BuyerStoreID:      buyerStoreID,
-					VendorStoreID:     vendorID,
-					Currency:          record.Currency,
-					ShippingAddress:   appliedShippingAddress,
-					SubtotalCents:     orderTotals.SubtotalCents,
-					DiscountsCents:    orderTotals.DiscountsCents,
-					TaxCents:          0,
-					TransportFeeCents: 0,
-					PaymentMethod:     appliedPaymentMethod,
-					TotalCents:        orderTotals.TotalCents,
-					BalanceDueCents:   orderTotals.TotalCents,
-					Warnings:          cartGroup.Warnings,
-					Promo:             cartGroup.Promo,
-					ShippingLine:      appliedShippingLine,
+					CartID:                  record.ID,
+					CheckoutGroupID:         *checkoutGroupID,
+					BuyerStoreID:            buyerStoreID,
+					VendorStoreID:           vendorID,
+					Status:                  enums.VendorOrderStatusCreatedPending,
+					Currency:                record.Currency,
+					ShippingAddress:         appliedShippingAddress,
+					SubtotalCents:           orderTotals.SubtotalCents,
+					DiscountsCents:          orderTotals.DiscountsCents,
+					TaxCents:                taxCents,
+					TransportFeeCents:       transportFeeCents,
+					PaymentMethod:           vendorPaymentMethod,
+					TotalCents:              orderTotals.TotalCents,
+					BalanceDueCents:         orderTotals.TotalCents,
+					Warnings:                cartGroup.Warnings,
+					Promo:                   cartGroup.Promo,
+					ShippingLine:            vendorShippingLine,
+					DeliveryDistanceMeters:  distanceMeters,
+					DeliveryDurationSeconds: durationSeconds,
+					OpensAt:                 vendorOpensAt[vendorID],
 				}
 				if storeToken != nil {
 					tokenValue := storeToken.Raw
@@ -307,6 +462,9 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 					lineToken := productTokens[item.ProductID]
 					lineItems = append(lineItems, buildLineItem(createdOrder.ID, item, product, result, lineToken))
 				}
+				if err := checkLineItemTotals(lineItems, quotedMerchandiseCents(cartGroup, items, reservationMap)); err != nil {
+					return err
+				}
 
 				if err := ordersRepo.CreateOrderLineItems(ctx, lineItems); err != nil {
 					return err
@@ -321,14 +479,19 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 					}
 					createdOrder.Status = enums.VendorOrderStatusRejected
 					createdOrder.BalanceDueCents = 0
+				} else if vendor.AutoAccept && createdOrder.Status == enums.VendorOrderStatusCreatedPending && createdOrder.OpensAt == nil {
+					// Orders queued outside business hours wait for the vendor to accept them after opening.
+					if err := s.autoAcceptOrder(ctx, tx, ordersRepo, createdOrder); err != nil {
+						return err
+					}
 				}
 			}
 
 			if createdOrder.PaymentIntent == nil {
 				intent := &models.PaymentIntent{
 					OrderID:     createdOrder.ID,
-					Method:      appliedPaymentMethod,
-					Status:      intentStatus,
+					Method:      vendorPaymentMethod,
+					Status:      initialIntentStatus(vendorPaymentMethod),
 					AmountCents: orderTotals.TotalCents,
 				}
 				if _, err := ordersRepo.CreatePaymentIntent(ctx, intent); err != nil {
@@ -366,6 +529,10 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 			VendorOrders:     orderRecords,
 			CartVendorGroups: vendorGroupSnapshots,
 		}
+		// Past the deadline the client may already hold a 504; roll back instead of committing.
+		if err := ctx.Err(); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeTimeout, err, "checkout deadline exceeded")
+		}
 		return nil
 	})
 	if err != nil {
@@ -374,6 +541,18 @@ func (s *service) Execute(ctx context.Context, buyerStoreID, cartID uuid.UUID, i
 	return result, nil
 }
 
+// vendorShippingLine charges the shipping line the cart quote priced for the vendor group. The line is
+// rated again only when the group was quoted without one, or the buyer has since moved the destination
+// or asked for a different line.
+func (s *service) vendorShippingLine(ctx context.Context, group models.CartVendorGroup, quotedAddress *types.Address, vendor *stores.StoreDTO, destination types.Address, subtotalCents int, requested *types.ShippingLine) (*types.ShippingLine, error) {
+	if quoted := group.ShippingLine; quoted != nil && quotedAddress != nil && sameCoordinates(*quotedAddress, destination) &&
+		(requested == nil || strings.TrimSpace(requested.Code) == "" || requested.Code == quoted.Code) {
+		line := *quoted
+		return &line, nil
+	}
+	return NewShippingQuoter(s.shipping).QuoteShipping(ctx, vendor, destination, subtotalCents, requested)
+}
+
 func (s *service) loadVendorStore(ctx context.Context, vendorID uuid.UUID, buyerState string, cache map[uuid.UUID]*stores.StoreDTO) (*stores.StoreDTO, error) {
 	if vendor, ok := cache[vendorID]; ok {
 		return vendor, nil
@@ -389,6 +568,38 @@ func (s *service) loadVendorStore(ctx context.Context, vendorID uuid.UUID, buyer
 	return vendor, nil
 }
 
+// validateCurrency rejects carts whose currency no longer matches the buyer
+// store or any of the products being ordered.
+func (s *service) validateCurrency(ctx context.Context, record *models.CartRecord, buyerStore *stores.StoreDTO, items []models.CartItem, cache map[uuid.UUID]*models.Product) error {
+	if storeCurrency := currencyOrDefault(buyerStore.Currency); record.Currency != storeCurrency {
+		return pkgerrors.New(pkgerrors.CodeValidation, "cart currency does not match buyer store currency").WithDetails(map[string]any{
+			"cart_currency":  record.Currency,
+			"store_currency": storeCurrency,
+		})
+	}
+	for _, item := range items {
+		product, err := s.loadProduct(ctx, item.ProductID, cache)
+		if err != nil {
+			return err
+		}
+		if productCurrency := currencyOrDefault(product.Currency); productCurrency != record.Currency {
+			return pkgerrors.New(pkgerrors.CodeValidation, "cart items must share the buyer store currency").WithDetails(map[string]any{
+				"product_id":       product.ID,
+				"product_currency": productCurrency,
+				"cart_currency":    record.Currency,
+			})
+		}
+	}
+	return nil
+}
+
+func currencyOrDefault(currency enums.Currency) enums.Currency {
+	if currency.IsValid() {
+		return currency
+	}
+	return enums.CurrencyUSD
+}
+
 func (s *service) loadProduct(ctx context.Context, productID uuid.UUID, cache map[uuid.UUID]*models.Product) (*models.Product, error) {
 	if product, ok := cache[productID]; ok {
 		return product, nil
@@ -415,6 +626,31 @@ func (s *service) emitOrderCreatedEvent(ctx context.Context, tx *gorm.DB, checko
 	return s.outbox.EmitIfNotExists(ctx, tx, event)
 }
 
+// autoAcceptOrder accepts a new order on behalf of a vendor that opted into auto-accept, emitting
+// the same order_decided event a manual VendorDecision would so downstream consumers see no difference.
+func (s *service) autoAcceptOrder(ctx context.Context, tx *gorm.DB, ordersRepo orders.Repository, order *models.VendorOrder) error {
+	if err := ordersRepo.UpdateVendorOrderStatus(ctx, order.ID, enums.VendorOrderStatusAccepted); err != nil {
+		return err
+	}
+	order.Status = enums.VendorOrderStatusAccepted
+	event := outbox.DomainEvent{
+		EventType:     enums.EventOrderDecided,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   order.ID,
+		Version:       1,
+		DedupKey:      outbox.DedupKey(enums.EventOrderDecided, order.ID),
+		Data: payloads.OrderDecisionEvent{
+			OrderID:         order.ID,
+			CheckoutGroupID: order.CheckoutGroupID,
+			BuyerStoreID:    order.BuyerStoreID,
+			VendorStoreID:   order.VendorStoreID,
+			Decision:        enums.VendorOrderDecisionAccept,
+			Status:          enums.VendorOrderStatusAccepted,
+		},
+	}
+	return s.outbox.Emit(ctx, tx, event)
+}
+
 func (s *service) emitCheckoutConvertedEvent(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -536,18 +772,55 @@ func uuidToString(u *uuid.UUID) string {
 	return u.String()
 }
 
-func buildLineItem(orderID uuid.UUID, cartItem models.CartItem, product *models.Product, reservation reservation.InventoryReservationResult, attributedToken *token.NormalizedToken) models.OrderLineItem {
-	total := cartItem.LineSubtotalCents
+// lineTotalCents is the discounted amount a reserved cart item contributes to its vendor order
+// total, as priced by the cart quote. Line items and order totals both use it so the lines always
+// sum to the order's merchandise total.
+func lineTotalCents(cartItem models.CartItem) int {
+	total := cartItem.LineTotalCents
 	if total == 0 {
-		total = cartItem.UnitPriceCents * cartItem.Quantity
+		subtotal := cartItem.LineSubtotalCents
+		if subtotal == 0 {
+			subtotal = cartItem.UnitPriceCents * cartItem.Quantity
+		}
+		total = subtotal - lineDiscountCents(cartItem)
 	}
-	discount := 0
-	if cartItem.AppliedVolumeDiscount != nil {
+	if total < 0 {
+		total = 0
+	}
+	return total
+}
+
+// lineDiscountCents is the volume discount the cart quote applied to a line.
+func lineDiscountCents(cartItem models.CartItem) int {
+	discount := cartItem.LineDiscountsCents
+	if discount == 0 && cartItem.AppliedVolumeDiscount != nil {
 		discount = cartItem.AppliedVolumeDiscount.AmountCents
 	}
 	if discount < 0 {
 		discount = 0
 	}
+	return discount
+}
+
+// quotedMerchandiseCents is the vendor group's discounted merchandise total from the cart quote,
+// less the quoted totals of lines that failed to reserve. Line items are checked against it so a
+// drift between the quote and the order fails checkout instead of charging a different amount.
+func quotedMerchandiseCents(group models.CartVendorGroup, items []models.CartItem, reservationMap map[uuid.UUID]reservation.InventoryReservationResult) int {
+	total := group.SubtotalCents - group.LineDiscountsCents
+	for _, item := range items {
+		if !reservationMap[item.ID].Reserved {
+			total -= lineTotalCents(item)
+		}
+	}
+	if total < 0 {
+		total = 0
+	}
+	return total
+}
+
+func buildLineItem(orderID uuid.UUID, cartItem models.CartItem, product *models.Product, reservation reservation.InventoryReservationResult, attributedToken *token.NormalizedToken) models.OrderLineItem {
+	total := lineTotalCents(cartItem)
+	discount := lineDiscountCents(cartItem)
 	status := enums.LineItemStatusPending
 	var notes *string
 	if !reservation.Reserved {
@@ -656,28 +929,14 @@ func computeVendorOrderTotals(items []models.CartItem, reservationMap map[uuid.U
 			itemSubtotal = 0
 		}
 
-		itemDiscount := 0
-		if item.AppliedVolumeDiscount != nil {
-			itemDiscount = item.AppliedVolumeDiscount.AmountCents
-		}
-		if itemDiscount < 0 {
-			itemDiscount = 0
-		}
+		itemDiscount := lineDiscountCents(item)
 		if itemDiscount > itemSubtotal {
 			itemDiscount = itemSubtotal
 		}
 
-		itemTotal := item.LineSubtotalCents
-		if itemTotal == 0 {
-			itemTotal = itemSubtotal - itemDiscount
-		}
-		if itemTotal < 0 {
-			itemTotal = 0
-		}
-
 		totals.SubtotalCents += itemSubtotal
 		totals.DiscountsCents += itemDiscount
-		totals.TotalCents += itemTotal
+		totals.TotalCents += lineTotalCents(item)
 	}
 
 	if totals.DiscountsCents > totals.SubtotalCents {
@@ -690,6 +949,23 @@ func computeVendorOrderTotals(items []models.CartItem, reservationMap map[uuid.U
 	return totals
 }
 
+// checkLineItemTotals guards the invariant that the non-rejected line items add up to the
+// discounted merchandise total the cart quote showed the buyer, so a rounding slip fails checkout
+// instead of charging a total the quote cannot explain.
+func checkLineItemTotals(items []models.OrderLineItem, merchandiseCents int) error {
+	lineTotals := make([]int, 0, len(items))
+	for _, item := range items {
+		if item.Status == enums.LineItemStatusRejected {
+			continue
+		}
+		lineTotals = append(lineTotals, item.TotalCents)
+	}
+	if err := money.CheckLineTotals(lineTotals, merchandiseCents); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeInternal, err, "vendor order totals mismatch")
+	}
+	return nil
+}
+
 func validateCartForCheckout(record *models.CartRecord) error {
 	if record == nil {
 		return pkgerrors.New(pkgerrors.CodeValidation, "cart missing")
@@ -713,6 +989,267 @@ func validateCartForCheckout(record *models.CartRecord) error {
 	return nil
 }
 
+// validateQuoteHash rejects checkout when the client's known quote no longer matches the cart.
+func validateQuoteHash(record *models.CartRecord, expected string) error {
+	if expected == "" || expected == record.QuoteHash {
+		return nil
+	}
+	return pkgerrors.New(pkgerrors.CodeConflict, "cart quote changed; re-quote before checkout").WithDetails(map[string]any{
+		"expected_quote_hash": expected,
+		"current_quote_hash":  record.QuoteHash,
+	})
+}
+
+// validateExpectedVendorTotals rejects checkout when a vendor group total differs from the amount
+// the buyer confirmed, so the buyer re-confirms instead of paying a silently changed price.
+func validateExpectedVendorTotals(record *models.CartRecord, expected map[uuid.UUID]int) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	actual := make(map[uuid.UUID]int, len(record.VendorGroups))
+	for _, group := range record.VendorGroups {
+		actual[group.VendorStoreID] = group.TotalCents
+	}
+	for vendorID, expectedTotal := range expected {
+		total, ok := actual[vendorID]
+		if !ok {
+			return pkgerrors.New(pkgerrors.CodeConflict, "vendor group not found in cart quote").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+			})
+		}
+		if total != expectedTotal {
+			return pkgerrors.New(pkgerrors.CodeConflict, "vendor group total changed; confirm the new price").WithDetails(map[string]any{
+				"vendor_store_id":      vendorID,
+				"expected_total_cents": expectedTotal,
+				"current_total_cents":  total,
+			})
+		}
+	}
+	return nil
+}
+
+// resolveVendorPaymentMethods picks the payment method for every vendor group on the cart, using the
+// buyer's per-vendor override when present and fallback otherwise. Overrides must name a vendor group
+// being checked out and a known payment method.
+func resolveVendorPaymentMethods(record *models.CartRecord, fallback enums.PaymentMethod, overrides map[uuid.UUID]enums.PaymentMethod) (map[uuid.UUID]enums.PaymentMethod, error) {
+	methods := make(map[uuid.UUID]enums.PaymentMethod, len(record.VendorGroups))
+	for _, group := range record.VendorGroups {
+		methods[group.VendorStoreID] = fallback
+	}
+	for vendorID, method := range overrides {
+		if _, ok := methods[vendorID]; !ok {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "payment method set for a vendor not in checkout").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+			})
+		}
+		if !method.IsValid() {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid payment method").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+				"payment_method":  method,
+			})
+		}
+		methods[vendorID] = method
+	}
+	return methods, nil
+}
+
+// validateVendorPaymentMethods applies the ACH feature flag to every vendor group paying by ACH.
+func (s *service) validateVendorPaymentMethods(ctx context.Context, buyerStoreID uuid.UUID, methods map[uuid.UUID]enums.PaymentMethod) error {
+	var achAllowed *bool
+	for vendorID, method := range methods {
+		if method != enums.PaymentMethodACH {
+			continue
+		}
+		if achAllowed == nil {
+			enabled := s.achEnabled(ctx, buyerStoreID)
+			achAllowed = &enabled
+		}
+		if !*achAllowed {
+			return pkgerrors.New(pkgerrors.CodeValidation, "ach payments are disabled").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+			})
+		}
+	}
+	return nil
+}
+
+// initialIntentStatus is the status a new payment intent starts in: ACH transfers are pending until
+// they settle, cash is unpaid until collected.
+func initialIntentStatus(method enums.PaymentMethod) enums.PaymentStatus {
+	if method == enums.PaymentMethodACH {
+		return enums.PaymentStatusPending
+	}
+	return enums.PaymentStatusUnpaid
+}
+
+// splitCartForVendors moves the selected vendor groups and their items onto a new cart record,
+// which checkout then converts, and leaves the other groups on the original cart so it stays
+// active. The moved rows keep their ids so reservations and line items still point at them.
+// The remaining cart's quote hash is cleared because its content no longer matches the quote.
+func splitCartForVendors(ctx context.Context, cartRepo cart.CartRepository, record *models.CartRecord, vendorIDs []uuid.UUID) (*models.CartRecord, error) {
+	if len(vendorIDs) == 0 {
+		return record, nil
+	}
+
+	groupsByVendor := make(map[uuid.UUID]models.CartVendorGroup, len(record.VendorGroups))
+	for _, group := range record.VendorGroups {
+		groupsByVendor[group.VendorStoreID] = group
+	}
+	selected := make(map[uuid.UUID]struct{}, len(vendorIDs))
+	for _, vendorID := range vendorIDs {
+		group, ok := groupsByVendor[vendorID]
+		if !ok {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "vendor group not found in cart").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+			})
+		}
+		if group.Status != enums.VendorGroupStatusOK {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "vendor group is not orderable").WithDetails(map[string]any{
+				"vendor_store_id": vendorID,
+				"status":          group.Status,
+			})
+		}
+		selected[vendorID] = struct{}{}
+	}
+	if len(selected) == len(record.VendorGroups) {
+		return record, nil
+	}
+
+	var keptItems, movedItems []models.CartItem
+	for _, item := range record.Items {
+		if _, ok := selected[item.VendorStoreID]; ok {
+			movedItems = append(movedItems, item)
+		} else {
+			keptItems = append(keptItems, item)
+		}
+	}
+	var keptGroups, movedGroups []models.CartVendorGroup
+	for _, group := range record.VendorGroups {
+		if _, ok := selected[group.VendorStoreID]; ok {
+			movedGroups = append(movedGroups, group)
+		} else {
+			keptGroups = append(keptGroups, group)
+		}
+	}
+
+	// Only one active cart may exist per buyer, so the split-off cart starts out converted;
+	// finalizeCart stamps the rest of the checkout details on it.
+	split := &models.CartRecord{
+		BuyerStoreID:    record.BuyerStoreID,
+		Status:          enums.CartStatusConverted,
+		SplitFromCartID: &record.ID,
+		ShippingAddress: record.ShippingAddress,
+		ShippingLine:    record.ShippingLine,
+		Currency:        record.Currency,
+		ValidUntil:      record.ValidUntil,
+		AdTokens:        record.AdTokens,
+	}
+	applyVendorGroupTotals(split, movedGroups)
+	split, err := cartRepo.Create(ctx, split)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear the moved rows off the active cart before re-inserting them under the split cart.
+	if err := cartRepo.ReplaceItems(ctx, record.ID, keptItems); err != nil {
+		return nil, err
+	}
+	if err := cartRepo.ReplaceVendorGroups(ctx, record.ID, keptGroups); err != nil {
+		return nil, err
+	}
+	if err := cartRepo.ReplaceItems(ctx, split.ID, movedItems); err != nil {
+		return nil, err
+	}
+	if err := cartRepo.ReplaceVendorGroups(ctx, split.ID, movedGroups); err != nil {
+		return nil, err
+	}
+
+	record.Items = keptItems
+	record.VendorGroups = keptGroups
+	record.QuoteHash = ""
+	applyVendorGroupTotals(record, keptGroups)
+	if _, err := cartRepo.Update(ctx, record); err != nil {
+		return nil, err
+	}
+
+	split.Items = movedItems
+	split.VendorGroups = movedGroups
+	return split, nil
+}
+
+// findCheckedOutSplit returns the cart an earlier partial checkout split off for exactly these vendors,
+// so a retried partial checkout replays that checkout instead of failing on groups that already left
+// the active cart. Nothing is replayed while any requested vendor is still in the active cart.
+func findCheckedOutSplit(ctx context.Context, cartRepo cart.CartRepository, record *models.CartRecord, vendorIDs []uuid.UUID) (*models.CartRecord, error) {
+	if len(vendorIDs) == 0 {
+		return nil, nil
+	}
+	requested := make(map[uuid.UUID]struct{}, len(vendorIDs))
+	for _, vendorID := range vendorIDs {
+		requested[vendorID] = struct{}{}
+	}
+	for _, group := range record.VendorGroups {
+		if _, ok := requested[group.VendorStoreID]; ok {
+			return nil, nil
+		}
+	}
+
+	splits, err := cartRepo.FindSplitsFrom(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range splits {
+		split := &splits[i]
+		if split.Status != enums.CartStatusConverted || len(split.VendorGroups) != len(requested) {
+			continue
+		}
+		matches := true
+		for _, group := range split.VendorGroups {
+			if _, ok := requested[group.VendorStoreID]; !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return split, nil
+		}
+	}
+	return nil, nil
+}
+
+func applyVendorGroupTotals(record *models.CartRecord, groups []models.CartVendorGroup) {
+	record.SubtotalCents = 0
+	record.DiscountsCents = 0
+	record.TotalCents = 0
+	for _, group := range groups {
+		record.SubtotalCents += group.SubtotalCents
+		record.DiscountsCents += group.DiscountsCents
+		record.TotalCents += group.TotalCents
+	}
+}
+
+// excludeOutOfZoneGroup marks the vendor's cart group invalid with an out_of_zone warning, so checkout
+// skips it and reports why instead of failing the whole order.
+func excludeOutOfZoneGroup(record *models.CartRecord, vendorID uuid.UUID) {
+	for i := range record.VendorGroups {
+		group := &record.VendorGroups[i]
+		if group.VendorStoreID != vendorID {
+			continue
+		}
+		group.Status = enums.VendorGroupStatusInvalid
+		for _, warning := range group.Warnings {
+			if warning.Type == enums.VendorGroupWarningTypeOutOfZone {
+				return
+			}
+		}
+		group.Warnings = append(group.Warnings, types.VendorGroupWarning{
+			Type:    enums.VendorGroupWarningTypeOutOfZone,
+			Message: outOfZoneWarningMessage,
+		})
+		return
+	}
+}
+
 func finalizeCart(record *models.CartRecord, shippingAddress, billingAddress *types.Address, tip float32, paymentMethod enums.PaymentMethod, shippingLine *types.ShippingLine) {
 	if record == nil {
 		return
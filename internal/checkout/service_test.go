@@ -4,19 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/cart"
 	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/featureflags"
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/memberships"
 	"github.com/angelmondragon/packfinderz-backend/internal/orders"
 	"github.com/angelmondragon/packfinderz-backend/internal/stores"
 	"github.com/angelmondragon/packfinderz-backend/pkg/ads/token"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
@@ -45,7 +50,9 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 				VendorStoreID:         vendorID,
 				Quantity:              2,
 				UnitPriceCents:        1500,
-				LineSubtotalCents:     2500,
+				LineSubtotalCents:     3000,
+				LineDiscountsCents:    500,
+				LineTotalCents:        2500,
 				Status:                enums.CartItemStatusOK,
 				AppliedVolumeDiscount: &types.AppliedVolumeDiscount{Label: "tier 2", AmountCents: 500},
 			},
@@ -61,10 +68,12 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 		},
 		VendorGroups: []models.CartVendorGroup{
 			{
-				VendorStoreID: vendorID,
-				Status:        enums.VendorGroupStatusOK,
-				SubtotalCents: 3000,
-				TotalCents:    2500,
+				VendorStoreID:      vendorID,
+				Status:             enums.VendorGroupStatusOK,
+				SubtotalCents:      3000,
+				LineDiscountsCents: 500,
+				DiscountsCents:     500,
+				TotalCents:         2500,
 			},
 			{
 				VendorStoreID: invalidVendorID,
@@ -143,7 +152,9 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		FlatRateRater{Code: "express", Title: "Express", PriceCents: 500},
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -186,13 +197,16 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 	if order.SubtotalCents != 3000 {
 		t.Fatalf("subtotal mismatch: got %d", order.SubtotalCents)
 	}
-	if order.TotalCents != 2500 {
+	if order.TransportFeeCents != 500 {
+		t.Fatalf("transport fee mismatch: got %d", order.TransportFeeCents)
+	}
+	if order.TotalCents != 3000 {
 		t.Fatalf("total mismatch: got %d", order.TotalCents)
 	}
 	if order.DiscountsCents != 500 {
 		t.Fatalf("discount mismatch: got %d", order.DiscountsCents)
 	}
-	if order.BalanceDueCents != 2500 {
+	if order.BalanceDueCents != 3000 {
 		t.Fatalf("balance due mismatch: %d", order.BalanceDueCents)
 	}
 
@@ -206,7 +220,7 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 	if item.DiscountCents != 500 {
 		t.Fatalf("line discount mismatch: %d", item.DiscountCents)
 	}
-	if item.LineSubtotalCents != 2500 {
+	if item.LineSubtotalCents != 3000 {
 		t.Fatalf("line subtotal mismatch: %d", item.LineSubtotalCents)
 	}
 
@@ -241,6 +255,312 @@ func TestServiceUsesVendorGroupTotals(t *testing.T) {
 	}
 }
 
+func TestServiceChargesQuotedShippingLine(t *testing.T) {
+	t.Parallel()
+
+	quotedAddress := types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US", Lat: 36.14, Lng: -95.96}
+	movedAddress := quotedAddress
+	movedAddress.Lat = 36.20
+
+	cases := []struct {
+		name      string
+		shipTo    types.Address
+		requested *types.ShippingLine
+		wantCode  string
+		wantFee   int
+	}{
+		{name: "unchanged quote", shipTo: quotedAddress, wantCode: "standard", wantFee: 300},
+		{name: "same line requested", shipTo: quotedAddress, requested: &types.ShippingLine{Code: "standard"}, wantCode: "standard", wantFee: 300},
+		{name: "destination moved", shipTo: movedAddress, wantCode: "express", wantFee: 900},
+		{name: "different line requested", shipTo: quotedAddress, requested: &types.ShippingLine{Code: "express"}, wantCode: "express", wantFee: 900},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buyerID := uuid.New()
+			vendorID := uuid.New()
+			productID := uuid.New()
+			itemID := uuid.New()
+			shippingAddress := quotedAddress
+			cartRecord := &models.CartRecord{
+				ID:              uuid.New(),
+				BuyerStoreID:    buyerID,
+				Status:          enums.CartStatusActive,
+				Currency:        enums.CurrencyUSD,
+				ValidUntil:      time.Now().Add(10 * time.Minute),
+				ShippingAddress: &shippingAddress,
+				Items: []models.CartItem{{
+					ID:                itemID,
+					ProductID:         productID,
+					VendorStoreID:     vendorID,
+					Quantity:          2,
+					UnitPriceCents:    1000,
+					LineSubtotalCents: 2000,
+					LineTotalCents:    2000,
+					Status:            enums.CartItemStatusOK,
+				}},
+				VendorGroups: []models.CartVendorGroup{{
+					VendorStoreID: vendorID,
+					Status:        enums.VendorGroupStatusOK,
+					SubtotalCents: 2000,
+					ShippingLine:  &types.ShippingLine{Code: "standard", Title: "Standard", PriceCents: 300},
+					TotalCents:    2300,
+				}},
+			}
+
+			storeSvc := &stubStoreService{
+				records: map[uuid.UUID]*stores.StoreDTO{
+					buyerID:  {ID: buyerID, Type: enums.StoreTypeBuyer, KYCStatus: enums.KYCStatusVerified, Address: types.Address{State: "OK"}},
+					vendorID: {ID: vendorID, Type: enums.StoreTypeVendor, KYCStatus: enums.KYCStatusVerified, SubscriptionActive: true, Address: types.Address{State: "OK"}},
+				},
+			}
+			productLoader := stubProductLoader{
+				products: map[uuid.UUID]*models.Product{
+					productID: {ID: productID, StoreID: vendorID, SKU: "SKU", Title: "Product", Category: enums.ProductCategoryFlower, Unit: enums.ProductUnitGram},
+				},
+			}
+			reserver := stubReservationRunner{
+				results: map[uuid.UUID]reservation.InventoryReservationResult{
+					itemID: {CartItemID: itemID, ProductID: productID, Qty: 2, Reserved: true},
+				},
+			}
+
+			// The rater now quotes a different line and price than the buyer was quoted.
+			service, err := NewService(
+				stubTxRunner{},
+				&stubCartRepo{record: cartRecord},
+				newStubOrdersRepository(),
+				storeSvc,
+				productLoader,
+				reserver,
+				&stubOutboxPublisher{},
+				newStubCheckoutTokenParser(nil),
+				nil,
+				FlatRateRater{Code: "express", Title: "Express", PriceCents: 900},
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("build service: %v", err)
+			}
+
+			shipTo := tc.shipTo
+			result, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+				IdempotencyKey:  "key",
+				ShippingAddress: &shipTo,
+				PaymentMethod:   enums.PaymentMethodCash,
+				ShippingLine:    tc.requested,
+			})
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(result.VendorOrders) != 1 {
+				t.Fatalf("expected 1 vendor order, got %d", len(result.VendorOrders))
+			}
+			order := result.VendorOrders[0]
+			if order.ShippingLine == nil || order.ShippingLine.Code != tc.wantCode || order.TransportFeeCents != tc.wantFee {
+				t.Fatalf("expected %s at %d, got %+v with fee %d", tc.wantCode, tc.wantFee, order.ShippingLine, order.TransportFeeCents)
+			}
+			if order.TotalCents != 2000+tc.wantFee {
+				t.Fatalf("expected total %d, got %d", 2000+tc.wantFee, order.TotalCents)
+			}
+		})
+	}
+}
+
+func TestServiceChecksOutSelectedVendorGroups(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorA := uuid.New()
+	vendorB := uuid.New()
+	productA := uuid.New()
+	productB := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		QuoteHash:    "quote-hash",
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productA,
+				VendorStoreID:     vendorA,
+				Quantity:          1,
+				UnitPriceCents:    1000,
+				LineSubtotalCents: 1000,
+				LineTotalCents:    1000,
+				Status:            enums.CartItemStatusOK,
+			},
+			{
+				ID:                uuid.New(),
+				ProductID:         productB,
+				VendorStoreID:     vendorB,
+				Quantity:          2,
+				UnitPriceCents:    700,
+				LineSubtotalCents: 1400,
+				LineTotalCents:    1400,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{VendorStoreID: vendorA, Status: enums.VendorGroupStatusOK, SubtotalCents: 1000, TotalCents: 1000},
+			{VendorStoreID: vendorB, Status: enums.VendorGroupStatusOK, SubtotalCents: 1400, TotalCents: 1400},
+		},
+		SubtotalCents: 2400,
+		TotalCents:    2400,
+	}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			vendorA: {
+				ID:                 vendorA,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+			vendorB: {
+				ID:                 vendorB,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productA: {ID: productA, StoreID: vendorA, SKU: "A", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+			productB: {ID: productB, StoreID: vendorB, SKU: "B", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+		},
+	}
+
+	reserver := stubReservationRunner{
+		results: map[uuid.UUID]reservation.InventoryReservationResult{},
+	}
+	for _, item := range cartRecord.Items {
+		reserver.results[item.ID] = reservation.InventoryReservationResult{
+			CartItemID: item.ID,
+			ProductID:  item.ProductID,
+			Qty:        item.Quantity,
+			Reserved:   true,
+		}
+	}
+
+	orderRepo := newStubOrdersRepository()
+	service, err := NewService(
+		stubTxRunner{},
+		cartRepo,
+		orderRepo,
+		storeSvc,
+		productLoader,
+		reserver,
+		&stubOutboxPublisher{},
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	originalCartID := cartRecord.ID
+	result, err := service.Execute(context.Background(), buyerID, originalCartID, CheckoutInput{
+		IdempotencyKey: "key",
+		PaymentMethod:  enums.PaymentMethodCash,
+		VendorStoreIDs: []uuid.UUID{vendorA},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	if len(result.VendorOrders) != 1 || result.VendorOrders[0].VendorStoreID != vendorA {
+		t.Fatalf("expected a single order for the selected vendor, got %+v", result.VendorOrders)
+	}
+	if len(cartRepo.created) != 1 {
+		t.Fatalf("expected the selected groups to move to a new cart, got %d", len(cartRepo.created))
+	}
+	converted := cartRepo.created[0]
+	if result.CartID == nil || *result.CartID != converted.ID || converted.ID == originalCartID {
+		t.Fatalf("expected checkout to convert the split cart, got %v", result.CartID)
+	}
+	if converted.Status != enums.CartStatusConverted || converted.TotalCents != 1000 {
+		t.Fatalf("unexpected converted cart: status=%s total=%d", converted.Status, converted.TotalCents)
+	}
+	if items := cartRepo.items[converted.ID]; len(items) != 1 || items[0].VendorStoreID != vendorA {
+		t.Fatalf("expected the selected vendor item on the converted cart, got %+v", items)
+	}
+	if len(result.CartVendorGroups) != 1 || result.CartVendorGroups[0].VendorStoreID != vendorA {
+		t.Fatalf("expected only the selected vendor group in the response, got %+v", result.CartVendorGroups)
+	}
+
+	if cartRecord.Status != enums.CartStatusActive {
+		t.Fatalf("expected the original cart to stay active, got %s", cartRecord.Status)
+	}
+	if items := cartRepo.items[originalCartID]; len(items) != 1 || items[0].VendorStoreID != vendorB {
+		t.Fatalf("expected the unselected vendor item to remain in the cart, got %+v", items)
+	}
+	if groups := cartRepo.groups[originalCartID]; len(groups) != 1 || groups[0].VendorStoreID != vendorB {
+		t.Fatalf("expected the unselected vendor group to remain in the cart, got %+v", groups)
+	}
+	if cartRecord.TotalCents != 1400 || cartRecord.QuoteHash != "" {
+		t.Fatalf("expected remaining cart totals to be recomputed, got total=%d hash=%q", cartRecord.TotalCents, cartRecord.QuoteHash)
+	}
+
+	// A retry of the same partial checkout (e.g. after a lost response) replays the split cart.
+	retried, err := service.Execute(context.Background(), buyerID, originalCartID, CheckoutInput{
+		IdempotencyKey: "key-retry",
+		PaymentMethod:  enums.PaymentMethodCash,
+		VendorStoreIDs: []uuid.UUID{vendorA},
+	})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if retried.ID != result.ID || len(retried.VendorOrders) != 1 || len(cartRepo.created) != 1 {
+		t.Fatalf("expected the retry to replay checkout group %s, got %s with %d orders", result.ID, retried.ID, len(retried.VendorOrders))
+	}
+}
+
+func TestSplitCartForVendorsRejectsInvalidSelection(t *testing.T) {
+	t.Parallel()
+
+	cartRecord := &models.CartRecord{
+		VendorGroups: []models.CartVendorGroup{
+			{VendorStoreID: uuid.New(), Status: enums.VendorGroupStatusOK},
+			{VendorStoreID: uuid.New(), Status: enums.VendorGroupStatusInvalid},
+		},
+	}
+	cartRepo := &stubCartRepo{}
+
+	for name, vendorID := range map[string]uuid.UUID{
+		"unknown vendor":       uuid.New(),
+		"invalid vendor group": cartRecord.VendorGroups[1].VendorStoreID,
+	} {
+		_, err := splitCartForVendors(context.Background(), cartRepo, cartRecord, []uuid.UUID{vendorID})
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+	if len(cartRepo.created) != 0 {
+		t.Fatalf("expected no cart split on invalid selection")
+	}
+}
+
 func TestServiceAttributesAdTokens(t *testing.T) {
 	t.Parallel()
 
@@ -391,7 +711,9 @@ func TestServiceAttributesAdTokens(t *testing.T) {
 		reserver,
 		publisher,
 		parser,
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -512,7 +834,9 @@ func TestServicePersistsBillingAddressAndTip(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -638,7 +962,9 @@ func TestServiceDefaultsBillingAddressToShippingAddress(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -794,7 +1120,9 @@ func TestServiceReusesExistingVendorOrderAndOutbox(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -906,7 +1234,9 @@ func TestServiceAllowsACHWhenFlagEnabled(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		true,
+		staticFlags{featureflags.FlagAllowACH: true},
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1028,7 +1358,9 @@ func TestServiceRejectsACHWhenFlagDisabled(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1049,7 +1381,354 @@ func TestServiceRejectsACHWhenFlagDisabled(t *testing.T) {
 	}
 }
 
-func TestServiceRejectsExpiredCartQuote(t *testing.T) {
+type twoVendorCheckoutFixture struct {
+	buyerID    uuid.UUID
+	vendorA    uuid.UUID
+	vendorB    uuid.UUID
+	cart       *models.CartRecord
+	storeSvc   *stubStoreService
+	products   stubProductLoader
+	reserver   stubReservationRunner
+	shipTo     *types.Address
+	orderRepo  *stubOrdersRepository
+	cartRepo   *stubCartRepo
+	outboxStub *stubOutboxPublisher
+}
+
+func newTwoVendorCheckoutFixture() twoVendorCheckoutFixture {
+	buyerID := uuid.New()
+	vendorA := uuid.New()
+	vendorB := uuid.New()
+	productA := uuid.New()
+	productB := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{ID: uuid.New(), ProductID: productA, VendorStoreID: vendorA, Quantity: 1, UnitPriceCents: 1000, LineSubtotalCents: 1000, LineTotalCents: 1000, Status: enums.CartItemStatusOK},
+			{ID: uuid.New(), ProductID: productB, VendorStoreID: vendorB, Quantity: 2, UnitPriceCents: 700, LineSubtotalCents: 1400, LineTotalCents: 1400, Status: enums.CartItemStatusOK},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{VendorStoreID: vendorA, Status: enums.VendorGroupStatusOK, SubtotalCents: 1000, TotalCents: 1000},
+			{VendorStoreID: vendorB, Status: enums.VendorGroupStatusOK, SubtotalCents: 1400, TotalCents: 1400},
+		},
+		SubtotalCents: 2400,
+		TotalCents:    2400,
+	}
+	vendor := func(id uuid.UUID) *stores.StoreDTO {
+		return &stores.StoreDTO{ID: id, Type: enums.StoreTypeVendor, KYCStatus: enums.KYCStatusVerified, SubscriptionActive: true, Address: types.Address{State: "OK"}}
+	}
+	reserver := stubReservationRunner{results: map[uuid.UUID]reservation.InventoryReservationResult{}}
+	for _, item := range cartRecord.Items {
+		reserver.results[item.ID] = reservation.InventoryReservationResult{CartItemID: item.ID, ProductID: item.ProductID, Qty: item.Quantity, Reserved: true}
+	}
+	return twoVendorCheckoutFixture{
+		buyerID: buyerID,
+		vendorA: vendorA,
+		vendorB: vendorB,
+		cart:    cartRecord,
+		storeSvc: &stubStoreService{records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {ID: buyerID, Type: enums.StoreTypeBuyer, KYCStatus: enums.KYCStatusVerified, Address: types.Address{State: "OK"}},
+			vendorA: vendor(vendorA),
+			vendorB: vendor(vendorB),
+		}},
+		products: stubProductLoader{products: map[uuid.UUID]*models.Product{
+			productA: {ID: productA, StoreID: vendorA, SKU: "A", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+			productB: {ID: productB, StoreID: vendorB, SKU: "B", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+		}},
+		reserver:   reserver,
+		shipTo:     &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+		orderRepo:  newStubOrdersRepository(),
+		cartRepo:   &stubCartRepo{record: cartRecord},
+		outboxStub: &stubOutboxPublisher{},
+	}
+}
+
+func (f twoVendorCheckoutFixture) service(t *testing.T, flags featureFlags, opts ...ServiceOption) Service {
+	t.Helper()
+	svc, err := NewService(stubTxRunner{}, f.cartRepo, f.orderRepo, f.storeSvc, f.products, f.reserver, f.outboxStub, newStubCheckoutTokenParser(nil), flags, nil, nil, opts...)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+	return svc
+}
+
+func TestServiceSplitsPaymentMethodsAcrossVendors(t *testing.T) {
+	t.Parallel()
+
+	f := newTwoVendorCheckoutFixture()
+	svc := f.service(t, staticFlags{featureflags.FlagAllowACH: true})
+
+	result, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+		IdempotencyKey:        "mixed-key",
+		ShippingAddress:       f.shipTo,
+		PaymentMethod:         enums.PaymentMethodCash,
+		PaymentMethodByVendor: map[uuid.UUID]enums.PaymentMethod{f.vendorB: enums.PaymentMethodACH},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(result.VendorOrders) != 2 {
+		t.Fatalf("expected 2 vendor orders, got %d", len(result.VendorOrders))
+	}
+
+	want := map[uuid.UUID]struct {
+		method enums.PaymentMethod
+		status enums.PaymentStatus
+	}{
+		f.vendorA: {enums.PaymentMethodCash, enums.PaymentStatusUnpaid},
+		f.vendorB: {enums.PaymentMethodACH, enums.PaymentStatusPending},
+	}
+	for _, order := range result.VendorOrders {
+		expected := want[order.VendorStoreID]
+		if order.PaymentMethod != expected.method {
+			t.Fatalf("vendor %s: expected order method %s, got %s", order.VendorStoreID, expected.method, order.PaymentMethod)
+		}
+		intent, ok := f.orderRepo.paymentIntents[order.ID]
+		if !ok {
+			t.Fatalf("payment intent missing for order %s", order.ID)
+		}
+		if intent.Method != expected.method || intent.Status != expected.status {
+			t.Fatalf("vendor %s: expected intent %s/%s, got %s/%s", order.VendorStoreID, expected.method, expected.status, intent.Method, intent.Status)
+		}
+	}
+}
+
+func TestServiceRejectsPerVendorACHWhenFlagDisabled(t *testing.T) {
+	t.Parallel()
+
+	f := newTwoVendorCheckoutFixture()
+	svc := f.service(t, nil)
+
+	_, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+		IdempotencyKey:        "mixed-key",
+		ShippingAddress:       f.shipTo,
+		PaymentMethod:         enums.PaymentMethodCash,
+		PaymentMethodByVendor: map[uuid.UUID]enums.PaymentMethod{f.vendorA: enums.PaymentMethodACH},
+	})
+	typed := pkgerrors.As(err)
+	if typed == nil || typed.Code() != pkgerrors.CodeValidation || typed.Message() != "ach payments are disabled" {
+		t.Fatalf("expected ach disabled validation error, got %v", err)
+	}
+	if len(f.orderRepo.paymentIntents) != 0 {
+		t.Fatalf("expected no payment intents when checkout is rejected")
+	}
+}
+
+func TestResolveVendorPaymentMethodsRejectsUnknownVendor(t *testing.T) {
+	t.Parallel()
+
+	record := &models.CartRecord{VendorGroups: []models.CartVendorGroup{{VendorStoreID: uuid.New()}}}
+	_, err := resolveVendorPaymentMethods(record, enums.PaymentMethodCash, map[uuid.UUID]enums.PaymentMethod{uuid.New(): enums.PaymentMethodACH})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+type stubAddressNormalizer struct {
+	err error
+}
+
+func (s stubAddressNormalizer) Normalize(ctx context.Context, addr types.Address) (types.Address, error) {
+	if s.err != nil {
+		return types.Address{}, s.err
+	}
+	addr.Line1 = strings.ToUpper(addr.Line1)
+	addr.Lat = 36.1372
+	addr.Lng = -95.9663
+	return addr, nil
+}
+
+func TestServiceValidatesShippingAddress(t *testing.T) {
+	t.Parallel()
+
+	unverifiable := pkgerrors.New(pkgerrors.CodeValidation, "address could not be verified")
+	cases := []struct {
+		name       string
+		strict     bool
+		normalizer stubAddressNormalizer
+		wantErr    bool
+		wantLat    float64
+	}{
+		{name: "strict valid", strict: true, wantLat: 36.1372},
+		{name: "strict invalid", strict: true, normalizer: stubAddressNormalizer{err: unverifiable}, wantErr: true},
+		{name: "lenient valid", wantLat: 36.1372},
+		{name: "lenient invalid", normalizer: stubAddressNormalizer{err: unverifiable}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newTwoVendorCheckoutFixture()
+			svc := f.service(t, nil, WithAddressValidation(tc.normalizer, tc.strict))
+
+			result, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+				IdempotencyKey:  "address-key",
+				ShippingAddress: f.shipTo,
+				PaymentMethod:   enums.PaymentMethodCash,
+			})
+			if tc.wantErr {
+				typed := pkgerrors.As(err)
+				if typed == nil || typed.Code() != pkgerrors.CodeValidation {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				if len(f.orderRepo.paymentIntents) != 0 {
+					t.Fatalf("expected no vendor orders for an unverifiable address")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(result.VendorOrders) != 2 {
+				t.Fatalf("expected 2 vendor orders, got %d", len(result.VendorOrders))
+			}
+			for _, order := range result.VendorOrders {
+				if order.ShippingAddress == nil {
+					t.Fatalf("vendor order %s missing shipping address", order.ID)
+				}
+				if order.ShippingAddress.Lat != tc.wantLat {
+					t.Fatalf("expected lat %v, got %v", tc.wantLat, order.ShippingAddress.Lat)
+				}
+			}
+			if f.shipTo.Lat != 0 {
+				t.Fatalf("expected caller address to be left untouched")
+			}
+		})
+	}
+}
+
+func TestNewAddressValidationOption(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewAddressValidationOption(config.ShippingConfig{AddressValidation: "off"}, nil); err != nil {
+		t.Fatalf("off: unexpected error %v", err)
+	}
+	if _, err := NewAddressValidationOption(config.ShippingConfig{AddressValidation: "strict"}, nil); err == nil {
+		t.Fatalf("expected error when strict mode has no normalizer")
+	}
+	if _, err := NewAddressValidationOption(config.ShippingConfig{AddressValidation: "sometimes"}, stubAddressNormalizer{}); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
+
+func TestServiceAppliesVendorBusinessHours(t *testing.T) {
+	t.Parallel()
+
+	weekdays := &types.BusinessHours{Timezone: "America/Chicago"}
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri"} {
+		weekdays.Windows = append(weekdays.Windows, types.BusinessHoursWindow{Day: day, Open: "09:00", Close: "17:00"})
+	}
+	// Wednesday 2026-10-14: 10:00 and 18:30 in Chicago (CDT, UTC-5).
+	inHours := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	afterHours := time.Date(2026, 10, 14, 23, 30, 0, 0, time.UTC)
+	nextOpening := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		now         time.Time
+		block       bool
+		wantErr     bool
+		wantOpensAt *time.Time
+	}{
+		{name: "in hours", now: inHours},
+		{name: "after hours queued", now: afterHours, wantOpensAt: &nextOpening},
+		{name: "after hours blocked", now: afterHours, block: true, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newTwoVendorCheckoutFixture()
+			f.storeSvc.records[f.vendorA].BusinessHours = weekdays
+			svc := f.service(t, nil, WithAfterHoursBlocking(tc.block))
+			svc.(*service).now = func() time.Time { return tc.now }
+
+			result, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+				IdempotencyKey:  "hours-key",
+				ShippingAddress: f.shipTo,
+				PaymentMethod:   enums.PaymentMethodCash,
+			})
+			if tc.wantErr {
+				typed := pkgerrors.As(err)
+				if typed == nil || typed.Code() != pkgerrors.CodeValidation || typed.Message() != "vendor is closed" {
+					t.Fatalf("expected closed vendor validation error, got %v", err)
+				}
+				details, _ := typed.Details().(map[string]any)
+				if opensAt, _ := details["opens_at"].(time.Time); !opensAt.Equal(nextOpening) {
+					t.Fatalf("expected opens_at %s in details, got %v", nextOpening, details["opens_at"])
+				}
+				if len(f.orderRepo.paymentIntents) != 0 {
+					t.Fatalf("expected no vendor orders when the vendor is closed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			for _, order := range result.VendorOrders {
+				if order.Status != enums.VendorOrderStatusCreatedPending {
+					t.Fatalf("vendor %s: expected pending order, got %s", order.VendorStoreID, order.Status)
+				}
+				if order.VendorStoreID != f.vendorA || tc.wantOpensAt == nil {
+					if order.OpensAt != nil {
+						t.Fatalf("vendor %s: expected no opens_at, got %s", order.VendorStoreID, order.OpensAt)
+					}
+					continue
+				}
+				if order.OpensAt == nil || !order.OpensAt.Equal(*tc.wantOpensAt) {
+					t.Fatalf("expected opens_at %s, got %v", tc.wantOpensAt, order.OpensAt)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceDoesNotAutoAcceptQueuedOrders(t *testing.T) {
+	t.Parallel()
+
+	f := newTwoVendorCheckoutFixture()
+	vendor := f.storeSvc.records[f.vendorA]
+	vendor.AutoAccept = true
+	vendor.BusinessHours = &types.BusinessHours{Timezone: "UTC", Windows: []types.BusinessHoursWindow{{Day: "mon", Open: "09:00", Close: "17:00"}}}
+	svc := f.service(t, nil)
+	// Sunday, so the Monday-only vendor is closed.
+	svc.(*service).now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+
+	result, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+		IdempotencyKey:  "queued-key",
+		ShippingAddress: f.shipTo,
+		PaymentMethod:   enums.PaymentMethodCash,
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	for _, order := range result.VendorOrders {
+		if order.VendorStoreID == f.vendorA && order.Status != enums.VendorOrderStatusCreatedPending {
+			t.Fatalf("expected queued order to stay pending, got %s", order.Status)
+		}
+	}
+}
+
+func TestNewAfterHoursOption(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{"", "queue", "block"} {
+		if _, err := NewAfterHoursOption(config.OrdersConfig{AfterHours: mode}); err != nil {
+			t.Fatalf("%q: unexpected error %v", mode, err)
+		}
+	}
+	if _, err := NewAfterHoursOption(config.OrdersConfig{AfterHours: "later"}); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
+
+func TestServiceRejectsOutOfZoneVendorBeforeReserving(t *testing.T) {
 	t.Parallel()
 
 	buyerID := uuid.New()
@@ -1061,15 +1740,15 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 		BuyerStoreID: buyerID,
 		Status:       enums.CartStatusActive,
 		Currency:     enums.CurrencyUSD,
-		ValidUntil:   time.Now().Add(-1 * time.Minute),
+		ValidUntil:   time.Now().Add(10 * time.Minute),
 		Items: []models.CartItem{
 			{
 				ID:                uuid.New(),
 				ProductID:         productID,
 				VendorStoreID:     vendorID,
 				Quantity:          1,
-				UnitPriceCents:    1000,
-				LineSubtotalCents: 1000,
+				UnitPriceCents:    1200,
+				LineSubtotalCents: 1200,
 				Status:            enums.CartItemStatusOK,
 			},
 		},
@@ -1077,13 +1756,12 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 			{
 				VendorStoreID: vendorID,
 				Status:        enums.VendorGroupStatusOK,
-				SubtotalCents: 1000,
-				TotalCents:    1000,
+				SubtotalCents: 1200,
+				TotalCents:    1200,
 			},
 		},
 	}
 
-	cartRepo := &stubCartRepo{record: cartRecord}
 	storeSvc := &stubStoreService{
 		records: map[uuid.UUID]*stores.StoreDTO{
 			buyerID: {
@@ -1098,6 +1776,7 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 				KYCStatus:          enums.KYCStatusVerified,
 				SubscriptionActive: true,
 				Address:            types.Address{State: "OK"},
+				DeliveryZones:      &types.DeliveryZones{States: []string{"KS"}},
 			},
 		},
 	}
@@ -1107,21 +1786,549 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 			productID: {
 				ID:       productID,
 				StoreID:  vendorID,
-				SKU:      "SKU123",
+				SKU:      "SKU-ZONE",
 				Unit:     enums.ProductUnitUnit,
 				Category: enums.ProductCategoryFlower,
 			},
 		},
 	}
 
-	reserver := stubReservationRunner{
-		results: map[uuid.UUID]reservation.InventoryReservationResult{},
-	}
-	reserver.results[cartRecord.Items[0].ID] = reservation.InventoryReservationResult{
-		CartItemID: cartRecord.Items[0].ID,
-		ProductID:  cartRecord.Items[0].ProductID,
-		Qty:        cartRecord.Items[0].Quantity,
-		Reserved:   true,
+	reserver := &countingReservationRunner{}
+	service, err := NewService(
+		stubTxRunner{},
+		&stubCartRepo{record: cartRecord},
+		newStubOrdersRepository(),
+		storeSvc,
+		productLoader,
+		reserver,
+		&stubOutboxPublisher{},
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	_, err = service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey:  "zone-key",
+		ShippingAddress: &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+	})
+	typed := pkgerrors.As(err)
+	if typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if reserver.calls != 0 {
+		t.Fatalf("expected no reservation for an out-of-zone vendor, got %d calls", reserver.calls)
+	}
+}
+
+func TestServiceSkipsOutOfZoneVendorGroups(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	inZoneVendorID := uuid.New()
+	outOfZoneVendorID := uuid.New()
+	inZoneProductID := uuid.New()
+	outOfZoneProductID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         inZoneProductID,
+				VendorStoreID:     inZoneVendorID,
+				Quantity:          1,
+				UnitPriceCents:    1200,
+				LineSubtotalCents: 1200,
+				LineTotalCents:    1200,
+				Status:            enums.CartItemStatusOK,
+			},
+			{
+				ID:                uuid.New(),
+				ProductID:         outOfZoneProductID,
+				VendorStoreID:     outOfZoneVendorID,
+				Quantity:          1,
+				UnitPriceCents:    900,
+				LineSubtotalCents: 900,
+				LineTotalCents:    900,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{VendorStoreID: inZoneVendorID, Status: enums.VendorGroupStatusOK, SubtotalCents: 1200, TotalCents: 1200},
+			{VendorStoreID: outOfZoneVendorID, Status: enums.VendorGroupStatusOK, SubtotalCents: 900, TotalCents: 900},
+		},
+	}
+
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			inZoneVendorID: {
+				ID:                 inZoneVendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+				DeliveryZones:      &types.DeliveryZones{States: []string{"OK"}},
+			},
+			outOfZoneVendorID: {
+				ID:                 outOfZoneVendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+				DeliveryZones:      &types.DeliveryZones{States: []string{"KS"}},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			inZoneProductID:    {ID: inZoneProductID, StoreID: inZoneVendorID, SKU: "SKU-IN", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+			outOfZoneProductID: {ID: outOfZoneProductID, StoreID: outOfZoneVendorID, SKU: "SKU-OUT", Unit: enums.ProductUnitUnit, Category: enums.ProductCategoryFlower},
+		},
+	}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	service, err := NewService(
+		stubTxRunner{},
+		cartRepo,
+		newStubOrdersRepository(),
+		storeSvc,
+		productLoader,
+		stubReservationRunner{},
+		&stubOutboxPublisher{},
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	result, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey:  "zone-skip-key",
+		ShippingAddress: &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(result.VendorOrders) != 1 || result.VendorOrders[0].VendorStoreID != inZoneVendorID {
+		t.Fatalf("expected only the in-zone vendor order, got %+v", result.VendorOrders)
+	}
+	var excluded *models.CartVendorGroup
+	for i := range result.CartVendorGroups {
+		if result.CartVendorGroups[i].VendorStoreID == outOfZoneVendorID {
+			excluded = &result.CartVendorGroups[i]
+		}
+	}
+	if excluded == nil || excluded.Status != enums.VendorGroupStatusInvalid {
+		t.Fatalf("expected out-of-zone group reported invalid, got %+v", excluded)
+	}
+	if len(excluded.Warnings) != 1 || excluded.Warnings[0].Type != enums.VendorGroupWarningTypeOutOfZone {
+		t.Fatalf("expected out_of_zone warning, got %+v", excluded.Warnings)
+	}
+}
+
+func TestServiceRollsBackCheckoutPastDeadline(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	productID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productID,
+				VendorStoreID:     vendorID,
+				Quantity:          1,
+				UnitPriceCents:    1200,
+				LineSubtotalCents: 1200,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{
+				VendorStoreID: vendorID,
+				Status:        enums.VendorGroupStatusOK,
+				SubtotalCents: 1200,
+				TotalCents:    1200,
+			},
+		},
+	}
+
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			vendorID: {
+				ID:                 vendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productID: {
+				ID:       productID,
+				StoreID:  vendorID,
+				SKU:      "SKU-LATE",
+				Unit:     enums.ProductUnitUnit,
+				Category: enums.ProductCategoryFlower,
+			},
+		},
+	}
+
+	service, err := NewService(
+		stubTxRunner{},
+		&stubCartRepo{record: cartRecord},
+		newStubOrdersRepository(),
+		storeSvc,
+		productLoader,
+		stubReservationRunner{},
+		&stubOutboxPublisher{},
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = service.Execute(ctx, buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey:  "late-key",
+		ShippingAddress: &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+	})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeTimeout {
+		t.Fatalf("expected timeout error so the transaction rolls back, got %v", err)
+	}
+}
+
+func TestServiceRejectsExpiredCartQuote(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	productID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(-1 * time.Minute),
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productID,
+				VendorStoreID:     vendorID,
+				Quantity:          1,
+				UnitPriceCents:    1000,
+				LineSubtotalCents: 1000,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{
+				VendorStoreID: vendorID,
+				Status:        enums.VendorGroupStatusOK,
+				SubtotalCents: 1000,
+				TotalCents:    1000,
+			},
+		},
+	}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			vendorID: {
+				ID:                 vendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productID: {
+				ID:       productID,
+				StoreID:  vendorID,
+				SKU:      "SKU123",
+				Unit:     enums.ProductUnitUnit,
+				Category: enums.ProductCategoryFlower,
+			},
+		},
+	}
+
+	reserver := stubReservationRunner{
+		results: map[uuid.UUID]reservation.InventoryReservationResult{},
+	}
+	reserver.results[cartRecord.Items[0].ID] = reservation.InventoryReservationResult{
+		CartItemID: cartRecord.Items[0].ID,
+		ProductID:  cartRecord.Items[0].ProductID,
+		Qty:        cartRecord.Items[0].Quantity,
+		Reserved:   true,
+	}
+
+	orderRepo := newStubOrdersRepository()
+	publisher := &stubOutboxPublisher{}
+
+	service, err := NewService(
+		stubTxRunner{},
+		cartRepo,
+		orderRepo,
+		storeSvc,
+		productLoader,
+		reserver,
+		publisher,
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	if _, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey: "key",
+	}); err == nil {
+		t.Fatalf("expected error for expired cart")
+	} else if typed := pkgerrors.As(err); typed == nil {
+		t.Fatalf("unexpected error type: %v", err)
+	} else if typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict code, got %s", typed.Code())
+	} else if typed.Message() != "cart quote expired" {
+		t.Fatalf("unexpected error message: %s", typed.Message())
+	}
+}
+
+func TestServiceRejectsChangedQuoteHash(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	productID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		QuoteHash:    "current-hash",
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productID,
+				VendorStoreID:     vendorID,
+				Quantity:          1,
+				UnitPriceCents:    1000,
+				LineSubtotalCents: 1000,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{
+				VendorStoreID: vendorID,
+				Status:        enums.VendorGroupStatusOK,
+				SubtotalCents: 1000,
+				TotalCents:    1000,
+			},
+		},
+	}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			vendorID: {
+				ID:                 vendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productID: {
+				ID:       productID,
+				StoreID:  vendorID,
+				SKU:      "SKU123",
+				Unit:     enums.ProductUnitUnit,
+				Category: enums.ProductCategoryFlower,
+			},
+		},
+	}
+
+	reserver := stubReservationRunner{
+		results: map[uuid.UUID]reservation.InventoryReservationResult{},
+	}
+	reserver.results[cartRecord.Items[0].ID] = reservation.InventoryReservationResult{
+		CartItemID: cartRecord.Items[0].ID,
+		ProductID:  cartRecord.Items[0].ProductID,
+		Qty:        cartRecord.Items[0].Quantity,
+		Reserved:   true,
+	}
+
+	orderRepo := newStubOrdersRepository()
+	publisher := &stubOutboxPublisher{}
+
+	service, err := NewService(
+		stubTxRunner{},
+		cartRepo,
+		orderRepo,
+		storeSvc,
+		productLoader,
+		reserver,
+		publisher,
+		newStubCheckoutTokenParser(nil),
+		nil,
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	if _, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey: "key",
+		QuoteHash:      "stale-hash",
+	}); err == nil {
+		t.Fatalf("expected error for changed quote hash")
+	} else if typed := pkgerrors.As(err); typed == nil {
+		t.Fatalf("unexpected error type: %v", err)
+	} else if typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict code, got %s", typed.Code())
+	} else if typed.Message() != "cart quote changed; re-quote before checkout" {
+		t.Fatalf("unexpected error message: %s", typed.Message())
+	}
+	if cartRecord.Status != enums.CartStatusActive {
+		t.Fatalf("expected cart to stay active, got %s", cartRecord.Status)
+	}
+}
+
+func TestServiceRejectsChangedVendorGroupTotal(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	productID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{
+				ID:                uuid.New(),
+				ProductID:         productID,
+				VendorStoreID:     vendorID,
+				Quantity:          1,
+				UnitPriceCents:    1000,
+				LineSubtotalCents: 1000,
+				Status:            enums.CartItemStatusOK,
+			},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{
+				VendorStoreID: vendorID,
+				Status:        enums.VendorGroupStatusOK,
+				SubtotalCents: 1000,
+				TotalCents:    1000,
+			},
+		},
+	}
+
+	cartRepo := &stubCartRepo{record: cartRecord}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID: {
+				ID:        buyerID,
+				Type:      enums.StoreTypeBuyer,
+				KYCStatus: enums.KYCStatusVerified,
+				Address:   types.Address{State: "OK"},
+			},
+			vendorID: {
+				ID:                 vendorID,
+				Type:               enums.StoreTypeVendor,
+				KYCStatus:          enums.KYCStatusVerified,
+				SubscriptionActive: true,
+				Address:            types.Address{State: "OK"},
+			},
+		},
+	}
+
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			productID: {
+				ID:       productID,
+				StoreID:  vendorID,
+				SKU:      "SKU123",
+				Unit:     enums.ProductUnitUnit,
+				Category: enums.ProductCategoryFlower,
+			},
+		},
+	}
+
+	reserver := stubReservationRunner{
+		results: map[uuid.UUID]reservation.InventoryReservationResult{},
+	}
+	reserver.results[cartRecord.Items[0].ID] = reservation.InventoryReservationResult{
+		CartItemID: cartRecord.Items[0].ID,
+		ProductID:  cartRecord.Items[0].ProductID,
+		Qty:        cartRecord.Items[0].Quantity,
+		Reserved:   true,
 	}
 
 	orderRepo := newStubOrdersRepository()
@@ -1136,7 +2343,9 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1144,15 +2353,23 @@ func TestServiceRejectsExpiredCartQuote(t *testing.T) {
 
 	if _, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
 		IdempotencyKey: "key",
+		// The buyer confirmed 900 but the freshly loaded vendor group totals 1000.
+		ExpectedVendorTotals: map[uuid.UUID]int{vendorID: 900},
 	}); err == nil {
-		t.Fatalf("expected error for expired cart")
+		t.Fatalf("expected error for changed vendor group total")
 	} else if typed := pkgerrors.As(err); typed == nil {
 		t.Fatalf("unexpected error type: %v", err)
 	} else if typed.Code() != pkgerrors.CodeConflict {
 		t.Fatalf("expected conflict code, got %s", typed.Code())
-	} else if typed.Message() != "cart quote expired" {
+	} else if typed.Message() != "vendor group total changed; confirm the new price" {
 		t.Fatalf("unexpected error message: %s", typed.Message())
 	}
+	if len(orderRepo.vendorOrders) != 0 {
+		t.Fatalf("expected no vendor orders, got %d", len(orderRepo.vendorOrders))
+	}
+	if cartRecord.Status != enums.CartStatusActive {
+		t.Fatalf("expected cart to stay active, got %s", cartRecord.Status)
+	}
 }
 
 func TestServiceReplaysConvertedCart(t *testing.T) {
@@ -1240,7 +2457,9 @@ func TestServiceReplaysConvertedCart(t *testing.T) {
 		stubReservationRunner{},
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1358,7 +2577,9 @@ func TestServiceRejectsVendorWhenAllReservationsFail(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1417,7 +2638,9 @@ func TestServiceAdjustsTotalsWhenSomeReservationsFail(t *testing.T) {
 		VendorStoreID:         vendorID,
 		Quantity:              2,
 		UnitPriceCents:        2000,
-		LineSubtotalCents:     3500,
+		LineSubtotalCents:     4000,
+		LineDiscountsCents:    500,
+		LineTotalCents:        3500,
 		Status:                enums.CartItemStatusOK,
 		AppliedVolumeDiscount: &types.AppliedVolumeDiscount{Label: "tier 2", AmountCents: 500},
 	}
@@ -1428,6 +2651,7 @@ func TestServiceAdjustsTotalsWhenSomeReservationsFail(t *testing.T) {
 		Quantity:          1,
 		UnitPriceCents:    1000,
 		LineSubtotalCents: 1000,
+		LineTotalCents:    1000,
 		Status:            enums.CartItemStatusOK,
 	}
 
@@ -1440,10 +2664,12 @@ func TestServiceAdjustsTotalsWhenSomeReservationsFail(t *testing.T) {
 		Items:        []models.CartItem{item1, item2},
 		VendorGroups: []models.CartVendorGroup{
 			{
-				VendorStoreID: vendorID,
-				Status:        enums.VendorGroupStatusOK,
-				SubtotalCents: 5000,
-				TotalCents:    4500,
+				VendorStoreID:      vendorID,
+				Status:             enums.VendorGroupStatusOK,
+				SubtotalCents:      5000,
+				LineDiscountsCents: 500,
+				DiscountsCents:     500,
+				TotalCents:         4500,
 			},
 		},
 	}
@@ -1518,7 +2744,9 @@ func TestServiceAdjustsTotalsWhenSomeReservationsFail(t *testing.T) {
 		reserver,
 		publisher,
 		newStubCheckoutTokenParser(nil),
-		false,
+		nil,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("build service: %v", err)
@@ -1570,6 +2798,295 @@ func TestServiceAdjustsTotalsWhenSomeReservationsFail(t *testing.T) {
 	}
 }
 
+func TestServiceAutoAcceptsOrdersForOptedInVendors(t *testing.T) {
+	t.Parallel()
+
+	buyerID := uuid.New()
+	trustedVendorID := uuid.New()
+	normalVendorID := uuid.New()
+	trustedProductID := uuid.New()
+	normalProductID := uuid.New()
+
+	cartRecord := &models.CartRecord{
+		ID:           uuid.New(),
+		BuyerStoreID: buyerID,
+		Status:       enums.CartStatusActive,
+		Currency:     enums.CurrencyUSD,
+		ValidUntil:   time.Now().Add(10 * time.Minute),
+		Items: []models.CartItem{
+			{ID: uuid.New(), ProductID: trustedProductID, VendorStoreID: trustedVendorID, Quantity: 1, UnitPriceCents: 1000, LineSubtotalCents: 1000, Status: enums.CartItemStatusOK},
+			{ID: uuid.New(), ProductID: normalProductID, VendorStoreID: normalVendorID, Quantity: 1, UnitPriceCents: 2000, LineSubtotalCents: 2000, Status: enums.CartItemStatusOK},
+		},
+		VendorGroups: []models.CartVendorGroup{
+			{VendorStoreID: trustedVendorID, Status: enums.VendorGroupStatusOK, SubtotalCents: 1000, TotalCents: 1000},
+			{VendorStoreID: normalVendorID, Status: enums.VendorGroupStatusOK, SubtotalCents: 2000, TotalCents: 2000},
+		},
+	}
+
+	vendor := func(id uuid.UUID, autoAccept bool) *stores.StoreDTO {
+		return &stores.StoreDTO{
+			ID:                 id,
+			Type:               enums.StoreTypeVendor,
+			KYCStatus:          enums.KYCStatusVerified,
+			SubscriptionActive: true,
+			Address:            types.Address{State: "OK"},
+			CompanyName:        "Vendor",
+			AutoAccept:         autoAccept,
+		}
+	}
+	storeSvc := &stubStoreService{
+		records: map[uuid.UUID]*stores.StoreDTO{
+			buyerID:         {ID: buyerID, Type: enums.StoreTypeBuyer, KYCStatus: enums.KYCStatusVerified, Address: types.Address{State: "OK"}, CompanyName: "Buyer"},
+			trustedVendorID: vendor(trustedVendorID, true),
+			normalVendorID:  vendor(normalVendorID, false),
+		},
+	}
+	productLoader := stubProductLoader{
+		products: map[uuid.UUID]*models.Product{
+			trustedProductID: {ID: trustedProductID, StoreID: trustedVendorID, SKU: "TRUST", Title: "Trusted", Category: enums.ProductCategoryFlower, Unit: enums.ProductUnitGram},
+			normalProductID:  {ID: normalProductID, StoreID: normalVendorID, SKU: "NORM", Title: "Normal", Category: enums.ProductCategoryFlower, Unit: enums.ProductUnitGram},
+		},
+	}
+	reserver := stubReservationRunner{results: map[uuid.UUID]reservation.InventoryReservationResult{}}
+	for _, item := range cartRecord.Items {
+		reserver.results[item.ID] = reservation.InventoryReservationResult{CartItemID: item.ID, ProductID: item.ProductID, Qty: item.Quantity, Reserved: true}
+	}
+
+	orderRepo := newStubOrdersRepository()
+	publisher := &stubOutboxPublisher{}
+	service, err := NewService(
+		stubTxRunner{},
+		&stubCartRepo{record: cartRecord},
+		orderRepo,
+		storeSvc,
+		productLoader,
+		reserver,
+		publisher,
+		newStubCheckoutTokenParser(nil),
+		nil,
+		FlatRateRater{Code: "express", Title: "Express", PriceCents: 500},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("build service: %v", err)
+	}
+
+	if _, err := service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+		IdempotencyKey:  "key",
+		ShippingAddress: &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+		PaymentMethod:   enums.PaymentMethodCash,
+		ShippingLine:    &types.ShippingLine{Code: "express", Title: "Express", PriceCents: 500},
+	}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	statuses := map[uuid.UUID]enums.VendorOrderStatus{}
+	orderIDs := map[uuid.UUID]uuid.UUID{}
+	for _, order := range orderRepo.vendorOrders {
+		statuses[order.VendorStoreID] = order.Status
+		orderIDs[order.VendorStoreID] = order.ID
+	}
+	if statuses[trustedVendorID] != enums.VendorOrderStatusAccepted {
+		t.Fatalf("expected auto-accept vendor order accepted, got %q", statuses[trustedVendorID])
+	}
+	if statuses[normalVendorID] != enums.VendorOrderStatusCreatedPending {
+		t.Fatalf("expected normal vendor order pending, got %q", statuses[normalVendorID])
+	}
+
+	var decided []outbox.DomainEvent
+	for _, event := range publisher.events {
+		if event.EventType == enums.EventOrderDecided {
+			decided = append(decided, event)
+		}
+	}
+	if len(decided) != 1 {
+		t.Fatalf("expected one order_decided event, got %d", len(decided))
+	}
+	payload, ok := decided[0].Data.(payloads.OrderDecisionEvent)
+	if !ok {
+		t.Fatalf("unexpected payload %T", decided[0].Data)
+	}
+	if payload.OrderID != orderIDs[trustedVendorID] || payload.Decision != enums.VendorOrderDecisionAccept || payload.Status != enums.VendorOrderStatusAccepted {
+		t.Fatalf("unexpected decision payload %+v", payload)
+	}
+}
+
+func TestVendorOrderTotalsMatchLineItems(t *testing.T) {
+	reserved := models.CartItem{
+		ID:                    uuid.New(),
+		ProductID:             uuid.New(),
+		UnitPriceCents:        333,
+		Quantity:              3,
+		LineSubtotalCents:     999,
+		LineDiscountsCents:    100,
+		LineTotalCents:        899,
+		AppliedVolumeDiscount: &types.AppliedVolumeDiscount{AmountCents: 100},
+	}
+	rejected := models.CartItem{ID: uuid.New(), ProductID: uuid.New(), UnitPriceCents: 500, Quantity: 1, LineSubtotalCents: 500, LineTotalCents: 500}
+	results := map[uuid.UUID]reservation.InventoryReservationResult{
+		reserved.ID: {CartItemID: reserved.ID, Reserved: true},
+		rejected.ID: {CartItemID: rejected.ID, Reserved: false, Reason: "insufficient_inventory"},
+	}
+	group := models.CartVendorGroup{SubtotalCents: 1499, LineDiscountsCents: 100, TotalCents: 1399}
+	items := []models.CartItem{reserved, rejected}
+
+	totals := computeVendorOrderTotals(items, results)
+	if totals.TotalCents != 899 || totals.DiscountsCents != 100 {
+		t.Fatalf("expected the discounted line total to be charged, got %+v", totals)
+	}
+	orderID := uuid.New()
+	lines := []models.OrderLineItem{
+		buildLineItem(orderID, reserved, &models.Product{Title: "A"}, results[reserved.ID], nil),
+		buildLineItem(orderID, rejected, &models.Product{Title: "B"}, results[rejected.ID], nil),
+	}
+	quoted := quotedMerchandiseCents(group, items, results)
+	if quoted != totals.TotalCents {
+		t.Fatalf("expected quoted merchandise %d to match order total %d", quoted, totals.TotalCents)
+	}
+	if err := checkLineItemTotals(lines, quoted); err != nil {
+		t.Fatalf("expected line items to match quoted total %d: %v", quoted, err)
+	}
+
+	// A line priced without its quoted discount no longer matches the quote.
+	lines[0].TotalCents = reserved.LineSubtotalCents
+	err := checkLineItemTotals(lines, quoted)
+	if err == nil {
+		t.Fatal("expected an undiscounted line to fail")
+	}
+	if pkgerrors.As(err).Code() != pkgerrors.CodeInternal {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+}
+
+func TestServiceRetriesReservationVersionConflict(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		conflicts int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "retry succeeds", conflicts: 1, wantCalls: 2},
+		{name: "attempts exhausted", conflicts: maxReservationAttempts, wantCalls: maxReservationAttempts, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buyerID := uuid.New()
+			vendorID := uuid.New()
+			productID := uuid.New()
+
+			cartRecord := &models.CartRecord{
+				ID:           uuid.New(),
+				BuyerStoreID: buyerID,
+				Status:       enums.CartStatusActive,
+				Currency:     enums.CurrencyUSD,
+				ValidUntil:   time.Now().Add(10 * time.Minute),
+				Items: []models.CartItem{
+					{
+						ID:                uuid.New(),
+						ProductID:         productID,
+						VendorStoreID:     vendorID,
+						Quantity:          2,
+						UnitPriceCents:    1500,
+						LineSubtotalCents: 3000,
+						Status:            enums.CartItemStatusOK,
+					},
+				},
+				VendorGroups: []models.CartVendorGroup{
+					{
+						VendorStoreID: vendorID,
+						Status:        enums.VendorGroupStatusOK,
+						SubtotalCents: 3000,
+						TotalCents:    3000,
+					},
+				},
+			}
+
+			storeSvc := &stubStoreService{
+				records: map[uuid.UUID]*stores.StoreDTO{
+					buyerID: {
+						ID:          buyerID,
+						Type:        enums.StoreTypeBuyer,
+						KYCStatus:   enums.KYCStatusVerified,
+						Address:     types.Address{State: "OK"},
+						CompanyName: "Buyer",
+					},
+					vendorID: {
+						ID:                 vendorID,
+						Type:               enums.StoreTypeVendor,
+						KYCStatus:          enums.KYCStatusVerified,
+						SubscriptionActive: true,
+						Address:            types.Address{State: "OK"},
+						CompanyName:        "Vendor",
+					},
+				},
+			}
+
+			productLoader := stubProductLoader{
+				products: map[uuid.UUID]*models.Product{
+					productID: {
+						ID:       productID,
+						StoreID:  vendorID,
+						SKU:      "SKU123",
+						Title:    "Test Product",
+						Category: enums.ProductCategoryFlower,
+						Unit:     enums.ProductUnitGram,
+					},
+				},
+			}
+
+			reserver := &conflictingReservationRunner{conflicts: tc.conflicts}
+			orderRepo := newStubOrdersRepository()
+
+			service, err := NewService(
+				stubTxRunner{},
+				&stubCartRepo{record: cartRecord},
+				orderRepo,
+				storeSvc,
+				productLoader,
+				reserver,
+				&stubOutboxPublisher{},
+				newStubCheckoutTokenParser(nil),
+				nil,
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("build service: %v", err)
+			}
+
+			_, err = service.Execute(context.Background(), buyerID, cartRecord.ID, CheckoutInput{
+				IdempotencyKey:  "key",
+				ShippingAddress: &types.Address{Line1: "123 Market", City: "Tulsa", State: "OK", PostalCode: "74104", Country: "US"},
+				PaymentMethod:   enums.PaymentMethodCash,
+			})
+			if reserver.calls != tc.wantCalls {
+				t.Fatalf("expected %d reservation attempts, got %d", tc.wantCalls, reserver.calls)
+			}
+			if tc.wantErr {
+				if !inventory.IsVersionConflict(err) {
+					t.Fatalf("expected version conflict, got %v", err)
+				}
+				if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeConflict {
+					t.Fatalf("expected conflict error, got %v", err)
+				}
+				if orderRepo.createOrderCalls != 0 {
+					t.Fatalf("expected no orders after exhausted retries, got %d", orderRepo.createOrderCalls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if orderRepo.createOrderCalls != 1 {
+				t.Fatalf("expected one order after retry, got %d", orderRepo.createOrderCalls)
+			}
+		})
+	}
+}
+
 func ptrString(value string) *string {
 	return &value
 }
@@ -1583,6 +3100,9 @@ func (stubTxRunner) WithTx(ctx context.Context, fn func(tx *gorm.DB) error) erro
 type stubCartRepo struct {
 	record  *models.CartRecord
 	updated *models.CartRecord
+	created []*models.CartRecord
+	items   map[uuid.UUID][]models.CartItem
+	groups  map[uuid.UUID][]models.CartVendorGroup
 }
 
 func (s *stubCartRepo) WithTx(tx *gorm.DB) cart.CartRepository {
@@ -1600,8 +3120,26 @@ func (s *stubCartRepo) FindByIDAndBuyerStore(ctx context.Context, id, buyerStore
 	return s.record, nil
 }
 
+func (s *stubCartRepo) FindSplitsFrom(ctx context.Context, sourceCartID uuid.UUID) ([]models.CartRecord, error) {
+	var splits []models.CartRecord
+	for _, record := range s.created {
+		if record.SplitFromCartID != nil && *record.SplitFromCartID == sourceCartID {
+			splits = append(splits, *record)
+		}
+	}
+	return splits, nil
+}
+
 func (s *stubCartRepo) Create(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
-	return nil, errors.New("not implemented")
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+	s.created = append(s.created, record)
+	return record, nil
+}
+
+func (s *stubCartRepo) CreateOrReuseActive(ctx context.Context, record *models.CartRecord) (*models.CartRecord, bool, error) {
+	return nil, false, errors.New("not implemented")
 }
 
 func (s *stubCartRepo) Update(ctx context.Context, record *models.CartRecord) (*models.CartRecord, error) {
@@ -1610,11 +3148,19 @@ func (s *stubCartRepo) Update(ctx context.Context, record *models.CartRecord) (*
 }
 
 func (s *stubCartRepo) ReplaceItems(ctx context.Context, cartID uuid.UUID, items []models.CartItem) error {
-	return errors.New("not implemented")
+	if s.items == nil {
+		s.items = map[uuid.UUID][]models.CartItem{}
+	}
+	s.items[cartID] = append([]models.CartItem(nil), items...)
+	return nil
 }
 
 func (s *stubCartRepo) ReplaceVendorGroups(ctx context.Context, cartID uuid.UUID, groups []models.CartVendorGroup) error {
-	return errors.New("not implemented")
+	if s.groups == nil {
+		s.groups = map[uuid.UUID][]models.CartVendorGroup{}
+	}
+	s.groups[cartID] = append([]models.CartVendorGroup(nil), groups...)
+	return nil
 }
 
 func (s *stubCartRepo) UpdateStatus(ctx context.Context, id, buyerStoreID uuid.UUID, status enums.CartStatus) error {
@@ -1656,6 +3202,18 @@ func (*stubStoreService) RemoveUser(ctx context.Context, actorID, storeID, targe
 	return errors.New("not implemented")
 }
 
+func (*stubStoreService) UpdateKYCStatus(ctx context.Context, adminID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*stores.StoreDTO, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*stubStoreService) VerifyTaxExemption(ctx context.Context, adminID, storeID uuid.UUID, review stores.TaxExemptionReview) (*stores.StoreDTO, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*stubStoreService) SearchVendors(ctx context.Context, input stores.SearchVendorsInput) (*stores.SearchVendorsResult, error) {
+	return nil, errors.New("not implemented")
+}
+
 type stubCheckoutTokenParser struct {
 	parsed map[string]token.Payload
 }
@@ -1686,6 +3244,16 @@ func (s stubProductLoader) FindByID(ctx context.Context, id uuid.UUID) (*models.
 	return nil, gorm.ErrRecordNotFound
 }
 
+type countingReservationRunner struct {
+	stubReservationRunner
+	calls int
+}
+
+func (s *countingReservationRunner) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
+	s.calls++
+	return s.stubReservationRunner.Reserve(ctx, tx, requests)
+}
+
 type stubReservationRunner struct {
 	results map[uuid.UUID]reservation.InventoryReservationResult
 }
@@ -1707,6 +3275,21 @@ func (s stubReservationRunner) Reserve(ctx context.Context, tx *gorm.DB, request
 	return results, nil
 }
 
+// conflictingReservationRunner loses the version race for the first conflicts calls, mimicking a
+// concurrent checkout that committed between our read and compare-and-swap.
+type conflictingReservationRunner struct {
+	conflicts int
+	calls     int
+}
+
+func (s *conflictingReservationRunner) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
+	s.calls++
+	if s.calls <= s.conflicts {
+		return nil, inventory.NewVersionConflictError(requests[0].ProductID)
+	}
+	return stubReservationRunner{}.Reserve(ctx, tx, requests)
+}
+
 type stubOutboxPublisher struct {
 	events []outbox.DomainEvent
 	seen   map[string]struct{}
@@ -1844,7 +3427,7 @@ func (*stubOrdersRepository) ListVendorOrders(ctx context.Context, vendorStoreID
 	return nil, errors.New("not implemented")
 }
 
-func (*stubOrdersRepository) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*orders.AgentOrderQueueList, error) {
+func (*stubOrdersRepository) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters orders.AgentQueueFilters) (*orders.AgentOrderQueueList, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -1856,6 +3439,10 @@ func (*stubOrdersRepository) ListPayoutOrders(ctx context.Context, params pagina
 	return nil, errors.New("not implemented")
 }
 
+func (*stubOrdersRepository) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(orders.AccountingExportLine) error) error {
+	return errors.New("not implemented")
+}
+
 func (*stubOrdersRepository) FindOrderDetail(ctx context.Context, orderID uuid.UUID) (*orders.OrderDetail, error) {
 	return nil, errors.New("not implemented")
 }
@@ -1868,10 +3455,35 @@ func (*stubOrdersRepository) FindVendorOrder(ctx context.Context, orderID uuid.U
 	return nil, errors.New("not implemented")
 }
 
-func (*stubOrdersRepository) UpdateVendorOrderStatus(ctx context.Context, orderID uuid.UUID, status enums.VendorOrderStatus) error {
+func (s *stubOrdersRepository) UpdateVendorOrderStatus(ctx context.Context, orderID uuid.UUID, status enums.VendorOrderStatus) error {
+	order, ok := s.vendorOrders[orderID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	order.Status = status
+	return nil
+}
+
+func (s *stubOrdersRepository) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	panic("not implemented")
+}
+
+func (*stubOrdersRepository) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	return errors.New("not implemented")
+}
+
+func (*stubOrdersRepository) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*stubOrdersRepository) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
 	return errors.New("not implemented")
 }
 
+func (*stubOrdersRepository) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (*stubOrdersRepository) UpdateOrderLineItemStatus(ctx context.Context, lineItemID uuid.UUID, status enums.LineItemStatus, notes *string) error {
 	return errors.New("not implemented")
 }
@@ -1897,3 +3509,13 @@ func (*stubOrdersRepository) UpdatePaymentIntent(ctx context.Context, orderID uu
 func (*stubOrdersRepository) UpdateOrderAssignment(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error {
 	return errors.New("not implemented")
 }
+
+func (*stubOrdersRepository) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	return false, nil
+}
+
+type staticFlags map[string]bool
+
+func (f staticFlags) Enabled(_ context.Context, key string, _ *uuid.UUID) bool {
+	return f[key]
+}
@@ -0,0 +1,177 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+const (
+	shippingModeFlat     = "flat"
+	shippingModeDistance = "distance"
+)
+
+// ShippingRateRequest carries the inputs used to price shipping for a single vendor order.
+type ShippingRateRequest struct {
+	Origin            types.Address
+	Destination       types.Address
+	SubtotalCents     int
+	MaxDistanceMeters int
+}
+
+// ShippingRater quotes the shipping lines available for a vendor order. An empty result means the
+// vendor cannot ship to the destination.
+type ShippingRater interface {
+	Rates(ctx context.Context, req ShippingRateRequest) ([]types.ShippingLine, error)
+}
+
+// FlatRateRater charges the same fee regardless of distance.
+type FlatRateRater struct {
+	Code          string
+	Title         string
+	PriceCents    int
+	FreeOverCents int
+}
+
+// NewFlatRateRater builds the default flat-rate rater.
+func NewFlatRateRater(priceCents, freeOverCents int) FlatRateRater {
+	return FlatRateRater{
+		Code:          "standard",
+		Title:         "Standard delivery",
+		PriceCents:    priceCents,
+		FreeOverCents: freeOverCents,
+	}
+}
+
+// Rates returns the single flat-rate line.
+func (r FlatRateRater) Rates(ctx context.Context, req ShippingRateRequest) ([]types.ShippingLine, error) {
+	return []types.ShippingLine{{
+		Code:       r.Code,
+		Title:      r.Title,
+		PriceCents: applyFreeShipping(r.PriceCents, r.FreeOverCents, req.SubtotalCents),
+	}}, nil
+}
+
+// DistanceRater prices delivery from the straight-line distance between the vendor and buyer.
+type DistanceRater struct {
+	Code          string
+	Title         string
+	BaseCents     int
+	PerMileCents  int
+	FreeOverCents int
+}
+
+// NewDistanceRater builds a distance-based rater charging a base fee plus a per-mile rate.
+func NewDistanceRater(baseCents, perMileCents, freeOverCents int) DistanceRater {
+	return DistanceRater{
+		Code:          "local_delivery",
+		Title:         "Local delivery",
+		BaseCents:     baseCents,
+		PerMileCents:  perMileCents,
+		FreeOverCents: freeOverCents,
+	}
+}
+
+// Rates returns a single line priced by whole miles travelled, or none when the destination lies
+// outside the vendor's delivery radius.
+func (r DistanceRater) Rates(ctx context.Context, req ShippingRateRequest) ([]types.ShippingLine, error) {
+	if !hasCoordinates(req.Origin) || !hasCoordinates(req.Destination) {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "address coordinates required for distance shipping")
+	}
+	meters := maps.DistanceMeters(
+		maps.LatLng{Latitude: req.Origin.Lat, Longitude: req.Origin.Lng},
+		maps.LatLng{Latitude: req.Destination.Lat, Longitude: req.Destination.Lng},
+	)
+	if req.MaxDistanceMeters > 0 && meters > float64(req.MaxDistanceMeters) {
+		return nil, nil
+	}
+	miles := int(math.Ceil(meters / maps.MetersPerMile))
+	price := r.BaseCents + miles*r.PerMileCents
+	return []types.ShippingLine{{
+		Code:       r.Code,
+		Title:      r.Title,
+		PriceCents: applyFreeShipping(price, r.FreeOverCents, req.SubtotalCents),
+	}}, nil
+}
+
+// ShippingQuoter prices a vendor group's shipping line with a ShippingRater. The cart quote uses it
+// so the buyer sees the fee, and checkout charges the quoted line.
+type ShippingQuoter struct {
+	rater ShippingRater
+}
+
+// NewShippingQuoter builds a quoter over rater.
+func NewShippingQuoter(rater ShippingRater) ShippingQuoter {
+	return ShippingQuoter{rater: rater}
+}
+
+// QuoteShipping rates shipping from the vendor to the destination and resolves the buyer's chosen line.
+func (q ShippingQuoter) QuoteShipping(ctx context.Context, vendor *stores.StoreDTO, destination types.Address, subtotalCents int, requested *types.ShippingLine) (*types.ShippingLine, error) {
+	rates, err := q.rater.Rates(ctx, ShippingRateRequest{
+		Origin:            vendor.Address,
+		Destination:       destination,
+		SubtotalCents:     subtotalCents,
+		MaxDistanceMeters: vendor.DeliveryRadiusMeters,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selectShippingLine(rates, requested)
+}
+
+// NewShippingRater picks the rater configured for the environment.
+func NewShippingRater(cfg config.ShippingConfig) (ShippingRater, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Mode)) {
+	case "", shippingModeFlat:
+		return NewFlatRateRater(cfg.FlatRateCents, cfg.FreeOverCents), nil
+	case shippingModeDistance:
+		return NewDistanceRater(cfg.BaseCents, cfg.PerMileCents, cfg.FreeOverCents), nil
+	default:
+		return nil, fmt.Errorf("unsupported shipping mode %q", cfg.Mode)
+	}
+}
+
+// selectShippingLine resolves the buyer's requested line against the quoted rates, always using the
+// quoted price. Without a request the cheapest line is chosen.
+func selectShippingLine(rates []types.ShippingLine, requested *types.ShippingLine) (*types.ShippingLine, error) {
+	if len(rates) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "no shipping options available for vendor")
+	}
+	if requested != nil && strings.TrimSpace(requested.Code) != "" {
+		for _, rate := range rates {
+			if rate.Code == requested.Code {
+				line := rate
+				return &line, nil
+			}
+		}
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "shipping line not available").WithDetails(map[string]any{
+			"code": requested.Code,
+		})
+	}
+	sorted := append([]types.ShippingLine(nil), rates...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PriceCents < sorted[j].PriceCents })
+	line := sorted[0]
+	return &line, nil
+}
+
+func applyFreeShipping(priceCents, freeOverCents, subtotalCents int) int {
+	if priceCents < 0 {
+		return 0
+	}
+	if freeOverCents > 0 && subtotalCents >= freeOverCents {
+		return 0
+	}
+	return priceCents
+}
+
+func hasCoordinates(addr types.Address) bool {
+	return addr.Lat != 0 || addr.Lng != 0
+}
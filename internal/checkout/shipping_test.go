@@ -0,0 +1,100 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
+)
+
+func TestFlatRateRaterAppliesFreeShippingThreshold(t *testing.T) {
+	rater := NewFlatRateRater(750, 10000)
+
+	rates, err := rater.Rates(context.Background(), ShippingRateRequest{SubtotalCents: 5000})
+	if err != nil {
+		t.Fatalf("rates: %v", err)
+	}
+	if len(rates) != 1 || rates[0].PriceCents != 750 {
+		t.Fatalf("unexpected flat rates: %+v", rates)
+	}
+
+	rates, err = rater.Rates(context.Background(), ShippingRateRequest{SubtotalCents: 10000})
+	if err != nil {
+		t.Fatalf("rates: %v", err)
+	}
+	if len(rates) != 1 || rates[0].PriceCents != 0 {
+		t.Fatalf("expected free shipping over threshold, got %+v", rates)
+	}
+}
+
+func TestDistanceRaterPricesByMile(t *testing.T) {
+	rater := NewDistanceRater(500, 100, 0)
+	// Tulsa to Oklahoma City is roughly 98 straight-line miles.
+	req := ShippingRateRequest{
+		Origin:        types.Address{Lat: 36.1540, Lng: -95.9928},
+		Destination:   types.Address{Lat: 35.4676, Lng: -97.5164},
+		SubtotalCents: 5000,
+	}
+
+	rates, err := rater.Rates(context.Background(), req)
+	if err != nil {
+		t.Fatalf("rates: %v", err)
+	}
+	if len(rates) != 1 {
+		t.Fatalf("expected one rate, got %d", len(rates))
+	}
+	if rates[0].PriceCents < 500+95*100 || rates[0].PriceCents > 500+100*100 {
+		t.Fatalf("unexpected distance price: %d", rates[0].PriceCents)
+	}
+
+	req.MaxDistanceMeters = 50000
+	rates, err = rater.Rates(context.Background(), req)
+	if err != nil {
+		t.Fatalf("rates: %v", err)
+	}
+	if len(rates) != 0 {
+		t.Fatalf("expected no rates outside delivery radius, got %+v", rates)
+	}
+}
+
+func TestDistanceRaterRequiresCoordinates(t *testing.T) {
+	rater := NewDistanceRater(500, 100, 0)
+	_, err := rater.Rates(context.Background(), ShippingRateRequest{
+		Origin:      types.Address{Lat: 36.1540, Lng: -95.9928},
+		Destination: types.Address{State: "OK"},
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestSelectShippingLineUsesQuotedPrice(t *testing.T) {
+	rates := []types.ShippingLine{
+		{Code: "express", Title: "Express", PriceCents: 1500},
+		{Code: "standard", Title: "Standard", PriceCents: 500},
+	}
+
+	line, err := selectShippingLine(rates, nil)
+	if err != nil {
+		t.Fatalf("select default: %v", err)
+	}
+	if line.Code != "standard" {
+		t.Fatalf("expected cheapest line, got %s", line.Code)
+	}
+
+	line, err = selectShippingLine(rates, &types.ShippingLine{Code: "express", PriceCents: 1})
+	if err != nil {
+		t.Fatalf("select express: %v", err)
+	}
+	if line.PriceCents != 1500 {
+		t.Fatalf("expected quoted price, got %d", line.PriceCents)
+	}
+
+	if _, err := selectShippingLine(rates, &types.ShippingLine{Code: "overnight"}); err == nil {
+		t.Fatal("expected error for unknown shipping line")
+	}
+}
@@ -0,0 +1,68 @@
+package checkout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
+)
+
+// TaxCalculator prices the sales tax owed on a vendor order's merchandise total.
+type TaxCalculator interface {
+	TaxCents(buyer *stores.StoreDTO, taxableCents int, now time.Time) int
+}
+
+// RateTaxCalculator charges a single percentage on the merchandise total. Buyers holding a verified,
+// unexpired exemption certificate pay no tax.
+type RateTaxCalculator struct {
+	RatePercent float64
+	Rounding    money.RoundingMode
+}
+
+// NewRateTaxCalculator builds a percentage calculator rounding with the configured money mode.
+func NewRateTaxCalculator(ratePercent float64, rounding money.RoundingMode) RateTaxCalculator {
+	return RateTaxCalculator{RatePercent: ratePercent, Rounding: rounding}
+}
+
+// TaxCents returns the tax on taxableCents, or zero for an exempt buyer.
+func (c RateTaxCalculator) TaxCents(buyer *stores.StoreDTO, taxableCents int, now time.Time) int {
+	if c.RatePercent <= 0 || taxableCents <= 0 || buyer.TaxExemptAt(now) {
+		return 0
+	}
+	return c.Rounding.Percent(taxableCents, c.RatePercent)
+}
+
+// WithTaxCalculator sets how checkout prices tax. Without one orders carry no tax.
+func WithTaxCalculator(calc TaxCalculator) ServiceOption {
+	return func(s *service) {
+		s.tax = calc
+	}
+}
+
+// NewTaxCalculator validates the configured tax rate and builds its calculator, so the cart quote
+// and checkout can share one.
+func NewTaxCalculator(cfg config.TaxConfig, rounding money.RoundingMode) (RateTaxCalculator, error) {
+	if cfg.RatePercent < 0 || cfg.RatePercent > 100 {
+		return RateTaxCalculator{}, fmt.Errorf("tax rate %v must be between 0 and 100 percent", cfg.RatePercent)
+	}
+	return NewRateTaxCalculator(cfg.RatePercent, rounding), nil
+}
+
+// NewTaxOption maps the configured tax rate to a service option.
+func NewTaxOption(cfg config.TaxConfig, rounding money.RoundingMode) (ServiceOption, error) {
+	calc, err := NewTaxCalculator(cfg, rounding)
+	if err != nil {
+		return nil, err
+	}
+	return WithTaxCalculator(calc), nil
+}
+
+// taxCents prices tax for one vendor order, defaulting to none when no calculator is configured.
+func (s *service) taxCents(buyer *stores.StoreDTO, taxableCents int, now time.Time) int {
+	if s.tax == nil {
+		return 0
+	}
+	return s.tax.TaxCents(buyer, taxableCents, now)
+}
@@ -0,0 +1,123 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+func checkoutTaxByVendor(t *testing.T, f twoVendorCheckoutFixture, now time.Time) map[uuid.UUID][2]int {
+	t.Helper()
+	svc := f.service(t, nil, WithTaxCalculator(NewRateTaxCalculator(10, money.RoundHalfUp)))
+	svc.(*service).now = func() time.Time { return now }
+
+	result, err := svc.Execute(context.Background(), f.buyerID, f.cart.ID, CheckoutInput{
+		IdempotencyKey:  "tax-key",
+		ShippingAddress: f.shipTo,
+		PaymentMethod:   enums.PaymentMethodCash,
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	got := map[uuid.UUID][2]int{}
+	for _, order := range result.VendorOrders {
+		intent, ok := f.orderRepo.paymentIntents[order.ID]
+		if !ok {
+			t.Fatalf("payment intent missing for order %s", order.ID)
+		}
+		if intent.AmountCents != order.TotalCents {
+			t.Fatalf("vendor %s: intent amount %d does not match order total %d", order.VendorStoreID, intent.AmountCents, order.TotalCents)
+		}
+		got[order.VendorStoreID] = [2]int{order.TaxCents, order.TotalCents}
+	}
+	return got
+}
+
+func TestServiceChargesTaxOnMerchandiseTotal(t *testing.T) {
+	t.Parallel()
+
+	f := newTwoVendorCheckoutFixture()
+	got := checkoutTaxByVendor(t, f, time.Now())
+
+	if got[f.vendorA] != [2]int{100, 1100} || got[f.vendorB] != [2]int{140, 1540} {
+		t.Fatalf("unexpected tax/total by vendor: %+v", got)
+	}
+}
+
+func TestServiceSkipsTaxForVerifiedExemptBuyer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	f := newTwoVendorCheckoutFixture()
+	certificateID := uuid.New()
+	verifiedAt := now.AddDate(0, -1, 0)
+	expiresAt := now.AddDate(1, 0, 0)
+	buyer := f.storeSvc.records[f.buyerID]
+	buyer.TaxExempt = true
+	buyer.TaxExemptCertificateMediaID = &certificateID
+	buyer.TaxExemptCertificateVerifiedAt = &verifiedAt
+	buyer.TaxExemptCertificateExpiresAt = &expiresAt
+
+	got := checkoutTaxByVendor(t, f, now)
+
+	if got[f.vendorA] != [2]int{0, 1000} || got[f.vendorB] != [2]int{0, 1400} {
+		t.Fatalf("expected exempt buyer to pay no tax, got %+v", got)
+	}
+}
+
+func TestServiceTaxesBuyerWithExpiredExemptionCertificate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	f := newTwoVendorCheckoutFixture()
+	certificateID := uuid.New()
+	verifiedAt := now.AddDate(-1, 0, 0)
+	expiresAt := now.Add(-time.Hour)
+	buyer := f.storeSvc.records[f.buyerID]
+	buyer.TaxExempt = true
+	buyer.TaxExemptCertificateMediaID = &certificateID
+	buyer.TaxExemptCertificateVerifiedAt = &verifiedAt
+	buyer.TaxExemptCertificateExpiresAt = &expiresAt
+
+	got := checkoutTaxByVendor(t, f, now)
+
+	if got[f.vendorA] != [2]int{100, 1100} || got[f.vendorB] != [2]int{140, 1540} {
+		t.Fatalf("expected expired certificate to fall back to tax, got %+v", got)
+	}
+}
+
+func TestServiceTaxesBuyerWithUnverifiedExemptionCertificate(t *testing.T) {
+	t.Parallel()
+
+	f := newTwoVendorCheckoutFixture()
+	certificateID := uuid.New()
+	buyer := f.storeSvc.records[f.buyerID]
+	buyer.TaxExempt = true
+	buyer.TaxExemptCertificateMediaID = &certificateID
+
+	got := checkoutTaxByVendor(t, f, time.Now())
+
+	if got[f.vendorA][0] != 100 || got[f.vendorB][0] != 140 {
+		t.Fatalf("expected unverified certificate to be taxed, got %+v", got)
+	}
+}
+
+func TestNewTaxOption(t *testing.T) {
+	t.Parallel()
+
+	for _, rate := range []float64{0, 8.25, 100} {
+		if _, err := NewTaxOption(config.TaxConfig{RatePercent: rate}, money.RoundHalfUp); err != nil {
+			t.Fatalf("%v: unexpected error %v", rate, err)
+		}
+	}
+	for _, rate := range []float64{-1, 101} {
+		if _, err := NewTaxOption(config.TaxConfig{RatePercent: rate}, money.RoundHalfUp); err == nil {
+			t.Fatalf("%v: expected error", rate)
+		}
+	}
+}
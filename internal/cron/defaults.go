@@ -0,0 +1,210 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/billing"
+	"github.com/angelmondragon/packfinderz-backend/internal/licenses"
+	"github.com/angelmondragon/packfinderz-backend/internal/media"
+	"github.com/angelmondragon/packfinderz-backend/internal/notifications"
+	"github.com/angelmondragon/packfinderz-backend/internal/orders"
+	product "github.com/angelmondragon/packfinderz-backend/internal/products"
+	"github.com/angelmondragon/packfinderz-backend/internal/stores"
+	"github.com/angelmondragon/packfinderz-backend/internal/subscriptions"
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/square"
+)
+
+const lockKeyFormat = "pf:cron-worker:lock:%s"
+
+// LockKey returns the Redis key that keeps scheduled and manual runs from overlapping in env.
+func LockKey(env string) string {
+	if env == "" {
+		env = "local"
+	}
+	return fmt.Sprintf(lockKeyFormat, env)
+}
+
+// DefaultRegistryParams holds what the scheduled jobs need.
+type DefaultRegistryParams struct {
+	Logger              *logger.Logger
+	DB                  *db.Client
+	Config              *config.Config
+	GCS                 gcsClient
+	Square              *square.Client
+	MediaCleanupMetrics *metrics.MediaCleanupMetrics
+	// BigQuery enables the outbox export job when the export table is configured.
+	BigQuery outboxExportWarehouse
+}
+
+// NewDefaultRegistry builds the registry the cron worker schedules and runs manual requests from.
+func NewDefaultRegistry(params DefaultRegistryParams) (*Registry, error) {
+	if params.Logger == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if params.DB == nil {
+		return nil, fmt.Errorf("db client required")
+	}
+	if params.Config == nil {
+		return nil, fmt.Errorf("config required")
+	}
+	logg := params.Logger
+	dbClient := params.DB
+	cfg := params.Config
+
+	licenseRepo := licenses.NewRepository(dbClient.DB())
+	storeRepo := stores.NewRepository(dbClient.DB())
+	mediaRepo := media.NewRepository(dbClient.DB())
+	attachmentRepo := media.NewMediaAttachmentRepository(dbClient.DB())
+	outboxRepo := outbox.NewRepository(dbClient.DB())
+	outboxSvc := outbox.NewService(outboxRepo, logg)
+
+	registry := NewRegistry()
+	licenseJob, err := NewLicenseLifecycleJob(LicenseLifecycleJobParams{
+		Logger:         logg,
+		DB:             dbClient,
+		LicenseRepo:    licenseRepo,
+		StoreRepo:      storeRepo,
+		MediaRepo:      mediaRepo,
+		AttachmentRepo: attachmentRepo,
+		Outbox:         outboxSvc,
+		OutboxRepo:     outboxRepo,
+		GCS:            params.GCS,
+		GCSBucket:      cfg.GCS.BucketName,
+		DryRun:         cfg.Cron.DryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("license job: %w", err)
+	}
+	registry.Register(licenseJob)
+
+	ordersRepo := orders.NewRepository(dbClient.DB())
+	orderTTLJob, err := NewOrderTTLJob(OrderTTLJobParams{
+		Logger:        logg,
+		DB:            dbClient,
+		PendingReader: ordersRepo,
+		Inventory:     orders.NewInventoryReleaser(outboxSvc),
+		Outbox:        outboxSvc,
+		OutboxRepo:    outboxRepo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("order ttl job: %w", err)
+	}
+	registry.Register(orderTTLJob)
+
+	if cfg.Orders.ReservationTTL > 0 {
+		reservationExpiryJob, err := NewReservationExpiryJob(ReservationExpiryJobParams{
+			Logger:        logg,
+			DB:            dbClient,
+			PendingReader: ordersRepo,
+			Inventory:     orders.NewInventoryReleaser(outboxSvc),
+			TTL:           cfg.Orders.ReservationTTL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reservation expiry job: %w", err)
+		}
+		registry.Register(reservationExpiryJob)
+	}
+
+	notificationRepo := notifications.NewRepository(dbClient.DB())
+	notificationCleanupJob, err := NewNotificationCleanupJob(NotificationCleanupJobParams{
+		Logger:        logg,
+		DB:            dbClient,
+		Repository:    notificationRepo,
+		Retention:     cfg.Notifications.RetentionDays,
+		TypeRetention: cfg.Notifications.TypeRetentionDays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("notification cleanup job: %w", err)
+	}
+	registry.Register(notificationCleanupJob)
+
+	lowStockJob, err := NewLowStockAlertJob(LowStockAlertJobParams{
+		Logger:           logg,
+		DB:               dbClient,
+		Inventory:        product.NewRepository(dbClient.DB()),
+		Notifications:    notificationRepo,
+		DefaultThreshold: cfg.Inventory.LowStockThreshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("low stock alert job: %w", err)
+	}
+	registry.Register(lowStockJob)
+
+	pendingMediaParams := PendingMediaCleanupJobParams{
+		Logger:         logg,
+		DB:             dbClient,
+		MediaRepo:      mediaRepo,
+		AttachmentRepo: attachmentRepo,
+		DryRun:         cfg.Cron.DryRun,
+	}
+	if params.MediaCleanupMetrics != nil {
+		pendingMediaParams.Metrics = params.MediaCleanupMetrics
+	}
+	pendingMediaCleanupJob, err := NewPendingMediaCleanupJob(pendingMediaParams)
+	if err != nil {
+		return nil, fmt.Errorf("pending media cleanup job: %w", err)
+	}
+	registry.Register(pendingMediaCleanupJob)
+
+	if params.GCS != nil {
+		mediaPurgeJob, err := NewMediaPurgeJob(MediaPurgeJobParams{
+			Logger:         logg,
+			DB:             dbClient,
+			MediaRepo:      mediaRepo,
+			AttachmentRepo: attachmentRepo,
+			GCS:            params.GCS,
+			GCSBucket:      cfg.GCS.BucketName,
+			DryRun:         cfg.Cron.DryRun,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("media purge job: %w", err)
+		}
+		registry.Register(mediaPurgeJob)
+	}
+
+	outboxRetentionJob, err := NewOutboxRetentionJob(OutboxRetentionJobParams{
+		Logger:     logg,
+		DB:         dbClient,
+		Repository: outboxRepo,
+		DryRun:     cfg.Cron.DryRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox retention job: %w", err)
+	}
+	registry.Register(outboxRetentionJob)
+
+	if table := strings.TrimSpace(cfg.BigQuery.OutboxExportTable); table != "" && params.BigQuery != nil {
+		outboxExportJob, err := NewOutboxExportJob(OutboxExportJobParams{
+			Logger:     logg,
+			Repository: outboxRepo,
+			Warehouse:  params.BigQuery,
+			Table:      table,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("outbox export job: %w", err)
+		}
+		registry.Register(outboxExportJob)
+	}
+
+	subscriptionJob, err := NewSubscriptionReconcileJob(SubscriptionReconcileJobParams{
+		Logger:        logg,
+		DB:            dbClient,
+		BillingRepo:   billing.NewRepository(dbClient.DB()),
+		StoreRepo:     storeRepo,
+		SquareClient:  subscriptions.NewSquareClient(params.Square, cfg.Square.LocationID),
+		Notifications: notificationRepo,
+		GracePeriod:   cfg.Square.SubscriptionGracePeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscription reconcile job: %w", err)
+	}
+	registry.Register(subscriptionJob)
+
+	return registry, nil
+}
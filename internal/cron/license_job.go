@@ -34,6 +34,8 @@ type LicenseLifecycleJobParams struct {
 	OutboxRepo     outboxExistenceChecker
 	GCS            gcsClient
 	GCSBucket      string
+	// DryRun counts the warnings, expirations, and deletions the job would make without making them.
+	DryRun bool
 }
 
 // NewLicenseLifecycleJob constructs the license lifecycle cron job.
@@ -73,6 +75,7 @@ func NewLicenseLifecycleJob(params LicenseLifecycleJobParams) (Job, error) {
 		outboxRepo:     params.OutboxRepo,
 		gcs:            params.GCS,
 		bucket:         params.GCSBucket,
+		dryRun:         params.DryRun,
 		now:            time.Now,
 	}, nil
 }
@@ -126,11 +129,14 @@ type licenseLifecycleJob struct {
 	outboxRepo     outboxExistenceChecker
 	gcs            gcsClient
 	bucket         string
+	dryRun         bool
 	now            func() time.Time
 }
 
 func (j *licenseLifecycleJob) Name() string { return "license-lifecycle" }
 
+func (j *licenseLifecycleJob) DryRun() bool { return j.dryRun }
+
 func (j *licenseLifecycleJob) Run(ctx context.Context) error {
 	var errs []error
 	if err := j.warnExpiring(ctx); err != nil {
@@ -166,6 +172,10 @@ func (j *licenseLifecycleJob) warnExpiring(ctx context.Context) error {
 		if exists {
 			continue
 		}
+		if j.dryRun {
+			count++
+			continue
+		}
 		if err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
 			event := outbox.DomainEvent{
 				EventType:     enums.EventLicenseExpiringSoon,
@@ -186,7 +196,7 @@ func (j *licenseLifecycleJob) warnExpiring(ctx context.Context) error {
 		}
 		count++
 	}
-	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count})
+	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count, "dry_run": j.dryRun})
 	j.logg.Info(logCtx, "license warn loop complete")
 	return nil
 }
@@ -204,12 +214,16 @@ func (j *licenseLifecycleJob) expireLicenses(ctx context.Context) error {
 		if lic.Status != enums.LicenseStatusVerified || lic.ExpirationDate == nil {
 			continue
 		}
+		if j.dryRun {
+			count++
+			continue
+		}
 		if err := j.expireLicense(ctx, lic); err != nil {
 			return err
 		}
 		count++
 	}
-	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count})
+	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count, "dry_run": j.dryRun})
 	j.logg.Info(logCtx, "license expiry loop complete")
 	return nil
 }
@@ -252,12 +266,16 @@ func (j *licenseLifecycleJob) deleteExpired(ctx context.Context) error {
 	}
 	count := 0
 	for _, lic := range licenses {
+		if j.dryRun {
+			count++
+			continue
+		}
 		if err := j.deleteLicense(ctx, lic); err != nil {
 			return err
 		}
 		count++
 	}
-	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count})
+	logCtx := j.logg.WithFields(ctx, map[string]any{"count": count, "dry_run": j.dryRun})
 	j.logg.Info(logCtx, "license hard-delete loop complete")
 	return nil
 }
@@ -96,6 +96,55 @@ func TestLicenseLifecycleJob_deleteExpiredRemovesAttachmentsAndMedia(t *testing.
 	}
 }
 
+func TestLicenseLifecycleJob_dryRunReportsWithoutWriting(t *testing.T) {
+	helper := createLicenseJobTest(t)
+	now := time.Date(2026, 1, 30, 12, 0, 0, 0, time.UTC)
+	helper.job.now = func() time.Time { return now }
+	logg, logs := newRecordedLogger()
+	helper.job.logg = logg
+	helper.job.dryRun = true
+	helper.job.bucket = "bucket"
+	helper.licenseRepo.expiring = []models.License{{
+		ID:             uuid.New(),
+		StoreID:        uuid.New(),
+		Status:         enums.LicenseStatusVerified,
+		ExpirationDate: ptrTime(now.Add(expiryWarningDays * 24 * time.Hour)),
+	}}
+	helper.licenseRepo.expiredRange = []models.License{{
+		ID:             uuid.New(),
+		StoreID:        uuid.New(),
+		Status:         enums.LicenseStatusVerified,
+		ExpirationDate: ptrTime(now.Add(-24 * time.Hour)),
+	}}
+	helper.licenseRepo.expiredBefore = []models.License{{
+		ID:             uuid.New(),
+		StoreID:        uuid.New(),
+		Status:         enums.LicenseStatusExpired,
+		MediaID:        uuid.New(),
+		ExpirationDate: ptrTime(now.Add(-40 * 24 * time.Hour)),
+		GCSKey:         "license/key",
+	}}
+
+	if err := helper.job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(helper.outboxSvc.events) != 0 {
+		t.Fatalf("expected no events in dry run, got %d", len(helper.outboxSvc.events))
+	}
+	if len(helper.licenseRepo.updateCalls) != 0 || len(helper.licenseRepo.deleteCalls) != 0 {
+		t.Fatalf("expected no license writes, got updates=%d deletes=%d", len(helper.licenseRepo.updateCalls), len(helper.licenseRepo.deleteCalls))
+	}
+	if len(helper.mediaRepo.deleteCalls) != 0 || helper.gcs.deletedKey != "" || helper.storeRepo.updatedStatus != "" {
+		t.Fatal("expected no media, gcs, or store writes in dry run")
+	}
+	for _, message := range []string{"license warn loop complete", "license expiry loop complete", "license hard-delete loop complete"} {
+		fields := loggedFields(t, logs, message)
+		if fields["count"] != float64(1) || fields["dry_run"] != true {
+			t.Fatalf("expected %q to report count=1 dry_run=true, got %v", message, fields)
+		}
+	}
+}
+
 type licenseJobTestHelper struct {
 	job            *licenseLifecycleJob
 	licenseRepo    *fakeLicenseRepo
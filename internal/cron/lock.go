@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,14 +24,20 @@ type redisStore interface {
 	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
 	Del(ctx context.Context, keys ...string) error
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
 }
 
-// RedisLock implements Lock using Redis SETNX + TTL.
+// RedisLock implements Lock using Redis SETNX + TTL. While held, a heartbeat extends the TTL so
+// a job that outlives it does not let a second worker in.
 type RedisLock struct {
 	client redisStore
 	key    string
 	ttl    time.Duration
-	owner  string
+
+	mu        sync.Mutex
+	owner     string
+	stopRenew context.CancelFunc
+	renewDone chan struct{}
 }
 
 // NewRedisLock constructs a Redis-backed lock.
@@ -54,14 +61,57 @@ func (l *RedisLock) Acquire(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("setnx: %w", err)
 	}
-	if ok {
-		l.owner = owner
+	if !ok {
+		return false, nil
 	}
-	return ok, nil
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.owner = owner
+	renewCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	l.stopRenew = cancel
+	l.renewDone = make(chan struct{})
+	go l.renew(renewCtx, owner, l.renewDone)
+	return true, nil
 }
 
-// Release frees the lock only if the owner value still matches.
+// renew extends the TTL every third of its length until stopped or ownership is lost.
+func (l *RedisLock) renew(ctx context.Context, owner string, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			value, err := l.client.Get(ctx, l.key)
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					return
+				}
+				continue
+			}
+			if value != owner {
+				return
+			}
+			if extended, err := l.client.Expire(ctx, l.key, l.ttl); err == nil && !extended {
+				return
+			}
+		}
+	}
+}
+
+// Release stops renewal and frees the lock only if the owner value still matches.
 func (l *RedisLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopRenew != nil {
+		l.stopRenew()
+		<-l.renewDone
+		l.stopRenew = nil
+		l.renewDone = nil
+	}
 	if l.owner == "" {
 		return nil
 	}
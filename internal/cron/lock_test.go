@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type expiringStore struct {
+	mu       sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+	renewals int
+}
+
+func newExpiringStore() *expiringStore {
+	return &expiringStore{values: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+func (s *expiringStore) live(key string) bool {
+	if _, ok := s.values[key]; !ok {
+		return false
+	}
+	if time.Now().After(s.expires[key]) {
+		delete(s.values, key)
+		delete(s.expires, key)
+		return false
+	}
+	return true
+}
+
+func (s *expiringStore) SetNX(_ context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live(key) {
+		return false, nil
+	}
+	s.values[key] = value.(string)
+	s.expires[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (s *expiringStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return "", redis.Nil
+	}
+	return s.values[key], nil
+}
+
+func (s *expiringStore) Del(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+	return nil
+}
+
+func (s *expiringStore) Expire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return false, nil
+	}
+	s.expires[key] = time.Now().Add(ttl)
+	s.renewals++
+	return true, nil
+}
+
+func (s *expiringStore) renewalCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renewals
+}
+
+func TestRedisLockRenewsWhileHeldAndReleases(t *testing.T) {
+	store := newExpiringStore()
+	ttl := 60 * time.Millisecond
+	lock, err := NewRedisLock(store, "cron-lock", ttl)
+	if err != nil {
+		t.Fatalf("new lock: %v", err)
+	}
+	other, err := NewRedisLock(store, "cron-lock", ttl)
+	if err != nil {
+		t.Fatalf("new lock: %v", err)
+	}
+
+	ctx := context.Background()
+	locked, err := lock.Acquire(ctx)
+	if err != nil || !locked {
+		t.Fatalf("expected lock acquired, got locked=%t err=%v", locked, err)
+	}
+
+	time.Sleep(4 * ttl)
+	if locked, err := other.Acquire(ctx); err != nil || locked {
+		t.Fatalf("expected lock still held past its original ttl, got locked=%t err=%v", locked, err)
+	}
+	if store.renewalCount() == 0 {
+		t.Fatalf("expected the lock ttl to be renewed")
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	renewals := store.renewalCount()
+	time.Sleep(2 * ttl)
+	if got := store.renewalCount(); got != renewals {
+		t.Fatalf("expected renewal to stop after release, got %d more", got-renewals)
+	}
+	locked, err = other.Acquire(ctx)
+	if err != nil || !locked {
+		t.Fatalf("expected lock free after release, got locked=%t err=%v", locked, err)
+	}
+	if err := other.Release(ctx); err != nil {
+		t.Fatalf("release other: %v", err)
+	}
+}
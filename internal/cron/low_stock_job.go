@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	product "github.com/angelmondragon/packfinderz-backend/internal/products"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const lowStockBatchSize = 500
+
+// LowStockAlertJobParams configures the low-stock alerting job.
+type LowStockAlertJobParams struct {
+	Logger           *logger.Logger
+	DB               txRunner
+	Inventory        lowStockInventoryRepo
+	Notifications    lowStockNotificationRepo
+	DefaultThreshold int
+	BatchSize        int
+}
+
+type lowStockInventoryRepo interface {
+	ListLowStockCandidates(ctx context.Context, defaultThreshold, limit int) ([]product.LowStockItem, error)
+	ResetLowStockAlerts(ctx context.Context, defaultThreshold int) (int64, error)
+	MarkLowStockAlertedWithTx(tx *gorm.DB, productID uuid.UUID, defaultThreshold int, at time.Time) (bool, error)
+}
+
+type lowStockNotificationRepo interface {
+	CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error
+}
+
+// NewLowStockAlertJob constructs the cron job that notifies vendors when inventory runs low.
+func NewLowStockAlertJob(params LowStockAlertJobParams) (Job, error) {
+	if params.Logger == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if params.DB == nil {
+		return nil, fmt.Errorf("db runner required")
+	}
+	if params.Inventory == nil {
+		return nil, fmt.Errorf("inventory repository required")
+	}
+	if params.Notifications == nil {
+		return nil, fmt.Errorf("notifications repository required")
+	}
+	if params.DefaultThreshold < 0 {
+		return nil, fmt.Errorf("default threshold must be non-negative")
+	}
+	batch := params.BatchSize
+	if batch <= 0 {
+		batch = lowStockBatchSize
+	}
+	return &lowStockAlertJob{
+		logg:          params.Logger,
+		db:            params.DB,
+		inventory:     params.Inventory,
+		notifications: params.Notifications,
+		threshold:     params.DefaultThreshold,
+		batchSize:     batch,
+		now:           time.Now,
+	}, nil
+}
+
+type lowStockAlertJob struct {
+	logg          *logger.Logger
+	db            txRunner
+	inventory     lowStockInventoryRepo
+	notifications lowStockNotificationRepo
+	threshold     int
+	batchSize     int
+	now           func() time.Time
+}
+
+func (j *lowStockAlertJob) Name() string { return "low-stock-alert" }
+
+// Run sweeps alert markers on rows back at or above their current threshold before alerting, while
+// a product sitting at a steady low level keeps its marker and stays quiet. Stock writes re-arm the
+// marker as soon as a restock crosses the threshold, so a product refilled and drained again between
+// two runs is still alerted once more.
+func (j *lowStockAlertJob) Run(ctx context.Context) error {
+	rearmed, err := j.inventory.ResetLowStockAlerts(ctx, j.threshold)
+	if err != nil {
+		return fmt.Errorf("reset low stock alerts: %w", err)
+	}
+
+	items, err := j.inventory.ListLowStockCandidates(ctx, j.threshold, j.batchSize)
+	if err != nil {
+		return fmt.Errorf("query low stock inventory: %w", err)
+	}
+
+	alerted := 0
+	for _, item := range items {
+		sent, err := j.alert(ctx, item)
+		if err != nil {
+			return fmt.Errorf("alert low stock product %s: %w", item.ProductID, err)
+		}
+		if sent {
+			alerted++
+		}
+	}
+
+	logCtx := j.logg.WithFields(ctx, map[string]any{
+		"default_threshold": j.threshold,
+		"rearmed":           rearmed,
+		"candidates":        len(items),
+		"alerted":           alerted,
+	})
+	j.logg.Info(logCtx, "low stock alert loop complete")
+	return nil
+}
+
+func (j *lowStockAlertJob) alert(ctx context.Context, item product.LowStockItem) (bool, error) {
+	sent := false
+	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
+		marked, err := j.inventory.MarkLowStockAlertedWithTx(tx, item.ProductID, j.threshold, j.now().UTC())
+		if err != nil {
+			return err
+		}
+		if !marked {
+			return nil
+		}
+		link := fmt.Sprintf("/vendor/products/%s", item.ProductID)
+		if err := j.notifications.CreateWithTx(ctx, tx, &models.Notification{
+			StoreID: item.StoreID,
+			Type:    enums.NotificationTypeLowStock,
+			Title:   fmt.Sprintf("Low stock: %s", item.Title),
+			Message: fmt.Sprintf("%s (SKU %s) has %d available, below your threshold of %d.", item.Title, item.SKU, item.AvailableQty, item.Threshold),
+			Link:    &link,
+		}); err != nil {
+			return err
+		}
+		sent = true
+		return nil
+	})
+	return sent, err
+}
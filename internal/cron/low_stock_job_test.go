@@ -0,0 +1,242 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	product "github.com/angelmondragon/packfinderz-backend/internal/products"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestLowStockAlertJobAlertsOnThresholdCrossing(t *testing.T) {
+	inventory := newFakeLowStockInventory()
+	perProduct := inventory.add(20, 10)
+	global := inventory.add(20, 0)
+	untracked := inventory.add(2, 0)
+	notifications := &fakeLowStockNotifications{}
+	job := newLowStockAlertJob(t, inventory, notifications, 5)
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(notifications.created) != 1 {
+		t.Fatalf("expected only the untracked-threshold product below global default to alert, got %d", len(notifications.created))
+	}
+	if notifications.created[0].StoreID != inventory.rows[untracked].storeID {
+		t.Fatalf("unexpected notification target %s", notifications.created[0].StoreID)
+	}
+
+	inventory.setAvailable(perProduct, 9)
+	inventory.setAvailable(global, 4)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(notifications.created) != 3 {
+		t.Fatalf("expected both crossings to alert, got %d notifications", len(notifications.created))
+	}
+	for _, n := range notifications.created {
+		if n.Type != enums.NotificationTypeLowStock {
+			t.Fatalf("unexpected notification type %s", n.Type)
+		}
+		if n.Link == nil {
+			t.Fatal("expected notification link")
+		}
+	}
+}
+
+func TestLowStockAlertJobDebouncesSteadyLowLevel(t *testing.T) {
+	inventory := newFakeLowStockInventory()
+	id := inventory.add(3, 10)
+	notifications := &fakeLowStockNotifications{}
+	job := newLowStockAlertJob(t, inventory, notifications, 0)
+
+	for i := 0; i < 3; i++ {
+		if err := job.Run(context.Background()); err != nil {
+			t.Fatalf("Run %d: %v", i, err)
+		}
+	}
+	if len(notifications.created) != 1 {
+		t.Fatalf("expected a single alert while stock stays low, got %d", len(notifications.created))
+	}
+
+	inventory.setAvailable(id, 2)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(notifications.created) != 1 {
+		t.Fatalf("expected further drops below threshold to stay quiet, got %d", len(notifications.created))
+	}
+}
+
+func TestLowStockAlertJobRealertsAfterRestock(t *testing.T) {
+	inventory := newFakeLowStockInventory()
+	id := inventory.add(3, 10)
+	notifications := &fakeLowStockNotifications{}
+	job := newLowStockAlertJob(t, inventory, notifications, 0)
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	inventory.setAvailable(id, 10)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if inventory.rows[id].alerted {
+		t.Fatal("expected restock to re-arm the alert")
+	}
+
+	inventory.setAvailable(id, 1)
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(notifications.created) != 2 {
+		t.Fatalf("expected a second alert after restock and drop, got %d", len(notifications.created))
+	}
+}
+
+func TestLowStockAlertJobKeepsMarkerWhenNotificationFails(t *testing.T) {
+	inventory := newFakeLowStockInventory()
+	id := inventory.add(1, 10)
+	notifications := &fakeLowStockNotifications{err: errors.New("boom")}
+	job := newLowStockAlertJob(t, inventory, notifications, 0)
+
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if inventory.rows[id].alerted {
+		t.Fatal("expected alert marker to roll back with the failed notification")
+	}
+}
+
+func newLowStockAlertJob(t *testing.T, inventory *fakeLowStockInventory, notifications *fakeLowStockNotifications, threshold int) *lowStockAlertJob {
+	t.Helper()
+	jobIface, err := NewLowStockAlertJob(LowStockAlertJobParams{
+		Logger:           logger.New(logger.Options{ServiceName: "test"}),
+		DB:               inventory,
+		Inventory:        inventory,
+		Notifications:    notifications,
+		DefaultThreshold: threshold,
+	})
+	if err != nil {
+		t.Fatalf("NewLowStockAlertJob: %v", err)
+	}
+	job, ok := jobIface.(*lowStockAlertJob)
+	if !ok {
+		t.Fatalf("expected lowStockAlertJob, got %T", jobIface)
+	}
+	job.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return job
+}
+
+type fakeLowStockRow struct {
+	storeID   uuid.UUID
+	available int
+	threshold int
+	alerted   bool
+}
+
+// fakeLowStockInventory mirrors the SQL semantics of the product repository and doubles as a
+// transaction runner that restores the alert markers when the callback fails.
+type fakeLowStockInventory struct {
+	rows  map[uuid.UUID]*fakeLowStockRow
+	order []uuid.UUID
+}
+
+func newFakeLowStockInventory() *fakeLowStockInventory {
+	return &fakeLowStockInventory{rows: map[uuid.UUID]*fakeLowStockRow{}}
+}
+
+func (f *fakeLowStockInventory) add(available, threshold int) uuid.UUID {
+	id := uuid.New()
+	f.rows[id] = &fakeLowStockRow{storeID: uuid.New(), available: available, threshold: threshold}
+	f.order = append(f.order, id)
+	return id
+}
+
+func (f *fakeLowStockInventory) setAvailable(id uuid.UUID, available int) {
+	f.rows[id].available = available
+}
+
+func (f *fakeLowStockInventory) effective(row *fakeLowStockRow, defaultThreshold int) int {
+	if row.threshold > 0 {
+		return row.threshold
+	}
+	return defaultThreshold
+}
+
+func (f *fakeLowStockInventory) ListLowStockCandidates(ctx context.Context, defaultThreshold, limit int) ([]product.LowStockItem, error) {
+	var items []product.LowStockItem
+	for _, id := range f.order {
+		row := f.rows[id]
+		threshold := f.effective(row, defaultThreshold)
+		if row.alerted || row.available >= threshold {
+			continue
+		}
+		items = append(items, product.LowStockItem{
+			ProductID:    id,
+			StoreID:      row.storeID,
+			SKU:          "SKU",
+			Title:        "Product",
+			AvailableQty: row.available,
+			Threshold:    threshold,
+		})
+		if len(items) == limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeLowStockInventory) ResetLowStockAlerts(ctx context.Context, defaultThreshold int) (int64, error) {
+	var reset int64
+	for _, row := range f.rows {
+		if row.alerted && row.available >= f.effective(row, defaultThreshold) {
+			row.alerted = false
+			reset++
+		}
+	}
+	return reset, nil
+}
+
+func (f *fakeLowStockInventory) MarkLowStockAlertedWithTx(tx *gorm.DB, productID uuid.UUID, defaultThreshold int, at time.Time) (bool, error) {
+	row := f.rows[productID]
+	if row == nil || row.alerted || row.available >= f.effective(row, defaultThreshold) {
+		return false, nil
+	}
+	row.alerted = true
+	return true, nil
+}
+
+func (f *fakeLowStockInventory) WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	snapshot := map[uuid.UUID]bool{}
+	for id, row := range f.rows {
+		snapshot[id] = row.alerted
+	}
+	if err := fn(nil); err != nil {
+		for id, alerted := range snapshot {
+			f.rows[id].alerted = alerted
+		}
+		return err
+	}
+	return nil
+}
+
+type fakeLowStockNotifications struct {
+	created []models.Notification
+	err     error
+}
+
+func (f *fakeLowStockNotifications) CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.created = append(f.created, *notification)
+	return nil
+}
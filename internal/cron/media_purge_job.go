@@ -0,0 +1,149 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MediaPurgeJobParams struct {
+	Logger         *logger.Logger
+	DB             txRunner
+	MediaRepo      mediaPurgeRepo
+	AttachmentRepo pendingAttachmentRepo
+	GCS            gcsClient
+	GCSBucket      string
+	// DryRun logs how many media rows are due for purging without deleting them.
+	DryRun bool
+}
+
+type mediaPurgeRepo interface {
+	ListPurgeable(ctx context.Context, cutoff time.Time) ([]models.Media, error)
+	DeletePendingDeletionWithTx(tx *gorm.DB, id uuid.UUID) (bool, error)
+}
+
+// NewMediaPurgeJob removes media whose deletion grace period has ended: the attachments, the row and
+// the GCS object go together, so a failed object delete leaves the media pending for the next run.
+func NewMediaPurgeJob(params MediaPurgeJobParams) (Job, error) {
+	if params.Logger == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if params.DB == nil {
+		return nil, fmt.Errorf("db runner required")
+	}
+	if params.MediaRepo == nil {
+		return nil, fmt.Errorf("media repository required")
+	}
+	if params.AttachmentRepo == nil {
+		return nil, fmt.Errorf("attachment repository required")
+	}
+	if params.GCS == nil {
+		return nil, fmt.Errorf("gcs client required")
+	}
+	if params.GCSBucket == "" {
+		return nil, fmt.Errorf("gcs bucket required")
+	}
+	return &mediaPurgeJob{
+		logg:        params.Logger,
+		db:          params.DB,
+		repo:        params.MediaRepo,
+		attachments: params.AttachmentRepo,
+		gcs:         params.GCS,
+		bucket:      params.GCSBucket,
+		dryRun:      params.DryRun,
+		now:         time.Now,
+	}, nil
+}
+
+type mediaPurgeJob struct {
+	logg        *logger.Logger
+	db          txRunner
+	repo        mediaPurgeRepo
+	attachments pendingAttachmentRepo
+	gcs         gcsClient
+	bucket      string
+	dryRun      bool
+	now         func() time.Time
+}
+
+func (j *mediaPurgeJob) Name() string { return "media-purge" }
+
+func (j *mediaPurgeJob) DryRun() bool { return j.dryRun }
+
+func (j *mediaPurgeJob) Run(ctx context.Context) error {
+	cutoff := j.now().UTC()
+	rows, err := j.repo.ListPurgeable(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("query purgeable media: %w", err)
+	}
+	if j.dryRun {
+		logCtx := j.logg.WithFields(ctx, map[string]any{
+			"cutoff":         cutoff,
+			"media_to_purge": len(rows),
+		})
+		j.logg.Info(logCtx, "media purge dry run complete")
+		return nil
+	}
+
+	var purged, skipped, failed int
+	for _, mediaRow := range rows {
+		deleted, err := j.purge(ctx, mediaRow)
+		if err != nil {
+			failed++
+			logCtx := j.logg.WithField(ctx, "media_id", mediaRow.ID)
+			j.logg.Error(logCtx, "media purge failed", err)
+			continue
+		}
+		if !deleted {
+			// Restored (or purged by another run) after the scan.
+			skipped++
+			continue
+		}
+		purged++
+	}
+
+	logCtx := j.logg.WithFields(ctx, map[string]any{
+		"cutoff":           cutoff,
+		"media_candidates": len(rows),
+		"media_purged":     purged,
+		"media_skipped":    skipped,
+		"media_failed":     failed,
+	})
+	j.logg.Info(logCtx, "media purge complete")
+	if failed > 0 {
+		return fmt.Errorf("media purge: %d of %d media failed", failed, len(rows))
+	}
+	return nil
+}
+
+// purge deletes the row first so a concurrent undelete either wins before it or finds nothing, and
+// deletes the GCS object last so an object failure rolls the row back for the next run.
+func (j *mediaPurgeJob) purge(ctx context.Context, mediaRow models.Media) (bool, error) {
+	var deleted bool
+	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		deleted, err = j.repo.DeletePendingDeletionWithTx(tx, mediaRow.ID)
+		if err != nil {
+			return fmt.Errorf("delete media row: %w", err)
+		}
+		if !deleted {
+			return nil
+		}
+		if _, err := j.attachments.DeleteByMediaID(ctx, tx, mediaRow.ID); err != nil {
+			return fmt.Errorf("delete media attachments: %w", err)
+		}
+		if err := j.gcs.DeleteObject(ctx, j.bucket, mediaRow.GCSKey); err != nil {
+			return fmt.Errorf("delete gcs object: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
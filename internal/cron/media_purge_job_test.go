@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestMediaPurgeRemovesMediaAfterGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	rows := []models.Media{
+		{ID: uuid.New(), GCSKey: "store/product/a.png"},
+		{ID: uuid.New(), GCSKey: "store/product/b.png"},
+	}
+	repo := &fakeMediaPurgeRepo{rows: rows}
+	attachments := &fakePendingAttachmentRepo{}
+	gcs := &fakeMediaPurgeGCS{}
+	job := newMediaPurgeJob(t, repo, attachments, gcs)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if !repo.lastCutoff.Equal(now) {
+		t.Fatalf("expected cutoff %s got %s", now, repo.lastCutoff)
+	}
+	if len(repo.deletedIDs) != len(rows) || len(attachments.deletedMediaIDs) != len(rows) {
+		t.Fatalf("expected rows and attachments purged, got media=%d attachments=%d", len(repo.deletedIDs), len(attachments.deletedMediaIDs))
+	}
+	if len(gcs.deletedKeys) != 2 || gcs.deletedKeys[0] != "store/product/a.png" || gcs.deletedKeys[1] != "store/product/b.png" {
+		t.Fatalf("expected gcs objects deleted, got %v", gcs.deletedKeys)
+	}
+}
+
+func TestMediaPurgeSkipsRestoredMedia(t *testing.T) {
+	t.Parallel()
+
+	restoredID := uuid.New()
+	repo := &fakeMediaPurgeRepo{
+		rows:     []models.Media{{ID: restoredID, GCSKey: "store/product/a.png"}},
+		restored: map[uuid.UUID]bool{restoredID: true},
+	}
+	gcs := &fakeMediaPurgeGCS{}
+	job := newMediaPurgeJob(t, repo, &fakePendingAttachmentRepo{}, gcs)
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(gcs.deletedKeys) != 0 {
+		t.Fatalf("expected restored media object kept, got %v", gcs.deletedKeys)
+	}
+}
+
+func TestMediaPurgeReportsObjectDeleteFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeMediaPurgeRepo{rows: []models.Media{{ID: uuid.New(), GCSKey: "store/product/a.png"}}}
+	gcs := &fakeMediaPurgeGCS{err: errors.New("boom")}
+	job := newMediaPurgeJob(t, repo, &fakePendingAttachmentRepo{}, gcs)
+
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestMediaPurgeDryRunKeepsMedia(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeMediaPurgeRepo{rows: []models.Media{{ID: uuid.New(), GCSKey: "store/product/a.png"}}}
+	gcs := &fakeMediaPurgeGCS{}
+	job := newMediaPurgeJob(t, repo, &fakePendingAttachmentRepo{}, gcs)
+	job.dryRun = true
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(repo.deletedIDs) != 0 || len(gcs.deletedKeys) != 0 {
+		t.Fatalf("expected no deletes in dry run, got media=%d objects=%d", len(repo.deletedIDs), len(gcs.deletedKeys))
+	}
+}
+
+func newMediaPurgeJob(t *testing.T, repo *fakeMediaPurgeRepo, attachments *fakePendingAttachmentRepo, gcs *fakeMediaPurgeGCS) *mediaPurgeJob {
+	t.Helper()
+	jobIface, err := NewMediaPurgeJob(MediaPurgeJobParams{
+		Logger:         logger.New(logger.Options{ServiceName: "test"}),
+		DB:             pendingMediaFakeTxRunner{},
+		MediaRepo:      repo,
+		AttachmentRepo: attachments,
+		GCS:            gcs,
+		GCSBucket:      "bucket",
+	})
+	if err != nil {
+		t.Fatalf("NewMediaPurgeJob: %v", err)
+	}
+	job, ok := jobIface.(*mediaPurgeJob)
+	if !ok {
+		t.Fatalf("expected mediaPurgeJob, got %T", jobIface)
+	}
+	return job
+}
+
+type fakeMediaPurgeRepo struct {
+	rows       []models.Media
+	lastCutoff time.Time
+	deletedIDs []uuid.UUID
+	// restored holds media undeleted after the scan, so the delete matches nothing.
+	restored map[uuid.UUID]bool
+}
+
+func (f *fakeMediaPurgeRepo) ListPurgeable(ctx context.Context, cutoff time.Time) ([]models.Media, error) {
+	f.lastCutoff = cutoff
+	return f.rows, nil
+}
+
+func (f *fakeMediaPurgeRepo) DeletePendingDeletionWithTx(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if f.restored[id] {
+		return false, nil
+	}
+	f.deletedIDs = append(f.deletedIDs, id)
+	return true, nil
+}
+
+type fakeMediaPurgeGCS struct {
+	deletedKeys []string
+	err         error
+}
+
+func (f *fakeMediaPurgeGCS) DeleteObject(ctx context.Context, bucket, object string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deletedKeys = append(f.deletedKeys, object)
+	return nil
+}
@@ -3,8 +3,11 @@ package cron
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -16,10 +19,13 @@ type NotificationCleanupJobParams struct {
 	DB         txRunner
 	Repository notificationsCleanupRepo
 	Retention  int
+	// TypeRetention overrides Retention (in days) per notification type, keyed by the raw type name.
+	TypeRetention map[string]int
 }
 
 type notificationsCleanupRepo interface {
-	DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time) (int64, error)
+	DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time, excludeTypes ...enums.NotificationType) (int64, error)
+	DeleteTypeOlderThan(ctx context.Context, tx *gorm.DB, notificationType enums.NotificationType, cutoff time.Time) (int64, error)
 }
 
 func NewNotificationCleanupJob(params NotificationCleanupJobParams) (Job, error) {
@@ -36,44 +42,79 @@ func NewNotificationCleanupJob(params NotificationCleanupJobParams) (Job, error)
 	if retention <= 0 {
 		retention = notificationRetentionDays
 	}
+	typeRetention := make(map[enums.NotificationType]int, len(params.TypeRetention))
+	for raw, days := range params.TypeRetention {
+		notificationType, err := enums.ParseNotificationType(strings.ToLower(strings.TrimSpace(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("notification retention: %w", err)
+		}
+		if days <= 0 {
+			return nil, fmt.Errorf("notification retention for %s must be positive", notificationType)
+		}
+		typeRetention[notificationType] = days
+	}
 	return &notificationCleanupJob{
-		logg:      params.Logger,
-		db:        params.DB,
-		repo:      params.Repository,
-		retention: retention,
-		now:       time.Now,
+		logg:          params.Logger,
+		db:            params.DB,
+		repo:          params.Repository,
+		retention:     retention,
+		typeRetention: typeRetention,
+		now:           time.Now,
 	}, nil
 }
 
 type notificationCleanupJob struct {
-	logg      *logger.Logger
-	db        txRunner
-	repo      notificationsCleanupRepo
-	retention int
-	now       func() time.Time
+	logg          *logger.Logger
+	db            txRunner
+	repo          notificationsCleanupRepo
+	retention     int
+	typeRetention map[enums.NotificationType]int
+	now           func() time.Time
 }
 
 func (j *notificationCleanupJob) Name() string { return "notification-cleanup" }
 
 func (j *notificationCleanupJob) Run(ctx context.Context) error {
-	cutoff := j.now().UTC().Add(-time.Duration(j.retention) * 24 * time.Hour)
+	now := j.now().UTC()
+	cutoff := now.Add(-retentionWindow(j.retention))
+	overrides := make([]enums.NotificationType, 0, len(j.typeRetention))
+	for notificationType := range j.typeRetention {
+		overrides = append(overrides, notificationType)
+	}
+	sort.Slice(overrides, func(i, k int) bool { return overrides[i] < overrides[k] })
+
+	deletedByType := make(map[string]int64, len(overrides))
 	var deleted int64
 	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
-		rows, err := j.repo.DeleteOlderThan(ctx, tx, cutoff)
+		for _, notificationType := range overrides {
+			typeCutoff := now.Add(-retentionWindow(j.typeRetention[notificationType]))
+			rows, err := j.repo.DeleteTypeOlderThan(ctx, tx, notificationType, typeCutoff)
+			if err != nil {
+				return fmt.Errorf("%s: %w", notificationType, err)
+			}
+			deletedByType[string(notificationType)] = rows
+			deleted += rows
+		}
+		rows, err := j.repo.DeleteOlderThan(ctx, tx, cutoff, overrides...)
 		if err != nil {
 			return err
 		}
-		deleted = rows
+		deleted += rows
 		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("notification cleanup: %w", err)
 	}
 	logCtx := j.logg.WithFields(ctx, map[string]any{
-		"cutoff":         cutoff,
-		"retention_days": j.retention,
-		"rows_deleted":   deleted,
+		"cutoff":               cutoff,
+		"retention_days":       j.retention,
+		"rows_deleted":         deleted,
+		"rows_deleted_by_type": deletedByType,
 	})
 	j.logg.Info(logCtx, "notification cleanup complete")
 	return nil
 }
+
+func retentionWindow(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -3,9 +3,11 @@ package cron
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -38,6 +40,63 @@ func TestNotificationCleanupJobPropagatesErrors(t *testing.T) {
 	}
 }
 
+func TestNotificationCleanupJobAppliesTypeRetention(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days) * 24 * time.Hour) }
+	repo := &fakeNotificationRepo{rows: []fakeNotificationRow{
+		{name: "old order alert", kind: enums.NotificationTypeOrderAlert, createdAt: daysAgo(100)},
+		{name: "aging order alert", kind: enums.NotificationTypeOrderAlert, createdAt: daysAgo(60)},
+		{name: "old market update", kind: enums.NotificationTypeMarketUpdate, createdAt: daysAgo(10)},
+		{name: "fresh market update", kind: enums.NotificationTypeMarketUpdate, createdAt: daysAgo(3)},
+		{name: "old compliance", kind: enums.NotificationTypeCompliance, createdAt: daysAgo(45)},
+		{name: "fresh compliance", kind: enums.NotificationTypeCompliance, createdAt: daysAgo(20)},
+	}}
+	jobIface, err := NewNotificationCleanupJob(NotificationCleanupJobParams{
+		Logger:     logger.New(logger.Options{ServiceName: "test"}),
+		DB:         notificationFakeTxRunner{},
+		Repository: repo,
+		TypeRetention: map[string]int{
+			"order_alert":     90,
+			" Market_Update ": 7,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewNotificationCleanupJob: %v", err)
+	}
+	job := jobIface.(*notificationCleanupJob)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var kept []string
+	for _, row := range repo.rows {
+		kept = append(kept, row.name)
+	}
+	expected := []string{"aging order alert", "fresh market update", "fresh compliance"}
+	if !slices.Equal(kept, expected) {
+		t.Fatalf("expected %v to survive, got %v", expected, kept)
+	}
+}
+
+func TestNewNotificationCleanupJobRejectsInvalidTypeRetention(t *testing.T) {
+	for _, typeRetention := range []map[string]int{
+		{"newsletter": 7},
+		{"order_alert": 0},
+	} {
+		_, err := NewNotificationCleanupJob(NotificationCleanupJobParams{
+			Logger:        logger.New(logger.Options{ServiceName: "test"}),
+			DB:            notificationFakeTxRunner{},
+			Repository:    &fakeNotificationRepo{},
+			TypeRetention: typeRetention,
+		})
+		if err == nil {
+			t.Fatalf("expected error for %v", typeRetention)
+		}
+	}
+}
+
 func newNotificationCleanupJob(t *testing.T, repo *fakeNotificationRepo) *notificationCleanupJob {
 	t.Helper()
 	jobIface, err := NewNotificationCleanupJob(NotificationCleanupJobParams{
@@ -60,15 +119,51 @@ type fakeNotificationRepo struct {
 	deletedRows int64
 	err         error
 	called      int
+	// rows, when seeded, are deleted by the fake so tests can assert what survives.
+	rows []fakeNotificationRow
+}
+
+type fakeNotificationRow struct {
+	name      string
+	kind      enums.NotificationType
+	createdAt time.Time
 }
 
-func (f *fakeNotificationRepo) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time) (int64, error) {
+func (f *fakeNotificationRepo) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time, excludeTypes ...enums.NotificationType) (int64, error) {
 	f.called++
 	f.lastCutoff = cutoff
 	if f.err != nil {
 		return 0, f.err
 	}
-	return f.deletedRows, nil
+	if f.rows == nil {
+		return f.deletedRows, nil
+	}
+	return f.deleteWhere(func(row fakeNotificationRow) bool {
+		return row.createdAt.Before(cutoff) && !slices.Contains(excludeTypes, row.kind)
+	}), nil
+}
+
+func (f *fakeNotificationRepo) DeleteTypeOlderThan(ctx context.Context, tx *gorm.DB, notificationType enums.NotificationType, cutoff time.Time) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.deleteWhere(func(row fakeNotificationRow) bool {
+		return row.kind == notificationType && row.createdAt.Before(cutoff)
+	}), nil
+}
+
+func (f *fakeNotificationRepo) deleteWhere(match func(fakeNotificationRow) bool) int64 {
+	var deleted int64
+	kept := f.rows[:0]
+	for _, row := range f.rows {
+		if match(row) {
+			deleted++
+			continue
+		}
+		kept = append(kept, row)
+	}
+	f.rows = kept
+	return deleted
 }
 
 type notificationFakeTxRunner struct{}
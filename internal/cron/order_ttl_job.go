@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/orders"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
@@ -41,6 +42,7 @@ type transactionalOrderRepo interface {
 	FindOrderLineItemsByOrder(ctx context.Context, orderID uuid.UUID) ([]models.OrderLineItem, error)
 	UpdateVendorOrder(ctx context.Context, orderID uuid.UUID, updates map[string]any) error
 	UpdateOrderLineItemStatus(ctx context.Context, lineItemID uuid.UUID, status enums.LineItemStatus, notes *string) error
+	MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error)
 }
 
 type transactionalRepoFactory func(tx *gorm.DB) transactionalOrderRepo
@@ -190,8 +192,11 @@ func (j *orderTTLJob) expireOrder(ctx context.Context, order models.VendorOrder)
 			if item.Status == enums.LineItemStatusFulfilled || item.Status == enums.LineItemStatusRejected {
 				continue
 			}
-			if err := orders.ReleaseLineItemInventory(ctx, tx, item, j.inventory); err != nil {
-				return err
+			// The reservation expiry job may already have handed this stock back.
+			if current.ReservationReleasedAt == nil {
+				if err := orders.ReleaseLineItemInventory(ctx, tx, item, j.inventory, inventory.AdjustmentActor{}); err != nil {
+					return err
+				}
 			}
 			if item.Status != enums.LineItemStatusRejected {
 				if err := repo.UpdateOrderLineItemStatus(ctx, item.ID, enums.LineItemStatusRejected, nil); err != nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
@@ -188,7 +189,7 @@ type inventoryReleaseCall struct {
 	qty       int
 }
 
-func (f *fakeInventoryReleaser) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int) error {
+func (f *fakeInventoryReleaser) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int, actor inventory.AdjustmentActor) error {
 	f.calls = append(f.calls, inventoryReleaseCall{productID: productID, qty: qty})
 	return nil
 }
@@ -235,6 +236,14 @@ func (f *fakeTransactionalRepo) UpdateOrderLineItemStatus(ctx context.Context, l
 	return nil
 }
 
+func (f *fakeTransactionalRepo) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	if f.order == nil || f.order.ID != orderID || f.order.Status != enums.VendorOrderStatusCreatedPending || f.order.ReservationReleasedAt != nil {
+		return false, nil
+	}
+	f.order.ReservationReleasedAt = &at
+	return true, nil
+}
+
 func ptrUUID(id uuid.UUID) *uuid.UUID {
 	return &id
 }
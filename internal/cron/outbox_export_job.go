@@ -0,0 +1,182 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cbigquery "cloud.google.com/go/bigquery"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+const (
+	outboxExportCursorName  = "bigquery-outbox-export"
+	outboxExportBatchSize   = 500
+	outboxExportMaxBatches  = 200
+	outboxExportSettleDelay = 5 * time.Minute
+)
+
+type OutboxExportJobParams struct {
+	Logger     *logger.Logger
+	Repository outboxExportRepo
+	Warehouse  outboxExportWarehouse
+	Table      string
+	BatchSize  int
+	MaxBatches int
+	// SettleDelay skips events published more recently than this, so a publisher transaction that
+	// stamped an earlier published_at but committed late is not passed over by the cursor.
+	SettleDelay time.Duration
+}
+
+type outboxExportRepo interface {
+	FetchPublishedAfter(ctx context.Context, cursor *models.OutboxExportCursor, before time.Time, limit int) ([]models.OutboxEvent, error)
+	GetExportCursor(ctx context.Context, name string) (*models.OutboxExportCursor, error)
+	SaveExportCursor(ctx context.Context, cursor models.OutboxExportCursor) error
+}
+
+type outboxExportWarehouse interface {
+	Exec(ctx context.Context, sql string, params []cbigquery.QueryParameter) error
+	QualifiedTable(table string) string
+}
+
+// outboxExportRow is one outbox event as stored in the BigQuery staging table.
+type outboxExportRow struct {
+	EventID       string    `bigquery:"event_id"`
+	EventType     string    `bigquery:"event_type"`
+	AggregateType string    `bigquery:"aggregate_type"`
+	AggregateID   string    `bigquery:"aggregate_id"`
+	Payload       string    `bigquery:"payload"`
+	CreatedAt     time.Time `bigquery:"created_at"`
+	PublishedAt   time.Time `bigquery:"published_at"`
+}
+
+// outboxExportMergeSQL inserts only the event IDs the staging table does not hold yet, so a batch
+// replayed after a failed cursor save never duplicates rows.
+const outboxExportMergeSQL = `MERGE %s AS target
+USING UNNEST(@rows) AS source
+ON target.event_id = source.event_id
+WHEN NOT MATCHED THEN
+  INSERT (event_id, event_type, aggregate_type, aggregate_id, payload, created_at, published_at)
+  VALUES (source.event_id, source.event_type, source.aggregate_type, source.aggregate_id, source.payload, source.created_at, source.published_at)`
+
+// NewOutboxExportJob copies newly published outbox events into a BigQuery staging table so the
+// analytics dataset can be rebuilt from the event history.
+func NewOutboxExportJob(params OutboxExportJobParams) (Job, error) {
+	if params.Logger == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if params.Repository == nil {
+		return nil, fmt.Errorf("outbox repository required")
+	}
+	if params.Warehouse == nil {
+		return nil, fmt.Errorf("bigquery client required")
+	}
+	table := strings.TrimSpace(params.Table)
+	if table == "" {
+		return nil, fmt.Errorf("bigquery export table required")
+	}
+	batchSize := params.BatchSize
+	if batchSize <= 0 {
+		batchSize = outboxExportBatchSize
+	}
+	maxBatches := params.MaxBatches
+	if maxBatches <= 0 {
+		maxBatches = outboxExportMaxBatches
+	}
+	settle := params.SettleDelay
+	if settle <= 0 {
+		settle = outboxExportSettleDelay
+	}
+	return &outboxExportJob{
+		logg:       params.Logger,
+		repo:       params.Repository,
+		warehouse:  params.Warehouse,
+		mergeSQL:   fmt.Sprintf(outboxExportMergeSQL, params.Warehouse.QualifiedTable(table)),
+		batchSize:  batchSize,
+		maxBatches: maxBatches,
+		settle:     settle,
+		now:        time.Now,
+	}, nil
+}
+
+type outboxExportJob struct {
+	logg       *logger.Logger
+	repo       outboxExportRepo
+	warehouse  outboxExportWarehouse
+	mergeSQL   string
+	batchSize  int
+	maxBatches int
+	settle     time.Duration
+	now        func() time.Time
+}
+
+func (j *outboxExportJob) Name() string { return "outbox-bigquery-export" }
+
+// Run merges batches of published events into BigQuery, advancing the cursor after each batch
+// lands, until it catches up or hits the per-run batch cap.
+func (j *outboxExportJob) Run(ctx context.Context) error {
+	cursor, err := j.repo.GetExportCursor(ctx, outboxExportCursorName)
+	if err != nil {
+		return fmt.Errorf("outbox export: load cursor: %w", err)
+	}
+	before := j.now().UTC().Add(-j.settle)
+
+	exported := 0
+	for batch := 0; batch < j.maxBatches; batch++ {
+		events, err := j.repo.FetchPublishedAfter(ctx, cursor, before, j.batchSize)
+		if err != nil {
+			return fmt.Errorf("outbox export: fetch events: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		rows := make([]outboxExportRow, 0, len(events))
+		for _, event := range events {
+			rows = append(rows, newOutboxExportRow(event))
+		}
+		if err := j.warehouse.Exec(ctx, j.mergeSQL, []cbigquery.QueryParameter{{Name: "rows", Value: rows}}); err != nil {
+			return fmt.Errorf("outbox export: merge batch: %w", err)
+		}
+
+		last := events[len(events)-1]
+		next := models.OutboxExportCursor{
+			Name:            outboxExportCursorName,
+			LastPublishedAt: *last.PublishedAt,
+			LastEventID:     last.ID,
+		}
+		if err := j.repo.SaveExportCursor(ctx, next); err != nil {
+			return fmt.Errorf("outbox export: save cursor: %w", err)
+		}
+		cursor = &next
+		exported += len(events)
+
+		if len(events) < j.batchSize {
+			break
+		}
+	}
+
+	logCtx := j.logg.WithFields(ctx, map[string]any{
+		"events_exported":  exported,
+		"published_before": before,
+	})
+	j.logg.Info(logCtx, "outbox export complete")
+	return nil
+}
+
+func newOutboxExportRow(event models.OutboxEvent) outboxExportRow {
+	row := outboxExportRow{
+		EventID:       event.ID.String(),
+		EventType:     string(event.EventType),
+		AggregateType: string(event.AggregateType),
+		AggregateID:   event.AggregateID.String(),
+		Payload:       string(event.Payload),
+		CreatedAt:     event.CreatedAt.UTC(),
+	}
+	if event.PublishedAt != nil {
+		row.PublishedAt = event.PublishedAt.UTC()
+	}
+	return row
+}
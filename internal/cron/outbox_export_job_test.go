@@ -0,0 +1,198 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cbigquery "cloud.google.com/go/bigquery"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+func TestOutboxExportJobMergesBatchesAndAdvancesCursor(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeOutboxExportRepo(now.Add(-time.Hour), 5)
+	warehouse := newFakeOutboxWarehouse()
+	job := newOutboxExportJob(t, repo, warehouse)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if warehouse.execs != 3 {
+		t.Fatalf("expected 3 merge batches of size 2, got %d", warehouse.execs)
+	}
+	if len(warehouse.rows) != 5 {
+		t.Fatalf("expected 5 exported rows, got %d", len(warehouse.rows))
+	}
+	if !strings.Contains(warehouse.lastSQL, "MERGE `proj.analytics.outbox_events_staging`") ||
+		!strings.Contains(warehouse.lastSQL, "WHEN NOT MATCHED THEN") {
+		t.Fatalf("expected merge keyed on event_id, got %s", warehouse.lastSQL)
+	}
+	if repo.cursor == nil || repo.cursor.LastEventID != repo.events[4].ID {
+		t.Fatalf("expected cursor at last event, got %+v", repo.cursor)
+	}
+	if expected := now.Add(-outboxExportSettleDelay); !repo.lastBefore.Equal(expected) {
+		t.Fatalf("expected settle bound %s, got %s", expected, repo.lastBefore)
+	}
+}
+
+func TestOutboxExportJobReplayAfterCursorFailureDoesNotDuplicate(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeOutboxExportRepo(now.Add(-time.Hour), 3)
+	repo.saveErr = errors.New("db down")
+	warehouse := newFakeOutboxWarehouse()
+	job := newOutboxExportJob(t, repo, warehouse)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected cursor save error")
+	}
+	if len(warehouse.rows) != 2 || repo.cursor != nil {
+		t.Fatalf("expected first batch merged without cursor, got rows=%d cursor=%+v", len(warehouse.rows), repo.cursor)
+	}
+
+	repo.saveErr = nil
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("rerun: %v", err)
+	}
+	if warehouse.inserted != 3 {
+		t.Fatalf("expected each event inserted once, got %d inserts", warehouse.inserted)
+	}
+	if len(warehouse.rows) != 3 {
+		t.Fatalf("expected 3 distinct rows, got %d", len(warehouse.rows))
+	}
+}
+
+func TestOutboxExportJobPropagatesMergeError(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeOutboxExportRepo(now.Add(-time.Hour), 1)
+	warehouse := newFakeOutboxWarehouse()
+	warehouse.err = errors.New("quota exceeded")
+	job := newOutboxExportJob(t, repo, warehouse)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("expected merge error")
+	}
+	if repo.cursor != nil {
+		t.Fatalf("expected cursor untouched after failed merge, got %+v", repo.cursor)
+	}
+}
+
+func newOutboxExportJob(t *testing.T, repo *fakeOutboxExportRepo, warehouse *fakeOutboxWarehouse) *outboxExportJob {
+	t.Helper()
+	jobIface, err := NewOutboxExportJob(OutboxExportJobParams{
+		Logger:     logger.New(logger.Options{ServiceName: "test"}),
+		Repository: repo,
+		Warehouse:  warehouse,
+		Table:      "outbox_events_staging",
+		BatchSize:  2,
+	})
+	if err != nil {
+		t.Fatalf("NewOutboxExportJob: %v", err)
+	}
+	job, ok := jobIface.(*outboxExportJob)
+	if !ok {
+		t.Fatalf("expected outboxExportJob, got %T", jobIface)
+	}
+	return job
+}
+
+type fakeOutboxExportRepo struct {
+	events     []models.OutboxEvent
+	cursor     *models.OutboxExportCursor
+	saveErr    error
+	lastBefore time.Time
+}
+
+func newFakeOutboxExportRepo(start time.Time, count int) *fakeOutboxExportRepo {
+	repo := &fakeOutboxExportRepo{}
+	for i := 0; i < count; i++ {
+		publishedAt := start.Add(time.Duration(i) * time.Minute)
+		repo.events = append(repo.events, models.OutboxEvent{
+			ID:            uuid.New(),
+			EventType:     enums.EventOrderPaid,
+			AggregateType: enums.AggregateVendorOrder,
+			AggregateID:   uuid.New(),
+			Payload:       json.RawMessage(`{"n":1}`),
+			CreatedAt:     publishedAt,
+			PublishedAt:   &publishedAt,
+		})
+	}
+	return repo
+}
+
+func (f *fakeOutboxExportRepo) FetchPublishedAfter(ctx context.Context, cursor *models.OutboxExportCursor, before time.Time, limit int) ([]models.OutboxEvent, error) {
+	f.lastBefore = before
+	var out []models.OutboxEvent
+	for _, event := range f.events {
+		if !event.PublishedAt.Before(before) {
+			continue
+		}
+		if cursor != nil && !event.PublishedAt.After(cursor.LastPublishedAt) {
+			continue
+		}
+		out = append(out, event)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeOutboxExportRepo) GetExportCursor(ctx context.Context, name string) (*models.OutboxExportCursor, error) {
+	return f.cursor, nil
+}
+
+func (f *fakeOutboxExportRepo) SaveExportCursor(ctx context.Context, cursor models.OutboxExportCursor) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.cursor = &cursor
+	return nil
+}
+
+// fakeOutboxWarehouse applies the MERGE semantics the job relies on: rows are keyed by event_id
+// and an existing key is left alone.
+type fakeOutboxWarehouse struct {
+	rows     map[string]outboxExportRow
+	execs    int
+	inserted int
+	lastSQL  string
+	err      error
+}
+
+func newFakeOutboxWarehouse() *fakeOutboxWarehouse {
+	return &fakeOutboxWarehouse{rows: map[string]outboxExportRow{}}
+}
+
+func (f *fakeOutboxWarehouse) Exec(ctx context.Context, sql string, params []cbigquery.QueryParameter) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.execs++
+	f.lastSQL = sql
+	rows, ok := params[0].Value.([]outboxExportRow)
+	if !ok || params[0].Name != "rows" {
+		return errors.New("unexpected merge params")
+	}
+	for _, row := range rows {
+		if _, exists := f.rows[row.EventID]; exists {
+			continue
+		}
+		f.rows[row.EventID] = row
+		f.inserted++
+	}
+	return nil
+}
+
+func (f *fakeOutboxWarehouse) QualifiedTable(table string) string {
+	return "`proj.analytics." + table + "`"
+}
@@ -20,9 +20,12 @@ type OutboxRetentionJobParams struct {
 	Repository  outboxRetentionRepo
 	Retention   int
 	MinAttempts int
+	// DryRun counts the rows that would be deleted instead of deleting them.
+	DryRun bool
 }
 
 type outboxRetentionRepo interface {
+	CountPublishedBefore(ctx context.Context, cutoff time.Time, minAttemptCount int) (int64, error)
 	DeletePublishedBefore(ctx context.Context, tx *gorm.DB, cutoff time.Time, minAttemptCount int) (int64, error)
 }
 
@@ -50,6 +53,7 @@ func NewOutboxRetentionJob(params OutboxRetentionJobParams) (Job, error) {
 		repo:        params.Repository,
 		retention:   retention,
 		minAttempts: minAttempts,
+		dryRun:      params.DryRun,
 		now:         time.Now,
 	}, nil
 }
@@ -60,13 +64,30 @@ type outboxRetentionJob struct {
 	repo        outboxRetentionRepo
 	retention   int
 	minAttempts int
+	dryRun      bool
 	now         func() time.Time
 }
 
 func (j *outboxRetentionJob) Name() string { return "outbox-retention" }
 
+func (j *outboxRetentionJob) DryRun() bool { return j.dryRun }
+
 func (j *outboxRetentionJob) Run(ctx context.Context) error {
 	cutoff := j.now().UTC().Add(-time.Duration(j.retention) * 24 * time.Hour)
+	if j.dryRun {
+		count, err := j.repo.CountPublishedBefore(ctx, cutoff, j.minAttempts)
+		if err != nil {
+			return fmt.Errorf("outbox retention dry run: %w", err)
+		}
+		logCtx := j.logg.WithFields(ctx, map[string]any{
+			"cutoff":         cutoff,
+			"retention_days": j.retention,
+			"min_attempts":   j.minAttempts,
+			"rows_to_delete": count,
+		})
+		j.logg.Info(logCtx, "outbox retention dry run complete")
+		return nil
+	}
 	var deleted int64
 	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
 		rows, err := j.repo.DeletePublishedBefore(ctx, tx, cutoff, j.minAttempts)
@@ -31,6 +31,25 @@ func TestOutboxRetentionJobDeletesPublishedRows(t *testing.T) {
 	}
 }
 
+func TestOutboxRetentionJobDryRunCountsWithoutDeleting(t *testing.T) {
+	repo := &fakeOutboxRetentionRepo{count: 12}
+	job := newOutboxRetentionJob(t, repo)
+	logg, logs := newRecordedLogger()
+	job.logg = logg
+	job.dryRun = true
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if repo.called != 0 {
+		t.Fatalf("expected no deletes in dry run, got %d", repo.called)
+	}
+	fields := loggedFields(t, logs, "outbox retention dry run complete")
+	if fields["rows_to_delete"] != float64(12) {
+		t.Fatalf("expected rows_to_delete=12, got %v", fields["rows_to_delete"])
+	}
+}
+
 func TestOutboxRetentionJobPropagatesError(t *testing.T) {
 	repo := &fakeOutboxRetentionRepo{err: errors.New("boom")}
 	job := newOutboxRetentionJob(t, repo)
@@ -61,9 +80,19 @@ type fakeOutboxRetentionRepo struct {
 	lastCutoff  time.Time
 	minAttempts int
 	called      int
+	count       int64
 	err         error
 }
 
+func (f *fakeOutboxRetentionRepo) CountPublishedBefore(ctx context.Context, cutoff time.Time, minAttemptCount int) (int64, error) {
+	f.lastCutoff = cutoff
+	f.minAttempts = minAttemptCount
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.count, nil
+}
+
 func (f *fakeOutboxRetentionRepo) DeletePublishedBefore(ctx context.Context, tx *gorm.DB, cutoff time.Time, minAttemptCount int) (int64, error) {
 	f.called++
 	f.lastCutoff = cutoff
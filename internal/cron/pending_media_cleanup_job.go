@@ -19,11 +19,19 @@ type PendingMediaCleanupJobParams struct {
 	MediaRepo      pendingMediaCleanupRepo
 	AttachmentRepo pendingAttachmentRepo
 	RetentionDays  int
+	Metrics        pendingMediaCleanupRecorder
+	// DryRun logs how many stale uploads would be deleted without deleting them.
+	DryRun bool
 }
 
 type pendingMediaCleanupRepo interface {
 	ListPendingBefore(ctx context.Context, cutoff time.Time) ([]models.Media, error)
-	DeleteWithTx(tx *gorm.DB, id uuid.UUID) error
+	DeletePendingWithTx(tx *gorm.DB, id uuid.UUID) (bool, error)
+}
+
+// pendingMediaCleanupRecorder lets alerts catch orphan spikes, which usually mean the upload flow broke.
+type pendingMediaCleanupRecorder interface {
+	ObservePendingMediaCleanup(job string, scanned, deleted, skipped int)
 }
 
 type pendingAttachmentRepo interface {
@@ -53,6 +61,8 @@ func NewPendingMediaCleanupJob(params PendingMediaCleanupJobParams) (Job, error)
 		repo:          params.MediaRepo,
 		attachments:   params.AttachmentRepo,
 		retentionDays: retention,
+		metrics:       params.Metrics,
+		dryRun:        params.DryRun,
 		now:           time.Now,
 	}, nil
 }
@@ -63,47 +73,73 @@ type pendingMediaCleanupJob struct {
 	repo          pendingMediaCleanupRepo
 	attachments   pendingAttachmentRepo
 	retentionDays int
+	metrics       pendingMediaCleanupRecorder
+	dryRun        bool
 	now           func() time.Time
 }
 
 func (j *pendingMediaCleanupJob) Name() string { return "pending-media-cleanup" }
 
+func (j *pendingMediaCleanupJob) DryRun() bool { return j.dryRun }
+
 func (j *pendingMediaCleanupJob) Run(ctx context.Context) error {
 	cutoff := j.now().UTC().Add(-time.Duration(j.retentionDays) * 24 * time.Hour)
+	// Dry runs report the same selection the real run would delete from.
+	rows, err := j.repo.ListPendingBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("query pending media: %w", err)
+	}
+	mediaCandidates := len(rows)
+	if j.dryRun {
+		logCtx := j.logg.WithFields(ctx, map[string]any{
+			"cutoff":           cutoff,
+			"retention_days":   j.retentionDays,
+			"media_candidates": mediaCandidates,
+			"media_to_delete":  mediaCandidates,
+		})
+		j.logg.Info(logCtx, "pending media cleanup dry run complete")
+		return nil
+	}
+
 	var (
-		deletedMedia       int64
+		deletedMedia       int
+		skippedMedia       int
 		deletedAttachments int64
-		mediaCandidates    int
 	)
-	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
-		rows, err := j.repo.ListPendingBefore(ctx, cutoff)
-		if err != nil {
-			return fmt.Errorf("query pending media: %w", err)
-		}
-		mediaCandidates = len(rows)
+	err = j.db.WithTx(ctx, func(tx *gorm.DB) error {
 		for _, mediaRow := range rows {
+			deleted, err := j.repo.DeletePendingWithTx(tx, mediaRow.ID)
+			if err != nil {
+				return fmt.Errorf("delete media row: %w", err)
+			}
+			if !deleted {
+				// The upload finished (or another run removed it) after the scan.
+				skippedMedia++
+				continue
+			}
+			deletedMedia++
+
 			attachmentsDeleted, err := j.attachments.DeleteByMediaID(ctx, tx, mediaRow.ID)
 			if err != nil {
 				return fmt.Errorf("delete media attachments: %w", err)
 			}
 			deletedAttachments += attachmentsDeleted
-
-			if err := j.repo.DeleteWithTx(tx, mediaRow.ID); err != nil {
-				return fmt.Errorf("delete media row: %w", err)
-			}
-			deletedMedia++
 		}
 		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("pending media cleanup: %w", err)
 	}
+	if j.metrics != nil {
+		j.metrics.ObservePendingMediaCleanup(j.Name(), mediaCandidates, deletedMedia, skippedMedia)
+	}
 
 	logCtx := j.logg.WithFields(ctx, map[string]any{
 		"cutoff":              cutoff,
 		"retention_days":      j.retentionDays,
 		"media_candidates":    mediaCandidates,
 		"media_deleted":       deletedMedia,
+		"media_skipped":       skippedMedia,
 		"attachments_deleted": deletedAttachments,
 	})
 	j.logg.Info(logCtx, "pending media cleanup complete")
@@ -41,6 +41,72 @@ func TestPendingMediaCleanupDeletesStaleRows(t *testing.T) {
 	}
 }
 
+func TestPendingMediaCleanupRecordsMetrics(t *testing.T) {
+	t.Parallel()
+
+	finishedID := uuid.New()
+	repo := &fakePendingMediaRepo{
+		rows:     []models.Media{{ID: uuid.New()}, {ID: finishedID}, {ID: uuid.New()}},
+		finished: map[uuid.UUID]bool{finishedID: true},
+	}
+	attachmentRepo := &fakePendingAttachmentRepo{}
+	recorder := &fakePendingMediaCleanupRecorder{}
+	job := newPendingMediaCleanupJob(t, repo, attachmentRepo)
+	job.metrics = recorder
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(recorder.runs) != 1 {
+		t.Fatalf("expected one metrics observation, got %d", len(recorder.runs))
+	}
+	want := recordedPendingMediaCleanup{job: "pending-media-cleanup", scanned: 3, deleted: 2, skipped: 1}
+	if recorder.runs[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, recorder.runs[0])
+	}
+	if recorder.runs[0].deleted != len(repo.deletedIDs) {
+		t.Fatalf("expected deleted metric to match %d deleted rows", len(repo.deletedIDs))
+	}
+	for _, id := range attachmentRepo.deletedMediaIDs {
+		if id == finishedID {
+			t.Fatal("expected attachments of a finished upload to be kept")
+		}
+	}
+}
+
+func TestPendingMediaCleanupDryRunKeepsRows(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakePendingMediaRepo{rows: []models.Media{{ID: uuid.New()}, {ID: uuid.New()}}}
+	attachmentRepo := &fakePendingAttachmentRepo{}
+	recorder := &fakePendingMediaCleanupRecorder{}
+	job := newPendingMediaCleanupJob(t, repo, attachmentRepo)
+	logg, logs := newRecordedLogger()
+	job.logg = logg
+	job.metrics = recorder
+	job.dryRun = true
+	now := time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if expectedCutoff := now.Add(-pendingMediaRetentionDays * 24 * time.Hour); !repo.lastCutoff.Equal(expectedCutoff) {
+		t.Fatalf("expected dry run to select with the real cutoff %s, got %s", expectedCutoff, repo.lastCutoff)
+	}
+	if len(repo.deletedIDs) != 0 || len(attachmentRepo.deletedMediaIDs) != 0 {
+		t.Fatalf("expected no deletes in dry run, got media=%d attachments=%d", len(repo.deletedIDs), len(attachmentRepo.deletedMediaIDs))
+	}
+	if len(recorder.runs) != 0 {
+		t.Fatal("expected dry run not to record cleanup metrics")
+	}
+	fields := loggedFields(t, logs, "pending media cleanup dry run complete")
+	if fields["media_to_delete"] != float64(2) {
+		t.Fatalf("expected media_to_delete=2, got %v", fields["media_to_delete"])
+	}
+}
+
 func TestPendingMediaCleanupPropagatesErrors(t *testing.T) {
 	t.Parallel()
 
@@ -76,6 +142,8 @@ type fakePendingMediaRepo struct {
 	deleteErr  error
 	lastCutoff time.Time
 	deletedIDs []uuid.UUID
+	// finished holds media whose upload completed after the scan, so the delete matches nothing.
+	finished map[uuid.UUID]bool
 }
 
 func (f *fakePendingMediaRepo) ListPendingBefore(ctx context.Context, cutoff time.Time) ([]models.Media, error) {
@@ -86,9 +154,28 @@ func (f *fakePendingMediaRepo) ListPendingBefore(ctx context.Context, cutoff tim
 	return f.rows, nil
 }
 
-func (f *fakePendingMediaRepo) DeleteWithTx(tx *gorm.DB, id uuid.UUID) error {
+func (f *fakePendingMediaRepo) DeletePendingWithTx(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if f.deleteErr != nil {
+		return false, f.deleteErr
+	}
+	if f.finished[id] {
+		return false, nil
+	}
 	f.deletedIDs = append(f.deletedIDs, id)
-	return f.deleteErr
+	return true, nil
+}
+
+type recordedPendingMediaCleanup struct {
+	job                       string
+	scanned, deleted, skipped int
+}
+
+type fakePendingMediaCleanupRecorder struct {
+	runs []recordedPendingMediaCleanup
+}
+
+func (f *fakePendingMediaCleanupRecorder) ObservePendingMediaCleanup(job string, scanned, deleted, skipped int) {
+	f.runs = append(f.runs, recordedPendingMediaCleanup{job: job, scanned: scanned, deleted: deleted, skipped: skipped})
 }
 
 type fakePendingAttachmentRepo struct {
@@ -8,6 +8,12 @@ type Job interface {
 	Run(ctx context.Context) error
 }
 
+// DryRunJob is a Job that can report what it would change without changing anything.
+type DryRunJob interface {
+	Job
+	DryRun() bool
+}
+
 // Registry tracks registered cron jobs.
 type Registry struct {
 	jobs []Job
@@ -33,6 +39,16 @@ func (r *Registry) Register(job Job) {
 	r.jobs = append(r.jobs, job)
 }
 
+// Find returns the registered job with the given name.
+func (r *Registry) Find(name string) (Job, bool) {
+	for _, job := range r.jobs {
+		if job.Name() == name {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
 // Jobs returns the registered jobs in the order they were added.
 func (r *Registry) Jobs() []Job {
 	jobs := make([]Job, len(r.jobs))
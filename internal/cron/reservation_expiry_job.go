@@ -0,0 +1,126 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
+	"github.com/angelmondragon/packfinderz-backend/internal/orders"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// ReservationExpiryJobParams configure the job that hands back stock held by abandoned orders.
+type ReservationExpiryJobParams struct {
+	Logger                   *logger.Logger
+	DB                       txRunner
+	PendingReader            pendingOrderReader
+	Inventory                orders.InventoryReleaser
+	TTL                      time.Duration
+	TransactionalRepoFactory transactionalRepoFactory
+}
+
+// NewReservationExpiryJob builds the cron job that releases the stock reserved at checkout for
+// pending orders nobody acted on within the TTL. The orders stay pending; the order TTL job still
+// nudges and expires them, skipping the release this job already did.
+func NewReservationExpiryJob(params ReservationExpiryJobParams) (Job, error) {
+	if params.Logger == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if params.DB == nil {
+		return nil, fmt.Errorf("db runner required")
+	}
+	if params.PendingReader == nil {
+		return nil, fmt.Errorf("pending orders reader required")
+	}
+	if params.Inventory == nil {
+		return nil, fmt.Errorf("inventory releaser required")
+	}
+	if params.TTL <= 0 {
+		return nil, fmt.Errorf("reservation ttl must be positive")
+	}
+	repoFactory := params.TransactionalRepoFactory
+	if repoFactory == nil {
+		repoFactory = defaultTransactionalRepo
+	}
+	return &reservationExpiryJob{
+		logg:          params.Logger,
+		db:            params.DB,
+		pendingReader: params.PendingReader,
+		inventory:     params.Inventory,
+		ttl:           params.TTL,
+		repoFactory:   repoFactory,
+		now:           time.Now,
+	}, nil
+}
+
+type reservationExpiryJob struct {
+	logg          *logger.Logger
+	db            txRunner
+	pendingReader pendingOrderReader
+	inventory     orders.InventoryReleaser
+	ttl           time.Duration
+	repoFactory   transactionalRepoFactory
+	now           func() time.Time
+}
+
+func (j *reservationExpiryJob) Name() string { return "reservation-expiry" }
+
+func (j *reservationExpiryJob) Run(ctx context.Context) error {
+	cutoff := j.now().UTC().Add(-j.ttl)
+	pending, err := j.pendingReader.FindPendingOrdersBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("query pending orders for reservation expiry: %w", err)
+	}
+	released := 0
+	for _, order := range pending {
+		if order.ReservationReleasedAt != nil {
+			continue
+		}
+		ok, err := j.releaseOrder(ctx, order)
+		if err != nil {
+			return err
+		}
+		if ok {
+			released++
+		}
+	}
+	logCtx := j.logg.WithFields(ctx, map[string]any{"count": released})
+	j.logg.Info(logCtx, "reservation expiry loop complete")
+	return nil
+}
+
+// releaseOrder claims the order's reservation and releases the stock of its open line items in the
+// same transaction. The claim only matches a pending order that still holds its reservation, so a
+// concurrent run, vendor decision, or cancel that got there first leaves nothing to release.
+func (j *reservationExpiryJob) releaseOrder(ctx context.Context, order models.VendorOrder) (bool, error) {
+	released := false
+	err := j.db.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := j.repoFactory(tx)
+		claimed, err := repo.MarkReservationReleased(ctx, order.ID, j.now().UTC())
+		if err != nil {
+			return fmt.Errorf("claim reservation release for order %s: %w", order.ID, err)
+		}
+		if !claimed {
+			return nil
+		}
+		items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.Status == enums.LineItemStatusFulfilled || item.Status == enums.LineItemStatusRejected {
+				continue
+			}
+			if err := orders.ReleaseLineItemInventory(ctx, tx, item, j.inventory, inventory.AdjustmentActor{}); err != nil {
+				return err
+			}
+		}
+		released = true
+		return nil
+	})
+	return released, err
+}
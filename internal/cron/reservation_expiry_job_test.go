@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fixedPendingReader struct {
+	orders  []models.VendorOrder
+	cutoffs []time.Time
+}
+
+func (f *fixedPendingReader) FindPendingOrdersBefore(ctx context.Context, cutoff time.Time) ([]models.VendorOrder, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	return f.orders, nil
+}
+
+func newReservationExpiryJobTest(t *testing.T, reader pendingOrderReader, repo *fakeTransactionalRepo, inventory *fakeInventoryReleaser, now time.Time) *reservationExpiryJob {
+	t.Helper()
+	jobIface, err := NewReservationExpiryJob(ReservationExpiryJobParams{
+		Logger:        logger.New(logger.Options{ServiceName: "test"}),
+		DB:            fakeTxRunner{},
+		PendingReader: reader,
+		Inventory:     inventory,
+		TTL:           72 * time.Hour,
+		TransactionalRepoFactory: func(tx *gorm.DB) transactionalOrderRepo {
+			return repo
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewReservationExpiryJob: %v", err)
+	}
+	job := jobIface.(*reservationExpiryJob)
+	job.now = func() time.Time { return now }
+	return job
+}
+
+func TestReservationExpiryJob_releasesStaleReservationOnce(t *testing.T) {
+	now := time.Date(2026, 1, 30, 12, 0, 0, 0, time.UTC)
+	order := models.VendorOrder{
+		ID:     uuid.New(),
+		Status: enums.VendorOrderStatusCreatedPending,
+	}
+	pendingLine := models.OrderLineItem{ID: uuid.New(), OrderID: order.ID, ProductID: ptrUUID(uuid.New()), Qty: 4, Status: enums.LineItemStatusPending}
+	rejectedLine := models.OrderLineItem{ID: uuid.New(), OrderID: order.ID, ProductID: ptrUUID(uuid.New()), Qty: 2, Status: enums.LineItemStatusRejected}
+	current := order
+	repo := &fakeTransactionalRepo{order: &current, items: []models.OrderLineItem{pendingLine, rejectedLine}}
+	inventory := &fakeInventoryReleaser{}
+	reader := &fixedPendingReader{orders: []models.VendorOrder{order}}
+	job := newReservationExpiryJobTest(t, reader, repo, inventory, now)
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(reader.cutoffs) != 1 || !reader.cutoffs[0].Equal(now.Add(-72*time.Hour)) {
+		t.Fatalf("expected cutoff at the reservation ttl, got %v", reader.cutoffs)
+	}
+	if len(inventory.calls) != 1 || inventory.calls[0].productID != *pendingLine.ProductID || inventory.calls[0].qty != 4 {
+		t.Fatalf("expected only the open line released, got %+v", inventory.calls)
+	}
+	if repo.order.ReservationReleasedAt == nil || !repo.order.ReservationReleasedAt.Equal(now) {
+		t.Fatalf("expected reservation_released_at stamped, got %v", repo.order.ReservationReleasedAt)
+	}
+	if len(repo.lineItemUpdates) != 0 || len(repo.orderUpdates) != 0 {
+		t.Fatalf("expected the order to stay pending and untouched")
+	}
+
+	// The reader can still return the order with a stale snapshot; the claim keeps the second run
+	// from releasing again.
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(inventory.calls) != 1 {
+		t.Fatalf("expected stock released exactly once, got %d releases", len(inventory.calls))
+	}
+}
+
+func TestReservationExpiryJob_orderTTLSkipsReleasedStock(t *testing.T) {
+	now := time.Date(2026, 1, 30, 12, 0, 0, 0, time.UTC)
+	order := models.VendorOrder{
+		ID:              uuid.New(),
+		CheckoutGroupID: uuid.New(),
+		Status:          enums.VendorOrderStatusCreatedPending,
+	}
+	line := models.OrderLineItem{ID: uuid.New(), OrderID: order.ID, ProductID: ptrUUID(uuid.New()), Qty: 3, Status: enums.LineItemStatusPending}
+	current := order
+	repo := &fakeTransactionalRepo{order: &current, items: []models.OrderLineItem{line}}
+	inventory := &fakeInventoryReleaser{}
+	reservationJob := newReservationExpiryJobTest(t, &fixedPendingReader{orders: []models.VendorOrder{order}}, repo, inventory, now)
+	if err := reservationJob.Run(context.Background()); err != nil {
+		t.Fatalf("reservation expiry Run: %v", err)
+	}
+
+	reader := &fakePendingReader{
+		nudgeCutoff:      now.Add(-pendingNudgeDays * 24 * time.Hour),
+		expireCutoff:     now.Add(-orderExpirationDays * 24 * time.Hour),
+		expirationOrders: []models.VendorOrder{order},
+	}
+	helper := newOrderTTLJobTest(t, reader)
+	helper.job.now = func() time.Time { return now }
+	helper.job.inventory = inventory
+	helper.job.repoFactory = func(tx *gorm.DB) transactionalOrderRepo { return repo }
+	if err := helper.job.Run(context.Background()); err != nil {
+		t.Fatalf("order ttl Run: %v", err)
+	}
+
+	if len(inventory.calls) != 1 {
+		t.Fatalf("expected stock released exactly once across both jobs, got %d releases", len(inventory.calls))
+	}
+	if len(repo.orderUpdates) != 1 || repo.orderUpdates[0].status != enums.VendorOrderStatusExpired {
+		t.Fatalf("expected the order to still expire, got %+v", repo.orderUpdates)
+	}
+	if len(repo.lineItemUpdates) != 1 || repo.lineItemUpdates[0].status != enums.LineItemStatusRejected {
+		t.Fatalf("expected the open line rejected on expiry, got %+v", repo.lineItemUpdates)
+	}
+}
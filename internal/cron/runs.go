@@ -0,0 +1,179 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+const defaultRunPollInterval = 5 * time.Second
+
+// RunRepository persists manual job runs so the API can queue them without building the job
+// registry, and the cron worker can run them.
+type RunRepository interface {
+	Create(ctx context.Context, run *models.CronJobRun) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.CronJobRun, error)
+	// ClaimNextQueued marks the oldest queued run as running and returns it, or nil when none is
+	// queued.
+	ClaimNextQueued(ctx context.Context, now time.Time) (*models.CronJobRun, error)
+	// Requeue hands a claimed run back to the queue, e.g. when the scheduler holds the lock.
+	Requeue(ctx context.Context, id uuid.UUID) error
+	Finish(ctx context.Context, run *models.CronJobRun) error
+}
+
+type runRepository struct {
+	db *gorm.DB
+}
+
+// NewRunRepository builds a Postgres-backed RunRepository.
+func NewRunRepository(db *gorm.DB) RunRepository {
+	return &runRepository{db: db}
+}
+
+func (r *runRepository) Create(ctx context.Context, run *models.CronJobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *runRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.CronJobRun, error) {
+	var run models.CronJobRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *runRepository) ClaimNextQueued(ctx context.Context, now time.Time) (*models.CronJobRun, error) {
+	var run models.CronJobRun
+	err := r.db.WithContext(ctx).Raw(`
+UPDATE cron_job_runs
+SET status = ?, started_at = ?, updated_at = ?
+WHERE id = (
+  SELECT id FROM cron_job_runs
+  WHERE status = ?
+  ORDER BY created_at ASC, id ASC
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *`,
+		enums.CronJobRunStatusRunning, now, now, enums.CronJobRunStatusQueued,
+	).Scan(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	if run.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+func (r *runRepository) Requeue(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.CronJobRun{}).
+		Where("id = ? AND status = ?", id, enums.CronJobRunStatusRunning).
+		Updates(map[string]any{
+			"status":     enums.CronJobRunStatusQueued,
+			"started_at": nil,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *runRepository) Finish(ctx context.Context, run *models.CronJobRun) error {
+	return r.db.WithContext(ctx).Model(&models.CronJobRun{}).
+		Where("id = ?", run.ID).
+		Updates(map[string]any{
+			"status":      run.Status,
+			"dry_run":     run.DryRun,
+			"error":       run.Error,
+			"duration_ms": run.DurationMS,
+			"finished_at": run.FinishedAt,
+			"updated_at":  run.UpdatedAt,
+		}).Error
+}
+
+// JobRun reports a queued manual run and, once the cron worker has run it, its outcome.
+type JobRun struct {
+	ID          uuid.UUID              `json:"id"`
+	Job         string                 `json:"job"`
+	Status      enums.CronJobRunStatus `json:"status"`
+	DryRun      bool                   `json:"dry_run"`
+	Error       string                 `json:"error,omitempty"`
+	DurationMS  *int64                 `json:"duration_ms,omitempty"`
+	RequestedAt time.Time              `json:"requested_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	FinishedAt  *time.Time             `json:"finished_at,omitempty"`
+}
+
+func newJobRun(run *models.CronJobRun) JobRun {
+	out := JobRun{
+		ID:          run.ID,
+		Job:         run.Job,
+		Status:      run.Status,
+		DryRun:      run.DryRun,
+		DurationMS:  run.DurationMS,
+		RequestedAt: run.CreatedAt,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+	if run.Error != nil {
+		out.Error = *run.Error
+	}
+	return out
+}
+
+// RunQueue queues manual job runs for the cron worker and reports their progress. It is all the
+// API needs to trigger a job, so the API never builds the job registry or runs a job itself.
+type RunQueue struct {
+	repo RunRepository
+}
+
+// NewRunQueue builds a RunQueue over repo.
+func NewRunQueue(repo RunRepository) (*RunQueue, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("run repository required")
+	}
+	return &RunQueue{repo: repo}, nil
+}
+
+// Request queues a run of the named job. The cron worker checks the name when it picks the run up,
+// so an unknown job shows up as a failed run.
+func (q *RunQueue) Request(ctx context.Context, job string, requestedBy uuid.UUID) (JobRun, error) {
+	job = strings.TrimSpace(job)
+	if job == "" {
+		return JobRun{}, pkgerrors.New(pkgerrors.CodeValidation, "job name is required")
+	}
+	run := &models.CronJobRun{
+		ID:     uuid.New(),
+		Job:    job,
+		Status: enums.CronJobRunStatusQueued,
+	}
+	if requestedBy != uuid.Nil {
+		run.RequestedBy = &requestedBy
+	}
+	if err := q.repo.Create(ctx, run); err != nil {
+		return JobRun{}, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "queue cron job run")
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now().UTC()
+	}
+	return newJobRun(run), nil
+}
+
+// Get returns a queued, running, or finished run.
+func (q *RunQueue) Get(ctx context.Context, id uuid.UUID) (JobRun, error) {
+	run, err := q.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return JobRun{}, pkgerrors.New(pkgerrors.CodeNotFound, "cron job run not found")
+		}
+		return JobRun{}, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load cron job run")
+	}
+	return newJobRun(run), nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
 )
@@ -18,6 +20,13 @@ type ServiceParams struct {
 	Lock     Lock
 	Metrics  *metrics.CronJobMetrics
 	Interval time.Duration
+	// DryRun only runs jobs that are themselves in dry-run mode and skips the rest, so a cycle
+	// reports intended mutations without applying any.
+	DryRun bool
+	// Runs holds the manual runs admins queue through the API. The service polls it every
+	// RunPollInterval (default 5s) between scheduled cycles.
+	Runs            RunRepository
+	RunPollInterval time.Duration
 }
 
 // Service executes registered cron jobs on a fixed cadence.
@@ -27,6 +36,18 @@ type Service struct {
 	lock     Lock
 	metrics  *metrics.CronJobMetrics
 	interval time.Duration
+	dryRun   bool
+	runs     RunRepository
+	runPoll  time.Duration
+}
+
+// JobResult summarizes a manually triggered job run.
+type JobResult struct {
+	Job        string `json:"job"`
+	DryRun     bool   `json:"dry_run"`
+	Succeeded  bool   `json:"succeeded"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
 }
 
 // NewService builds a cron service.
@@ -45,12 +66,19 @@ func NewService(params ServiceParams) (*Service, error) {
 	if interval <= 0 {
 		interval = defaultInterval
 	}
+	runPoll := params.RunPollInterval
+	if runPoll <= 0 {
+		runPoll = defaultRunPollInterval
+	}
 	return &Service{
 		logg:     params.Logger,
 		registry: registry,
 		lock:     params.Lock,
 		metrics:  params.Metrics,
 		interval: interval,
+		dryRun:   params.DryRun,
+		runs:     params.Runs,
+		runPoll:  runPoll,
 	}, nil
 }
 
@@ -65,6 +93,13 @@ func (s *Service) Run(ctx context.Context) error {
 	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
+	var pollC <-chan time.Time
+	if s.runs != nil {
+		poll := time.NewTicker(s.runPoll)
+		defer poll.Stop()
+		pollC = poll.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -74,6 +109,50 @@ func (s *Service) Run(ctx context.Context) error {
 			if err := s.runCycle(ctx); err != nil {
 				s.logg.Error(ctx, "scheduled run failed", err)
 			}
+		case <-pollC:
+			s.processQueuedRuns(ctx)
+		}
+	}
+}
+
+// processQueuedRuns runs the manual runs queued through the API, oldest first. A run that finds
+// the lock held by another instance goes back to the queue for the next poll.
+func (s *Service) processQueuedRuns(ctx context.Context) {
+	for {
+		run, err := s.runs.ClaimNextQueued(ctx, time.Now().UTC())
+		if err != nil {
+			s.logg.Error(ctx, "failed to claim queued cron job run", err)
+			return
+		}
+		if run == nil {
+			return
+		}
+
+		runCtx := s.logg.WithField(ctx, "cron_job_run_id", run.ID.String())
+		result, err := s.RunJob(runCtx, run.Job)
+		if err != nil {
+			if typed := pkgerrors.As(err); typed != nil && typed.Code() == pkgerrors.CodeConflict {
+				if reqErr := s.runs.Requeue(ctx, run.ID); reqErr != nil {
+					s.logg.Error(runCtx, "failed to requeue cron job run", reqErr)
+				}
+				return
+			}
+			result = JobResult{Job: run.Job, DryRun: s.dryRun, Error: err.Error()}
+		}
+
+		finishedAt := time.Now().UTC()
+		run.DryRun = result.DryRun
+		run.FinishedAt = &finishedAt
+		run.UpdatedAt = finishedAt
+		run.DurationMS = &result.DurationMS
+		run.Status = enums.CronJobRunStatusSucceeded
+		if !result.Succeeded {
+			run.Status = enums.CronJobRunStatusFailed
+			message := result.Error
+			run.Error = &message
+		}
+		if err := s.runs.Finish(ctx, run); err != nil {
+			s.logg.Error(runCtx, "failed to record cron job run", err)
 		}
 	}
 }
@@ -95,15 +174,60 @@ func (s *Service) runCycle(ctx context.Context) error {
 
 	s.logg.Info(ctx, "scheduled run starting")
 	for _, job := range s.registry.Jobs() {
+		if s.dryRun && !isDryRun(job) {
+			s.logg.Warn(s.logg.WithField(ctx, "job", job.Name()), "skipping job without dry-run support")
+			continue
+		}
 		s.runJob(ctx, job)
 	}
 	s.logg.Info(ctx, "scheduled run complete")
 	return nil
 }
 
-func (s *Service) runJob(ctx context.Context, job Job) {
+// RunJob runs one registered job now, holding the same lock as the scheduled cycle so the two
+// never overlap. A failing job is reported in the result rather than as an error.
+func (s *Service) RunJob(ctx context.Context, name string) (JobResult, error) {
+	job, ok := s.registry.Find(name)
+	if !ok {
+		return JobResult{}, pkgerrors.New(pkgerrors.CodeNotFound, "cron job not found")
+	}
+	if s.dryRun && !isDryRun(job) {
+		return JobResult{}, pkgerrors.New(pkgerrors.CodeStateConflict, "cron job does not support dry run")
+	}
+
+	locked, err := s.lock.Acquire(ctx)
+	if err != nil {
+		return JobResult{}, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "acquire cron lock")
+	}
+	if !locked {
+		return JobResult{}, pkgerrors.New(pkgerrors.CodeConflict, "cron jobs are already running")
+	}
+	defer func() {
+		if relErr := s.lock.Release(ctx); relErr != nil {
+			s.logg.Error(ctx, "failed to release cron lock", relErr)
+		}
+	}()
+
+	s.logg.Info(s.logg.WithField(ctx, "job", name), "manual job run requested")
+	duration, runErr := s.runJob(ctx, job)
+	result := JobResult{
+		Job:        job.Name(),
+		DryRun:     s.dryRun,
+		Succeeded:  runErr == nil,
+		DurationMS: duration.Milliseconds(),
+	}
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+	return result, nil
+}
+
+func (s *Service) runJob(ctx context.Context, job Job) (time.Duration, error) {
 	jobCtx := s.logg.WithField(ctx, "job", job.Name())
 	jobCtx = s.logg.WithField(jobCtx, "event", "cron.job")
+	if s.dryRun {
+		jobCtx = s.logg.WithField(jobCtx, "dry_run", true)
+	}
 	s.logg.Info(jobCtx, "job start")
 	start := time.Now()
 	err := job.Run(jobCtx)
@@ -113,10 +237,16 @@ func (s *Service) runJob(ctx context.Context, job Job) {
 	if err != nil {
 		s.logg.Error(jobCtx, "job failed", err)
 		s.recordFailure(job.Name())
-		return
+		return duration, err
 	}
 	s.logg.Info(jobCtx, "job completed")
 	s.recordSuccess(job.Name())
+	return duration, nil
+}
+
+func isDryRun(job Job) bool {
+	dryRunJob, ok := job.(DryRunJob)
+	return ok && dryRunJob.DryRun()
 }
 
 func (s *Service) observeDuration(job string, duration time.Duration) {
@@ -131,6 +261,7 @@ func (s *Service) recordSuccess(job string) {
 		return
 	}
 	s.metrics.IncSuccess(job)
+	s.metrics.SetLastSuccess(job, time.Now())
 }
 
 func (s *Service) recordFailure(job string) {
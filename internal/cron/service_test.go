@@ -1,11 +1,21 @@
 package cron
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type fakeLock struct {
@@ -35,6 +45,65 @@ func (t *testJob) Run(context.Context) error {
 	return t.err
 }
 
+type dryRunTestJob struct {
+	testJob
+	dryRun bool
+}
+
+func (t *dryRunTestJob) DryRun() bool { return t.dryRun }
+
+// newRecordedLogger returns a logger whose JSON output can be searched with loggedFields.
+func newRecordedLogger() (*logger.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return logger.New(logger.Options{ServiceName: "cron-test", Output: buf}), buf
+}
+
+// loggedFields returns the fields of the last log line with the given message.
+func loggedFields(t *testing.T, buf *bytes.Buffer, message string) map[string]any {
+	t.Helper()
+	var found map[string]any
+	for _, line := range bytes.Split(buf.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		if entry["message"] == message {
+			found = entry
+		}
+	}
+	if found == nil {
+		t.Fatalf("no log line with message %q in:\n%s", message, buf.String())
+	}
+	return found
+}
+
+func TestServiceDryRunSkipsJobsWithoutDryRun(t *testing.T) {
+	dryRunJob := &dryRunTestJob{testJob: testJob{name: "dry"}, dryRun: true}
+	liveJob := &dryRunTestJob{testJob: testJob{name: "live"}}
+	plainJob := &testJob{name: "plain"}
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(dryRunJob, liveJob, plainJob),
+		Lock:     &fakeLock{},
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+	if err := service.runCycle(context.Background()); err != nil {
+		t.Fatalf("run cycle: %v", err)
+	}
+	if dryRunJob.runs != 1 {
+		t.Fatalf("expected dry-run job to run once, ran %d", dryRunJob.runs)
+	}
+	if liveJob.runs != 0 || plainJob.runs != 0 {
+		t.Fatalf("expected jobs without dry run to be skipped, ran live=%d plain=%d", liveJob.runs, plainJob.runs)
+	}
+}
+
 func TestServiceRunCycleRunsAllJobsEvenOnFailure(t *testing.T) {
 	logg := logger.New(logger.Options{ServiceName: "cron-test"})
 	registry := NewRegistry(&testJob{name: "success"}, &testJob{name: "fail", err: errors.New("boom")})
@@ -70,3 +139,244 @@ func TestServiceRunCycleRunsAllJobsEvenOnFailure(t *testing.T) {
 		t.Fatalf("second job type mismatch")
 	}
 }
+
+func TestServiceRecordsLastSuccessOnlyForSucceededJobs(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(&testJob{name: "success"}, &testJob{name: "fail", err: errors.New("boom")}),
+		Lock:     &fakeLock{},
+		Metrics:  metrics.NewCronJobMetrics(reg),
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+	before := time.Now().Unix()
+	if err := service.runCycle(context.Background()); err != nil {
+		t.Fatalf("run cycle: %v", err)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	lastSuccess := map[string]float64{}
+	for _, mf := range mfs {
+		if mf.GetName() != "job_last_success_timestamp_seconds" {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "job" {
+					lastSuccess[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	if got, ok := lastSuccess["success"]; !ok || got < float64(before) {
+		t.Fatalf("expected last success timestamp for succeeded job, got %v (present=%t)", got, ok)
+	}
+	if _, ok := lastSuccess["fail"]; ok {
+		t.Fatalf("expected no last success timestamp for failed job")
+	}
+}
+
+type lockProbeJob struct {
+	lock       *fakeLock
+	heldDuring bool
+}
+
+func (j *lockProbeJob) Name() string { return "probe" }
+
+func (j *lockProbeJob) Run(context.Context) error {
+	j.heldDuring = j.lock.acquired
+	return nil
+}
+
+func TestServiceRunJobRunsNamedJobUnderLock(t *testing.T) {
+	lock := &fakeLock{}
+	job := &lockProbeJob{lock: lock}
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(&testJob{name: "other"}, job),
+		Lock:     lock,
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	result, err := service.RunJob(context.Background(), "probe")
+	if err != nil {
+		t.Fatalf("run job: %v", err)
+	}
+	if !job.heldDuring {
+		t.Fatal("expected the cron lock to be held while the job ran")
+	}
+	if lock.acquired {
+		t.Fatal("expected the cron lock to be released after the job")
+	}
+	if result.Job != "probe" || !result.Succeeded {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestServiceRunJobReportsJobFailure(t *testing.T) {
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(&testJob{name: "fail", err: errors.New("boom")}),
+		Lock:     &fakeLock{},
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	result, err := service.RunJob(context.Background(), "fail")
+	if err != nil {
+		t.Fatalf("run job: %v", err)
+	}
+	if result.Succeeded || result.Error != "boom" {
+		t.Fatalf("expected failed result with error, got %+v", result)
+	}
+}
+
+func TestServiceRunJobRejectsUnknownOrLockedJobs(t *testing.T) {
+	lock := &fakeLock{}
+	job := &testJob{name: "known"}
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(job),
+		Lock:     lock,
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	_, err = service.RunJob(context.Background(), "missing")
+	if err == nil || pkgerrors.As(err).Code() != pkgerrors.CodeNotFound {
+		t.Fatalf("expected not found for unknown job, got %v", err)
+	}
+
+	lock.acquired = true
+	_, err = service.RunJob(context.Background(), "known")
+	if err == nil || pkgerrors.As(err).Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict while the scheduled run holds the lock, got %v", err)
+	}
+	if job.runs != 0 {
+		t.Fatalf("expected job not to run, ran %d", job.runs)
+	}
+}
+
+type fakeRunRepository struct {
+	queued   []*models.CronJobRun
+	requeued []uuid.UUID
+	finished []*models.CronJobRun
+}
+
+func (f *fakeRunRepository) Create(_ context.Context, run *models.CronJobRun) error {
+	f.queued = append(f.queued, run)
+	return nil
+}
+
+func (f *fakeRunRepository) FindByID(context.Context, uuid.UUID) (*models.CronJobRun, error) {
+	return nil, nil
+}
+
+func (f *fakeRunRepository) ClaimNextQueued(context.Context, time.Time) (*models.CronJobRun, error) {
+	if len(f.queued) == 0 {
+		return nil, nil
+	}
+	run := f.queued[0]
+	f.queued = f.queued[1:]
+	run.Status = enums.CronJobRunStatusRunning
+	return run, nil
+}
+
+func (f *fakeRunRepository) Requeue(_ context.Context, id uuid.UUID) error {
+	f.requeued = append(f.requeued, id)
+	return nil
+}
+
+func (f *fakeRunRepository) Finish(_ context.Context, run *models.CronJobRun) error {
+	f.finished = append(f.finished, run)
+	return nil
+}
+
+func TestServiceProcessesQueuedRuns(t *testing.T) {
+	job := &testJob{name: "known"}
+	failing := &testJob{name: "broken", err: errors.New("boom")}
+	runs := &fakeRunRepository{}
+	queue, err := NewRunQueue(runs)
+	if err != nil {
+		t.Fatalf("new run queue: %v", err)
+	}
+	for _, name := range []string{"known", "broken", "missing"} {
+		if _, err := queue.Request(context.Background(), name, uuid.New()); err != nil {
+			t.Fatalf("queue %s: %v", name, err)
+		}
+	}
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(job, failing),
+		Lock:     &fakeLock{},
+		Runs:     runs,
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	service.processQueuedRuns(context.Background())
+
+	if job.runs != 1 || failing.runs != 1 {
+		t.Fatalf("expected each known job to run once, got %d and %d", job.runs, failing.runs)
+	}
+	if len(runs.finished) != 3 {
+		t.Fatalf("expected three finished runs, got %d", len(runs.finished))
+	}
+	want := map[string]enums.CronJobRunStatus{
+		"known":   enums.CronJobRunStatusSucceeded,
+		"broken":  enums.CronJobRunStatusFailed,
+		"missing": enums.CronJobRunStatusFailed,
+	}
+	for _, run := range runs.finished {
+		if run.Status != want[run.Job] {
+			t.Fatalf("expected %s to finish %s, got %s", run.Job, want[run.Job], run.Status)
+		}
+		if run.FinishedAt == nil {
+			t.Fatalf("expected %s to record when it finished", run.Job)
+		}
+		if run.Status == enums.CronJobRunStatusFailed && (run.Error == nil || *run.Error == "") {
+			t.Fatalf("expected %s to record its error", run.Job)
+		}
+	}
+}
+
+func TestServiceRequeuesRunWhileLocked(t *testing.T) {
+	job := &testJob{name: "known"}
+	runs := &fakeRunRepository{}
+	queue, err := NewRunQueue(runs)
+	if err != nil {
+		t.Fatalf("new run queue: %v", err)
+	}
+	queued, err := queue.Request(context.Background(), "known", uuid.Nil)
+	if err != nil {
+		t.Fatalf("queue run: %v", err)
+	}
+	service, err := NewService(ServiceParams{
+		Logger:   logger.New(logger.Options{ServiceName: "cron-test"}),
+		Registry: NewRegistry(job),
+		Lock:     &fakeLock{acquired: true},
+		Runs:     runs,
+	})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	service.processQueuedRuns(context.Background())
+
+	if job.runs != 0 || len(runs.finished) != 0 {
+		t.Fatalf("expected no run while the lock is held, got runs=%d finished=%d", job.runs, len(runs.finished))
+	}
+	if len(runs.requeued) != 1 || runs.requeued[0] != queued.ID {
+		t.Fatalf("expected the run requeued, got %v", runs.requeued)
+	}
+}
@@ -27,9 +27,12 @@ type SubscriptionReconcileJobParams struct {
 	BillingRepo  billing.Repository
 	StoreRepo    storesRepository
 	SquareClient subscriptions.SquareSubscriptionClient
-	Limit        int
-	Lookback     time.Duration
-	Now          func() time.Time
+	// Notifications, when set, receives the warning for lapses first seen by the job.
+	Notifications subscriptionNotificationRepo
+	GracePeriod   time.Duration
+	Limit         int
+	Lookback      time.Duration
+	Now           func() time.Time
 }
 
 // NewSubscriptionReconcileJob builds a reconciliation cron job.
@@ -49,6 +52,9 @@ func NewSubscriptionReconcileJob(params SubscriptionReconcileJobParams) (Job, er
 	if params.SquareClient == nil {
 		return nil, fmt.Errorf("square client required")
 	}
+	if params.GracePeriod < 0 {
+		return nil, fmt.Errorf("grace period must be non-negative")
+	}
 	now := params.Now
 	if now == nil {
 		now = time.Now
@@ -62,30 +68,40 @@ func NewSubscriptionReconcileJob(params SubscriptionReconcileJobParams) (Job, er
 		limit = defaultReconcileLimit
 	}
 	return &subscriptionReconcileJob{
-		logg:        params.Logger,
-		db:          params.DB,
-		billingRepo: params.BillingRepo,
-		storeRepo:   params.StoreRepo,
-		square:      params.SquareClient,
-		now:         now,
-		limit:       limit,
-		lookback:    lookback,
+		logg:          params.Logger,
+		db:            params.DB,
+		billingRepo:   params.BillingRepo,
+		storeRepo:     params.StoreRepo,
+		square:        params.SquareClient,
+		notifications: params.Notifications,
+		grace:         params.GracePeriod,
+		now:           now,
+		limit:         limit,
+		lookback:      lookback,
 	}, nil
 }
 
+type subscriptionNotificationRepo interface {
+	CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error
+}
+
 type storesRepository interface {
-	UpdateSubscriptionActiveWithTx(tx *gorm.DB, storeID uuid.UUID, active bool) error
+	FindByIDWithTx(tx *gorm.DB, id uuid.UUID) (*models.Store, error)
+	UpdateWithTx(tx *gorm.DB, store *models.Store) error
+	ExpireSubscriptionGrace(ctx context.Context, now time.Time) (int64, error)
 }
 
 type subscriptionReconcileJob struct {
-	logg        *logger.Logger
-	db          txRunner
-	billingRepo billing.Repository
-	storeRepo   storesRepository
-	square      subscriptions.SquareSubscriptionClient
-	now         func() time.Time
-	limit       int
-	lookback    time.Duration
+	logg          *logger.Logger
+	db            txRunner
+	billingRepo   billing.Repository
+	storeRepo     storesRepository
+	square        subscriptions.SquareSubscriptionClient
+	notifications subscriptionNotificationRepo
+	grace         time.Duration
+	now           func() time.Time
+	limit         int
+	lookback      time.Duration
 }
 
 func (j *subscriptionReconcileJob) Name() string { return "subscription-reconcile" }
@@ -93,6 +109,11 @@ func (j *subscriptionReconcileJob) Name() string { return "subscription-reconcil
 func (j *subscriptionReconcileJob) Run(ctx context.Context) error {
 	logCtx := j.logg.WithField(ctx, "job", j.Name())
 	logCtx = j.logg.WithField(logCtx, "event", "cron.job")
+	// Grace windows can end without any further Square event, so revoke those stores up front.
+	expired, err := j.storeRepo.ExpireSubscriptionGrace(logCtx, j.now().UTC())
+	if err != nil {
+		return fmt.Errorf("expire subscription grace: %w", err)
+	}
 	snapshot, err := j.billingRepo.ListSubscriptionsForReconciliation(logCtx, j.limit, j.lookback)
 	if err != nil {
 		return fmt.Errorf("list subscriptions for reconciliation: %w", err)
@@ -108,8 +129,9 @@ func (j *subscriptionReconcileJob) Run(ctx context.Context) error {
 		synced++
 	}
 	reportCtx := j.logg.WithFields(logCtx, map[string]any{
-		"candidates": scanned,
-		"synced":     synced,
+		"candidates":    scanned,
+		"synced":        synced,
+		"grace_expired": expired,
 	})
 	j.logg.Info(reportCtx, "subscription reconcile loop complete")
 	return errs
@@ -151,13 +173,27 @@ func (j *subscriptionReconcileJob) reconcileSubscription(ctx context.Context, su
 		if err := repo.UpdateSubscription(logCtx, stored); err != nil {
 			return err
 		}
-		active := deriveStoreSubscriptionActive(j.now(), squareSub, stored)
-		if err := j.storeRepo.UpdateSubscriptionActiveWithTx(tx, stored.StoreID, active); err != nil {
+		now := j.now()
+		active := subscriptions.StoreEntitled(now, squareSub, stored)
+		store, err := j.storeRepo.FindByIDWithTx(tx, stored.StoreID)
+		if err != nil {
 			return err
 		}
+		change := subscriptions.ApplyStoreEntitlement(store, active, now, j.grace)
+		if change.Changed {
+			if err := j.storeRepo.UpdateWithTx(tx, store); err != nil {
+				return err
+			}
+		}
+		if change.GraceStarted && j.notifications != nil {
+			if err := j.notifications.CreateWithTx(logCtx, tx, subscriptions.GraceWarningNotification(store.ID, *store.SubscriptionGraceUntil)); err != nil {
+				return err
+			}
+		}
 		successCtx := j.logg.WithFields(logCtx, map[string]any{
 			"square_status": squareSub.Status,
 			"entitled":      active,
+			"store_active":  store.SubscriptionActive,
 		})
 		j.logg.Info(successCtx, "subscription reconciled")
 		return nil
@@ -208,40 +244,9 @@ func pendingCancelAction(actions []*subscriptions.SquareSubscriptionAction) *sub
 	return nil
 }
 
-func deriveStoreSubscriptionActive(now time.Time, square *subscriptions.SquareSubscription, stored *models.Subscription) bool {
-	if square == nil {
-		return false
-	}
-	if cancel := pendingCancelAction(square.Actions); cancel != nil {
-		if t, ok := actionTime(cancel); ok && !now.Before(t) {
-			return false
-		}
-	}
-	if pause := pendingPauseAction(square.Actions); pause != nil {
-		if t, ok := actionTime(pause); ok && !now.Before(t) {
-			return false
-		}
-	}
-	entitledUntil := timeFromUnix(square.ChargedThroughDate)
-	if entitledUntil.IsZero() && stored != nil {
-		entitledUntil = stored.CurrentPeriodEnd
-	}
-	if entitledUntil.IsZero() {
-		return strings.EqualFold(square.Status, "ACTIVE")
-	}
-	return !now.After(entitledUntil)
-}
-
 func actionTime(action *subscriptions.SquareSubscriptionAction) (time.Time, bool) {
 	if action == nil || action.EffectiveDate == 0 {
 		return time.Time{}, false
 	}
 	return time.Unix(action.EffectiveDate, 0).UTC(), true
 }
-
-func timeFromUnix(ts int64) time.Time {
-	if ts == 0 {
-		return time.Time{}
-	}
-	return time.Unix(ts, 0).UTC()
-}
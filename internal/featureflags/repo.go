@@ -0,0 +1,70 @@
+package featureflags
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository persists runtime flag overrides.
+type Repository interface {
+	ListByEnvironment(ctx context.Context, environment string) ([]models.FeatureFlag, error)
+	Upsert(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, environment, key string, storeID *uuid.UUID) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository binds the feature flag repository to the database.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListByEnvironment(ctx context.Context, environment string) ([]models.FeatureFlag, error) {
+	var rows []models.FeatureFlag
+	err := r.db.WithContext(ctx).
+		Where("environment = ?", environment).
+		Order("key ASC").
+		Order("created_at ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// Upsert updates the override matching (environment, key, store) or inserts it when missing.
+func (r *repository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		res := scopeOverride(tx.Model(&models.FeatureFlag{}), flag.Environment, flag.Key, flag.StoreID).
+			Updates(map[string]any{"enabled": flag.Enabled, "updated_at": now})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+		if flag.ID == uuid.Nil {
+			flag.ID = uuid.New()
+		}
+		flag.CreatedAt = now
+		flag.UpdatedAt = now
+		return tx.Create(flag).Error
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, environment, key string, storeID *uuid.UUID) (bool, error) {
+	res := scopeOverride(r.db.WithContext(ctx), environment, key, storeID).Delete(&models.FeatureFlag{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func scopeOverride(q *gorm.DB, environment, key string, storeID *uuid.UUID) *gorm.DB {
+	q = q.Where("environment = ? AND key = ?", environment, key)
+	if storeID == nil {
+		return q.Where("store_id IS NULL")
+	}
+	return q.Where("store_id = ?", *storeID)
+}
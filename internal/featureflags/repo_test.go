@@ -0,0 +1,67 @@
+package featureflags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRepositoryUpsertAndDelete(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE feature_flags (
+  id TEXT PRIMARY KEY,
+  key TEXT NOT NULL,
+  environment TEXT NOT NULL,
+  store_id TEXT,
+  enabled INTEGER NOT NULL,
+  created_at DATETIME,
+  updated_at DATETIME
+);`).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	repo := NewRepository(db)
+	ctx := context.Background()
+	storeID := uuid.New()
+
+	for _, flag := range []*models.FeatureFlag{
+		{Key: FlagAllowACH, Environment: "dev", Enabled: true},
+		{Key: FlagAllowACH, Environment: "dev", StoreID: &storeID, Enabled: true},
+		{Key: FlagAllowACH, Environment: "dev", Enabled: false},
+	} {
+		if err := repo.Upsert(ctx, flag); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+	}
+
+	rows, err := repo.ListByEnvironment(ctx, "dev")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected environment and store overrides only, got %d rows", len(rows))
+	}
+	for _, row := range rows {
+		if row.StoreID == nil && row.Enabled {
+			t.Fatalf("expected the environment override to be updated in place")
+		}
+	}
+
+	deleted, err := repo.Delete(ctx, "dev", FlagAllowACH, &storeID)
+	if err != nil || !deleted {
+		t.Fatalf("delete store override: deleted=%v err=%v", deleted, err)
+	}
+	deleted, err = repo.Delete(ctx, "dev", FlagAllowACH, &storeID)
+	if err != nil || deleted {
+		t.Fatalf("expected second delete to be a no-op: deleted=%v err=%v", deleted, err)
+	}
+	if rows, _ := repo.ListByEnvironment(ctx, "dev"); len(rows) != 1 || rows[0].StoreID != nil {
+		t.Fatalf("expected only the environment override to remain")
+	}
+}
@@ -0,0 +1,242 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// FlagAllowACH gates `payment_method=ach` at checkout.
+const FlagAllowACH = "allow_ach"
+
+const defaultRefreshInterval = 30 * time.Second
+
+// Defaults seeds the known flags from static config. Only keys listed here can be overridden.
+func Defaults(cfg config.FeatureFlagsConfig) map[string]bool {
+	return map[string]bool{
+		FlagAllowACH: cfg.AllowACH,
+	}
+}
+
+// Service resolves flags as store override, then environment override, then config default.
+type Service interface {
+	Enabled(ctx context.Context, key string, storeID *uuid.UUID) bool
+	List(ctx context.Context) ([]Flag, error)
+	Set(ctx context.Context, input SetInput) error
+	Clear(ctx context.Context, key string, storeID *uuid.UUID) error
+}
+
+// Flag is the admin view of a flag and its overrides in the current environment.
+type Flag struct {
+	Key            string          `json:"key"`
+	Default        bool            `json:"default"`
+	Enabled        bool            `json:"enabled"`
+	Overridden     bool            `json:"overridden"`
+	StoreOverrides []StoreOverride `json:"store_overrides"`
+}
+
+// StoreOverride is a per-store value for a flag.
+type StoreOverride struct {
+	StoreID uuid.UUID `json:"store_id"`
+	Enabled bool      `json:"enabled"`
+}
+
+// SetInput overrides a flag for the environment, or for one store when StoreID is set.
+type SetInput struct {
+	Key     string
+	StoreID *uuid.UUID
+	Enabled bool
+}
+
+type snapshot struct {
+	global   map[string]bool
+	stores   map[string]map[uuid.UUID]bool
+	loadedAt time.Time
+}
+
+type service struct {
+	repo        Repository
+	environment string
+	defaults    map[string]bool
+	refresh     time.Duration
+	now         func() time.Time
+
+	mu      sync.RWMutex
+	current *snapshot
+}
+
+// NewService wires the flag service. Overrides are cached in memory and reloaded once they
+// are older than refresh, so changes made on another instance show up within that window.
+func NewService(repo Repository, environment string, defaults map[string]bool, refresh time.Duration) (Service, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("feature flag repository required")
+	}
+	environment = strings.ToLower(strings.TrimSpace(environment))
+	if environment == "" {
+		return nil, fmt.Errorf("feature flag environment required")
+	}
+	if refresh <= 0 {
+		refresh = defaultRefreshInterval
+	}
+	seeded := make(map[string]bool, len(defaults))
+	for key, enabled := range defaults {
+		seeded[key] = enabled
+	}
+	return &service{
+		repo:        repo,
+		environment: environment,
+		defaults:    seeded,
+		refresh:     refresh,
+		now:         time.Now,
+	}, nil
+}
+
+func (s *service) Enabled(ctx context.Context, key string, storeID *uuid.UUID) bool {
+	snap := s.snapshot(ctx)
+	if storeID != nil {
+		if enabled, ok := snap.stores[key][*storeID]; ok {
+			return enabled
+		}
+	}
+	if enabled, ok := snap.global[key]; ok {
+		return enabled
+	}
+	return s.defaults[key]
+}
+
+func (s *service) List(ctx context.Context) ([]Flag, error) {
+	rows, err := s.repo.ListByEnvironment(ctx, s.environment)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list feature flags")
+	}
+	snap := buildSnapshot(rows, s.now())
+
+	keys := make([]string, 0, len(s.defaults))
+	for key := range s.defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]Flag, 0, len(keys))
+	for _, key := range keys {
+		flag := Flag{Key: key, Default: s.defaults[key], Enabled: s.defaults[key], StoreOverrides: []StoreOverride{}}
+		if enabled, ok := snap.global[key]; ok {
+			flag.Enabled = enabled
+			flag.Overridden = true
+		}
+		for storeID, enabled := range snap.stores[key] {
+			flag.StoreOverrides = append(flag.StoreOverrides, StoreOverride{StoreID: storeID, Enabled: enabled})
+		}
+		sort.Slice(flag.StoreOverrides, func(i, j int) bool {
+			return flag.StoreOverrides[i].StoreID.String() < flag.StoreOverrides[j].StoreID.String()
+		})
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (s *service) Set(ctx context.Context, input SetInput) error {
+	key, err := s.knownKey(input.Key)
+	if err != nil {
+		return err
+	}
+	flag := &models.FeatureFlag{
+		Key:         key,
+		Environment: s.environment,
+		StoreID:     input.StoreID,
+		Enabled:     input.Enabled,
+	}
+	if err := s.repo.Upsert(ctx, flag); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "save feature flag")
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *service) Clear(ctx context.Context, key string, storeID *uuid.UUID) error {
+	key, err := s.knownKey(key)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repo.Delete(ctx, s.environment, key, storeID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "clear feature flag")
+	}
+	if !deleted {
+		return pkgerrors.New(pkgerrors.CodeNotFound, "feature flag override not found")
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *service) knownKey(raw string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if _, ok := s.defaults[key]; !ok {
+		return "", pkgerrors.New(pkgerrors.CodeValidation, "unknown feature flag")
+	}
+	return key, nil
+}
+
+// snapshot returns the cached overrides, reloading them when the cache is stale. A failed
+// reload keeps serving the previous overrides (or the config defaults) until the next interval.
+func (s *service) snapshot(ctx context.Context) *snapshot {
+	now := s.now()
+	s.mu.RLock()
+	current := s.current
+	s.mu.RUnlock()
+	if current != nil && now.Sub(current.loadedAt) < s.refresh {
+		return current
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil && now.Sub(s.current.loadedAt) < s.refresh {
+		return s.current
+	}
+	rows, err := s.repo.ListByEnvironment(ctx, s.environment)
+	if err != nil {
+		if s.current == nil {
+			s.current = buildSnapshot(nil, now)
+		} else {
+			stale := *s.current
+			stale.loadedAt = now
+			s.current = &stale
+		}
+		return s.current
+	}
+	s.current = buildSnapshot(rows, now)
+	return s.current
+}
+
+func (s *service) invalidate() {
+	s.mu.Lock()
+	s.current = nil
+	s.mu.Unlock()
+}
+
+func buildSnapshot(rows []models.FeatureFlag, loadedAt time.Time) *snapshot {
+	snap := &snapshot{
+		global:   map[string]bool{},
+		stores:   map[string]map[uuid.UUID]bool{},
+		loadedAt: loadedAt,
+	}
+	for _, row := range rows {
+		if row.StoreID == nil {
+			snap.global[row.Key] = row.Enabled
+			continue
+		}
+		if snap.stores[row.Key] == nil {
+			snap.stores[row.Key] = map[uuid.UUID]bool{}
+		}
+		snap.stores[row.Key][*row.StoreID] = row.Enabled
+	}
+	return snap
+}
@@ -0,0 +1,132 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/google/uuid"
+)
+
+type stubRepository struct {
+	rows  []models.FeatureFlag
+	err   error
+	loads int
+}
+
+func (s *stubRepository) ListByEnvironment(_ context.Context, environment string) ([]models.FeatureFlag, error) {
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	var rows []models.FeatureFlag
+	for _, row := range s.rows {
+		if row.Environment == environment {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (s *stubRepository) Upsert(_ context.Context, flag *models.FeatureFlag) error {
+	s.rows = append(s.rows, *flag)
+	return nil
+}
+
+func (s *stubRepository) Delete(context.Context, string, string, *uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func newTestService(t *testing.T, repo Repository, now *time.Time) *service {
+	t.Helper()
+	svc, err := NewService(repo, "dev", map[string]bool{FlagAllowACH: false}, time.Minute)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	impl := svc.(*service)
+	impl.now = func() time.Time { return *now }
+	return impl
+}
+
+func TestServiceRuntimeOverrideTakesPrecedence(t *testing.T) {
+	now := time.Now()
+	storeID := uuid.New()
+	otherStore := uuid.New()
+	repo := &stubRepository{rows: []models.FeatureFlag{
+		{Key: FlagAllowACH, Environment: "dev", Enabled: true},
+		{Key: FlagAllowACH, Environment: "dev", StoreID: &storeID, Enabled: false},
+		{Key: FlagAllowACH, Environment: "prod", Enabled: false},
+	}}
+	svc := newTestService(t, repo, &now)
+	ctx := context.Background()
+
+	if !svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected environment override to beat the config default")
+	}
+	if !svc.Enabled(ctx, FlagAllowACH, &otherStore) {
+		t.Fatalf("expected stores without an override to use the environment value")
+	}
+	if svc.Enabled(ctx, FlagAllowACH, &storeID) {
+		t.Fatalf("expected store override to beat the environment value")
+	}
+	if svc.Enabled(ctx, "unknown", nil) {
+		t.Fatalf("expected unknown flags to be off")
+	}
+	if repo.loads != 1 {
+		t.Fatalf("expected one load for cached reads, got %d", repo.loads)
+	}
+}
+
+func TestServiceCacheRefreshes(t *testing.T) {
+	now := time.Now()
+	repo := &stubRepository{}
+	svc := newTestService(t, repo, &now)
+	ctx := context.Background()
+
+	if svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected config default before any override")
+	}
+
+	// Another instance flips the flag; this one keeps the cached value until it is stale.
+	repo.rows = append(repo.rows, models.FeatureFlag{Key: FlagAllowACH, Environment: "dev", Enabled: true})
+	now = now.Add(30 * time.Second)
+	if svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected cached value within the refresh interval")
+	}
+	now = now.Add(31 * time.Second)
+	if !svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected refreshed override after the interval")
+	}
+
+	// A failed reload keeps serving the last overrides.
+	repo.err = errors.New("db down")
+	now = now.Add(2 * time.Minute)
+	if !svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected last known value when reload fails")
+	}
+	if repo.loads != 3 {
+		t.Fatalf("expected 3 loads, got %d", repo.loads)
+	}
+}
+
+func TestServiceSetInvalidatesCache(t *testing.T) {
+	now := time.Now()
+	repo := &stubRepository{}
+	svc := newTestService(t, repo, &now)
+	ctx := context.Background()
+
+	if svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected config default before any override")
+	}
+	if err := svc.Set(ctx, SetInput{Key: " ALLOW_ACH ", Enabled: true}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if !svc.Enabled(ctx, FlagAllowACH, nil) {
+		t.Fatalf("expected local writes to apply without waiting for the interval")
+	}
+	if err := svc.Set(ctx, SetInput{Key: "new_checkout", Enabled: true}); err == nil {
+		t.Fatalf("expected unknown flag keys to be rejected")
+	}
+}
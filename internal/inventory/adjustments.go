@@ -0,0 +1,61 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdjustmentActor identifies who caused an inventory change. System jobs leave both fields nil.
+type AdjustmentActor struct {
+	UserID  *uuid.UUID
+	StoreID *uuid.UUID
+}
+
+// NewAdjustmentActor builds an actor, treating nil UUIDs as absent.
+func NewAdjustmentActor(userID, storeID uuid.UUID) AdjustmentActor {
+	var actor AdjustmentActor
+	if userID != uuid.Nil {
+		actor.UserID = &userID
+	}
+	if storeID != uuid.Nil {
+		actor.StoreID = &storeID
+	}
+	return actor
+}
+
+// RecordAdjustment appends an inventory_adjustments row inside the caller's transaction.
+// Zero deltas are skipped so no-op edits do not clutter the history. Callers record the adjustment
+// after updating the counts, so the inventory_changed snapshot emitted here carries the new counts
+// and stock increases also re-arm the product's low-stock alert.
+func RecordAdjustment(ctx context.Context, tx *gorm.DB, publisher OutboxPublisher, productID uuid.UUID, availableDelta, reservedDelta int, reason enums.InventoryAdjustmentReason, actor AdjustmentActor) error {
+	if availableDelta == 0 && reservedDelta == 0 {
+		return nil
+	}
+	if tx == nil {
+		return pkgerrors.New(pkgerrors.CodeDependency, "transaction required for inventory adjustment")
+	}
+	adjustment := &models.InventoryAdjustment{
+		ID:             uuid.New(),
+		ProductID:      productID,
+		AvailableDelta: availableDelta,
+		ReservedDelta:  reservedDelta,
+		Reason:         reason,
+		ActorUserID:    actor.UserID,
+		ActorStoreID:   actor.StoreID,
+	}
+	if err := tx.WithContext(ctx).Create(adjustment).Error; err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record inventory adjustment")
+	}
+	if err := EmitInventoryChanged(ctx, tx, publisher, productID, availableDelta, reservedDelta, reason); err != nil {
+		return err
+	}
+	if availableDelta > 0 {
+		return RearmLowStockAlert(ctx, tx, productID)
+	}
+	return nil
+}
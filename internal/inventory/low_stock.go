@@ -0,0 +1,25 @@
+package inventory
+
+import (
+	"context"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RearmLowStockAlert clears a product's low-stock alert marker once available stock is back at or
+// above the threshold the alert was raised at, so the next drop below it alerts again even when the
+// restock and the drop both happen between two runs of the alert job.
+func RearmLowStockAlert(ctx context.Context, tx *gorm.DB, productID uuid.UUID) error {
+	if tx == nil {
+		return pkgerrors.New(pkgerrors.CodeDependency, "transaction required for low stock re-arm")
+	}
+	err := tx.WithContext(ctx).Exec(`UPDATE inventory_items
+SET low_stock_alerted_at = NULL, low_stock_alert_threshold = NULL
+WHERE product_id = ? AND low_stock_alerted_at IS NOT NULL AND available_qty >= low_stock_alert_threshold`, productID).Error
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "re-arm low stock alert")
+	}
+	return nil
+}
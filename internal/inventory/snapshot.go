@@ -0,0 +1,65 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxPublisher is the shared outbox service that queues inventory events.
+type OutboxPublisher interface {
+	Emit(ctx context.Context, tx *gorm.DB, event outbox.DomainEvent) error
+}
+
+// EmitInventoryChanged queues an inventory_changed outbox event carrying the product's counters
+// after the change, inside the caller's transaction, so the analytics pipeline sees every
+// reservation, release, and vendor edit that commits and none that roll back.
+func EmitInventoryChanged(ctx context.Context, tx *gorm.DB, publisher OutboxPublisher, productID uuid.UUID, availableDelta, reservedDelta int, reason enums.InventoryAdjustmentReason) error {
+	if tx == nil {
+		return pkgerrors.New(pkgerrors.CodeDependency, "transaction required for inventory snapshot")
+	}
+	if publisher == nil {
+		return pkgerrors.New(pkgerrors.CodeDependency, "outbox publisher required for inventory snapshot")
+	}
+	var snapshot struct {
+		AvailableQty int
+		ReservedQty  int
+		StoreID      uuid.UUID
+	}
+	err := tx.WithContext(ctx).Raw(`SELECT ii.available_qty, ii.reserved_qty, p.store_id
+FROM inventory_items ii
+JOIN products p ON p.id = ii.product_id
+WHERE ii.product_id = ?`, productID).Scan(&snapshot).Error
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load inventory snapshot")
+	}
+
+	changedAt := time.Now().UTC()
+	event := outbox.DomainEvent{
+		EventType:     enums.EventInventoryChanged,
+		AggregateType: enums.AggregateProduct,
+		AggregateID:   productID,
+		Version:       payloads.DefaultVersion,
+		OccurredAt:    changedAt,
+		Data: payloads.InventoryChangedEvent{
+			ProductID:      productID,
+			VendorStoreID:  snapshot.StoreID,
+			AvailableQty:   snapshot.AvailableQty,
+			ReservedQty:    snapshot.ReservedQty,
+			AvailableDelta: availableDelta,
+			ReservedDelta:  reservedDelta,
+			Reason:         reason,
+			ChangedAt:      changedAt,
+		},
+	}
+	if err := publisher.Emit(ctx, tx, event); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "emit inventory changed event")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package inventory
+
+import (
+	"errors"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// ErrVersionConflict signals that an inventory row changed between read and write. Callers should
+// retry the surrounding transaction.
+var ErrVersionConflict = errors.New("inventory version conflict")
+
+// IsVersionConflict reports whether err was caused by a lost inventory compare-and-swap.
+func IsVersionConflict(err error) bool {
+	return errors.Is(err, ErrVersionConflict)
+}
+
+// NewVersionConflictError wraps ErrVersionConflict in the API-facing conflict error.
+func NewVersionConflictError(productID uuid.UUID) error {
+	return pkgerrors.Wrap(pkgerrors.CodeConflict, ErrVersionConflict, "inventory changed concurrently").WithDetails(map[string]any{
+		"product_id": productID,
+	})
+}
@@ -0,0 +1,134 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const basisPointsPerUnit = 10000
+
+// DeliveryFeePolicy prices an agent's earning for one delivered order.
+type DeliveryFeePolicy struct {
+	BaseCents int
+	RateBps   int
+}
+
+// NewDeliveryFeePolicy builds the policy from config, rejecting negative amounts.
+func NewDeliveryFeePolicy(cfg config.AgentConfig) (DeliveryFeePolicy, error) {
+	if cfg.DeliveryFeeBaseCents < 0 {
+		return DeliveryFeePolicy{}, pkgerrors.New(pkgerrors.CodeValidation, "agent delivery fee base must not be negative")
+	}
+	if cfg.DeliveryFeeBps < 0 || cfg.DeliveryFeeBps > basisPointsPerUnit {
+		return DeliveryFeePolicy{}, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("agent delivery fee bps must be between 0 and %d", basisPointsPerUnit))
+	}
+	return DeliveryFeePolicy{
+		BaseCents: cfg.DeliveryFeeBaseCents,
+		RateBps:   cfg.DeliveryFeeBps,
+	}, nil
+}
+
+// FeeFor returns the base fee plus the rate applied to orderTotalCents, rounded half up.
+func (p DeliveryFeePolicy) FeeFor(orderTotalCents int) int {
+	share := 0
+	if p.RateBps > 0 && orderTotalCents > 0 {
+		share = (orderTotalCents*p.RateBps + basisPointsPerUnit/2) / basisPointsPerUnit
+	}
+	return p.BaseCents + share
+}
+
+// AgentEarningInput identifies the delivered order an agent is paid for.
+type AgentEarningInput struct {
+	OrderID         uuid.UUID
+	BuyerStoreID    uuid.UUID
+	VendorStoreID   uuid.UUID
+	AgentUserID     uuid.UUID
+	OrderTotalCents int
+}
+
+type agentEarningMetadata struct {
+	OrderTotalCents int `json:"order_total_cents"`
+	BaseCents       int `json:"base_cents"`
+	RateBps         int `json:"rate_bps"`
+}
+
+// AgentOrderEarning is one delivered order in an agent earnings summary.
+type AgentOrderEarning struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	AmountCents int       `json:"amount_cents"`
+	EarnedAt    time.Time `json:"earned_at"`
+}
+
+// AgentEarningsSummary totals an agent's delivery fees over [From, To).
+type AgentEarningsSummary struct {
+	AgentUserID uuid.UUID           `json:"agent_user_id"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	TotalCents  int                 `json:"total_cents"`
+	OrderCount  int                 `json:"order_count"`
+	Orders      []AgentOrderEarning `json:"orders"`
+}
+
+// RecordAgentEarning appends an agent_delivery_fee event priced by the service's fee policy.
+// Callers guard against duplicates with HasEvent, as with cash collection.
+func (s *service) RecordAgentEarning(ctx context.Context, input AgentEarningInput) (*models.LedgerEvent, error) {
+	if input.OrderTotalCents < 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "order total must not be negative")
+	}
+	metadata, err := json.Marshal(agentEarningMetadata{
+		OrderTotalCents: input.OrderTotalCents,
+		BaseCents:       s.fees.BaseCents,
+		RateBps:         s.fees.RateBps,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeInternal, err, "encode agent earning metadata")
+	}
+	return s.RecordEvent(ctx, RecordLedgerEventInput{
+		OrderID:       input.OrderID,
+		BuyerStoreID:  input.BuyerStoreID,
+		VendorStoreID: input.VendorStoreID,
+		ActorUserID:   input.AgentUserID,
+		Type:          enums.LedgerEventTypeAgentDeliveryFee,
+		AmountCents:   s.fees.FeeFor(input.OrderTotalCents),
+		Metadata:      metadata,
+	})
+}
+
+// AgentEarnings sums the agent's delivery fees recorded in [from, to) with a per-order breakdown.
+func (s *service) AgentEarnings(ctx context.Context, agentUserID uuid.UUID, from, to time.Time) (*AgentEarningsSummary, error) {
+	if agentUserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "agent user id is required")
+	}
+	if !from.Before(to) {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "earnings window start must be before end")
+	}
+
+	events, err := s.repo.ListByActorAndType(ctx, agentUserID, enums.LedgerEventTypeAgentDeliveryFee, from, to)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list agent earnings")
+	}
+
+	summary := &AgentEarningsSummary{
+		AgentUserID: agentUserID,
+		From:        from,
+		To:          to,
+		Orders:      make([]AgentOrderEarning, 0, len(events)),
+	}
+	for _, event := range events {
+		summary.TotalCents += event.AmountCents
+		summary.Orders = append(summary.Orders, AgentOrderEarning{
+			OrderID:     event.OrderID,
+			AmountCents: event.AmountCents,
+			EarnedAt:    event.CreatedAt,
+		})
+	}
+	summary.OrderCount = len(summary.Orders)
+	return summary, nil
+}
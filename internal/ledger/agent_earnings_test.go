@@ -0,0 +1,116 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+func TestNewDeliveryFeePolicy(t *testing.T) {
+	policy, err := NewDeliveryFeePolicy(config.AgentConfig{DeliveryFeeBaseCents: 500, DeliveryFeeBps: 250})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := policy.FeeFor(10000); got != 750 {
+		t.Fatalf("expected 750 got %d", got)
+	}
+	// 2.5% of 1,234 cents is 30.85, which rounds to 31.
+	if got := policy.FeeFor(1234); got != 531 {
+		t.Fatalf("expected 531 got %d", got)
+	}
+
+	if _, err := NewDeliveryFeePolicy(config.AgentConfig{DeliveryFeeBaseCents: -1}); err == nil {
+		t.Fatal("expected negative base to be rejected")
+	}
+	if _, err := NewDeliveryFeePolicy(config.AgentConfig{DeliveryFeeBps: 10001}); err == nil {
+		t.Fatal("expected rate above 100% to be rejected")
+	}
+}
+
+func TestService_RecordAgentEarning(t *testing.T) {
+	var created *models.LedgerEvent
+	repo := &fakeRepository{
+		createFn: func(ctx context.Context, event *models.LedgerEvent) error {
+			created = event
+			return nil
+		},
+	}
+	svc, err := NewService(repo, DeliveryFeePolicy{BaseCents: 500, RateBps: 100})
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+
+	input := AgentEarningInput{
+		OrderID:         uuid.New(),
+		BuyerStoreID:    uuid.New(),
+		VendorStoreID:   uuid.New(),
+		AgentUserID:     uuid.New(),
+		OrderTotalCents: 20000,
+	}
+	if _, err := svc.RecordAgentEarning(context.Background(), input); err != nil {
+		t.Fatalf("RecordAgentEarning error: %v", err)
+	}
+	if created == nil {
+		t.Fatal("expected ledger event to be created")
+	}
+	if created.Type != enums.LedgerEventTypeAgentDeliveryFee || created.AmountCents != 700 {
+		t.Fatalf("unexpected agent earning %s %d", created.Type, created.AmountCents)
+	}
+	if created.ActorUserID != input.AgentUserID || created.OrderID != input.OrderID {
+		t.Fatalf("unexpected earning ownership %+v", created)
+	}
+	var metadata agentEarningMetadata
+	if err := json.Unmarshal(created.Metadata, &metadata); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	if metadata.OrderTotalCents != 20000 || metadata.BaseCents != 500 || metadata.RateBps != 100 {
+		t.Fatalf("unexpected metadata %+v", metadata)
+	}
+}
+
+func TestService_AgentEarnings(t *testing.T) {
+	agentID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	first := models.LedgerEvent{OrderID: uuid.New(), AmountCents: 500, CreatedAt: from.Add(time.Hour)}
+	second := models.LedgerEvent{OrderID: uuid.New(), AmountCents: 725, CreatedAt: from.Add(48 * time.Hour)}
+
+	repo := &fakeRepository{
+		listByActor: func(ctx context.Context, actorUserID uuid.UUID, eventType enums.LedgerEventType, gotFrom, gotTo time.Time) ([]models.LedgerEvent, error) {
+			if actorUserID != agentID || eventType != enums.LedgerEventTypeAgentDeliveryFee {
+				t.Fatalf("unexpected query %s %s", actorUserID, eventType)
+			}
+			if !gotFrom.Equal(from) || !gotTo.Equal(to) {
+				t.Fatalf("unexpected window %s - %s", gotFrom, gotTo)
+			}
+			return []models.LedgerEvent{first, second}, nil
+		},
+	}
+	svc, err := NewService(repo, DeliveryFeePolicy{})
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+
+	summary, err := svc.AgentEarnings(context.Background(), agentID, from, to)
+	if err != nil {
+		t.Fatalf("AgentEarnings error: %v", err)
+	}
+	if summary.TotalCents != 1225 || summary.OrderCount != 2 {
+		t.Fatalf("unexpected totals %d over %d orders", summary.TotalCents, summary.OrderCount)
+	}
+	if summary.Orders[0].OrderID != first.OrderID || summary.Orders[1].AmountCents != 725 {
+		t.Fatalf("unexpected breakdown %+v", summary.Orders)
+	}
+
+	_, err = svc.AgentEarnings(context.Background(), agentID, to, from)
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected inverted window to be rejected as validation, got %v", err)
+	}
+}
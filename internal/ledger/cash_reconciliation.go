@@ -0,0 +1,134 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	dbpkg "github.com/angelmondragon/packfinderz-backend/pkg/db"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// OutstandingCash is cash an agent collected for an order but has not yet handed in.
+type OutstandingCash struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	AmountCents int       `json:"amount_cents"`
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// AgentCashReconciliation compares the cash an agent holds with what they have remitted.
+type AgentCashReconciliation struct {
+	AgentUserID      uuid.UUID         `json:"agent_user_id"`
+	OutstandingCents int               `json:"outstanding_cents"`
+	RemittedCents    int               `json:"remitted_cents"`
+	Outstanding      []OutstandingCash `json:"outstanding"`
+}
+
+// RemittedCash is one order's cash recorded as handed in.
+type RemittedCash struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	AmountCents int       `json:"amount_cents"`
+	RemittedAt  time.Time `json:"remitted_at"`
+}
+
+// RecordRemittanceInput marks the cash for OrderIDs as handed in by the agent.
+type RecordRemittanceInput struct {
+	AgentUserID      uuid.UUID
+	OrderIDs         []uuid.UUID
+	RecordedByUserID uuid.UUID
+}
+
+// AgentCashReconciliation lists the agent's collected-but-unremitted cash per order.
+func (s *service) AgentCashReconciliation(ctx context.Context, agentUserID uuid.UUID) (*AgentCashReconciliation, error) {
+	if agentUserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "agent user id is required")
+	}
+
+	events, err := s.repo.ListUnremittedCash(ctx, agentUserID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list unremitted cash")
+	}
+	remitted, err := s.repo.SumRemittedCash(ctx, agentUserID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "sum remitted cash")
+	}
+
+	report := &AgentCashReconciliation{
+		AgentUserID:   agentUserID,
+		RemittedCents: remitted,
+		Outstanding:   make([]OutstandingCash, 0, len(events)),
+	}
+	for _, event := range events {
+		report.OutstandingCents += event.AmountCents
+		report.Outstanding = append(report.Outstanding, OutstandingCash{
+			OrderID:     event.OrderID,
+			AmountCents: event.AmountCents,
+			CollectedAt: event.CreatedAt,
+		})
+	}
+	return report, nil
+}
+
+// RecordRemittance marks the agent's outstanding cash for each order as handed in, for the
+// full collected amount. Orders the agent does not hold cash for are rejected as a whole.
+func (s *service) RecordRemittance(ctx context.Context, input RecordRemittanceInput) ([]RemittedCash, error) {
+	if input.AgentUserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "agent user id is required")
+	}
+	if input.RecordedByUserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "recorded by user id is required")
+	}
+	if len(input.OrderIDs) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "at least one order id is required")
+	}
+
+	events, err := s.repo.ListUnremittedCash(ctx, input.AgentUserID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list unremitted cash")
+	}
+	outstanding := make(map[uuid.UUID]int, len(events))
+	for _, event := range events {
+		outstanding[event.OrderID] = event.AmountCents
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[uuid.UUID]struct{}, len(input.OrderIDs))
+	remittances := make([]models.CashRemittance, 0, len(input.OrderIDs))
+	for _, orderID := range input.OrderIDs {
+		if _, dup := seen[orderID]; dup {
+			continue
+		}
+		seen[orderID] = struct{}{}
+		amount, ok := outstanding[orderID]
+		if !ok {
+			return nil, pkgerrors.New(pkgerrors.CodeStateConflict, "no outstanding cash for order").
+				WithDetails(map[string]any{"order_id": orderID})
+		}
+		remittances = append(remittances, models.CashRemittance{
+			ID:               uuid.New(),
+			OrderID:          orderID,
+			AgentUserID:      input.AgentUserID,
+			AmountCents:      amount,
+			RecordedByUserID: input.RecordedByUserID,
+			RemittedAt:       now,
+		})
+	}
+
+	if err := s.repo.CreateCashRemittances(ctx, remittances); err != nil {
+		if dbpkg.IsUniqueViolation(err, "ux_cash_remittances_order") {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeStateConflict, err, "cash already remitted")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record cash remittance")
+	}
+
+	remitted := make([]RemittedCash, 0, len(remittances))
+	for _, remittance := range remittances {
+		remitted = append(remitted, RemittedCash{
+			OrderID:     remittance.OrderID,
+			AmountCents: remittance.AmountCents,
+			RemittedAt:  remittance.RemittedAt,
+		})
+	}
+	return remitted, nil
+}
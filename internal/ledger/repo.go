@@ -2,8 +2,10 @@ package ledger
 
 import (
 	"context"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -13,6 +15,10 @@ type Repository interface {
 	WithTx(tx *gorm.DB) Repository
 	Create(ctx context.Context, event *models.LedgerEvent) error
 	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.LedgerEvent, error)
+	ListByActorAndType(ctx context.Context, actorUserID uuid.UUID, eventType enums.LedgerEventType, from, to time.Time) ([]models.LedgerEvent, error)
+	ListUnremittedCash(ctx context.Context, agentUserID uuid.UUID) ([]models.LedgerEvent, error)
+	SumRemittedCash(ctx context.Context, agentUserID uuid.UUID) (int, error)
+	CreateCashRemittances(ctx context.Context, remittances []models.CashRemittance) error
 }
 
 type repository struct {
@@ -45,3 +51,50 @@ func (r *repository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]mo
 	}
 	return events, nil
 }
+
+// ListByActorAndType returns events of eventType recorded by actorUserID with created_at in [from, to).
+func (r *repository) ListByActorAndType(ctx context.Context, actorUserID uuid.UUID, eventType enums.LedgerEventType, from, to time.Time) ([]models.LedgerEvent, error) {
+	var events []models.LedgerEvent
+	if err := r.db.WithContext(ctx).
+		Where("actor_user_id = ? AND type = ?", actorUserID, eventType).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListUnremittedCash returns the agent's cash_collected events whose order has no remittance yet.
+func (r *repository) ListUnremittedCash(ctx context.Context, agentUserID uuid.UUID) ([]models.LedgerEvent, error) {
+	var events []models.LedgerEvent
+	if err := r.db.WithContext(ctx).
+		Table("ledger_events AS le").
+		Select("le.*").
+		Where("le.actor_user_id = ? AND le.type = ?", agentUserID, enums.LedgerEventTypeCashCollected).
+		Where("NOT EXISTS (SELECT 1 FROM cash_remittances cr WHERE cr.order_id = le.order_id)").
+		Order("le.created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *repository) SumRemittedCash(ctx context.Context, agentUserID uuid.UUID) (int, error) {
+	var total int
+	if err := r.db.WithContext(ctx).
+		Model(&models.CashRemittance{}).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Where("agent_user_id = ?", agentUserID).
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *repository) CreateCashRemittances(ctx context.Context, remittances []models.CashRemittance) error {
+	if len(remittances) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&remittances).Error
+}
@@ -0,0 +1,138 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupLedgerTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, ddl := range []string{
+		`CREATE TABLE ledger_events (
+  id TEXT PRIMARY KEY,
+  order_id TEXT NOT NULL,
+  buyer_store_id TEXT NOT NULL,
+  vendor_store_id TEXT NOT NULL,
+  actor_user_id TEXT NOT NULL,
+  type TEXT NOT NULL,
+  amount_cents INTEGER NOT NULL,
+  metadata TEXT,
+  created_at DATETIME
+);`,
+		`CREATE TABLE cash_remittances (
+  id TEXT PRIMARY KEY,
+  order_id TEXT NOT NULL,
+  agent_user_id TEXT NOT NULL,
+  amount_cents INTEGER NOT NULL,
+  recorded_by_user_id TEXT NOT NULL,
+  remitted_at DATETIME NOT NULL
+);`,
+		`CREATE UNIQUE INDEX ux_cash_remittances_order ON cash_remittances (order_id);`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatalf("create schema: %v", err)
+		}
+	}
+	return db
+}
+
+func createLedgerEvent(t *testing.T, repo Repository, orderID, actorID uuid.UUID, eventType enums.LedgerEventType, amount int) {
+	t.Helper()
+	event := &models.LedgerEvent{
+		ID:            uuid.New(),
+		OrderID:       orderID,
+		BuyerStoreID:  uuid.New(),
+		VendorStoreID: uuid.New(),
+		ActorUserID:   actorID,
+		Type:          eventType,
+		AmountCents:   amount,
+	}
+	if err := repo.Create(context.Background(), event); err != nil {
+		t.Fatalf("create ledger event: %v", err)
+	}
+}
+
+func TestService_AgentCashReconciliation(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(setupLedgerTestDB(t))
+	svc, err := NewService(repo, DeliveryFeePolicy{})
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+
+	agentID := uuid.New()
+	adminID := uuid.New()
+	firstOrder := uuid.New()
+	secondOrder := uuid.New()
+	otherAgentOrder := uuid.New()
+
+	report, err := svc.AgentCashReconciliation(ctx, agentID)
+	if err != nil {
+		t.Fatalf("reconciliation error: %v", err)
+	}
+	if report.OutstandingCents != 0 || len(report.Outstanding) != 0 {
+		t.Fatalf("expected nothing outstanding got %+v", report)
+	}
+
+	createLedgerEvent(t, repo, firstOrder, agentID, enums.LedgerEventTypeCashCollected, 1500)
+	createLedgerEvent(t, repo, secondOrder, agentID, enums.LedgerEventTypeCashCollected, 2500)
+	createLedgerEvent(t, repo, secondOrder, agentID, enums.LedgerEventTypeAgentDeliveryFee, 500)
+	createLedgerEvent(t, repo, otherAgentOrder, uuid.New(), enums.LedgerEventTypeCashCollected, 9900)
+
+	report, err = svc.AgentCashReconciliation(ctx, agentID)
+	if err != nil {
+		t.Fatalf("reconciliation error: %v", err)
+	}
+	if report.OutstandingCents != 4000 || len(report.Outstanding) != 2 || report.RemittedCents != 0 {
+		t.Fatalf("expected collections to be outstanding got %+v", report)
+	}
+
+	remitted, err := svc.RecordRemittance(ctx, RecordRemittanceInput{
+		AgentUserID:      agentID,
+		OrderIDs:         []uuid.UUID{firstOrder},
+		RecordedByUserID: adminID,
+	})
+	if err != nil {
+		t.Fatalf("record remittance error: %v", err)
+	}
+	if len(remitted) != 1 || remitted[0].AmountCents != 1500 {
+		t.Fatalf("unexpected remittances %+v", remitted)
+	}
+
+	report, err = svc.AgentCashReconciliation(ctx, agentID)
+	if err != nil {
+		t.Fatalf("reconciliation error: %v", err)
+	}
+	if report.OutstandingCents != 2500 || report.RemittedCents != 1500 {
+		t.Fatalf("expected remittance to clear first order got %+v", report)
+	}
+	if len(report.Outstanding) != 1 || report.Outstanding[0].OrderID != secondOrder {
+		t.Fatalf("unexpected outstanding orders %+v", report.Outstanding)
+	}
+
+	for name, orderID := range map[string]uuid.UUID{
+		"already remitted":  firstOrder,
+		"other agent order": otherAgentOrder,
+	} {
+		_, err := svc.RecordRemittance(ctx, RecordRemittanceInput{
+			AgentUserID:      agentID,
+			OrderIDs:         []uuid.UUID{orderID},
+			RecordedByUserID: adminID,
+		})
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+			t.Fatalf("%s: expected state conflict got %v", name, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
@@ -14,10 +15,15 @@ import (
 type Service interface {
 	RecordEvent(ctx context.Context, input RecordLedgerEventInput) (*models.LedgerEvent, error)
 	HasEvent(ctx context.Context, orderID uuid.UUID, eventType enums.LedgerEventType) (bool, error)
+	RecordAgentEarning(ctx context.Context, input AgentEarningInput) (*models.LedgerEvent, error)
+	AgentEarnings(ctx context.Context, agentUserID uuid.UUID, from, to time.Time) (*AgentEarningsSummary, error)
+	AgentCashReconciliation(ctx context.Context, agentUserID uuid.UUID) (*AgentCashReconciliation, error)
+	RecordRemittance(ctx context.Context, input RecordRemittanceInput) ([]RemittedCash, error)
 }
 
 type service struct {
 	repo Repository
+	fees DeliveryFeePolicy
 }
 
 // RecordLedgerEventInput captures the immutable data a ledger event requires.
@@ -31,12 +37,12 @@ type RecordLedgerEventInput struct {
 	Metadata      json.RawMessage       `json:"metadata"`
 }
 
-// NewService wires a ledger service with the provided repository.
-func NewService(repo Repository) (Service, error) {
+// NewService wires a ledger service with the provided repository and agent delivery fee policy.
+func NewService(repo Repository, fees DeliveryFeePolicy) (Service, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("ledger repository required")
 	}
-	return &service{repo: repo}, nil
+	return &service{repo: repo, fees: fees}, nil
 }
 
 func (s *service) RecordEvent(ctx context.Context, input RecordLedgerEventInput) (*models.LedgerEvent, error) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
@@ -13,7 +14,8 @@ import (
 )
 
 type fakeRepository struct {
-	createFn func(ctx context.Context, event *models.LedgerEvent) error
+	createFn    func(ctx context.Context, event *models.LedgerEvent) error
+	listByActor func(ctx context.Context, actorUserID uuid.UUID, eventType enums.LedgerEventType, from, to time.Time) ([]models.LedgerEvent, error)
 }
 
 func (f *fakeRepository) WithTx(tx *gorm.DB) Repository {
@@ -31,9 +33,28 @@ func (f *fakeRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) (
 	return nil, nil
 }
 
+func (f *fakeRepository) ListUnremittedCash(ctx context.Context, agentUserID uuid.UUID) ([]models.LedgerEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeRepository) SumRemittedCash(ctx context.Context, agentUserID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeRepository) CreateCashRemittances(ctx context.Context, remittances []models.CashRemittance) error {
+	return nil
+}
+
+func (f *fakeRepository) ListByActorAndType(ctx context.Context, actorUserID uuid.UUID, eventType enums.LedgerEventType, from, to time.Time) ([]models.LedgerEvent, error) {
+	if f.listByActor != nil {
+		return f.listByActor(ctx, actorUserID, eventType, from, to)
+	}
+	return nil, nil
+}
+
 func TestService_RecordEvent(t *testing.T) {
 	repo := &fakeRepository{}
-	svc, err := NewService(repo)
+	svc, err := NewService(repo, DeliveryFeePolicy{})
 	if err != nil {
 		t.Fatalf("unexpected service error: %v", err)
 	}
@@ -78,7 +99,7 @@ func TestService_RecordEvent(t *testing.T) {
 
 func TestService_RecordEventValidation(t *testing.T) {
 	repo := &fakeRepository{}
-	svc, err := NewService(repo)
+	svc, err := NewService(repo, DeliveryFeePolicy{})
 	if err != nil {
 		t.Fatalf("unexpected service error: %v", err)
 	}
@@ -147,7 +168,7 @@ func TestService_RecordEventValidation(t *testing.T) {
 
 func TestService_RecordEventRepoError(t *testing.T) {
 	repo := &fakeRepository{}
-	svc, err := NewService(repo)
+	svc, err := NewService(repo, DeliveryFeePolicy{})
 	if err != nil {
 		t.Fatalf("unexpected service error: %v", err)
 	}
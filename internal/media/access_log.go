@@ -0,0 +1,118 @@
+package media
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+)
+
+const defaultReadPurpose = "download"
+
+// protectedReadKinds are served through short-lived signed URLs, and every URL handed out is
+// recorded in media_access_logs.
+var protectedReadKinds = map[enums.MediaKind]struct{}{
+	enums.MediaKindLicenseDoc: {},
+	enums.MediaKindManifest:   {},
+}
+
+func isProtectedReadKind(kind enums.MediaKind) bool {
+	_, ok := protectedReadKinds[kind]
+	return ok
+}
+
+type accessLogRepository interface {
+	Create(ctx context.Context, entry *models.MediaAccessLog) error
+	List(ctx context.Context, query AccessLogQuery) (*AccessLogList, error)
+}
+
+// ServiceOption customizes the media service.
+type ServiceOption func(*service)
+
+// WithAccessLog records protected media downloads in the provided repository.
+func WithAccessLog(repo accessLogRepository) ServiceOption {
+	return func(s *service) {
+		s.accessLogs = repo
+	}
+}
+
+// AccessLogQuery filters the admin view of protected media downloads.
+type AccessLogQuery struct {
+	MediaID *uuid.UUID
+	StoreID *uuid.UUID
+	pagination.Params
+}
+
+// AccessLogList is a page of access log entries, newest first.
+type AccessLogList struct {
+	Entries    []models.MediaAccessLog `json:"entries"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+// AccessLogRepository persists media access log rows.
+type AccessLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessLogRepository constructs the access log repository bound to the provided GORM DB.
+func NewAccessLogRepository(db *gorm.DB) *AccessLogRepository {
+	return &AccessLogRepository{db: db}
+}
+
+// Create appends an access log row.
+func (r *AccessLogRepository) Create(ctx context.Context, entry *models.MediaAccessLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// List returns access log rows newest first, optionally scoped to a media row or owning store.
+func (r *AccessLogRepository) List(ctx context.Context, query AccessLogQuery) (*AccessLogList, error) {
+	limit := pagination.NormalizeLimit(query.Limit)
+	cursor, err := pagination.ParseCursor(strings.TrimSpace(query.Cursor))
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid cursor")
+	}
+
+	qb := r.db.WithContext(ctx).Model(&models.MediaAccessLog{})
+	if query.MediaID != nil {
+		qb = qb.Where("media_id = ?", *query.MediaID)
+	}
+	if query.StoreID != nil {
+		qb = qb.Where("store_id = ?", *query.StoreID)
+	}
+	if cursor != nil {
+		qb = qb.Where("(created_at < ?) OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	var rows []models.MediaAccessLog
+	if err := qb.Order("created_at DESC").Order("id DESC").Limit(pagination.LimitWithBuffer(query.Limit)).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	list := &AccessLogList{Entries: rows}
+	if len(rows) > limit {
+		list.Entries = rows[:limit]
+		last := list.Entries[limit-1]
+		list.NextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return list, nil
+}
+
+func (s *service) ListAccessLogs(ctx context.Context, query AccessLogQuery) (*AccessLogList, error) {
+	if s.accessLogs == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeInternal, "media access log unavailable")
+	}
+	list, err := s.accessLogs.List(ctx, query)
+	if err != nil {
+		if typed := pkgerrors.As(err); typed != nil {
+			return nil, err
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list media access logs")
+	}
+	return list, nil
+}
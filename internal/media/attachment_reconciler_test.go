@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -66,6 +67,18 @@ func (s *stubAttachmentMediaRepo) MarkDeleted(ctx context.Context, id uuid.UUID,
 	return nil
 }
 
+func (s *stubAttachmentMediaRepo) MarkReady(ctx context.Context, id uuid.UUID, readyAt time.Time, publicURL string, sizeBytes int64, mimeType string) (bool, error) {
+	return false, nil
+}
+
+func (s *stubAttachmentMediaRepo) MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error) {
+	return false, nil
+}
+
+func (s *stubAttachmentMediaRepo) Undelete(ctx context.Context, id uuid.UUID, status enums.MediaStatus, now time.Time) (bool, error) {
+	return false, nil
+}
+
 func TestAttachmentReconcilerCreatesAndDeletesAttachments(t *testing.T) {
 	t.Parallel()
 
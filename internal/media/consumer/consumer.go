@@ -13,6 +13,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	gcsclient "github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -32,12 +33,14 @@ type repository interface {
 type Consumer struct {
 	repo         repository
 	subscription *pubsub.Subscriber
+	guard        *redelivery.Guard
 	logg         *logger.Logger
 	now          func() time.Time
 }
 
-// NewConsumer constructs a consumer that watches the provided subscription.
-func NewConsumer(repo repository, subscription *pubsub.Subscriber, logg *logger.Logger) (*Consumer, error) {
+// NewConsumer constructs a consumer that watches the provided subscription. A nil guard retries
+// failing messages without a cap.
+func NewConsumer(repo repository, subscription *pubsub.Subscriber, logg *logger.Logger, guard *redelivery.Guard) (*Consumer, error) {
 	if repo == nil {
 		return nil, errors.New("media repository is required")
 	}
@@ -50,6 +53,7 @@ func NewConsumer(repo repository, subscription *pubsub.Subscriber, logg *logger.
 	return &Consumer{
 		repo:         repo,
 		subscription: subscription,
+		guard:        guard,
 		logg:         logg,
 		now:          time.Now,
 	}, nil
@@ -57,14 +61,22 @@ func NewConsumer(repo repository, subscription *pubsub.Subscriber, logg *logger.
 
 // Run processes messages until the context is canceled or the subscription errors.
 func (c *Consumer) Run(ctx context.Context) error {
-	return c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-		result := c.process(ctx, msg)
-		if result.nack {
+	err := c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		delivery := redelivery.Message{ID: msg.ID, Data: msg.Data, Attributes: msg.Attributes, DeliveryAttempt: msg.DeliveryAttempt}
+		decision := c.guard.Handle(ctx, delivery, func(ctx context.Context) error {
+			if c.process(ctx, msg).nack {
+				return redelivery.ErrRetry
+			}
+			return nil
+		})
+		if decision == redelivery.Nack {
 			msg.Nack()
 			return
 		}
 		msg.Ack()
 	})
+	c.guard.Drain()
+	return err
 }
 
 type processResult struct {
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
+	"time"
 
 	pubsub "cloud.google.com/go/pubsub/v2"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -20,37 +22,28 @@ const (
 
 type deletionRepository interface {
 	FindByGCSKey(ctx context.Context, gcsKey string) (*models.Media, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error)
 }
 
-type attachmentRepository interface {
-	Delete(ctx context.Context, tx *gorm.DB, entityType string, entityID, mediaID uuid.UUID) error
-	ListByMediaID(ctx context.Context, mediaID uuid.UUID) ([]models.MediaAttachment, error)
-}
-
-type detachmentHandler interface {
-	Detach(ctx context.Context, attachment models.MediaAttachment) error
-}
-
-// DeletionConsumer watches Pub/Sub for GCS OBJECT_DELETE notifications and removes attachments.
+// DeletionConsumer watches Pub/Sub for GCS OBJECT_DELETE notifications and schedules the matching
+// media for purging. Attachments and the row are only removed by the media purge cron job once
+// the grace period ends, so a mistaken delete can still be undone.
 type DeletionConsumer struct {
 	repo         deletionRepository
-	attachments  attachmentRepository
-	detacher     detachmentHandler
 	subscription *pubsub.Subscriber
+	guard        *redelivery.Guard
 	logg         *logger.Logger
+	grace        time.Duration
+	now          func() time.Time
 }
 
-// NewDeletionConsumer wires the dependencies required for recursive media cleanup.
-func NewDeletionConsumer(repo deletionRepository, attachments attachmentRepository, detacher detachmentHandler, subscription *pubsub.Subscriber, logg *logger.Logger) (*DeletionConsumer, error) {
+// NewDeletionConsumer wires the dependencies required to schedule deleted media for purging.
+func NewDeletionConsumer(repo deletionRepository, subscription *pubsub.Subscriber, logg *logger.Logger, guard *redelivery.Guard, grace time.Duration) (*DeletionConsumer, error) {
 	if repo == nil {
 		return nil, errors.New("media repository is required")
 	}
-	if attachments == nil {
-		return nil, errors.New("attachment repository is required")
-	}
-	if detacher == nil {
-		return nil, errors.New("detacher is required")
+	if grace <= 0 {
+		return nil, errors.New("deletion grace period must be positive")
 	}
 	if subscription == nil {
 		return nil, errors.New("media deletion subscription is required")
@@ -60,23 +53,32 @@ func NewDeletionConsumer(repo deletionRepository, attachments attachmentReposito
 	}
 	return &DeletionConsumer{
 		repo:         repo,
-		attachments:  attachments,
-		detacher:     detacher,
 		subscription: subscription,
+		guard:        guard,
 		logg:         logg,
+		grace:        grace,
+		now:          time.Now,
 	}, nil
 }
 
 // Run processes deletion notifications until the context is canceled.
 func (c *DeletionConsumer) Run(ctx context.Context) error {
-	return c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-		result := c.process(ctx, msg)
-		if result.nack {
+	err := c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		delivery := redelivery.Message{ID: msg.ID, Data: msg.Data, Attributes: msg.Attributes, DeliveryAttempt: msg.DeliveryAttempt}
+		decision := c.guard.Handle(ctx, delivery, func(ctx context.Context) error {
+			if c.process(ctx, msg).nack {
+				return redelivery.ErrRetry
+			}
+			return nil
+		})
+		if decision == redelivery.Nack {
 			msg.Nack()
 			return
 		}
 		msg.Ack()
 	})
+	c.guard.Drain()
+	return err
 }
 
 func (c *DeletionConsumer) process(ctx context.Context, msg *pubsub.Message) processResult {
@@ -128,43 +130,22 @@ func (c *DeletionConsumer) process(ctx context.Context, msg *pubsub.Message) pro
 		return c.handleDBError(logCtx, err)
 	}
 
-	attachments, err := c.attachments.ListByMediaID(logCtx, mediaRow.ID)
-	if err != nil {
-		return c.handleDBError(logCtx, err)
+	if mediaRow.Status == enums.MediaStatusPendingDeletion {
+		c.logg.Info(logCtx, "media already pending deletion")
+		return processResult{ack: true}
 	}
 
-	sort.Slice(attachments, func(i, j int) bool {
-		if attachments[i].EntityType != attachments[j].EntityType {
-			return attachments[i].EntityType < attachments[j].EntityType
-		}
-		if attachments[i].EntityID != attachments[j].EntityID {
-			return attachments[i].EntityID.String() < attachments[j].EntityID.String()
-		}
-		return attachments[i].ID.String() < attachments[j].ID.String()
-	})
-
-	for _, attachment := range attachments {
-		if err := c.detacher.Detach(ctx, attachment); err != nil {
-			c.logg.Error(logCtx, "domain detachment failed", err)
-			return processResult{nack: true}
-		}
-
-		if err := c.attachments.Delete(ctx, nil, attachment.EntityType, attachment.EntityID, attachment.MediaID); err != nil {
-			return c.handleDBError(logCtx, err)
-		}
+	now := c.now().UTC()
+	purgeAfter := now.Add(c.grace)
+	if _, err := c.repo.MarkPendingDeletion(logCtx, mediaRow.ID, now, purgeAfter); err != nil {
+		return c.handleDBError(logCtx, err)
 	}
 
 	logCtx = c.logg.WithFields(logCtx, map[string]any{
-		"attachments_processed": len(attachments),
+		"media_id":    mediaRow.ID,
+		"purge_after": purgeAfter,
 	})
-	c.logg.Info(logCtx, "attachments removed, deleting media record")
-
-	if err := c.repo.Delete(ctx, mediaRow.ID); err != nil {
-		return c.handleDBError(logCtx, err)
-	}
-
-	logCtx = c.logg.WithField(logCtx, "media_deleted", mediaRow.ID)
-	c.logg.Info(logCtx, "media deletion complete")
+	c.logg.Info(logCtx, "media scheduled for purge")
 	return processResult{ack: true}
 }
 
@@ -187,16 +168,3 @@ func (c *DeletionConsumer) buildLogFields(messageID string, attrs gcsAttributes,
 	}
 	return fields
 }
-
-type nopDetacher struct {
-	logg *logger.Logger
-}
-
-func (n nopDetacher) Detach(ctx context.Context, attachment models.MediaAttachment) error {
-	return nil
-}
-
-// NewNoopDetacher returns a detacher that only logs the attachment being removed.
-func NewNoopDetacher(logg *logger.Logger) detachmentHandler {
-	return nopDetacher{logg: logg}
-}
@@ -4,66 +4,34 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"testing"
+	"time"
 
 	pubsub "cloud.google.com/go/pubsub/v2"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 type stubDeletionRepo struct {
-	media        *models.Media
-	attachments  []models.MediaAttachment
-	findErr      error
-	deleteErr    error
-	deletedID    uuid.UUID
-	deleteCalled bool
+	media      *models.Media
+	findErr    error
+	markErr    error
+	markedID   uuid.UUID
+	purgeAfter time.Time
+	markCalled bool
 }
 
 func (s *stubDeletionRepo) FindByGCSKey(ctx context.Context, gcsKey string) (*models.Media, error) {
 	return s.media, s.findErr
 }
 
-func (s *stubDeletionRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	s.deleteCalled = true
-	s.deletedID = id
-	return s.deleteErr
-}
-
-func (s *stubDeletionRepo) ListByMediaID(ctx context.Context, mediaID uuid.UUID) ([]models.MediaAttachment, error) {
-	return s.attachments, nil
-}
-
-type stubAttachmentRepo struct {
-	attachments []models.MediaAttachment
-	deleted     []models.MediaAttachment
-	err         error
-}
-
-func (s *stubAttachmentRepo) Delete(ctx context.Context, tx *gorm.DB, entityType string, entityID, mediaID uuid.UUID) error {
-	s.deleted = append(s.deleted, models.MediaAttachment{
-		EntityType: entityType,
-		EntityID:   entityID,
-		MediaID:    mediaID,
-	})
-	return s.err
-}
-
-func (s *stubAttachmentRepo) ListByMediaID(ctx context.Context, mediaID uuid.UUID) ([]models.MediaAttachment, error) {
-	return s.attachments, nil
-}
-
-type recordingDetacher struct {
-	calls []models.MediaAttachment
-	err   error
-}
-
-func (r *recordingDetacher) Detach(ctx context.Context, attachment models.MediaAttachment) error {
-	r.calls = append(r.calls, attachment)
-	return r.err
+func (s *stubDeletionRepo) MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error) {
+	s.markCalled = true
+	s.markedID = id
+	s.purgeAfter = purgeAfter
+	return s.markErr == nil, s.markErr
 }
 
 func encodePayload(payload gcsPayload) []byte {
@@ -81,81 +49,59 @@ func buildMessage(name string) *pubsub.Message {
 	}
 }
 
-func TestDeletionConsumerProcessesAttachments(t *testing.T) {
-	t.Parallel()
-
-	storeID := uuid.New()
-	mediaID := uuid.New()
-	attachments := []models.MediaAttachment{
-		{ID: uuid.New(), EntityType: "store", EntityID: uuid.New(), MediaID: mediaID},
-		{ID: uuid.New(), EntityType: "product", EntityID: uuid.New(), MediaID: mediaID},
+func newTestDeletionConsumer(t *testing.T, repo *stubDeletionRepo, grace time.Duration) *DeletionConsumer {
+	t.Helper()
+	consumer, err := NewDeletionConsumer(repo, &pubsub.Subscriber{}, logger.New(logger.Options{ServiceName: "test"}), nil, grace)
+	if err != nil {
+		t.Fatalf("NewDeletionConsumer: %v", err)
 	}
+	return consumer
+}
+
+func TestDeletionConsumerSchedulesPurge(t *testing.T) {
+	t.Parallel()
 
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
 	repo := &stubDeletionRepo{
 		media: &models.Media{
-			ID:      mediaID,
-			StoreID: storeID,
+			ID:      uuid.New(),
+			StoreID: uuid.New(),
+			Status:  enums.MediaStatusReady,
 			GCSKey:  "packfinderz-media/path",
 		},
-		attachments: attachments,
-	}
-	attachmentRepo := &stubAttachmentRepo{attachments: attachments}
-	detacher := &recordingDetacher{}
-	sub := &pubsub.Subscriber{}
-	logg := logger.New(logger.Options{ServiceName: "test"})
-	consumer, err := NewDeletionConsumer(repo, attachmentRepo, detacher, sub, logg)
-	if err != nil {
-		t.Fatalf("NewDeletionConsumer: %v", err)
 	}
+	consumer := newTestDeletionConsumer(t, repo, 72*time.Hour)
+	consumer.now = func() time.Time { return now }
 
 	result := consumer.process(context.Background(), buildMessage(repo.media.GCSKey))
 	if !result.ack || result.nack {
 		t.Fatalf("expected ack result")
 	}
-	if len(detacher.calls) != len(attachments) {
-		t.Fatalf("expected detacher called, got %d", len(detacher.calls))
+	if !repo.markCalled || repo.markedID != repo.media.ID {
+		t.Fatalf("expected media %s marked pending deletion", repo.media.ID)
 	}
-	if len(attachmentRepo.deleted) != len(attachments) {
-		t.Fatalf("expected attachments deleted, got %d", len(attachmentRepo.deleted))
-	}
-	if !repo.deleteCalled {
-		t.Fatal("expected media delete called")
-	}
-	if repo.deletedID != repo.media.ID {
-		t.Fatalf("expected media %s deleted, got %s", repo.media.ID, repo.deletedID)
+	if want := now.Add(72 * time.Hour); !repo.purgeAfter.Equal(want) {
+		t.Fatalf("expected purge_after %s, got %s", want, repo.purgeAfter)
 	}
 }
 
-func TestDeletionConsumerNacksOnDetacherError(t *testing.T) {
+func TestDeletionConsumerKeepsExistingPurgeSchedule(t *testing.T) {
 	t.Parallel()
 
-	storeID := uuid.New()
-	mediaID := uuid.New()
-
 	repo := &stubDeletionRepo{
 		media: &models.Media{
-			ID:      mediaID,
-			StoreID: storeID,
-			GCSKey:  "media/object",
-		},
-		attachments: []models.MediaAttachment{
-			{ID: uuid.New(), EntityType: "store", EntityID: uuid.New(), MediaID: mediaID},
+			ID:     uuid.New(),
+			Status: enums.MediaStatusPendingDeletion,
+			GCSKey: "media/object",
 		},
 	}
-	attachmentRepo := &stubAttachmentRepo{attachments: repo.attachments}
-	detacher := &recordingDetacher{err: errors.New("boom")}
-	sub := &pubsub.Subscriber{}
-	logg := logger.New(logger.Options{ServiceName: "test"})
-	consumer, _ := NewDeletionConsumer(repo, attachmentRepo, detacher, sub, logg)
+	consumer := newTestDeletionConsumer(t, repo, time.Hour)
 
 	result := consumer.process(context.Background(), buildMessage(repo.media.GCSKey))
-	if !result.nack {
-		t.Fatalf("expected nack on detacher failure")
-	}
-	if len(attachmentRepo.deleted) != 0 {
-		t.Fatalf("expected no deletes, got %d", len(attachmentRepo.deleted))
+	if !result.ack || result.nack {
+		t.Fatalf("expected ack result")
 	}
-	if repo.deleteCalled {
-		t.Fatal("expected delete not called on detacher failure")
+	if repo.markCalled {
+		t.Fatal("expected media already pending deletion to be left alone")
 	}
 }
@@ -0,0 +1,87 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
+)
+
+const defaultDeletionGracePeriod = 7 * 24 * time.Hour
+
+// WithDeletionGracePeriod sets how long deleted media stays recoverable. Non-positive values keep
+// the default.
+func WithDeletionGracePeriod(grace time.Duration) ServiceOption {
+	return func(s *service) {
+		if grace > 0 {
+			s.deletionGrace = grace
+		}
+	}
+}
+
+// UndeleteMedia restores media that is pending deletion while its grace period is still running.
+// Media whose GCS object is already gone (e.g. removed outside the API) cannot be restored.
+func (s *service) UndeleteMedia(ctx context.Context, params DeleteMediaParams) error {
+	if params.StoreID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	if params.MediaID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "media id required")
+	}
+
+	mediaRow, err := s.repo.FindByID(ctx, params.MediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgerrors.New(pkgerrors.CodeNotFound, "media not found")
+		}
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "lookup media")
+	}
+	if mediaRow.StoreID != params.StoreID {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "media does not belong to active store")
+	}
+	if mediaRow.Status != enums.MediaStatusPendingDeletion {
+		return pkgerrors.New(pkgerrors.CodeConflict, "media is not pending deletion")
+	}
+
+	now := s.now().UTC()
+	if mediaRow.PurgeAfter == nil || !now.Before(*mediaRow.PurgeAfter) {
+		return pkgerrors.New(pkgerrors.CodeConflict, "media deletion grace period has ended")
+	}
+
+	if _, err := s.gcs.ObjectAttrs(ctx, s.bucket, mediaRow.GCSKey); err != nil {
+		if errors.Is(err, gcs.ErrObjectNotFound) {
+			return pkgerrors.New(pkgerrors.CodeConflict, "media object no longer exists in storage")
+		}
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "lookup media object")
+	}
+
+	restored, err := s.repo.Undelete(ctx, mediaRow.ID, restoredStatus(*mediaRow), now)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "restore media")
+	}
+	if !restored {
+		return pkgerrors.New(pkgerrors.CodeConflict, "media deletion grace period has ended")
+	}
+	return nil
+}
+
+// restoredStatus infers the status media had before deletion from its lifecycle timestamps.
+func restoredStatus(m models.Media) enums.MediaStatus {
+	switch {
+	case m.ReadyAt != nil:
+		return enums.MediaStatusReady
+	case m.FailedAt != nil:
+		return enums.MediaStatusFailed
+	case m.UploadedAt != nil:
+		return enums.MediaStatusUploaded
+	default:
+		return enums.MediaStatusPending
+	}
+}
@@ -52,6 +52,19 @@ func (r *Repository) DeleteWithTx(tx *gorm.DB, id uuid.UUID) error {
 	return tx.Where("id = ?", id).Delete(&models.Media{}).Error
 }
 
+// DeletePendingWithTx deletes the media row only while it is still pending and reports whether a
+// row was removed, so a cleanup racing a finished upload leaves the upload alone.
+func (r *Repository) DeletePendingWithTx(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		return false, gorm.ErrInvalidTransaction
+	}
+	result := tx.Where("id = ? AND status = ?", id, enums.MediaStatusPending).Delete(&models.Media{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func escapeLike(value string) string {
 	value = strings.ReplaceAll(value, `\`, `\\`)
 	value = strings.ReplaceAll(value, `%`, `\%`)
@@ -161,6 +174,85 @@ func (r *Repository) MarkUploaded(ctx context.Context, id uuid.UUID, uploadedAt
 		Updates(updates).Error
 }
 
+// MarkReady records the stored object's size and content type and moves a pending or uploaded row
+// to ready. It reports false when the row had already left those states, e.g. it was deleted.
+func (r *Repository) MarkReady(ctx context.Context, id uuid.UUID, readyAt time.Time, publicURL string, sizeBytes int64, mimeType string) (bool, error) {
+	updates := map[string]any{
+		"status":      enums.MediaStatusReady,
+		"uploaded_at": gorm.Expr("COALESCE(uploaded_at, ?)", readyAt),
+		"ready_at":    readyAt,
+		"size_bytes":  sizeBytes,
+		"public_url":  publicURL,
+	}
+	if strings.TrimSpace(mimeType) != "" {
+		updates["mime_type"] = mimeType
+	}
+	res := r.db.WithContext(ctx).Model(&models.Media{}).
+		Where("id = ? AND status IN ?", id, []enums.MediaStatus{enums.MediaStatusPending, enums.MediaStatusUploaded}).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// MarkPendingDeletion schedules the media for purging at purgeAfter. It reports false when the row
+// is already pending deletion, leaving the original purge time in place.
+func (r *Repository) MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.Media{}).
+		Where("id = ? AND status <> ?", id, enums.MediaStatusPendingDeletion).
+		Updates(map[string]any{
+			"status":      enums.MediaStatusPendingDeletion,
+			"deleted_at":  deletedAt,
+			"purge_after": purgeAfter,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// Undelete restores pending-deletion media to status while its purge_after is still ahead of now,
+// reporting false once the grace period has ended or the row was purged.
+func (r *Repository) Undelete(ctx context.Context, id uuid.UUID, status enums.MediaStatus, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.Media{}).
+		Where("id = ? AND status = ? AND purge_after > ?", id, enums.MediaStatusPendingDeletion, now).
+		Updates(map[string]any{
+			"status":      status,
+			"deleted_at":  nil,
+			"purge_after": nil,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ListPurgeable returns pending-deletion media whose grace period ended at or before cutoff.
+func (r *Repository) ListPurgeable(ctx context.Context, cutoff time.Time) ([]models.Media, error) {
+	var results []models.Media
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND purge_after <= ?", enums.MediaStatusPendingDeletion, cutoff).
+		Order("purge_after ASC").
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeletePendingDeletionWithTx deletes the media row only while it is still pending deletion and
+// reports whether a row was removed, so a purge racing an undelete leaves the media alone.
+func (r *Repository) DeletePendingDeletionWithTx(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		return false, gorm.ErrInvalidTransaction
+	}
+	result := tx.Where("id = ? AND status = ?", id, enums.MediaStatusPendingDeletion).Delete(&models.Media{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // MarkDeleted marks the media as deleted with a timestamp.
 func (r *Repository) MarkDeleted(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
 	return r.db.WithContext(ctx).Model(&models.Media{}).
@@ -13,12 +13,14 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 const (
 	maxUploadBytes       = 20 * 1024 * 1024
+	maxPresignBatchSize  = 20
 	readURLPendingErrMsg = "media upload pending"
 )
 
@@ -32,13 +34,17 @@ type mediaRepository interface {
 	List(ctx context.Context, opts listQuery) ([]models.Media, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Media, error)
 	MarkDeleted(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	MarkReady(ctx context.Context, id uuid.UUID, readyAt time.Time, publicURL string, sizeBytes int64, mimeType string) (bool, error)
+	MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error)
+	Undelete(ctx context.Context, id uuid.UUID, status enums.MediaStatus, now time.Time) (bool, error)
 	Count(ctx context.Context, opts listQuery) (int64, error)
 	FetchBoundaryCursor(ctx context.Context, opts listQuery, ascending bool) (string, error)
 }
 
 type gcsClient interface {
 	SignedURL(bucket, object, contentType string, expires time.Duration) (string, error)
-	DeleteObject(ctx context.Context, bucket, object string) error
+	SignedReadURL(bucket, object string, expires time.Duration) (string, error)
+	ObjectAttrs(ctx context.Context, bucket, object string) (*gcs.ObjectAttrs, error)
 }
 
 type mediaAttachmentLookup interface {
@@ -48,24 +54,35 @@ type mediaAttachmentLookup interface {
 // Service exposes media-presign semantics.
 type Service interface {
 	PresignUpload(ctx context.Context, userID, storeID uuid.UUID, input PresignInput) (*PresignOutput, error)
+	PresignUploadBatch(ctx context.Context, userID, storeID uuid.UUID, inputs []PresignInput) ([]PresignOutput, error)
+	ConfirmUpload(ctx context.Context, userID, storeID, mediaID uuid.UUID) (*ListItem, error)
 	ListMedia(ctx context.Context, params ListParams) (*MediaListResult, error)
 	DeleteMedia(ctx context.Context, params DeleteMediaParams) error
+	UndeleteMedia(ctx context.Context, params DeleteMediaParams) error
 	GenerateReadURL(ctx context.Context, params ReadURLParams) (*ReadURLOutput, error)
+	ListAccessLogs(ctx context.Context, query AccessLogQuery) (*AccessLogList, error)
 }
 
 type service struct {
-	repo         mediaRepository
-	memberships  membershipsRepository
-	gcs          gcsClient
-	attachments  mediaAttachmentLookup
-	bucket       string
-	uploadTTL    time.Duration
-	downloadTTL  time.Duration
-	allowedRoles []enums.MemberRole
+	repo        mediaRepository
+	memberships membershipsRepository
+	gcs         gcsClient
+	attachments mediaAttachmentLookup
+	bucket      string
+	uploadTTL   time.Duration
+	downloadTTL time.Duration
+	// uploadTTLByKind and downloadTTLByKind override the TTLs above for individual media kinds.
+	uploadTTLByKind   map[enums.MediaKind]time.Duration
+	downloadTTLByKind map[enums.MediaKind]time.Duration
+	allowedRoles      []enums.MemberRole
+	accessLogs        accessLogRepository
+	// deletionGrace is how long deleted media can be restored before the purge job removes it.
+	deletionGrace time.Duration
+	now           func() time.Time
 }
 
 // NewService constructs a media service backed by the provided repositories and GCS signer.
-func NewService(repo mediaRepository, memberships membershipsRepository, attachments mediaAttachmentLookup, gcsClient gcsClient, bucket string, uploadTTL, downloadTTL time.Duration) (Service, error) {
+func NewService(repo mediaRepository, memberships membershipsRepository, attachments mediaAttachmentLookup, gcsClient gcsClient, bucket string, uploadTTL, downloadTTL time.Duration, opts ...ServiceOption) (Service, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("media repository required")
 	}
@@ -87,7 +104,7 @@ func NewService(repo mediaRepository, memberships membershipsRepository, attachm
 	if downloadTTL <= 0 {
 		return nil, fmt.Errorf("download ttl must be positive")
 	}
-	return &service{
+	svc := &service{
 		repo:        repo,
 		memberships: memberships,
 		gcs:         gcsClient,
@@ -102,7 +119,13 @@ func NewService(repo mediaRepository, memberships membershipsRepository, attachm
 			enums.MemberRoleStaff,
 			enums.MemberRoleOps,
 		},
-	}, nil
+		deletionGrace: defaultDeletionGracePeriod,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc, nil
 }
 
 // PresignInput models the payload required to request an upload URL.
@@ -123,72 +146,154 @@ type PresignOutput struct {
 }
 
 func (s *service) PresignUpload(ctx context.Context, userID, storeID uuid.UUID, input PresignInput) (*PresignOutput, error) {
+	if err := requireUploader(userID, storeID); err != nil {
+		return nil, err
+	}
+	upload, err := validatePresignInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireUploadRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+	return s.presign(ctx, userID, storeID, upload)
+}
+
+// PresignUploadBatch presigns up to maxPresignBatchSize uploads in one call. Every input is
+// validated before any media row is created, and a failure part-way through removes the rows
+// already created, so the batch succeeds or fails as a whole.
+func (s *service) PresignUploadBatch(ctx context.Context, userID, storeID uuid.UUID, inputs []PresignInput) ([]PresignOutput, error) {
+	if err := requireUploader(userID, storeID); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "at least one upload is required")
+	}
+	if len(inputs) > maxPresignBatchSize {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("at most %d uploads may be presigned at once", maxPresignBatchSize))
+	}
+
+	uploads := make([]PresignInput, len(inputs))
+	fields := map[string]string{}
+	for i, input := range inputs {
+		upload, err := validatePresignInput(input)
+		if err != nil {
+			msg := err.Error()
+			if typed := pkgerrors.As(err); typed != nil {
+				msg = typed.Message()
+			}
+			fields[fmt.Sprintf("uploads[%d]", i)] = msg
+			continue
+		}
+		uploads[i] = upload
+	}
+	if len(fields) > 0 {
+		return nil, pkgerrors.NewValidationError("invalid uploads", fields)
+	}
+
+	if err := s.requireUploadRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]PresignOutput, 0, len(uploads))
+	for _, upload := range uploads {
+		out, err := s.presign(ctx, userID, storeID, upload)
+		if err != nil {
+			for _, created := range outputs {
+				_ = s.repo.Delete(ctx, created.MediaID)
+			}
+			return nil, err
+		}
+		outputs = append(outputs, *out)
+	}
+	return outputs, nil
+}
+
+func requireUploader(userID, storeID uuid.UUID) error {
 	if userID == uuid.Nil {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "user identity missing")
+		return pkgerrors.New(pkgerrors.CodeValidation, "user identity missing")
 	}
 	if storeID == uuid.Nil {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store identity missing")
+		return pkgerrors.New(pkgerrors.CodeValidation, "store identity missing")
 	}
+	return nil
+}
 
+// validatePresignInput checks an upload request and returns it with the file name trimmed and
+// the MIME type normalized.
+func validatePresignInput(input PresignInput) (PresignInput, error) {
 	if input.Kind == "" || !input.Kind.IsValid() {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid media kind")
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, "invalid media kind")
 	}
 
 	fileName := strings.TrimSpace(input.FileName)
 	if fileName == "" {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "file_name is required")
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, "file_name is required")
 	}
 
 	if input.SizeBytes <= 0 {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "size_bytes must be positive")
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, "size_bytes must be positive")
 	}
 	if input.SizeBytes > maxUploadBytes {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("size_bytes must be ≤ %d bytes", maxUploadBytes))
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("size_bytes must be ≤ %d bytes", maxUploadBytes))
 	}
 
 	mimeType := strings.TrimSpace(input.MimeType)
 	if mimeType == "" {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "mime_type is required")
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, "mime_type is required")
 	}
-	var err error
-	mimeType, err = sniffMimeType(mimeType)
+	mimeType, err := sniffMimeType(mimeType)
 	if err != nil {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, err.Error())
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation, err.Error())
 	}
 	if !isAllowedMime(input.Kind, mimeType) {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation,
+		return PresignInput{}, pkgerrors.New(pkgerrors.CodeValidation,
 			fmt.Sprintf("%s uploads only accept %s", input.Kind, allowedMimeDescription(input.Kind)))
 	}
 
+	return PresignInput{
+		Kind:      input.Kind,
+		MimeType:  mimeType,
+		FileName:  fileName,
+		SizeBytes: input.SizeBytes,
+	}, nil
+}
+
+func (s *service) requireUploadRole(ctx context.Context, userID, storeID uuid.UUID) error {
 	ok, err := s.memberships.UserHasRole(ctx, userID, storeID, s.allowedRoles...)
 	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check membership role")
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check membership role")
 	}
 	if !ok {
-		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "insufficient store role")
+		return pkgerrors.New(pkgerrors.CodeForbidden, "insufficient store role")
 	}
+	return nil
+}
 
+// presign creates the pending media row for a validated upload and signs its PUT URL.
+func (s *service) presign(ctx context.Context, userID, storeID uuid.UUID, upload PresignInput) (*PresignOutput, error) {
 	mediaID := uuid.New()
-	gcsKey := buildGCSKey(storeID, input.Kind, mediaID, fileName)
+	gcsKey := buildGCSKey(storeID, upload.Kind, mediaID, upload.FileName)
 
 	mediaRow := &models.Media{
 		ID:        mediaID,
 		StoreID:   storeID,
 		UserID:    userID,
-		Kind:      input.Kind,
+		Kind:      upload.Kind,
 		Status:    enums.MediaStatusPending,
 		GCSKey:    gcsKey,
-		FileName:  fileName,
-		MimeType:  mimeType,
-		SizeBytes: input.SizeBytes,
+		FileName:  upload.FileName,
+		MimeType:  upload.MimeType,
+		SizeBytes: upload.SizeBytes,
 	}
 
 	if _, err := s.repo.Create(ctx, mediaRow); err != nil {
 		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "persist media row")
 	}
 
-	expiresAt := time.Now().Add(s.uploadTTL)
-	signedURL, err := s.gcs.SignedURL(s.bucket, gcsKey, mimeType, s.uploadTTL)
+	uploadTTL := s.uploadTTLFor(upload.Kind)
+	expiresAt := time.Now().Add(uploadTTL)
+	signedURL, err := s.gcs.SignedURL(s.bucket, gcsKey, upload.MimeType, uploadTTL)
 	if err != nil {
 		_ = s.repo.Delete(ctx, mediaID)
 		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "sign upload url")
@@ -198,14 +303,101 @@ func (s *service) PresignUpload(ctx context.Context, userID, storeID uuid.UUID,
 		MediaID:      mediaID,
 		GCSKey:       gcsKey,
 		SignedPUTURL: signedURL,
-		ContentType:  mimeType,
+		ContentType:  upload.MimeType,
 		ExpiresAt:    expiresAt,
 	}, nil
 }
 
+// ConfirmUpload lets the client report a finished upload instead of waiting for the GCS
+// notification consumer, which stays as the backstop. It checks the object exists, records the
+// stored size and content type, and marks the media ready. Confirming ready media is a no-op.
+func (s *service) ConfirmUpload(ctx context.Context, userID, storeID, mediaID uuid.UUID) (*ListItem, error) {
+	if userID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "user identity missing")
+	}
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store identity missing")
+	}
+	if mediaID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "media id required")
+	}
+
+	ok, err := s.memberships.UserHasRole(ctx, userID, storeID, s.allowedRoles...)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check membership role")
+	}
+	if !ok {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "insufficient store role")
+	}
+
+	mediaRow, err := s.repo.FindByID(ctx, mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "media not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "lookup media")
+	}
+	if mediaRow.StoreID != storeID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "media does not belong to active store")
+	}
+
+	switch mediaRow.Status {
+	case enums.MediaStatusReady:
+		return s.confirmedItem(*mediaRow), nil
+	case enums.MediaStatusPending, enums.MediaStatusUploaded:
+	default:
+		return nil, pkgerrors.New(pkgerrors.CodeConflict, "media cannot be confirmed in current state")
+	}
+
+	attrs, err := s.gcs.ObjectAttrs(ctx, s.bucket, mediaRow.GCSKey)
+	if err != nil {
+		if errors.Is(err, gcs.ErrObjectNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeConflict, "uploaded object not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "lookup uploaded object")
+	}
+	publicURL, err := gcs.PublicURL(s.bucket, mediaRow.GCSKey)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeInternal, err, "build public url")
+	}
+
+	readyAt := time.Now().UTC()
+	updated, err := s.repo.MarkReady(ctx, mediaRow.ID, readyAt, publicURL, attrs.Size, attrs.ContentType)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "mark media ready")
+	}
+	if !updated {
+		return nil, pkgerrors.New(pkgerrors.CodeConflict, "media cannot be confirmed in current state")
+	}
+
+	mediaRow.Status = enums.MediaStatusReady
+	mediaRow.SizeBytes = attrs.Size
+	if attrs.ContentType != "" {
+		mediaRow.MimeType = attrs.ContentType
+	}
+	mediaRow.PublicURL = publicURL
+	if mediaRow.UploadedAt == nil {
+		mediaRow.UploadedAt = &readyAt
+	}
+	mediaRow.ReadyAt = &readyAt
+	return s.confirmedItem(*mediaRow), nil
+}
+
+func (s *service) confirmedItem(mediaRow models.Media) *ListItem {
+	item := toListItem(mediaRow)
+	url, _ := s.buildReadURL(mediaRow)
+	item.SignedURL = stringPtr(url)
+	return &item
+}
+
 type ReadURLParams struct {
 	StoreID uuid.UUID
 	MediaID uuid.UUID
+	// ActorUserID and ActorStoreID identify who asked for the URL; protected media records them
+	// in the access log together with Purpose (defaults to "download").
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+	Purpose      string
 }
 
 type ReadURLOutput struct {
@@ -240,6 +432,10 @@ func (s *service) GenerateReadURL(ctx context.Context, params ReadURLParams) (*R
 		return nil, pkgerrors.New(pkgerrors.CodeConflict, "media not available for download")
 	}
 
+	if isProtectedReadKind(mediaRow.Kind) {
+		return s.protectedReadURL(ctx, mediaRow, params)
+	}
+
 	// if mediaRow.PublicURL == "" {
 	// 	return nil, pkgerrors.New(pkgerrors.CodeDependency, "public url missing for media")
 	// }
@@ -250,11 +446,58 @@ func (s *service) GenerateReadURL(ctx context.Context, params ReadURLParams) (*R
 	}, nil
 }
 
+// protectedReadURL signs a short-lived download URL and records who asked for it. The URL is only
+// returned once the access log row is written, so no protected download goes unrecorded.
+func (s *service) protectedReadURL(ctx context.Context, mediaRow *models.Media, params ReadURLParams) (*ReadURLOutput, error) {
+	if s.accessLogs == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeInternal, "media access log unavailable")
+	}
+	downloadTTL := s.downloadTTLFor(mediaRow.Kind)
+	url, err := s.gcs.SignedReadURL(s.bucket, mediaRow.GCSKey, downloadTTL)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "generate signed read url")
+	}
+	expiresAt := time.Now().UTC().Add(downloadTTL)
+
+	purpose := strings.TrimSpace(params.Purpose)
+	if purpose == "" {
+		purpose = defaultReadPurpose
+	}
+	entry := &models.MediaAccessLog{
+		MediaID:      mediaRow.ID,
+		StoreID:      mediaRow.StoreID,
+		ActorUserID:  optionalUUID(params.ActorUserID),
+		ActorStoreID: optionalUUID(params.ActorStoreID),
+		MediaKind:    mediaRow.Kind,
+		Purpose:      purpose,
+		ExpiresAt:    expiresAt,
+	}
+	if err := s.accessLogs.Create(ctx, entry); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record media access")
+	}
+
+	return &ReadURLOutput{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func optionalUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// DeleteMediaParams identifies the media to delete or restore within the active store.
 type DeleteMediaParams struct {
 	StoreID uuid.UUID
 	MediaID uuid.UUID
 }
 
+// DeleteMedia schedules unprotected media for purging after the deletion grace period. The GCS
+// object and row are left in place until the media purge cron job runs, so UndeleteMedia can
+// restore them in the meantime.
 func (s *service) DeleteMedia(ctx context.Context, params DeleteMediaParams) error {
 	if params.StoreID == uuid.Nil {
 		return pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
@@ -284,11 +527,12 @@ func (s *service) DeleteMedia(ctx context.Context, params DeleteMediaParams) err
 			fmt.Sprintf("media has protected attachments: %s", strings.Join(protected, ", ")))
 	}
 
-	if err := s.gcs.DeleteObject(ctx, s.bucket, mediaRow.GCSKey); err != nil {
-		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "delete gcs object")
+	if mediaRow.Status == enums.MediaStatusPendingDeletion {
+		return nil
 	}
 
-	if err = s.repo.Delete(ctx, mediaRow.ID); err != nil {
+	now := s.now().UTC()
+	if _, err := s.repo.MarkPendingDeletion(ctx, mediaRow.ID, now, now.Add(s.deletionGrace)); err != nil {
 		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "could not delete object")
 	}
 
@@ -8,15 +8,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	gcsclient "github.com/angelmondragon/packfinderz-backend/pkg/storage/gcs"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type stubMediaRepo struct {
 	created     *models.Media
+	createdAll  []*models.Media
 	deleteID    uuid.UUID
 	createErr   error
 	deleteErr   error
@@ -25,6 +28,11 @@ type stubMediaRepo struct {
 	markDeleted bool
 	deletedAt   time.Time
 	markErr     error
+	markedReady bool
+	readySize   int64
+	readyMime   string
+	purgeAfter  time.Time
+	restored    enums.MediaStatus
 }
 
 func (s *stubMediaRepo) Create(ctx context.Context, media *models.Media) (*models.Media, error) {
@@ -32,6 +40,7 @@ func (s *stubMediaRepo) Create(ctx context.Context, media *models.Media) (*model
 		return nil, s.createErr
 	}
 	s.created = media
+	s.createdAll = append(s.createdAll, media)
 	return media, nil
 }
 
@@ -63,6 +72,31 @@ func (s *stubMediaRepo) MarkDeleted(ctx context.Context, id uuid.UUID, deletedAt
 	return nil
 }
 
+func (s *stubMediaRepo) MarkReady(ctx context.Context, id uuid.UUID, readyAt time.Time, publicURL string, sizeBytes int64, mimeType string) (bool, error) {
+	s.markedReady = true
+	s.readySize = sizeBytes
+	s.readyMime = mimeType
+	return true, nil
+}
+
+func (s *stubMediaRepo) MarkPendingDeletion(ctx context.Context, id uuid.UUID, deletedAt, purgeAfter time.Time) (bool, error) {
+	if s.markErr != nil {
+		return false, s.markErr
+	}
+	s.markDeleted = true
+	s.deletedAt = deletedAt
+	s.purgeAfter = purgeAfter
+	return true, nil
+}
+
+func (s *stubMediaRepo) Undelete(ctx context.Context, id uuid.UUID, status enums.MediaStatus, now time.Time) (bool, error) {
+	if s.findMedia == nil || s.findMedia.PurgeAfter == nil || !now.Before(*s.findMedia.PurgeAfter) {
+		return false, nil
+	}
+	s.restored = status
+	return true, nil
+}
+
 func (s *stubMediaRepo) Count(ctx context.Context, opts listQuery) (int64, error) {
 	return 0, nil
 }
@@ -91,9 +125,31 @@ type stubGCS struct {
 	lastMimeType string
 	deleteCalled bool
 	deleteErr    error
+	lastReadTTL  time.Duration
+	lastTTL      time.Duration
+	attrs        *gcsclient.ObjectAttrs
+	attrsErr     error
+}
+
+type stubAccessLogRepo struct {
+	entries []models.MediaAccessLog
+	err     error
+}
+
+func (s *stubAccessLogRepo) Create(ctx context.Context, entry *models.MediaAccessLog) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+func (s *stubAccessLogRepo) List(ctx context.Context, query AccessLogQuery) (*AccessLogList, error) {
+	return &AccessLogList{Entries: s.entries}, nil
 }
 
 func (s *stubGCS) SignedURL(bucket, object, contentType string, expires time.Duration) (string, error) {
+	s.lastTTL = expires
 	s.lastBucket = bucket
 	s.lastObject = object
 	s.lastMimeType = contentType
@@ -103,6 +159,25 @@ func (s *stubGCS) SignedURL(bucket, object, contentType string, expires time.Dur
 	return s.url, nil
 }
 
+func (s *stubGCS) SignedReadURL(bucket, object string, expires time.Duration) (string, error) {
+	s.lastBucket = bucket
+	s.lastObject = object
+	s.lastReadTTL = expires
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.url, nil
+}
+
+func (s *stubGCS) ObjectAttrs(ctx context.Context, bucket, object string) (*gcsclient.ObjectAttrs, error) {
+	s.lastBucket = bucket
+	s.lastObject = object
+	if s.attrsErr != nil {
+		return nil, s.attrsErr
+	}
+	return s.attrs, nil
+}
+
 func (s *stubGCS) DeleteObject(ctx context.Context, bucket, object string) error {
 	s.deleteCalled = true
 	s.lastBucket = bucket
@@ -221,6 +296,87 @@ func TestMediaServicePresignValidation(t *testing.T) {
 	}
 }
 
+func TestMediaServicePresignUploadBatch(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubMediaRepo{}
+	gcs := &stubGCS{url: "https://signed.example"}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	storeID := uuid.New()
+	inputs := []PresignInput{
+		{Kind: enums.MediaKindProduct, MimeType: "image/png", FileName: " front.png ", SizeBytes: 1024},
+		{Kind: enums.MediaKindProduct, MimeType: "image/jpeg", FileName: "back.jpg", SizeBytes: 2048},
+		{Kind: enums.MediaKindCOA, MimeType: "application/pdf", FileName: "coa.pdf", SizeBytes: 4096},
+	}
+	outputs, err := svc.PresignUploadBatch(context.Background(), uuid.New(), storeID, inputs)
+	if err != nil {
+		t.Fatalf("PresignUploadBatch returned error: %v", err)
+	}
+	if len(outputs) != len(inputs) || len(repo.createdAll) != len(inputs) {
+		t.Fatalf("expected %d presigned uploads, got %d outputs and %d rows", len(inputs), len(outputs), len(repo.createdAll))
+	}
+	seen := map[uuid.UUID]bool{}
+	for i, out := range outputs {
+		row := repo.createdAll[i]
+		if out.MediaID != row.ID || out.GCSKey != row.GCSKey || out.SignedPUTURL != gcs.url {
+			t.Fatalf("output %d does not match its media row: %+v vs %+v", i, out, row)
+		}
+		if row.StoreID != storeID || row.Kind != inputs[i].Kind || out.ContentType != inputs[i].MimeType {
+			t.Fatalf("output %d out of order or mismatched: %+v", i, row)
+		}
+		seen[out.MediaID] = true
+	}
+	if len(seen) != len(inputs) {
+		t.Fatalf("expected distinct media ids, got %v", seen)
+	}
+	if repo.createdAll[0].FileName != "front.png" {
+		t.Fatalf("expected trimmed file name, got %q", repo.createdAll[0].FileName)
+	}
+}
+
+func TestMediaServicePresignUploadBatchValidation(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubMediaRepo{}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, &stubGCS{url: "ok"}, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	valid := PresignInput{Kind: enums.MediaKindProduct, MimeType: "image/png", FileName: "photo.png", SizeBytes: 1024}
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		inputs := make([]PresignInput, maxPresignBatchSize+1)
+		for i := range inputs {
+			inputs[i] = valid
+		}
+		_, err := svc.PresignUploadBatch(context.Background(), uuid.New(), uuid.New(), inputs)
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+			t.Fatalf("expected validation error for an oversized batch, got %v", err)
+		}
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		invalid := valid
+		invalid.SizeBytes = maxUploadBytes + 1
+		_, err := svc.PresignUploadBatch(context.Background(), uuid.New(), uuid.New(), []PresignInput{valid, invalid})
+		typed := pkgerrors.As(err)
+		if typed == nil || typed.Code() != pkgerrors.CodeValidation {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+		if _, ok := typed.FieldErrors()["uploads[1]"]; !ok || len(typed.FieldErrors()) != 1 {
+			t.Fatalf("expected the second upload flagged, got %v", typed.FieldErrors())
+		}
+	})
+
+	if len(repo.createdAll) != 0 {
+		t.Fatalf("expected no media rows for rejected batches, got %d", len(repo.createdAll))
+	}
+}
+
 func TestMediaServicePresignForbidden(t *testing.T) {
 	t.Parallel()
 
@@ -330,6 +486,214 @@ func TestMediaServiceGenerateReadURLSuccess(t *testing.T) {
 	}
 }
 
+func TestMediaServiceGenerateReadURLAuditsLicenseDownloads(t *testing.T) {
+	t.Parallel()
+
+	storeID := uuid.New()
+	userID := uuid.New()
+	mediaID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:        mediaID,
+			StoreID:   storeID,
+			Kind:      enums.MediaKindLicenseDoc,
+			Status:    enums.MediaStatusUploaded,
+			GCSKey:    "license/key.pdf",
+			PublicURL: "https://public.example/license.pdf",
+		},
+	}
+	gcs := &stubGCS{url: "https://signed.example/license.pdf"}
+	logs := &stubAccessLogRepo{}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", time.Minute, 15*time.Minute, WithAccessLog(logs))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	resp, err := svc.GenerateReadURL(context.Background(), ReadURLParams{
+		StoreID:      storeID,
+		MediaID:      mediaID,
+		ActorUserID:  userID,
+		ActorStoreID: storeID,
+		Purpose:      "compliance_review",
+	})
+	if err != nil {
+		t.Fatalf("GenerateReadURL returned error: %v", err)
+	}
+	if resp.URL != gcs.url || gcs.lastObject != "license/key.pdf" || gcs.lastReadTTL != 15*time.Minute {
+		t.Fatalf("expected a signed url for the license object, got %s (object %s ttl %s)", resp.URL, gcs.lastObject, gcs.lastReadTTL)
+	}
+	if len(logs.entries) != 1 {
+		t.Fatalf("expected one access log entry, got %d", len(logs.entries))
+	}
+	entry := logs.entries[0]
+	if entry.MediaID != mediaID || entry.StoreID != storeID || entry.MediaKind != enums.MediaKindLicenseDoc {
+		t.Fatalf("unexpected access log entry %+v", entry)
+	}
+	if entry.ActorUserID == nil || *entry.ActorUserID != userID || entry.Purpose != "compliance_review" {
+		t.Fatalf("expected actor and purpose recorded, got %+v", entry)
+	}
+	if !entry.ExpiresAt.Equal(resp.ExpiresAt) {
+		t.Fatalf("expected log expiry %s to match url expiry %s", entry.ExpiresAt, resp.ExpiresAt)
+	}
+}
+
+func TestMediaServiceURLExpiryByKind(t *testing.T) {
+	t.Parallel()
+
+	storeID := uuid.New()
+	mediaID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:      mediaID,
+			StoreID: storeID,
+			Kind:    enums.MediaKindLicenseDoc,
+			Status:  enums.MediaStatusReady,
+			GCSKey:  "license/key.pdf",
+		},
+	}
+	gcs := &stubGCS{url: "https://signed.example"}
+	opt, err := NewURLExpiryOption(config.GCSConfig{
+		UploadURLExpiryByKind:   map[string]time.Duration{"license_doc": 2 * time.Minute},
+		DownloadURLExpiryByKind: map[string]time.Duration{" License_Doc ": 5 * time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("NewURLExpiryOption: %v", err)
+	}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", 30*time.Minute, 48*time.Hour, WithAccessLog(&stubAccessLogRepo{}), opt)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	resp, err := svc.GenerateReadURL(context.Background(), ReadURLParams{StoreID: storeID, MediaID: mediaID})
+	if err != nil {
+		t.Fatalf("GenerateReadURL returned error: %v", err)
+	}
+	if gcs.lastReadTTL != 5*time.Minute {
+		t.Fatalf("expected license read url to use the 5m override, got %s", gcs.lastReadTTL)
+	}
+	if time.Until(resp.ExpiresAt) > 5*time.Minute {
+		t.Fatalf("expected expiry within 5m, got %s", resp.ExpiresAt)
+	}
+
+	if _, err := svc.PresignUpload(context.Background(), uuid.New(), storeID, PresignInput{
+		Kind:      enums.MediaKindProduct,
+		MimeType:  "image/png",
+		FileName:  "photo.png",
+		SizeBytes: 1024,
+	}); err != nil {
+		t.Fatalf("PresignUpload returned error: %v", err)
+	}
+	if gcs.lastTTL != 30*time.Minute {
+		t.Fatalf("expected product image upload to use the default 30m, got %s", gcs.lastTTL)
+	}
+}
+
+func TestNewURLExpiryOptionRejectsInvalidOverrides(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewURLExpiryOption(config.GCSConfig{DownloadURLExpiryByKind: map[string]time.Duration{"passport": time.Minute}}); err == nil {
+		t.Fatal("expected unknown media kind to be rejected")
+	}
+	if _, err := NewURLExpiryOption(config.GCSConfig{UploadURLExpiryByKind: map[string]time.Duration{"license_doc": 0}}); err == nil {
+		t.Fatal("expected non-positive expiry to be rejected")
+	}
+}
+
+func TestMediaServiceGenerateReadURLFailsWhenAuditFails(t *testing.T) {
+	t.Parallel()
+
+	storeID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:      uuid.New(),
+			StoreID: storeID,
+			Kind:    enums.MediaKindLicenseDoc,
+			Status:  enums.MediaStatusReady,
+			GCSKey:  "license/key.pdf",
+		},
+	}
+	logs := &stubAccessLogRepo{err: errors.New("db down")}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, &stubGCS{url: "https://signed.example"}, "bucket", time.Minute, 15*time.Minute, WithAccessLog(logs))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	resp, err := svc.GenerateReadURL(context.Background(), ReadURLParams{StoreID: storeID, MediaID: repo.findMedia.ID})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeDependency {
+		t.Fatalf("expected dependency error, got %v", err)
+	}
+	if resp != nil {
+		t.Fatalf("expected no url when the access log write fails")
+	}
+}
+
+func TestMediaServiceConfirmUploadMarksReady(t *testing.T) {
+	t.Parallel()
+
+	storeID := uuid.New()
+	mediaID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:       mediaID,
+			StoreID:  storeID,
+			Kind:     enums.MediaKindProduct,
+			Status:   enums.MediaStatusPending,
+			GCSKey:   "media/key.png",
+			MimeType: "image/png",
+		},
+	}
+	gcs := &stubGCS{attrs: &gcsclient.ObjectAttrs{Size: 2048, ContentType: "image/png"}}
+
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	item, err := svc.ConfirmUpload(context.Background(), uuid.New(), storeID, mediaID)
+	if err != nil {
+		t.Fatalf("ConfirmUpload returned error: %v", err)
+	}
+	if gcs.lastObject != "media/key.png" {
+		t.Fatalf("expected object lookup for media/key.png, got %s", gcs.lastObject)
+	}
+	if !repo.markedReady || repo.readySize != 2048 || repo.readyMime != "image/png" {
+		t.Fatalf("expected ready update with size and mime, got %+v", repo)
+	}
+	if item.Status != enums.MediaStatusReady || item.SizeBytes != 2048 {
+		t.Fatalf("unexpected item %+v", item)
+	}
+}
+
+func TestMediaServiceConfirmUploadMissingObject(t *testing.T) {
+	t.Parallel()
+
+	storeID := uuid.New()
+	mediaID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:      mediaID,
+			StoreID: storeID,
+			Kind:    enums.MediaKindProduct,
+			Status:  enums.MediaStatusPending,
+			GCSKey:  "media/key.png",
+		},
+	}
+	gcs := &stubGCS{attrsErr: gcsclient.ErrObjectNotFound}
+
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	_, err = svc.ConfirmUpload(context.Background(), uuid.New(), storeID, mediaID)
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if repo.markedReady {
+		t.Fatal("expected media to stay pending")
+	}
+}
+
 func TestMediaServiceGenerateReadURLStoreMismatch(t *testing.T) {
 	t.Parallel()
 
@@ -436,12 +800,83 @@ func TestMediaServiceDeleteSuccess(t *testing.T) {
 		t.Fatalf("NewService: %v", err)
 	}
 
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.(*service).now = func() time.Time { return now }
+
 	if err := svc.DeleteMedia(context.Background(), DeleteMediaParams{
 		StoreID: storeID,
 		MediaID: mediaID,
 	}); err != nil {
 		t.Fatalf("DeleteMedia returned error: %v", err)
 	}
+	if !repo.markDeleted || !repo.purgeAfter.Equal(now.Add(defaultDeletionGracePeriod)) {
+		t.Fatalf("expected media pending deletion until %s, got %s", now.Add(defaultDeletionGracePeriod), repo.purgeAfter)
+	}
+	if gcs.deleteCalled {
+		t.Fatal("gcs object should be kept until the grace period ends")
+	}
+}
+
+func TestMediaServiceUndeleteWithinGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	readyAt := now.Add(-48 * time.Hour)
+	purgeAfter := now.Add(time.Hour)
+	storeID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:         uuid.New(),
+			StoreID:    storeID,
+			Status:     enums.MediaStatusPendingDeletion,
+			GCSKey:     "media/key",
+			ReadyAt:    &readyAt,
+			PurgeAfter: &purgeAfter,
+		},
+	}
+	gcs := &stubGCS{attrs: &gcsclient.ObjectAttrs{Size: 10}}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, gcs, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	svc.(*service).now = func() time.Time { return now }
+
+	if err := svc.UndeleteMedia(context.Background(), DeleteMediaParams{StoreID: storeID, MediaID: repo.findMedia.ID}); err != nil {
+		t.Fatalf("UndeleteMedia returned error: %v", err)
+	}
+	if repo.restored != enums.MediaStatusReady {
+		t.Fatalf("expected media restored to ready, got %q", repo.restored)
+	}
+}
+
+func TestMediaServiceUndeleteAfterGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	purgeAfter := now.Add(-time.Minute)
+	storeID := uuid.New()
+	repo := &stubMediaRepo{
+		findMedia: &models.Media{
+			ID:         uuid.New(),
+			StoreID:    storeID,
+			Status:     enums.MediaStatusPendingDeletion,
+			GCSKey:     "media/key",
+			PurgeAfter: &purgeAfter,
+		},
+	}
+	svc, err := NewService(repo, stubMemberships{ok: true}, &stubAttachmentLookup{}, &stubGCS{}, "bucket", time.Minute, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	svc.(*service).now = func() time.Time { return now }
+
+	err = svc.UndeleteMedia(context.Background(), DeleteMediaParams{StoreID: storeID, MediaID: repo.findMedia.ID})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if repo.restored != "" {
+		t.Fatal("expected media to stay pending deletion")
+	}
 }
 
 func TestMediaServiceDeleteStoreMismatch(t *testing.T) {
@@ -486,8 +921,9 @@ func TestMediaServiceDeleteDependencyError(t *testing.T) {
 			GCSKey:  "media/key",
 		},
 	}
+	repo.markErr = errors.New("boom")
 	members := stubMemberships{ok: true}
-	gcs := &stubGCS{deleteErr: errors.New("boom")}
+	gcs := &stubGCS{}
 	attachments := &stubAttachmentLookup{}
 	svc, err := NewService(repo, members, attachments, gcs, "bucket", time.Minute, 15*time.Minute)
 	if err != nil {
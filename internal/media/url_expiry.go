@@ -0,0 +1,62 @@
+package media
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// WithURLExpiryOverrides signs upload and download URLs for the listed media kinds with their own
+// TTLs instead of the service-wide defaults.
+func WithURLExpiryOverrides(upload, download map[enums.MediaKind]time.Duration) ServiceOption {
+	return func(s *service) {
+		s.uploadTTLByKind = upload
+		s.downloadTTLByKind = download
+	}
+}
+
+// NewURLExpiryOption builds the per-kind expiry overrides from config, rejecting unknown media
+// kinds and non-positive windows.
+func NewURLExpiryOption(cfg config.GCSConfig) (ServiceOption, error) {
+	upload, err := parseKindExpiries("upload", cfg.UploadURLExpiryByKind)
+	if err != nil {
+		return nil, err
+	}
+	download, err := parseKindExpiries("download", cfg.DownloadURLExpiryByKind)
+	if err != nil {
+		return nil, err
+	}
+	return WithURLExpiryOverrides(upload, download), nil
+}
+
+func parseKindExpiries(purpose string, raw map[string]time.Duration) (map[enums.MediaKind]time.Duration, error) {
+	expiries := make(map[enums.MediaKind]time.Duration, len(raw))
+	for key, ttl := range raw {
+		kind, err := enums.ParseMediaKind(strings.ToLower(strings.TrimSpace(key)))
+		if err != nil {
+			return nil, fmt.Errorf("%s url expiry: %w", purpose, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("%s url expiry for %s must be positive", purpose, kind)
+		}
+		expiries[kind] = ttl
+	}
+	return expiries, nil
+}
+
+func (s *service) uploadTTLFor(kind enums.MediaKind) time.Duration {
+	if ttl, ok := s.uploadTTLByKind[kind]; ok {
+		return ttl
+	}
+	return s.uploadTTL
+}
+
+func (s *service) downloadTTLFor(kind enums.MediaKind) time.Duration {
+	if ttl, ok := s.downloadTTLByKind[kind]; ok {
+		return ttl
+	}
+	return s.downloadTTL
+}
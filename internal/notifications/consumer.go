@@ -13,6 +13,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/idempotency"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"github.com/google/uuid"
 )
 
@@ -22,16 +23,17 @@ type repository interface {
 	Create(ctx context.Context, notification *models.Notification) error
 }
 
-// Consumer watches domain events and turns license status transitions into notifications.
+// Consumer watches domain events and turns license and store KYC status transitions into notifications.
 type Consumer struct {
 	repo         repository
 	subscription *pubsub.Subscriber
 	idempotency  *idempotency.Manager
+	guard        *redelivery.Guard
 	logg         *logger.Logger
 }
 
-// NewConsumer builds a license notification consumer.
-func NewConsumer(repo repository, subscription *pubsub.Subscriber, manager *idempotency.Manager, logg *logger.Logger) (*Consumer, error) {
+// NewConsumer builds a license notification consumer. A nil guard retries failing messages without a cap.
+func NewConsumer(repo repository, subscription *pubsub.Subscriber, manager *idempotency.Manager, logg *logger.Logger, guard *redelivery.Guard) (*Consumer, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("notifications repository required")
 	}
@@ -48,20 +50,29 @@ func NewConsumer(repo repository, subscription *pubsub.Subscriber, manager *idem
 		repo:         repo,
 		subscription: subscription,
 		idempotency:  manager,
+		guard:        guard,
 		logg:         logg,
 	}, nil
 }
 
 // Run starts the consumer loop until the context is canceled.
 func (c *Consumer) Run(ctx context.Context) error {
-	return c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-		result := c.process(ctx, msg)
-		if result.nack {
+	err := c.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		delivery := redelivery.Message{ID: msg.ID, Data: msg.Data, Attributes: msg.Attributes, DeliveryAttempt: msg.DeliveryAttempt}
+		decision := c.guard.Handle(ctx, delivery, func(ctx context.Context) error {
+			if c.process(ctx, msg).nack {
+				return redelivery.ErrRetry
+			}
+			return nil
+		})
+		if decision == redelivery.Nack {
 			msg.Nack()
 			return
 		}
 		msg.Ack()
 	})
+	c.guard.Drain()
+	return err
 }
 
 type processResult struct {
@@ -77,8 +88,8 @@ func (c *Consumer) process(ctx context.Context, msg *pubsub.Message) processResu
 	}
 	logCtx := c.logg.WithFields(ctx, fields)
 
-	if eventType != string(enums.EventLicenseStatusChanged) {
-		c.logg.Info(logCtx, "skipping non-license event")
+	if eventType != string(enums.EventLicenseStatusChanged) && eventType != string(enums.EventStoreKYCStatusChanged) {
+		c.logg.Info(logCtx, "skipping non-compliance event")
 		return processResult{ack: true}
 	}
 
@@ -94,6 +105,8 @@ func (c *Consumer) process(ctx context.Context, msg *pubsub.Message) processResu
 		return processResult{ack: true}
 	}
 
+	// Dedup on the domain event ID, not msg.ID: the outbox republishes an event under a new
+	// message ID when a publish is retried, and each copy must still produce one notification.
 	already, err := c.idempotency.CheckAndMarkProcessed(ctx, licenseNotificationConsumer, eventID)
 	if err != nil {
 		c.logg.Error(logCtx, "idempotency check failed", err)
@@ -104,6 +117,25 @@ func (c *Consumer) process(ctx context.Context, msg *pubsub.Message) processResu
 		return processResult{ack: true}
 	}
 
+	if eventType == string(enums.EventStoreKYCStatusChanged) {
+		var payload payloads.StoreKYCStatusChangedEvent
+		if err := json.Unmarshal(envelope.Data, &payload); err != nil {
+			c.logg.Error(logCtx, "failed to parse payload", err)
+			_ = c.idempotency.Delete(ctx, licenseNotificationConsumer, eventID)
+			return processResult{nack: true}
+		}
+		logCtx = c.logg.WithFields(logCtx, map[string]any{
+			"store_id": payload.StoreID.String(),
+			"status":   payload.Status,
+		})
+		if err := c.createKYCNotification(ctx, payload, logCtx); err != nil {
+			c.logg.Error(logCtx, "notification handling failed", err)
+			_ = c.idempotency.Delete(ctx, licenseNotificationConsumer, eventID)
+			return processResult{nack: true}
+		}
+		return processResult{ack: true}
+	}
+
 	var payload payloads.LicenseStatusChangedEvent
 	if err := json.Unmarshal(envelope.Data, &payload); err != nil {
 		c.logg.Error(logCtx, "failed to parse payload", err)
@@ -187,6 +219,35 @@ func (c *Consumer) createAdminNotification(ctx context.Context, payload payloads
 	return nil
 }
 
+func (c *Consumer) createKYCNotification(ctx context.Context, payload payloads.StoreKYCStatusChangedEvent, logCtx context.Context) error {
+	if payload.StoreID == uuid.Nil {
+		return fmt.Errorf("store id missing")
+	}
+	title := "Verification status updated"
+	message := fmt.Sprintf("Your store verification status changed to %s.", payload.Status)
+	switch payload.Status {
+	case enums.KYCStatusVerified:
+		title = "Store verified"
+		message = "Your store has been verified."
+	case enums.KYCStatusRejected, enums.KYCStatusSuspended:
+		if payload.Reason != "" {
+			message = fmt.Sprintf("Your store verification status changed to %s. Reason: %s", payload.Status, payload.Reason)
+		}
+	}
+	notification := &models.Notification{
+		StoreID: payload.StoreID,
+		Type:    enums.NotificationTypeCompliance,
+		Title:   title,
+		Message: strings.TrimSpace(message),
+		Link:    stringPtr(fmt.Sprintf("/stores/%s", payload.StoreID)),
+	}
+	if err := c.repo.Create(ctx, notification); err != nil {
+		return err
+	}
+	c.logg.Info(logCtx, "store notified of kyc change")
+	return nil
+}
+
 func stringPtr(value string) *string {
 	return &value
 }
@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	pubsub "cloud.google.com/go/pubsub/v2"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/idempotency"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+)
+
+type memoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+func (s *memoryIdempotencyStore) Get(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func (s *memoryIdempotencyStore) SetNX(_ context.Context, key string, _ any, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		s.keys = map[string]struct{}{}
+	}
+	if _, ok := s.keys[key]; ok {
+		return false, nil
+	}
+	s.keys[key] = struct{}{}
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) IdempotencyKey(scope, id string) string {
+	return "pf:idempotency:" + scope + ":" + id
+}
+
+func (s *memoryIdempotencyStore) Del(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+type recordingNotificationRepo struct {
+	created []models.Notification
+}
+
+func (r *recordingNotificationRepo) Create(_ context.Context, notification *models.Notification) error {
+	r.created = append(r.created, *notification)
+	return nil
+}
+
+func newTestConsumer(t *testing.T, repo repository) *Consumer {
+	t.Helper()
+	manager, err := idempotency.NewManager(&memoryIdempotencyStore{}, time.Hour)
+	if err != nil {
+		t.Fatalf("new idempotency manager: %v", err)
+	}
+	logg := logger.New(logger.Options{ServiceName: "test", Output: io.Discard})
+	consumer, err := NewConsumer(repo, &pubsub.Subscriber{}, manager, logg, nil)
+	if err != nil {
+		t.Fatalf("new consumer: %v", err)
+	}
+	return consumer
+}
+
+func licenseEventMessage(t *testing.T, messageID string, eventID uuid.UUID, payload payloads.LicenseStatusChangedEvent) *pubsub.Message {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	envelope, err := json.Marshal(outbox.PayloadEnvelope{
+		Version:    1,
+		EventID:    eventID.String(),
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		t.Fatalf("marshal envelope: %v", err)
+	}
+	return &pubsub.Message{
+		ID:         messageID,
+		Data:       envelope,
+		Attributes: map[string]string{"event_type": string(enums.EventLicenseStatusChanged)},
+	}
+}
+
+func TestConsumerDedupsEventAcrossMessages(t *testing.T) {
+	repo := &recordingNotificationRepo{}
+	consumer := newTestConsumer(t, repo)
+
+	eventID := uuid.New()
+	payload := payloads.LicenseStatusChangedEvent{
+		LicenseID: uuid.New(),
+		StoreID:   uuid.New(),
+		Status:    enums.LicenseStatusVerified,
+	}
+
+	for _, messageID := range []string{"msg-1", "msg-2"} {
+		result := consumer.process(context.Background(), licenseEventMessage(t, messageID, eventID, payload))
+		if !result.ack || result.nack {
+			t.Fatalf("expected %s to be acked, got %+v", messageID, result)
+		}
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("expected a single notification for one event, got %d", len(repo.created))
+	}
+	if repo.created[0].StoreID != payload.StoreID {
+		t.Fatalf("expected notification for store %s, got %s", payload.StoreID, repo.created[0].StoreID)
+	}
+}
+
+func TestConsumerNotifiesForDistinctEvents(t *testing.T) {
+	repo := &recordingNotificationRepo{}
+	consumer := newTestConsumer(t, repo)
+
+	payload := payloads.LicenseStatusChangedEvent{
+		LicenseID: uuid.New(),
+		StoreID:   uuid.New(),
+		Status:    enums.LicenseStatusVerified,
+	}
+
+	// The same message ID carrying two different events must not be collapsed.
+	for _, eventID := range []uuid.UUID{uuid.New(), uuid.New()} {
+		result := consumer.process(context.Background(), licenseEventMessage(t, "msg-1", eventID, payload))
+		if !result.ack || result.nack {
+			t.Fatalf("expected event %s to be acked, got %+v", eventID, result)
+		}
+	}
+
+	if len(repo.created) != 2 {
+		t.Fatalf("expected one notification per event, got %d", len(repo.created))
+	}
+}
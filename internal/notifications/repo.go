@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,10 +15,12 @@ import (
 type Repository interface {
 	WithTx(tx *gorm.DB) Repository
 	Create(ctx context.Context, notification *models.Notification) error
+	CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error
 	List(ctx context.Context, params listNotificationsParams) ([]models.Notification, *pagination.Cursor, error)
 	MarkRead(ctx context.Context, storeID, notificationID uuid.UUID, now time.Time) (notificationMarkResult, error)
 	MarkAllRead(ctx context.Context, storeID uuid.UUID, now time.Time) (int64, error)
-	DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time) (int64, error)
+	DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time, excludeTypes ...enums.NotificationType) (int64, error)
+	DeleteTypeOlderThan(ctx context.Context, tx *gorm.DB, notificationType enums.NotificationType, cutoff time.Time) (int64, error)
 }
 
 type repositoryImpl struct {
@@ -52,6 +55,10 @@ func (r *repositoryImpl) Create(ctx context.Context, notification *models.Notifi
 	return r.db.WithContext(ctx).Create(notification).Error
 }
 
+func (r *repositoryImpl) CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error {
+	return r.WithTx(tx).Create(ctx, notification)
+}
+
 func (r *repositoryImpl) List(ctx context.Context, params listNotificationsParams) ([]models.Notification, *pagination.Cursor, error) {
 	limit := pagination.LimitWithBuffer(params.Limit)
 	normalized := pagination.NormalizeLimit(params.Limit)
@@ -113,13 +120,32 @@ func (r *repositoryImpl) MarkAllRead(ctx context.Context, storeID uuid.UUID, now
 	return result.RowsAffected, nil
 }
 
-func (r *repositoryImpl) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time) (int64, error) {
+// DeleteOlderThan removes notifications created before cutoff, skipping excludeTypes so types with
+// their own retention can be swept separately.
+func (r *repositoryImpl) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time, excludeTypes ...enums.NotificationType) (int64, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	query := db.WithContext(ctx).Where("created_at < ?", cutoff)
+	if len(excludeTypes) > 0 {
+		query = query.Where("type NOT IN ?", excludeTypes)
+	}
+	result := query.Delete(&models.Notification{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteTypeOlderThan removes notifications of one type created before cutoff.
+func (r *repositoryImpl) DeleteTypeOlderThan(ctx context.Context, tx *gorm.DB, notificationType enums.NotificationType, cutoff time.Time) (int64, error) {
 	db := r.db
 	if tx != nil {
 		db = tx
 	}
 	result := db.WithContext(ctx).
-		Where("created_at < ?", cutoff).
+		Where("type = ? AND created_at < ?", notificationType, cutoff).
 		Delete(&models.Notification{})
 	if result.Error != nil {
 		return 0, result.Error
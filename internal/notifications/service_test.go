@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	paginationpkg "github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
@@ -20,7 +21,12 @@ type fakeRepository struct {
 }
 
 // DeleteOlderThan implements [Repository].
-func (f *fakeRepository) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time) (int64, error) {
+func (f *fakeRepository) DeleteOlderThan(ctx context.Context, tx *gorm.DB, cutoff time.Time, excludeTypes ...enums.NotificationType) (int64, error) {
+	panic("unimplemented")
+}
+
+// DeleteTypeOlderThan implements [Repository].
+func (f *fakeRepository) DeleteTypeOlderThan(ctx context.Context, tx *gorm.DB, notificationType enums.NotificationType, cutoff time.Time) (int64, error) {
 	panic("unimplemented")
 }
 
@@ -32,6 +38,10 @@ func (f *fakeRepository) Create(ctx context.Context, notification *models.Notifi
 	return nil
 }
 
+func (f *fakeRepository) CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error {
+	return nil
+}
+
 func (f *fakeRepository) List(ctx context.Context, params listNotificationsParams) ([]models.Notification, *paginationpkg.Cursor, error) {
 	if f.listFn != nil {
 		return f.listFn(ctx, params)
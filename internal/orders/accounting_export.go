@@ -0,0 +1,71 @@
+package orders
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+)
+
+// AccountingExportLine is one line item of an order being exported for accounting, carrying the
+// order-level totals alongside so each CSV row is self-contained.
+type AccountingExportLine struct {
+	OrderID           uuid.UUID      `gorm:"column:order_id"`
+	OrderNumber       int64          `gorm:"column:order_number"`
+	OrderReference    *string        `gorm:"column:order_reference"`
+	OrderCreatedAt    time.Time      `gorm:"column:order_created_at"`
+	Currency          enums.Currency `gorm:"column:currency"`
+	BuyerName         string         `gorm:"column:buyer_name"`
+	VendorName        string         `gorm:"column:vendor_name"`
+	ItemName          string         `gorm:"column:item_name"`
+	ItemCategory      string         `gorm:"column:item_category"`
+	Qty               int            `gorm:"column:qty"`
+	UnitPriceCents    int            `gorm:"column:unit_price_cents"`
+	LineTotalCents    int            `gorm:"column:line_total_cents"`
+	DiscountsCents    int            `gorm:"column:discounts_cents"`
+	TaxCents          int            `gorm:"column:tax_cents"`
+	TransportFeeCents int            `gorm:"column:transport_fee_cents"`
+	OrderTotalCents   int            `gorm:"column:order_total_cents"`
+}
+
+// accountingExcludedOrderStatuses never produced a sale, so they stay out of accounting exports.
+var accountingExcludedOrderStatuses = []enums.VendorOrderStatus{
+	enums.VendorOrderStatusRejected,
+	enums.VendorOrderStatusCanceled,
+	enums.VendorOrderStatusExpired,
+}
+
+// StreamAccountingExport calls fn for every non-rejected line item of the orders created in
+// [from, to), ordered by order creation. Rows are read from a cursor rather than loaded at once so
+// large windows can be streamed straight to the client.
+func (r *repository) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(AccountingExportLine) error) error {
+	rows, err := r.db.WithContext(ctx).Table("vendor_orders vo").
+		Select(`vo.id AS order_id, vo.order_number, vo.order_reference, vo.created_at AS order_created_at, vo.currency,
+			buyer.company_name AS buyer_name, vendor.company_name AS vendor_name,
+			li.name AS item_name, li.category AS item_category, li.qty, li.unit_price_cents, li.total_cents AS line_total_cents,
+			vo.discounts_cents, vo.tax_cents, vo.transport_fee_cents, vo.total_cents AS order_total_cents`).
+		Joins("JOIN stores buyer ON buyer.id = vo.buyer_store_id").
+		Joins("JOIN stores vendor ON vendor.id = vo.vendor_store_id").
+		Joins("JOIN order_line_items li ON li.order_id = vo.id").
+		Where("vo.created_at >= ? AND vo.created_at < ?", from, to).
+		Where("vo.status NOT IN ?", accountingExcludedOrderStatuses).
+		Where("li.status <> ?", enums.LineItemStatusRejected).
+		Order("vo.created_at ASC").Order("vo.id ASC").Order("li.created_at ASC").Order("li.id ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line AccountingExportLine
+		if err := r.db.ScanRows(rows, &line); err != nil {
+			return err
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
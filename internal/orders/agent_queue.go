@@ -0,0 +1,114 @@
+package orders
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
+)
+
+// maxProximityQueueCandidates caps how many queue rows are ranked in memory when the agent
+// queue is geo-sorted. The oldest orders are kept when the cap is hit so none starve, and the
+// response is flagged as truncated so agents know to narrow the filters.
+const maxProximityQueueCandidates = 500
+
+const proximityCursorPrefix = "geo"
+
+// proximityCursor resumes a geo-sorted queue after the last returned (distance, id) pair.
+// It is only meaningful for the agent location it was issued for.
+type proximityCursor struct {
+	DistanceMeters float64
+	ID             uuid.UUID
+}
+
+func encodeProximityCursor(c proximityCursor) string {
+	payload := fmt.Sprintf("%s|%s|%s", proximityCursorPrefix, strconv.FormatFloat(c.DistanceMeters, 'g', -1, 64), c.ID)
+	return base64.StdEncoding.EncodeToString([]byte(payload))
+}
+
+func parseProximityCursor(value string) (*proximityCursor, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	invalid := pkgerrors.New(pkgerrors.CodeValidation, "invalid cursor for location-sorted queue")
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.Split(string(decoded), "|")
+	if len(parts) != 3 || parts[0] != proximityCursorPrefix {
+		return nil, invalid
+	}
+	distance, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, invalid
+	}
+	return &proximityCursor{DistanceMeters: distance, ID: id}, nil
+}
+
+// capProximityCandidates trims oldest-first rows to the ranking cap and reports whether any
+// matching orders were left out. Callers fetch one row past the cap to detect that.
+func capProximityCandidates(records []agentOrderQueueRecord) ([]agentOrderQueueRecord, bool) {
+	if len(records) > maxProximityQueueCandidates {
+		return records[:maxProximityQueueCandidates], true
+	}
+	return records, false
+}
+
+type rankedQueueRecord struct {
+	record         agentOrderQueueRecord
+	distanceMeters float64
+}
+
+// after reports whether the ranked row sorts strictly after the cursor position.
+func (r rankedQueueRecord) after(c proximityCursor) bool {
+	if r.distanceMeters != c.DistanceMeters {
+		return r.distanceMeters > c.DistanceMeters
+	}
+	return r.record.ID.String() > c.ID.String()
+}
+
+// rankByProximity orders queue rows by distance from origin to the vendor address, breaking
+// ties by order ID so pages are deterministic.
+func rankByProximity(origin maps.LatLng, records []agentOrderQueueRecord) []rankedQueueRecord {
+	ranked := make([]rankedQueueRecord, 0, len(records))
+	for _, record := range records {
+		vendor := maps.LatLng{Latitude: record.VendorAddress.Lat, Longitude: record.VendorAddress.Lng}
+		ranked = append(ranked, rankedQueueRecord{
+			record:         record,
+			distanceMeters: maps.DistanceMeters(origin, vendor),
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].distanceMeters != ranked[j].distanceMeters {
+			return ranked[i].distanceMeters < ranked[j].distanceMeters
+		}
+		return ranked[i].record.ID.String() < ranked[j].record.ID.String()
+	})
+	return ranked
+}
+
+// pageProximity returns the page following cursor plus the cursor for the next page.
+func pageProximity(ranked []rankedQueueRecord, cursor *proximityCursor, pageSize int) ([]rankedQueueRecord, string) {
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(ranked), func(i int) bool { return ranked[i].after(*cursor) })
+	}
+	end := start + pageSize
+	if end >= len(ranked) {
+		return ranked[start:], ""
+	}
+	page := ranked[start:end]
+	last := page[len(page)-1]
+	return page, encodeProximityCursor(proximityCursor{DistanceMeters: last.distanceMeters, ID: last.record.ID})
+}
@@ -0,0 +1,125 @@
+package orders
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification types emitted while a buyer's late cancel is negotiated.
+const (
+	notificationCancelRequested = "order_cancel_request"
+	notificationCancelApproved  = "order_cancel_request_approved"
+	notificationCancelDenied    = "order_cancel_request_denied"
+)
+
+// CancelRequestDecisionInput carries the vendor's answer to a buyer's pending cancel request.
+type CancelRequestDecisionInput struct {
+	OrderID      uuid.UUID
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+	ActorRole    string
+}
+
+// ApproveCancelRequest cancels an order whose buyer asked to cancel after the cancel window closed,
+// exactly as an in-window buyer cancel would, and tells the buyer.
+func (s *service) ApproveCancelRequest(ctx context.Context, input CancelRequestDecisionInput) error {
+	if err := validateCancelRequestDecision(input); err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, err := loadVendorCancelRequest(ctx, repo, input)
+		if err != nil {
+			return err
+		}
+
+		if err := s.cancelVendorOrder(ctx, tx, repo, order, input.ActorUserID, input.ActorStoreID, input.ActorRole, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		return s.emitCancelRequestNotification(ctx, tx, order, notificationCancelApproved, input.ActorUserID, input.ActorStoreID, input.ActorRole)
+	})
+}
+
+// DenyCancelRequest clears the buyer's pending cancel request, leaving the order to proceed, and
+// tells the buyer.
+func (s *service) DenyCancelRequest(ctx context.Context, input CancelRequestDecisionInput) error {
+	if err := validateCancelRequestDecision(input); err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, err := loadVendorCancelRequest(ctx, repo, input)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.UpdateVendorOrder(ctx, order.ID, map[string]any{"cancel_requested_at": nil}); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "clear cancel request")
+		}
+
+		return s.emitCancelRequestNotification(ctx, tx, order, notificationCancelDenied, input.ActorUserID, input.ActorStoreID, input.ActorRole)
+	})
+}
+
+func validateCancelRequestDecision(input CancelRequestDecisionInput) error {
+	if input.OrderID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.ActorUserID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+	}
+	if input.ActorStoreID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+	}
+	return nil
+}
+
+func loadVendorCancelRequest(ctx context.Context, repo Repository, input CancelRequestDecisionInput) (*models.VendorOrder, error) {
+	order, err := repo.FindVendorOrder(ctx, input.OrderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+	}
+	if order.VendorStoreID != input.ActorStoreID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
+	}
+	if order.CancelRequestedAt == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeStateConflict, "order has no pending cancel request")
+	}
+	if !isCancelableStatus(order.Status) {
+		return nil, pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be canceled in current state")
+	}
+	return order, nil
+}
+
+func (s *service) emitCancelRequestNotification(ctx context.Context, tx *gorm.DB, order *models.VendorOrder, notificationType string, actorUserID, actorStoreID uuid.UUID, actorRole string) error {
+	event := outbox.DomainEvent{
+		EventType:     enums.EventNotificationRequested,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   order.ID,
+		Version:       1,
+		Actor:         buildActor(actorUserID, actorStoreID, actorRole),
+		Data: payloads.NotificationRequestedEvent{
+			OrderID:         order.ID,
+			CheckoutGroupID: order.CheckoutGroupID,
+			BuyerStoreID:    order.BuyerStoreID,
+			VendorStoreID:   order.VendorStoreID,
+			OrderReference:  orderReference(order),
+			Type:            notificationType,
+		},
+	}
+	return s.outbox.Emit(ctx, tx, event)
+}
@@ -0,0 +1,181 @@
+package orders
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxCommentLength caps an order comment body, in characters.
+const maxCommentLength = 2000
+
+// CommentRole is the side of an order a comment is read or written from. The API layer picks it
+// from the route (store, agent, or admin) rather than the JWT role, since store members can also
+// hold an "admin" membership role.
+type CommentRole string
+
+const (
+	CommentRoleBuyer  CommentRole = "buyer"
+	CommentRoleVendor CommentRole = "vendor"
+	CommentRoleAgent  CommentRole = "agent"
+	CommentRoleAdmin  CommentRole = "admin"
+)
+
+// seesInternal reports whether the role may read and write internal comments. Buyers never do.
+func (r CommentRole) seesInternal() bool {
+	switch r {
+	case CommentRoleVendor, CommentRoleAgent, CommentRoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// AddCommentInput carries a new order-level comment.
+type AddCommentInput struct {
+	OrderID      uuid.UUID
+	Body         string
+	Visibility   enums.OrderCommentVisibility
+	Role         CommentRole
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+}
+
+// ListCommentsInput identifies the order and the reader whose visibility applies.
+type ListCommentsInput struct {
+	OrderID      uuid.UUID
+	Role         CommentRole
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+}
+
+// AddComment records a comment on the order after checking the author may access it. Buyers may
+// only post shared comments.
+func (s *service) AddComment(ctx context.Context, input AddCommentInput) (*OrderComment, error) {
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "comment body required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "comment body too long")
+	}
+	visibility := input.Visibility
+	if visibility == "" {
+		visibility = enums.OrderCommentVisibilityShared
+	}
+	if !visibility.IsValid() {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid comment visibility")
+	}
+	if visibility == enums.OrderCommentVisibilityInternal && !input.Role.seesInternal() {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "internal comments are not available to buyers")
+	}
+	if err := s.authorizeCommentAccess(ctx, input.OrderID, input.Role, input.ActorUserID, input.ActorStoreID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.OrderComment{
+		ID:           uuid.New(),
+		OrderID:      input.OrderID,
+		AuthorUserID: input.ActorUserID,
+		AuthorRole:   string(input.Role),
+		Visibility:   visibility,
+		Body:         body,
+	}
+	if input.ActorStoreID != uuid.Nil {
+		storeID := input.ActorStoreID
+		comment.AuthorStoreID = &storeID
+	}
+	if err := s.repo.CreateOrderComment(ctx, comment); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create order comment")
+	}
+	result := buildOrderComment(*comment)
+	return &result, nil
+}
+
+// ListComments returns the order's comments oldest first, leaving out internal comments for buyers.
+func (s *service) ListComments(ctx context.Context, input ListCommentsInput) ([]OrderComment, error) {
+	if err := s.authorizeCommentAccess(ctx, input.OrderID, input.Role, input.ActorUserID, input.ActorStoreID); err != nil {
+		return nil, err
+	}
+	records, err := s.repo.ListOrderComments(ctx, input.OrderID, input.Role.seesInternal())
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list order comments")
+	}
+	comments := make([]OrderComment, 0, len(records))
+	for _, record := range records {
+		comments = append(comments, buildOrderComment(record))
+	}
+	return comments, nil
+}
+
+// authorizeCommentAccess checks the actor is a party to the order: the buyer or vendor store, the
+// agent holding the active assignment, or an admin.
+func (s *service) authorizeCommentAccess(ctx context.Context, orderID uuid.UUID, role CommentRole, actorUserID, actorStoreID uuid.UUID) error {
+	if orderID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if actorUserID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+	}
+
+	switch role {
+	case CommentRoleBuyer, CommentRoleVendor:
+		if actorStoreID == uuid.Nil {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+		}
+		order, err := s.repo.FindVendorOrder(ctx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+		}
+		owner := order.BuyerStoreID
+		if role == CommentRoleVendor {
+			owner = order.VendorStoreID
+		}
+		if owner != actorStoreID {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
+		}
+	case CommentRoleAgent:
+		detail, err := s.repo.FindOrderDetail(ctx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load order detail")
+		}
+		if detail == nil || detail.ActiveAssignment == nil || detail.ActiveAssignment.AgentUserID != actorUserID {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "order not assigned to agent")
+		}
+	case CommentRoleAdmin:
+		if _, err := s.repo.FindVendorOrder(ctx, orderID); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+		}
+	default:
+		return pkgerrors.New(pkgerrors.CodeForbidden, "comment role required")
+	}
+	return nil
+}
+
+func buildOrderComment(record models.OrderComment) OrderComment {
+	return OrderComment{
+		ID:            record.ID,
+		OrderID:       record.OrderID,
+		AuthorUserID:  record.AuthorUserID,
+		AuthorStoreID: record.AuthorStoreID,
+		AuthorRole:    CommentRole(record.AuthorRole),
+		Visibility:    record.Visibility,
+		Body:          record.Body,
+		CreatedAt:     record.CreatedAt,
+	}
+}
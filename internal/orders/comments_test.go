@@ -0,0 +1,131 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+func newCommentsService(t *testing.T, order *models.VendorOrder) (Service, *stubOrdersRepo) {
+	t.Helper()
+	repo := &stubOrdersRepo{order: order}
+	svc, err := NewService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{}, newStubLedgerService(nil, nil))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	return svc, repo
+}
+
+func TestInternalCommentHiddenFromBuyerButVisibleToAdmin(t *testing.T) {
+	order := &models.VendorOrder{ID: uuid.New(), BuyerStoreID: uuid.New(), VendorStoreID: uuid.New()}
+	svc, _ := newCommentsService(t, order)
+	ctx := context.Background()
+
+	if _, err := svc.AddComment(ctx, AddCommentInput{
+		OrderID:      order.ID,
+		Body:         "Buyer paid late twice; collect cash before unloading.",
+		Visibility:   enums.OrderCommentVisibilityInternal,
+		Role:         CommentRoleVendor,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: order.VendorStoreID,
+	}); err != nil {
+		t.Fatalf("add internal comment: %v", err)
+	}
+	shared, err := svc.AddComment(ctx, AddCommentInput{
+		OrderID:      order.ID,
+		Body:         "  Delivery moved to Thursday.  ",
+		Role:         CommentRoleVendor,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: order.VendorStoreID,
+	})
+	if err != nil {
+		t.Fatalf("add shared comment: %v", err)
+	}
+	if shared.Visibility != enums.OrderCommentVisibilityShared || shared.Body != "Delivery moved to Thursday." {
+		t.Fatalf("expected trimmed shared comment by default, got %+v", shared)
+	}
+
+	buyerView, err := svc.ListComments(ctx, ListCommentsInput{
+		OrderID:      order.ID,
+		Role:         CommentRoleBuyer,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: order.BuyerStoreID,
+	})
+	if err != nil {
+		t.Fatalf("buyer list: %v", err)
+	}
+	if len(buyerView) != 1 || buyerView[0].ID != shared.ID {
+		t.Fatalf("expected buyer to see only the shared comment, got %+v", buyerView)
+	}
+
+	adminView, err := svc.ListComments(ctx, ListCommentsInput{
+		OrderID:     order.ID,
+		Role:        CommentRoleAdmin,
+		ActorUserID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("admin list: %v", err)
+	}
+	if len(adminView) != 2 {
+		t.Fatalf("expected admin to see both comments, got %d", len(adminView))
+	}
+	if adminView[0].Visibility != enums.OrderCommentVisibilityInternal || adminView[0].AuthorRole != CommentRoleVendor {
+		t.Fatalf("expected internal vendor comment first, got %+v", adminView[0])
+	}
+}
+
+func TestBuyerCannotPostInternalComment(t *testing.T) {
+	order := &models.VendorOrder{ID: uuid.New(), BuyerStoreID: uuid.New(), VendorStoreID: uuid.New()}
+	svc, repo := newCommentsService(t, order)
+
+	_, err := svc.AddComment(context.Background(), AddCommentInput{
+		OrderID:      order.ID,
+		Body:         "note to self",
+		Visibility:   enums.OrderCommentVisibilityInternal,
+		Role:         CommentRoleBuyer,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: order.BuyerStoreID,
+	})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if len(repo.comments) != 0 {
+		t.Fatalf("expected no comment stored, got %d", len(repo.comments))
+	}
+}
+
+func TestCommentAccessRequiresPartyToOrder(t *testing.T) {
+	order := &models.VendorOrder{ID: uuid.New(), BuyerStoreID: uuid.New(), VendorStoreID: uuid.New()}
+	svc, repo := newCommentsService(t, order)
+	ctx := context.Background()
+
+	_, err := svc.ListComments(ctx, ListCommentsInput{
+		OrderID:      order.ID,
+		Role:         CommentRoleVendor,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: uuid.New(),
+	})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeForbidden {
+		t.Fatalf("expected forbidden for foreign vendor, got %v", err)
+	}
+
+	agentID := uuid.New()
+	repo.findOrderDetail = func(ctx context.Context, orderID uuid.UUID) (*OrderDetail, error) {
+		return &OrderDetail{ActiveAssignment: &OrderAssignmentSummary{AgentUserID: agentID}}, nil
+	}
+	if _, err := svc.ListComments(ctx, ListCommentsInput{OrderID: order.ID, Role: CommentRoleAgent, ActorUserID: uuid.New()}); err == nil {
+		t.Fatal("expected unassigned agent to be rejected")
+	}
+	if _, err := svc.ListComments(ctx, ListCommentsInput{OrderID: order.ID, Role: CommentRoleAgent, ActorUserID: agentID}); err != nil {
+		t.Fatalf("expected assigned agent to list comments, got %v", err)
+	}
+
+	_, err = svc.AddComment(ctx, AddCommentInput{OrderID: order.ID, Body: "   ", Role: CommentRoleAdmin, ActorUserID: uuid.New()})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error for empty body, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
@@ -12,31 +13,46 @@ import (
 
 // BuyerOrderFilters describe the inputs supported by the buyer orders list.
 type BuyerOrderFilters struct {
-	OrderStatus       *enums.VendorOrderStatus
+	// OrderStatuses keeps orders whose status is any of the listed values.
+	OrderStatuses     []enums.VendorOrderStatus
 	FulfillmentStatus *enums.VendorOrderFulfillmentStatus
 	ShippingStatus    *enums.VendorOrderShippingStatus
 	PaymentStatus     *enums.PaymentStatus
 	DateFrom          *time.Time
 	DateTo            *time.Time
-	Query             string
+	// Query matches the order number or reference, either store's name, or a line item name.
+	Query string
 }
 
 // VendorOrderFilters describe the inputs supported by the vendor orders list.
 type VendorOrderFilters struct {
-	OrderStatus        *enums.VendorOrderStatus
+	// OrderStatuses keeps orders whose status is any of the listed values.
+	OrderStatuses      []enums.VendorOrderStatus
 	FulfillmentStatus  *enums.VendorOrderFulfillmentStatus
 	ShippingStatus     *enums.VendorOrderShippingStatus
 	PaymentStatus      *enums.PaymentStatus
 	DateFrom           *time.Time
 	DateTo             *time.Time
 	ActionableStatuses []enums.VendorOrderStatus
-	Query              string
+	// Query matches the order number or reference, either store's name, or a line item name.
+	Query string
+}
+
+// AgentQueueFilters narrow the unassigned dispatch queue. When Near is set the queue is
+// ordered by distance from that point to the vendor (pickup) address instead of recency.
+type AgentQueueFilters struct {
+	VendorState   string
+	MinTotalCents *int
+	MaxTotalCents *int
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	Near          *maps.LatLng
 }
 
 // OrderPagination captures the pagination metadata shared across listing responses.
 type OrderPagination struct {
 	Page    int    `json:"page"`
-	Total   int    `json:"total"`
+	Total   *int   `json:"total,omitempty"`
 	Current string `json:"current,omitempty"`
 	First   string `json:"first,omitempty"`
 	Last    string `json:"last,omitempty"`
@@ -64,6 +80,7 @@ type OrderStoreSummary struct {
 type BuyerOrderSummary struct {
 	ID                uuid.UUID                          `json:"id"`
 	OrderNumber       int64                              `json:"order_number"`
+	OrderReference    *string                            `json:"order_reference,omitempty"`
 	CreatedAt         time.Time                          `json:"created_at"`
 	TotalCents        int                                `json:"total_cents"`
 	DiscountsCents    int                                `json:"discount_cents"`
@@ -83,27 +100,40 @@ type BuyerOrderListResult struct {
 
 // VendorOrderSummary exposes aggregated fields returned in the vendor list.
 type VendorOrderSummary struct {
-	ID                uuid.UUID                          `json:"id"`
-	Status            enums.VendorOrderStatus            `json:"status"`
-	OrderNumber       int64                              `json:"order_number"`
-	CreatedAt         time.Time                          `json:"created_at"`
-	TotalCents        int                                `json:"total_cents"`
-	DiscountsCents    int                                `json:"discount_cents"`
-	TotalItems        int                                `json:"total_items"`
-	OrderStatus       enums.VendorOrderStatus            `json:"order_status"`
-	PaymentStatus     enums.PaymentStatus                `json:"payment_status"`
-	FulfillmentStatus enums.VendorOrderFulfillmentStatus `json:"fulfillment_status"`
-	ShippingStatus    enums.VendorOrderShippingStatus    `json:"shipping_status"`
-	Buyer             OrderStoreSummary                  `json:"buyer"`
-	DeliveredAt       *time.Time                         `json:"delivered_at,omitempty"`
-	Assignments       *[]models.OrderAssignment          `json:"assignments,omitempty"`
-	ShippingLine      *types.ShippingLine                `json:"shipping,omitempty"`
+	ID                      uuid.UUID                          `json:"id"`
+	Status                  enums.VendorOrderStatus            `json:"status"`
+	OrderNumber             int64                              `json:"order_number"`
+	OrderReference          *string                            `json:"order_reference,omitempty"`
+	CreatedAt               time.Time                          `json:"created_at"`
+	TotalCents              int                                `json:"total_cents"`
+	DiscountsCents          int                                `json:"discount_cents"`
+	Currency                enums.Currency                     `json:"currency"`
+	TotalItems              int                                `json:"total_items"`
+	OrderStatus             enums.VendorOrderStatus            `json:"order_status"`
+	PaymentStatus           enums.PaymentStatus                `json:"payment_status"`
+	FulfillmentStatus       enums.VendorOrderFulfillmentStatus `json:"fulfillment_status"`
+	ShippingStatus          enums.VendorOrderShippingStatus    `json:"shipping_status"`
+	Buyer                   OrderStoreSummary                  `json:"buyer"`
+	DeliveredAt             *time.Time                         `json:"delivered_at,omitempty"`
+	OpensAt                 *time.Time                         `json:"opens_at,omitempty"`
+	Assignments             *[]models.OrderAssignment          `json:"assignments,omitempty"`
+	ShippingLine            *types.ShippingLine                `json:"shipping,omitempty"`
+	DeliveryDistanceMeters  *int                               `json:"delivery_distance_meters,omitempty"`
+	DeliveryDurationSeconds *int                               `json:"delivery_duration_seconds,omitempty"`
+	PickupWindow            *PickupWindow                      `json:"pickup_window,omitempty"`
+}
+
+// PickupWindow is when the vendor expects the order to be ready for an agent to collect.
+type PickupWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
 // AgentOrderQueueSummary describes the orders exposed to agents on the dispatch queue.
 type AgentOrderQueueSummary struct {
 	OrderID           uuid.UUID                          `json:"order_id"`
 	OrderNumber       int64                              `json:"order_number"`
+	OrderReference    *string                            `json:"order_reference,omitempty"`
 	CreatedAt         time.Time                          `json:"created_at"`
 	TotalCents        int                                `json:"total_cents"`
 	DiscountsCents    int                                `json:"discount_cents"`
@@ -113,21 +143,29 @@ type AgentOrderQueueSummary struct {
 	ShippingStatus    enums.VendorOrderShippingStatus    `json:"shipping_status"`
 	Buyer             OrderStoreSummary                  `json:"buyer"`
 	Vendor            OrderStoreSummary                  `json:"vendor"`
+	// PickupWindow is set when the vendor scheduled a ready time; agents should not arrive before it.
+	PickupWindow *PickupWindow `json:"pickup_window,omitempty"`
+	// DistanceMeters is the straight-line distance to the vendor when the queue is geo-sorted.
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
 }
 
 // AgentOrderQueueList wraps paginated dispatch queue rows.
 type AgentOrderQueueList struct {
 	Orders     []AgentOrderQueueSummary `json:"orders"`
 	NextCursor string                   `json:"next_cursor,omitempty"`
+	// Truncated is set on a location-sorted queue when more orders matched than were ranked;
+	// only the oldest ones were considered, so closer orders may be missing.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // PayoutOrderSummary exposes payout-eligible orders to admins.
 type PayoutOrderSummary struct {
-	OrderID       uuid.UUID `json:"order_id"`
-	VendorStoreID uuid.UUID `json:"vendor_store_id"`
-	OrderNumber   int64     `json:"order_number"`
-	AmountCents   int       `json:"amount_cents"`
-	DeliveredAt   time.Time `json:"delivered_at"`
+	OrderID        uuid.UUID `json:"order_id"`
+	VendorStoreID  uuid.UUID `json:"vendor_store_id"`
+	OrderNumber    int64     `json:"order_number"`
+	OrderReference *string   `json:"order_reference,omitempty"`
+	AmountCents    int       `json:"amount_cents"`
+	DeliveredAt    time.Time `json:"delivered_at"`
 }
 
 // PayoutOrderList wraps paginated payout summaries.
@@ -187,6 +225,15 @@ type LineItemDetail struct {
 	TotalCents     int       `json:"total_cents"`
 	Status         string    `json:"status"`
 	Notes          *string   `json:"notes,omitempty"`
+	// Substitution is the vendor's open offer while the line is pending_substitution.
+	Substitution *LineItemSubstitution `json:"substitution,omitempty"`
+}
+
+// LineItemSubstitution describes a substitute the vendor offered in place of the ordered product.
+type LineItemSubstitution struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	Quantity       int       `json:"quantity"`
+	UnitPriceCents int       `json:"unit_price_cents"`
 }
 
 // PaymentIntentDetail surfaces the payment intent fields needed on detail responses.
@@ -209,3 +256,15 @@ type OrderDetail struct {
 	VendorStore      OrderStoreSummary       `json:"vendor_store"`
 	ActiveAssignment *OrderAssignmentSummary `json:"active_assignment,omitempty"`
 }
+
+// OrderComment is an order-level note as returned to a reader allowed to see it.
+type OrderComment struct {
+	ID            uuid.UUID                    `json:"id"`
+	OrderID       uuid.UUID                    `json:"order_id"`
+	AuthorUserID  uuid.UUID                    `json:"author_user_id"`
+	AuthorStoreID *uuid.UUID                   `json:"author_store_id,omitempty"`
+	AuthorRole    CommentRole                  `json:"author_role"`
+	Visibility    enums.OrderCommentVisibility `json:"visibility"`
+	Body          string                       `json:"body"`
+	CreatedAt     time.Time                    `json:"created_at"`
+}
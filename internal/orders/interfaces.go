@@ -25,16 +25,23 @@ type Repository interface {
 	ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID, input ListOrdersInput, filters BuyerOrderFilters) (*BuyerOrderListResult, error)
 	ListVendorOrders(ctx context.Context, vendorStoreID uuid.UUID, input ListOrdersInput, filters VendorOrderFilters) (*VendorOrderListResult, error)
 	ListOrdersBetweenStores(ctx context.Context, vendorStoreID, buyerStoreID uuid.UUID) ([]VendorOrderSummary, error)
-	ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*AgentOrderQueueList, error)
+	ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters AgentQueueFilters) (*AgentOrderQueueList, error)
 	ListAssignedOrders(ctx context.Context, agentID uuid.UUID, params pagination.Params) (*AgentOrderQueueList, error)
 	ListPayoutOrders(ctx context.Context, params pagination.Params) (*PayoutOrderList, error)
+	StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(AccountingExportLine) error) error
 	FindOrderDetail(ctx context.Context, orderID uuid.UUID) (*OrderDetail, error)
 	FindPendingOrdersBefore(ctx context.Context, cutoff time.Time) ([]models.VendorOrder, error)
 	FindVendorOrder(ctx context.Context, orderID uuid.UUID) (*models.VendorOrder, error)
 	UpdateVendorOrderStatus(ctx context.Context, orderID uuid.UUID, status enums.VendorOrderStatus) error
+	MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error)
 	UpdateOrderLineItemStatus(ctx context.Context, lineItemID uuid.UUID, status enums.LineItemStatus, notes *string) error
+	UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error
 	UpdateVendorOrder(ctx context.Context, orderID uuid.UUID, updates map[string]any) error
 	UpdatePaymentIntent(ctx context.Context, orderID uuid.UUID, updates map[string]any) error
 	UpdateOrderAssignment(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error
+	CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error)
+	FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error)
 	HasBuyerStorePurchasedFromVendor(ctx context.Context, buyerStoreID, vendorStoreID uuid.UUID) (bool, error)
+	CreateOrderComment(ctx context.Context, comment *models.OrderComment) error
+	ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error)
 }
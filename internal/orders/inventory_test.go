@@ -0,0 +1,124 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupInventoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.InventoryItem{}))
+	require.NoError(t, db.Exec(`ALTER TABLE inventory_items ADD COLUMN low_stock_alerted_at DATETIME`).Error)
+	require.NoError(t, db.Exec(`ALTER TABLE inventory_items ADD COLUMN low_stock_alert_threshold INTEGER`).Error)
+	require.NoError(t, db.Exec(`
+CREATE TABLE inventory_adjustments (
+  id TEXT PRIMARY KEY,
+  product_id TEXT NOT NULL,
+  available_delta INTEGER NOT NULL,
+  reserved_delta INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  actor_user_id TEXT,
+  actor_store_id TEXT,
+  created_at DATETIME
+)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE products (id TEXT PRIMARY KEY, store_id TEXT NOT NULL)`).Error)
+	require.NoError(t, db.Exec(`
+CREATE TABLE outbox_events (
+  id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  event_type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at DATETIME,
+  published_at DATETIME,
+  attempt_count INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  dedup_key TEXT
+)`).Error)
+	return db
+}
+
+func TestInventoryReleaserRecordsAdjustment(t *testing.T) {
+	db := setupInventoryTestDB(t)
+	ctx := context.Background()
+	productID := uuid.New()
+	vendorUser := uuid.New()
+	vendorStore := uuid.New()
+	require.NoError(t, db.Create(&models.InventoryItem{ProductID: productID, AvailableQty: 4, ReservedQty: 3}).Error)
+
+	releaser := NewInventoryReleaser(outbox.NewService(outbox.NewRepository(db), nil))
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return releaser.Release(ctx, tx, productID, 3, inventory.NewAdjustmentActor(vendorUser, vendorStore))
+	})
+	require.NoError(t, err)
+
+	var inv models.InventoryItem
+	require.NoError(t, db.First(&inv, "product_id = ?", productID).Error)
+	require.Equal(t, 7, inv.AvailableQty)
+	require.Equal(t, 0, inv.ReservedQty)
+
+	var adjustments []models.InventoryAdjustment
+	require.NoError(t, db.Where("product_id = ?", productID).Find(&adjustments).Error)
+	require.Len(t, adjustments, 1)
+	require.Equal(t, 3, adjustments[0].AvailableDelta)
+	require.Equal(t, -3, adjustments[0].ReservedDelta)
+	require.Equal(t, enums.InventoryAdjustmentReasonOrderRelease, adjustments[0].Reason)
+	require.NotNil(t, adjustments[0].ActorUserID)
+	require.Equal(t, vendorUser, *adjustments[0].ActorUserID)
+	require.NotNil(t, adjustments[0].ActorStoreID)
+	require.Equal(t, vendorStore, *adjustments[0].ActorStoreID)
+}
+
+func TestInventoryReleaserRearmsLowStockAlert(t *testing.T) {
+	db := setupInventoryTestDB(t)
+	ctx := context.Background()
+	restocked := uuid.New()
+	stillLow := uuid.New()
+	require.NoError(t, db.Create(&models.InventoryItem{ProductID: restocked, AvailableQty: 2, ReservedQty: 3}).Error)
+	require.NoError(t, db.Create(&models.InventoryItem{ProductID: stillLow, AvailableQty: 1, ReservedQty: 1}).Error)
+	require.NoError(t, db.Exec(`UPDATE inventory_items SET low_stock_alerted_at = CURRENT_TIMESTAMP, low_stock_alert_threshold = 5`).Error)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := NewInventoryReleaser(outbox.NewService(outbox.NewRepository(db), nil)).Release(ctx, tx, restocked, 3, inventory.AdjustmentActor{}); err != nil {
+			return err
+		}
+		return NewInventoryReleaser(outbox.NewService(outbox.NewRepository(db), nil)).Release(ctx, tx, stillLow, 1, inventory.AdjustmentActor{})
+	})
+	require.NoError(t, err)
+
+	var rearmed int64
+	require.NoError(t, db.Table("inventory_items").Where("product_id = ? AND low_stock_alerted_at IS NULL", restocked).Count(&rearmed).Error)
+	require.Equal(t, int64(1), rearmed)
+
+	var alerted int64
+	require.NoError(t, db.Table("inventory_items").Where("product_id = ? AND low_stock_alerted_at IS NOT NULL", stillLow).Count(&alerted).Error)
+	require.Equal(t, int64(1), alerted)
+}
+
+func TestInventoryReleaserSkipsAdjustmentWhenNothingReleased(t *testing.T) {
+	db := setupInventoryTestDB(t)
+	ctx := context.Background()
+	productID := uuid.New()
+	require.NoError(t, db.Create(&models.InventoryItem{ProductID: productID, AvailableQty: 4, ReservedQty: 1}).Error)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return NewInventoryReleaser(outbox.NewService(outbox.NewRepository(db), nil)).Release(ctx, tx, productID, 2, inventory.AdjustmentActor{})
+	})
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&models.InventoryAdjustment{}).Where("product_id = ?", productID).Count(&count).Error)
+	require.Zero(t, count)
+}
@@ -0,0 +1,49 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+)
+
+// orderReferencePattern matches references produced by FormatOrderReference.
+var orderReferencePattern = regexp.MustCompile(`^\d{4}-\d{6,}$`)
+
+// FormatOrderReference renders the buyer-facing reference for the seq-th order of a year,
+// e.g. 2024-000123. Sequences past 999999 keep every digit rather than wrapping.
+func FormatOrderReference(year int, seq int64) string {
+	return fmt.Sprintf("%d-%06d", year, seq)
+}
+
+// IsOrderReference reports whether value is shaped like an order reference.
+func IsOrderReference(value string) bool {
+	return orderReferencePattern.MatchString(value)
+}
+
+// nextOrderReference bumps the per-year counter and returns the formatted reference. The
+// upsert runs on the root connection, outside any checkout transaction, so concurrent
+// checkouts only contend on the counter row for the length of the increment. A reference
+// claimed by a transaction that later rolls back is never reused, which leaves a gap.
+func (r *repository) nextOrderReference(ctx context.Context, at time.Time) (string, error) {
+	year := at.UTC().Year()
+	var seq int64
+	err := r.root.WithContext(ctx).Raw(`INSERT INTO order_reference_sequences (year, last_value)
+		VALUES (?, 1)
+		ON CONFLICT (year) DO UPDATE SET last_value = order_reference_sequences.last_value + 1
+		RETURNING last_value`, year).Scan(&seq).Error
+	if err != nil {
+		return "", err
+	}
+	return FormatOrderReference(year, seq), nil
+}
+
+// orderReference returns the order's reference, or "" for orders created before references existed.
+func orderReference(order *models.VendorOrder) string {
+	if order == nil || order.OrderReference == nil {
+		return ""
+	}
+	return *order.OrderReference
+}
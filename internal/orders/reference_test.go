@@ -0,0 +1,142 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatOrderReference(t *testing.T) {
+	assert.Equal(t, "2024-000123", FormatOrderReference(2024, 123))
+	assert.Equal(t, "2025-000001", FormatOrderReference(2025, 1))
+	assert.Equal(t, "2024-1234567", FormatOrderReference(2024, 1234567))
+
+	assert.True(t, IsOrderReference("2024-000123"))
+	assert.True(t, IsOrderReference("2024-1234567"))
+	assert.False(t, IsOrderReference("2024-123"))
+	assert.False(t, IsOrderReference("1202"))
+	assert.False(t, IsOrderReference("24-000123"))
+}
+
+func newReferenceTestOrder(buyer, vendor *models.Store) *models.VendorOrder {
+	order := &models.VendorOrder{
+		CartID:            uuid.New(),
+		CheckoutGroupID:   uuid.New(),
+		BuyerStoreID:      buyer.ID,
+		VendorStoreID:     vendor.ID,
+		Currency:          enums.CurrencyUSD,
+		Status:            enums.VendorOrderStatusCreatedPending,
+		RefundStatus:      enums.RefundStatusNone,
+		PaymentMethod:     enums.PaymentMethodCash,
+		FulfillmentStatus: enums.VendorOrderFulfillmentStatusPending,
+		ShippingStatus:    enums.VendorOrderShippingStatusPending,
+		SubtotalCents:     1000,
+		TotalCents:        1000,
+		BalanceDueCents:   1000,
+	}
+	order.ID = uuid.New()
+	return order
+}
+
+func TestCreateVendorOrderAssignsSequentialReferences(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	buyer := newStore(t, db, "Reference Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Reference Vendor", enums.StoreTypeVendor)
+	year := time.Now().UTC().Year()
+
+	first, err := repo.CreateVendorOrder(ctx, newReferenceTestOrder(buyer, vendor))
+	require.NoError(t, err)
+	second, err := repo.CreateVendorOrder(ctx, newReferenceTestOrder(buyer, vendor))
+	require.NoError(t, err)
+	require.NotNil(t, first.OrderReference)
+	require.NotNil(t, second.OrderReference)
+	assert.Equal(t, FormatOrderReference(year, 1), *first.OrderReference)
+	assert.Equal(t, FormatOrderReference(year, 2), *second.OrderReference)
+
+	// A failed insert burns its reference; the next order skips it rather than reusing it.
+	duplicate := newReferenceTestOrder(buyer, vendor)
+	duplicate.ID = second.ID
+	_, err = repo.CreateVendorOrder(ctx, duplicate)
+	require.Error(t, err)
+
+	third, err := repo.CreateVendorOrder(ctx, newReferenceTestOrder(buyer, vendor))
+	require.NoError(t, err)
+	require.NotNil(t, third.OrderReference)
+	assert.Equal(t, FormatOrderReference(year, 4), *third.OrderReference)
+
+	var stored models.VendorOrder
+	require.NoError(t, db.First(&stored, "id = ?", third.ID.String()).Error)
+	require.NotNil(t, stored.OrderReference)
+	assert.Equal(t, *third.OrderReference, *stored.OrderReference)
+}
+
+func TestRepositoryListOrders_searchByReference(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+	buyer := newStore(t, db, "Reference Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Reference Vendor", enums.StoreTypeVendor)
+
+	now := time.Now().UTC()
+	first := createOrder(t, db, buyer, vendor, 1301, now.Add(-time.Hour), 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	second := createOrder(t, db, buyer, vendor, 1302, now, 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	require.NoError(t, db.Model(&models.VendorOrder{}).Where("id = ?", first.ID).Update("order_reference", "2024-000001").Error)
+	require.NoError(t, db.Model(&models.VendorOrder{}).Where("id = ?", second.ID).Update("order_reference", "2024-000002").Error)
+
+	input := ListOrdersInput{Pagination: pagination.Params{Limit: 10}, Page: 1}
+	list, err := repo.ListBuyerOrders(context.Background(), buyer.ID, input, BuyerOrderFilters{Query: "#2024-000002"})
+	require.NoError(t, err)
+	require.Len(t, list.Orders, 1)
+	assert.Equal(t, second.ID, list.Orders[0].ID)
+	require.NotNil(t, list.Orders[0].OrderReference)
+	assert.Equal(t, "2024-000002", *list.Orders[0].OrderReference)
+}
+
+func TestNextOrderReferenceUniqueUnderConcurrency(t *testing.T) {
+	db := setupConcurrentOrdersTestDB(t)
+	repo := NewRepository(db).(*repository)
+	at := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	const workers = 20
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[string]struct{}, workers)
+		errs []error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reference, err := repo.nextOrderReference(context.Background(), at)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			seen[reference] = struct{}{}
+		}()
+	}
+	wg.Wait()
+
+	require.Empty(t, errs)
+	require.Len(t, seen, workers)
+	for i := 1; i <= workers; i++ {
+		assert.Contains(t, seen, fmt.Sprintf("2024-%06d", i))
+	}
+
+	next, err := repo.nextOrderReference(context.Background(), at.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-000001", next)
+}
@@ -2,18 +2,24 @@ package orders
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type repository struct {
 	db *gorm.DB
+	// root stays bound to the base connection when the repository is scoped to a transaction,
+	// so order references are allocated outside it.
+	root *gorm.DB
 }
 
 // var completedVendorOrderStatuses = []enums.VendorOrderStatus{
@@ -29,17 +35,24 @@ type repository struct {
 
 // NewRepository builds an orders repository bound to the provided DB.
 func NewRepository(db *gorm.DB) Repository {
-	return &repository{db: db}
+	return &repository{db: db, root: db}
 }
 
 func (r *repository) WithTx(tx *gorm.DB) Repository {
 	if tx == nil {
 		return r
 	}
-	return &repository{db: tx}
+	return &repository{db: tx, root: r.root}
 }
 
 func (r *repository) CreateVendorOrder(ctx context.Context, order *models.VendorOrder) (*models.VendorOrder, error) {
+	if order.OrderReference == nil {
+		reference, err := r.nextOrderReference(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		order.OrderReference = &reference
+	}
 	if err := r.db.WithContext(ctx).Create(order).Error; err != nil {
 		return nil, err
 	}
@@ -163,9 +176,34 @@ func (r *repository) UpdateVendorOrderStatus(ctx context.Context, orderID uuid.U
 	return r.db.WithContext(ctx).
 		Model(&models.VendorOrder{}).
 		Where("id = ?", orderID).
-		Updates(map[string]any{
+		Updates(stampAcceptedAt(map[string]any{
 			"status": status,
-		}).Error
+		})).Error
+}
+
+// stampAcceptedAt sets accepted_at the first time an update moves an order out of pending, whether
+// the vendor accepted it or line item decisions took it straight to dispatch. The buyer cancel
+// window runs from that moment and treats a missing stamp as closed.
+func stampAcceptedAt(updates map[string]any) map[string]any {
+	status, ok := updates["status"].(enums.VendorOrderStatus)
+	if !ok || status == enums.VendorOrderStatusCreatedPending {
+		return updates
+	}
+	updates["accepted_at"] = gorm.Expr("COALESCE(accepted_at, ?)", time.Now().UTC())
+	return updates
+}
+
+// MarkReservationReleased records that a pending order's reserved stock was handed back. It matches
+// at most once per order, so callers release stock only when it reports true.
+func (r *repository) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.VendorOrder{}).
+		Where("id = ? AND status = ? AND reservation_released_at IS NULL", orderID, enums.VendorOrderStatusCreatedPending).
+		Update("reservation_released_at", at)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
 }
 
 func (r *repository) UpdateOrderLineItemStatus(ctx context.Context, lineItemID uuid.UUID, status enums.LineItemStatus, notes *string) error {
@@ -181,6 +219,25 @@ func (r *repository) UpdateOrderLineItemStatus(ctx context.Context, lineItemID u
 		Updates(updates).Error
 }
 
+func (r *repository) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.OrderLineItem{}).
+		Where("id = ?", lineItemID).
+		Updates(updates).Error
+}
+
+// FindProduct loads a live (not soft-deleted) product, e.g. a vendor's proposed substitute.
+func (r *repository) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.WithContext(ctx).Scopes(db.NotDeleted("deleted_at")).First(&product, "id = ?", productID).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
 func (r *repository) UpdateVendorOrder(ctx context.Context, orderID uuid.UUID, updates map[string]any) error {
 	if len(updates) == 0 {
 		return nil
@@ -188,7 +245,7 @@ func (r *repository) UpdateVendorOrder(ctx context.Context, orderID uuid.UUID, u
 	return r.db.WithContext(ctx).
 		Model(&models.VendorOrder{}).
 		Where("id = ?", orderID).
-		Updates(updates).Error
+		Updates(stampAcceptedAt(updates)).Error
 }
 
 func (r *repository) UpdatePaymentIntent(ctx context.Context, orderID uuid.UUID, updates map[string]any) error {
@@ -202,6 +259,7 @@ func (r *repository) UpdatePaymentIntent(ctx context.Context, orderID uuid.UUID,
 }
 
 func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID, input ListOrdersInput, filters BuyerOrderFilters) (*BuyerOrderListResult, error) {
+	ctx = db.ReadOnly(ctx)
 	params := input.Pagination
 	pageSize := pagination.NormalizeLimit(params.Limit)
 	limitWithBuffer := pagination.LimitWithBuffer(params.Limit)
@@ -219,6 +277,7 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 	qb = qb.Select(`vo.id,
 		vo.created_at,
 		vo.order_number,
+		vo.order_reference,
 		vo.total_cents,
 		vo.discounts_cents,
 		vo.status AS order_status,
@@ -232,7 +291,8 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 		(SELECT COALESCE(SUM(qty), 0) FROM order_line_items WHERE order_id = vo.id) AS total_items`)
 
 	if cursor != nil {
-		qb = qb.Where("(vo.created_at < ?) OR (vo.created_at = ? AND vo.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		clause, args := cursor.Before("vo")
+		qb = qb.Where(clause, args...)
 	}
 
 	qb = qb.Order("vo.created_at DESC").Order("vo.id DESC").Limit(limitWithBuffer)
@@ -256,6 +316,7 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 			ID:                record.ID,
 			CreatedAt:         record.CreatedAt,
 			OrderNumber:       record.OrderNumber,
+			OrderReference:    record.OrderReference,
 			TotalCents:        record.TotalCents,
 			DiscountsCents:    record.DiscountsCents,
 			TotalItems:        record.TotalItems,
@@ -272,10 +333,8 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 		})
 	}
 
-	countQB := r.buyerOrderBaseQuery(ctx, buyerStoreID)
-	countQB = applyBuyerOrderFilters(countQB, filters)
-	var totalCount int64
-	if err := countQB.Count(&totalCount).Error; err != nil {
+	total, err := countOrdersIfRequested(params, applyBuyerOrderFilters(r.buyerOrderBaseQuery(ctx, buyerStoreID), filters))
+	if err != nil {
 		return nil, err
 	}
 
@@ -306,7 +365,7 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 		Orders: orders,
 		Pagination: OrderPagination{
 			Page:    page,
-			Total:   int(totalCount),
+			Total:   total,
 			Current: currentCursor,
 			First:   firstCursor,
 			Last:    lastCursor,
@@ -317,6 +376,7 @@ func (r *repository) ListBuyerOrders(ctx context.Context, buyerStoreID uuid.UUID
 }
 
 func (r *repository) ListVendorOrders(ctx context.Context, vendorStoreID uuid.UUID, input ListOrdersInput, filters VendorOrderFilters) (*VendorOrderListResult, error) {
+	ctx = db.ReadOnly(ctx)
 	params := input.Pagination
 	pageSize := pagination.NormalizeLimit(params.Limit)
 	limitWithBuffer := pagination.LimitWithBuffer(params.Limit)
@@ -334,7 +394,8 @@ func (r *repository) ListVendorOrders(ctx context.Context, vendorStoreID uuid.UU
 	qb = r.applyVendorOrderSummarySelect(qb)
 
 	if cursor != nil {
-		qb = qb.Where("(vo.created_at < ?) OR (vo.created_at = ? AND vo.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		clause, args := cursor.Before("vo")
+		qb = qb.Where(clause, args...)
 	}
 
 	qb = qb.Order("vo.created_at DESC").Order("vo.id DESC").Limit(limitWithBuffer)
@@ -354,10 +415,8 @@ func (r *repository) ListVendorOrders(ctx context.Context, vendorStoreID uuid.UU
 
 	orders := vendorOrderRecordsToSummaries(resultRows)
 
-	countQB := r.vendorOrderBaseQuery(ctx, vendorStoreID)
-	countQB = applyVendorOrderFilters(countQB, filters)
-	var totalCount int64
-	if err := countQB.Count(&totalCount).Error; err != nil {
+	total, err := countOrdersIfRequested(params, applyVendorOrderFilters(r.vendorOrderBaseQuery(ctx, vendorStoreID), filters))
+	if err != nil {
 		return nil, err
 	}
 
@@ -388,7 +447,7 @@ func (r *repository) ListVendorOrders(ctx context.Context, vendorStoreID uuid.UU
 		Orders: orders,
 		Pagination: OrderPagination{
 			Page:    page,
-			Total:   int(totalCount),
+			Total:   total,
 			Current: currentCursor,
 			First:   firstCursor,
 			Last:    lastCursor,
@@ -398,6 +457,19 @@ func (r *repository) ListVendorOrders(ctx context.Context, vendorStoreID uuid.UU
 	}, nil
 }
 
+// countOrdersIfRequested runs the filtered COUNT(*) only when the caller opted into totals.
+func countOrdersIfRequested(params pagination.Params, qb *gorm.DB) (*int, error) {
+	if !params.IncludeTotal {
+		return nil, nil
+	}
+	var count int64
+	if err := qb.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	total := int(count)
+	return &total, nil
+}
+
 func (r *repository) ListOrdersBetweenStores(ctx context.Context, vendorStoreID, buyerStoreID uuid.UUID) ([]VendorOrderSummary, error) {
 	qb := r.vendorOrderSummaryQuery(ctx, vendorStoreID)
 	qb = qb.Where("vo.buyer_store_id = ?", buyerStoreID)
@@ -425,8 +497,8 @@ func (r *repository) buyerOrderSummaryQuery(ctx context.Context, buyerStoreID uu
 }
 
 func applyBuyerOrderFilters(q *gorm.DB, filters BuyerOrderFilters) *gorm.DB {
-	if filters.OrderStatus != nil {
-		q = q.Where("vo.status = ?", *filters.OrderStatus)
+	if len(filters.OrderStatuses) > 0 {
+		q = q.Where("vo.status IN ?", filters.OrderStatuses)
 	}
 	if filters.FulfillmentStatus != nil {
 		q = q.Where("vo.fulfillment_status = ?", *filters.FulfillmentStatus)
@@ -443,17 +515,7 @@ func applyBuyerOrderFilters(q *gorm.DB, filters BuyerOrderFilters) *gorm.DB {
 	if filters.DateTo != nil {
 		q = q.Where("vo.created_at <= ?", filters.DateTo)
 	}
-	if qStr := strings.TrimSpace(filters.Query); qStr != "" {
-		pattern := "%" + strings.ToLower(qStr) + "%"
-		q = q.Where(`(
-		CAST(vo.order_number AS TEXT) LIKE ? OR
-		LOWER(vs.company_name) LIKE ? OR
-		LOWER(COALESCE(vs.dba_name, '')) LIKE ? OR
-		LOWER(bs.company_name) LIKE ? OR
-		LOWER(COALESCE(bs.dba_name, '')) LIKE ?
-	)`, pattern, pattern, pattern, pattern, pattern)
-	}
-	return q
+	return applyOrderQuery(q, filters.Query)
 }
 
 func (r *repository) vendorOrderBaseQuery(ctx context.Context, vendorStoreID uuid.UUID) *gorm.DB {
@@ -472,6 +534,7 @@ func (r *repository) applyVendorOrderSummarySelect(q *gorm.DB) *gorm.DB {
 	return q.Select(`vo.id,
 		vo.created_at,
 		vo.order_number,
+		vo.order_reference,
 		vo.total_cents,
 		vo.discounts_cents,
 		vo.fulfillment_status,
@@ -486,8 +549,8 @@ func (r *repository) applyVendorOrderSummarySelect(q *gorm.DB) *gorm.DB {
 }
 
 func applyVendorOrderFilters(q *gorm.DB, filters VendorOrderFilters) *gorm.DB {
-	if filters.OrderStatus != nil {
-		q = q.Where("vo.status = ?", *filters.OrderStatus)
+	if len(filters.OrderStatuses) > 0 {
+		q = q.Where("vo.status IN ?", filters.OrderStatuses)
 	}
 	if filters.FulfillmentStatus != nil {
 		q = q.Where("vo.fulfillment_status = ?", *filters.FulfillmentStatus)
@@ -507,16 +570,37 @@ func applyVendorOrderFilters(q *gorm.DB, filters VendorOrderFilters) *gorm.DB {
 	if filters.DateTo != nil {
 		q = q.Where("vo.created_at <= ?", filters.DateTo)
 	}
-	if qs := strings.TrimSpace(filters.Query); qs != "" {
-		pattern := "%" + strings.ToLower(qs) + "%"
-		q = q.Where(`(
-			LOWER(vs.company_name) LIKE ? OR
-			LOWER(COALESCE(vs.dba_name, '')) LIKE ? OR
-			LOWER(bs.company_name) LIKE ? OR
-			LOWER(COALESCE(bs.dba_name, '')) LIKE ?
-		)`, pattern, pattern, pattern, pattern)
-	}
-	return q
+	return applyOrderQuery(q, filters.Query)
+}
+
+// applyOrderQuery matches a substring of the order number, either store's name, or a line item
+// name, or an exact order number or reference (with or without a leading #). LIKE wildcards in the
+// query are matched literally.
+func applyOrderQuery(q *gorm.DB, query string) *gorm.DB {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return q
+	}
+	pattern := "%" + escapeLike(strings.ToLower(query)) + "%"
+	clause := `CAST(vo.order_number AS TEXT) LIKE ? ESCAPE '\' OR
+		LOWER(vs.company_name) LIKE ? ESCAPE '\' OR
+		LOWER(COALESCE(vs.dba_name, '')) LIKE ? ESCAPE '\' OR
+		LOWER(bs.company_name) LIKE ? ESCAPE '\' OR
+		LOWER(COALESCE(bs.dba_name, '')) LIKE ? ESCAPE '\' OR
+		EXISTS (SELECT 1 FROM order_line_items oli WHERE oli.order_id = vo.id AND LOWER(oli.name) LIKE ? ESCAPE '\')`
+	args := []any{pattern, pattern, pattern, pattern, pattern, pattern}
+	if number, err := strconv.ParseInt(strings.TrimPrefix(query, "#"), 10, 64); err == nil {
+		clause = "vo.order_number = ? OR " + clause
+		args = append([]any{number}, args...)
+	} else if reference := strings.TrimPrefix(query, "#"); IsOrderReference(reference) {
+		clause = "vo.order_reference = ? OR " + clause
+		args = append([]any{reference}, args...)
+	}
+	return q.Where("("+clause+")", args...)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
 
 func (r *repository) fetchOrderBoundaryCursor(q *gorm.DB, asc bool) (string, error) {
@@ -558,10 +642,13 @@ func (r *repository) ListAssignedOrders(ctx context.Context, agentID uuid.UUID,
 		Select(`vo.id,
 			vo.created_at,
 			vo.order_number,
+			vo.order_reference,
 			vo.total_cents,
 			vo.discounts_cents,
 			vo.fulfillment_status,
 			vo.shipping_status,
+			vo.pickup_window_start,
+			vo.pickup_window_end,
 			pi.status AS payment_status,
 			bs.id AS buyer_store_id,
 			bs.company_name AS buyer_company_name,
@@ -579,7 +666,8 @@ func (r *repository) ListAssignedOrders(ctx context.Context, agentID uuid.UUID,
 		Where("oa.agent_user_id = ?", agentID)
 
 	if cursor != nil {
-		qb = qb.Where("(vo.created_at < ?) OR (vo.created_at = ? AND vo.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		clause, args := cursor.Before("vo")
+		qb = qb.Where(clause, args...)
 	}
 
 	qb = qb.Order("vo.created_at DESC").Order("vo.id DESC").Limit(limitWithBuffer)
@@ -599,29 +687,7 @@ func (r *repository) ListAssignedOrders(ctx context.Context, agentID uuid.UUID,
 
 	orders := make([]AgentOrderQueueSummary, 0, len(resultRows))
 	for _, record := range resultRows {
-		orders = append(orders, AgentOrderQueueSummary{
-			OrderID:           record.ID,
-			OrderNumber:       record.OrderNumber,
-			CreatedAt:         record.CreatedAt,
-			TotalCents:        record.TotalCents,
-			DiscountsCents:    record.DiscountsCents,
-			TotalItems:        record.TotalItems,
-			PaymentStatus:     record.PaymentStatus,
-			FulfillmentStatus: record.FulfillmentStatus,
-			ShippingStatus:    record.ShippingStatus,
-			Buyer: OrderStoreSummary{
-				ID:          record.BuyerStoreID,
-				CompanyName: record.BuyerCompanyName,
-				DBAName:     record.BuyerDBAName,
-				LogoURL:     record.BuyerLogoURL,
-			},
-			Vendor: OrderStoreSummary{
-				ID:          record.VendorStoreID,
-				CompanyName: record.VendorCompanyName,
-				DBAName:     record.VendorDBAName,
-				LogoURL:     record.VendorLogoURL,
-			},
-		})
+		orders = append(orders, record.summary())
 	}
 
 	return &AgentOrderQueueList{
@@ -630,7 +696,11 @@ func (r *repository) ListAssignedOrders(ctx context.Context, agentID uuid.UUID,
 	}, nil
 }
 
-func (r *repository) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*AgentOrderQueueList, error) {
+func (r *repository) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters AgentQueueFilters) (*AgentOrderQueueList, error) {
+	if filters.Near != nil {
+		return r.listUnassignedHoldOrdersByProximity(ctx, params, filters)
+	}
+
 	pageSize := pagination.NormalizeLimit(params.Limit)
 	limitWithBuffer := pagination.LimitWithBuffer(params.Limit)
 	if limitWithBuffer <= pageSize {
@@ -642,14 +712,90 @@ func (r *repository) ListUnassignedHoldOrders(ctx context.Context, params pagina
 		return nil, err
 	}
 
+	qb := r.unassignedHoldOrdersQuery(ctx, filters)
+
+	if cursor != nil {
+		clause, args := cursor.Before("vo")
+		qb = qb.Where(clause, args...)
+	}
+
+	qb = qb.Order("vo.created_at DESC").Order("vo.id DESC").Limit(limitWithBuffer)
+
+	var records []agentOrderQueueRecord
+	if err := qb.Scan(&records).Error; err != nil {
+		return nil, err
+	}
+
+	resultRows := records
+	nextCursor := ""
+	if len(records) > pageSize {
+		resultRows = records[:pageSize]
+		last := resultRows[len(resultRows)-1]
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	orders := make([]AgentOrderQueueSummary, 0, len(resultRows))
+	for _, record := range resultRows {
+		orders = append(orders, record.summary())
+	}
+
+	return &AgentOrderQueueList{
+		Orders:     orders,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// listUnassignedHoldOrdersByProximity ranks the filtered queue by distance from filters.Near
+// to the vendor address. Ranking happens in memory over at most maxProximityQueueCandidates
+// rows, so the cursor encodes the (distance, id) position rather than a timestamp, and the
+// list is marked Truncated when more orders matched than were ranked.
+func (r *repository) listUnassignedHoldOrdersByProximity(ctx context.Context, params pagination.Params, filters AgentQueueFilters) (*AgentOrderQueueList, error) {
+	pageSize := pagination.NormalizeLimit(params.Limit)
+
+	cursor, err := parseProximityCursor(params.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []agentOrderQueueRecord
+	if err := r.unassignedHoldOrdersQuery(ctx, filters).
+		Order("vo.created_at ASC").
+		Order("vo.id ASC").
+		Limit(maxProximityQueueCandidates + 1).
+		Scan(&records).Error; err != nil {
+		return nil, err
+	}
+	records, truncated := capProximityCandidates(records)
+
+	page, nextCursor := pageProximity(rankByProximity(*filters.Near, records), cursor, pageSize)
+
+	orders := make([]AgentOrderQueueSummary, 0, len(page))
+	for _, ranked := range page {
+		summary := ranked.record.summary()
+		distance := ranked.distanceMeters
+		summary.DistanceMeters = &distance
+		orders = append(orders, summary)
+	}
+
+	return &AgentOrderQueueList{
+		Orders:     orders,
+		NextCursor: nextCursor,
+		Truncated:  truncated,
+	}, nil
+}
+
+func (r *repository) unassignedHoldOrdersQuery(ctx context.Context, filters AgentQueueFilters) *gorm.DB {
 	qb := r.db.WithContext(ctx).Table("vendor_orders AS vo").
 		Select(`vo.id,
 			vo.created_at,
 			vo.order_number,
+			vo.order_reference,
 			vo.total_cents,
 			vo.discounts_cents,
 			vo.fulfillment_status,
 			vo.shipping_status,
+			vo.pickup_window_start,
+			vo.pickup_window_end,
 			pi.status AS payment_status,
 			bs.id AS buyer_store_id,
 			bs.company_name AS buyer_company_name,
@@ -659,6 +805,7 @@ func (r *repository) ListUnassignedHoldOrders(ctx context.Context, params pagina
 			vs.company_name AS vendor_company_name,
 			vs.dba_name AS vendor_dba_name,
 			vs.logo_url AS vendor_logo_url,
+			vs.address AS vendor_address,
 			(SELECT COALESCE(SUM(qty), 0) FROM order_line_items WHERE order_id = vo.id) AS total_items`).
 		Joins("JOIN payment_intents pi ON pi.order_id = vo.id").
 		Joins("JOIN stores bs ON bs.id = vo.buyer_store_id").
@@ -667,64 +814,31 @@ func (r *repository) ListUnassignedHoldOrders(ctx context.Context, params pagina
 		Where("vo.status = ?", enums.VendorOrderStatusReadyForDispatch).
 		Where("oa.order_id IS NULL")
 
-	if cursor != nil {
-		qb = qb.Where("(vo.created_at < ?) OR (vo.created_at = ? AND vo.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	if state := strings.TrimSpace(filters.VendorState); state != "" {
+		qb = qb.Where("LOWER((vs.address).state) = LOWER(?)", state)
 	}
-
-	qb = qb.Order("vo.created_at DESC").Order("vo.id DESC").Limit(limitWithBuffer)
-
-	var records []agentOrderQueueRecord
-	if err := qb.Scan(&records).Error; err != nil {
-		return nil, err
+	if filters.MinTotalCents != nil {
+		qb = qb.Where("vo.total_cents >= ?", *filters.MinTotalCents)
 	}
-
-	resultRows := records
-	nextCursor := ""
-	if len(records) > pageSize {
-		resultRows = records[:pageSize]
-		last := resultRows[len(resultRows)-1]
-		nextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	if filters.MaxTotalCents != nil {
+		qb = qb.Where("vo.total_cents <= ?", *filters.MaxTotalCents)
 	}
-
-	orders := make([]AgentOrderQueueSummary, 0, len(resultRows))
-	for _, record := range resultRows {
-		orders = append(orders, AgentOrderQueueSummary{
-			OrderID:           record.ID,
-			OrderNumber:       record.OrderNumber,
-			CreatedAt:         record.CreatedAt,
-			TotalCents:        record.TotalCents,
-			DiscountsCents:    record.DiscountsCents,
-			TotalItems:        record.TotalItems,
-			PaymentStatus:     record.PaymentStatus,
-			FulfillmentStatus: record.FulfillmentStatus,
-			ShippingStatus:    record.ShippingStatus,
-			Buyer: OrderStoreSummary{
-				ID:          record.BuyerStoreID,
-				CompanyName: record.BuyerCompanyName,
-				DBAName:     record.BuyerDBAName,
-				LogoURL:     record.BuyerLogoURL,
-			},
-			Vendor: OrderStoreSummary{
-				ID:          record.VendorStoreID,
-				CompanyName: record.VendorCompanyName,
-				DBAName:     record.VendorDBAName,
-				LogoURL:     record.VendorLogoURL,
-			},
-		})
+	if filters.CreatedBefore != nil {
+		qb = qb.Where("vo.created_at <= ?", *filters.CreatedBefore)
 	}
-
-	return &AgentOrderQueueList{
-		Orders:     orders,
-		NextCursor: nextCursor,
-	}, nil
+	if filters.CreatedAfter != nil {
+		qb = qb.Where("vo.created_at >= ?", *filters.CreatedAfter)
+	}
+	return qb
 }
 
 type payoutOrderRecord struct {
-	ID            uuid.UUID
-	OrderNumber   int64
-	VendorStoreID uuid.UUID
-	DeliveredAt   time.Time
-	AmountCents   int
+	ID             uuid.UUID
+	OrderNumber    int64
+	OrderReference *string
+	VendorStoreID  uuid.UUID
+	DeliveredAt    time.Time
+	AmountCents    int
 }
 
 func (r *repository) ListPayoutOrders(ctx context.Context, params pagination.Params) (*PayoutOrderList, error) {
@@ -736,7 +850,7 @@ func (r *repository) ListPayoutOrders(ctx context.Context, params pagination.Par
 
 	var records []payoutOrderRecord
 	qb := r.db.WithContext(ctx).Table("vendor_orders vo").
-		Select("vo.id, vo.order_number, vo.vendor_store_id, vo.delivered_at, pi.amount_cents").
+		Select("vo.id, vo.order_number, vo.order_reference, vo.vendor_store_id, vo.delivered_at, pi.amount_cents").
 		Joins("JOIN payment_intents pi ON pi.order_id = vo.id").
 		Where("vo.status = ?", enums.VendorOrderStatusDelivered).
 		Where("pi.status = ?", enums.PaymentStatusSettled)
@@ -764,11 +878,12 @@ func (r *repository) ListPayoutOrders(ctx context.Context, params pagination.Par
 	}
 	for _, rec := range records {
 		list.Orders = append(list.Orders, PayoutOrderSummary{
-			OrderID:       rec.ID,
-			VendorStoreID: rec.VendorStoreID,
-			OrderNumber:   rec.OrderNumber,
-			AmountCents:   rec.AmountCents,
-			DeliveredAt:   rec.DeliveredAt,
+			OrderID:        rec.ID,
+			VendorStoreID:  rec.VendorStoreID,
+			OrderNumber:    rec.OrderNumber,
+			OrderReference: rec.OrderReference,
+			AmountCents:    rec.AmountCents,
+			DeliveredAt:    rec.DeliveredAt,
 		})
 	}
 	list.NextCursor = nextCursor
@@ -843,6 +958,7 @@ type buyerOrderRecord struct {
 	ID                uuid.UUID
 	CreatedAt         time.Time
 	OrderNumber       int64
+	OrderReference    *string
 	TotalCents        int
 	DiscountsCents    int
 	OrderStatus       enums.VendorOrderStatus
@@ -860,6 +976,7 @@ type vendorOrderRecord struct {
 	ID                uuid.UUID
 	CreatedAt         time.Time
 	OrderNumber       int64
+	OrderReference    *string
 	TotalCents        int
 	DiscountsCents    int
 	OrderStatus       enums.VendorOrderStatus
@@ -881,6 +998,7 @@ func vendorOrderRecordsToSummaries(records []vendorOrderRecord) []VendorOrderSum
 			OrderStatus:       record.OrderStatus,
 			CreatedAt:         record.CreatedAt,
 			OrderNumber:       record.OrderNumber,
+			OrderReference:    record.OrderReference,
 			TotalCents:        record.TotalCents,
 			DiscountsCents:    record.DiscountsCents,
 			TotalItems:        record.TotalItems,
@@ -902,6 +1020,7 @@ type agentOrderQueueRecord struct {
 	ID                uuid.UUID
 	CreatedAt         time.Time
 	OrderNumber       int64
+	OrderReference    *string
 	TotalCents        int
 	DiscountsCents    int
 	FulfillmentStatus enums.VendorOrderFulfillmentStatus
@@ -915,7 +1034,38 @@ type agentOrderQueueRecord struct {
 	VendorCompanyName string
 	VendorDBAName     *string
 	VendorLogoURL     *string
+	VendorAddress     types.Address
 	TotalItems        int
+	PickupWindowStart *time.Time
+	PickupWindowEnd   *time.Time
+}
+
+func (record agentOrderQueueRecord) summary() AgentOrderQueueSummary {
+	return AgentOrderQueueSummary{
+		OrderID:           record.ID,
+		OrderNumber:       record.OrderNumber,
+		OrderReference:    record.OrderReference,
+		CreatedAt:         record.CreatedAt,
+		TotalCents:        record.TotalCents,
+		DiscountsCents:    record.DiscountsCents,
+		TotalItems:        record.TotalItems,
+		PaymentStatus:     record.PaymentStatus,
+		FulfillmentStatus: record.FulfillmentStatus,
+		ShippingStatus:    record.ShippingStatus,
+		Buyer: OrderStoreSummary{
+			ID:          record.BuyerStoreID,
+			CompanyName: record.BuyerCompanyName,
+			DBAName:     record.BuyerDBAName,
+			LogoURL:     record.BuyerLogoURL,
+		},
+		Vendor: OrderStoreSummary{
+			ID:          record.VendorStoreID,
+			CompanyName: record.VendorCompanyName,
+			DBAName:     record.VendorDBAName,
+			LogoURL:     record.VendorLogoURL,
+		},
+		PickupWindow: buildPickupWindow(record.PickupWindowStart, record.PickupWindowEnd),
+	}
 }
 
 func buildVendorOrderSummary(order *models.VendorOrder) *VendorOrderSummary {
@@ -923,23 +1073,36 @@ func buildVendorOrderSummary(order *models.VendorOrder) *VendorOrderSummary {
 		return nil
 	}
 	return &VendorOrderSummary{
-		ID:                order.ID,
-		Status:            order.Status,
-		OrderNumber:       order.OrderNumber,
-		CreatedAt:         order.CreatedAt,
-		TotalCents:        order.TotalCents,
-		DiscountsCents:    order.DiscountsCents,
-		TotalItems:        sumOrderItems(order.Items),
-		OrderStatus:       order.Status,
-		PaymentStatus:     paymentStatus(order.PaymentIntent),
-		FulfillmentStatus: order.FulfillmentStatus,
-		ShippingStatus:    order.ShippingStatus,
-		DeliveredAt:       order.DeliveredAt,
-		Assignments:       &order.Assignments,
-		ShippingLine:      order.ShippingLine,
+		ID:                      order.ID,
+		Status:                  order.Status,
+		OrderNumber:             order.OrderNumber,
+		OrderReference:          order.OrderReference,
+		CreatedAt:               order.CreatedAt,
+		TotalCents:              order.TotalCents,
+		DiscountsCents:          order.DiscountsCents,
+		Currency:                order.Currency,
+		TotalItems:              sumOrderItems(order.Items),
+		OrderStatus:             order.Status,
+		PaymentStatus:           paymentStatus(order.PaymentIntent),
+		FulfillmentStatus:       order.FulfillmentStatus,
+		ShippingStatus:          order.ShippingStatus,
+		DeliveredAt:             order.DeliveredAt,
+		OpensAt:                 order.OpensAt,
+		Assignments:             &order.Assignments,
+		ShippingLine:            order.ShippingLine,
+		DeliveryDistanceMeters:  order.DeliveryDistanceMeters,
+		DeliveryDurationSeconds: order.DeliveryDurationSeconds,
+		PickupWindow:            buildPickupWindow(order.PickupWindowStart, order.PickupWindowEnd),
 	}
 }
 
+func buildPickupWindow(start, end *time.Time) *PickupWindow {
+	if start == nil || end == nil {
+		return nil
+	}
+	return &PickupWindow{Start: start.UTC(), End: end.UTC()}
+}
+
 func sumOrderItems(items []models.OrderLineItem) int {
 	total := 0
 	for _, item := range items {
@@ -969,6 +1132,18 @@ func buildLineItemDetail(item models.OrderLineItem) LineItemDetail {
 		TotalCents:     item.TotalCents,
 		Status:         string(item.Status),
 		Notes:          item.Notes,
+		Substitution:   buildLineItemSubstitution(item),
+	}
+}
+
+func buildLineItemSubstitution(item models.OrderLineItem) *LineItemSubstitution {
+	if item.SubstituteProductID == nil || item.SubstituteQty == nil || item.SubstituteUnitPriceCents == nil {
+		return nil
+	}
+	return &LineItemSubstitution{
+		ProductID:      *item.SubstituteProductID,
+		Quantity:       *item.SubstituteQty,
+		UnitPriceCents: *item.SubstituteUnitPriceCents,
 	}
 }
 
@@ -1005,6 +1180,34 @@ func buildAssignmentSummary(assignment *models.OrderAssignment) *OrderAssignment
 	}
 }
 
+// CreateOrderAssignmentIfUnassigned inserts assignment as the order's active assignment only
+// when the order has none, reporting false when another assignment already holds it. The
+// NOT EXISTS guard and ux_order_assignments_order_active together make concurrent claims safe.
+func (r *repository) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	if assignment.ID == uuid.Nil {
+		assignment.ID = uuid.New()
+	}
+	if assignment.AssignedAt.IsZero() {
+		assignment.AssignedAt = time.Now().UTC()
+	}
+	assignment.Active = true
+
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO order_assignments (id, order_id, agent_user_id, assigned_by_user_id, assigned_at, active)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM order_assignments WHERE order_id = ? AND active = ?
+		)
+		ON CONFLICT DO NOTHING`,
+		assignment.ID, assignment.OrderID, assignment.AgentUserID, assignment.AssignedByUserID, assignment.AssignedAt, true,
+		assignment.OrderID, true,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
 func (r *repository) UpdateOrderAssignment(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error {
 	if len(updates) == 0 {
 		return nil
@@ -1014,3 +1217,21 @@ func (r *repository) UpdateOrderAssignment(ctx context.Context, assignmentID uui
 		Where("id = ?", assignmentID).
 		Updates(updates).Error
 }
+
+func (r *repository) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+// ListOrderComments returns an order's comments oldest first. Internal comments are left out
+// unless includeInternal is set.
+func (r *repository) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	qb := r.db.WithContext(ctx).Where("order_id = ?", orderID)
+	if !includeInternal {
+		qb = qb.Where("visibility = ?", enums.OrderCommentVisibilityShared)
+	}
+	var comments []models.OrderComment
+	if err := qb.Order("created_at ASC").Order("id ASC").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
@@ -2,11 +2,15 @@ package orders
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/maps"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
@@ -18,8 +22,26 @@ import (
 
 func setupOrdersTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
+	return setupOrdersTestDBWithDSN(t, ":memory:")
+}
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+// setupConcurrentOrdersTestDB opens a file-backed database so each pooled connection sees the same
+// data. Transactions begin immediately and wait on the busy timeout, so concurrent writers queue
+// behind each other instead of failing with SQLITE_BUSY.
+func setupConcurrentOrdersTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", filepath.Join(t.TempDir(), "orders.db"))
+	db := setupOrdersTestDBWithDSN(t, dsn)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func setupOrdersTestDBWithDSN(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	require.NoError(t, err)
 
 	stores := `
@@ -34,8 +56,18 @@ CREATE TABLE IF NOT EXISTS stores (
   square_customer_id TEXT,
   kyc_status TEXT NOT NULL DEFAULT 'pending_verification',
   subscription_active INTEGER NOT NULL DEFAULT 0,
+  subscription_grace_until DATETIME,
+  tax_exempt INTEGER NOT NULL DEFAULT 0,
+  tax_exempt_certificate_media_id TEXT,
+  tax_exempt_certificate_verified_at DATETIME,
+  tax_exempt_certificate_expires_at DATETIME,
   delivery_radius_meters INTEGER NOT NULL DEFAULT 0,
+  delivery_zones TEXT,
+  business_hours TEXT,
+  auto_accept INTEGER NOT NULL DEFAULT 0,
+  volume_discount_strategy TEXT NOT NULL DEFAULT 'highest_min_qty',
   address TEXT NOT NULL,
+  currency TEXT NOT NULL DEFAULT 'USD',
   badge TEXT,
   last_logged_in_at DATETIME,
   social TEXT,
@@ -69,17 +101,26 @@ CREATE TABLE IF NOT EXISTS vendor_orders (
   shipping_line TEXT,
   attributed_token TEXT,
   ad_token TEXT,
+  delivery_distance_meters INTEGER,
+  delivery_duration_seconds INTEGER,
   total_cents INTEGER NOT NULL,
   balance_due_cents INTEGER NOT NULL,
   fulfillment_status TEXT NOT NULL,
   shipping_status TEXT NOT NULL,
   order_number INTEGER NOT NULL DEFAULT 0,
+  order_reference TEXT UNIQUE,
   notes TEXT,
   internal_notes TEXT,
   fulfilled_at DATETIME,
   delivered_at DATETIME,
   canceled_at DATETIME,
+  accepted_at DATETIME,
+  cancel_requested_at DATETIME,
   expired_at DATETIME,
+  reservation_released_at DATETIME,
+  opens_at DATETIME,
+  pickup_window_start DATETIME,
+  pickup_window_end DATETIME,
   created_at DATETIME,
   updated_at DATETIME
 );`
@@ -108,6 +149,9 @@ CREATE TABLE IF NOT EXISTS order_line_items (
   ad_token TEXT,
   status TEXT NOT NULL,
   notes TEXT,
+  substitute_product_id TEXT,
+  substitute_qty INTEGER,
+  substitute_unit_price_cents INTEGER,
   created_at DATETIME,
   updated_at DATETIME
 );`
@@ -142,6 +186,8 @@ CREATE TABLE IF NOT EXISTS order_assignments (
 	require.NoError(t, db.Exec(orderLineItems).Error)
 	require.NoError(t, db.Exec(paymentIntents).Error)
 	require.NoError(t, db.Exec(orderAssignments).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX ux_order_assignments_order_active ON order_assignments (order_id) WHERE active = 1;`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE IF NOT EXISTS order_reference_sequences (year INTEGER PRIMARY KEY, last_value INTEGER NOT NULL);`).Error)
 	return db
 }
 
@@ -266,6 +312,106 @@ func TestRepository_ListPayoutOrders(t *testing.T) {
 	assert.Empty(t, list.NextCursor)
 }
 
+func TestRepository_StreamAccountingExport(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	first := createOrder(t, db, buyer, vendor, 1, from, 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	second := createOrder(t, db, buyer, vendor, 2, to.Add(-time.Minute), 2, enums.PaymentStatusSettled, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	createOrder(t, db, buyer, vendor, 3, from.Add(-time.Second), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	createOrder(t, db, buyer, vendor, 4, to, 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	createOrder(t, db, buyer, vendor, 5, from.Add(time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusRejected, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	var lines []AccountingExportLine
+	err := repo.StreamAccountingExport(context.Background(), from, to, func(line AccountingExportLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, first.ID, lines[0].OrderID)
+	assert.Equal(t, second.ID, lines[1].OrderID)
+	assert.Equal(t, "Buyer", lines[1].BuyerName)
+	assert.Equal(t, "Vendor", lines[1].VendorName)
+	assert.Equal(t, 2, lines[1].Qty)
+	assert.Equal(t, second.TotalCents, lines[1].OrderTotalCents)
+}
+
+func TestRepositoryUpdateVendorOrderStatusStampsAcceptedAtOnce(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	order := createOrder(t, db, buyer, vendor, 1, time.Now().UTC(), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	ctx := context.Background()
+	require.NoError(t, repo.UpdateVendorOrderStatus(ctx, order.ID, enums.VendorOrderStatusAccepted))
+	first, err := repo.FindVendorOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, first.AcceptedAt)
+
+	require.NoError(t, repo.UpdateVendorOrderStatus(ctx, order.ID, enums.VendorOrderStatusAccepted))
+	second, err := repo.FindVendorOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, second.AcceptedAt)
+	assert.True(t, first.AcceptedAt.Equal(*second.AcceptedAt))
+}
+
+func TestRepositoryUpdateVendorOrderStampsAcceptedAtLeavingPending(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	order := createOrder(t, db, buyer, vendor, 1, time.Now().UTC(), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	ctx := context.Background()
+	require.NoError(t, repo.UpdateVendorOrder(ctx, order.ID, map[string]any{"total_cents": 500}))
+	pending, err := repo.FindVendorOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pending.AcceptedAt)
+
+	require.NoError(t, repo.UpdateVendorOrder(ctx, order.ID, map[string]any{"status": enums.VendorOrderStatusReadyForDispatch}))
+	dispatched, err := repo.FindVendorOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, dispatched.AcceptedAt)
+}
+
+func TestRepositoryMarkReservationReleasedClaimsOnce(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	pending := createOrder(t, db, buyer, vendor, 1, time.Now().UTC(), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	accepted := createOrder(t, db, buyer, vendor, 2, time.Now().UTC(), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	ctx := context.Background()
+	at := time.Now().UTC()
+	claimed, err := repo.MarkReservationReleased(ctx, pending.ID, at)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = repo.MarkReservationReleased(ctx, pending.ID, at.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	claimed, err = repo.MarkReservationReleased(ctx, accepted.ID, at)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	reloaded, err := repo.FindVendorOrder(ctx, pending.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.ReservationReleasedAt)
+	assert.WithinDuration(t, at, *reloaded.ReservationReleasedAt, time.Second)
+}
+
 func TestRepository_ListPayoutOrders_Pagination(t *testing.T) {
 	db := setupOrdersTestDB(t)
 	repo := NewRepository(db)
@@ -346,6 +492,91 @@ func TestRepositoryListBuyerOrders_pagination(t *testing.T) {
 	assert.Empty(t, second.Pagination.Next)
 }
 
+func TestRepositoryListBuyerOrders_cursorStableOnTimestampCollision(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Collision Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Collision Vendor", enums.StoreTypeVendor)
+
+	created := time.Now().UTC().Truncate(time.Second)
+	seeded := map[uuid.UUID]bool{}
+	for i := 0; i < 5; i++ {
+		order := createOrder(t, db, buyer, vendor, int64(i+1), created, 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+		seeded[order.ID] = true
+	}
+
+	seen := map[uuid.UUID]bool{}
+	cursor := ""
+	for page := 1; page <= len(seeded); page++ {
+		list, err := repo.ListBuyerOrders(context.Background(), buyer.ID, ListOrdersInput{
+			Pagination: pagination.Params{Limit: 2, Cursor: cursor},
+			Page:       page,
+		}, BuyerOrderFilters{})
+		require.NoError(t, err)
+		for _, order := range list.Orders {
+			require.False(t, seen[order.ID], "order %s repeated across pages", order.ID)
+			seen[order.ID] = true
+		}
+		cursor = list.Pagination.Next
+		if cursor == "" {
+			break
+		}
+	}
+
+	assert.Equal(t, seeded, seen)
+}
+
+func TestRepositoryListOrders_includeTotal(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Total Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Total Vendor", enums.StoreTypeVendor)
+	otherVendor := newStore(t, db, "Other Vendor", enums.StoreTypeVendor)
+
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		createOrder(t, db, buyer, vendor, int64(i+1), now.Add(-time.Duration(i)*time.Minute), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	}
+	createOrder(t, db, buyer, otherVendor, 4, now.Add(-time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	withoutTotal, err := repo.ListBuyerOrders(context.Background(), buyer.ID, ListOrdersInput{
+		Pagination: pagination.Params{Limit: 1},
+		Page:       1,
+	}, BuyerOrderFilters{})
+	require.NoError(t, err)
+	assert.Nil(t, withoutTotal.Pagination.Total)
+
+	buyerTotal, err := repo.ListBuyerOrders(context.Background(), buyer.ID, ListOrdersInput{
+		Pagination: pagination.Params{Limit: 1, IncludeTotal: true},
+		Page:       1,
+	}, BuyerOrderFilters{})
+	require.NoError(t, err)
+	require.Len(t, buyerTotal.Orders, 1)
+	require.NotNil(t, buyerTotal.Pagination.Total)
+	assert.Equal(t, 4, *buyerTotal.Pagination.Total)
+
+	vendorTotal, err := repo.ListVendorOrders(context.Background(), vendor.ID, ListOrdersInput{
+		Pagination: pagination.Params{Limit: 1, IncludeTotal: true},
+		Page:       1,
+	}, VendorOrderFilters{})
+	require.NoError(t, err)
+	require.NotNil(t, vendorTotal.Pagination.Total)
+	assert.Equal(t, 3, *vendorTotal.Pagination.Total)
+
+	vendorWithoutTotal, err := repo.ListVendorOrders(context.Background(), vendor.ID, ListOrdersInput{
+		Pagination: pagination.Params{Limit: 1},
+		Page:       1,
+	}, VendorOrderFilters{})
+	require.NoError(t, err)
+	assert.Nil(t, vendorWithoutTotal.Pagination.Total)
+
+	payload, err := json.Marshal(vendorWithoutTotal.Pagination)
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), `"total"`)
+}
+
 func TestRepositoryListBuyerOrders_filtersAndSearch(t *testing.T) {
 	db := setupOrdersTestDB(t)
 	repo := NewRepository(db)
@@ -373,6 +604,104 @@ func TestRepositoryListBuyerOrders_filtersAndSearch(t *testing.T) {
 	assert.Empty(t, list.Pagination.Next)
 }
 
+func TestRepositoryListOrders_search(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Search Buyer", enums.StoreTypeBuyer)
+	otherBuyer := newStore(t, db, "Other Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Search Vendor", enums.StoreTypeVendor)
+
+	now := time.Now().UTC()
+	gummies := createOrder(t, db, buyer, vendor, 1201, now.Add(-2*time.Hour), 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	flower := createOrder(t, db, buyer, vendor, 1202, now.Add(-time.Hour), 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	other := createOrder(t, db, otherBuyer, vendor, 1203, now, 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+	require.NoError(t, db.Model(&models.OrderLineItem{}).Where("order_id = ?", gummies.ID).Update("name", "Watermelon Gummies").Error)
+	require.NoError(t, db.Model(&models.OrderLineItem{}).Where("order_id = ?", flower.ID).Update("name", "Blue Dream Flower").Error)
+	require.NoError(t, db.Model(&models.OrderLineItem{}).Where("order_id = ?", other.ID).Update("name", "Sour Gummies").Error)
+
+	input := ListOrdersInput{Pagination: pagination.Params{Limit: 10}, Page: 1}
+	buyerIDs := func(search string) []uuid.UUID {
+		t.Helper()
+		list, err := repo.ListBuyerOrders(context.Background(), buyer.ID, input, BuyerOrderFilters{Query: search})
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, 0, len(list.Orders))
+		for _, order := range list.Orders {
+			ids = append(ids, order.ID)
+		}
+		return ids
+	}
+	vendorIDs := func(search string) []uuid.UUID {
+		t.Helper()
+		list, err := repo.ListVendorOrders(context.Background(), vendor.ID, input, VendorOrderFilters{Query: search})
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, 0, len(list.Orders))
+		for _, order := range list.Orders {
+			ids = append(ids, order.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uuid.UUID{flower.ID}, buyerIDs("1202"))
+	assert.Equal(t, []uuid.UUID{flower.ID}, vendorIDs("#1202"))
+	assert.Equal(t, []uuid.UUID{gummies.ID}, buyerIDs("gummies"))
+	assert.Equal(t, []uuid.UUID{other.ID, gummies.ID}, vendorIDs("GUMMIES"))
+	assert.Equal(t, []uuid.UUID{other.ID}, vendorIDs("other buyer"))
+	assert.Empty(t, buyerIDs("other buyer"))
+	assert.Empty(t, buyerIDs("%"))
+	assert.Empty(t, vendorIDs("gumm_es"))
+}
+
+func TestRepositoryListOrders_dateRangeAndStatuses(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Window Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Window Vendor", enums.StoreTypeVendor)
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tooOld := createOrder(t, db, buyer, vendor, 1301, base.Add(-48*time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	accepted := createOrder(t, db, buyer, vendor, 1302, base, 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	inTransit := createOrder(t, db, buyer, vendor, 1303, base.Add(time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusInTransit, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusInTransit)
+	createOrder(t, db, buyer, vendor, 1304, base.Add(2*time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusCreatedPending, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+	createOrder(t, db, buyer, vendor, 1305, base.Add(48*time.Hour), 1, enums.PaymentStatusUnpaid, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPending, enums.VendorOrderShippingStatusPending)
+
+	from := base.Add(-time.Hour)
+	to := base.Add(24 * time.Hour)
+	statuses := []enums.VendorOrderStatus{enums.VendorOrderStatusAccepted, enums.VendorOrderStatusInTransit}
+	input := ListOrdersInput{Pagination: pagination.Params{Limit: 10}, Page: 1}
+
+	buyerList, err := repo.ListBuyerOrders(context.Background(), buyer.ID, input, BuyerOrderFilters{
+		DateFrom:      &from,
+		DateTo:        &to,
+		OrderStatuses: statuses,
+	})
+	require.NoError(t, err)
+	require.Len(t, buyerList.Orders, 2)
+	assert.Equal(t, inTransit.ID, buyerList.Orders[0].ID)
+	assert.Equal(t, accepted.ID, buyerList.Orders[1].ID)
+
+	vendorList, err := repo.ListVendorOrders(context.Background(), vendor.ID, input, VendorOrderFilters{
+		DateFrom:      &from,
+		DateTo:        &to,
+		OrderStatuses: statuses,
+	})
+	require.NoError(t, err)
+	require.Len(t, vendorList.Orders, 2)
+	assert.Equal(t, inTransit.ID, vendorList.Orders[0].ID)
+	assert.Equal(t, accepted.ID, vendorList.Orders[1].ID)
+
+	// The bounds are inclusive.
+	exact := base.Add(-48 * time.Hour)
+	buyerList, err = repo.ListBuyerOrders(context.Background(), buyer.ID, input, BuyerOrderFilters{
+		DateTo:        &exact,
+		OrderStatuses: statuses,
+	})
+	require.NoError(t, err)
+	require.Len(t, buyerList.Orders, 1)
+	assert.Equal(t, tooOld.ID, buyerList.Orders[0].ID)
+}
+
 func TestRepositoryListVendorOrders_pagination(t *testing.T) {
 	db := setupOrdersTestDB(t)
 	repo := NewRepository(db)
@@ -435,6 +764,126 @@ func TestRepositoryListVendorOrders_filtersAndSearch(t *testing.T) {
 	assert.Empty(t, list.Pagination.Next)
 }
 
+func createQueueOrder(t *testing.T, db *gorm.DB, buyer, vendor *models.Store, number int64, created time.Time, qty int) *models.VendorOrder {
+	t.Helper()
+	return createOrder(t, db, buyer, vendor, number, created, qty, enums.PaymentStatusUnpaid, enums.VendorOrderStatusReadyForDispatch, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusPending)
+}
+
+func TestRepositoryListUnassignedHoldOrders_filters(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	now := time.Now().UTC()
+
+	small := createQueueOrder(t, db, buyer, vendor, 1, now.Add(-3*time.Hour), 1)
+	large := createQueueOrder(t, db, buyer, vendor, 2, now.Add(-2*time.Hour), 5)
+	fresh := createQueueOrder(t, db, buyer, vendor, 3, now.Add(-10*time.Minute), 3)
+	assigned := createQueueOrder(t, db, buyer, vendor, 4, now.Add(-time.Hour), 3)
+	assignOrder(t, db, assigned.ID, uuid.New(), uuid.New())
+
+	list, err := repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 10}, AgentQueueFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{fresh.ID, large.ID, small.ID}, queueOrderIDs(list))
+
+	list, err = repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 10}, AgentQueueFilters{
+		MinTotalCents: ptr(2000),
+		MaxTotalCents: ptr(4000),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{fresh.ID}, queueOrderIDs(list))
+
+	list, err = repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 10}, AgentQueueFilters{
+		CreatedBefore: ptr(now.Add(-time.Hour)),
+		CreatedAfter:  ptr(now.Add(-150 * time.Minute)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{large.ID}, queueOrderIDs(list))
+}
+
+func TestRepositoryListUnassignedHoldOrders_pickupWindow(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	scheduled := createQueueOrder(t, db, buyer, vendor, 1, now.Add(-time.Hour), 1)
+	unscheduled := createQueueOrder(t, db, buyer, vendor, 2, now.Add(-2*time.Hour), 1)
+	start := now.Add(3 * time.Hour)
+	end := start.Add(time.Hour)
+	require.NoError(t, repo.UpdateVendorOrder(context.Background(), scheduled.ID, map[string]any{
+		"pickup_window_start": start,
+		"pickup_window_end":   end,
+	}))
+
+	list, err := repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 10}, AgentQueueFilters{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{scheduled.ID, unscheduled.ID}, queueOrderIDs(list))
+	require.NotNil(t, list.Orders[0].PickupWindow)
+	assert.True(t, list.Orders[0].PickupWindow.Start.Equal(start))
+	assert.True(t, list.Orders[0].PickupWindow.End.Equal(end))
+	assert.Nil(t, list.Orders[1].PickupWindow)
+}
+
+func TestRepositoryListUnassignedHoldOrders_sortsByProximity(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	near := newStore(t, db, "Near Vendor", enums.StoreTypeVendor)
+	mid := newStore(t, db, "Mid Vendor", enums.StoreTypeVendor)
+	far := newStore(t, db, "Far Vendor", enums.StoreTypeVendor)
+	// Norman (near) is the default; move the others toward Oklahoma City and Tulsa.
+	require.NoError(t, db.Model(mid).Update("address", types.Address{Line1: "1 Main St", City: "Oklahoma City", State: "OK", PostalCode: "73102", Country: "US", Lat: 35.4676, Lng: -97.5164}).Error)
+	require.NoError(t, db.Model(far).Update("address", types.Address{Line1: "1 Main St", City: "Tulsa", State: "OK", PostalCode: "74103", Country: "US", Lat: 36.1540, Lng: -95.9928}).Error)
+
+	now := time.Now().UTC()
+	farOrder := createQueueOrder(t, db, buyer, far, 1, now.Add(-time.Minute), 1)
+	nearOrder := createQueueOrder(t, db, buyer, near, 2, now.Add(-3*time.Hour), 1)
+	midOrder := createQueueOrder(t, db, buyer, mid, 3, now.Add(-2*time.Hour), 1)
+
+	filters := AgentQueueFilters{Near: &maps.LatLng{Latitude: 35.2226, Longitude: -97.4395}}
+	first, err := repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 2}, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{nearOrder.ID, midOrder.ID}, queueOrderIDs(first))
+	require.NotNil(t, first.Orders[0].DistanceMeters)
+	require.NotNil(t, first.Orders[1].DistanceMeters)
+	assert.Less(t, *first.Orders[0].DistanceMeters, *first.Orders[1].DistanceMeters)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 2, Cursor: first.NextCursor}, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{farOrder.ID}, queueOrderIDs(second))
+	assert.Empty(t, second.NextCursor)
+	assert.False(t, second.Truncated)
+
+	_, err = repo.ListUnassignedHoldOrders(context.Background(), pagination.Params{Limit: 2, Cursor: "not-a-cursor"}, filters)
+	require.Error(t, err)
+}
+
+func TestCapProximityCandidatesFlagsTruncation(t *testing.T) {
+	records := make([]agentOrderQueueRecord, maxProximityQueueCandidates+1)
+
+	capped, truncated := capProximityCandidates(records)
+	assert.True(t, truncated)
+	assert.Len(t, capped, maxProximityQueueCandidates)
+
+	capped, truncated = capProximityCandidates(records[:maxProximityQueueCandidates])
+	assert.False(t, truncated)
+	assert.Len(t, capped, maxProximityQueueCandidates)
+}
+
+func queueOrderIDs(list *AgentOrderQueueList) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(list.Orders))
+	for _, order := range list.Orders {
+		ids = append(ids, order.OrderID)
+	}
+	return ids
+}
+
 func TestRepositoryListOrdersBetweenStores(t *testing.T) {
 	db := setupOrdersTestDB(t)
 	repo := NewRepository(db)
@@ -467,6 +916,10 @@ func TestRepositoryFindOrderDetail(t *testing.T) {
 	now := time.Now().UTC()
 	order := createOrder(t, db, buyer, vendor, 10, now, 2, enums.PaymentStatusPending, enums.VendorOrderStatusAccepted, enums.VendorOrderFulfillmentStatusPartial, enums.VendorOrderShippingStatusInTransit)
 	assignOrder(t, db, order.ID, uuid.New(), uuid.New())
+	require.NoError(t, db.Model(&models.VendorOrder{}).Where("id = ?", order.ID).Updates(map[string]any{
+		"delivery_distance_meters":  8046,
+		"delivery_duration_seconds": 900,
+	}).Error)
 
 	detail, err := repo.FindOrderDetail(context.Background(), order.ID)
 	require.NoError(t, err)
@@ -475,11 +928,42 @@ func TestRepositoryFindOrderDetail(t *testing.T) {
 	assert.Equal(t, int64(10), detail.Order.OrderNumber)
 	assert.Equal(t, "Detail Buyer", detail.BuyerStore.CompanyName)
 	assert.Equal(t, "Detail Vendor", detail.VendorStore.CompanyName)
+	assert.Equal(t, ptr(8046), detail.Order.DeliveryDistanceMeters)
+	assert.Equal(t, ptr(900), detail.Order.DeliveryDurationSeconds)
 	require.Len(t, detail.LineItems, 1)
 	require.NotNil(t, detail.PaymentIntent)
 	require.NotNil(t, detail.ActiveAssignment)
 }
 
+func TestRepositoryFindOrderDetailKeepsSnapshotOfDeletedProduct(t *testing.T) {
+	db := setupOrdersTestDB(t)
+	repo := NewRepository(db)
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+  id TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  price_cents INTEGER NOT NULL,
+  deleted_at DATETIME
+);`).Error)
+
+	buyer := newStore(t, db, "Snapshot Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Snapshot Vendor", enums.StoreTypeVendor)
+	now := time.Now().UTC()
+	order := createOrder(t, db, buyer, vendor, 11, now, 1, enums.PaymentStatusPaid, enums.VendorOrderStatusDelivered, enums.VendorOrderFulfillmentStatusFulfilled, enums.VendorOrderShippingStatusDelivered)
+
+	productID := uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO products (id, title, price_cents) VALUES (?, ?, ?)`, productID, "Test Item", 1000).Error)
+	require.NoError(t, db.Model(&models.OrderLineItem{}).Where("order_id = ?", order.ID).Update("product_id", productID).Error)
+
+	// The vendor later edits and soft-deletes the product; the order keeps what was bought.
+	require.NoError(t, db.Exec(`UPDATE products SET title = ?, price_cents = ?, deleted_at = ? WHERE id = ?`, "Renamed", 2500, now, productID).Error)
+
+	detail, err := repo.FindOrderDetail(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Len(t, detail.LineItems, 1)
+	assert.Equal(t, "Test Item", detail.LineItems[0].Name)
+	assert.Equal(t, 1000, detail.LineItems[0].UnitPriceCents)
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -3,13 +3,17 @@ package orders
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
 	"github.com/google/uuid"
@@ -26,7 +30,14 @@ type outboxPublisher interface {
 
 // InventoryReleaser returns reserved stock when a line item is rejected.
 type InventoryReleaser interface {
-	Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int) error
+	Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int, actor inventory.AdjustmentActor) error
+}
+
+// NudgeLimiter records recent nudges so buyers cannot flood a vendor with reminders.
+type NudgeLimiter interface {
+	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+	RateLimitKey(scope string) string
 }
 
 type inventoryReserver interface {
@@ -37,13 +48,22 @@ type inventoryReserver interface {
 type Service interface {
 	VendorDecision(ctx context.Context, input VendorDecisionInput) error
 	LineItemDecision(ctx context.Context, input LineItemDecisionInput) error
-	CancelOrder(ctx context.Context, input BuyerCancelInput) error
+	CancelOrder(ctx context.Context, input BuyerCancelInput) (*BuyerCancelResult, error)
 	NudgeVendor(ctx context.Context, input BuyerNudgeInput) error
 	RetryOrder(ctx context.Context, input BuyerRetryInput) (*BuyerRetryResult, error)
+	SchedulePickup(ctx context.Context, input SchedulePickupInput) error
+	ClaimOrder(ctx context.Context, input AgentClaimInput) (*OrderAssignmentSummary, error)
 	AgentPickup(ctx context.Context, input AgentPickupInput) error
 	AgentDeliver(ctx context.Context, input AgentDeliverInput) error
 	AgentCashCollected(ctx context.Context, input AgentCashCollectedInput) error
 	ConfirmPayout(ctx context.Context, input ConfirmPayoutInput) error
+	ProposeSubstitution(ctx context.Context, input ProposeSubstitutionInput) error
+	AcceptSubstitution(ctx context.Context, input SubstitutionDecisionInput) error
+	RejectSubstitution(ctx context.Context, input SubstitutionDecisionInput) error
+	ApproveCancelRequest(ctx context.Context, input CancelRequestDecisionInput) error
+	DenyCancelRequest(ctx context.Context, input CancelRequestDecisionInput) error
+	AddComment(ctx context.Context, input AddCommentInput) (*OrderComment, error)
+	ListComments(ctx context.Context, input ListCommentsInput) ([]OrderComment, error)
 }
 
 type service struct {
@@ -53,6 +73,79 @@ type service struct {
 	inventory InventoryReleaser
 	reserver  inventoryReserver
 	ledger    ledger.Service
+
+	cancelWindow  time.Duration
+	nudgeLimiter  NudgeLimiter
+	nudgeCooldown time.Duration
+
+	retryableStatuses []enums.VendorOrderStatus
+}
+
+// defaultCancelWindow is how long after acceptance buyers may cancel without vendor approval.
+const defaultCancelWindow = 15 * time.Minute
+
+// ServiceOption customizes the orders service.
+type ServiceOption func(*service)
+
+// WithCancelWindow sets how long after acceptance a buyer may cancel directly. Zero makes every
+// cancel of an accepted order a request the vendor must approve.
+func WithCancelWindow(window time.Duration) ServiceOption {
+	return func(s *service) {
+		if window >= 0 {
+			s.cancelWindow = window
+		}
+	}
+}
+
+// WithNudgeCooldown throttles buyer nudges to one per order per cooldown, tracked in limiter. A nil
+// limiter or non-positive cooldown leaves nudges unthrottled.
+func WithNudgeCooldown(limiter NudgeLimiter, cooldown time.Duration) ServiceOption {
+	return func(s *service) {
+		if limiter == nil || cooldown <= 0 {
+			return
+		}
+		s.nudgeLimiter = limiter
+		s.nudgeCooldown = cooldown
+	}
+}
+
+// defaultRetryableStatuses lists the order statuses a buyer may retry when no override is configured.
+var defaultRetryableStatuses = []enums.VendorOrderStatus{
+	enums.VendorOrderStatusExpired,
+	enums.VendorOrderStatusRejected,
+}
+
+// WithRetryableStatuses overrides which order statuses a buyer may retry. An empty list keeps the
+// defaults; use ParseRetryableStatuses to validate configured values.
+func WithRetryableStatuses(statuses []enums.VendorOrderStatus) ServiceOption {
+	return func(s *service) {
+		if len(statuses) > 0 {
+			s.retryableStatuses = statuses
+		}
+	}
+}
+
+// ParseRetryableStatuses converts configured status names into retryable statuses. Only orders that
+// ended without being fulfilled (expired, rejected, canceled) can be retried.
+func ParseRetryableStatuses(values []string) ([]enums.VendorOrderStatus, error) {
+	statuses := make([]enums.VendorOrderStatus, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		status, err := enums.ParseVendorOrderStatus(value)
+		if err != nil {
+			return nil, err
+		}
+		switch status {
+		case enums.VendorOrderStatusExpired, enums.VendorOrderStatusRejected, enums.VendorOrderStatusCanceled:
+		default:
+			return nil, fmt.Errorf("vendor order status %q cannot be retried", value)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
 }
 
 // VendorDecisionInput captures the data required to change an order's decision state.
@@ -91,6 +184,21 @@ type BuyerCancelInput struct {
 	ActorRole    string
 }
 
+// BuyerCancelOutcome reports what a buyer cancel did.
+type BuyerCancelOutcome string
+
+const (
+	// BuyerCancelOutcomeCanceled means the order was canceled.
+	BuyerCancelOutcomeCanceled BuyerCancelOutcome = "canceled"
+	// BuyerCancelOutcomeRequested means the cancel window had passed, so the vendor was asked to approve.
+	BuyerCancelOutcomeRequested BuyerCancelOutcome = "cancel_requested"
+)
+
+// BuyerCancelResult surfaces the outcome of a buyer cancel.
+type BuyerCancelResult struct {
+	Outcome BuyerCancelOutcome `json:"outcome"`
+}
+
 // BuyerNudgeInput captures the buyer request used to prod the vendor.
 type BuyerNudgeInput struct {
 	OrderID      uuid.UUID
@@ -99,7 +207,7 @@ type BuyerNudgeInput struct {
 	ActorRole    string
 }
 
-// BuyerRetryInput reuses an expired order snapshot so the buyer can try again.
+// BuyerRetryInput reuses an expired or rejected order snapshot so the buyer can try again.
 type BuyerRetryInput struct {
 	OrderID      uuid.UUID
 	ActorUserID  uuid.UUID
@@ -112,6 +220,20 @@ type BuyerRetryResult struct {
 	OrderID uuid.UUID `json:"order_id"`
 }
 
+// SchedulePickupInput carries the vendor's expected ready window for an order.
+type SchedulePickupInput struct {
+	OrderID      uuid.UUID
+	Window       PickupWindow
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+}
+
+// AgentClaimInput captures the agent claiming an unassigned order from the dispatch queue.
+type AgentClaimInput struct {
+	OrderID     uuid.UUID
+	AgentUserID uuid.UUID
+}
+
 // AgentPickupInput captures the agent and order for pickup confirmation.
 type AgentPickupInput struct {
 	OrderID     uuid.UUID
@@ -137,7 +259,7 @@ type ConfirmPayoutInput struct {
 }
 
 // NewService builds a vendor order service with the required dependencies.
-func NewService(repo Repository, tx txRunner, outbox outboxPublisher, inventory InventoryReleaser, reserver inventoryReserver, ledgerSvc ledger.Service) (Service, error) {
+func NewService(repo Repository, tx txRunner, outbox outboxPublisher, inventory InventoryReleaser, reserver inventoryReserver, ledgerSvc ledger.Service, opts ...ServiceOption) (Service, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("orders repository required")
 	}
@@ -156,14 +278,21 @@ func NewService(repo Repository, tx txRunner, outbox outboxPublisher, inventory
 	if ledgerSvc == nil {
 		return nil, fmt.Errorf("ledger service required")
 	}
-	return &service{
-		repo:      repo,
-		tx:        tx,
-		outbox:    outbox,
-		inventory: inventory,
-		reserver:  reserver,
-		ledger:    ledgerSvc,
-	}, nil
+	svc := &service{
+		repo:         repo,
+		tx:           tx,
+		outbox:       outbox,
+		inventory:    inventory,
+		reserver:     reserver,
+		ledger:       ledgerSvc,
+		cancelWindow: defaultCancelWindow,
+
+		retryableStatuses: defaultRetryableStatuses,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc, nil
 }
 
 func (s *service) VendorDecision(ctx context.Context, input VendorDecisionInput) error {
@@ -200,6 +329,16 @@ func (s *service) VendorDecision(ctx context.Context, input VendorDecisionInput)
 		if order.Status != enums.VendorOrderStatusCreatedPending {
 			return pkgerrors.New(pkgerrors.CodeStateConflict, "vendor decision not allowed in current state")
 		}
+		if targetStatus == enums.VendorOrderStatusAccepted && order.OpensAt != nil && time.Now().Before(*order.OpensAt) {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "order can be accepted once business hours start").WithDetails(map[string]any{
+				"opens_at": order.OpensAt.UTC(),
+			})
+		}
+		if targetStatus == enums.VendorOrderStatusAccepted && !holdsReservation(order) {
+			if err := s.reacquireReservation(ctx, tx, repo, order, inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID)); err != nil {
+				return err
+			}
+		}
 
 		if err := repo.UpdateVendorOrderStatus(ctx, order.ID, targetStatus); err != nil {
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update order status")
@@ -212,6 +351,7 @@ func (s *service) VendorDecision(ctx context.Context, input VendorDecisionInput)
 			AggregateID:   order.ID,
 			Version:       1,
 			Actor:         buildActor(input.ActorUserID, input.ActorStoreID, input.ActorRole),
+			DedupKey:      outbox.DedupKey(enums.EventOrderDecided, order.ID),
 			Data: payloads.OrderDecisionEvent{
 				OrderID:         order.ID,
 				CheckoutGroupID: order.CheckoutGroupID,
@@ -276,8 +416,9 @@ func (s *service) LineItemDecision(ctx context.Context, input LineItemDecisionIn
 			return pkgerrors.New(pkgerrors.CodeStateConflict, "line item cannot be updated in current state")
 		}
 
-		if targetStatus == enums.LineItemStatusRejected && lineItem.ProductID != nil && lineItem.Qty > 0 {
-			if err := s.inventory.Release(ctx, tx, *lineItem.ProductID, lineItem.Qty); err != nil {
+		if targetStatus == enums.LineItemStatusRejected && holdsReservation(order) && lineItem.ProductID != nil && lineItem.Qty > 0 {
+			actor := inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID)
+			if err := s.inventory.Release(ctx, tx, *lineItem.ProductID, lineItem.Qty, actor); err != nil {
 				return err
 			}
 		}
@@ -291,28 +432,27 @@ func (s *service) LineItemDecision(ctx context.Context, input LineItemDecisionIn
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "reload line items")
 		}
 
-		subtotal := 0
 		pending := 0
 		rejected := 0
 		for _, item := range items {
-			if item.Status == enums.LineItemStatusPending {
+			switch item.Status {
+			case enums.LineItemStatusPending, enums.LineItemStatusPendingSubstitution:
 				pending++
-			}
-			if item.Status == enums.LineItemStatusRejected {
+			case enums.LineItemStatusRejected:
 				rejected++
-				continue
 			}
-			subtotal += item.TotalCents
 		}
 
-		diff := order.TotalCents - order.SubtotalCents
-		if diff < 0 {
-			diff = 0
-		}
-		total := subtotal + diff
-		if total < 0 {
-			total = 0
+		// A pending order whose reservation lapsed must hold stock again before a line is fulfilled
+		// or the order moves on, so the vendor never dispatches stock that was handed back.
+		if !holdsReservation(order) && (targetStatus != enums.LineItemStatusRejected || pending == 0) {
+			actor := inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID)
+			if err := s.reacquireReservation(ctx, tx, repo, order, actor); err != nil {
+				return err
+			}
 		}
+
+		subtotal, total := recomputeOrderTotals(order, items)
 		balance := total
 
 		updates := map[string]any{
@@ -370,18 +510,22 @@ func (s *service) LineItemDecision(ctx context.Context, input LineItemDecisionIn
 	})
 }
 
-func (s *service) CancelOrder(ctx context.Context, input BuyerCancelInput) error {
+// CancelOrder cancels a buyer's order. Once the vendor has accepted, the buyer may only cancel
+// directly within the cancel window; after it closes the vendor is notified of a cancel request
+// and the order is left untouched.
+func (s *service) CancelOrder(ctx context.Context, input BuyerCancelInput) (*BuyerCancelResult, error) {
 	if input.OrderID == uuid.Nil {
-		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "order id required")
 	}
 	if input.ActorUserID == uuid.Nil {
-		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
 	}
 	if input.ActorStoreID == uuid.Nil {
-		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
 	}
 
-	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+	var result *BuyerCancelResult
+	err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
 		repo := s.repo.WithTx(tx)
 		order, err := repo.FindVendorOrder(ctx, input.OrderID)
 		if err != nil {
@@ -397,52 +541,101 @@ func (s *service) CancelOrder(ctx context.Context, input BuyerCancelInput) error
 			return pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be canceled in current state")
 		}
 
-		items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
-		if err != nil {
-			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load order line items")
+		now := time.Now().UTC()
+		if !s.withinCancelWindow(order, now) {
+			result = &BuyerCancelResult{Outcome: BuyerCancelOutcomeRequested}
+			if order.CancelRequestedAt != nil {
+				return nil
+			}
+			if err := repo.UpdateVendorOrder(ctx, order.ID, map[string]any{"cancel_requested_at": now}); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record cancel request")
+			}
+			return s.emitCancelRequestNotification(ctx, tx, order, notificationCancelRequested, input.ActorUserID, input.ActorStoreID, input.ActorRole)
 		}
 
-		for _, item := range items {
-			if item.Status == enums.LineItemStatusFulfilled {
-				continue
-			}
-			if err := releaseLineItem(item, s.inventory, ctx, tx); err != nil {
+		if err := s.cancelVendorOrder(ctx, tx, repo, order, input.ActorUserID, input.ActorStoreID, input.ActorRole, now); err != nil {
+			return err
+		}
+		result = &BuyerCancelResult{Outcome: BuyerCancelOutcomeCanceled}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// cancelVendorOrder releases the order's unfulfilled stock, rejects its open line items, marks it
+// canceled with nothing left to pay, and emits order_canceled.
+func (s *service) cancelVendorOrder(ctx context.Context, tx *gorm.DB, repo Repository, order *models.VendorOrder, actorUserID, actorStoreID uuid.UUID, actorRole string, now time.Time) error {
+	items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load order line items")
+	}
+
+	for _, item := range items {
+		if item.Status == enums.LineItemStatusFulfilled {
+			continue
+		}
+		if holdsReservation(order) {
+			if err := releaseLineItem(item, s.inventory, inventory.NewAdjustmentActor(actorUserID, actorStoreID), ctx, tx); err != nil {
 				return err
 			}
-			if item.Status != enums.LineItemStatusRejected {
-				if err := repo.UpdateOrderLineItemStatus(ctx, item.ID, enums.LineItemStatusRejected, nil); err != nil {
-					return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update line item status")
-				}
+		}
+		if item.Status != enums.LineItemStatusRejected {
+			if err := repo.UpdateOrderLineItemStatus(ctx, item.ID, enums.LineItemStatusRejected, nil); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update line item status")
 			}
 		}
+	}
 
-		now := time.Now().UTC()
-		updates := map[string]any{
-			"status":            enums.VendorOrderStatusCanceled,
-			"balance_due_cents": 0,
-			"canceled_at":       now,
-		}
-		if err := repo.UpdateVendorOrder(ctx, order.ID, updates); err != nil {
-			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update vendor order")
-		}
+	updates := map[string]any{
+		"status":              enums.VendorOrderStatusCanceled,
+		"balance_due_cents":   0,
+		"canceled_at":         now,
+		"cancel_requested_at": nil,
+	}
+	if err := repo.UpdateVendorOrder(ctx, order.ID, updates); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update vendor order")
+	}
 
-		event := outbox.DomainEvent{
-			EventType:     enums.EventOrderCanceled,
-			AggregateType: enums.AggregateVendorOrder,
-			AggregateID:   order.ID,
-			Version:       1,
-			Actor:         buildActor(input.ActorUserID, input.ActorStoreID, input.ActorRole),
-			OccurredAt:    now,
-			Data: payloads.OrderCanceledEvent{
-				OrderID:         order.ID,
-				CheckoutGroupID: order.CheckoutGroupID,
-				BuyerStoreID:    order.BuyerStoreID,
-				VendorStoreID:   order.VendorStoreID,
-				CanceledAt:      now,
-			},
+	event := outbox.DomainEvent{
+		EventType:     enums.EventOrderCanceled,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   order.ID,
+		Version:       1,
+		Actor:         buildActor(actorUserID, actorStoreID, actorRole),
+		OccurredAt:    now,
+		Data: payloads.OrderCanceledEvent{
+			OrderID:         order.ID,
+			CheckoutGroupID: order.CheckoutGroupID,
+			BuyerStoreID:    order.BuyerStoreID,
+			VendorStoreID:   order.VendorStoreID,
+			CanceledAt:      now,
+		},
+	}
+	return s.outbox.Emit(ctx, tx, event)
+}
+
+func (s *service) isRetryableStatus(status enums.VendorOrderStatus) bool {
+	for _, candidate := range s.retryableStatuses {
+		if candidate == status {
+			return true
 		}
-		return s.outbox.Emit(ctx, tx, event)
-	})
+	}
+	return false
+}
+
+// withinCancelWindow reports whether the buyer may still cancel without vendor approval. Orders the
+// vendor has not accepted can always be canceled; accepted orders only until the window closes.
+func (s *service) withinCancelWindow(order *models.VendorOrder, now time.Time) bool {
+	if !isPostAcceptanceStatus(order.Status) {
+		return true
+	}
+	if order.AcceptedAt == nil {
+		return false
+	}
+	return !now.After(order.AcceptedAt.Add(s.cancelWindow))
 }
 
 func (s *service) NudgeVendor(ctx context.Context, input BuyerNudgeInput) error {
@@ -456,7 +649,8 @@ func (s *service) NudgeVendor(ctx context.Context, input BuyerNudgeInput) error
 		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
 	}
 
-	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+	var cooldownKey string
+	err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
 		repo := s.repo.WithTx(tx)
 		order, err := repo.FindVendorOrder(ctx, input.OrderID)
 		if err != nil {
@@ -471,6 +665,17 @@ func (s *service) NudgeVendor(ctx context.Context, input BuyerNudgeInput) error
 		if isFinalOrderStatus(order.Status) {
 			return pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be nudged in current state")
 		}
+		if s.nudgeLimiter != nil {
+			key := s.nudgeLimiter.RateLimitKey("order_nudge:" + order.ID.String())
+			claimed, err := s.nudgeLimiter.SetNX(ctx, key, input.ActorUserID.String(), s.nudgeCooldown)
+			if err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check nudge cooldown")
+			}
+			if !claimed {
+				return pkgerrors.New(pkgerrors.CodeRateLimit, "the vendor was already nudged about this order recently; please try again later")
+			}
+			cooldownKey = key
+		}
 
 		event := outbox.DomainEvent{
 			EventType:     enums.EventNotificationRequested,
@@ -483,11 +688,24 @@ func (s *service) NudgeVendor(ctx context.Context, input BuyerNudgeInput) error
 				CheckoutGroupID: order.CheckoutGroupID,
 				BuyerStoreID:    order.BuyerStoreID,
 				VendorStoreID:   order.VendorStoreID,
+				OrderReference:  orderReference(order),
 				Type:            "order_nudge",
 			},
 		}
 		return s.outbox.Emit(ctx, tx, event)
 	})
+	if err != nil && cooldownKey != "" {
+		// The nudge never went out, so don't hold the buyer to the cooldown.
+		_ = s.nudgeLimiter.Del(ctx, cooldownKey)
+	}
+	return err
+}
+
+// withTxRetry runs fn in a transaction, starting over on serialization failures and deadlocks.
+func (s *service) withTxRetry(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return db.RetryTransient(ctx, func() error {
+		return s.tx.WithTx(ctx, fn)
+	})
 }
 
 func (s *service) RetryOrder(ctx context.Context, input BuyerRetryInput) (*BuyerRetryResult, error) {
@@ -502,7 +720,7 @@ func (s *service) RetryOrder(ctx context.Context, input BuyerRetryInput) (*Buyer
 	}
 
 	var result *BuyerRetryResult
-	err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+	err := s.withTxRetry(ctx, func(tx *gorm.DB) error {
 		repo := s.repo.WithTx(tx)
 		order, err := repo.FindVendorOrder(ctx, input.OrderID)
 		if err != nil {
@@ -514,14 +732,34 @@ func (s *service) RetryOrder(ctx context.Context, input BuyerRetryInput) (*Buyer
 		if order.BuyerStoreID != input.ActorStoreID {
 			return pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
 		}
-		if order.Status != enums.VendorOrderStatusExpired {
-			return pkgerrors.New(pkgerrors.CodeStateConflict, "order retry only allowed for expired orders")
+		if !s.isRetryableStatus(order.Status) {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be retried in current state").
+				WithDetails(map[string]any{"status": order.Status})
 		}
 
 		items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
 		if err != nil {
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load order line items")
 		}
+		if order.Status == enums.VendorOrderStatusRejected {
+			// A vendor-rejected order can still hold stock for lines that were never rejected
+			// individually. Hand that stock back first so the retry reserves it afresh instead of
+			// counting it twice.
+			actor := inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID)
+			for _, item := range items {
+				if item.Status == enums.LineItemStatusRejected {
+					continue
+				}
+				if holdsReservation(order) {
+					if err := releaseLineItem(item, s.inventory, actor, ctx, tx); err != nil {
+						return err
+					}
+				}
+				if err := repo.UpdateOrderLineItemStatus(ctx, item.ID, enums.LineItemStatusRejected, nil); err != nil {
+					return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update line item status")
+				}
+			}
+		}
 		requests := make([]reservation.InventoryReservationRequest, 0, len(items))
 		for _, item := range items {
 			if item.ProductID != nil && item.Qty > 0 {
@@ -529,6 +767,7 @@ func (s *service) RetryOrder(ctx context.Context, input BuyerRetryInput) (*Buyer
 					CartItemID: item.ID,
 					ProductID:  *item.ProductID,
 					Qty:        item.Qty,
+					Actor:      inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID),
 				})
 			}
 		}
@@ -642,6 +881,123 @@ func (s *service) RetryOrder(ctx context.Context, input BuyerRetryInput) (*Buyer
 	return result, nil
 }
 
+// ClaimOrder assigns a ready-for-dispatch order to the calling agent. The assignment is a
+// conditional insert, so when two agents race for the same order exactly one wins and the
+// other receives CodeStateConflict.
+func (s *service) ClaimOrder(ctx context.Context, input AgentClaimInput) (*OrderAssignmentSummary, error) {
+	if input.OrderID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.AgentUserID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "agent identity missing")
+	}
+
+	var summary *OrderAssignmentSummary
+	err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, err := repo.FindVendorOrder(ctx, input.OrderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+		}
+		if order.Status != enums.VendorOrderStatusReadyForDispatch {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be claimed in current state")
+		}
+
+		agentID := input.AgentUserID
+		assignment := &models.OrderAssignment{
+			OrderID:          order.ID,
+			AgentUserID:      agentID,
+			AssignedByUserID: &agentID,
+		}
+		claimed, err := repo.CreateOrderAssignmentIfUnassigned(ctx, assignment)
+		if err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create order assignment")
+		}
+		if !claimed {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "order already claimed")
+		}
+
+		event := outbox.DomainEvent{
+			EventType:     enums.EventNotificationRequested,
+			AggregateType: enums.AggregateVendorOrder,
+			AggregateID:   order.ID,
+			Version:       1,
+			Actor:         buildActor(agentID, uuid.Nil, "agent"),
+			Data: payloads.NotificationRequestedEvent{
+				OrderID:         order.ID,
+				CheckoutGroupID: order.CheckoutGroupID,
+				BuyerStoreID:    order.BuyerStoreID,
+				VendorStoreID:   order.VendorStoreID,
+				OrderReference:  orderReference(order),
+				Type:            "order_assigned",
+			},
+		}
+		if err := s.outbox.Emit(ctx, tx, event); err != nil {
+			return err
+		}
+
+		summary = buildAssignmentSummary(assignment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// SchedulePickup records when the vendor expects the order to be ready so agents browsing the
+// dispatch queue do not arrive early. The order stays claimable; the window is advisory.
+func (s *service) SchedulePickup(ctx context.Context, input SchedulePickupInput) error {
+	if input.OrderID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.ActorUserID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+	}
+	if input.ActorStoreID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+	}
+	start := input.Window.Start.UTC()
+	end := input.Window.End.UTC()
+	if start.IsZero() || end.IsZero() {
+		return pkgerrors.New(pkgerrors.CodeValidation, "pickup window start and end required")
+	}
+	if !start.After(time.Now().UTC()) {
+		return pkgerrors.New(pkgerrors.CodeValidation, "pickup window must start in the future")
+	}
+	if !end.After(start) {
+		return pkgerrors.New(pkgerrors.CodeValidation, "pickup window end must be after start")
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, err := repo.FindVendorOrder(ctx, input.OrderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+		}
+		if order.VendorStoreID != input.ActorStoreID {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
+		}
+		if !isPickupSchedulable(order.Status) {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "pickup cannot be scheduled in current state")
+		}
+
+		if err := repo.UpdateVendorOrder(ctx, order.ID, map[string]any{
+			"pickup_window_start": start,
+			"pickup_window_end":   end,
+		}); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update pickup window")
+		}
+		return nil
+	})
+}
+
 func (s *service) AgentPickup(ctx context.Context, input AgentPickupInput) error {
 	if input.OrderID == uuid.Nil {
 		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
@@ -757,7 +1113,7 @@ func (s *service) AgentDeliver(ctx context.Context, input AgentDeliverInput) err
 			}
 		}
 
-		return nil
+		return s.recordAgentEarning(ctx, input.OrderID, input.AgentUserID, detail)
 	})
 }
 
@@ -867,6 +1223,7 @@ func (s *service) AgentCashCollected(ctx context.Context, input AgentCashCollect
 				BuyerStoreID:    detail.BuyerStore.ID,
 				VendorStoreID:   detail.VendorStore.ID,
 				AmountCents:     amount,
+				Amount:          money.New(amount, detail.Order.Currency),
 				CashCollectedAt: collectionTime,
 			},
 			OccurredAt: collectionTime,
@@ -887,10 +1244,35 @@ func (s *service) AgentCashCollected(ctx context.Context, input AgentCashCollect
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "append ledger event")
 		}
 
-		return nil
+		return s.recordAgentEarning(ctx, input.OrderID, input.AgentUserID, detail)
 	})
 }
 
+// recordAgentEarning books the delivering agent's fee once per order. Both delivery and cash
+// collection call it so the agent is paid whichever handoff is confirmed first.
+func (s *service) recordAgentEarning(ctx context.Context, orderID, agentUserID uuid.UUID, detail *OrderDetail) error {
+	has, err := s.ledger.HasEvent(ctx, orderID, enums.LedgerEventTypeAgentDeliveryFee)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check agent earning")
+	}
+	if has {
+		return nil
+	}
+	if detail.BuyerStore.ID == uuid.Nil || detail.VendorStore.ID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeDependency, "order stores missing")
+	}
+	if _, err := s.ledger.RecordAgentEarning(ctx, ledger.AgentEarningInput{
+		OrderID:         orderID,
+		BuyerStoreID:    detail.BuyerStore.ID,
+		VendorStoreID:   detail.VendorStore.ID,
+		AgentUserID:     agentUserID,
+		OrderTotalCents: detail.Order.TotalCents,
+	}); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "append agent earning")
+	}
+	return nil
+}
+
 func (s *service) failCashCollection(ctx context.Context, tx *gorm.DB, repo Repository, orderID, paymentIntentID uuid.UUID, actor *outbox.ActorRef, reason string) error {
 	paymentUpdates := map[string]any{
 		"status":         enums.PaymentStatusFailed,
@@ -947,6 +1329,26 @@ func mapDecisionToStatus(decision enums.VendorOrderDecision) (enums.VendorOrderS
 	}
 }
 
+// recomputeOrderTotals sums the non-rejected line items and carries over the order's existing
+// fees (total minus subtotal) so tax and transport survive line item changes.
+func recomputeOrderTotals(order *models.VendorOrder, items []models.OrderLineItem) (subtotal, total int) {
+	for _, item := range items {
+		if item.Status == enums.LineItemStatusRejected {
+			continue
+		}
+		subtotal += item.TotalCents
+	}
+	diff := order.TotalCents - order.SubtotalCents
+	if diff < 0 {
+		diff = 0
+	}
+	total = subtotal + diff
+	if total < 0 {
+		total = 0
+	}
+	return subtotal, total
+}
+
 func mapLineItemDecision(decision LineItemDecision) (enums.LineItemStatus, error) {
 	switch decision {
 	case LineItemDecisionFulfill:
@@ -980,19 +1382,63 @@ func buildActor(userID, storeID uuid.UUID, role string) *outbox.ActorRef {
 	}
 }
 
-func releaseLineItem(item models.OrderLineItem, releaser InventoryReleaser, ctx context.Context, tx *gorm.DB) error {
+// holdsReservation reports whether the order's open line items still have stock reserved. The cron
+// worker hands stock back early for pending orders nobody acted on within the reservation TTL, and
+// marks them with reservation_released_at so no later path releases the same stock again.
+func holdsReservation(order *models.VendorOrder) bool {
+	return order.ReservationReleasedAt == nil
+}
+
+// reacquireReservation reserves stock again for an order whose reservation lapsed, so a vendor
+// accepting it late never ships stock that was already handed back.
+func (s *service) reacquireReservation(ctx context.Context, tx *gorm.DB, repo Repository, order *models.VendorOrder, actor inventory.AdjustmentActor) error {
+	items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load order line items")
+	}
+	requests := make([]reservation.InventoryReservationRequest, 0, len(items))
+	for _, item := range items {
+		if item.Status == enums.LineItemStatusRejected || item.ProductID == nil || item.Qty <= 0 {
+			continue
+		}
+		requests = append(requests, reservation.InventoryReservationRequest{
+			CartItemID: item.ID,
+			ProductID:  *item.ProductID,
+			Qty:        item.Qty,
+			Actor:      actor,
+		})
+	}
+	if len(requests) > 0 {
+		reserved, err := s.reserver.Reserve(ctx, tx, requests)
+		if err != nil {
+			return err
+		}
+		for _, res := range reserved {
+			if !res.Reserved {
+				return pkgerrors.New(pkgerrors.CodeConflict, "reserved stock was released and is no longer available")
+			}
+		}
+	}
+	if err := repo.UpdateVendorOrder(ctx, order.ID, map[string]any{"reservation_released_at": nil}); err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "clear reservation release")
+	}
+	order.ReservationReleasedAt = nil
+	return nil
+}
+
+func releaseLineItem(item models.OrderLineItem, releaser InventoryReleaser, actor inventory.AdjustmentActor, ctx context.Context, tx *gorm.DB) error {
 	if item.ProductID == nil || item.Qty <= 0 {
 		return nil
 	}
-	if err := releaser.Release(ctx, tx, *item.ProductID, item.Qty); err != nil {
+	if err := releaser.Release(ctx, tx, *item.ProductID, item.Qty, actor); err != nil {
 		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "release inventory")
 	}
 	return nil
 }
 
 // ReleaseLineItemInventory exposes the shared inventory release helper.
-func ReleaseLineItemInventory(ctx context.Context, tx *gorm.DB, item models.OrderLineItem, releaser InventoryReleaser) error {
-	return releaseLineItem(item, releaser, ctx, tx)
+func ReleaseLineItemInventory(ctx context.Context, tx *gorm.DB, item models.OrderLineItem, releaser InventoryReleaser, actor inventory.AdjustmentActor) error {
+	return releaseLineItem(item, releaser, actor, ctx, tx)
 }
 
 func (s *service) ConfirmPayout(ctx context.Context, input ConfirmPayoutInput) error {
@@ -1063,14 +1509,16 @@ func (s *service) ConfirmPayout(ctx context.Context, input ConfirmPayoutInput) e
 			EventType:     enums.EventOrderPaid,
 			AggregateType: enums.AggregateVendorOrder,
 			AggregateID:   input.OrderID,
-			Version:       1,
+			Version:       payloads.OrderPaidEventVersion,
 			Actor:         buildActor(input.ActorUserID, input.ActorStoreID, input.ActorRole),
+			DedupKey:      outbox.DedupKey(enums.EventOrderPaid, input.OrderID),
 			Data: payloads.OrderPaidEvent{
 				OrderID:         input.OrderID,
 				BuyerStoreID:    detail.BuyerStore.ID,
 				VendorStoreID:   detail.VendorStore.ID,
 				PaymentIntentID: detail.PaymentIntent.ID,
 				AmountCents:     detail.PaymentIntent.AmountCents,
+				Amount:          money.New(detail.PaymentIntent.AmountCents, detail.Order.Currency),
 				VendorPaidAt:    now,
 			},
 		}
@@ -1095,14 +1543,49 @@ func isFinalOrderStatus(status enums.VendorOrderStatus) bool {
 	}
 }
 
-type inventoryReleaserImpl struct{}
+// isPostAcceptanceStatus reports whether the vendor has accepted the order and started working on it.
+func isPostAcceptanceStatus(status enums.VendorOrderStatus) bool {
+	switch status {
+	case enums.VendorOrderStatusAccepted,
+		enums.VendorOrderStatusPartiallyAccepted,
+		enums.VendorOrderStatusFulfilled,
+		enums.VendorOrderStatusReadyForDispatch,
+		enums.VendorOrderStatusHold,
+		enums.VendorOrderStatusHoldForPickup:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPickupSchedulable reports whether the vendor can still set a ready time: the order was
+// accepted and no agent has picked it up yet.
+func isPickupSchedulable(status enums.VendorOrderStatus) bool {
+	switch status {
+	case enums.VendorOrderStatusAccepted,
+		enums.VendorOrderStatusPartiallyAccepted,
+		enums.VendorOrderStatusReadyForDispatch,
+		enums.VendorOrderStatusHoldForPickup:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxReleaseAttempts bounds how often a release re-reads the inventory row after losing a version race.
+const maxReleaseAttempts = 3
 
-// NewInventoryReleaser exposes the default inventory release implementation.
-func NewInventoryReleaser() InventoryReleaser {
-	return inventoryReleaserImpl{}
+type inventoryReleaserImpl struct {
+	outbox inventory.OutboxPublisher
 }
 
-func (inventoryReleaserImpl) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int) error {
+// NewInventoryReleaser exposes the default inventory release implementation, queueing
+// inventory_changed events through the shared outbox publisher.
+func NewInventoryReleaser(outbox inventory.OutboxPublisher) InventoryReleaser {
+	return inventoryReleaserImpl{outbox: outbox}
+}
+
+func (r inventoryReleaserImpl) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int, actor inventory.AdjustmentActor) error {
 	if qty <= 0 {
 		return nil
 	}
@@ -1110,29 +1593,47 @@ func (inventoryReleaserImpl) Release(ctx context.Context, tx *gorm.DB, productID
 		return pkgerrors.New(pkgerrors.CodeDependency, "transaction required for inventory release")
 	}
 
-	res := tx.WithContext(ctx).Exec(`
+	db := tx.WithContext(ctx)
+	for attempt := 1; attempt <= maxReleaseAttempts; attempt++ {
+		item, err := reservation.LoadInventory(db, productID)
+		if err != nil {
+			return err
+		}
+		if item == nil || item.ReservedQty < qty {
+			return nil
+		}
+
+		res := db.Exec(`
 		UPDATE inventory_items
 		SET available_qty = available_qty + ?,
 			reserved_qty = reserved_qty - ?,
+			version = version + 1,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE product_id = ? AND reserved_qty >= ?
-	`, qty, qty, productID, qty)
-	if res.Error != nil {
-		return pkgerrors.Wrap(pkgerrors.CodeDependency, res.Error, "release inventory")
+		WHERE product_id = ? AND version = ? AND reserved_qty >= ?
+	`, qty, qty, productID, item.Version, qty)
+		if res.Error != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, res.Error, "release inventory")
+		}
+		if res.RowsAffected > 0 {
+			return inventory.RecordAdjustment(ctx, tx, r.outbox, productID, qty, -qty, enums.InventoryAdjustmentReasonOrderRelease, actor)
+		}
 	}
-	return nil
+	return inventory.NewVersionConflictError(productID)
 }
 
-type inventoryReserverImpl struct{}
+type inventoryReserverImpl struct {
+	outbox inventory.OutboxPublisher
+}
 
-// NewInventoryReserver exposes the default inventory reservation helper.
-func NewInventoryReserver() inventoryReserver {
-	return inventoryReserverImpl{}
+// NewInventoryReserver exposes the default inventory reservation helper, queueing
+// inventory_changed events through the shared outbox publisher.
+func NewInventoryReserver(outbox inventory.OutboxPublisher) inventoryReserver {
+	return inventoryReserverImpl{outbox: outbox}
 }
 
-func (inventoryReserverImpl) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
+func (r inventoryReserverImpl) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
 	if len(requests) == 0 {
 		return nil, nil
 	}
-	return reservation.ReserveInventory(ctx, tx, requests)
+	return reservation.ReserveInventory(ctx, tx, r.outbox, requests)
 }
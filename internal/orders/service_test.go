@@ -2,15 +2,19 @@ package orders
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/ledger"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
@@ -30,7 +34,10 @@ type stubOrdersRepo struct {
 	findPaymentIntent    func(ctx context.Context, orderID uuid.UUID) (*models.PaymentIntent, error)
 	findOrderDetail      func(ctx context.Context, orderID uuid.UUID) (*OrderDetail, error)
 	updateAssignment     func(ctx context.Context, assignmentID uuid.UUID, updates map[string]any) error
+	createAssignment     func(ctx context.Context, assignment *models.OrderAssignment) (bool, error)
 	updatePaymentIntent  func(ctx context.Context, orderID uuid.UUID, updates map[string]any) error
+	products             map[uuid.UUID]*models.Product
+	comments             []models.OrderComment
 }
 
 // HasBuyerStorePurchasedFromVendor implements [Repository].
@@ -129,7 +136,11 @@ func (s *stubOrdersRepo) ListPayoutOrders(ctx context.Context, params pagination
 	return &PayoutOrderList{}, nil
 }
 
-func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params) (*AgentOrderQueueList, error) {
+func (s *stubOrdersRepo) StreamAccountingExport(ctx context.Context, from, to time.Time, fn func(AccountingExportLine) error) error {
+	return nil
+}
+
+func (s *stubOrdersRepo) ListUnassignedHoldOrders(ctx context.Context, params pagination.Params, filters AgentQueueFilters) (*AgentOrderQueueList, error) {
 	return &AgentOrderQueueList{}, nil
 }
 
@@ -156,6 +167,14 @@ func (s *stubOrdersRepo) UpdateVendorOrderStatus(ctx context.Context, orderID uu
 	return nil
 }
 
+func (s *stubOrdersRepo) MarkReservationReleased(ctx context.Context, orderID uuid.UUID, at time.Time) (bool, error) {
+	if s.order == nil || s.order.ID != orderID || s.order.ReservationReleasedAt != nil {
+		return false, nil
+	}
+	s.order.ReservationReleasedAt = &at
+	return true, nil
+}
+
 func (s *stubOrdersRepo) FindOrderLineItem(ctx context.Context, lineItemID uuid.UUID) (*models.OrderLineItem, error) {
 	if s.lineItems == nil {
 		return nil, gorm.ErrRecordNotFound
@@ -180,6 +199,76 @@ func (s *stubOrdersRepo) UpdateOrderLineItemStatus(ctx context.Context, lineItem
 	return nil
 }
 
+func (s *stubOrdersRepo) UpdateOrderLineItem(ctx context.Context, lineItemID uuid.UUID, updates map[string]any) error {
+	item, ok := s.lineItems[lineItemID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for key, value := range updates {
+		switch key {
+		case "status":
+			item.Status = value.(enums.LineItemStatus)
+		case "substitute_product_id":
+			if v, ok := value.(uuid.UUID); ok {
+				item.SubstituteProductID = &v
+			} else {
+				item.SubstituteProductID = nil
+			}
+		case "substitute_qty":
+			if v, ok := value.(int); ok {
+				item.SubstituteQty = &v
+			} else {
+				item.SubstituteQty = nil
+			}
+		case "substitute_unit_price_cents":
+			if v, ok := value.(int); ok {
+				item.SubstituteUnitPriceCents = &v
+			} else {
+				item.SubstituteUnitPriceCents = nil
+			}
+		case "product_id":
+			v := value.(uuid.UUID)
+			item.ProductID = &v
+		case "name":
+			item.Name = value.(string)
+		case "qty":
+			item.Qty = value.(int)
+		case "unit_price_cents":
+			item.UnitPriceCents = value.(int)
+		case "total_cents":
+			item.TotalCents = value.(int)
+		}
+	}
+	return nil
+}
+
+func (s *stubOrdersRepo) FindProduct(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	product, ok := s.products[productID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return product, nil
+}
+
+func (s *stubOrdersRepo) CreateOrderComment(ctx context.Context, comment *models.OrderComment) error {
+	s.comments = append(s.comments, *comment)
+	return nil
+}
+
+func (s *stubOrdersRepo) ListOrderComments(ctx context.Context, orderID uuid.UUID, includeInternal bool) ([]models.OrderComment, error) {
+	var out []models.OrderComment
+	for _, comment := range s.comments {
+		if comment.OrderID != orderID {
+			continue
+		}
+		if !includeInternal && comment.Visibility != enums.OrderCommentVisibilityShared {
+			continue
+		}
+		out = append(out, comment)
+	}
+	return out, nil
+}
+
 func (s *stubOrdersRepo) UpdateVendorOrder(ctx context.Context, orderID uuid.UUID, updates map[string]any) error {
 	s.orderUpdates = updates
 	if s.order == nil || s.order.ID != orderID {
@@ -207,6 +296,12 @@ func (s *stubOrdersRepo) UpdateVendorOrder(ctx context.Context, orderID uuid.UUI
 			if v, ok := value.(enums.VendorOrderStatus); ok {
 				s.order.Status = v
 			}
+		case "cancel_requested_at":
+			if v, ok := value.(time.Time); ok {
+				s.order.CancelRequestedAt = &v
+			} else {
+				s.order.CancelRequestedAt = nil
+			}
 		}
 	}
 	return nil
@@ -227,9 +322,17 @@ func (s *stubOrdersRepo) UpdateOrderAssignment(ctx context.Context, assignmentID
 	return nil
 }
 
+func (s *stubOrdersRepo) CreateOrderAssignmentIfUnassigned(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+	if s.createAssignment != nil {
+		return s.createAssignment(ctx, assignment)
+	}
+	return true, nil
+}
+
 type stubLedgerService struct {
-	recordFn func(ctx context.Context, input ledger.RecordLedgerEventInput) (*models.LedgerEvent, error)
-	hasFn    func(ctx context.Context, orderID uuid.UUID, eventType enums.LedgerEventType) (bool, error)
+	recordFn       func(ctx context.Context, input ledger.RecordLedgerEventInput) (*models.LedgerEvent, error)
+	hasFn          func(ctx context.Context, orderID uuid.UUID, eventType enums.LedgerEventType) (bool, error)
+	agentEarningFn func(ctx context.Context, input ledger.AgentEarningInput) (*models.LedgerEvent, error)
 }
 
 func (s *stubLedgerService) RecordEvent(ctx context.Context, input ledger.RecordLedgerEventInput) (*models.LedgerEvent, error) {
@@ -246,6 +349,25 @@ func (s *stubLedgerService) HasEvent(ctx context.Context, orderID uuid.UUID, eve
 	return false, nil
 }
 
+func (s *stubLedgerService) RecordAgentEarning(ctx context.Context, input ledger.AgentEarningInput) (*models.LedgerEvent, error) {
+	if s.agentEarningFn != nil {
+		return s.agentEarningFn(ctx, input)
+	}
+	return &models.LedgerEvent{ID: uuid.New()}, nil
+}
+
+func (s *stubLedgerService) AgentEarnings(ctx context.Context, agentUserID uuid.UUID, from, to time.Time) (*ledger.AgentEarningsSummary, error) {
+	return &ledger.AgentEarningsSummary{AgentUserID: agentUserID, From: from, To: to}, nil
+}
+
+func (s *stubLedgerService) AgentCashReconciliation(ctx context.Context, agentUserID uuid.UUID) (*ledger.AgentCashReconciliation, error) {
+	return &ledger.AgentCashReconciliation{AgentUserID: agentUserID}, nil
+}
+
+func (s *stubLedgerService) RecordRemittance(ctx context.Context, input ledger.RecordRemittanceInput) ([]ledger.RemittedCash, error) {
+	return nil, nil
+}
+
 func newStubLedgerService(recordFn func(ctx context.Context, input ledger.RecordLedgerEventInput) (*models.LedgerEvent, error), hasFn func(ctx context.Context, orderID uuid.UUID, eventType enums.LedgerEventType) (bool, error)) *stubLedgerService {
 	return &stubLedgerService{
 		recordFn: recordFn,
@@ -282,7 +404,7 @@ type stubInventoryReleaser struct {
 	err   error
 }
 
-func (s *stubInventoryReleaser) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int) error {
+func (s *stubInventoryReleaser) Release(ctx context.Context, tx *gorm.DB, productID uuid.UUID, qty int, actor inventory.AdjustmentActor) error {
 	if s.err != nil {
 		return s.err
 	}
@@ -291,8 +413,9 @@ func (s *stubInventoryReleaser) Release(ctx context.Context, tx *gorm.DB, produc
 }
 
 type stubInventoryReserver struct {
-	calls []reservation.InventoryReservationRequest
-	err   error
+	calls       []reservation.InventoryReservationRequest
+	err         error
+	unavailable bool
 }
 
 func (s *stubInventoryReserver) Reserve(ctx context.Context, tx *gorm.DB, requests []reservation.InventoryReservationRequest) ([]reservation.InventoryReservationResult, error) {
@@ -306,7 +429,7 @@ func (s *stubInventoryReserver) Reserve(ctx context.Context, tx *gorm.DB, reques
 			CartItemID: req.CartItemID,
 			ProductID:  req.ProductID,
 			Qty:        req.Qty,
-			Reserved:   true,
+			Reserved:   !s.unavailable,
 		}
 	}
 	return results, nil
@@ -358,6 +481,141 @@ func TestVendorDecision(t *testing.T) {
 	if outbox.event.EventType != enums.EventOrderDecided {
 		t.Fatalf("unexpected event type %s", outbox.event.EventType)
 	}
+	if want := "order_decided:" + orderID.String(); outbox.event.DedupKey != want {
+		t.Fatalf("expected dedup key %s, got %q", want, outbox.event.DedupKey)
+	}
+}
+
+func TestVendorDecisionAcceptReacquiresLapsedReservation(t *testing.T) {
+	orderID := uuid.New()
+	storeID := uuid.New()
+	productID := uuid.New()
+	releasedAt := time.Now().Add(-time.Hour)
+	lineID := uuid.New()
+	rejectedID := uuid.New()
+	rejectedProductID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:                    orderID,
+			VendorStoreID:         storeID,
+			BuyerStoreID:          uuid.New(),
+			Status:                enums.VendorOrderStatusCreatedPending,
+			ReservationReleasedAt: &releasedAt,
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			lineID:     {ID: lineID, OrderID: orderID, ProductID: &productID, Qty: 2, Status: enums.LineItemStatusPending},
+			rejectedID: {ID: rejectedID, OrderID: orderID, ProductID: &rejectedProductID, Qty: 5, Status: enums.LineItemStatusRejected},
+		},
+	}
+	reserver := &stubInventoryReserver{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, reserver)
+	if err != nil {
+		t.Fatalf("service constructor failed: %v", err)
+	}
+
+	err = svc.VendorDecision(context.Background(), VendorDecisionInput{
+		OrderID:      orderID,
+		Decision:     enums.VendorOrderDecisionAccept,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: storeID,
+	})
+	if err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if len(reserver.calls) != 1 || reserver.calls[0].ProductID != productID || reserver.calls[0].Qty != 2 {
+		t.Fatalf("expected the open line re-reserved, got %+v", reserver.calls)
+	}
+	if _, ok := repo.orderUpdates["reservation_released_at"]; !ok || repo.order.ReservationReleasedAt != nil {
+		t.Fatalf("expected reservation_released_at cleared, got %v", repo.orderUpdates)
+	}
+	if repo.updatedStatus != enums.VendorOrderStatusAccepted {
+		t.Fatalf("expected status accepted got %s", repo.updatedStatus)
+	}
+}
+
+func TestVendorDecisionAcceptWaitsForBusinessHours(t *testing.T) {
+	orderID := uuid.New()
+	storeID := uuid.New()
+	opensAt := time.Now().Add(2 * time.Hour)
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:            orderID,
+			VendorStoreID: storeID,
+			BuyerStoreID:  uuid.New(),
+			Status:        enums.VendorOrderStatusCreatedPending,
+			OpensAt:       &opensAt,
+		},
+	}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("service constructor failed: %v", err)
+	}
+
+	err = svc.VendorDecision(context.Background(), VendorDecisionInput{
+		OrderID:      orderID,
+		Decision:     enums.VendorOrderDecisionAccept,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: storeID,
+	})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict before opening, got %v", err)
+	}
+	if repo.updatedStatus != "" {
+		t.Fatalf("expected no status change, got %s", repo.updatedStatus)
+	}
+
+	opened := time.Now().Add(-time.Minute)
+	repo.order.OpensAt = &opened
+	if err := svc.VendorDecision(context.Background(), VendorDecisionInput{
+		OrderID:      orderID,
+		Decision:     enums.VendorOrderDecisionAccept,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: storeID,
+	}); err != nil {
+		t.Fatalf("expected accept after opening, got %v", err)
+	}
+	if repo.updatedStatus != enums.VendorOrderStatusAccepted {
+		t.Fatalf("expected status accepted got %s", repo.updatedStatus)
+	}
+}
+
+func TestCancelOrderSkipsReleaseForLapsedReservation(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	releasedAt := time.Now().Add(-time.Hour)
+	lineID := uuid.New()
+	productID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:                    orderID,
+			BuyerStoreID:          buyerStore,
+			VendorStoreID:         uuid.New(),
+			Status:                enums.VendorOrderStatusCreatedPending,
+			ReservationReleasedAt: &releasedAt,
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			lineID: {ID: lineID, OrderID: orderID, ProductID: &productID, Qty: 2, Status: enums.LineItemStatusPending},
+		},
+	}
+	releaser := &stubInventoryReleaser{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, releaser, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("service constructor failed: %v", err)
+	}
+
+	result, err := svc.CancelOrder(context.Background(), BuyerCancelInput{OrderID: orderID, ActorUserID: uuid.New(), ActorStoreID: buyerStore})
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if result.Outcome != BuyerCancelOutcomeCanceled {
+		t.Fatalf("expected canceled outcome got %s", result.Outcome)
+	}
+	if len(releaser.calls) != 0 {
+		t.Fatalf("expected no release for stock the cron worker already handed back, got %+v", releaser.calls)
+	}
+	if repo.lineItems[lineID].Status != enums.LineItemStatusRejected {
+		t.Fatalf("expected line rejected, got %s", repo.lineItems[lineID].Status)
+	}
 }
 
 func TestVendorDecisionIdempotent(t *testing.T) {
@@ -418,6 +676,8 @@ func TestVendorDecisionInvalidState(t *testing.T) {
 }
 
 func TestCancelOrderReleasesInventory(t *testing.T) {
+	// Accepted a minute ago, well inside the default cancel window.
+	acceptedAt := time.Now().Add(-time.Minute)
 	orderID := uuid.New()
 	buyerStore := uuid.New()
 	vendorStore := uuid.New()
@@ -430,6 +690,7 @@ func TestCancelOrderReleasesInventory(t *testing.T) {
 			VendorStoreID:   vendorStore,
 			CheckoutGroupID: uuid.New(),
 			Status:          enums.VendorOrderStatusAccepted,
+			AcceptedAt:      &acceptedAt,
 		},
 		lineItems: map[uuid.UUID]*models.OrderLineItem{
 			lineItemID: {
@@ -449,7 +710,7 @@ func TestCancelOrderReleasesInventory(t *testing.T) {
 		t.Fatalf("construct service: %v", err)
 	}
 
-	err = svc.CancelOrder(context.Background(), BuyerCancelInput{
+	result, err := svc.CancelOrder(context.Background(), BuyerCancelInput{
 		OrderID:      orderID,
 		ActorUserID:  uuid.New(),
 		ActorStoreID: buyerStore,
@@ -458,6 +719,9 @@ func TestCancelOrderReleasesInventory(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected success got %v", err)
 	}
+	if result == nil || result.Outcome != BuyerCancelOutcomeCanceled {
+		t.Fatalf("expected canceled outcome got %+v", result)
+	}
 	if len(inventory.calls) != 1 {
 		t.Fatalf("expected inventory release called got %d", len(inventory.calls))
 	}
@@ -472,6 +736,213 @@ func TestCancelOrderReleasesInventory(t *testing.T) {
 	}
 }
 
+func TestCancelOrderOutsideWindowRequestsVendorApproval(t *testing.T) {
+	acceptedAt := time.Now().Add(-time.Hour)
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	productID := uuid.New()
+	lineItemID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              orderID,
+			BuyerStoreID:    buyerStore,
+			VendorStoreID:   uuid.New(),
+			CheckoutGroupID: uuid.New(),
+			Status:          enums.VendorOrderStatusAccepted,
+			AcceptedAt:      &acceptedAt,
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			lineItemID: {
+				ID:        lineItemID,
+				OrderID:   orderID,
+				ProductID: &productID,
+				Qty:       3,
+				Status:    enums.LineItemStatusPending,
+			},
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	inventory := &stubInventoryReleaser{}
+	svc, err := NewService(repo, stubTxRunner{}, outbox, inventory, &stubInventoryReserver{}, newStubLedgerService(nil, nil), WithCancelWindow(30*time.Minute))
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	result, err := svc.CancelOrder(context.Background(), BuyerCancelInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: buyerStore,
+		ActorRole:    "owner",
+	})
+	if err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if result == nil || result.Outcome != BuyerCancelOutcomeRequested {
+		t.Fatalf("expected cancel_requested outcome got %+v", result)
+	}
+	if len(inventory.calls) != 0 {
+		t.Fatalf("expected no inventory release got %d", len(inventory.calls))
+	}
+	if _, ok := repo.orderUpdates["status"]; ok || len(repo.orderUpdates) != 1 {
+		t.Fatalf("expected only the cancel request to be recorded got %+v", repo.orderUpdates)
+	}
+	if repo.order.CancelRequestedAt == nil {
+		t.Fatalf("expected cancel request recorded")
+	}
+	if repo.lineItems[lineItemID].Status != enums.LineItemStatusPending {
+		t.Fatalf("expected line item to stay pending got %s", repo.lineItems[lineItemID].Status)
+	}
+	if !outbox.called || outbox.event.EventType != enums.EventNotificationRequested {
+		t.Fatalf("expected notification event got %v", outbox.event.EventType)
+	}
+	payload, ok := outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || payload.Type != "order_cancel_request" {
+		t.Fatalf("expected order_cancel_request notification got %+v", outbox.event.Data)
+	}
+}
+
+func TestApproveCancelRequestCancelsOrder(t *testing.T) {
+	requestedAt := time.Now().Add(-time.Minute)
+	orderID := uuid.New()
+	vendorStore := uuid.New()
+	productID := uuid.New()
+	lineItemID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:                orderID,
+			BuyerStoreID:      uuid.New(),
+			VendorStoreID:     vendorStore,
+			CheckoutGroupID:   uuid.New(),
+			Status:            enums.VendorOrderStatusAccepted,
+			CancelRequestedAt: &requestedAt,
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			lineItemID: {
+				ID:        lineItemID,
+				OrderID:   orderID,
+				ProductID: &productID,
+				Qty:       3,
+				Status:    enums.LineItemStatusPending,
+			},
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	inventory := &stubInventoryReleaser{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, outbox, inventory, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	if err := svc.ApproveCancelRequest(context.Background(), CancelRequestDecisionInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: vendorStore,
+		ActorRole:    "owner",
+	}); err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if repo.order.Status != enums.VendorOrderStatusCanceled {
+		t.Fatalf("expected order canceled got %s", repo.order.Status)
+	}
+	if repo.order.CancelRequestedAt != nil {
+		t.Fatalf("expected cancel request cleared")
+	}
+	if len(inventory.calls) != 1 {
+		t.Fatalf("expected inventory release called got %d", len(inventory.calls))
+	}
+	if repo.lineItems[lineItemID].Status != enums.LineItemStatusRejected {
+		t.Fatalf("expected line item rejected got %s", repo.lineItems[lineItemID].Status)
+	}
+	payload, ok := outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || payload.Type != notificationCancelApproved {
+		t.Fatalf("expected %s notification got %+v", notificationCancelApproved, outbox.event.Data)
+	}
+}
+
+func TestDenyCancelRequestKeepsOrder(t *testing.T) {
+	requestedAt := time.Now().Add(-time.Minute)
+	orderID := uuid.New()
+	vendorStore := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:                orderID,
+			BuyerStoreID:      uuid.New(),
+			VendorStoreID:     vendorStore,
+			CheckoutGroupID:   uuid.New(),
+			Status:            enums.VendorOrderStatusAccepted,
+			CancelRequestedAt: &requestedAt,
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	inventory := &stubInventoryReleaser{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, outbox, inventory, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	input := CancelRequestDecisionInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: vendorStore,
+		ActorRole:    "owner",
+	}
+	if err := svc.DenyCancelRequest(context.Background(), input); err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if repo.order.Status != enums.VendorOrderStatusAccepted {
+		t.Fatalf("expected order to stay accepted got %s", repo.order.Status)
+	}
+	if repo.order.CancelRequestedAt != nil {
+		t.Fatalf("expected cancel request cleared")
+	}
+	if len(inventory.calls) != 0 {
+		t.Fatalf("expected no inventory release got %d", len(inventory.calls))
+	}
+	payload, ok := outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || payload.Type != notificationCancelDenied {
+		t.Fatalf("expected %s notification got %+v", notificationCancelDenied, outbox.event.Data)
+	}
+
+	err = svc.DenyCancelRequest(context.Background(), input)
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict without a pending request got %v", err)
+	}
+}
+
+func TestCancelOrderBeforeAcceptanceIgnoresWindow(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              orderID,
+			BuyerStoreID:    buyerStore,
+			VendorStoreID:   uuid.New(),
+			CheckoutGroupID: uuid.New(),
+			Status:          enums.VendorOrderStatusCreatedPending,
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	svc, err := NewService(repo, stubTxRunner{}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{}, newStubLedgerService(nil, nil), WithCancelWindow(0))
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	result, err := svc.CancelOrder(context.Background(), BuyerCancelInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: buyerStore,
+	})
+	if err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if result == nil || result.Outcome != BuyerCancelOutcomeCanceled {
+		t.Fatalf("expected canceled outcome got %+v", result)
+	}
+	if outbox.event.EventType != enums.EventOrderCanceled {
+		t.Fatalf("expected canceled event got %v", outbox.event.EventType)
+	}
+}
+
 func TestNudgeVendorEmitsNotificationEvent(t *testing.T) {
 	orderID := uuid.New()
 	vendorStore := uuid.New()
@@ -492,17 +963,122 @@ func TestNudgeVendorEmitsNotificationEvent(t *testing.T) {
 		t.Fatalf("construct service: %v", err)
 	}
 
-	err = svc.NudgeVendor(context.Background(), BuyerNudgeInput{
-		OrderID:      orderID,
-		ActorUserID:  uuid.New(),
-		ActorStoreID: vendorStore,
-		ActorRole:    "owner",
-	})
-	if err != nil {
-		t.Fatalf("expected success got %v", err)
+	err = svc.NudgeVendor(context.Background(), BuyerNudgeInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: vendorStore,
+		ActorRole:    "owner",
+	})
+	if err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if !outbox.called || outbox.event.EventType != enums.EventNotificationRequested {
+		t.Fatalf("expected notification event got %v", outbox.event.EventType)
+	}
+}
+
+// stubNudgeLimiter mimics Redis SET NX with expiry against a controllable clock.
+type stubNudgeLimiter struct {
+	now  time.Time
+	keys map[string]time.Time
+}
+
+func (s *stubNudgeLimiter) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	if expires, ok := s.keys[key]; ok && s.now.Before(expires) {
+		return false, nil
+	}
+	s.keys[key] = s.now.Add(ttl)
+	return true, nil
+}
+
+func (s *stubNudgeLimiter) Del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+func (s *stubNudgeLimiter) RateLimitKey(scope string) string {
+	return "pf:rate_limit:" + scope
+}
+
+func TestNudgeVendorEnforcesCooldown(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              orderID,
+			BuyerStoreID:    buyerStore,
+			VendorStoreID:   uuid.New(),
+			CheckoutGroupID: uuid.New(),
+			Status:          enums.VendorOrderStatusAccepted,
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	limiter := &stubNudgeLimiter{now: time.Now(), keys: map[string]time.Time{}}
+	svc, err := NewService(repo, stubTxRunner{}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{}, newStubLedgerService(nil, nil), WithNudgeCooldown(limiter, time.Hour))
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+	input := BuyerNudgeInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: buyerStore,
+		ActorRole:    "owner",
+	}
+
+	if err := svc.NudgeVendor(context.Background(), input); err != nil {
+		t.Fatalf("first nudge: %v", err)
+	}
+	if !outbox.called {
+		t.Fatalf("expected first nudge to emit a notification")
+	}
+
+	outbox.called = false
+	limiter.now = limiter.now.Add(30 * time.Minute)
+	err = svc.NudgeVendor(context.Background(), input)
+	if err == nil {
+		t.Fatal("expected second nudge inside the cooldown to be rejected")
+	}
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeRateLimit {
+		t.Fatalf("expected rate limit error got %v", err)
+	}
+	if outbox.called {
+		t.Fatalf("expected suppressed nudge not to emit")
+	}
+
+	limiter.now = limiter.now.Add(31 * time.Minute)
+	if err := svc.NudgeVendor(context.Background(), input); err != nil {
+		t.Fatalf("nudge after cooldown: %v", err)
+	}
+	if !outbox.called {
+		t.Fatalf("expected nudge after cooldown to emit a notification")
+	}
+}
+
+func TestNudgeVendorReleasesCooldownWhenEmitFails(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:           orderID,
+			BuyerStoreID: buyerStore,
+			Status:       enums.VendorOrderStatusCreatedPending,
+		},
+	}
+	outbox := &stubOutboxPublisher{err: errors.New("outbox down")}
+	limiter := &stubNudgeLimiter{now: time.Now(), keys: map[string]time.Time{}}
+	svc, err := NewService(repo, stubTxRunner{}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{}, newStubLedgerService(nil, nil), WithNudgeCooldown(limiter, time.Hour))
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+	input := BuyerNudgeInput{OrderID: orderID, ActorUserID: uuid.New(), ActorStoreID: buyerStore}
+
+	if err := svc.NudgeVendor(context.Background(), input); err == nil {
+		t.Fatal("expected emit failure to surface")
 	}
-	if !outbox.called || outbox.event.EventType != enums.EventNotificationRequested {
-		t.Fatalf("expected notification event got %v", outbox.event.EventType)
+	if len(limiter.keys) != 0 {
+		t.Fatalf("expected cooldown to be released, got %v", limiter.keys)
 	}
 }
 
@@ -591,6 +1167,148 @@ func TestRetryOrderCreatesNewOrder(t *testing.T) {
 	}
 }
 
+func newRejectedRetryRepo(orderID, buyerStore, productID uuid.UUID, lineStatus enums.LineItemStatus) (*stubOrdersRepo, *[]models.OrderLineItem) {
+	lineItemID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              orderID,
+			BuyerStoreID:    buyerStore,
+			VendorStoreID:   uuid.New(),
+			SubtotalCents:   1500,
+			TotalCents:      1500,
+			Status:          enums.VendorOrderStatusRejected,
+			CheckoutGroupID: uuid.New(),
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			lineItemID: {
+				ID:         lineItemID,
+				OrderID:    orderID,
+				ProductID:  &productID,
+				Qty:        3,
+				TotalCents: 1500,
+				Status:     lineStatus,
+			},
+		},
+		findPaymentIntent: func(ctx context.Context, orderID uuid.UUID) (*models.PaymentIntent, error) {
+			return &models.PaymentIntent{Method: enums.PaymentMethodCash}, nil
+		},
+	}
+	repo.createVendorOrder = func(ctx context.Context, order *models.VendorOrder) (*models.VendorOrder, error) {
+		order.ID = uuid.New()
+		return order, nil
+	}
+	created := make([]models.OrderLineItem, 0)
+	repo.createOrderLineItems = func(ctx context.Context, items []models.OrderLineItem) error {
+		created = append(created, items...)
+		return nil
+	}
+	return repo, &created
+}
+
+func TestRetryOrderReservesRejectedOrderNowInStock(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	productID := uuid.New()
+	// Checkout rejected the order because nothing could be reserved; the stock has since returned.
+	repo, created := newRejectedRetryRepo(orderID, buyerStore, productID, enums.LineItemStatusRejected)
+	outbox := &stubOutboxPublisher{}
+	releaser := &stubInventoryReleaser{}
+	reserver := &stubInventoryReserver{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, outbox, releaser, reserver)
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	result, err := svc.RetryOrder(context.Background(), BuyerRetryInput{
+		OrderID:      orderID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: buyerStore,
+		ActorRole:    "owner",
+	})
+	if err != nil {
+		t.Fatalf("expected rejected order retry to succeed got %v", err)
+	}
+	if result == nil || result.OrderID == uuid.Nil {
+		t.Fatalf("unexpected retry result %v", result)
+	}
+	if len(reserver.calls) != 1 || reserver.calls[0].ProductID != productID || reserver.calls[0].Qty != 3 {
+		t.Fatalf("expected the rejected line to be re-reserved, got %+v", reserver.calls)
+	}
+	if len(releaser.calls) != 0 {
+		t.Fatalf("expected nothing released for lines that never held stock, got %+v", releaser.calls)
+	}
+	if len(*created) != 1 || (*created)[0].Status != enums.LineItemStatusPending {
+		t.Fatalf("expected a pending line on the new order, got %+v", *created)
+	}
+	if !outbox.called || outbox.event.EventType != enums.EventOrderRetried {
+		t.Fatalf("expected retry event got %v", outbox.event.EventType)
+	}
+}
+
+func TestRetryOrderRejectedReleasesHeldStockBeforeReserving(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	productID := uuid.New()
+	repo, _ := newRejectedRetryRepo(orderID, buyerStore, productID, enums.LineItemStatusPending)
+	releaser := &stubInventoryReleaser{}
+	reserver := &stubInventoryReserver{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, releaser, reserver)
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	if _, err := svc.RetryOrder(context.Background(), BuyerRetryInput{OrderID: orderID, ActorUserID: uuid.New(), ActorStoreID: buyerStore}); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(releaser.calls) != 1 || releaser.calls[0].productID != productID || releaser.calls[0].qty != 3 {
+		t.Fatalf("expected held stock released once, got %+v", releaser.calls)
+	}
+	if len(reserver.calls) != 1 {
+		t.Fatalf("expected stock re-reserved for the new order, got %+v", reserver.calls)
+	}
+	for _, item := range repo.lineItems {
+		if item.OrderID == orderID && item.Status != enums.LineItemStatusRejected {
+			t.Fatalf("expected original line marked rejected, got %s", item.Status)
+		}
+	}
+}
+
+func TestRetryOrderHonorsConfiguredStatuses(t *testing.T) {
+	orderID := uuid.New()
+	buyerStore := uuid.New()
+	repo, _ := newRejectedRetryRepo(orderID, buyerStore, uuid.New(), enums.LineItemStatusRejected)
+	reserver := &stubInventoryReserver{}
+	svc, err := NewService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, reserver, newStubLedgerService(nil, nil),
+		WithRetryableStatuses([]enums.VendorOrderStatus{enums.VendorOrderStatusExpired}))
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	_, err = svc.RetryOrder(context.Background(), BuyerRetryInput{OrderID: orderID, ActorUserID: uuid.New(), ActorStoreID: buyerStore})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict got %v", err)
+	}
+	if len(reserver.calls) != 0 {
+		t.Fatalf("expected no reservation attempt")
+	}
+}
+
+func TestParseRetryableStatuses(t *testing.T) {
+	statuses, err := ParseRetryableStatuses([]string{"expired", " rejected ", ""})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0] != enums.VendorOrderStatusExpired || statuses[1] != enums.VendorOrderStatusRejected {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if _, err := ParseRetryableStatuses([]string{"accepted"}); err == nil {
+		t.Fatal("expected active statuses to be refused")
+	}
+	if _, err := ParseRetryableStatuses([]string{"bogus"}); err == nil {
+		t.Fatal("expected unknown statuses to be refused")
+	}
+}
+
 func TestLineItemDecisionFulfillEmitsEvent(t *testing.T) {
 	orderID := uuid.New()
 	storeID := uuid.New()
@@ -674,6 +1392,72 @@ func TestLineItemDecisionFulfillEmitsEvent(t *testing.T) {
 	}
 }
 
+func TestLineItemDecisionReacquiresLapsedReservation(t *testing.T) {
+	orderID := uuid.New()
+	storeID := uuid.New()
+	lineID := uuid.New()
+	productID := uuid.New()
+	releasedAt := time.Now().Add(-time.Hour)
+	newRepo := func() *stubOrdersRepo {
+		return &stubOrdersRepo{
+			order: &models.VendorOrder{
+				ID:                    orderID,
+				VendorStoreID:         storeID,
+				BuyerStoreID:          uuid.New(),
+				CheckoutGroupID:       uuid.New(),
+				Status:                enums.VendorOrderStatusCreatedPending,
+				ReservationReleasedAt: &releasedAt,
+				SubtotalCents:         1200,
+				TotalCents:            1200,
+				BalanceDueCents:       1200,
+			},
+			lineItems: map[uuid.UUID]*models.OrderLineItem{
+				lineID: {ID: lineID, OrderID: orderID, ProductID: &productID, Qty: 2, TotalCents: 1200, Status: enums.LineItemStatusPending},
+			},
+		}
+	}
+	input := LineItemDecisionInput{
+		OrderID:      orderID,
+		LineItemID:   lineID,
+		Decision:     LineItemDecisionFulfill,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: storeID,
+		ActorRole:    "owner",
+	}
+
+	repo := newRepo()
+	reserver := &stubInventoryReserver{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, reserver)
+	if err != nil {
+		t.Fatalf("constructor failed: %v", err)
+	}
+	if err := svc.LineItemDecision(context.Background(), input); err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if len(reserver.calls) != 1 || reserver.calls[0].ProductID != productID || reserver.calls[0].Qty != 2 {
+		t.Fatalf("expected the line re-reserved, got %+v", reserver.calls)
+	}
+	if repo.order.ReservationReleasedAt != nil {
+		t.Fatal("expected reservation_released_at cleared")
+	}
+	if repo.order.Status != enums.VendorOrderStatusReadyForDispatch {
+		t.Fatalf("unexpected order status %s", repo.order.Status)
+	}
+
+	repo = newRepo()
+	svc, err = newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{unavailable: true})
+	if err != nil {
+		t.Fatalf("constructor failed: %v", err)
+	}
+	err = svc.LineItemDecision(context.Background(), input)
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeConflict {
+		t.Fatalf("expected conflict when stock is gone, got %v", err)
+	}
+	if repo.order.Status != enums.VendorOrderStatusCreatedPending {
+		t.Fatalf("expected order to stay pending, got %s", repo.order.Status)
+	}
+}
+
 func TestLineItemDecisionRejectReleasesInventory(t *testing.T) {
 	orderID := uuid.New()
 	storeID := uuid.New()
@@ -911,7 +1695,10 @@ func TestAgentDeliverSuccess(t *testing.T) {
 		Order: &VendorOrderSummary{
 			Status:         enums.VendorOrderStatusInTransit,
 			ShippingStatus: enums.VendorOrderShippingStatusInTransit,
+			TotalCents:     4200,
 		},
+		BuyerStore:  OrderStoreSummary{ID: uuid.New()},
+		VendorStore: OrderStoreSummary{ID: uuid.New()},
 		ActiveAssignment: &OrderAssignmentSummary{
 			ID:          assignID,
 			AgentUserID: agentID,
@@ -935,7 +1722,14 @@ func TestAgentDeliverSuccess(t *testing.T) {
 			return nil
 		},
 	}
-	svc, _ := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	var earning *ledger.AgentEarningInput
+	ledgerSvc := &stubLedgerService{
+		agentEarningFn: func(ctx context.Context, input ledger.AgentEarningInput) (*models.LedgerEvent, error) {
+			earning = &input
+			return &models.LedgerEvent{ID: uuid.New()}, nil
+		},
+	}
+	svc, _ := NewService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{}, ledgerSvc)
 	err := svc.AgentDeliver(context.Background(), AgentDeliverInput{OrderID: orderID, AgentUserID: agentID})
 	if err != nil {
 		t.Fatalf("expected success got %v", err)
@@ -952,6 +1746,15 @@ func TestAgentDeliverSuccess(t *testing.T) {
 	if !assignmentUpdated {
 		t.Fatal("expected assignment update")
 	}
+	if earning == nil {
+		t.Fatal("expected agent earning to be recorded")
+	}
+	if earning.OrderID != orderID || earning.AgentUserID != agentID || earning.OrderTotalCents != 4200 {
+		t.Fatalf("unexpected agent earning %+v", earning)
+	}
+	if earning.BuyerStoreID != detail.BuyerStore.ID || earning.VendorStoreID != detail.VendorStore.ID {
+		t.Fatalf("unexpected earning stores %+v", earning)
+	}
 }
 
 func TestAgentDeliverForbiddenWhenNotAssigned(t *testing.T) {
@@ -1020,6 +1823,8 @@ func TestAgentDeliverIdempotent(t *testing.T) {
 			ShippingStatus: enums.VendorOrderShippingStatusDelivered,
 			DeliveredAt:    &now,
 		},
+		BuyerStore:  OrderStoreSummary{ID: uuid.New()},
+		VendorStore: OrderStoreSummary{ID: uuid.New()},
 		ActiveAssignment: &OrderAssignmentSummary{
 			ID:           uuid.New(),
 			AgentUserID:  agentID,
@@ -1036,7 +1841,16 @@ func TestAgentDeliverIdempotent(t *testing.T) {
 			return nil
 		},
 	}
-	svc, _ := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	ledgerSvc := &stubLedgerService{
+		hasFn: func(ctx context.Context, orderID uuid.UUID, eventType enums.LedgerEventType) (bool, error) {
+			return eventType == enums.LedgerEventTypeAgentDeliveryFee, nil
+		},
+		agentEarningFn: func(ctx context.Context, input ledger.AgentEarningInput) (*models.LedgerEvent, error) {
+			t.Fatal("expected agent earning to be recorded only once")
+			return nil, nil
+		},
+	}
+	svc, _ := NewService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{}, ledgerSvc)
 	err := svc.AgentDeliver(context.Background(), AgentDeliverInput{OrderID: orderID, AgentUserID: agentID})
 	if err != nil {
 		t.Fatalf("expected success got %v", err)
@@ -1114,6 +1928,9 @@ func TestAgentCashCollectedAppendsLedgerEvent(t *testing.T) {
 	if payload.AmountCents != 1234 {
 		t.Fatalf("unexpected event amount %d", payload.AmountCents)
 	}
+	if payload.Amount.String() != "$12.34" {
+		t.Fatalf("unexpected formatted amount %s", payload.Amount)
+	}
 	if payload.BuyerStoreID != buyerID {
 		t.Fatalf("unexpected buyer store %s", payload.BuyerStoreID)
 	}
@@ -1542,6 +2359,9 @@ func TestService_ConfirmPayoutFinalizesOrder(t *testing.T) {
 	if event.AmountCents != detail.PaymentIntent.AmountCents {
 		t.Fatalf("unexpected amount %d", event.AmountCents)
 	}
+	if event.Amount != money.USD(detail.PaymentIntent.AmountCents) {
+		t.Fatalf("unexpected amount %+v", event.Amount)
+	}
 	if event.BuyerStoreID != buyerID || event.VendorStoreID != vendorID {
 		t.Fatalf("unexpected stores in event %+v", event)
 	}
@@ -1551,6 +2371,9 @@ func TestService_ConfirmPayoutFinalizesOrder(t *testing.T) {
 	if event.VendorPaidAt.IsZero() {
 		t.Fatalf("vendor paid timestamp missing")
 	}
+	if want := "order_paid:" + orderID.String(); outbox.event.DedupKey != want {
+		t.Fatalf("expected dedup key %s, got %q", want, outbox.event.DedupKey)
+	}
 }
 
 func TestService_ConfirmPayoutIdempotent(t *testing.T) {
@@ -1633,3 +2456,241 @@ func TestService_ConfirmPayoutMissingPaymentIntent(t *testing.T) {
 		t.Fatal("expected error for missing payment intent")
 	}
 }
+
+type gormTxRunner struct {
+	db *gorm.DB
+}
+
+func (r gormTxRunner) WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func TestClaimOrderEmitsAssignmentNotification(t *testing.T) {
+	orderID := uuid.New()
+	agentID := uuid.New()
+	var created *models.OrderAssignment
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              orderID,
+			BuyerStoreID:    uuid.New(),
+			VendorStoreID:   uuid.New(),
+			CheckoutGroupID: uuid.New(),
+			Status:          enums.VendorOrderStatusReadyForDispatch,
+		},
+		createAssignment: func(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+			created = assignment
+			return true, nil
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	summary, err := svc.ClaimOrder(context.Background(), AgentClaimInput{OrderID: orderID, AgentUserID: agentID})
+	if err != nil {
+		t.Fatalf("expected success got %v", err)
+	}
+	if created == nil || created.OrderID != orderID || created.AgentUserID != agentID {
+		t.Fatalf("unexpected assignment %+v", created)
+	}
+	if summary == nil || summary.AgentUserID != agentID {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if !outbox.called || outbox.event.EventType != enums.EventNotificationRequested {
+		t.Fatalf("expected notification event got %v", outbox.event.EventType)
+	}
+	payload, ok := outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || payload.Type != "order_assigned" || payload.OrderID != orderID {
+		t.Fatalf("unexpected payload %+v", outbox.event.Data)
+	}
+}
+
+func TestClaimOrderAlreadyClaimed(t *testing.T) {
+	orderID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{ID: orderID, Status: enums.VendorOrderStatusReadyForDispatch},
+		createAssignment: func(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+			return false, nil
+		},
+	}
+	outbox := &stubOutboxPublisher{}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	_, err = svc.ClaimOrder(context.Background(), AgentClaimInput{OrderID: orderID, AgentUserID: uuid.New()})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict got %v", err)
+	}
+	if outbox.called {
+		t.Fatal("expected no notification for a lost claim")
+	}
+}
+
+func TestSchedulePickupSetsWindow(t *testing.T) {
+	orderID := uuid.New()
+	storeID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{ID: orderID, VendorStoreID: storeID, Status: enums.VendorOrderStatusReadyForDispatch},
+	}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	start := time.Now().Add(2 * time.Hour).UTC()
+	end := start.Add(time.Hour)
+	err = svc.SchedulePickup(context.Background(), SchedulePickupInput{
+		OrderID:      orderID,
+		Window:       PickupWindow{Start: start, End: end},
+		ActorUserID:  uuid.New(),
+		ActorStoreID: storeID,
+	})
+	if err != nil {
+		t.Fatalf("schedule pickup: %v", err)
+	}
+	if repo.orderUpdates["pickup_window_start"] != start || repo.orderUpdates["pickup_window_end"] != end {
+		t.Fatalf("unexpected updates %v", repo.orderUpdates)
+	}
+	if _, ok := repo.orderUpdates["status"]; ok {
+		t.Fatalf("scheduling pickup should not change the order status")
+	}
+}
+
+func TestSchedulePickupRejectsInvalidRequests(t *testing.T) {
+	orderID := uuid.New()
+	storeID := uuid.New()
+	future := time.Now().Add(time.Hour).UTC()
+
+	cases := map[string]struct {
+		status  enums.VendorOrderStatus
+		storeID uuid.UUID
+		window  PickupWindow
+		code    pkgerrors.Code
+	}{
+		"in transit": {
+			status: enums.VendorOrderStatusInTransit, storeID: storeID,
+			window: PickupWindow{Start: future, End: future.Add(time.Hour)},
+			code:   pkgerrors.CodeStateConflict,
+		},
+		"awaiting decision": {
+			status: enums.VendorOrderStatusCreatedPending, storeID: storeID,
+			window: PickupWindow{Start: future, End: future.Add(time.Hour)},
+			code:   pkgerrors.CodeStateConflict,
+		},
+		"other vendor": {
+			status: enums.VendorOrderStatusReadyForDispatch, storeID: uuid.New(),
+			window: PickupWindow{Start: future, End: future.Add(time.Hour)},
+			code:   pkgerrors.CodeForbidden,
+		},
+		"past start": {
+			status: enums.VendorOrderStatusReadyForDispatch, storeID: storeID,
+			window: PickupWindow{Start: time.Now().Add(-time.Minute), End: future},
+			code:   pkgerrors.CodeValidation,
+		},
+		"end before start": {
+			status: enums.VendorOrderStatusReadyForDispatch, storeID: storeID,
+			window: PickupWindow{Start: future, End: future.Add(-time.Minute)},
+			code:   pkgerrors.CodeValidation,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &stubOrdersRepo{
+				order: &models.VendorOrder{ID: orderID, VendorStoreID: storeID, Status: tc.status},
+			}
+			svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+			if err != nil {
+				t.Fatalf("construct service: %v", err)
+			}
+			err = svc.SchedulePickup(context.Background(), SchedulePickupInput{
+				OrderID:      orderID,
+				Window:       tc.window,
+				ActorUserID:  uuid.New(),
+				ActorStoreID: tc.storeID,
+			})
+			if typed := pkgerrors.As(err); typed == nil || typed.Code() != tc.code {
+				t.Fatalf("expected %s got %v", tc.code, err)
+			}
+			if repo.orderUpdates != nil {
+				t.Fatalf("expected no updates, got %v", repo.orderUpdates)
+			}
+		})
+	}
+}
+
+func TestClaimOrderRejectsOrdersOutsideQueue(t *testing.T) {
+	orderID := uuid.New()
+	repo := &stubOrdersRepo{
+		order: &models.VendorOrder{ID: orderID, Status: enums.VendorOrderStatusInTransit},
+		createAssignment: func(ctx context.Context, assignment *models.OrderAssignment) (bool, error) {
+			t.Fatal("assignment should not be attempted")
+			return false, nil
+		},
+	}
+	svc, err := newTestOrdersService(repo, stubTxRunner{}, &stubOutboxPublisher{}, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	_, err = svc.ClaimOrder(context.Background(), AgentClaimInput{OrderID: orderID, AgentUserID: uuid.New()})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict got %v", err)
+	}
+}
+
+func TestClaimOrderConcurrentClaimsSingleWinner(t *testing.T) {
+	// Each claim runs on its own pooled connection; both read the order as ready for dispatch, so the
+	// conditional assignment insert decides the winner.
+	db := setupConcurrentOrdersTestDB(t)
+
+	buyer := newStore(t, db, "Buyer", enums.StoreTypeBuyer)
+	vendor := newStore(t, db, "Vendor", enums.StoreTypeVendor)
+	order := createQueueOrder(t, db, buyer, vendor, 1, time.Now().UTC(), 1)
+
+	outbox := &stubOutboxPublisher{}
+	svc, err := newTestOrdersService(NewRepository(db), gormTxRunner{db: db}, outbox, &stubInventoryReleaser{}, &stubInventoryReserver{})
+	if err != nil {
+		t.Fatalf("construct service: %v", err)
+	}
+
+	const claimers = 2
+	errs := make([]error, claimers)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = svc.ClaimOrder(context.Background(), AgentClaimInput{OrderID: order.ID, AgentUserID: uuid.New()})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+			t.Fatalf("expected state conflict for losing claim got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one successful claim got %d", succeeded)
+	}
+
+	var active int64
+	if err := db.Table("order_assignments").Where("order_id = ? AND active = ?", order.ID, true).Count(&active).Error; err != nil {
+		t.Fatalf("count assignments: %v", err)
+	}
+	if active != 1 {
+		t.Fatalf("expected one active assignment got %d", active)
+	}
+}
@@ -0,0 +1,324 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/checkout/reservation"
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification types emitted while a substitution is negotiated.
+const (
+	notificationSubstitutionProposed = "line_item_substitution_proposed"
+	notificationSubstitutionAccepted = "line_item_substitution_accepted"
+	notificationSubstitutionRejected = "line_item_substitution_rejected"
+)
+
+// ProposeSubstitutionInput carries a vendor's offer to replace a line item with another product.
+type ProposeSubstitutionInput struct {
+	OrderID             uuid.UUID
+	LineItemID          uuid.UUID
+	SubstituteProductID uuid.UUID
+	Qty                 int
+	UnitPriceCents      int
+	ActorUserID         uuid.UUID
+	ActorStoreID        uuid.UUID
+	ActorRole           string
+}
+
+// SubstitutionDecisionInput carries the buyer's answer to a pending substitution.
+type SubstitutionDecisionInput struct {
+	OrderID      uuid.UUID
+	LineItemID   uuid.UUID
+	ActorUserID  uuid.UUID
+	ActorStoreID uuid.UUID
+	ActorRole    string
+}
+
+// ProposeSubstitution parks a pending line item as pending_substitution with the vendor's offer and
+// asks the buyer to approve it. Inventory is not touched until the buyer accepts.
+func (s *service) ProposeSubstitution(ctx context.Context, input ProposeSubstitutionInput) error {
+	if input.OrderID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.LineItemID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "line item id required")
+	}
+	if input.SubstituteProductID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "substitute product id required")
+	}
+	if input.Qty <= 0 {
+		return pkgerrors.New(pkgerrors.CodeValidation, "substitute qty must be greater than zero")
+	}
+	if input.UnitPriceCents < 0 {
+		return pkgerrors.New(pkgerrors.CodeValidation, "substitute unit price must be >= 0")
+	}
+	if input.ActorUserID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+	}
+	if input.ActorStoreID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, lineItem, err := loadSubstitutionLine(ctx, repo, input.OrderID, input.LineItemID)
+		if err != nil {
+			return err
+		}
+		if order.VendorStoreID != input.ActorStoreID {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
+		}
+		if lineItem.Status != enums.LineItemStatusPending {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "substitution only allowed for pending line items")
+		}
+		if lineItem.ProductID != nil && *lineItem.ProductID == input.SubstituteProductID {
+			return pkgerrors.New(pkgerrors.CodeValidation, "substitute must be a different product")
+		}
+
+		product, err := repo.FindProduct(ctx, input.SubstituteProductID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "substitute product not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load substitute product")
+		}
+		if product.StoreID != order.VendorStoreID {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "substitute product does not belong to store")
+		}
+		if !product.IsActive {
+			return pkgerrors.New(pkgerrors.CodeValidation, "substitute product is not active")
+		}
+		if input.Qty < product.MOQ || (product.MaxQty > 0 && input.Qty > product.MaxQty) {
+			return pkgerrors.New(pkgerrors.CodeValidation, "substitute qty outside the product's order limits")
+		}
+
+		if err := repo.UpdateOrderLineItem(ctx, lineItem.ID, map[string]any{
+			"status":                      enums.LineItemStatusPendingSubstitution,
+			"substitute_product_id":       input.SubstituteProductID,
+			"substitute_qty":              input.Qty,
+			"substitute_unit_price_cents": input.UnitPriceCents,
+		}); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record substitution")
+		}
+
+		return s.emitSubstitutionNotification(ctx, tx, order, lineItem.ID, notificationSubstitutionProposed, input.ActorUserID, input.ActorStoreID, input.ActorRole)
+	})
+}
+
+// AcceptSubstitution swaps the line item to the offered product: the original reservation is
+// released, the substitute is reserved, the line is re-snapshotted, and order totals are recomputed.
+// The line returns to pending so the vendor fulfills it through the normal decision flow.
+func (s *service) AcceptSubstitution(ctx context.Context, input SubstitutionDecisionInput) error {
+	if err := validateSubstitutionDecision(input); err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, lineItem, err := loadBuyerSubstitutionLine(ctx, repo, input)
+		if err != nil {
+			return err
+		}
+		if lineItem.SubstituteProductID == nil || lineItem.SubstituteQty == nil || lineItem.SubstituteUnitPriceCents == nil {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "substitution offer is incomplete")
+		}
+
+		product, err := repo.FindProduct(ctx, *lineItem.SubstituteProductID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeConflict, "substitute product is no longer available")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load substitute product")
+		}
+
+		// When the order's reservation already lapsed there is nothing to swap; the substitute is
+		// reserved with the rest of the order if the vendor accepts it.
+		if holdsReservation(order) {
+			actor := inventory.NewAdjustmentActor(input.ActorUserID, input.ActorStoreID)
+			if err := releaseLineItem(*lineItem, s.inventory, actor, ctx, tx); err != nil {
+				return err
+			}
+			reserved, err := s.reserver.Reserve(ctx, tx, []reservation.InventoryReservationRequest{{
+				CartItemID: lineItem.ID,
+				ProductID:  product.ID,
+				Qty:        *lineItem.SubstituteQty,
+				Actor:      actor,
+			}})
+			if err != nil {
+				return err
+			}
+			for _, res := range reserved {
+				if !res.Reserved {
+					return pkgerrors.New(pkgerrors.CodeConflict, "insufficient inventory for substitute")
+				}
+			}
+		}
+
+		updates := substitutedLineItemUpdates(product, *lineItem.SubstituteQty, *lineItem.SubstituteUnitPriceCents)
+		if err := repo.UpdateOrderLineItem(ctx, lineItem.ID, updates); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "apply substitution")
+		}
+
+		items, err := repo.FindOrderLineItemsByOrder(ctx, order.ID)
+		if err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "reload line items")
+		}
+		subtotal, total := recomputeOrderTotals(order, items)
+		if err := repo.UpdateVendorOrder(ctx, order.ID, map[string]any{
+			"subtotal_cents":    subtotal,
+			"total_cents":       total,
+			"balance_due_cents": total,
+		}); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update order totals")
+		}
+
+		return s.emitSubstitutionNotification(ctx, tx, order, lineItem.ID, notificationSubstitutionAccepted, input.ActorUserID, input.ActorStoreID, input.ActorRole)
+	})
+}
+
+// RejectSubstitution drops the vendor's offer and returns the line to pending, leaving the vendor
+// to fulfill or reject the original product.
+func (s *service) RejectSubstitution(ctx context.Context, input SubstitutionDecisionInput) error {
+	if err := validateSubstitutionDecision(input); err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		order, lineItem, err := loadBuyerSubstitutionLine(ctx, repo, input)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.UpdateOrderLineItem(ctx, lineItem.ID, clearedSubstitutionUpdates(enums.LineItemStatusPending)); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "clear substitution")
+		}
+
+		return s.emitSubstitutionNotification(ctx, tx, order, lineItem.ID, notificationSubstitutionRejected, input.ActorUserID, input.ActorStoreID, input.ActorRole)
+	})
+}
+
+func validateSubstitutionDecision(input SubstitutionDecisionInput) error {
+	if input.OrderID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.LineItemID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "line item id required")
+	}
+	if input.ActorUserID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeUnauthorized, "user identity missing")
+	}
+	if input.ActorStoreID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "store context missing")
+	}
+	return nil
+}
+
+func loadSubstitutionLine(ctx context.Context, repo Repository, orderID, lineItemID uuid.UUID) (*models.VendorOrder, *models.OrderLineItem, error) {
+	order, err := repo.FindVendorOrder(ctx, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+		}
+		return nil, nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor order")
+	}
+	if isFinalOrderStatus(order.Status) {
+		return nil, nil, pkgerrors.New(pkgerrors.CodeStateConflict, "order cannot be updated in current state")
+	}
+	lineItem, err := repo.FindOrderLineItem(ctx, lineItemID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, pkgerrors.New(pkgerrors.CodeNotFound, "line item not found")
+		}
+		return nil, nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load line item")
+	}
+	if lineItem.OrderID != order.ID {
+		return nil, nil, pkgerrors.New(pkgerrors.CodeForbidden, "line item does not belong to order")
+	}
+	return order, lineItem, nil
+}
+
+func loadBuyerSubstitutionLine(ctx context.Context, repo Repository, input SubstitutionDecisionInput) (*models.VendorOrder, *models.OrderLineItem, error) {
+	order, lineItem, err := loadSubstitutionLine(ctx, repo, input.OrderID, input.LineItemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.BuyerStoreID != input.ActorStoreID {
+		return nil, nil, pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to store")
+	}
+	if lineItem.Status != enums.LineItemStatusPendingSubstitution {
+		return nil, nil, pkgerrors.New(pkgerrors.CodeStateConflict, "line item has no pending substitution")
+	}
+	return order, lineItem, nil
+}
+
+// substitutedLineItemUpdates re-snapshots the line from the substitute product at the offered price.
+// Volume discounts from the original line do not carry over.
+func substitutedLineItemUpdates(product *models.Product, qty, unitPriceCents int) map[string]any {
+	var classification *string
+	if product.Classification != nil {
+		value := string(*product.Classification)
+		classification = &value
+	}
+	var maxQty *int
+	if product.MaxQty > 0 {
+		value := product.MaxQty
+		maxQty = &value
+	}
+	lineTotal := qty * unitPriceCents
+
+	updates := clearedSubstitutionUpdates(enums.LineItemStatusPending)
+	updates["product_id"] = product.ID
+	updates["name"] = product.Title
+	updates["thumbnail"] = nil
+	updates["category"] = string(product.Category)
+	updates["strain"] = product.Strain
+	updates["classification"] = classification
+	updates["unit"] = product.Unit
+	updates["moq"] = product.MOQ
+	updates["max_qty"] = maxQty
+	updates["unit_price_cents"] = unitPriceCents
+	updates["qty"] = qty
+	updates["discount_cents"] = 0
+	updates["line_subtotal_cents"] = lineTotal
+	updates["total_cents"] = lineTotal
+	updates["applied_volume_discount"] = nil
+	return updates
+}
+
+func clearedSubstitutionUpdates(status enums.LineItemStatus) map[string]any {
+	return map[string]any{
+		"status":                      status,
+		"substitute_product_id":       nil,
+		"substitute_qty":              nil,
+		"substitute_unit_price_cents": nil,
+	}
+}
+
+func (s *service) emitSubstitutionNotification(ctx context.Context, tx *gorm.DB, order *models.VendorOrder, lineItemID uuid.UUID, notificationType string, actorUserID, actorStoreID uuid.UUID, actorRole string) error {
+	event := outbox.DomainEvent{
+		EventType:     enums.EventNotificationRequested,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   order.ID,
+		Version:       1,
+		Actor:         buildActor(actorUserID, actorStoreID, actorRole),
+		Data: payloads.NotificationRequestedEvent{
+			OrderID:         order.ID,
+			CheckoutGroupID: order.CheckoutGroupID,
+			BuyerStoreID:    order.BuyerStoreID,
+			VendorStoreID:   order.VendorStoreID,
+			OrderReference:  orderReference(order),
+			Type:            notificationType,
+			LineItemID:      &lineItemID,
+		},
+	}
+	return s.outbox.Emit(ctx, tx, event)
+}
@@ -0,0 +1,225 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+)
+
+type substitutionFixture struct {
+	repo       *stubOrdersRepo
+	outbox     *stubOutboxPublisher
+	inventory  *stubInventoryReleaser
+	reserver   *stubInventoryReserver
+	svc        Service
+	orderID    uuid.UUID
+	vendorID   uuid.UUID
+	buyerID    uuid.UUID
+	lineID     uuid.UUID
+	originalID uuid.UUID
+	substitute *models.Product
+}
+
+func newSubstitutionFixture(t *testing.T) *substitutionFixture {
+	t.Helper()
+	f := &substitutionFixture{
+		orderID:    uuid.New(),
+		vendorID:   uuid.New(),
+		buyerID:    uuid.New(),
+		lineID:     uuid.New(),
+		originalID: uuid.New(),
+	}
+	f.substitute = &models.Product{
+		ID:       uuid.New(),
+		StoreID:  f.vendorID,
+		Title:    "Sour Diesel 3.5g",
+		Category: enums.ProductCategoryFlower,
+		Unit:     enums.ProductUnitUnit,
+		MOQ:      1,
+		IsActive: true,
+	}
+	otherID := uuid.New()
+	f.repo = &stubOrdersRepo{
+		order: &models.VendorOrder{
+			ID:              f.orderID,
+			VendorStoreID:   f.vendorID,
+			BuyerStoreID:    f.buyerID,
+			CheckoutGroupID: uuid.New(),
+			Status:          enums.VendorOrderStatusAccepted,
+			SubtotalCents:   3000,
+			TotalCents:      3500,
+			BalanceDueCents: 3500,
+		},
+		lineItems: map[uuid.UUID]*models.OrderLineItem{
+			f.lineID: {
+				ID:             f.lineID,
+				OrderID:        f.orderID,
+				ProductID:      &f.originalID,
+				Name:           "Blue Dream 3.5g",
+				Qty:            2,
+				UnitPriceCents: 1000,
+				TotalCents:     2000,
+				Status:         enums.LineItemStatusPending,
+			},
+			otherID: {
+				ID:         otherID,
+				OrderID:    f.orderID,
+				Qty:        1,
+				TotalCents: 1000,
+				Status:     enums.LineItemStatusPending,
+			},
+		},
+		products: map[uuid.UUID]*models.Product{f.substitute.ID: f.substitute},
+	}
+	f.outbox = &stubOutboxPublisher{}
+	f.inventory = &stubInventoryReleaser{}
+	f.reserver = &stubInventoryReserver{}
+	svc, err := newTestOrdersService(f.repo, stubTxRunner{}, f.outbox, f.inventory, f.reserver)
+	if err != nil {
+		t.Fatalf("constructor failed: %v", err)
+	}
+	f.svc = svc
+	return f
+}
+
+func (f *substitutionFixture) propose(t *testing.T) {
+	t.Helper()
+	err := f.svc.ProposeSubstitution(context.Background(), ProposeSubstitutionInput{
+		OrderID:             f.orderID,
+		LineItemID:          f.lineID,
+		SubstituteProductID: f.substitute.ID,
+		Qty:                 3,
+		UnitPriceCents:      900,
+		ActorUserID:         uuid.New(),
+		ActorStoreID:        f.vendorID,
+		ActorRole:           "owner",
+	})
+	if err != nil {
+		t.Fatalf("propose substitution: %v", err)
+	}
+	line := f.repo.lineItems[f.lineID]
+	if line.Status != enums.LineItemStatusPendingSubstitution {
+		t.Fatalf("expected pending_substitution, got %s", line.Status)
+	}
+	if line.SubstituteProductID == nil || *line.SubstituteProductID != f.substitute.ID {
+		t.Fatalf("expected substitute product recorded, got %v", line.SubstituteProductID)
+	}
+	event, ok := f.outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || event.Type != notificationSubstitutionProposed {
+		t.Fatalf("expected proposal notification, got %+v", f.outbox.event.Data)
+	}
+	if event.LineItemID == nil || *event.LineItemID != f.lineID {
+		t.Fatalf("expected notification for line %s, got %v", f.lineID, event.LineItemID)
+	}
+	if len(f.inventory.calls) != 0 || len(f.reserver.calls) != 0 {
+		t.Fatal("expected inventory untouched until the buyer decides")
+	}
+}
+
+func (f *substitutionFixture) decision() SubstitutionDecisionInput {
+	return SubstitutionDecisionInput{
+		OrderID:      f.orderID,
+		LineItemID:   f.lineID,
+		ActorUserID:  uuid.New(),
+		ActorStoreID: f.buyerID,
+		ActorRole:    "owner",
+	}
+}
+
+func TestSubstitutionProposeThenAccept(t *testing.T) {
+	f := newSubstitutionFixture(t)
+	f.propose(t)
+
+	if err := f.svc.AcceptSubstitution(context.Background(), f.decision()); err != nil {
+		t.Fatalf("accept substitution: %v", err)
+	}
+
+	if len(f.inventory.calls) != 1 || f.inventory.calls[0].productID != f.originalID || f.inventory.calls[0].qty != 2 {
+		t.Fatalf("expected original reservation released, got %+v", f.inventory.calls)
+	}
+	if len(f.reserver.calls) != 1 || f.reserver.calls[0].ProductID != f.substitute.ID || f.reserver.calls[0].Qty != 3 {
+		t.Fatalf("expected substitute reserved, got %+v", f.reserver.calls)
+	}
+
+	line := f.repo.lineItems[f.lineID]
+	if line.Status != enums.LineItemStatusPending {
+		t.Fatalf("expected line back to pending, got %s", line.Status)
+	}
+	if line.ProductID == nil || *line.ProductID != f.substitute.ID || line.Name != f.substitute.Title {
+		t.Fatalf("expected line to snapshot the substitute, got %+v", line)
+	}
+	if line.Qty != 3 || line.UnitPriceCents != 900 || line.TotalCents != 2700 {
+		t.Fatalf("unexpected line pricing %+v", line)
+	}
+	if line.SubstituteProductID != nil || line.SubstituteQty != nil || line.SubstituteUnitPriceCents != nil {
+		t.Fatalf("expected offer cleared, got %+v", line)
+	}
+
+	// 2700 substitute + 1000 other line, plus the 500 in fees carried over.
+	if f.repo.order.SubtotalCents != 3700 || f.repo.order.TotalCents != 4200 || f.repo.order.BalanceDueCents != 4200 {
+		t.Fatalf("unexpected order totals %+v", f.repo.order)
+	}
+	event, ok := f.outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || event.Type != notificationSubstitutionAccepted {
+		t.Fatalf("expected acceptance notification, got %+v", f.outbox.event.Data)
+	}
+}
+
+func TestSubstitutionProposeThenReject(t *testing.T) {
+	f := newSubstitutionFixture(t)
+	f.propose(t)
+
+	if err := f.svc.RejectSubstitution(context.Background(), f.decision()); err != nil {
+		t.Fatalf("reject substitution: %v", err)
+	}
+
+	line := f.repo.lineItems[f.lineID]
+	if line.Status != enums.LineItemStatusPending {
+		t.Fatalf("expected line back to pending, got %s", line.Status)
+	}
+	if line.ProductID == nil || *line.ProductID != f.originalID || line.Qty != 2 || line.TotalCents != 2000 {
+		t.Fatalf("expected original line kept, got %+v", line)
+	}
+	if line.SubstituteProductID != nil || line.SubstituteQty != nil || line.SubstituteUnitPriceCents != nil {
+		t.Fatalf("expected offer cleared, got %+v", line)
+	}
+	if len(f.inventory.calls) != 0 || len(f.reserver.calls) != 0 {
+		t.Fatal("expected inventory untouched on reject")
+	}
+	if f.repo.order.TotalCents != 3500 {
+		t.Fatalf("expected totals unchanged, got %d", f.repo.order.TotalCents)
+	}
+	event, ok := f.outbox.event.Data.(payloads.NotificationRequestedEvent)
+	if !ok || event.Type != notificationSubstitutionRejected {
+		t.Fatalf("expected rejection notification, got %+v", f.outbox.event.Data)
+	}
+
+	if err := f.svc.AcceptSubstitution(context.Background(), f.decision()); err == nil {
+		t.Fatal("expected accept to fail once the offer is rejected")
+	}
+}
+
+func TestProposeSubstitutionRejectsForeignProduct(t *testing.T) {
+	f := newSubstitutionFixture(t)
+	f.substitute.StoreID = uuid.New()
+
+	err := f.svc.ProposeSubstitution(context.Background(), ProposeSubstitutionInput{
+		OrderID:             f.orderID,
+		LineItemID:          f.lineID,
+		SubstituteProductID: f.substitute.ID,
+		Qty:                 1,
+		UnitPriceCents:      900,
+		ActorUserID:         uuid.New(),
+		ActorStoreID:        f.vendorID,
+	})
+	if err == nil {
+		t.Fatal("expected error for product from another store")
+	}
+	if f.repo.lineItems[f.lineID].Status != enums.LineItemStatusPending {
+		t.Fatal("expected line item unchanged")
+	}
+}
@@ -257,6 +257,9 @@ func (s *stubBillingRepo) FindSubscription(ctx context.Context, storeID uuid.UUI
 func (s *stubBillingRepo) FindSubscriptionBySquareID(ctx context.Context, squareSubscriptionID string) (*models.Subscription, error) {
 	return nil, nil
 }
+func (s *stubBillingRepo) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
+	return nil, nil
+}
 func (s *stubBillingRepo) CreatePaymentMethod(ctx context.Context, method *models.PaymentMethod) error {
 	s.created = append(s.created, method)
 	return nil
@@ -365,3 +368,15 @@ func stubCard(id string) *sq.Card {
 func ptrString(value string) *string {
 	return &value
 }
+
+func (s *stubBillingRepo) UpsertInvoice(ctx context.Context, invoice *models.Invoice) error {
+	return nil
+}
+
+func (s *stubBillingRepo) ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	return nil, nil
+}
+
+func (s *stubBillingRepo) FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	return nil, nil
+}
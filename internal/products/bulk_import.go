@@ -0,0 +1,464 @@
+package product
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxBulkImportRows caps how many data rows a single CSV import may contain.
+const MaxBulkImportRows = 500
+
+// Row statuses reported by BulkImport.
+const (
+	BulkImportRowCreated = "created"
+	BulkImportRowError   = "error"
+)
+
+// multiValueSeparator splits list columns such as feelings or flavors.
+const multiValueSeparator = "|"
+
+var (
+	bulkImportRequiredColumns = []string{
+		"sku", "title", "category", "unit", "moq", "price_cents",
+		"feelings", "flavors", "usage", "available_qty",
+	}
+	bulkImportOptionalColumns = []string{
+		"subtitle", "body_html", "strain", "classification", "batch_id", "metric_tag", "barcode",
+		"packaging_type", "compare_at_price_cents", "thc_percent", "cbd_percent",
+		"low_stock_threshold", "max_qty", "is_active", "is_featured",
+	}
+)
+
+// BulkImportRowResult reports the outcome for one CSV data row.
+type BulkImportRowResult struct {
+	Line      int        `json:"line"`
+	SKU       string     `json:"sku,omitempty"`
+	Status    string     `json:"status"`
+	ProductID *uuid.UUID `json:"product_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// BulkImportResult summarises a CSV import.
+type BulkImportResult struct {
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Rows    []BulkImportRowResult `json:"rows"`
+}
+
+type bulkImportRow struct {
+	line  int
+	sku   string
+	input CreateProductInput
+	err   error
+}
+
+// BulkImport creates every valid row of the CSV in a single transaction and reports per-row results.
+func (s *service) BulkImport(ctx context.Context, userID, storeID uuid.UUID, reader io.Reader) (*BulkImportResult, error) {
+	vendor, err := s.loadVendorStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	rows, err := parseBulkImportCSV(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkImportResult{Rows: make([]BulkImportRowResult, len(rows))}
+	if err := s.dbClient.WithTx(ctx, func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		for i, row := range rows {
+			if row.err != nil {
+				continue
+			}
+			created, err := createImportedProduct(ctx, txRepo, vendor, row)
+			if err != nil {
+				return err
+			}
+			if _, err := txRepo.UpsertInventory(ctx, newInventoryModel(created.ID, row.input.Inventory)); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, fmt.Sprintf("db: upsert inventory on line %d", row.line))
+			}
+			if err := recordVendorInventoryEdit(ctx, tx, s.outbox, created.ID, row.input.Inventory.AvailableQty, userID, storeID); err != nil {
+				return err
+			}
+			id := created.ID
+			result.Rows[i].ProductID = &id
+		}
+		// Past the deadline the client may already hold a 504; roll back instead of committing.
+		if err := ctx.Err(); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeTimeout, err, "bulk import deadline exceeded")
+		}
+		return nil
+	}); err != nil {
+		if pkgerrors.As(err) != nil {
+			return nil, err
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "bulk import products")
+	}
+
+	for i, row := range rows {
+		result.Rows[i].Line = row.line
+		result.Rows[i].SKU = row.sku
+		if row.err != nil {
+			result.Rows[i].Status = BulkImportRowError
+			result.Rows[i].Error = row.err.Error()
+			result.Failed++
+			continue
+		}
+		result.Rows[i].Status = BulkImportRowCreated
+		result.Created++
+	}
+	return result, nil
+}
+
+// createImportedProduct inserts one parsed row. GORM drops a false is_active on insert because
+// the column defaults to true, so inactive rows are deactivated explicitly afterwards.
+func createImportedProduct(ctx context.Context, txRepo *Repository, vendor *models.Store, row bulkImportRow) (*models.Product, error) {
+	created, err := txRepo.CreateProduct(ctx, newProductModel(vendor, row.input))
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, fmt.Sprintf("db: insert product on line %d", row.line))
+	}
+	if !row.input.IsActive {
+		if err := txRepo.SetProductActive(ctx, created.ID, false); err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, fmt.Sprintf("db: deactivate product on line %d", row.line))
+		}
+		created.IsActive = false
+	}
+	return created, nil
+}
+
+// parseBulkImportCSV reads the header and data rows, validating each row independently.
+// Header problems and oversized files fail the whole import; row problems are recorded on the row.
+func parseBulkImportCSV(reader io.Reader) ([]bulkImportRow, error) {
+	if reader == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "csv file is required")
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "csv file is empty")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid csv header")
+	}
+	columns, err := parseBulkImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	csvReader.FieldsPerRecord = len(header)
+
+	rows := make([]bulkImportRow, 0)
+	seenSKUs := make(map[string]int)
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid csv")
+		}
+		line, _ := csvReader.FieldPos(0)
+		if err != nil {
+			rows = append(rows, bulkImportRow{line: line, err: fmt.Errorf("expected %d columns", len(header))})
+		} else {
+			row := parseBulkImportRecord(columns, record)
+			row.line = line
+			if row.err == nil {
+				if firstLine, ok := seenSKUs[row.sku]; ok {
+					row.err = fmt.Errorf("duplicate sku %q (first seen on line %d)", row.sku, firstLine)
+				} else {
+					seenSKUs[row.sku] = line
+				}
+			}
+			rows = append(rows, row)
+		}
+
+		if len(rows) > MaxBulkImportRows {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("csv exceeds %d rows", MaxBulkImportRows))
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "csv contains no rows")
+	}
+	return rows, nil
+}
+
+func parseBulkImportHeader(header []string) (map[string]int, error) {
+	known := make(map[string]struct{}, len(bulkImportRequiredColumns)+len(bulkImportOptionalColumns))
+	for _, name := range bulkImportRequiredColumns {
+		known[name] = struct{}{}
+	}
+	for _, name := range bulkImportOptionalColumns {
+		known[name] = struct{}{}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, raw := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff")))
+		if _, ok := known[name]; !ok {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("unknown csv column %q", raw))
+		}
+		if _, dup := columns[name]; dup {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("duplicate csv column %q", name))
+		}
+		columns[name] = i
+	}
+
+	missing := make([]string, 0)
+	for _, name := range bulkImportRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "csv header missing required columns").WithDetails(map[string]any{
+			"missing": missing,
+		})
+	}
+	return columns, nil
+}
+
+func parseBulkImportRecord(columns map[string]int, record []string) bulkImportRow {
+	get := func(name string) string {
+		idx, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+	optional := func(name string) *string {
+		if value := get(name); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	row := bulkImportRow{sku: get("sku")}
+	fail := func(err error) bulkImportRow {
+		row.err = err
+		return row
+	}
+
+	category, err := enums.ParseProductCategory(get("category"))
+	if err != nil {
+		return fail(fmt.Errorf("invalid category"))
+	}
+	unit, err := enums.ParseProductUnit(get("unit"))
+	if err != nil {
+		return fail(fmt.Errorf("invalid unit"))
+	}
+
+	var classification *enums.ProductClassification
+	if raw := get("classification"); raw != "" {
+		parsed, err := enums.ParseProductClassification(raw)
+		if err != nil {
+			return fail(fmt.Errorf("invalid classification"))
+		}
+		classification = &parsed
+	}
+
+	feelings, err := parseMultiValue(get("feelings"), enums.ParseProductFeeling)
+	if err != nil {
+		return fail(fmt.Errorf("invalid feelings: %w", err))
+	}
+	flavors, err := parseMultiValue(get("flavors"), enums.ParseProductFlavor)
+	if err != nil {
+		return fail(fmt.Errorf("invalid flavors: %w", err))
+	}
+	usage, err := parseMultiValue(get("usage"), enums.ParseProductUsage)
+	if err != nil {
+		return fail(fmt.Errorf("invalid usage: %w", err))
+	}
+
+	moq, err := parseRequiredInt(get("moq"), "moq")
+	if err != nil {
+		return fail(err)
+	}
+	priceCents, err := parseRequiredInt(get("price_cents"), "price_cents")
+	if err != nil {
+		return fail(err)
+	}
+	availableQty, err := parseRequiredInt(get("available_qty"), "available_qty")
+	if err != nil {
+		return fail(err)
+	}
+	compareAt, err := parseOptionalInt(get("compare_at_price_cents"), "compare_at_price_cents")
+	if err != nil {
+		return fail(err)
+	}
+	lowStock, err := parseOptionalInt(get("low_stock_threshold"), "low_stock_threshold")
+	if err != nil {
+		return fail(err)
+	}
+	maxQty, err := parseOptionalInt(get("max_qty"), "max_qty")
+	if err != nil {
+		return fail(err)
+	}
+	thc, err := parseOptionalFloat(get("thc_percent"), "thc_percent")
+	if err != nil {
+		return fail(err)
+	}
+	cbd, err := parseOptionalFloat(get("cbd_percent"), "cbd_percent")
+	if err != nil {
+		return fail(err)
+	}
+	isActive, err := parseOptionalBool(get("is_active"), "is_active", true)
+	if err != nil {
+		return fail(err)
+	}
+	isFeatured, err := parseOptionalBool(get("is_featured"), "is_featured", false)
+	if err != nil {
+		return fail(err)
+	}
+
+	row.input = CreateProductInput{
+		SKU:                 row.sku,
+		Title:               get("title"),
+		Subtitle:            optional("subtitle"),
+		BodyHTML:            optional("body_html"),
+		BatchID:             optional("batch_id"),
+		MetricTag:           optional("metric_tag"),
+		Barcode:             optional("barcode"),
+		Category:            category,
+		Feelings:            feelings,
+		Flavors:             flavors,
+		Usage:               usage,
+		Strain:              optional("strain"),
+		Classification:      classification,
+		Unit:                unit,
+		MOQ:                 moq,
+		PriceCents:          priceCents,
+		CompareAtPriceCents: compareAt,
+		IsActive:            isActive,
+		IsFeatured:          isFeatured,
+		THCPercent:          thc,
+		CBDPercent:          cbd,
+		Inventory: InventoryInput{
+			AvailableQty:      availableQty,
+			LowStockThreshold: intOrZero(lowStock),
+		},
+		MaxQty:        intOrZero(maxQty),
+		PackagingType: optional("packaging_type"),
+	}
+	if err := validateBulkImportRow(row.input); err != nil {
+		return fail(err)
+	}
+	if err := validateCreateProductInput(row.input); err != nil {
+		if typed := pkgerrors.As(err); typed != nil {
+			return fail(errors.New(typed.Message()))
+		}
+		return fail(err)
+	}
+	return row
+}
+
+// validateBulkImportRow applies the field rules the create product payload enforces through its
+// validate tags, which CSV rows never pass through.
+func validateBulkImportRow(input CreateProductInput) error {
+	switch {
+	case input.SKU == "":
+		return errors.New("sku is required")
+	case input.Title == "":
+		return errors.New("title is required")
+	case len(input.Feelings) == 0 || len(input.Flavors) == 0 || len(input.Usage) == 0:
+		return errors.New("feelings, flavors, and usage are required")
+	case input.MOQ < 1:
+		return errors.New("moq must be at least 1")
+	case input.PriceCents < 0:
+		return errors.New("price_cents must be non-negative")
+	case input.CompareAtPriceCents != nil && *input.CompareAtPriceCents < 0:
+		return errors.New("compare_at_price_cents must be non-negative")
+	case input.THCPercent != nil && (*input.THCPercent < 0 || *input.THCPercent > 100):
+		return errors.New("thc_percent must be between 0 and 100")
+	case input.CBDPercent != nil && (*input.CBDPercent < 0 || *input.CBDPercent > 100):
+		return errors.New("cbd_percent must be between 0 and 100")
+	case input.Inventory.AvailableQty < 0:
+		return errors.New("available_qty must be non-negative")
+	}
+	return nil
+}
+
+func parseMultiValue[T interface{ String() string }](raw string, parser func(string) (T, error)) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, multiValueSeparator)
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		parsed, err := parser(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, parsed.String())
+	}
+	return values, nil
+}
+
+func parseRequiredInt(raw, field string) (int, error) {
+	if raw == "" {
+		return 0, fmt.Errorf("%s is required", field)
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", field)
+	}
+	return value, nil
+}
+
+func parseOptionalInt(raw, field string) (*int, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", field)
+	}
+	return &value, nil
+}
+
+func parseOptionalFloat(raw, field string) (*float64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", field)
+	}
+	return &value, nil
+}
+
+func parseOptionalBool(raw, field string, fallback bool) (bool, error) {
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", field)
+	}
+	return value, nil
+}
+
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
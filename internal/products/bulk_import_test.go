@@ -0,0 +1,163 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+const bulkImportTestHeader = "sku,title,category,unit,moq,price_cents,feelings,flavors,usage,available_qty,thc_percent,is_active\n"
+
+func TestParseBulkImportCSVValidFile(t *testing.T) {
+	csv := bulkImportTestHeader +
+		"SKU-1,Blue Dream,flower,gram,1,1200,relaxed|happy,citrus,stress_relief,50,21.5,true\n" +
+		"SKU-2,Sour Cart,cart,unit,2,3000,focused,diesel|pine,pain_relief,10,,\n"
+
+	rows, err := parseBulkImportCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if row.err != nil {
+			t.Fatalf("line %d: unexpected error %v", row.line, row.err)
+		}
+	}
+
+	first := rows[0]
+	if first.line != 2 || first.input.SKU != "SKU-1" || first.input.Category != enums.ProductCategoryFlower {
+		t.Fatalf("unexpected first row %+v", first)
+	}
+	if len(first.input.Feelings) != 2 || first.input.Feelings[1] != "happy" {
+		t.Fatalf("expected split feelings, got %v", first.input.Feelings)
+	}
+	if first.input.THCPercent == nil || *first.input.THCPercent != 21.5 {
+		t.Fatalf("expected thc 21.5, got %v", first.input.THCPercent)
+	}
+	if first.input.Inventory.AvailableQty != 50 {
+		t.Fatalf("expected available qty 50, got %d", first.input.Inventory.AvailableQty)
+	}
+
+	second := rows[1]
+	if !second.input.IsActive {
+		t.Fatalf("expected is_active to default true")
+	}
+	if second.input.THCPercent != nil {
+		t.Fatalf("expected empty thc to stay nil")
+	}
+}
+
+func TestParseBulkImportCSVReportsInvalidRows(t *testing.T) {
+	csv := bulkImportTestHeader +
+		"SKU-1,Blue Dream,flower,gram,1,1200,relaxed,citrus,sleep,5,,\n" +
+		"SKU-2,Bad Category,shoes,gram,1,1200,relaxed,citrus,sleep,5,,\n" +
+		"SKU-3,Zero MOQ,flower,gram,0,1200,relaxed,citrus,sleep,5,,\n" +
+		"SKU-4,Too Potent,flower,gram,1,1200,relaxed,citrus,sleep,5,140,\n" +
+		"SKU-1,Duplicate,flower,gram,1,1200,relaxed,citrus,sleep,5,,\n" +
+		"SKU-6,Short Row\n" +
+		"SKU-7,No Feelings,flower,gram,1,1200,,citrus,sleep,5,,\n"
+
+	rows, err := parseBulkImportCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+
+	wantErr := map[int]string{
+		3: "invalid category",
+		4: "moq must be at least 1",
+		5: "thc_percent must be between 0 and 100",
+		6: "duplicate sku",
+		7: "expected 12 columns",
+		8: "feelings, flavors, and usage are required",
+	}
+	if len(rows) != 7 {
+		t.Fatalf("expected 7 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		want, bad := wantErr[row.line]
+		switch {
+		case !bad && row.err != nil:
+			t.Fatalf("line %d: unexpected error %v", row.line, row.err)
+		case bad && row.err == nil:
+			t.Fatalf("line %d: expected error containing %q", row.line, want)
+		case bad && !strings.Contains(row.err.Error(), want):
+			t.Fatalf("line %d: expected error containing %q, got %q", row.line, want, row.err.Error())
+		}
+	}
+}
+
+func TestParseBulkImportCSVMalformedHeader(t *testing.T) {
+	cases := map[string]string{
+		"missing required column": "sku,title,category,unit,moq,price_cents,feelings,flavors,usage\n",
+		"unknown column":          "sku,title,category,unit,moq,price_cents,feelings,flavors,usage,available_qty,color\n",
+		"duplicate column":        "sku,sku,title,category,unit,moq,price_cents,feelings,flavors,usage,available_qty\n",
+		"empty file":              "",
+	}
+	for name, csv := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseBulkImportCSV(strings.NewReader(csv))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseBulkImportCSVRowCap(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(bulkImportTestHeader)
+	for i := 0; i <= MaxBulkImportRows; i++ {
+		fmt.Fprintf(&b, "SKU-%d,Item,flower,gram,1,100,relaxed,citrus,sleep,1,,\n", i)
+	}
+
+	_, err := parseBulkImportCSV(strings.NewReader(b.String()))
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error for oversized file, got %v", err)
+	}
+}
+
+func TestCreateImportedProductKeepsInactiveRows(t *testing.T) {
+	conn := openTestDB(t)
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin tx: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	user := mustCreateTestUser(t, tx)
+	store := mustCreateTestStore(t, tx, user.ID)
+
+	csv := bulkImportTestHeader + "SKU-OFF,Paused Haze,flower,gram,1,1200,relaxed,citrus,stress_relief,5,,false\n"
+	rows, err := parseBulkImportCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if rows[0].err != nil || rows[0].input.IsActive {
+		t.Fatalf("expected a valid inactive row, got %+v", rows[0])
+	}
+
+	repo := NewRepository(tx)
+	created, err := createImportedProduct(context.Background(), repo, store, rows[0])
+	if err != nil {
+		t.Fatalf("create imported product: %v", err)
+	}
+
+	detail, _, err := repo.GetProductDetail(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("get detail: %v", err)
+	}
+	if detail.IsActive {
+		t.Fatalf("expected imported product to stay inactive")
+	}
+}
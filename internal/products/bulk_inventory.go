@@ -0,0 +1,132 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxBulkInventoryItems caps how many products a single bulk inventory update may touch.
+const MaxBulkInventoryItems = 500
+
+// BulkInventoryItem sets the available quantity of one product.
+type BulkInventoryItem struct {
+	ProductID    uuid.UUID
+	AvailableQty int
+}
+
+// BulkInventoryItemResult reports the stock change applied to one product.
+type BulkInventoryItemResult struct {
+	ProductID            uuid.UUID `json:"product_id"`
+	PreviousAvailableQty int       `json:"previous_available_qty"`
+	AvailableQty         int       `json:"available_qty"`
+}
+
+// BulkInventoryResult summarises a bulk inventory update.
+type BulkInventoryResult struct {
+	Updated int                       `json:"updated"`
+	Items   []BulkInventoryItemResult `json:"items"`
+}
+
+// BulkUpdateInventory sets available stock for many products of the vendor store in one
+// transaction. Any invalid item rolls back the whole batch.
+func (s *service) BulkUpdateInventory(ctx context.Context, userID, storeID uuid.UUID, items []BulkInventoryItem) (*BulkInventoryResult, error) {
+	if err := validateBulkInventoryItems(items); err != nil {
+		return nil, err
+	}
+	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	var result *BulkInventoryResult
+	if err := s.dbClient.WithTx(ctx, func(tx *gorm.DB) error {
+		var err error
+		result, err = applyBulkInventory(ctx, tx, s.repo.WithTx(tx), s.outbox, userID, storeID, items)
+		return err
+	}); err != nil {
+		if pkgerrors.As(err) != nil {
+			return nil, err
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "bulk update inventory")
+	}
+	return result, nil
+}
+
+func validateBulkInventoryItems(items []BulkInventoryItem) error {
+	if len(items) == 0 {
+		return pkgerrors.New(pkgerrors.CodeValidation, "items are required")
+	}
+	if len(items) > MaxBulkInventoryItems {
+		return pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("at most %d items per request", MaxBulkInventoryItems))
+	}
+	seen := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		if item.ProductID == uuid.Nil {
+			return pkgerrors.New(pkgerrors.CodeValidation, "product_id is required")
+		}
+		if _, ok := seen[item.ProductID]; ok {
+			return pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("duplicate product %s", item.ProductID))
+		}
+		seen[item.ProductID] = struct{}{}
+		if item.AvailableQty < 0 {
+			return pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("product %s: available_qty must be >= 0", item.ProductID))
+		}
+	}
+	return nil
+}
+
+// applyBulkInventory writes each item inside tx, keeping the product's low-stock threshold and
+// recording a vendor edit adjustment. It stops at the first invalid item so the caller rolls back.
+func applyBulkInventory(ctx context.Context, tx *gorm.DB, txRepo *Repository, publisher inventory.OutboxPublisher, userID, storeID uuid.UUID, items []BulkInventoryItem) (*BulkInventoryResult, error) {
+	result := &BulkInventoryResult{Items: make([]BulkInventoryItemResult, 0, len(items))}
+	for _, item := range items {
+		product, err := txRepo.FindByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, pkgerrors.New(pkgerrors.CodeNotFound, fmt.Sprintf("product %s not found", item.ProductID))
+			}
+			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+		}
+		if product.StoreID != storeID {
+			return nil, pkgerrors.New(pkgerrors.CodeForbidden, fmt.Sprintf("product %s does not belong to store", item.ProductID))
+		}
+
+		threshold := 0
+		existing, err := txRepo.FindInventoryByProductID(ctx, item.ProductID)
+		switch {
+		case err == nil:
+			threshold = existing.LowStockThreshold
+			if existing.ReservedQty > item.AvailableQty {
+				return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("product %s: reserved_qty cannot exceed available_qty", item.ProductID))
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load inventory")
+		}
+
+		previousAvailable, err := writeVendorInventory(ctx, txRepo, item.ProductID, InventoryInput{
+			AvailableQty:      item.AvailableQty,
+			LowStockThreshold: threshold,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := recordVendorInventoryEdit(ctx, tx, publisher, item.ProductID, item.AvailableQty-previousAvailable, userID, storeID); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, BulkInventoryItemResult{
+			ProductID:            item.ProductID,
+			PreviousAvailableQty: previousAvailable,
+			AvailableQty:         item.AvailableQty,
+		})
+	}
+	result.Updated = len(result.Items)
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openBulkInventoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE products (id TEXT PRIMARY KEY, store_id TEXT NOT NULL, is_active INTEGER NOT NULL DEFAULT 1, deleted_at DATETIME)`,
+		`CREATE TABLE inventory_items (
+  product_id TEXT PRIMARY KEY,
+  available_qty INTEGER NOT NULL DEFAULT 0,
+  reserved_qty INTEGER NOT NULL DEFAULT 0,
+  low_stock_threshold INTEGER NOT NULL DEFAULT 0,
+  version INTEGER NOT NULL DEFAULT 0,
+  low_stock_alerted_at DATETIME,
+  low_stock_alert_threshold INTEGER,
+  updated_at DATETIME
+)`,
+		`CREATE TABLE inventory_adjustments (
+  id TEXT PRIMARY KEY,
+  product_id TEXT NOT NULL,
+  available_delta INTEGER NOT NULL,
+  reserved_delta INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  actor_user_id TEXT,
+  actor_store_id TEXT,
+  created_at DATETIME
+)`,
+		`CREATE TABLE outbox_events (
+  id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  event_type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at DATETIME,
+  published_at DATETIME,
+  attempt_count INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  dedup_key TEXT
+)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("create table: %v", err)
+		}
+	}
+	return db
+}
+
+func seedBulkInventoryProduct(t *testing.T, db *gorm.DB, storeID uuid.UUID, available, reserved, threshold int) uuid.UUID {
+	t.Helper()
+	productID := uuid.New()
+	if err := db.Exec(`INSERT INTO products (id, store_id) VALUES (?, ?)`, productID, storeID).Error; err != nil {
+		t.Fatalf("insert product: %v", err)
+	}
+	if err := db.Exec(
+		`INSERT INTO inventory_items (product_id, available_qty, reserved_qty, low_stock_threshold) VALUES (?, ?, ?, ?)`,
+		productID, available, reserved, threshold,
+	).Error; err != nil {
+		t.Fatalf("insert inventory: %v", err)
+	}
+	return productID
+}
+
+func runBulkInventory(db *gorm.DB, userID, storeID uuid.UUID, items []BulkInventoryItem) (*BulkInventoryResult, error) {
+	var result *BulkInventoryResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = applyBulkInventory(context.Background(), tx, NewRepository(tx), outbox.NewService(outbox.NewRepository(tx), nil), userID, storeID, items)
+		return err
+	})
+	return result, err
+}
+
+func TestApplyBulkInventoryUpdatesEveryItem(t *testing.T) {
+	db := openBulkInventoryTestDB(t)
+	userID, storeID := uuid.New(), uuid.New()
+	first := seedBulkInventoryProduct(t, db, storeID, 5, 2, 4)
+	second := seedBulkInventoryProduct(t, db, storeID, 10, 0, 0)
+
+	result, err := runBulkInventory(db, userID, storeID, []BulkInventoryItem{
+		{ProductID: first, AvailableQty: 25},
+		{ProductID: second, AvailableQty: 3},
+	})
+	if err != nil {
+		t.Fatalf("bulk update: %v", err)
+	}
+	if result.Updated != 2 || len(result.Items) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if got := result.Items[0]; got.ProductID != first || got.PreviousAvailableQty != 5 || got.AvailableQty != 25 {
+		t.Fatalf("unexpected first item %+v", got)
+	}
+	if got := result.Items[1]; got.ProductID != second || got.PreviousAvailableQty != 10 || got.AvailableQty != 3 {
+		t.Fatalf("unexpected second item %+v", got)
+	}
+
+	var inv models.InventoryItem
+	if err := db.First(&inv, "product_id = ?", first).Error; err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+	if inv.AvailableQty != 25 || inv.ReservedQty != 2 || inv.LowStockThreshold != 4 {
+		t.Fatalf("expected threshold and reservation kept, got %+v", inv)
+	}
+
+	var deltas []int
+	if err := db.Table("inventory_adjustments").Order("available_delta").Pluck("available_delta", &deltas).Error; err != nil {
+		t.Fatalf("load adjustments: %v", err)
+	}
+	if len(deltas) != 2 || deltas[0] != -7 || deltas[1] != 20 {
+		t.Fatalf("expected adjustments -7 and 20, got %v", deltas)
+	}
+}
+
+func TestApplyBulkInventoryRollsBackOnInvalidItem(t *testing.T) {
+	db := openBulkInventoryTestDB(t)
+	userID, storeID := uuid.New(), uuid.New()
+	valid := seedBulkInventoryProduct(t, db, storeID, 5, 0, 0)
+	reserved := seedBulkInventoryProduct(t, db, storeID, 8, 6, 0)
+
+	_, err := runBulkInventory(db, userID, storeID, []BulkInventoryItem{
+		{ProductID: valid, AvailableQty: 50},
+		{ProductID: reserved, AvailableQty: 4},
+	})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	var inv models.InventoryItem
+	if err := db.First(&inv, "product_id = ?", valid).Error; err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+	if inv.AvailableQty != 5 {
+		t.Fatalf("expected first item rolled back to 5, got %d", inv.AvailableQty)
+	}
+	var adjustments int64
+	if err := db.Table("inventory_adjustments").Count(&adjustments).Error; err != nil {
+		t.Fatalf("count adjustments: %v", err)
+	}
+	if adjustments != 0 {
+		t.Fatalf("expected no adjustments after rollback, got %d", adjustments)
+	}
+}
+
+func TestApplyBulkInventoryRejectsForeignProduct(t *testing.T) {
+	db := openBulkInventoryTestDB(t)
+	storeID := uuid.New()
+	foreign := seedBulkInventoryProduct(t, db, uuid.New(), 5, 0, 0)
+
+	_, err := runBulkInventory(db, uuid.New(), storeID, []BulkInventoryItem{{ProductID: foreign, AvailableQty: 1}})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeForbidden {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestValidateBulkInventoryItems(t *testing.T) {
+	id := uuid.New()
+	cases := map[string][]BulkInventoryItem{
+		"empty":     nil,
+		"nil id":    {{AvailableQty: 1}},
+		"negative":  {{ProductID: id, AvailableQty: -1}},
+		"duplicate": {{ProductID: id, AvailableQty: 1}, {ProductID: id, AvailableQty: 2}},
+		"too many":  make([]BulkInventoryItem, MaxBulkInventoryItems+1),
+	}
+	for name, items := range cases {
+		var typed *pkgerrors.Error
+		if err := validateBulkInventoryItems(items); !errors.As(err, &typed) || typed.Code() != pkgerrors.CodeValidation {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+	if err := validateBulkInventoryItems([]BulkInventoryItem{{ProductID: id, AvailableQty: 0}}); err != nil {
+		t.Fatalf("expected valid batch, got %v", err)
+	}
+}
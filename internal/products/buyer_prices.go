@@ -0,0 +1,169 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BuyerPriceDTO exposes a buyer-specific price override for a product.
+type BuyerPriceDTO struct {
+	ProductID    uuid.UUID `json:"product_id"`
+	BuyerStoreID uuid.UUID `json:"buyer_store_id"`
+	PriceCents   int       `json:"price_cents"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ListBuyerPrices returns the buyer price overrides on a product owned by the vendor store.
+func (s *service) ListBuyerPrices(ctx context.Context, userID, storeID, productID uuid.UUID) ([]BuyerPriceDTO, error) {
+	if _, err := s.loadOwnedProduct(ctx, userID, storeID, productID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListBuyerPrices(ctx, productID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list buyer prices")
+	}
+	prices := make([]BuyerPriceDTO, 0, len(rows))
+	for _, row := range rows {
+		prices = append(prices, buyerPriceDTO(row))
+	}
+	return prices, nil
+}
+
+// SetBuyerPrice creates or replaces the price the buyer store pays for the product. Volume discounts
+// still apply on top of it at quote time.
+func (s *service) SetBuyerPrice(ctx context.Context, userID, storeID, productID, buyerStoreID uuid.UUID, priceCents int) (*BuyerPriceDTO, error) {
+	if priceCents < 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "price_cents must be >= 0")
+	}
+	if _, err := s.loadOwnedProduct(ctx, userID, storeID, productID); err != nil {
+		return nil, err
+	}
+
+	buyer, err := s.storeRepo.FindByID(ctx, buyerStoreID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "buyer store not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load buyer store")
+	}
+	if buyer.Type != enums.StoreTypeBuyer {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "price overrides can only target buyer stores")
+	}
+
+	price, err := s.repo.UpsertBuyerPrice(ctx, &models.ProductBuyerPrice{
+		StoreID:      storeID,
+		ProductID:    productID,
+		BuyerStoreID: buyerStoreID,
+		PriceCents:   priceCents,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "save buyer price")
+	}
+	dto := buyerPriceDTO(*price)
+	return &dto, nil
+}
+
+// DeleteBuyerPrice removes the buyer's override so they fall back to the product base price.
+func (s *service) DeleteBuyerPrice(ctx context.Context, userID, storeID, productID, buyerStoreID uuid.UUID) error {
+	if _, err := s.loadOwnedProduct(ctx, userID, storeID, productID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteBuyerPrice(ctx, productID, buyerStoreID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "delete buyer price")
+	}
+	if !deleted {
+		return pkgerrors.New(pkgerrors.CodeNotFound, "buyer price not found")
+	}
+	return nil
+}
+
+// loadOwnedProduct checks the caller may manage the vendor store's catalog and that the product
+// belongs to it.
+func (s *service) loadOwnedProduct(ctx context.Context, userID, storeID, productID uuid.UUID) (*models.Product, error) {
+	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+	}
+	if product.StoreID != storeID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
+	}
+	return product, nil
+}
+
+func buyerPriceDTO(row models.ProductBuyerPrice) BuyerPriceDTO {
+	return BuyerPriceDTO{
+		ProductID:    row.ProductID,
+		BuyerStoreID: row.BuyerStoreID,
+		PriceCents:   row.PriceCents,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+// FindBuyerPrice returns the buyer's price override for the product, or gorm.ErrRecordNotFound.
+func (r *Repository) FindBuyerPrice(ctx context.Context, productID, buyerStoreID uuid.UUID) (*models.ProductBuyerPrice, error) {
+	var price models.ProductBuyerPrice
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND buyer_store_id = ?", productID, buyerStoreID).
+		First(&price).Error; err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// ListBuyerPrices returns every buyer override on a product, oldest first.
+func (r *Repository) ListBuyerPrices(ctx context.Context, productID uuid.UUID) ([]models.ProductBuyerPrice, error) {
+	var rows []models.ProductBuyerPrice
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at ASC, id ASC").
+		Find(&rows).
+		Error
+	return rows, err
+}
+
+// UpsertBuyerPrice inserts the override or updates the price of the existing one for the same
+// product and buyer.
+func (r *Repository) UpsertBuyerPrice(ctx context.Context, price *models.ProductBuyerPrice) (*models.ProductBuyerPrice, error) {
+	if price.ID == uuid.Nil {
+		price.ID = uuid.New()
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "buyer_store_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price_cents", "updated_at"}),
+		}).
+		Create(price).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindBuyerPrice(ctx, price.ProductID, price.BuyerStoreID)
+}
+
+// DeleteBuyerPrice removes the override and reports whether one existed.
+func (r *Repository) DeleteBuyerPrice(ctx context.Context, productID, buyerStoreID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("product_id = ? AND buyer_store_id = ?", productID, buyerStoreID).
+		Delete(&models.ProductBuyerPrice{})
+	return result.RowsAffected > 0, result.Error
+}
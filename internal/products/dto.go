@@ -38,9 +38,23 @@ type ProductDTO struct {
 	COAReadURL          *string             `json:"coa_read_url,omitempty"`
 	Vendor              VendorSummaryDTO    `json:"vendor"`
 	MaxQty              int                 `json:"max_qty"`
+	ExcludedStates      []string            `json:"excluded_states,omitempty"`
 	CreatedAt           time.Time           `json:"created_at"`
 	UpdatedAt           time.Time           `json:"updated_at"`
 	PackagingType       *string             `json:"packaging_type"`
+	// SignedURLsExpireAt is when the earliest signed URL in Media or COAReadURL stops working, or
+	// zero when none of them expire.
+	SignedURLsExpireAt time.Time `json:"-"`
+}
+
+// noteURLExpiry keeps the earliest signed URL expiry seen so far.
+func (dto *ProductDTO) noteURLExpiry(expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		return
+	}
+	if dto.SignedURLsExpireAt.IsZero() || expiresAt.Before(dto.SignedURLsExpireAt) {
+		dto.SignedURLsExpireAt = expiresAt
+	}
 }
 
 // InventoryDTO exposes inventory counts.
@@ -66,6 +80,7 @@ type ProductMediaDTO struct {
 	GCSKey    string     `json:"gcs_key"`
 	MediaID   *uuid.UUID `json:"media_id,omitempty"`
 	Position  int        `json:"position"`
+	IsPrimary bool       `json:"is_primary"`
 	CreatedAt time.Time  `json:"created_at"`
 }
 
@@ -109,7 +124,7 @@ type ProductSummary struct {
 // ProductListResult wraps a page of product summaries plus the cursor for the next page.
 type ProductPagination struct {
 	Page    int    `json:"page"`
-	Total   int    `json:"total"`
+	Total   *int   `json:"total,omitempty"`
 	Current string `json:"current,omitempty"`
 	First   string `json:"first,omitempty"`
 	Last    string `json:"last,omitempty"`
@@ -147,6 +162,7 @@ func NewProductDTO(product *models.Product, summary *VendorSummary) *ProductDTO
 		CreatedAt:           product.CreatedAt,
 		UpdatedAt:           product.UpdatedAt,
 		MaxQty:              product.MaxQty,
+		ExcludedStates:      product.ExcludedStates,
 	}
 	if product.Classification != nil {
 		classification := string(*product.Classification)
@@ -183,6 +199,7 @@ func NewProductDTO(product *models.Product, summary *VendorSummary) *ProductDTO
 				GCSKey:    pm.GCSKey,
 				MediaID:   pm.MediaID,
 				Position:  pm.Position,
+				IsPrimary: pm.IsPrimary,
 				CreatedAt: pm.CreatedAt,
 			}
 		}
@@ -0,0 +1,204 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// duplicateSKUSuffix is appended to the source SKU when cloning a product.
+const duplicateSKUSuffix = "-copy"
+
+// maxDuplicateSKUAttempts bounds the search for a free suffixed SKU.
+const maxDuplicateSKUAttempts = 50
+
+// DuplicateProduct clones a product, its volume discounts, and its media into an inactive draft
+// with a suffixed SKU and zero inventory.
+func (s *service) DuplicateProduct(ctx context.Context, userID, storeID, productID uuid.UUID) (*ProductDTO, error) {
+	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	source, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+	}
+	if source.StoreID != storeID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
+	}
+
+	discounts, err := s.repo.ListVolumeDiscounts(ctx, productID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load volume discounts")
+	}
+	media, err := s.repo.ListProductMedia(ctx, productID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product media")
+	}
+	threshold := 0
+	if inventory, err := s.repo.GetInventoryByProductID(ctx, productID); err == nil {
+		threshold = inventory.LowStockThreshold
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load inventory")
+	}
+
+	var cloneID uuid.UUID
+	if err := s.dbClient.WithTx(ctx, func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+
+		sku, err := nextDuplicateSKU(ctx, txRepo, storeID, source.SKU)
+		if err != nil {
+			return err
+		}
+
+		created, err := txRepo.CreateProduct(ctx, cloneProductModel(source, sku))
+		if err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: insert product")
+		}
+		cloneID = created.ID
+
+		if err := txRepo.SetProductActive(ctx, created.ID, false); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: deactivate product")
+		}
+		if _, err := txRepo.UpsertInventory(ctx, newInventoryModel(created.ID, InventoryInput{LowStockThreshold: threshold})); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: upsert inventory")
+		}
+		if err := txRepo.ReplaceVolumeDiscounts(ctx, created.ID, cloneVolumeDiscounts(discounts, created.ID)); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: insert volume discounts")
+		}
+
+		mediaRows := cloneProductMedia(media, created.ID)
+		if err := txRepo.ReplaceProductMedia(ctx, created.ID, mediaRows); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: replace product media")
+		}
+		if err := s.reconcileProductGalleryAttachments(ctx, tx, storeID, created.ID, nil, productMediaIDs(mediaRows)); err != nil {
+			return err
+		}
+		return s.reconcileProductCOAAttachments(ctx, tx, storeID, created.ID, nil, source.COAMediaID)
+	}); err != nil {
+		if pkgerrors.As(err) != nil {
+			return nil, err
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "duplicate product")
+	}
+
+	product, summary, err := s.repo.GetProductDetail(ctx, cloneID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product detail")
+	}
+	return s.newProductDTO(ctx, product, summary)
+}
+
+func nextDuplicateSKU(ctx context.Context, repo *Repository, storeID uuid.UUID, sku string) (string, error) {
+	for attempt := 1; attempt <= maxDuplicateSKUAttempts; attempt++ {
+		candidate := duplicateSKU(sku, attempt)
+		exists, err := repo.SKUExists(ctx, storeID, candidate)
+		if err != nil {
+			return "", pkgerrors.Wrap(pkgerrors.CodeDependency, err, "check sku")
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", pkgerrors.New(pkgerrors.CodeConflict, "no available sku for duplicate")
+}
+
+// duplicateSKU renders the n-th candidate SKU: "ABC-copy", "ABC-copy-2", ...
+func duplicateSKU(sku string, attempt int) string {
+	if attempt <= 1 {
+		return sku + duplicateSKUSuffix
+	}
+	return fmt.Sprintf("%s%s-%d", sku, duplicateSKUSuffix, attempt)
+}
+
+// cloneProductModel deep-copies the listing fields of src so the clone shares no pointers or
+// slices with the original. Associations, IDs, and timestamps are left for the database.
+func cloneProductModel(src *models.Product, sku string) *models.Product {
+	return &models.Product{
+		StoreID:             src.StoreID,
+		SKU:                 sku,
+		Currency:            src.Currency,
+		Title:               src.Title,
+		Subtitle:            clonePtr(src.Subtitle),
+		BodyHTML:            clonePtr(src.BodyHTML),
+		BatchID:             clonePtr(src.BatchID),
+		MetricTag:           clonePtr(src.MetricTag),
+		Barcode:             clonePtr(src.Barcode),
+		Category:            src.Category,
+		Feelings:            append(pq.StringArray(nil), src.Feelings...),
+		Flavors:             append(pq.StringArray(nil), src.Flavors...),
+		Usage:               append(pq.StringArray(nil), src.Usage...),
+		Strain:              clonePtr(src.Strain),
+		Classification:      clonePtr(src.Classification),
+		COAMediaID:          clonePtr(src.COAMediaID),
+		COAAdded:            src.COAAdded,
+		Unit:                src.Unit,
+		MOQ:                 src.MOQ,
+		PriceCents:          src.PriceCents,
+		CompareAtPriceCents: clonePtr(src.CompareAtPriceCents),
+		IsActive:            false,
+		IsFeatured:          false,
+		THCPercent:          clonePtr(src.THCPercent),
+		CBDPercent:          clonePtr(src.CBDPercent),
+		MaxQty:              src.MaxQty,
+		PackagingType:       clonePtr(src.PackagingType),
+	}
+}
+
+func cloneVolumeDiscounts(src []models.ProductVolumeDiscount, productID uuid.UUID) []models.ProductVolumeDiscount {
+	tiers := make([]models.ProductVolumeDiscount, 0, len(src))
+	for _, tier := range src {
+		tiers = append(tiers, models.ProductVolumeDiscount{
+			StoreID:         tier.StoreID,
+			ProductID:       productID,
+			MinQty:          tier.MinQty,
+			DiscountPercent: tier.DiscountPercent,
+		})
+	}
+	return tiers
+}
+
+func cloneProductMedia(src []models.ProductMedia, productID uuid.UUID) []models.ProductMedia {
+	rows := make([]models.ProductMedia, 0, len(src))
+	for _, row := range src {
+		rows = append(rows, models.ProductMedia{
+			ProductID: productID,
+			URL:       clonePtr(row.URL),
+			GCSKey:    row.GCSKey,
+			MediaID:   clonePtr(row.MediaID),
+			Position:  row.Position,
+			IsPrimary: row.IsPrimary,
+		})
+	}
+	return rows
+}
+
+func productMediaIDs(rows []models.ProductMedia) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if row.MediaID != nil {
+			ids = append(ids, *row.MediaID)
+		}
+	}
+	return ids
+}
+
+func clonePtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
@@ -0,0 +1,133 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestCloneProductModelIsIndependent(t *testing.T) {
+	subtitle := "original subtitle"
+	thc := 22.5
+	compareAt := 1500
+	classification := enums.ProductClassificationHybrid
+	source := &models.Product{
+		ID:                  uuid.New(),
+		StoreID:             uuid.New(),
+		SKU:                 "SKU-1",
+		Title:               "Blue Dream",
+		Subtitle:            &subtitle,
+		Category:            enums.ProductCategoryFlower,
+		Feelings:            pq.StringArray{"relaxed", "happy"},
+		Flavors:             pq.StringArray{"citrus"},
+		Usage:               pq.StringArray{"sleep"},
+		Classification:      &classification,
+		Unit:                enums.ProductUnitGram,
+		MOQ:                 2,
+		PriceCents:          1200,
+		CompareAtPriceCents: &compareAt,
+		IsActive:            true,
+		IsFeatured:          true,
+		THCPercent:          &thc,
+		MaxQty:              10,
+	}
+
+	clone := cloneProductModel(source, duplicateSKU(source.SKU, 1))
+
+	if clone.ID != uuid.Nil {
+		t.Fatalf("expected clone id to be assigned by the database")
+	}
+	if clone.SKU != "SKU-1-copy" {
+		t.Fatalf("unexpected sku %s", clone.SKU)
+	}
+	if clone.IsActive || clone.IsFeatured {
+		t.Fatalf("expected clone to be an inactive, unfeatured draft")
+	}
+	if clone.Title != source.Title || clone.PriceCents != source.PriceCents || clone.MOQ != source.MOQ || *clone.THCPercent != thc {
+		t.Fatalf("expected listing fields to carry over, got %+v", clone)
+	}
+
+	*clone.Subtitle = "edited"
+	*clone.THCPercent = 30
+	*clone.CompareAtPriceCents = 1
+	*clone.Classification = enums.ProductClassificationIndica
+	clone.Feelings[0] = "sleepy"
+	clone.Title = "Edited"
+
+	if *source.Subtitle != "original subtitle" || *source.THCPercent != 22.5 || *source.CompareAtPriceCents != 1500 {
+		t.Fatalf("editing clone pointers mutated the original: %+v", source)
+	}
+	if *source.Classification != enums.ProductClassificationHybrid {
+		t.Fatalf("editing clone classification mutated the original")
+	}
+	if source.Feelings[0] != "relaxed" || source.Title != "Blue Dream" {
+		t.Fatalf("editing clone slices mutated the original: %v", source.Feelings)
+	}
+}
+
+func TestCloneVolumeDiscountsCarriesTiers(t *testing.T) {
+	storeID := uuid.New()
+	sourceID := uuid.New()
+	cloneID := uuid.New()
+	source := []models.ProductVolumeDiscount{
+		{ID: uuid.New(), StoreID: storeID, ProductID: sourceID, MinQty: 10, DiscountPercent: 5},
+		{ID: uuid.New(), StoreID: storeID, ProductID: sourceID, MinQty: 50, DiscountPercent: 12.5},
+	}
+
+	tiers := cloneVolumeDiscounts(source, cloneID)
+
+	if len(tiers) != len(source) {
+		t.Fatalf("expected %d tiers, got %d", len(source), len(tiers))
+	}
+	for i, tier := range tiers {
+		if tier.ID != uuid.Nil {
+			t.Fatalf("expected tier %d id to be reset", i)
+		}
+		if tier.ProductID != cloneID || tier.StoreID != storeID {
+			t.Fatalf("tier %d not re-parented: %+v", i, tier)
+		}
+		if tier.MinQty != source[i].MinQty || tier.DiscountPercent != source[i].DiscountPercent {
+			t.Fatalf("tier %d values changed: %+v", i, tier)
+		}
+	}
+
+	tiers[0].DiscountPercent = 99
+	if source[0].DiscountPercent != 5 {
+		t.Fatalf("editing cloned tier mutated the original")
+	}
+}
+
+func TestCloneProductMediaPreservesOrder(t *testing.T) {
+	mediaID := uuid.New()
+	url := "https://cdn.example.com/a.jpg"
+	source := []models.ProductMedia{
+		{ID: uuid.New(), ProductID: uuid.New(), GCSKey: "a", MediaID: &mediaID, URL: &url, Position: 0},
+		{ID: uuid.New(), ProductID: uuid.New(), GCSKey: "b", Position: 1},
+	}
+	cloneID := uuid.New()
+
+	rows := cloneProductMedia(source, cloneID)
+
+	if len(rows) != 2 || rows[0].GCSKey != "a" || rows[1].Position != 1 || rows[0].ProductID != cloneID {
+		t.Fatalf("unexpected media rows %+v", rows)
+	}
+	if ids := productMediaIDs(rows); len(ids) != 1 || ids[0] != mediaID {
+		t.Fatalf("expected attachment ids [%s], got %v", mediaID, ids)
+	}
+	*rows[0].URL = "changed"
+	if url != "https://cdn.example.com/a.jpg" {
+		t.Fatalf("editing cloned media mutated the original url")
+	}
+}
+
+func TestDuplicateSKUSuffixes(t *testing.T) {
+	if got := duplicateSKU("ABC", 1); got != "ABC-copy" {
+		t.Fatalf("unexpected first candidate %s", got)
+	}
+	if got := duplicateSKU("ABC", 3); got != "ABC-copy-3" {
+		t.Fatalf("unexpected third candidate %s", got)
+	}
+}
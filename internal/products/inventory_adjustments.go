@@ -0,0 +1,107 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InventoryAdjustmentDTO exposes a single inventory audit entry.
+type InventoryAdjustmentDTO struct {
+	ID             uuid.UUID                       `json:"id"`
+	ProductID      uuid.UUID                       `json:"product_id"`
+	AvailableDelta int                             `json:"available_delta"`
+	ReservedDelta  int                             `json:"reserved_delta"`
+	Reason         enums.InventoryAdjustmentReason `json:"reason"`
+	ActorUserID    *uuid.UUID                      `json:"actor_user_id,omitempty"`
+	ActorStoreID   *uuid.UUID                      `json:"actor_store_id,omitempty"`
+	CreatedAt      time.Time                       `json:"created_at"`
+}
+
+// InventoryAdjustmentList wraps a page of inventory history, newest first.
+type InventoryAdjustmentList struct {
+	Adjustments []InventoryAdjustmentDTO `json:"adjustments"`
+	NextCursor  string                   `json:"next_cursor,omitempty"`
+}
+
+// ListInventoryAdjustments returns the inventory history for a product owned by the vendor store.
+func (s *service) ListInventoryAdjustments(ctx context.Context, userID, storeID, productID uuid.UUID, params pagination.Params) (*InventoryAdjustmentList, error) {
+	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+	}
+	if product.StoreID != storeID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
+	}
+
+	var cursor *pagination.Cursor
+	if value := strings.TrimSpace(params.Cursor); value != "" {
+		cursor, err = pagination.ParseCursor(value)
+		if err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid cursor")
+		}
+	}
+
+	rows, next, err := s.repo.ListInventoryAdjustments(ctx, productID, params.Limit, cursor)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list inventory adjustments")
+	}
+
+	result := &InventoryAdjustmentList{Adjustments: make([]InventoryAdjustmentDTO, 0, len(rows))}
+	for _, row := range rows {
+		result.Adjustments = append(result.Adjustments, InventoryAdjustmentDTO{
+			ID:             row.ID,
+			ProductID:      row.ProductID,
+			AvailableDelta: row.AvailableDelta,
+			ReservedDelta:  row.ReservedDelta,
+			Reason:         row.Reason,
+			ActorUserID:    row.ActorUserID,
+			ActorStoreID:   row.ActorStoreID,
+			CreatedAt:      row.CreatedAt,
+		})
+	}
+	if next != nil {
+		result.NextCursor = pagination.EncodeCursor(*next)
+	}
+	return result, nil
+}
+
+// ListInventoryAdjustments pages through a product's inventory history ordered by newest first.
+func (r *Repository) ListInventoryAdjustments(ctx context.Context, productID uuid.UUID, limit int, cursor *pagination.Cursor) ([]models.InventoryAdjustment, *pagination.Cursor, error) {
+	normalized := pagination.NormalizeLimit(limit)
+	query := r.db.WithContext(ctx).
+		Model(&models.InventoryAdjustment{}).
+		Where("product_id = ?", productID)
+	if cursor != nil {
+		clause, args := cursor.Before("")
+		query = query.Where(clause, args...)
+	}
+
+	var rows []models.InventoryAdjustment
+	if err := query.Order("created_at DESC, id DESC").Limit(pagination.LimitWithBuffer(limit)).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(rows) > normalized {
+		last := rows[normalized-1]
+		return rows[:normalized], &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+	}
+	return rows, nil, nil
+}
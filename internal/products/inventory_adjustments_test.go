@@ -0,0 +1,67 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRepositoryListInventoryAdjustmentsPagesWithoutGaps(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE inventory_adjustments (
+  id TEXT PRIMARY KEY,
+  product_id TEXT NOT NULL,
+  available_delta INTEGER NOT NULL,
+  reserved_delta INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  actor_user_id TEXT,
+  actor_store_id TEXT,
+  created_at DATETIME
+)`).Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	productID := uuid.New()
+	base := time.Date(2028, 1, 5, 12, 0, 0, 0, time.UTC)
+	// Two rows share a timestamp so the id tiebreaker is exercised at a page boundary.
+	for i, createdAt := range []time.Time{base, base.Add(time.Minute), base.Add(time.Minute), base.Add(2 * time.Minute), base.Add(3 * time.Minute)} {
+		if err := db.Exec(
+			`INSERT INTO inventory_adjustments (id, product_id, available_delta, reserved_delta, reason, created_at) VALUES (?, ?, ?, 0, ?, ?)`,
+			uuid.New(), productID, i+1, enums.InventoryAdjustmentReasonVendorEdit, createdAt,
+		).Error; err != nil {
+			t.Fatalf("insert adjustment: %v", err)
+		}
+	}
+
+	repo := NewRepository(db)
+	seen := map[uuid.UUID]bool{}
+	var cursor *pagination.Cursor
+	for page := 0; page < 5; page++ {
+		rows, next, err := repo.ListInventoryAdjustments(context.Background(), productID, 2, cursor)
+		if err != nil {
+			t.Fatalf("list page %d: %v", page, err)
+		}
+		for _, row := range rows {
+			if seen[row.ID] {
+				t.Fatalf("adjustment %s returned twice", row.ID)
+			}
+			seen[row.ID] = true
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 adjustments across pages, got %d", len(seen))
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestRepositoryInventoryOneToOne(t *testing.T) {
@@ -46,3 +50,54 @@ func TestRepositoryInventoryOneToOne(t *testing.T) {
 		t.Fatalf("expected available 10, got %d", fetched.AvailableQty)
 	}
 }
+
+func TestWriteVendorInventoryKeepsConcurrentReservation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.InventoryItem{}); err != nil {
+		t.Fatalf("migrate inventory: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewRepository(db)
+	productID := uuid.New()
+	if err := db.Create(&models.InventoryItem{ProductID: productID, AvailableQty: 10, ReservedQty: 2}).Error; err != nil {
+		t.Fatalf("seed inventory: %v", err)
+	}
+
+	// A reservation commits after the vendor read version 0.
+	if err := db.Exec(`UPDATE inventory_items SET available_qty = 7, reserved_qty = 5, version = version + 1 WHERE product_id = ?`, productID).Error; err != nil {
+		t.Fatalf("simulate reservation: %v", err)
+	}
+
+	updated, err := repo.UpdateInventoryAtVersion(ctx, productID, 20, 3, 0)
+	if err != nil {
+		t.Fatalf("update at stale version: %v", err)
+	}
+	if updated {
+		t.Fatal("expected stale version write to be rejected")
+	}
+
+	previous, err := writeVendorInventory(ctx, repo, productID, InventoryInput{AvailableQty: 20, LowStockThreshold: 3})
+	if err != nil {
+		t.Fatalf("write vendor inventory: %v", err)
+	}
+	if previous != 7 {
+		t.Fatalf("expected previous available 7, got %d", previous)
+	}
+
+	var inv models.InventoryItem
+	if err := db.First(&inv, "product_id = ?", productID).Error; err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+	if inv.AvailableQty != 20 || inv.ReservedQty != 5 || inv.LowStockThreshold != 3 || inv.Version != 2 {
+		t.Fatalf("unexpected inventory after edit: %+v", inv)
+	}
+
+	_, err = writeVendorInventory(ctx, repo, productID, InventoryInput{AvailableQty: 4})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error when reserved exceeds available, got %v", err)
+	}
+}
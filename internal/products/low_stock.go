@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// lowStockThresholdExpr resolves the effective threshold for an inventory row: the per-product
+// threshold when configured, otherwise the global default bound as the first parameter.
+const lowStockThresholdExpr = "CASE WHEN low_stock_threshold > 0 THEN low_stock_threshold ELSE ? END"
+
+// LowStockItem describes an inventory row that dropped below its effective threshold.
+type LowStockItem struct {
+	ProductID    uuid.UUID
+	StoreID      uuid.UUID
+	SKU          string
+	Title        string
+	AvailableQty int
+	Threshold    int
+}
+
+// ListLowStockCandidates returns active products below their threshold that have not been alerted
+// since the last time they were restocked.
+func (r *Repository) ListLowStockCandidates(ctx context.Context, defaultThreshold, limit int) ([]LowStockItem, error) {
+	var rows []LowStockItem
+	err := r.db.WithContext(ctx).
+		Table("inventory_items AS i").
+		Select("i.product_id, p.store_id, p.sku, p.title, i.available_qty, "+lowStockThresholdExpr+" AS threshold", defaultThreshold).
+		Joins("JOIN products p ON p.id = i.product_id").
+		Where("p.is_active = ?", true).
+		Scopes(db.NotDeleted("p.deleted_at")).
+		Where("i.low_stock_alerted_at IS NULL").
+		Where("i.available_qty < "+lowStockThresholdExpr, defaultThreshold).
+		Order("i.product_id").
+		Limit(limit).
+		Scan(&rows).
+		Error
+	return rows, err
+}
+
+// ResetLowStockAlerts re-arms alerts for rows at or above their current threshold. Stock writes
+// re-arm against the threshold stored at alert time (see inventory.RearmLowStockAlert); this sweep
+// also catches threshold changes made since the alert.
+func (r *Repository) ResetLowStockAlerts(ctx context.Context, defaultThreshold int) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Exec(`UPDATE inventory_items SET low_stock_alerted_at = NULL, low_stock_alert_threshold = NULL
+WHERE low_stock_alerted_at IS NOT NULL AND available_qty >= `+lowStockThresholdExpr, defaultThreshold)
+	return result.RowsAffected, result.Error
+}
+
+// MarkLowStockAlertedWithTx records that a low-stock alert was sent, along with the effective
+// threshold so stock writes can re-arm it without knowing the configured default. It reports false
+// when the row was already alerted or is no longer below its threshold.
+func (r *Repository) MarkLowStockAlertedWithTx(tx *gorm.DB, productID uuid.UUID, defaultThreshold int, at time.Time) (bool, error) {
+	result := tx.Exec(`UPDATE inventory_items SET low_stock_alerted_at = ?, low_stock_alert_threshold = `+lowStockThresholdExpr+`
+WHERE product_id = ? AND low_stock_alerted_at IS NULL AND available_qty < `+lowStockThresholdExpr,
+		at, defaultThreshold, productID, defaultThreshold)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,123 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MediaOrderItem places one of a product's media rows. Items are listed in display order, and
+// exactly one of them must be marked primary.
+type MediaOrderItem struct {
+	ID        uuid.UUID
+	IsPrimary bool
+}
+
+// ReorderMedia rewrites the display order and primary image of a product owned by the vendor
+// store. The order must list every media row on the product exactly once.
+func (s *service) ReorderMedia(ctx context.Context, userID, storeID, productID uuid.UUID, order []MediaOrderItem) (*ProductDTO, error) {
+	if _, err := s.loadOwnedProduct(ctx, userID, storeID, productID); err != nil {
+		return nil, err
+	}
+
+	if err := s.dbClient.WithTx(ctx, func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		rows, err := txRepo.ListProductMedia(ctx, productID)
+		if err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list product media")
+		}
+		ordered, err := planMediaOrder(rows, order)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.ReorderProductMedia(ctx, productID, ordered); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: reorder product media")
+		}
+		return nil
+	}); err != nil {
+		if pkgerrors.As(err) != nil {
+			return nil, err
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "reorder product media")
+	}
+
+	product, summary, err := s.repo.GetProductDetail(ctx, productID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product detail")
+	}
+	return s.newProductDTO(ctx, product, summary)
+}
+
+// planMediaOrder checks the requested order against the product's current media rows and returns
+// them with positions and the primary flag assigned.
+func planMediaOrder(rows []models.ProductMedia, order []MediaOrderItem) ([]models.ProductMedia, error) {
+	if len(rows) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "product has no media to reorder")
+	}
+	if len(order) != len(rows) {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("media order must list all %d product media", len(rows)))
+	}
+
+	byID := make(map[uuid.UUID]models.ProductMedia, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	ordered := make([]models.ProductMedia, 0, len(order))
+	seen := make(map[uuid.UUID]struct{}, len(order))
+	primaries := 0
+	for idx, item := range order {
+		row, ok := byID[item.ID]
+		if !ok {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, fmt.Sprintf("media %s does not belong to the product", item.ID))
+		}
+		if _, dup := seen[item.ID]; dup {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "duplicate media ids")
+		}
+		seen[item.ID] = struct{}{}
+		if item.IsPrimary {
+			primaries++
+		}
+		row.Position = idx
+		row.IsPrimary = item.IsPrimary
+		ordered = append(ordered, row)
+	}
+	if primaries != 1 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "exactly one media must be primary")
+	}
+	return ordered, nil
+}
+
+// ReorderProductMedia stores new positions and primary flags for the product's media rows. Call it
+// inside a transaction: rows are first moved past the current positions so the unique
+// (product_id, position) and primary indexes never see two rows collide mid-update.
+func (r *Repository) ReorderProductMedia(ctx context.Context, productID uuid.UUID, rows []models.ProductMedia) error {
+	tx := r.db.WithContext(ctx)
+	if err := tx.Model(&models.ProductMedia{}).
+		Where("product_id = ?", productID).
+		Updates(map[string]any{
+			"position":   gorm.Expr("position + (SELECT COALESCE(MAX(position), 0) + 1 FROM product_media WHERE product_id = ?)", productID),
+			"is_primary": false,
+		}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, row := range rows {
+		if err := tx.Model(&models.ProductMedia{}).
+			Where("id = ? AND product_id = ?", row.ID, productID).
+			Updates(map[string]any{
+				"position":   row.Position,
+				"is_primary": row.IsPrimary,
+				"updated_at": now,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
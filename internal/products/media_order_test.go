@@ -0,0 +1,124 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+func testProductMediaRows(n int) []models.ProductMedia {
+	rows := make([]models.ProductMedia, n)
+	for i := range rows {
+		rows[i] = models.ProductMedia{ID: uuid.New(), GCSKey: "gcs-key", Position: i, IsPrimary: i == 0}
+	}
+	return rows
+}
+
+func TestPlanMediaOrder(t *testing.T) {
+	rows := testProductMediaRows(3)
+
+	ordered, err := planMediaOrder(rows, []MediaOrderItem{
+		{ID: rows[2].ID},
+		{ID: rows[0].ID},
+		{ID: rows[1].ID, IsPrimary: true},
+	})
+	if err != nil {
+		t.Fatalf("plan media order: %v", err)
+	}
+	wantIDs := []uuid.UUID{rows[2].ID, rows[0].ID, rows[1].ID}
+	for i, row := range ordered {
+		if row.ID != wantIDs[i] || row.Position != i {
+			t.Fatalf("row %d: expected %s at position %d, got %s at %d", i, wantIDs[i], i, row.ID, row.Position)
+		}
+		if row.IsPrimary != (i == 2) {
+			t.Fatalf("row %d: unexpected primary flag %v", i, row.IsPrimary)
+		}
+	}
+	if rows[0].Position != 0 || !rows[0].IsPrimary {
+		t.Fatalf("expected the current rows left untouched, got %+v", rows[0])
+	}
+}
+
+func TestPlanMediaOrderRejectsInvalidOrders(t *testing.T) {
+	rows := testProductMediaRows(2)
+	cases := map[string][]MediaOrderItem{
+		"no primary":      {{ID: rows[0].ID}, {ID: rows[1].ID}},
+		"two primaries":   {{ID: rows[0].ID, IsPrimary: true}, {ID: rows[1].ID, IsPrimary: true}},
+		"missing media":   {{ID: rows[0].ID, IsPrimary: true}},
+		"duplicate media": {{ID: rows[0].ID, IsPrimary: true}, {ID: rows[0].ID}},
+		"foreign media":   {{ID: rows[0].ID, IsPrimary: true}, {ID: uuid.New()}},
+	}
+	for name, order := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := planMediaOrder(rows, order)
+			if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRepositoryReorderProductMedia(t *testing.T) {
+	conn := openTestDB(t)
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin tx: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	repo := NewRepository(tx)
+	ctx := context.Background()
+	user := mustCreateTestUser(t, tx)
+	store := mustCreateTestStore(t, tx, user.ID)
+	created, err := repo.CreateProduct(ctx, mustCreateTestProduct(t, tx, store.ID))
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	rows := testProductMediaRows(3)
+	for i := range rows {
+		rows[i].ProductID = created.ID
+	}
+	if err := repo.ReplaceProductMedia(ctx, created.ID, rows); err != nil {
+		t.Fatalf("replace media: %v", err)
+	}
+
+	ordered, err := planMediaOrder(rows, []MediaOrderItem{
+		{ID: rows[2].ID, IsPrimary: true},
+		{ID: rows[1].ID},
+		{ID: rows[0].ID},
+	})
+	if err != nil {
+		t.Fatalf("plan media order: %v", err)
+	}
+	if err := repo.ReorderProductMedia(ctx, created.ID, ordered); err != nil {
+		t.Fatalf("reorder media: %v", err)
+	}
+
+	detail, _, err := repo.GetProductDetail(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get detail: %v", err)
+	}
+	if len(detail.Media) != 3 {
+		t.Fatalf("expected 3 media rows, got %d", len(detail.Media))
+	}
+	for i, want := range []uuid.UUID{rows[2].ID, rows[1].ID, rows[0].ID} {
+		if detail.Media[i].ID != want || detail.Media[i].Position != i || detail.Media[i].IsPrimary != (i == 0) {
+			t.Fatalf("media %d: unexpected row %+v", i, detail.Media[i])
+		}
+	}
+
+	sp := tx.SavePoint("second_primary")
+	if sp.Error != nil {
+		t.Fatalf("savepoint: %v", sp.Error)
+	}
+	if err := tx.Model(&models.ProductMedia{}).Where("id = ?", rows[0].ID).Update("is_primary", true).Error; err == nil {
+		t.Fatal("expected the primary index to reject a second primary image")
+	}
+	tx.RollbackTo("second_primary")
+}
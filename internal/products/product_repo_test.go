@@ -2,6 +2,7 @@ package product
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
@@ -176,6 +177,189 @@ func TestRepositoryListProductSummaries(t *testing.T) {
 	}
 }
 
+func TestRepositorySoftDeleteAndRestoreProduct(t *testing.T) {
+	conn := openTestDB(t)
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin tx: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	ctx := context.Background()
+	repo := NewRepository(tx)
+	user := mustCreateTestUser(t, tx)
+	store := mustCreateTestStore(t, tx, user.ID)
+	product := mustCreateTestProduct(t, tx, store.ID)
+
+	listVendor := func() []ProductSummary {
+		t.Helper()
+		page, err := repo.ListProductSummaries(ctx, productListQuery{
+			Pagination:    pagination.Params{Limit: 10},
+			VendorStoreID: &store.ID,
+		})
+		if err != nil {
+			t.Fatalf("list products: %v", err)
+		}
+		return page.Products
+	}
+
+	if got := listVendor(); len(got) != 1 || got[0].ID != product.ID {
+		t.Fatalf("expected product listed before delete, got %v", got)
+	}
+
+	if err := repo.DeleteProduct(ctx, product.ID); err != nil {
+		t.Fatalf("delete product: %v", err)
+	}
+
+	if got := listVendor(); len(got) != 0 {
+		t.Fatalf("expected deleted product to vanish from listings, got %v", got)
+	}
+	if _, _, err := repo.GetProductDetail(ctx, product.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected detail lookup to miss deleted product, got %v", err)
+	}
+
+	// Order line items keep pointing at the row, so history still resolves it.
+	historical, err := repo.FindByIDIncludingDeleted(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("load deleted product: %v", err)
+	}
+	if historical.DeletedAt == nil {
+		t.Fatal("expected deleted_at to be set")
+	}
+	if historical.Title != product.Title {
+		t.Fatalf("expected title %q, got %q", product.Title, historical.Title)
+	}
+
+	if err := repo.RestoreProduct(ctx, product.ID); err != nil {
+		t.Fatalf("restore product: %v", err)
+	}
+	if got := listVendor(); len(got) != 1 || got[0].ID != product.ID {
+		t.Fatalf("expected restored product listed, got %v", got)
+	}
+}
+
+func TestRepositoryListProductSummariesRanksSearchResults(t *testing.T) {
+	conn := openTestDB(t)
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin tx: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	ctx := context.Background()
+	repo := NewRepository(tx)
+	user := mustCreateTestUser(t, tx)
+	store := mustCreateTestStore(t, tx, user.ID)
+
+	bodyOnly := mustInsertProduct(t, tx, store.ID, "BODY-ONLY", enums.ProductCategoryFlower, enums.ProductClassificationHybrid, 1000, true, nil, nil)
+	if err := tx.Model(&models.Product{}).Where("id = ?", bodyOnly.ID).Updates(map[string]any{
+		"title":     "House Flower",
+		"body_html": "<p>A blue tinted bud.</p>",
+	}).Error; err != nil {
+		t.Fatalf("update body-only product: %v", err)
+	}
+	best := mustInsertProduct(t, tx, store.ID, "BEST", enums.ProductCategoryFlower, enums.ProductClassificationSativa, 1000, true, nil, nil)
+	if err := tx.Model(&models.Product{}).Where("id = ?", best.ID).Updates(map[string]any{
+		"title":  "Blue Dream",
+		"strain": "Blue Dream",
+	}).Error; err != nil {
+		t.Fatalf("update best product: %v", err)
+	}
+	partial := mustInsertProduct(t, tx, store.ID, "PARTIAL", enums.ProductCategoryFlower, enums.ProductClassificationIndica, 1000, true, nil, nil)
+	if err := tx.Model(&models.Product{}).Where("id = ?", partial.ID).Update("title", "Blue Cheese").Error; err != nil {
+		t.Fatalf("update partial product: %v", err)
+	}
+	_ = mustInsertProduct(t, tx, store.ID, "UNRELATED", enums.ProductCategoryFlower, enums.ProductClassificationIndica, 1000, true, nil, nil)
+
+	query := productListQuery{
+		Pagination:    pagination.Params{Limit: 1},
+		Filters:       ProductListFilters{Query: "blue dream"},
+		VendorStoreID: &store.ID,
+	}
+
+	var ids []uuid.UUID
+	for page := 0; page < 5; page++ {
+		result, err := repo.ListProductSummaries(ctx, query)
+		if err != nil {
+			t.Fatalf("list products: %v", err)
+		}
+		for _, product := range result.Products {
+			ids = append(ids, product.ID)
+		}
+		if result.Pagination.Next == "" {
+			break
+		}
+		query.Pagination.Cursor = result.Pagination.Next
+	}
+
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 matching products across pages, got %d", len(ids))
+	}
+	if ids[0] != best.ID {
+		t.Fatalf("expected most relevant product first, got %s", ids[0])
+	}
+	if ids[1] != partial.ID {
+		t.Fatalf("expected title match ahead of body match, got %s", ids[1])
+	}
+	if ids[2] != bodyOnly.ID {
+		t.Fatalf("expected body match last, got %s", ids[2])
+	}
+}
+
+func TestRepositoryListProductSummariesHidesExcludedStates(t *testing.T) {
+	conn := openTestDB(t)
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("begin tx: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	ctx := context.Background()
+	repo := NewRepository(tx)
+	user := mustCreateTestUser(t, tx)
+	store := mustCreateTestStore(t, tx, user.ID)
+	hidden := mustInsertProduct(t, tx, store.ID, "HIDDEN", enums.ProductCategoryFlower, enums.ProductClassificationSativa, 1200, true, floatPtr(22), floatPtr(0.4))
+	if err := tx.Model(&models.Product{}).Where("id = ?", hidden.ID).Update("excluded_states", pq.StringArray{"OK"}).Error; err != nil {
+		t.Fatalf("exclude state: %v", err)
+	}
+	visible := mustInsertProduct(t, tx, store.ID, "VISIBLE", enums.ProductCategoryFlower, enums.ProductClassificationSativa, 1200, true, floatPtr(22), floatPtr(0.4))
+
+	buyerPage, err := repo.ListProductSummaries(ctx, productListQuery{
+		Pagination:     pagination.Params{Limit: 10},
+		RequestedState: "OK",
+	})
+	if err != nil {
+		t.Fatalf("list buyer products: %v", err)
+	}
+	seenVisible := false
+	for _, product := range buyerPage.Products {
+		if product.ID == hidden.ID {
+			t.Fatal("expected product excluded from OK to be hidden from OK buyers")
+		}
+		seenVisible = seenVisible || product.ID == visible.ID
+	}
+	if !seenVisible {
+		t.Fatalf("expected the visible product for OK buyers, got %v", buyerPage.Products)
+	}
+
+	vendorPage, err := repo.ListProductSummaries(ctx, productListQuery{
+		Pagination:    pagination.Params{Limit: 10},
+		VendorStoreID: &store.ID,
+	})
+	if err != nil {
+		t.Fatalf("list vendor products: %v", err)
+	}
+	if len(vendorPage.Products) != 2 {
+		t.Fatalf("expected vendor to still see both products, got %d", len(vendorPage.Products))
+	}
+}
+
 func mustInsertProduct(t *testing.T, tx *gorm.DB, storeID uuid.UUID, sku string, category enums.ProductCategory, classification enums.ProductClassification, price int, active bool, thc, cbd *float64) *models.Product {
 	t.Helper()
 	product := &models.Product{
@@ -0,0 +1,195 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultRelatedProductsLimit is used when callers do not request a size.
+	DefaultRelatedProductsLimit = 10
+	// MaxRelatedProductsLimit caps how many related products are computed and cached.
+	MaxRelatedProductsLimit = 20
+	// relatedCandidateFactor over-fetches co-purchased IDs because some drop out of the browse filters.
+	relatedCandidateFactor  = 3
+	relatedProductsCacheTTL = time.Hour
+)
+
+// redisStore defines the Redis operations used to cache related products.
+type redisStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+}
+
+// CoPurchase counts how many checkout groups contained both the source product and ProductID.
+type CoPurchase struct {
+	ProductID uuid.UUID
+	Count     int
+}
+
+// ListCoPurchasedProducts ranks products by the number of checkout groups they share with productID.
+func (r *Repository) ListCoPurchasedProducts(ctx context.Context, productID uuid.UUID, limit int) ([]CoPurchase, error) {
+	var rows []CoPurchase
+	err := r.db.WithContext(ctx).
+		Table("order_line_items src").
+		Select("oli.product_id AS product_id, COUNT(DISTINCT vo.checkout_group_id) AS count").
+		Joins("JOIN vendor_orders src_vo ON src_vo.id = src.order_id").
+		Joins("JOIN vendor_orders vo ON vo.checkout_group_id = src_vo.checkout_group_id").
+		Joins("JOIN order_line_items oli ON oli.order_id = vo.id").
+		Where("src.product_id = ?", productID).
+		Where("oli.product_id IS NOT NULL AND oli.product_id <> ?", productID).
+		Group("oli.product_id").
+		Order("count DESC").
+		Order("oli.product_id ASC").
+		Limit(limit).
+		Scan(&rows).
+		Error
+	return rows, err
+}
+
+// ListBrowsableProductSummaries returns the summaries for ids that a buyer in state could browse.
+func (r *Repository) ListBrowsableProductSummaries(ctx context.Context, ids []uuid.UUID, state string) ([]ProductSummary, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var records []productSummaryRecord
+	err := joinProductSummaryRelations(
+		applyProductListFilters(r.baseProductListQuery(ctx), productListQuery{RequestedState: state}).
+			Select(strings.Join(productSummaryColumns, ", ")),
+	).
+		Where("p.id IN ?", ids).
+		Scan(&records).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]ProductSummary, 0, len(records))
+	for _, record := range records {
+		summaries = append(summaries, record.toSummary())
+	}
+	return summaries, nil
+}
+
+// RelatedProducts returns products most often bought in the same checkout as productID, limited to
+// listings browsable in the source vendor's state. The caller must be able to see the source product,
+// which is checked before the cache so a cached ranking never leaks a hidden listing.
+func (s *service) RelatedProducts(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID, limit int) ([]ProductSummary, error) {
+	if limit <= 0 {
+		limit = DefaultRelatedProductsLimit
+	}
+	if limit > MaxRelatedProductsLimit {
+		limit = MaxRelatedProductsLimit
+	}
+
+	source, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+	}
+	if err := s.ensureProductVisible(ctx, storeID, storeType, source); err != nil {
+		return nil, err
+	}
+
+	if cached, ok := s.cachedRelatedProducts(ctx, productID); ok {
+		return truncateSummaries(cached, limit), nil
+	}
+	vendor, err := s.storeRepo.FindByID(ctx, source.StoreID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor store")
+	}
+
+	related, err := s.computeRelatedProducts(ctx, productID, vendor)
+	if err != nil {
+		return nil, err
+	}
+	s.storeRelatedProducts(ctx, productID, related)
+	return truncateSummaries(related, limit), nil
+}
+
+func (s *service) computeRelatedProducts(ctx context.Context, productID uuid.UUID, vendor *models.Store) ([]ProductSummary, error) {
+	candidates, err := s.repo.ListCoPurchasedProducts(ctx, productID, MaxRelatedProductsLimit*relatedCandidateFactor)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list co-purchased products")
+	}
+	ids := make([]uuid.UUID, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ProductID)
+	}
+
+	summaries, err := s.repo.ListBrowsableProductSummaries(ctx, ids, strings.ToUpper(strings.TrimSpace(vendor.Address.State)))
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load related products")
+	}
+	byID := make(map[uuid.UUID]ProductSummary, len(summaries))
+	for _, summary := range summaries {
+		byID[summary.ID] = summary
+	}
+
+	related := make([]ProductSummary, 0, MaxRelatedProductsLimit)
+	for _, candidate := range candidates {
+		summary, ok := byID[candidate.ProductID]
+		if !ok {
+			continue
+		}
+		related = append(related, summary)
+		if len(related) == MaxRelatedProductsLimit {
+			break
+		}
+	}
+	return related, nil
+}
+
+// cachedRelatedProducts treats cache errors as misses so Redis outages only cost a recompute.
+func (s *service) cachedRelatedProducts(ctx context.Context, productID uuid.UUID) ([]ProductSummary, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	raw, err := s.cache.Get(ctx, relatedProductsCacheKey(productID))
+	if err != nil || raw == "" {
+		return nil, false
+	}
+	var related []ProductSummary
+	if err := json.Unmarshal([]byte(raw), &related); err != nil {
+		return nil, false
+	}
+	return related, true
+}
+
+func (s *service) storeRelatedProducts(ctx context.Context, productID uuid.UUID, related []ProductSummary) {
+	if s.cache == nil {
+		return
+	}
+	payload, err := json.Marshal(related)
+	if err != nil {
+		return
+	}
+	_ = s.cache.Set(ctx, relatedProductsCacheKey(productID), string(payload), relatedProductsCacheTTL)
+}
+
+func relatedProductsCacheKey(productID uuid.UUID) string {
+	return "pf:related:" + productID.String()
+}
+
+func truncateSummaries(summaries []ProductSummary, limit int) []ProductSummary {
+	if len(summaries) > limit {
+		return summaries[:limit]
+	}
+	if summaries == nil {
+		return []ProductSummary{}
+	}
+	return summaries
+}
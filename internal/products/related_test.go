@@ -0,0 +1,190 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupCoPurchaseDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, ddl := range []string{
+		`CREATE TABLE vendor_orders (id TEXT PRIMARY KEY, checkout_group_id TEXT NOT NULL)`,
+		`CREATE TABLE order_line_items (id TEXT PRIMARY KEY, order_id TEXT NOT NULL, product_id TEXT)`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatalf("create table: %v", err)
+		}
+	}
+	return db
+}
+
+// seedCheckout records one checkout group split into a vendor order per product set.
+func seedCheckout(t *testing.T, db *gorm.DB, productSets ...[]uuid.UUID) {
+	t.Helper()
+
+	groupID := uuid.New()
+	for _, products := range productSets {
+		orderID := uuid.New()
+		if err := db.Exec(`INSERT INTO vendor_orders (id, checkout_group_id) VALUES (?, ?)`, orderID, groupID).Error; err != nil {
+			t.Fatalf("insert vendor order: %v", err)
+		}
+		for _, productID := range products {
+			if err := db.Exec(`INSERT INTO order_line_items (id, order_id, product_id) VALUES (?, ?, ?)`, uuid.New(), orderID, productID).Error; err != nil {
+				t.Fatalf("insert line item: %v", err)
+			}
+		}
+	}
+}
+
+func TestRepositoryListCoPurchasedProductsRanksByCheckoutCount(t *testing.T) {
+	db := setupCoPurchaseDB(t)
+	repo := NewRepository(db)
+
+	source := uuid.New()
+	often := uuid.New()
+	sometimes := uuid.New()
+	once := uuid.New()
+	unrelated := uuid.New()
+
+	// Co-purchases across vendors in the same checkout group still count.
+	seedCheckout(t, db, []uuid.UUID{source, often}, []uuid.UUID{sometimes})
+	seedCheckout(t, db, []uuid.UUID{source}, []uuid.UUID{often, sometimes})
+	seedCheckout(t, db, []uuid.UUID{source, often, once})
+	// Repeating a product inside one checkout must not inflate its count.
+	seedCheckout(t, db, []uuid.UUID{source, often}, []uuid.UUID{often})
+	seedCheckout(t, db, []uuid.UUID{unrelated, once, sometimes})
+
+	rows, err := repo.ListCoPurchasedProducts(context.Background(), source, 10)
+	if err != nil {
+		t.Fatalf("list co-purchased products: %v", err)
+	}
+
+	expected := []CoPurchase{
+		{ProductID: often, Count: 4},
+		{ProductID: sometimes, Count: 2},
+		{ProductID: once, Count: 1},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %+v", len(expected), rows)
+	}
+	for i, want := range expected {
+		if rows[i] != want {
+			t.Fatalf("row %d: expected %+v, got %+v", i, want, rows[i])
+		}
+	}
+
+	limited, err := repo.ListCoPurchasedProducts(context.Background(), source, 1)
+	if err != nil {
+		t.Fatalf("list limited: %v", err)
+	}
+	if len(limited) != 1 || limited[0].ProductID != often {
+		t.Fatalf("expected only the top product, got %+v", limited)
+	}
+}
+
+type fakeRelatedCache struct {
+	values map[string]string
+}
+
+func (f *fakeRelatedCache) Get(_ context.Context, key string) (string, error) {
+	return f.values[key], nil
+}
+
+func (f *fakeRelatedCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	f.values[key] = value.(string)
+	return nil
+}
+
+type stubRelatedStores map[uuid.UUID]*models.Store
+
+func (s stubRelatedStores) FindByID(_ context.Context, id uuid.UUID) (*models.Store, error) {
+	store, ok := s[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return store, nil
+}
+
+// newCachedRelatedService seeds a source product and a cached ranking for it.
+func newCachedRelatedService(t *testing.T, vendor *models.Store, active bool) (*service, uuid.UUID, uuid.UUID, []uuid.UUID) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE products (id TEXT PRIMARY KEY, store_id TEXT NOT NULL, is_active INTEGER NOT NULL, deleted_at DATETIME)`).Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	productID := uuid.New()
+	if err := db.Exec(`INSERT INTO products (id, store_id, is_active) VALUES (?, ?, ?)`, productID, vendor.ID, active).Error; err != nil {
+		t.Fatalf("insert product: %v", err)
+	}
+
+	cachedIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	cached := make([]ProductSummary, 0, len(cachedIDs))
+	for _, id := range cachedIDs {
+		cached = append(cached, ProductSummary{ID: id})
+	}
+	payload, err := json.Marshal(cached)
+	if err != nil {
+		t.Fatalf("marshal cache: %v", err)
+	}
+	cache := &fakeRelatedCache{values: map[string]string{relatedProductsCacheKey(productID): string(payload)}}
+
+	buyerID := uuid.New()
+	stores := stubRelatedStores{
+		vendor.ID: vendor,
+		buyerID:   {ID: buyerID, Type: enums.StoreTypeBuyer},
+	}
+	return &service{repo: NewRepository(db), storeRepo: stores, cache: cache}, buyerID, productID, cachedIDs
+}
+
+func TestRelatedProductsServesCachedRanking(t *testing.T) {
+	vendor := &models.Store{ID: uuid.New(), Type: enums.StoreTypeVendor, KYCStatus: enums.KYCStatusVerified, SubscriptionActive: true}
+	// The co-purchase tables do not exist, so a recompute would fail.
+	svc, buyerID, productID, cachedIDs := newCachedRelatedService(t, vendor, true)
+
+	related, err := svc.RelatedProducts(context.Background(), buyerID, enums.StoreTypeBuyer, productID, 2)
+	if err != nil {
+		t.Fatalf("related products: %v", err)
+	}
+	if len(related) != 2 || related[0].ID != cachedIDs[0] || related[1].ID != cachedIDs[1] {
+		t.Fatalf("expected top two cached products in order, got %+v", related)
+	}
+}
+
+func TestRelatedProductsChecksVisibilityBeforeCache(t *testing.T) {
+	verified := &models.Store{ID: uuid.New(), Type: enums.StoreTypeVendor, KYCStatus: enums.KYCStatusVerified, SubscriptionActive: true}
+	unsubscribed := &models.Store{ID: uuid.New(), Type: enums.StoreTypeVendor, KYCStatus: enums.KYCStatusVerified}
+
+	cases := []struct {
+		name   string
+		vendor *models.Store
+		active bool
+	}{
+		{name: "inactive product", vendor: verified, active: false},
+		{name: "vendor without subscription", vendor: unsubscribed, active: true},
+	}
+	for _, tc := range cases {
+		svc, buyerID, productID, _ := newCachedRelatedService(t, tc.vendor, tc.active)
+		_, err := svc.RelatedProducts(context.Background(), buyerID, enums.StoreTypeBuyer, productID, 2)
+		if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeNotFound {
+			t.Fatalf("%s: expected not found, got %v", tc.name, err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
@@ -78,8 +79,17 @@ func (r *Repository) WithTx(tx *gorm.DB) *Repository {
 	return &Repository{db: tx}
 }
 
-// FindByID loads the product without associations.
+// FindByID loads the product without associations, skipping soft-deleted rows.
 func (r *Repository) FindByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.WithContext(ctx).Scopes(db.NotDeleted("deleted_at")).First(&product, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// FindByIDIncludingDeleted loads the product even when it has been soft-deleted.
+func (r *Repository) FindByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Product, error) {
 	var product models.Product
 	if err := r.db.WithContext(ctx).First(&product, "id = ?", id).Error; err != nil {
 		return nil, err
@@ -123,6 +133,39 @@ func (r *Repository) ListProductMediaIDs(ctx context.Context, productID uuid.UUI
 	return ids, nil
 }
 
+// ListProductMedia returns the product's media rows ordered by position.
+func (r *Repository) ListProductMedia(ctx context.Context, productID uuid.UUID) ([]models.ProductMedia, error) {
+	var rows []models.ProductMedia
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("position ASC").
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SKUExists reports whether the store already has a product with the given SKU.
+func (r *Repository) SKUExists(ctx context.Context, storeID uuid.UUID, sku string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("store_id = ? AND sku = ?", storeID, sku).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SetProductActive updates only the is_active flag, which GORM skips on insert when false.
+func (r *Repository) SetProductActive(ctx context.Context, productID uuid.UUID, active bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", productID).
+		Update("is_active", active).Error
+}
+
 // CreateProduct inserts a new product row.
 func (r *Repository) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
 	if err := r.db.WithContext(ctx).Create(product).Error; err != nil {
@@ -153,9 +196,21 @@ func (r *Repository) FindInventoryByProductID(ctx context.Context, productID uui
 	return &inv, nil
 }
 
-// DeleteProduct removes a product by ID.
+// DeleteProduct soft-deletes a product by stamping deleted_at so order history keeps resolving it.
 func (r *Repository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Product{}).Error
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Scopes(db.NotDeleted("deleted_at")).
+		Where("id = ?", id).
+		Update("deleted_at", time.Now().UTC()).Error
+}
+
+// RestoreProduct clears deleted_at on a soft-deleted product.
+func (r *Repository) RestoreProduct(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
 }
 
 // GetProductDetail fetches a product with inventory, discounts, media, and vendor summary.
@@ -167,8 +222,9 @@ func (r *Repository) GetProductDetail(ctx context.Context, id uuid.UUID) (*model
 			return db.Order("min_qty DESC")
 		}).
 		Preload("Media", func(db *gorm.DB) *gorm.DB {
-			return db.Order("position ASC")
+			return db.Order("position ASC").Order("created_at ASC")
 		}).
+		Scopes(db.NotDeleted("deleted_at")).
 		First(&product, "id = ?", id).
 		Error
 	if err != nil {
@@ -191,8 +247,9 @@ func (r *Repository) ListProductsByStore(ctx context.Context, storeID uuid.UUID)
 			return db.Order("min_qty DESC")
 		}).
 		Preload("Media", func(db *gorm.DB) *gorm.DB {
-			return db.Order("position ASC")
+			return db.Order("position ASC").Order("created_at ASC")
 		}).
+		Scopes(db.NotDeleted("deleted_at")).
 		Where("store_id = ?", storeID).
 		Order("created_at DESC").
 		Find(&rows).
@@ -208,6 +265,26 @@ func (r *Repository) UpsertInventory(ctx context.Context, item *models.Inventory
 	return item, nil
 }
 
+// UpdateInventoryAtVersion overwrites the vendor-managed inventory fields only when the row still has
+// the expected version and its reserved stock fits the new available qty. reserved_qty is left to the
+// reservation flow. It reports false when the row changed since it was read.
+func (r *Repository) UpdateInventoryAtVersion(ctx context.Context, productID uuid.UUID, availableQty, lowStockThreshold, version int) (bool, error) {
+	res := r.db.WithContext(ctx).Exec(
+		`UPDATE inventory_items
+       SET available_qty = ?, low_stock_threshold = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+       WHERE product_id = ? AND version = ? AND reserved_qty <= ?`,
+		availableQty,
+		lowStockThreshold,
+		productID,
+		version,
+		availableQty,
+	)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
 // GetInventoryByProductID returns the inventory row for the provided product.
 func (r *Repository) GetInventoryByProductID(ctx context.Context, productID uuid.UUID) (*models.InventoryItem, error) {
 	var item models.InventoryItem
@@ -259,7 +336,8 @@ const promoExistsClause = "EXISTS (SELECT 1 FROM product_volume_discounts d WHER
 func (r *Repository) baseProductListQuery(ctx context.Context) *gorm.DB {
 	return r.db.WithContext(ctx).
 		Table("products p").
-		Joins("JOIN stores s ON s.id = p.store_id")
+		Joins("JOIN stores s ON s.id = p.store_id").
+		Scopes(db.NotDeleted("p.deleted_at"))
 }
 
 func applyProductListFilters(q *gorm.DB, query productListQuery) *gorm.DB {
@@ -295,10 +373,7 @@ func applyProductListFilters(q *gorm.DB, query productListQuery) *gorm.DB {
 			q = q.Where("NOT " + promoExistsClause)
 		}
 	}
-	if search := strings.TrimSpace(filter.Query); search != "" {
-		pattern := "%" + strings.ToLower(search) + "%"
-		q = q.Where("(LOWER(p.title) LIKE ? OR LOWER(p.sku) LIKE ?)", pattern, pattern)
-	}
+	q = newProductSearch(filter.Query).apply(q)
 
 	if query.VendorStoreID != nil {
 		q = q.Where("p.store_id = ?", *query.VendorStoreID)
@@ -310,87 +385,95 @@ func applyProductListFilters(q *gorm.DB, query productListQuery) *gorm.DB {
 
 		if query.RequestedState != "" {
 			q = q.Where("LOWER((s.address).state) = LOWER(?)", query.RequestedState)
+			q = q.Where("NOT (UPPER(?) = ANY(p.excluded_states))", query.RequestedState)
 		}
 	}
 	return q
 }
 
 func (r *Repository) fetchProductBoundaryCursor(ctx context.Context, query productListQuery, ascending bool) (string, error) {
-	var row struct {
-		CreatedAt time.Time
-		ID        uuid.UUID
-	}
-	qb := applyProductListFilters(r.baseProductListQuery(ctx), query).
-		Select("p.created_at", "p.id")
-	if ascending {
-		qb = qb.Order("p.created_at ASC").Order("p.id ASC")
-	} else {
-		qb = qb.Order("p.created_at DESC").Order("p.id DESC")
-	}
-	qb = qb.Limit(1)
-	if err := qb.First(&row).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil
-		}
+	var rows []struct {
+		CreatedAt  time.Time
+		ID         uuid.UUID
+		SearchRank sql.NullFloat64
+	}
+	search := newProductSearch(query.Filters.Query)
+	qb := search.selectColumns(applyProductListFilters(r.baseProductListQuery(ctx), query), []string{"p.created_at", "p.id"})
+	qb = search.order(qb, ascending).Limit(1)
+	if err := qb.Scan(&rows).Error; err != nil {
 		return "", err
 	}
-	return pagination.EncodeCursor(pagination.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}), nil
+	if len(rows) == 0 {
+		return "", nil
+	}
+	row := rows[0]
+	return encodeProductCursor(productCursor{
+		Cursor: pagination.Cursor{CreatedAt: row.CreatedAt, ID: row.ID},
+		Rank:   nullFloatPtr(row.SearchRank),
+	}), nil
+}
+
+var productSummaryColumns = []string{
+	"p.id",
+	"p.sku",
+	"p.title",
+	"p.subtitle",
+	"p.category",
+	"p.classification",
+	"p.unit",
+	"p.moq",
+	"p.price_cents",
+	"p.compare_at_price_cents",
+	"p.thc_percent",
+	"p.cbd_percent",
+	"p.coa_added",
+	"p.created_at",
+	"p.updated_at",
+	"p.store_id",
+	"p.max_qty",
+	promoExistsClause + " AS has_promo",
+	"pm_thumb.thumbnail_url AS thumbnail_url",
+	"inv.available_qty AS inventory_available",
+	"inv.reserved_qty AS inventory_reserved",
+	"inv.low_stock_threshold AS inventory_low_stock",
+	"inv.updated_at AS inventory_updated_at",
+	"inv.low_stock_threshold AS inventory_low_stock_threshold",
+}
+
+// joinProductSummaryRelations adds the inventory and thumbnail joins behind productSummaryColumns.
+func joinProductSummaryRelations(q *gorm.DB) *gorm.DB {
+	return q.
+		Joins("LEFT JOIN inventory_items inv ON inv.product_id = p.id").
+		Joins(`LEFT JOIN LATERAL (
+  SELECT COALESCE(pm.url, m.public_url) AS thumbnail_url
+  FROM product_media pm
+  LEFT JOIN media m ON pm.media_id = m.id
+  WHERE pm.product_id = p.id
+  ORDER BY pm.is_primary DESC, pm.position ASC, pm.created_at ASC
+  LIMIT 1
+) pm_thumb ON true`)
 }
 
 func (r *Repository) ListProductSummaries(ctx context.Context, query productListQuery) (*ProductListResult, error) {
+	ctx = db.ReadOnly(ctx)
 	pageSize := pagination.NormalizeLimit(query.Pagination.Limit)
 	limitWithBuffer := pagination.LimitWithBuffer(query.Pagination.Limit)
 	if limitWithBuffer <= pageSize {
 		limitWithBuffer = pageSize + 1
 	}
 
-	cursor, err := pagination.ParseCursor(query.Pagination.Cursor)
+	cursor, err := parseProductCursor(query.Pagination.Cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	dataQuery := applyProductListFilters(r.baseProductListQuery(ctx), query).
-		Select(strings.Join([]string{
-			"p.id",
-			"p.sku",
-			"p.title",
-			"p.subtitle",
-			"p.category",
-			"p.classification",
-			"p.unit",
-			"p.moq",
-			"p.price_cents",
-			"p.compare_at_price_cents",
-			"p.thc_percent",
-			"p.cbd_percent",
-			"p.coa_added",
-			"p.created_at",
-			"p.updated_at",
-			"p.store_id",
-			"p.max_qty",
-			promoExistsClause + " AS has_promo",
-			"pm_thumb.thumbnail_url AS thumbnail_url",
-			"inv.available_qty AS inventory_available",
-			"inv.reserved_qty AS inventory_reserved",
-			"inv.low_stock_threshold AS inventory_low_stock",
-			"inv.updated_at AS inventory_updated_at",
-			"inv.low_stock_threshold AS inventory_low_stock_threshold",
-		}, ", ")).
-		Joins("LEFT JOIN inventory_items inv ON inv.product_id = p.id").
-		Joins(`LEFT JOIN LATERAL (
-  SELECT COALESCE(pm.url, m.public_url) AS thumbnail_url
-  FROM product_media pm
-  LEFT JOIN media m ON pm.media_id = m.id
-  WHERE pm.product_id = p.id
-  ORDER BY pm.position ASC, pm.created_at ASC
-  LIMIT 1
-) pm_thumb ON true`)
-
-	if cursor != nil {
-		dataQuery = dataQuery.Where("(p.created_at < ?) OR (p.created_at = ? AND p.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
-	}
+	search := newProductSearch(query.Filters.Query)
+	dataQuery := joinProductSummaryRelations(
+		search.selectColumns(applyProductListFilters(r.baseProductListQuery(ctx), query), productSummaryColumns),
+	)
 
-	dataQuery = dataQuery.Order("p.created_at DESC").Order("p.id DESC").Limit(limitWithBuffer)
+	dataQuery = search.after(dataQuery, cursor)
+	dataQuery = search.order(dataQuery, false).Limit(limitWithBuffer)
 
 	var records []productSummaryRecord
 	if err := dataQuery.Scan(&records).Error; err != nil {
@@ -402,7 +485,10 @@ func (r *Repository) ListProductSummaries(ctx context.Context, query productList
 	if len(records) > pageSize {
 		resultRows = records[:pageSize]
 		last := resultRows[len(resultRows)-1]
-		nextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		nextCursor = encodeProductCursor(productCursor{
+			Cursor: pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+			Rank:   nullFloatPtr(last.SearchRank),
+		})
 	}
 
 	summaries := make([]ProductSummary, 0, len(resultRows))
@@ -410,9 +496,14 @@ func (r *Repository) ListProductSummaries(ctx context.Context, query productList
 		summaries = append(summaries, record.toSummary())
 	}
 
-	var totalCount int64
-	if err := applyProductListFilters(r.baseProductListQuery(ctx), query).Count(&totalCount).Error; err != nil {
-		return nil, err
+	var total *int
+	if query.Pagination.IncludeTotal {
+		var totalCount int64
+		if err := applyProductListFilters(r.baseProductListQuery(ctx), query).Count(&totalCount).Error; err != nil {
+			return nil, err
+		}
+		count := int(totalCount)
+		total = &count
 	}
 
 	firstCursor, err := r.fetchProductBoundaryCursor(ctx, query, false)
@@ -439,7 +530,7 @@ func (r *Repository) ListProductSummaries(ctx context.Context, query productList
 		Products: summaries,
 		Pagination: ProductPagination{
 			Page:    page,
-			Total:   int(totalCount),
+			Total:   total,
 			Current: currentCursor,
 			First:   firstCursor,
 			Last:    lastCursor,
@@ -473,6 +564,7 @@ type productSummaryRecord struct {
 	InventoryReserved   sql.NullInt64
 	InventoryUpdatedAt  sql.NullTime
 	InventoryLowStock   sql.NullInt64
+	SearchRank          sql.NullFloat64
 }
 
 func (r productSummaryRecord) toSummary() ProductSummary {
@@ -0,0 +1,155 @@
+package product
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// minFullTextQueryLength is the shortest query that goes through full-text search;
+// anything shorter falls back to prefix matching on title and SKU.
+const minFullTextQueryLength = 3
+
+// searchTSQuery ORs the normalized lexemes so products matching more terms rank higher
+// instead of requiring every term to match.
+const searchTSQuery = "replace(plainto_tsquery('english', ?)::text, '&', '|')::tsquery"
+
+// searchRankExpr is rounded so the value survives the cursor round-trip exactly.
+const searchRankExpr = "ROUND(ts_rank(p.search_vector, " + searchTSQuery + ")::numeric, 6)"
+
+type productSearch struct {
+	Term     string
+	FullText bool
+}
+
+func newProductSearch(query string) productSearch {
+	term := strings.TrimSpace(query)
+	return productSearch{
+		Term:     term,
+		FullText: utf8.RuneCountInString(term) >= minFullTextQueryLength,
+	}
+}
+
+func (s productSearch) active() bool {
+	return s.Term != ""
+}
+
+// ranked reports whether results are ordered by relevance rather than recency.
+func (s productSearch) ranked() bool {
+	return s.active() && s.FullText
+}
+
+func (s productSearch) apply(q *gorm.DB) *gorm.DB {
+	if !s.active() {
+		return q
+	}
+	lowered := strings.ToLower(s.Term)
+	if !s.FullText {
+		prefix := lowered + "%"
+		return q.Where("(LOWER(p.title) LIKE ? OR LOWER(p.sku) LIKE ?)", prefix, prefix)
+	}
+	return q.Where("(p.search_vector @@ "+searchTSQuery+" OR LOWER(p.sku) LIKE ?)", s.Term, "%"+lowered+"%")
+}
+
+// selectColumns appends the rank column when results are relevance-ordered.
+func (s productSearch) selectColumns(q *gorm.DB, columns []string) *gorm.DB {
+	selectSQL := strings.Join(columns, ", ")
+	if !s.ranked() {
+		return q.Select(selectSQL)
+	}
+	return q.Select(selectSQL+", "+searchRankExpr+" AS search_rank", s.Term)
+}
+
+func (s productSearch) order(q *gorm.DB, ascending bool) *gorm.DB {
+	direction := "DESC"
+	if ascending {
+		direction = "ASC"
+	}
+	tail := "p.created_at " + direction + ", p.id " + direction
+	if !s.ranked() {
+		return q.Order(tail)
+	}
+	// A single expression keeps GORM from dropping the parameterized rank when columns merge.
+	return q.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  searchRankExpr + " " + direction + ", " + tail,
+		Vars: []any{s.Term},
+	}})
+}
+
+func (s productSearch) after(q *gorm.DB, cursor *productCursor) *gorm.DB {
+	if cursor == nil {
+		return q
+	}
+	if !s.ranked() || cursor.Rank == nil {
+		clause, args := cursor.Before("p")
+		return q.Where(clause, args...)
+	}
+	rank := *cursor.Rank
+	return q.Where(
+		"("+searchRankExpr+" < ?) OR ("+searchRankExpr+" = ? AND p.created_at < ?) OR ("+searchRankExpr+" = ? AND p.created_at = ? AND p.id < ?)",
+		s.Term, rank,
+		s.Term, rank, cursor.CreatedAt,
+		s.Term, rank, cursor.CreatedAt, cursor.ID,
+	)
+}
+
+// productCursor extends the shared created_at|id cursor with the search rank so
+// relevance-ordered pages stay stable.
+type productCursor struct {
+	pagination.Cursor
+	Rank *float64
+}
+
+func encodeProductCursor(cursor productCursor) string {
+	if cursor.Rank == nil {
+		return pagination.EncodeCursor(cursor.Cursor)
+	}
+	payload := fmt.Sprintf("%s|%s|%s",
+		strconv.FormatFloat(*cursor.Rank, 'f', 6, 64),
+		cursor.CreatedAt.UTC().Format(time.RFC3339Nano),
+		cursor.ID.String(),
+	)
+	return base64.StdEncoding.EncodeToString([]byte(payload))
+}
+
+func parseProductCursor(value string) (*productCursor, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+	parts := strings.Split(string(decoded), "|")
+	if len(parts) != 3 {
+		base, err := pagination.ParseCursor(value)
+		if err != nil {
+			return nil, err
+		}
+		return &productCursor{Cursor: *base}, nil
+	}
+	rank, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor rank: %w", err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor id: %w", err)
+	}
+	return &productCursor{
+		Cursor: pagination.Cursor{CreatedAt: createdAt, ID: id},
+		Rank:   &rank,
+	}, nil
+}
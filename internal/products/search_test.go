@@ -0,0 +1,65 @@
+package product
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func TestNewProductSearchFallsBackToPrefixForShortQueries(t *testing.T) {
+	cases := []struct {
+		query    string
+		active   bool
+		fullText bool
+	}{
+		{query: "", active: false, fullText: false},
+		{query: "  ", active: false, fullText: false},
+		{query: "og", active: true, fullText: false},
+		{query: " kush ", active: true, fullText: true},
+		{query: "blue dream", active: true, fullText: true},
+	}
+	for _, tc := range cases {
+		search := newProductSearch(tc.query)
+		if search.active() != tc.active {
+			t.Fatalf("query %q: expected active=%v", tc.query, tc.active)
+		}
+		if search.ranked() != tc.fullText {
+			t.Fatalf("query %q: expected ranked=%v", tc.query, tc.fullText)
+		}
+	}
+}
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 8000, time.UTC)
+	id := uuid.New()
+	rank := 0.607927
+
+	encoded := encodeProductCursor(productCursor{
+		Cursor: pagination.Cursor{CreatedAt: createdAt, ID: id},
+		Rank:   &rank,
+	})
+	decoded, err := parseProductCursor(encoded)
+	if err != nil {
+		t.Fatalf("parse ranked cursor: %v", err)
+	}
+	if decoded.Rank == nil || *decoded.Rank != rank {
+		t.Fatalf("expected rank %v, got %v", rank, decoded.Rank)
+	}
+	if !decoded.CreatedAt.Equal(createdAt) || decoded.ID != id {
+		t.Fatalf("unexpected cursor %+v", decoded)
+	}
+
+	plain := pagination.EncodeCursor(pagination.Cursor{CreatedAt: createdAt, ID: id})
+	decoded, err = parseProductCursor(plain)
+	if err != nil {
+		t.Fatalf("parse plain cursor: %v", err)
+	}
+	if decoded.Rank != nil {
+		t.Fatalf("expected no rank on plain cursor, got %v", *decoded.Rank)
+	}
+	if encodeProductCursor(*decoded) != plain {
+		t.Fatal("expected plain cursor to re-encode unchanged")
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/angelmondragon/packfinderz-backend/internal/inventory"
 	"github.com/angelmondragon/packfinderz-backend/internal/media"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -20,8 +24,18 @@ type Service interface {
 	CreateProduct(ctx context.Context, userID, storeID uuid.UUID, input CreateProductInput) (*ProductDTO, error)
 	UpdateProduct(ctx context.Context, userID, storeID, productID uuid.UUID, input UpdateProductInput) (*ProductDTO, error)
 	DeleteProduct(ctx context.Context, userID, storeID, productID uuid.UUID) error
+	RestoreProduct(ctx context.Context, userID, storeID, productID uuid.UUID) (*ProductDTO, error)
 	ListProducts(ctx context.Context, input ListProductsInput) (*ProductListResult, error)
 	GetProductDetail(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID) (*ProductDTO, error)
+	BulkImport(ctx context.Context, userID, storeID uuid.UUID, reader io.Reader) (*BulkImportResult, error)
+	BulkUpdateInventory(ctx context.Context, userID, storeID uuid.UUID, items []BulkInventoryItem) (*BulkInventoryResult, error)
+	DuplicateProduct(ctx context.Context, userID, storeID, productID uuid.UUID) (*ProductDTO, error)
+	ListInventoryAdjustments(ctx context.Context, userID, storeID, productID uuid.UUID, params pagination.Params) (*InventoryAdjustmentList, error)
+	RelatedProducts(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, productID uuid.UUID, limit int) ([]ProductSummary, error)
+	ListBuyerPrices(ctx context.Context, userID, storeID, productID uuid.UUID) ([]BuyerPriceDTO, error)
+	SetBuyerPrice(ctx context.Context, userID, storeID, productID, buyerStoreID uuid.UUID, priceCents int) (*BuyerPriceDTO, error)
+	DeleteBuyerPrice(ctx context.Context, userID, storeID, productID, buyerStoreID uuid.UUID) error
+	ReorderMedia(ctx context.Context, userID, storeID, productID uuid.UUID, order []MediaOrderItem) (*ProductDTO, error)
 }
 
 // CreateProductInput holds the validated payload to create a product.
@@ -51,6 +65,7 @@ type CreateProductInput struct {
 	MediaIDs            []uuid.UUID
 	VolumeDiscounts     []VolumeDiscountInput
 	MaxQty              int
+	ExcludedStates      []string
 	COAMediaID          *uuid.UUID
 	PackagingType       *string
 }
@@ -89,10 +104,12 @@ type service struct {
 	mediaRepo         mediaReader
 	mediaSvc          media.Service
 	attachments       media.AttachmentReconciler
+	cache             redisStore
+	outbox            inventory.OutboxPublisher
 }
 
 // NewService constructs a product service instance.
-func NewService(repo *Repository, dbClient *db.Client, storeRepo storeLoader, membershipChecker membershipChecker, mediaRepo mediaReader, attachments media.AttachmentReconciler, mediaSvc media.Service) (Service, error) {
+func NewService(repo *Repository, dbClient *db.Client, storeRepo storeLoader, membershipChecker membershipChecker, mediaRepo mediaReader, attachments media.AttachmentReconciler, mediaSvc media.Service, cache redisStore, outbox inventory.OutboxPublisher) (Service, error) {
 	if repo == nil {
 		return nil, fmt.Errorf("product repository required")
 	}
@@ -114,6 +131,9 @@ func NewService(repo *Repository, dbClient *db.Client, storeRepo storeLoader, me
 	if mediaSvc == nil {
 		return nil, fmt.Errorf("media service required")
 	}
+	if outbox == nil {
+		return nil, fmt.Errorf("outbox publisher required")
+	}
 	return &service{
 		repo:              repo,
 		dbClient:          dbClient,
@@ -122,13 +142,16 @@ func NewService(repo *Repository, dbClient *db.Client, storeRepo storeLoader, me
 		mediaRepo:         mediaRepo,
 		mediaSvc:          mediaSvc,
 		attachments:       attachments,
+		cache:             cache,
+		outbox:            outbox,
 	}, nil
 }
 
 // CreateProduct creates the product with inventory, discounts, and media.
 func (s *service) CreateProduct(ctx context.Context, userID, storeID uuid.UUID, input CreateProductInput) (*ProductDTO, error) {
 
-	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+	vendor, err := s.loadVendorStore(ctx, storeID)
+	if err != nil {
 		return nil, err
 	}
 	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
@@ -141,69 +164,31 @@ func (s *service) CreateProduct(ctx context.Context, userID, storeID uuid.UUID,
 		}
 	}
 
-	if err := ensureUniqueDiscounts(input.VolumeDiscounts); err != nil {
+	if err := validateCreateProductInput(input); err != nil {
 		return nil, err
 	}
-	for _, discount := range input.VolumeDiscounts {
-		if err := validateDiscountPercent(discount.DiscountPercent); err != nil {
-			return nil, err
-		}
-	}
-
-	if err := validateMaxQty(input.MaxQty); err != nil {
-		return nil, err
-	}
-	if err := validateLowStockThreshold(input.Inventory.LowStockThreshold); err != nil {
+	excludedStates, err := normalizeExcludedStates(input.ExcludedStates)
+	if err != nil {
 		return nil, err
 	}
+	input.ExcludedStates = excludedStates
 
 	var createdProductID uuid.UUID
 	if err := s.dbClient.WithTx(ctx, func(tx *gorm.DB) error {
 		txRepo := s.repo.WithTx(tx)
 
-		product := &models.Product{
-			StoreID:             storeID,
-			SKU:                 input.SKU,
-			Title:               input.Title,
-			Subtitle:            input.Subtitle,
-			BodyHTML:            input.BodyHTML,
-			BatchID:             input.BatchID,
-			MetricTag:           input.MetricTag,
-			Barcode:             input.Barcode,
-			Category:            input.Category,
-			Feelings:            input.Feelings,
-			Flavors:             input.Flavors,
-			Usage:               input.Usage,
-			Strain:              input.Strain,
-			Classification:      input.Classification,
-			Unit:                input.Unit,
-			MOQ:                 input.MOQ,
-			PriceCents:          input.PriceCents,
-			CompareAtPriceCents: input.CompareAtPriceCents,
-			IsActive:            input.IsActive,
-			IsFeatured:          input.IsFeatured,
-			THCPercent:          input.THCPercent,
-			CBDPercent:          input.CBDPercent,
-			MaxQty:              input.MaxQty,
-			PackagingType:       input.PackagingType,
-			COAMediaID:          input.COAMediaID,
-			COAAdded:            input.COAMediaID != nil,
-		}
-
-		created, err := txRepo.CreateProduct(ctx, product)
+		created, err := txRepo.CreateProduct(ctx, newProductModel(vendor, input))
 		if err != nil {
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: insert product")
 		}
 		createdProductID = created.ID
 
-		inventory := &models.InventoryItem{
-			ProductID:         created.ID,
-			AvailableQty:      input.Inventory.AvailableQty,
-			LowStockThreshold: input.Inventory.LowStockThreshold,
-		}
-		if _, err := txRepo.UpsertInventory(ctx, inventory); err != nil {
+		if _, err := txRepo.UpsertInventory(ctx, newInventoryModel(created.ID, input.Inventory)); err != nil {
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "db: upsert inventory")
 		}
+		if err := recordVendorInventoryEdit(ctx, tx, s.outbox, created.ID, input.Inventory.AvailableQty, userID, storeID); err != nil {
+			return err
+		}
 
 		for _, discount := range input.VolumeDiscounts {
 			tier := &models.ProductVolumeDiscount{
@@ -273,6 +258,7 @@ type UpdateProductInput struct {
 	MediaIDs            *[]uuid.UUID
 	VolumeDiscounts     *[]VolumeDiscountInput
 	MaxQty              *int
+	ExcludedStates      *[]string
 	COAMediaID          *uuid.UUID
 	COAMediaIDSet       bool
 	BatchID             *string
@@ -289,6 +275,13 @@ func (s *service) UpdateProduct(ctx context.Context, userID, storeID, productID
 			return nil, err
 		}
 	}
+	if input.ExcludedStates != nil {
+		states, err := normalizeExcludedStates(*input.ExcludedStates)
+		if err != nil {
+			return nil, err
+		}
+		input.ExcludedStates = &states
+	}
 
 	if input.Inventory != nil {
 		if err := validateLowStockThreshold(input.Inventory.LowStockThreshold); err != nil {
@@ -379,30 +372,11 @@ func (s *service) UpdateProduct(ctx context.Context, userID, storeID, productID
 		}
 
 		if input.Inventory != nil {
-			// Load existing reserved qty from DB (never from client)
-			existingInv, err := txRepo.FindInventoryByProductID(ctx, product.ID)
-			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			previousAvailable, err := writeVendorInventory(ctx, txRepo, product.ID, *input.Inventory)
+			if err != nil {
 				return err
 			}
-
-			reserved := 0
-			if existingInv != nil {
-				reserved = existingInv.ReservedQty
-			}
-
-			// Now validate against DB reserved
-			if reserved > input.Inventory.AvailableQty {
-				return pkgerrors.New(pkgerrors.CodeValidation, "reserved_qty cannot exceed available_qty")
-			}
-
-			inventory := &models.InventoryItem{
-				ProductID:         product.ID,
-				AvailableQty:      input.Inventory.AvailableQty,
-				ReservedQty:       reserved,
-				LowStockThreshold: input.Inventory.LowStockThreshold,
-			}
-
-			if _, err := txRepo.UpsertInventory(ctx, inventory); err != nil {
+			if err := recordVendorInventoryEdit(ctx, tx, s.outbox, product.ID, input.Inventory.AvailableQty-previousAvailable, userID, storeID); err != nil {
 				return err
 			}
 		}
@@ -455,7 +429,8 @@ func (s *service) UpdateProduct(ctx context.Context, userID, storeID, productID
 	return s.newProductDTO(ctx, updated, summary)
 }
 
-// DeleteProduct removes a product and relies on FK cascades for related rows.
+// DeleteProduct soft-deletes a product so it drops out of listings and quotes while
+// historical order line items keep referencing it.
 func (s *service) DeleteProduct(ctx context.Context, userID, storeID, productID uuid.UUID) error {
 	if err := s.ensureVendorStore(ctx, storeID); err != nil {
 		return err
@@ -481,6 +456,39 @@ func (s *service) DeleteProduct(ctx context.Context, userID, storeID, productID
 	return nil
 }
 
+// RestoreProduct brings a soft-deleted product back into listings.
+func (s *service) RestoreProduct(ctx context.Context, userID, storeID, productID uuid.UUID) (*ProductDTO, error) {
+	if err := s.ensureVendorStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUserRole(ctx, userID, storeID); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByIDIncludingDeleted(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		}
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product")
+	}
+	if product.StoreID != storeID {
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
+	}
+
+	if product.DeletedAt != nil {
+		if err := s.repo.RestoreProduct(ctx, productID); err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "restore product")
+		}
+	}
+
+	restored, summary, err := s.repo.GetProductDetail(ctx, productID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product detail")
+	}
+	return s.newProductDTO(ctx, restored, summary)
+}
+
 func (s *service) ListProducts(ctx context.Context, input ListProductsInput) (*ProductListResult, error) {
 	page := input.Page
 	if page < 1 {
@@ -527,39 +535,48 @@ func (s *service) GetProductDetail(ctx context.Context, storeID uuid.UUID, store
 		}
 		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load product detail")
 	}
+	if err := s.ensureProductVisible(ctx, storeID, storeType, product); err != nil {
+		return nil, err
+	}
+
+	return s.newProductDTO(ctx, product, summary)
+}
 
+// ensureProductVisible applies the product detail gate: vendors see only their own products and buyers
+// see active products from verified, subscribed vendors.
+func (s *service) ensureProductVisible(ctx context.Context, storeID uuid.UUID, storeType enums.StoreType, product *models.Product) error {
 	switch storeType {
 	case enums.StoreTypeVendor:
 		if err := s.ensureVendorStore(ctx, storeID); err != nil {
-			return nil, err
+			return err
 		}
 		if product.StoreID != storeID {
-			return nil, pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
+			return pkgerrors.New(pkgerrors.CodeForbidden, "product does not belong to store")
 		}
 	case enums.StoreTypeBuyer:
-		if err := s.ensureBuyerStore(ctx, storeID); err != nil {
-			return nil, err
+		buyer, err := s.loadBuyerStore(ctx, storeID)
+		if err != nil {
+			return err
 		}
-		if !product.IsActive {
-			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+		if !product.IsActive || product.IsExcludedIn(buyer.Address.State) {
+			return pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
 		}
 		vendorStore, err := s.storeRepo.FindByID(ctx, product.StoreID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
+				return pkgerrors.New(pkgerrors.CodeNotFound, "product not found")
 			}
-			return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor store")
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load vendor store")
 		}
 		if vendorStore.Type != enums.StoreTypeVendor ||
 			vendorStore.KYCStatus != enums.KYCStatusVerified ||
 			!vendorStore.SubscriptionActive {
-			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "product not available")
+			return pkgerrors.New(pkgerrors.CodeNotFound, "product not available")
 		}
 	default:
-		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "unsupported store type")
+		return pkgerrors.New(pkgerrors.CodeForbidden, "unsupported store type")
 	}
-
-	return s.newProductDTO(ctx, product, summary)
+	return nil
 }
 
 func (s *service) newProductDTO(ctx context.Context, product *models.Product, summary *VendorSummary) (*ProductDTO, error) {
@@ -573,7 +590,7 @@ func (s *service) newProductDTO(ctx context.Context, product *models.Product, su
 				continue
 			}
 
-			url, err := s.fetchMediaReadURL(ctx, product.StoreID, *mediaID)
+			output, err := s.fetchMediaReadURL(ctx, product.StoreID, *mediaID)
 			if err != nil {
 				if typed := pkgerrors.As(err); typed != nil {
 					// Same pattern as COA: tolerate missing / conflicting media records.
@@ -584,7 +601,8 @@ func (s *service) newProductDTO(ctx context.Context, product *models.Product, su
 				return nil, err
 			}
 
-			dto.Media[i].URL = url
+			dto.Media[i].URL = &output.URL
+			dto.noteURLExpiry(output.ExpiresAt)
 		}
 	}
 
@@ -593,7 +611,7 @@ func (s *service) newProductDTO(ctx context.Context, product *models.Product, su
 		return dto, nil
 	}
 
-	url, err := s.fetchCOAReadURL(ctx, product.StoreID, *product.COAMediaID)
+	output, err := s.fetchCOAReadURL(ctx, product.StoreID, *product.COAMediaID)
 	if err != nil {
 		if typed := pkgerrors.As(err); typed != nil {
 			if typed.Code() == pkgerrors.CodeConflict || typed.Code() == pkgerrors.CodeNotFound {
@@ -602,59 +620,57 @@ func (s *service) newProductDTO(ctx context.Context, product *models.Product, su
 		}
 		return nil, err
 	}
-	dto.COAReadURL = url
+	dto.COAReadURL = &output.URL
+	dto.noteURLExpiry(output.ExpiresAt)
 
 	return dto, nil
 }
 
-func (s *service) fetchCOAReadURL(ctx context.Context, storeID, mediaID uuid.UUID) (*string, error) {
-	output, err := s.mediaSvc.GenerateReadURL(ctx, media.ReadURLParams{
+func (s *service) fetchCOAReadURL(ctx context.Context, storeID, mediaID uuid.UUID) (*media.ReadURLOutput, error) {
+	return s.mediaSvc.GenerateReadURL(ctx, media.ReadURLParams{
 		StoreID: storeID,
 		MediaID: mediaID,
 	})
-	if err != nil {
-		return nil, err
-	}
-	return &output.URL, nil
 }
 
-func (s *service) fetchMediaReadURL(ctx context.Context, storeID, mediaID uuid.UUID) (*string, error) {
-	output, err := s.mediaSvc.GenerateReadURL(ctx, media.ReadURLParams{
+func (s *service) fetchMediaReadURL(ctx context.Context, storeID, mediaID uuid.UUID) (*media.ReadURLOutput, error) {
+	return s.mediaSvc.GenerateReadURL(ctx, media.ReadURLParams{
 		StoreID: storeID,
 		MediaID: mediaID,
 	})
-	if err != nil {
-		return nil, err
-	}
-	return &output.URL, nil
 }
 
 func (s *service) ensureVendorStore(ctx context.Context, storeID uuid.UUID) error {
+	_, err := s.loadVendorStore(ctx, storeID)
+	return err
+}
+
+func (s *service) loadVendorStore(ctx context.Context, storeID uuid.UUID) (*models.Store, error) {
 	store, err := s.storeRepo.FindByID(ctx, storeID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
 		}
-		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
 	}
 	if store.Type != enums.StoreTypeVendor {
-		return pkgerrors.New(pkgerrors.CodeForbidden, "store is not a vendor")
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "store is not a vendor")
 	}
-	return nil
+	return store, nil
 }
 
-func (s *service) ensureBuyerStore(ctx context.Context, storeID uuid.UUID) error {
+func (s *service) loadBuyerStore(ctx context.Context, storeID uuid.UUID) (*models.Store, error) {
 	store, err := s.storeRepo.FindByID(ctx, storeID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
 		}
-		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
 	}
 	if store.Type != enums.StoreTypeBuyer {
-		return pkgerrors.New(pkgerrors.CodeForbidden, "store is not a buyer")
+		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "store is not a buyer")
 	}
-	return nil
+	return store, nil
 }
 
 func (s *service) ensureUserRole(ctx context.Context, userID, storeID uuid.UUID) error {
@@ -676,6 +692,104 @@ func (s *service) ensureUserRole(ctx context.Context, userID, storeID uuid.UUID)
 	return nil
 }
 
+// validateCreateProductInput applies the rules CreateProduct enforces on top of request payload
+// validation.
+func validateCreateProductInput(input CreateProductInput) error {
+	if err := ensureUniqueDiscounts(input.VolumeDiscounts); err != nil {
+		return err
+	}
+	for _, discount := range input.VolumeDiscounts {
+		if err := validateDiscountPercent(discount.DiscountPercent); err != nil {
+			return err
+		}
+	}
+	if err := validateMaxQty(input.MaxQty); err != nil {
+		return err
+	}
+	return validateLowStockThreshold(input.Inventory.LowStockThreshold)
+}
+
+func newProductModel(store *models.Store, input CreateProductInput) *models.Product {
+	return &models.Product{
+		StoreID:             store.ID,
+		Currency:            store.Currency,
+		SKU:                 input.SKU,
+		Title:               input.Title,
+		Subtitle:            input.Subtitle,
+		BodyHTML:            input.BodyHTML,
+		BatchID:             input.BatchID,
+		MetricTag:           input.MetricTag,
+		Barcode:             input.Barcode,
+		Category:            input.Category,
+		Feelings:            input.Feelings,
+		Flavors:             input.Flavors,
+		Usage:               input.Usage,
+		Strain:              input.Strain,
+		Classification:      input.Classification,
+		Unit:                input.Unit,
+		MOQ:                 input.MOQ,
+		PriceCents:          input.PriceCents,
+		CompareAtPriceCents: input.CompareAtPriceCents,
+		IsActive:            input.IsActive,
+		IsFeatured:          input.IsFeatured,
+		THCPercent:          input.THCPercent,
+		CBDPercent:          input.CBDPercent,
+		MaxQty:              input.MaxQty,
+		ExcludedStates:      pq.StringArray(append([]string{}, input.ExcludedStates...)),
+		PackagingType:       input.PackagingType,
+		COAMediaID:          input.COAMediaID,
+		COAAdded:            input.COAMediaID != nil,
+	}
+}
+
+// maxInventoryUpdateAttempts bounds how often a vendor stock edit re-reads the inventory row after
+// losing a version race to a reservation or release.
+const maxInventoryUpdateAttempts = 3
+
+// writeVendorInventory applies a vendor stock edit and returns the previous available qty. The write
+// is compare-and-swapped on the row version, like Reserve, so a reservation committing between the
+// read and the write is re-read instead of having its reserved_qty overwritten.
+func writeVendorInventory(ctx context.Context, txRepo *Repository, productID uuid.UUID, input InventoryInput) (int, error) {
+	for attempt := 1; attempt <= maxInventoryUpdateAttempts; attempt++ {
+		// Reserved qty always comes from the DB, never from the client.
+		existing, err := txRepo.FindInventoryByProductID(ctx, productID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		if existing == nil {
+			if _, err := txRepo.UpsertInventory(ctx, newInventoryModel(productID, input)); err != nil {
+				return 0, err
+			}
+			return 0, nil
+		}
+		if existing.ReservedQty > input.AvailableQty {
+			return 0, pkgerrors.New(pkgerrors.CodeValidation, "reserved_qty cannot exceed available_qty")
+		}
+
+		updated, err := txRepo.UpdateInventoryAtVersion(ctx, productID, input.AvailableQty, input.LowStockThreshold, existing.Version)
+		if err != nil {
+			return 0, err
+		}
+		if updated {
+			return existing.AvailableQty, nil
+		}
+	}
+	return 0, inventory.NewVersionConflictError(productID)
+}
+
+// recordVendorInventoryEdit appends the audit row for a manual change to available stock.
+func recordVendorInventoryEdit(ctx context.Context, tx *gorm.DB, publisher inventory.OutboxPublisher, productID uuid.UUID, availableDelta int, userID, storeID uuid.UUID) error {
+	return inventory.RecordAdjustment(ctx, tx, publisher, productID, availableDelta, 0, enums.InventoryAdjustmentReasonVendorEdit, inventory.NewAdjustmentActor(userID, storeID))
+}
+
+func newInventoryModel(productID uuid.UUID, input InventoryInput) *models.InventoryItem {
+	return &models.InventoryItem{
+		ProductID:         productID,
+		AvailableQty:      input.AvailableQty,
+		LowStockThreshold: input.LowStockThreshold,
+	}
+}
+
 func ensureUniqueDiscounts(discounts []VolumeDiscountInput) error {
 	seen := make(map[int]struct{}, len(discounts))
 	for _, tier := range discounts {
@@ -687,6 +801,24 @@ func ensureUniqueDiscounts(discounts []VolumeDiscountInput) error {
 	return nil
 }
 
+// normalizeExcludedStates upper-cases and dedupes the two-letter states a product is hidden from.
+func normalizeExcludedStates(states []string) ([]string, error) {
+	normalized := make([]string, 0, len(states))
+	seen := map[string]struct{}{}
+	for _, state := range states {
+		code := strings.ToUpper(strings.TrimSpace(state))
+		if len(code) != 2 {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "excluded_states must be two-letter codes")
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		normalized = append(normalized, code)
+	}
+	return normalized, nil
+}
+
 func validateMaxQty(value int) error {
 	if value < 0 {
 		return pkgerrors.New(pkgerrors.CodeValidation, "max_qty must be non-negative")
@@ -775,6 +907,9 @@ func applyUpdateToProduct(product *models.Product, input UpdateProductInput) {
 	if input.MaxQty != nil {
 		product.MaxQty = *input.MaxQty
 	}
+	if input.ExcludedStates != nil {
+		product.ExcludedStates = pq.StringArray(append([]string{}, (*input.ExcludedStates)...))
+	}
 	if input.PackagingType != nil {
 		product.PackagingType = input.PackagingType
 	}
@@ -803,6 +938,7 @@ func (s *service) buildProductMediaRows(ctx context.Context, storeID, productID
 			MediaID:   &mediaRow.ID,
 			URL:       nonEmptyStringPtr(mediaRow.PublicURL),
 			Position:  idx,
+			IsPrimary: idx == 0,
 		})
 	}
 	return rows, nil
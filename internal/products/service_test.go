@@ -122,6 +122,9 @@ func TestBuildProductMediaRows(t *testing.T) {
 		if rows[0].Position != 0 {
 			t.Fatalf("expected position 0, got %d", rows[0].Position)
 		}
+		if !rows[0].IsPrimary {
+			t.Fatal("expected the first media to be primary")
+		}
 		if rows[0].GCSKey != "gcs-key-product" {
 			t.Fatalf("expected gcs key, got %s", rows[0].GCSKey)
 		}
@@ -174,6 +177,22 @@ func TestValidateMaxQty(t *testing.T) {
 	}
 }
 
+func TestNormalizeExcludedStates(t *testing.T) {
+	states, err := normalizeExcludedStates([]string{" ok", "TX", "OK"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(states) != 2 || states[0] != "OK" || states[1] != "TX" {
+		t.Fatalf("expected [OK TX], got %v", states)
+	}
+	if _, err := normalizeExcludedStates([]string{"Oklahoma"}); err == nil {
+		t.Fatal("expected validation error for non two-letter state")
+	}
+	if states, err := normalizeExcludedStates(nil); err != nil || states == nil || len(states) != 0 {
+		t.Fatalf("expected empty non-nil states, got %v (%v)", states, err)
+	}
+}
+
 func TestValidateLowStockThreshold(t *testing.T) {
 	if err := validateLowStockThreshold(-5); err == nil {
 		t.Fatal("expected validation error for negative low_stock_threshold")
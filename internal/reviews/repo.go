@@ -96,6 +96,44 @@ func (r *Repository) DeleteReview(ctx context.Context, reviewID uuid.UUID) error
 		Error
 }
 
+// ExistsForOrder reports whether a review has already been left for the order.
+func (r *Repository) ExistsForOrder(ctx context.Context, orderID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.Review{}).
+		Where("order_id = ?", orderID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// VendorRatingSummary returns the average rating and count of visible reviews for a vendor.
+func (r *Repository) VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (RatingSummary, error) {
+	if vendorStoreID == uuid.Nil {
+		return RatingSummary{}, pkgerrors.New(pkgerrors.CodeValidation, "vendor store id required")
+	}
+
+	var row struct {
+		Average float64
+		Count   int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("vendor_store_id = ?", vendorStoreID).
+		Where("is_visible = ?", true).
+		Scan(&row).Error; err != nil {
+		return RatingSummary{}, err
+	}
+
+	return RatingSummary{
+		VendorStoreID: vendorStoreID,
+		Average:       row.Average,
+		Count:         row.Count,
+	}, nil
+}
+
 // ListReviewsByVendorStoreID returns a cursor page ordered by created_at DESC, id DESC.
 func (r *Repository) ListReviewsByVendorStoreID(ctx context.Context, vendorStoreID uuid.UUID, visibleOnly bool, cursor string, limit int) (ReviewListResult, error) {
 	if vendorStoreID == uuid.Nil {
@@ -210,3 +210,68 @@ func ptrBool(v bool) *bool {
 func ptrUUID(id uuid.UUID) *uuid.UUID {
 	return &id
 }
+
+func TestRepository_VendorRatingSummary(t *testing.T) {
+	db := setupReviewRepoTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	vendorID := uuid.New()
+	var hiddenID uuid.UUID
+	for _, rating := range []int16{5, 4, 2, 1} {
+		review, err := repo.CreateReview(ctx, CreateReviewInput{
+			ReviewType:    enums.ReviewTypeStore,
+			BuyerStoreID:  uuid.New(),
+			BuyerUserID:   uuid.New(),
+			VendorStoreID: ptrUUID(vendorID),
+			Rating:        rating,
+		})
+		require.NoError(t, err)
+		hiddenID = review.ID
+	}
+	require.NoError(t, db.Model(&models.Review{}).Where("id = ?", hiddenID).Update("is_visible", false).Error)
+
+	_, err := repo.CreateReview(ctx, CreateReviewInput{
+		ReviewType:    enums.ReviewTypeStore,
+		BuyerStoreID:  uuid.New(),
+		BuyerUserID:   uuid.New(),
+		VendorStoreID: ptrUUID(uuid.New()),
+		Rating:        1,
+	})
+	require.NoError(t, err)
+
+	summary, err := repo.VendorRatingSummary(ctx, vendorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), summary.Count)
+	assert.InDelta(t, 11.0/3.0, summary.Average, 0.0001)
+
+	empty, err := repo.VendorRatingSummary(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), empty.Count)
+	assert.Equal(t, 0.0, empty.Average)
+}
+
+func TestRepository_ExistsForOrder(t *testing.T) {
+	db := setupReviewRepoTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	exists, err := repo.ExistsForOrder(ctx, orderID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.CreateReview(ctx, CreateReviewInput{
+		ReviewType:    enums.ReviewTypeStore,
+		BuyerStoreID:  uuid.New(),
+		BuyerUserID:   uuid.New(),
+		VendorStoreID: ptrUUID(uuid.New()),
+		OrderID:       ptrUUID(orderID),
+		Rating:        4,
+	})
+	require.NoError(t, err)
+
+	exists, err = repo.ExistsForOrder(ctx, orderID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -3,7 +3,9 @@ package reviews
 import (
 	"context"
 	"errors"
+	"math"
 
+	dbpkg "github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
@@ -12,11 +14,15 @@ import (
 	"gorm.io/gorm"
 )
 
+const reviewsOrderUniqueIndex = "reviews_order_id_unique_idx"
+
 type reviewRepository interface {
 	CreateReview(ctx context.Context, input CreateReviewInput) (*Review, error)
 	ListReviewsByVendorStoreID(ctx context.Context, vendorStoreID uuid.UUID, visibleOnly bool, cursor string, limit int) (ReviewListResult, error)
 	GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*Review, error)
 	DeleteReview(ctx context.Context, reviewID uuid.UUID) error
+	ExistsForOrder(ctx context.Context, orderID uuid.UUID) (bool, error)
+	VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (RatingSummary, error)
 }
 
 type membershipRepository interface {
@@ -24,7 +30,7 @@ type membershipRepository interface {
 }
 
 type ordersRepository interface {
-	HasBuyerStorePurchasedFromVendor(ctx context.Context, buyerStoreID, vendorStoreID uuid.UUID) (bool, error)
+	FindVendorOrder(ctx context.Context, orderID uuid.UUID) (*models.VendorOrder, error)
 }
 
 // Service exposes review business operations.
@@ -32,6 +38,7 @@ type Service interface {
 	CreateReview(ctx context.Context, input CreateReviewInput) (*Review, error)
 	ListVisibleReviews(ctx context.Context, vendorStoreID uuid.UUID, params pagination.Params) (ReviewListResult, error)
 	DeleteReview(ctx context.Context, reviewID, buyerStoreID, buyerUserID uuid.UUID) error
+	VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (RatingSummary, error)
 }
 
 type service struct {
@@ -74,16 +81,42 @@ func (s *service) CreateReview(ctx context.Context, input CreateReviewInput) (*R
 		return nil, pkgerrors.New(pkgerrors.CodeForbidden, "membership is not active")
 	}
 
-	hasPurchase, err := s.orders.HasBuyerStorePurchasedFromVendor(ctx, input.BuyerStoreID, *input.VendorStoreID)
+	if input.OrderID == nil || *input.OrderID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "order id required")
+	}
+	if input.Rating < 1 || input.Rating > 5 {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "rating must be between 1 and 5")
+	}
+
+	order, err := s.orders.FindVendorOrder(ctx, *input.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.New(pkgerrors.CodeNotFound, "order not found")
+		}
+		return nil, err
+	}
+	if err := checkReviewEligibility(order, input.BuyerStoreID, *input.VendorStoreID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.ExistsForOrder(ctx, order.ID)
 	if err != nil {
 		return nil, err
 	}
-	if !hasPurchase {
-		return nil, pkgerrors.New(pkgerrors.CodeValidation, "buyer store has no qualifying purchases with this vendor")
+	if exists {
+		return nil, pkgerrors.New(pkgerrors.CodeConflict, "order has already been reviewed")
 	}
 	input.IsVerifiedPurchase = true
 
-	return s.repo.CreateReview(ctx, input)
+	review, err := s.repo.CreateReview(ctx, input)
+	if err != nil {
+		// A concurrent submission for the same order can pass ExistsForOrder and lose at the unique index.
+		if dbpkg.IsUniqueViolation(err, reviewsOrderUniqueIndex) {
+			return nil, pkgerrors.Wrap(pkgerrors.CodeConflict, err, "order has already been reviewed")
+		}
+		return nil, err
+	}
+	return review, nil
 }
 
 func (s *service) DeleteReview(ctx context.Context, reviewID, buyerStoreID, buyerUserID uuid.UUID) error {
@@ -108,3 +141,33 @@ func (s *service) ListVisibleReviews(ctx context.Context, vendorStoreID uuid.UUI
 	}
 	return s.repo.ListReviewsByVendorStoreID(ctx, vendorStoreID, true, params.Cursor, params.Limit)
 }
+
+func (s *service) VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (RatingSummary, error) {
+	if vendorStoreID == uuid.Nil {
+		return RatingSummary{}, pkgerrors.New(pkgerrors.CodeValidation, "vendor store id required")
+	}
+	summary, err := s.repo.VendorRatingSummary(ctx, vendorStoreID)
+	if err != nil {
+		return RatingSummary{}, err
+	}
+	summary.VendorStoreID = vendorStoreID
+	summary.Average = math.Round(summary.Average*100) / 100
+	return summary, nil
+}
+
+// checkReviewEligibility allows a review only when the order belongs to the buyer store,
+// was placed with the reviewed vendor, and has reached delivery.
+func checkReviewEligibility(order *models.VendorOrder, buyerStoreID, vendorStoreID uuid.UUID) error {
+	if order.BuyerStoreID != buyerStoreID {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "order does not belong to buyer store")
+	}
+	if order.VendorStoreID != vendorStoreID {
+		return pkgerrors.New(pkgerrors.CodeValidation, "order was not placed with this vendor")
+	}
+	switch order.Status {
+	case enums.VendorOrderStatusDelivered, enums.VendorOrderStatusClosed:
+		return nil
+	default:
+		return pkgerrors.New(pkgerrors.CodeStateConflict, "order must be delivered before it can be reviewed")
+	}
+}
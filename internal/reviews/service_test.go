@@ -2,6 +2,7 @@ package reviews
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
@@ -31,6 +32,9 @@ type fakeReviewRepo struct {
 	getErr     error
 	deleteID   uuid.UUID
 	deleteErr  error
+
+	orderReviewed bool
+	summary       RatingSummary
 }
 
 func (f *fakeReviewRepo) CreateReview(ctx context.Context, input CreateReviewInput) (*Review, error) {
@@ -55,6 +59,14 @@ func (f *fakeReviewRepo) DeleteReview(ctx context.Context, reviewID uuid.UUID) e
 	return f.deleteErr
 }
 
+func (f *fakeReviewRepo) ExistsForOrder(ctx context.Context, orderID uuid.UUID) (bool, error) {
+	return f.orderReviewed, nil
+}
+
+func (f *fakeReviewRepo) VendorRatingSummary(ctx context.Context, vendorStoreID uuid.UUID) (RatingSummary, error) {
+	return f.summary, nil
+}
+
 type fakeMembershipRepo struct {
 	membership *models.StoreMembership
 	err        error
@@ -65,14 +77,45 @@ func (f *fakeMembershipRepo) GetMembership(ctx context.Context, userID, storeID
 }
 
 type fakeOrdersRepo struct {
-	hasPurchase bool
-	err         error
-	calls       int
+	order *models.VendorOrder
+	err   error
+	calls int
 }
 
-func (f *fakeOrdersRepo) HasBuyerStorePurchasedFromVendor(ctx context.Context, buyerStoreID, vendorStoreID uuid.UUID) (bool, error) {
+func (f *fakeOrdersRepo) FindVendorOrder(ctx context.Context, orderID uuid.UUID) (*models.VendorOrder, error) {
 	f.calls++
-	return f.hasPurchase, f.err
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.order, nil
+}
+
+func activeMembershipRepo() *fakeMembershipRepo {
+	return &fakeMembershipRepo{
+		membership: &models.StoreMembership{
+			Status: enums.MembershipStatusActive,
+		},
+	}
+}
+
+// reviewableOrder returns a delivered order and the matching review input.
+func reviewableOrder() (*models.VendorOrder, CreateReviewInput) {
+	vendorID := uuid.New()
+	order := &models.VendorOrder{
+		ID:            uuid.New(),
+		BuyerStoreID:  uuid.New(),
+		VendorStoreID: vendorID,
+		Status:        enums.VendorOrderStatusDelivered,
+	}
+	orderID := order.ID
+	return order, CreateReviewInput{
+		ReviewType:    enums.ReviewTypeStore,
+		BuyerStoreID:  order.BuyerStoreID,
+		BuyerUserID:   uuid.New(),
+		VendorStoreID: &vendorID,
+		OrderID:       &orderID,
+		Rating:        5,
+	}
 }
 
 func TestServiceCreateReviewSuccess(t *testing.T) {
@@ -85,24 +128,10 @@ func TestServiceCreateReviewSuccess(t *testing.T) {
 			Rating:       5,
 		},
 	}
-	membershipRepo := &fakeMembershipRepo{
-		membership: &models.StoreMembership{
-			Status: enums.MembershipStatusActive,
-		},
-	}
-	ordersRepo := &fakeOrdersRepo{hasPurchase: true}
+	order, input := reviewableOrder()
+	ordersRepo := &fakeOrdersRepo{order: order}
 
-	svc := NewService(repo, membershipRepo, ordersRepo)
-	input := CreateReviewInput{
-		ReviewType:   enums.ReviewTypeStore,
-		BuyerStoreID: uuid.New(),
-		BuyerUserID:  uuid.New(),
-		VendorStoreID: func() *uuid.UUID {
-			id := uuid.New()
-			return &id
-		}(),
-		Rating: 5,
-	}
+	svc := NewService(repo, activeMembershipRepo(), ordersRepo)
 
 	result, err := svc.CreateReview(ctx, input)
 	require.NoError(t, err)
@@ -136,32 +165,100 @@ func TestServiceCreateReviewMembershipMissing(t *testing.T) {
 	assert.Equal(t, pkgerrors.CodeForbidden, typed.Code())
 }
 
-func TestServiceCreateReviewNoPurchase(t *testing.T) {
-	ctx := context.Background()
-	repo := &fakeReviewRepo{}
-	membershipRepo := &fakeMembershipRepo{
-		membership: &models.StoreMembership{
-			Status: enums.MembershipStatusActive,
+func TestServiceCreateReviewEligibility(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(order *models.VendorOrder, input *CreateReviewInput)
+		code   pkgerrors.Code
+	}{
+		{
+			name:   "closed order allowed",
+			mutate: func(order *models.VendorOrder, _ *CreateReviewInput) { order.Status = enums.VendorOrderStatusClosed },
+		},
+		{
+			name:   "order not delivered",
+			mutate: func(order *models.VendorOrder, _ *CreateReviewInput) { order.Status = enums.VendorOrderStatusInTransit },
+			code:   pkgerrors.CodeStateConflict,
+		},
+		{
+			name:   "order belongs to another buyer",
+			mutate: func(order *models.VendorOrder, _ *CreateReviewInput) { order.BuyerStoreID = uuid.New() },
+			code:   pkgerrors.CodeForbidden,
+		},
+		{
+			name:   "order placed with another vendor",
+			mutate: func(order *models.VendorOrder, _ *CreateReviewInput) { order.VendorStoreID = uuid.New() },
+			code:   pkgerrors.CodeValidation,
+		},
+		{
+			name:   "order missing",
+			mutate: func(_ *models.VendorOrder, input *CreateReviewInput) { input.OrderID = nil },
+			code:   pkgerrors.CodeValidation,
+		},
+		{
+			name:   "rating out of range",
+			mutate: func(_ *models.VendorOrder, input *CreateReviewInput) { input.Rating = 6 },
+			code:   pkgerrors.CodeValidation,
 		},
 	}
-	ordersRepo := &fakeOrdersRepo{hasPurchase: false}
 
-	svc := NewService(repo, membershipRepo, ordersRepo)
-	_, err := svc.CreateReview(ctx, CreateReviewInput{
-		ReviewType:   enums.ReviewTypeStore,
-		BuyerStoreID: uuid.New(),
-		BuyerUserID:  uuid.New(),
-		VendorStoreID: func() *uuid.UUID {
-			id := uuid.New()
-			return &id
-		}(),
-		Rating: 4,
-	})
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{createReturn: &Review{ID: uuid.New()}}
+			order, input := reviewableOrder()
+			tc.mutate(order, &input)
+			svc := NewService(repo, activeMembershipRepo(), &fakeOrdersRepo{order: order})
+
+			_, err := svc.CreateReview(context.Background(), input)
+			if tc.code == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			typed := pkgerrors.As(err)
+			require.NotNil(t, typed)
+			assert.Equal(t, tc.code, typed.Code())
+			assert.Equal(t, CreateReviewInput{}, repo.createInput)
+		})
+	}
+}
+
+func TestServiceCreateReviewOnePerOrder(t *testing.T) {
+	repo := &fakeReviewRepo{orderReviewed: true}
+	order, input := reviewableOrder()
+	svc := NewService(repo, activeMembershipRepo(), &fakeOrdersRepo{order: order})
+
+	_, err := svc.CreateReview(context.Background(), input)
 	require.Error(t, err)
 	typed := pkgerrors.As(err)
 	require.NotNil(t, typed)
-	assert.Equal(t, pkgerrors.CodeValidation, typed.Code())
-	assert.Equal(t, 1, ordersRepo.calls)
+	assert.Equal(t, pkgerrors.CodeConflict, typed.Code())
+}
+
+func TestServiceCreateReviewConcurrentDuplicateIsConflict(t *testing.T) {
+	repo := &fakeReviewRepo{
+		createErr: errors.New(`ERROR: duplicate key value violates unique constraint "reviews_order_id_unique_idx" (SQLSTATE 23505)`),
+	}
+	order, input := reviewableOrder()
+	svc := NewService(repo, activeMembershipRepo(), &fakeOrdersRepo{order: order})
+
+	_, err := svc.CreateReview(context.Background(), input)
+	require.Error(t, err)
+	typed := pkgerrors.As(err)
+	require.NotNil(t, typed)
+	assert.Equal(t, pkgerrors.CodeConflict, typed.Code())
+}
+
+func TestServiceVendorRatingSummaryRoundsAverage(t *testing.T) {
+	vendorID := uuid.New()
+	repo := &fakeReviewRepo{summary: RatingSummary{Average: 13.0 / 3.0, Count: 3}}
+	svc := NewService(repo, &fakeMembershipRepo{}, &fakeOrdersRepo{})
+
+	summary, err := svc.VendorRatingSummary(context.Background(), vendorID)
+	require.NoError(t, err)
+	assert.Equal(t, vendorID, summary.VendorStoreID)
+	assert.Equal(t, 4.33, summary.Average)
+	assert.Equal(t, int64(3), summary.Count)
 }
 
 func TestServiceListVisibleReviews(t *testing.T) {
@@ -56,3 +56,10 @@ type CreateReviewInput struct {
 	IsVerifiedPurchase bool
 	IsVisible          *bool
 }
+
+// RatingSummary aggregates the visible ratings left for a vendor.
+type RatingSummary struct {
+	VendorStoreID uuid.UUID `json:"vendor_store_id"`
+	Average       float64   `json:"average"`
+	Count         int64     `json:"count"`
+}
@@ -12,32 +12,42 @@ import (
 
 // StoreDTO exposes safe tenant data in API responses.
 type StoreDTO struct {
-	ID                   uuid.UUID         `json:"id"`
-	Type                 enums.StoreType   `json:"type"`
-	CompanyName          string            `json:"company_name"`
-	DBAName              *string           `json:"dba_name,omitempty"`
-	Description          *string           `json:"description,omitempty"`
-	Phone                *string           `json:"phone,omitempty"`
-	Email                *string           `json:"email,omitempty"`
-	KYCStatus            enums.KYCStatus   `json:"kyc_status"`
-	SubscriptionActive   bool              `json:"subscription_active"`
-	DeliveryRadiusMeters int               `json:"delivery_radius_meters"`
-	Address              types.Address     `json:"address"`
-	Social               *types.Social     `json:"social,omitempty"`
-	BannerURL            *string           `json:"banner_url,omitempty"`
-	LogoURL              *string           `json:"logo_url,omitempty"`
-	BannerMediaID        *uuid.UUID        `json:"banner_media_id,omitempty"`
-	LogoMediaID          *uuid.UUID        `json:"logo_media_id,omitempty"`
-	Ratings              map[string]int    `json:"ratings,omitempty"`
-	Categories           []string          `json:"categories,omitempty"`
-	OwnerID              uuid.UUID         `json:"owner"`
-	SquareCustomerID     *string           `json:"square_customer_id,omitempty"`
-	Badge                *enums.StoreBadge `json:"badge,omitempty"`
-	LastActiveAt         *time.Time        `json:"last_active_at,omitempty"`
-	Owner                OwnerSummaryDTO   `json:"owner_detail"`
-	Licenses             []StoreLicenseDTO `json:"licenses,omitempty"`
-	CreatedAt            time.Time         `json:"created_at"`
-	UpdatedAt            time.Time         `json:"updated_at"`
+	ID                             uuid.UUID                    `json:"id"`
+	Type                           enums.StoreType              `json:"type"`
+	CompanyName                    string                       `json:"company_name"`
+	DBAName                        *string                      `json:"dba_name,omitempty"`
+	Description                    *string                      `json:"description,omitempty"`
+	Phone                          *string                      `json:"phone,omitempty"`
+	Email                          *string                      `json:"email,omitempty"`
+	KYCStatus                      enums.KYCStatus              `json:"kyc_status"`
+	SubscriptionActive             bool                         `json:"subscription_active"`
+	SubscriptionGraceUntil         *time.Time                   `json:"subscription_grace_until,omitempty"`
+	DeliveryRadiusMeters           int                          `json:"delivery_radius_meters"`
+	DeliveryZones                  *types.DeliveryZones         `json:"delivery_zones,omitempty"`
+	BusinessHours                  *types.BusinessHours         `json:"business_hours,omitempty"`
+	AutoAccept                     bool                         `json:"auto_accept"`
+	TaxExempt                      bool                         `json:"tax_exempt"`
+	TaxExemptCertificateMediaID    *uuid.UUID                   `json:"tax_exempt_certificate_media_id,omitempty"`
+	TaxExemptCertificateVerifiedAt *time.Time                   `json:"tax_exempt_certificate_verified_at,omitempty"`
+	TaxExemptCertificateExpiresAt  *time.Time                   `json:"tax_exempt_certificate_expires_at,omitempty"`
+	VolumeDiscountStrategy         enums.VolumeDiscountStrategy `json:"volume_discount_strategy"`
+	Address                        types.Address                `json:"address"`
+	Currency                       enums.Currency               `json:"currency"`
+	Social                         *types.Social                `json:"social,omitempty"`
+	BannerURL                      *string                      `json:"banner_url,omitempty"`
+	LogoURL                        *string                      `json:"logo_url,omitempty"`
+	BannerMediaID                  *uuid.UUID                   `json:"banner_media_id,omitempty"`
+	LogoMediaID                    *uuid.UUID                   `json:"logo_media_id,omitempty"`
+	Ratings                        map[string]int               `json:"ratings,omitempty"`
+	Categories                     []string                     `json:"categories,omitempty"`
+	OwnerID                        uuid.UUID                    `json:"owner"`
+	SquareCustomerID               *string                      `json:"square_customer_id,omitempty"`
+	Badge                          *enums.StoreBadge            `json:"badge,omitempty"`
+	LastActiveAt                   *time.Time                   `json:"last_active_at,omitempty"`
+	Owner                          OwnerSummaryDTO              `json:"owner_detail"`
+	Licenses                       []StoreLicenseDTO            `json:"licenses,omitempty"`
+	CreatedAt                      time.Time                    `json:"created_at"`
+	UpdatedAt                      time.Time                    `json:"updated_at"`
 }
 
 type OwnerSummaryDTO struct {
@@ -77,22 +87,32 @@ func FromModel(m *models.Store, u *OwnerSummaryDTO) *StoreDTO {
 	}
 
 	dto := &StoreDTO{
-		ID:                   m.ID,
-		Type:                 m.Type,
-		CompanyName:          m.CompanyName,
-		DBAName:              m.DBAName,
-		Description:          m.Description,
-		Phone:                m.Phone,
-		Email:                m.Email,
-		KYCStatus:            m.KYCStatus,
-		SubscriptionActive:   m.SubscriptionActive,
-		DeliveryRadiusMeters: m.DeliveryRadiusMeters,
-		Address:              m.Address,
-		Social:               m.Social,
-		OwnerID:              m.OwnerID,
-		LastActiveAt:         m.LastActiveAt,
-		CreatedAt:            m.CreatedAt,
-		UpdatedAt:            m.UpdatedAt,
+		ID:                             m.ID,
+		Type:                           m.Type,
+		CompanyName:                    m.CompanyName,
+		DBAName:                        m.DBAName,
+		Description:                    m.Description,
+		Phone:                          m.Phone,
+		Email:                          m.Email,
+		KYCStatus:                      m.KYCStatus,
+		SubscriptionActive:             m.SubscriptionActive,
+		SubscriptionGraceUntil:         m.SubscriptionGraceUntil,
+		DeliveryRadiusMeters:           m.DeliveryRadiusMeters,
+		DeliveryZones:                  cloneDeliveryZones(m.DeliveryZones),
+		BusinessHours:                  cloneBusinessHours(m.BusinessHours),
+		AutoAccept:                     m.AutoAccept,
+		TaxExempt:                      m.TaxExempt,
+		TaxExemptCertificateMediaID:    copyUUIDPtr(m.TaxExemptCertificateMediaID),
+		TaxExemptCertificateVerifiedAt: m.TaxExemptCertificateVerifiedAt,
+		TaxExemptCertificateExpiresAt:  m.TaxExemptCertificateExpiresAt,
+		VolumeDiscountStrategy:         volumeDiscountStrategyOrDefault(m.VolumeDiscountStrategy),
+		Address:                        m.Address,
+		Currency:                       m.Currency,
+		Social:                         m.Social,
+		OwnerID:                        m.OwnerID,
+		LastActiveAt:                   m.LastActiveAt,
+		CreatedAt:                      m.CreatedAt,
+		UpdatedAt:                      m.UpdatedAt,
 	}
 
 	if u != nil && u.LastActiveAt != nil {
@@ -176,6 +196,42 @@ func (c CreateStoreDTO) ToModel() *models.Store {
 	return model
 }
 
+func cloneDeliveryZones(zones *types.DeliveryZones) *types.DeliveryZones {
+	if zones == nil {
+		return nil
+	}
+	cpy := *zones
+	cpy.States = append([]string(nil), zones.States...)
+	return &cpy
+}
+
+func cloneBusinessHours(hours *types.BusinessHours) *types.BusinessHours {
+	if hours == nil {
+		return nil
+	}
+	cpy := *hours
+	cpy.Windows = append([]types.BusinessHoursWindow(nil), hours.Windows...)
+	return &cpy
+}
+
+// TaxExemptAt reports whether the store is tax exempt at now: the flag must be set and the
+// certificate on file must be verified and not yet expired. A verified certificate without an
+// expiry never lapses.
+func (d *StoreDTO) TaxExemptAt(now time.Time) bool {
+	if d == nil || !d.TaxExempt || d.TaxExemptCertificateMediaID == nil || d.TaxExemptCertificateVerifiedAt == nil {
+		return false
+	}
+	return d.TaxExemptCertificateExpiresAt == nil || now.Before(*d.TaxExemptCertificateExpiresAt)
+}
+
+// volumeDiscountStrategyOrDefault maps unset strategies to the original highest-tier behaviour.
+func volumeDiscountStrategyOrDefault(value enums.VolumeDiscountStrategy) enums.VolumeDiscountStrategy {
+	if value == "" {
+		return enums.VolumeDiscountStrategyHighestMinQty
+	}
+	return value
+}
+
 func cloneStoreBadgePtr(value *enums.StoreBadge) *enums.StoreBadge {
 	if value == nil {
 		return nil
@@ -202,3 +258,39 @@ func squareCustomerIDForVendor(store *models.Store) *string {
 	empty := ""
 	return &empty
 }
+
+// VendorSummaryDTO is the lightweight vendor card returned by buyer discovery.
+type VendorSummaryDTO struct {
+	ID          uuid.UUID         `json:"id"`
+	CompanyName string            `json:"company_name"`
+	DBAName     *string           `json:"dba_name,omitempty"`
+	LogoURL     *string           `json:"logo_url,omitempty"`
+	City        string            `json:"city"`
+	State       string            `json:"state"`
+	Badge       *enums.StoreBadge `json:"badge,omitempty"`
+	Ratings     map[string]int    `json:"ratings,omitempty"`
+	Categories  []string          `json:"categories,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// SearchVendorsResult wraps a page of vendor summaries and the cursor for the next page.
+type SearchVendorsResult struct {
+	Vendors    []VendorSummaryDTO `json:"vendors"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// NewVendorSummaryDTO maps a store row into its discovery summary.
+func NewVendorSummaryDTO(m *models.Store) VendorSummaryDTO {
+	return VendorSummaryDTO{
+		ID:          m.ID,
+		CompanyName: m.CompanyName,
+		DBAName:     m.DBAName,
+		LogoURL:     m.LogoURL,
+		City:        m.Address.City,
+		State:       m.Address.State,
+		Badge:       cloneStoreBadgePtr(m.Badge),
+		Ratings:     map[string]int(m.Ratings),
+		Categories:  append([]string(nil), m.Categories...),
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const adminSystemRole = "admin"
+
+// kycTransitions lists the statuses an admin may move a store into from each current status.
+var kycTransitions = map[enums.KYCStatus][]enums.KYCStatus{
+	enums.KYCStatusPendingVerification: {enums.KYCStatusUnderReview, enums.KYCStatusRejected},
+	enums.KYCStatusUnderReview:         {enums.KYCStatusVerified, enums.KYCStatusRejected, enums.KYCStatusPendingVerification},
+	enums.KYCStatusVerified:            {enums.KYCStatusUnderReview, enums.KYCStatusSuspended},
+	enums.KYCStatusRejected:            {enums.KYCStatusUnderReview},
+	enums.KYCStatusExpired:             {enums.KYCStatusUnderReview},
+	enums.KYCStatusSuspended:           {enums.KYCStatusUnderReview, enums.KYCStatusVerified},
+}
+
+// CanTransitionKYC reports whether a store may move from one KYC status to another.
+func CanTransitionKYC(from, to enums.KYCStatus) bool {
+	for _, candidate := range kycTransitions[from] {
+		if candidate == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) UpdateKYCStatus(ctx context.Context, adminID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*StoreDTO, error) {
+	if adminID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "admin identity missing")
+	}
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store id is required")
+	}
+	if !status.IsValid() {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid kyc status")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" && (status == enums.KYCStatusRejected || status == enums.KYCStatusSuspended) {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "reason is required")
+	}
+
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	var updated *models.Store
+	if err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		store, err := s.repo.FindByIDWithTx(tx, storeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
+		}
+
+		previous := store.KYCStatus
+		if !CanTransitionKYC(previous, status) {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "kyc status transition not allowed").WithDetails(map[string]any{
+				"from": previous,
+				"to":   status,
+			})
+		}
+
+		if err := s.repo.UpdateStatusWithTx(tx, storeID, status); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update store kyc")
+		}
+
+		change := &models.StoreKYCStatusChange{
+			StoreID:     storeID,
+			AdminUserID: adminID,
+			FromStatus:  previous,
+			ToStatus:    status,
+		}
+		if reason != "" {
+			change.Reason = &reason
+		}
+		if err := s.repo.CreateKYCStatusChangeWithTx(tx, change); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record kyc status change")
+		}
+
+		event := outbox.DomainEvent{
+			EventType:     enums.EventStoreKYCStatusChanged,
+			AggregateType: enums.AggregateStore,
+			AggregateID:   storeID,
+			Actor:         &outbox.ActorRef{UserID: adminID, Role: adminSystemRole},
+			Data: payloads.StoreKYCStatusChangedEvent{
+				StoreID:        storeID,
+				PreviousStatus: previous,
+				Status:         status,
+				Reason:         reason,
+				ChangedBy:      adminID,
+			},
+			Version: 1,
+		}
+		if err := s.publisher.Emit(ctx, tx, event); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "emit kyc status event")
+		}
+
+		store.KYCStatus = status
+		updated = store
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return FromModel(updated, nil), nil
+}
@@ -0,0 +1,148 @@
+package stores
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox/payloads"
+	"github.com/google/uuid"
+)
+
+func newKYCService(t *testing.T, store *models.Store, admin *models.User) (Service, *stubStoreRepo, *stubOutboxPublisher) {
+	t.Helper()
+	repo := &stubStoreRepo{store: store}
+	publisher := &stubOutboxPublisher{}
+	svc, err := NewService(ServiceParams{
+		Repo:                 repo,
+		Memberships:          &stubMembershipsRepo{},
+		Users:                &stubUsersRepo{byID: admin},
+		PasswordCfg:          config.PasswordConfig{},
+		TransactionRunner:    newStubTxRunner(),
+		AttachmentReconciler: &stubAttachmentReconciler{},
+		MediaRepo:            &stubMediaRepo{},
+		LicenseRepo:          &stubLicenseRepo{},
+		Publisher:            publisher,
+		Logg:                 logger.New(logger.Options{ServiceName: "stores-test", Output: io.Discard}),
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	return svc, repo, publisher
+}
+
+func adminUser() *models.User {
+	return &models.User{ID: uuid.New(), SystemRole: stringPtr("admin")}
+}
+
+func TestUpdateKYCStatusTransitions(t *testing.T) {
+	cases := []struct {
+		name   string
+		from   enums.KYCStatus
+		to     enums.KYCStatus
+		reason string
+	}{
+		{name: "pending to under review", from: enums.KYCStatusPendingVerification, to: enums.KYCStatusUnderReview},
+		{name: "under review to verified", from: enums.KYCStatusUnderReview, to: enums.KYCStatusVerified},
+		{name: "under review to rejected", from: enums.KYCStatusUnderReview, to: enums.KYCStatusRejected, reason: "license mismatch"},
+		{name: "rejected back to under review", from: enums.KYCStatusRejected, to: enums.KYCStatusUnderReview},
+		{name: "verified to suspended", from: enums.KYCStatusVerified, to: enums.KYCStatusSuspended, reason: "compliance hold"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := baseStore()
+			store.KYCStatus = tc.from
+			admin := adminUser()
+			svc, repo, publisher := newKYCService(t, store, admin)
+
+			dto, err := svc.UpdateKYCStatus(context.Background(), admin.ID, store.ID, tc.to, tc.reason)
+			if err != nil {
+				t.Fatalf("update kyc: %v", err)
+			}
+			if dto.KYCStatus != tc.to {
+				t.Fatalf("expected status %s got %s", tc.to, dto.KYCStatus)
+			}
+			if repo.kycStatus != tc.to {
+				t.Fatalf("expected persisted status %s got %s", tc.to, repo.kycStatus)
+			}
+			if len(repo.changes) != 1 {
+				t.Fatalf("expected one audit row, got %d", len(repo.changes))
+			}
+			change := repo.changes[0]
+			if change.FromStatus != tc.from || change.ToStatus != tc.to || change.AdminUserID != admin.ID {
+				t.Fatalf("unexpected audit row %+v", change)
+			}
+			if tc.reason != "" && (change.Reason == nil || *change.Reason != tc.reason) {
+				t.Fatalf("expected reason %q got %v", tc.reason, change.Reason)
+			}
+			if len(publisher.events) != 1 {
+				t.Fatalf("expected one event, got %d", len(publisher.events))
+			}
+			event := publisher.events[0]
+			if event.EventType != enums.EventStoreKYCStatusChanged || event.AggregateID != store.ID {
+				t.Fatalf("unexpected event %+v", event)
+			}
+			payload, ok := event.Data.(payloads.StoreKYCStatusChangedEvent)
+			if !ok {
+				t.Fatalf("unexpected payload type %T", event.Data)
+			}
+			if payload.PreviousStatus != tc.from || payload.Status != tc.to {
+				t.Fatalf("unexpected payload %+v", payload)
+			}
+		})
+	}
+}
+
+func TestUpdateKYCStatusInvalidTransition(t *testing.T) {
+	store := baseStore()
+	store.KYCStatus = enums.KYCStatusPendingVerification
+	admin := adminUser()
+	svc, repo, publisher := newKYCService(t, store, admin)
+
+	_, err := svc.UpdateKYCStatus(context.Background(), admin.ID, store.ID, enums.KYCStatusVerified, "")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeStateConflict {
+		t.Fatalf("expected state conflict, got %v", err)
+	}
+	if repo.kycStatus != "" || len(repo.changes) != 0 || len(publisher.events) != 0 {
+		t.Fatalf("expected no side effects, got status=%s changes=%d events=%d", repo.kycStatus, len(repo.changes), len(publisher.events))
+	}
+}
+
+func TestUpdateKYCStatusRequiresAdmin(t *testing.T) {
+	store := baseStore()
+	store.KYCStatus = enums.KYCStatusUnderReview
+	user := &models.User{ID: uuid.New()}
+	svc, repo, _ := newKYCService(t, store, user)
+
+	_, err := svc.UpdateKYCStatus(context.Background(), user.ID, store.ID, enums.KYCStatusVerified, "")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if repo.kycStatus != "" {
+		t.Fatalf("expected status untouched, got %s", repo.kycStatus)
+	}
+}
+
+func TestUpdateKYCStatusRejectRequiresReason(t *testing.T) {
+	store := baseStore()
+	store.KYCStatus = enums.KYCStatusUnderReview
+	admin := adminUser()
+	svc, _, _ := newKYCService(t, store, admin)
+
+	_, err := svc.UpdateKYCStatus(context.Background(), admin.ID, store.ID, enums.KYCStatusRejected, "  ")
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
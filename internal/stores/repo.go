@@ -3,10 +3,13 @@ package stores
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -114,6 +117,7 @@ func (r *Repository) UpdateWithTx(tx *gorm.DB, store *models.Store) error {
 	return nil
 }
 
+// UpdateSubscriptionActiveWithTx sets the subscription flag outright and ends any grace window.
 func (r *Repository) UpdateSubscriptionActiveWithTx(tx *gorm.DB, storeID uuid.UUID, active bool) error {
 	if tx == nil {
 		return fmt.Errorf("tx is required")
@@ -125,8 +129,9 @@ func (r *Repository) UpdateSubscriptionActiveWithTx(tx *gorm.DB, storeID uuid.UU
 	res := tx.Model(&models.Store{}).
 		Where("id = ?", storeID).
 		Updates(map[string]any{
-			"subscription_active": active,
-			"updated_at":          time.Now(),
+			"subscription_active":      active,
+			"subscription_grace_until": nil,
+			"updated_at":               time.Now(),
 		})
 
 	if res.Error != nil {
@@ -151,6 +156,19 @@ func (r *Repository) UpdateStatusWithTx(tx *gorm.DB, storeID uuid.UUID, newStatu
 	return nil
 }
 
+// ExpireSubscriptionGrace revokes stores whose subscription grace window ended at or before now.
+func (r *Repository) ExpireSubscriptionGrace(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.Store{}).
+		Where("subscription_grace_until IS NOT NULL AND subscription_grace_until <= ?", now).
+		Updates(map[string]any{
+			"subscription_active":      false,
+			"subscription_grace_until": nil,
+			"updated_at":               now,
+		})
+	return res.RowsAffected, res.Error
+}
+
 func (r *Repository) UpdateLastLoggedInAt(ctx context.Context, storeID uuid.UUID) error {
 	if storeID == uuid.Nil {
 		return fmt.Errorf("storeID is required")
@@ -170,3 +188,60 @@ func (r *Repository) UpdateLastLoggedInAt(ctx context.Context, storeID uuid.UUID
 	}
 	return nil
 }
+
+// CreateKYCStatusChangeWithTx appends a KYC audit row using the provided transaction.
+func (r *Repository) CreateKYCStatusChangeWithTx(tx *gorm.DB, change *models.StoreKYCStatusChange) error {
+	if tx == nil {
+		return gorm.ErrInvalidTransaction
+	}
+	if change == nil {
+		return fmt.Errorf("kyc status change is required")
+	}
+	return tx.Create(change).Error
+}
+
+type vendorSearchQuery struct {
+	State      string
+	NamePrefix string
+	Category   *enums.ProductCategory
+	Cursor     *pagination.Cursor
+	Limit      int
+}
+
+// SearchVendors lists verified, subscribed vendor stores matching the discovery filters,
+// ordered newest first and keyed by (created_at, id) for cursor pagination.
+func (r *Repository) SearchVendors(ctx context.Context, query vendorSearchQuery) ([]models.Store, error) {
+	q := r.db.WithContext(ctx).
+		Table("stores s").
+		Select("s.*").
+		Where("s.type = ?", enums.StoreTypeVendor).
+		Where("s.kyc_status = ?", enums.KYCStatusVerified).
+		Where("s.subscription_active = ?", true)
+
+	if state := strings.TrimSpace(query.State); state != "" {
+		q = q.Where("LOWER((s.address).state) = LOWER(?)", state)
+	}
+	if prefix := strings.TrimSpace(query.NamePrefix); prefix != "" {
+		q = q.Where("LOWER(s.company_name) LIKE ?", escapeLike(strings.ToLower(prefix))+"%")
+	}
+	if query.Category != nil {
+		products := r.db.Table("products p").
+			Select("1").
+			Where("p.store_id = s.id AND p.category = ? AND p.is_active = ?", *query.Category, true).
+			Scopes(db.NotDeleted("p.deleted_at"))
+		q = q.Where("EXISTS (?)", products)
+	}
+	if query.Cursor != nil {
+		q = q.Where("(s.created_at < ?) OR (s.created_at = ? AND s.id < ?)", query.Cursor.CreatedAt, query.Cursor.CreatedAt, query.Cursor.ID)
+	}
+
+	var rows []models.Store
+	if err := q.Order("s.created_at DESC").Order("s.id DESC").Limit(query.Limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
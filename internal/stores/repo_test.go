@@ -0,0 +1,102 @@
+package stores
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupVendorSearchTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, ddl := range []string{
+		`CREATE TABLE stores (
+  id TEXT PRIMARY KEY,
+  type TEXT NOT NULL,
+  company_name TEXT NOT NULL,
+  kyc_status TEXT NOT NULL,
+  subscription_active INTEGER NOT NULL,
+  address TEXT,
+  created_at DATETIME
+);`,
+		`CREATE TABLE products (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL,
+  category TEXT NOT NULL,
+  is_active INTEGER NOT NULL,
+  deleted_at DATETIME
+);`,
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatalf("create schema: %v", err)
+		}
+	}
+	return db
+}
+
+func seedSearchVendor(t *testing.T, db *gorm.DB, name string, createdAt time.Time) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	if err := db.Exec(
+		`INSERT INTO stores (id, type, company_name, kyc_status, subscription_active, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id, enums.StoreTypeVendor, name, enums.KYCStatusVerified, true, createdAt,
+	).Error; err != nil {
+		t.Fatalf("insert store: %v", err)
+	}
+	return id
+}
+
+func seedSearchProduct(t *testing.T, db *gorm.DB, storeID uuid.UUID, category enums.ProductCategory, active bool, deletedAt *time.Time) {
+	t.Helper()
+	if err := db.Exec(
+		`INSERT INTO products (id, store_id, category, is_active, deleted_at) VALUES (?, ?, ?, ?, ?)`,
+		uuid.New(), storeID, category, active, deletedAt,
+	).Error; err != nil {
+		t.Fatalf("insert product: %v", err)
+	}
+}
+
+func TestRepositorySearchVendorsFiltersByCategory(t *testing.T) {
+	db := setupVendorSearchTestDB(t)
+	repo := NewRepository(db)
+
+	now := time.Now().UTC()
+	deletedAt := now.Add(-time.Hour)
+	withFlower := seedSearchVendor(t, db, "Flower Co", now)
+	onlyEdibles := seedSearchVendor(t, db, "Edible Co", now.Add(-time.Minute))
+	inactiveFlower := seedSearchVendor(t, db, "Paused Co", now.Add(-2*time.Minute))
+	deletedFlower := seedSearchVendor(t, db, "Deleted Co", now.Add(-3*time.Minute))
+	seedSearchVendor(t, db, "Empty Co", now.Add(-4*time.Minute))
+
+	seedSearchProduct(t, db, withFlower, enums.ProductCategoryFlower, true, nil)
+	seedSearchProduct(t, db, withFlower, enums.ProductCategoryEdible, true, nil)
+	seedSearchProduct(t, db, onlyEdibles, enums.ProductCategoryEdible, true, nil)
+	seedSearchProduct(t, db, inactiveFlower, enums.ProductCategoryFlower, false, nil)
+	seedSearchProduct(t, db, deletedFlower, enums.ProductCategoryFlower, true, &deletedAt)
+
+	category := enums.ProductCategoryFlower
+	rows, err := repo.SearchVendors(context.Background(), vendorSearchQuery{Category: &category, Limit: 10})
+	if err != nil {
+		t.Fatalf("search vendors: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ID != withFlower {
+		t.Fatalf("expected only the vendor with an active flower product, got %d rows", len(rows))
+	}
+
+	rows, err = repo.SearchVendors(context.Background(), vendorSearchQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("search vendors: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("expected all vendors without a category filter, got %d", len(rows))
+	}
+}
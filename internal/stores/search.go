@@ -0,0 +1,52 @@
+package stores
+
+import (
+	"context"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+)
+
+// SearchVendorsInput captures the buyer discovery filters.
+type SearchVendorsInput struct {
+	State      string
+	NamePrefix string
+	Category   *enums.ProductCategory
+	Pagination pagination.Params
+}
+
+func (s *service) SearchVendors(ctx context.Context, input SearchVendorsInput) (*SearchVendorsResult, error) {
+	if input.Category != nil && !input.Category.IsValid() {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "invalid category")
+	}
+
+	cursor, err := pagination.ParseCursor(input.Pagination.Cursor)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid cursor")
+	}
+
+	pageSize := pagination.NormalizeLimit(input.Pagination.Limit)
+	rows, err := s.repo.SearchVendors(ctx, vendorSearchQuery{
+		State:      strings.TrimSpace(input.State),
+		NamePrefix: strings.TrimSpace(input.NamePrefix),
+		Category:   input.Category,
+		Cursor:     cursor,
+		Limit:      pagination.LimitWithBuffer(input.Pagination.Limit),
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "search vendors")
+	}
+
+	result := &SearchVendorsResult{Vendors: make([]VendorSummaryDTO, 0, len(rows))}
+	if len(rows) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		result.NextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	for i := range rows {
+		result.Vendors = append(result.Vendors, NewVendorSummaryDTO(&rows[i]))
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package stores
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func vendorFixtures(states ...string) []models.Store {
+	now := time.Now().UTC()
+	vendors := make([]models.Store, 0, len(states))
+	for i, state := range states {
+		vendor := *baseStore()
+		vendor.Type = enums.StoreTypeVendor
+		vendor.CompanyName = "Vendor " + state
+		vendor.Address.State = state
+		vendor.CreatedAt = now.Add(-time.Duration(i) * time.Minute)
+		vendors = append(vendors, vendor)
+	}
+	return vendors
+}
+
+func TestSearchVendorsFiltersByState(t *testing.T) {
+	repo := &stubStoreRepo{vendors: vendorFixtures("OK", "TX", "OK", "CO")}
+	svc, err := newStoreService(repo, &stubMembershipsRepo{}, &stubUsersRepo{})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	result, err := svc.SearchVendors(context.Background(), SearchVendorsInput{State: " ok "})
+	if err != nil {
+		t.Fatalf("search vendors: %v", err)
+	}
+	if len(result.Vendors) != 2 {
+		t.Fatalf("expected 2 vendors, got %d", len(result.Vendors))
+	}
+	for _, vendor := range result.Vendors {
+		if vendor.State != "OK" {
+			t.Fatalf("unexpected vendor state %s", vendor.State)
+		}
+	}
+	if result.NextCursor != "" {
+		t.Fatalf("expected no next cursor, got %q", result.NextCursor)
+	}
+	if got := repo.searches[0].State; got != "ok" {
+		t.Fatalf("expected trimmed state filter, got %q", got)
+	}
+}
+
+func TestSearchVendorsPaginationContinuity(t *testing.T) {
+	repo := &stubStoreRepo{vendors: vendorFixtures("OK", "OK", "OK", "OK", "OK")}
+	svc, err := newStoreService(repo, &stubMembershipsRepo{}, &stubUsersRepo{})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	var order []uuid.UUID
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		result, err := svc.SearchVendors(context.Background(), SearchVendorsInput{
+			State:      "OK",
+			Pagination: pagination.Params{Limit: 2, Cursor: cursor},
+		})
+		if err != nil {
+			t.Fatalf("search vendors page %d: %v", page, err)
+		}
+		for _, vendor := range result.Vendors {
+			if seen[vendor.ID] {
+				t.Fatalf("vendor %s returned twice", vendor.ID)
+			}
+			seen[vendor.ID] = true
+			order = append(order, vendor.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	if len(order) != len(repo.vendors) {
+		t.Fatalf("expected %d vendors across pages, got %d", len(repo.vendors), len(order))
+	}
+	for i, vendor := range repo.vendors {
+		if order[i] != vendor.ID {
+			t.Fatalf("unexpected order at %d", i)
+		}
+	}
+}
+
+func TestSearchVendorsRejectsInvalidCursor(t *testing.T) {
+	svc, err := newStoreService(&stubStoreRepo{}, &stubMembershipsRepo{}, &stubUsersRepo{})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	if _, err := svc.SearchVendors(context.Background(), SearchVendorsInput{Pagination: pagination.Params{Cursor: "not-a-cursor"}}); err == nil {
+		t.Fatal("expected invalid cursor error")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/security"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
@@ -28,6 +29,9 @@ type storeRepository interface {
 	Update(ctx context.Context, store *models.Store) error
 	FindByIDWithTx(tx *gorm.DB, id uuid.UUID) (*models.Store, error)
 	UpdateWithTx(tx *gorm.DB, store *models.Store) error
+	UpdateStatusWithTx(tx *gorm.DB, storeID uuid.UUID, newStatus enums.KYCStatus) error
+	CreateKYCStatusChangeWithTx(tx *gorm.DB, change *models.StoreKYCStatusChange) error
+	SearchVendors(ctx context.Context, query vendorSearchQuery) ([]models.Store, error)
 }
 
 type licenseRepository interface {
@@ -43,6 +47,10 @@ type membershipsRepository interface {
 	CountMembersWithRoles(ctx context.Context, storeID uuid.UUID, roles ...enums.MemberRole) (int64, error)
 }
 
+type outboxPublisher interface {
+	Emit(ctx context.Context, tx *gorm.DB, event outbox.DomainEvent) error
+}
+
 type mediaLookup interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Media, error)
 }
@@ -63,6 +71,9 @@ type Service interface {
 	ListUsers(ctx context.Context, userID, storeID uuid.UUID) ([]memberships.StoreUserDTO, error)
 	InviteUser(ctx context.Context, inviterID, storeID uuid.UUID, input InviteUserInput) (*memberships.StoreUserDTO, string, error)
 	RemoveUser(ctx context.Context, actorID, storeID, targetUserID uuid.UUID) error
+	UpdateKYCStatus(ctx context.Context, adminID, storeID uuid.UUID, status enums.KYCStatus, reason string) (*StoreDTO, error)
+	VerifyTaxExemption(ctx context.Context, adminID, storeID uuid.UUID, review TaxExemptionReview) (*StoreDTO, error)
+	SearchVendors(ctx context.Context, input SearchVendorsInput) (*SearchVendorsResult, error)
 }
 
 type txRunner interface {
@@ -79,6 +90,7 @@ type ServiceParams struct {
 	AttachmentReconciler media.AttachmentReconciler
 	MediaRepo            mediaLookup
 	LicenseRepo          licenseRepository
+	Publisher            outboxPublisher
 	Logg                 *logger.Logger
 }
 
@@ -91,6 +103,7 @@ type service struct {
 	attachmentReconciler media.AttachmentReconciler
 	media                mediaLookup
 	licenseRepo          licenseRepository
+	publisher            outboxPublisher
 	Logg                 *logger.Logger
 }
 
@@ -117,6 +130,9 @@ func NewService(params ServiceParams) (Service, error) {
 	if params.LicenseRepo == nil {
 		return nil, fmt.Errorf("license repository required")
 	}
+	if params.Publisher == nil {
+		return nil, fmt.Errorf("outbox publisher required")
+	}
 	if params.Logg == nil {
 		return nil, fmt.Errorf("license repository required")
 	}
@@ -129,6 +145,7 @@ func NewService(params ServiceParams) (Service, error) {
 		attachmentReconciler: params.AttachmentReconciler,
 		media:                params.MediaRepo,
 		licenseRepo:          params.LicenseRepo,
+		publisher:            params.Publisher,
 		Logg:                 params.Logg,
 	}, nil
 }
@@ -146,6 +163,20 @@ type UpdateStoreInput struct {
 	LogoMediaID   types.NullableUUID
 	Ratings       *map[string]int
 	Categories    *[]string
+	DeliveryZones *types.DeliveryZones
+	// BusinessHours limits when the vendor accepts orders; checkout blocks or queues orders placed
+	// outside them. Empty windows clear the hours.
+	BusinessHours *types.BusinessHours
+	// AutoAccept lets a vendor skip manual decisions: checkout creates its orders already accepted.
+	AutoAccept *bool
+	// VolumeDiscountStrategy picks which volume tier applies when several qualify for a cart line.
+	VolumeDiscountStrategy *enums.VolumeDiscountStrategy
+	// TaxExempt asks checkout to skip tax for a buyer store. It only takes effect once the
+	// exemption certificate has been verified.
+	TaxExempt *bool
+	// TaxExemptCertificateMediaID replaces the exemption certificate on file, which resets its
+	// verification until it is reviewed again.
+	TaxExemptCertificateMediaID types.NullableUUID
 }
 
 // InviteUserInput captures the data required to invite a store user.
@@ -299,6 +330,7 @@ func (s *service) Update(ctx context.Context, userID, storeID uuid.UUID, input U
 
 		oldLogo := store.LogoMediaID
 		oldBanner := store.BannerMediaID
+		oldTaxCertificate := store.TaxExemptCertificateMediaID
 
 		// Apply patch
 		if input.CompanyName != nil {
@@ -348,6 +380,60 @@ func (s *service) Update(ctx context.Context, userID, storeID uuid.UUID, input U
 		if input.Categories != nil {
 			store.Categories = cloneCategories(*input.Categories)
 		}
+		if input.DeliveryZones != nil {
+			if store.Type != enums.StoreTypeVendor {
+				return pkgerrors.New(pkgerrors.CodeValidation, "delivery zones are only supported for vendor stores")
+			}
+			zones, err := normalizeDeliveryZones(input.DeliveryZones)
+			if err != nil {
+				return err
+			}
+			store.DeliveryZones = zones
+		}
+		if input.BusinessHours != nil {
+			if store.Type != enums.StoreTypeVendor {
+				return pkgerrors.New(pkgerrors.CodeValidation, "business hours are only supported for vendor stores")
+			}
+			hours, err := input.BusinessHours.Normalize()
+			if err != nil {
+				return pkgerrors.New(pkgerrors.CodeValidation, err.Error())
+			}
+			store.BusinessHours = hours
+		}
+		if input.AutoAccept != nil {
+			if store.Type != enums.StoreTypeVendor {
+				return pkgerrors.New(pkgerrors.CodeValidation, "auto accept is only supported for vendor stores")
+			}
+			store.AutoAccept = *input.AutoAccept
+		}
+		if input.VolumeDiscountStrategy != nil {
+			if store.Type != enums.StoreTypeVendor {
+				return pkgerrors.New(pkgerrors.CodeValidation, "volume discount strategy is only supported for vendor stores")
+			}
+			if !input.VolumeDiscountStrategy.IsValid() {
+				return pkgerrors.New(pkgerrors.CodeValidation, "invalid volume discount strategy")
+			}
+			store.VolumeDiscountStrategy = *input.VolumeDiscountStrategy
+		}
+		if input.TaxExempt != nil {
+			if store.Type != enums.StoreTypeBuyer {
+				return pkgerrors.New(pkgerrors.CodeValidation, "tax exemption is only supported for buyer stores")
+			}
+			store.TaxExempt = *input.TaxExempt
+		}
+		if input.TaxExemptCertificateMediaID.Valid {
+			if store.Type != enums.StoreTypeBuyer {
+				return pkgerrors.New(pkgerrors.CodeValidation, "tax exemption is only supported for buyer stores")
+			}
+			if err := s.requireStoreMedia(ctx, store.ID, input.TaxExemptCertificateMediaID.Value); err != nil {
+				return err
+			}
+			if !sameUUIDPtr(store.TaxExemptCertificateMediaID, input.TaxExemptCertificateMediaID.Value) {
+				store.TaxExemptCertificateMediaID = copyUUIDPtr(input.TaxExemptCertificateMediaID.Value)
+				store.TaxExemptCertificateVerifiedAt = nil
+				store.TaxExemptCertificateExpiresAt = nil
+			}
+		}
 
 		step = "debug_json_fields"
 		if s.Logg != nil {
@@ -408,6 +494,13 @@ func (s *service) Update(ctx context.Context, userID, storeID uuid.UUID, input U
 			return err
 		}
 
+		if input.TaxExemptCertificateMediaID.Valid {
+			step = "reconcile_tax_certificate"
+			if err := s.reconcileAttachment(ctx, tx, models.AttachmentEntityStoreTaxCertificate, store.ID, store.ID, oldTaxCertificate, store.TaxExemptCertificateMediaID); err != nil {
+				return err
+			}
+		}
+
 		step = "save_store"
 		if err := s.repo.UpdateWithTx(tx, store); err != nil {
 			return db.MapPGError(err)
@@ -610,6 +703,27 @@ func cloneCategories(value []string) pq.StringArray {
 	return res
 }
 
+// normalizeDeliveryZones upper-cases and dedupes states and clears the column when nothing is configured.
+func normalizeDeliveryZones(zones *types.DeliveryZones) (*types.DeliveryZones, error) {
+	normalized := &types.DeliveryZones{}
+	seen := map[string]struct{}{}
+	for _, state := range zones.States {
+		code := strings.ToUpper(strings.TrimSpace(state))
+		if len(code) != 2 {
+			return nil, pkgerrors.New(pkgerrors.CodeValidation, "delivery zone states must be two-letter codes")
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		normalized.States = append(normalized.States, code)
+	}
+	if normalized.IsEmpty() {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
 func (s *service) mediaPublicURL(ctx context.Context, storeID uuid.UUID, mediaID *uuid.UUID) (*string, error) {
 	if mediaID == nil {
 		return nil, nil
@@ -634,6 +748,28 @@ func (s *service) mediaPublicURL(ctx context.Context, storeID uuid.UUID, mediaID
 	return &url, nil
 }
 
+// requireStoreMedia checks that mediaID is an uploaded object owned by the store. Unlike
+// mediaPublicURL it accepts private media such as certificates.
+func (s *service) requireStoreMedia(ctx context.Context, storeID uuid.UUID, mediaID *uuid.UUID) error {
+	if mediaID == nil {
+		return nil
+	}
+	mediaRow, err := s.media.FindByID(ctx, *mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgerrors.New(pkgerrors.CodeValidation, "media not found")
+		}
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load media")
+	}
+	if mediaRow.StoreID != storeID {
+		return pkgerrors.New(pkgerrors.CodeValidation, "media belongs to different store")
+	}
+	if !isReadableMediaStatus(mediaRow.Status) {
+		return pkgerrors.New(pkgerrors.CodeConflict, "media not ready")
+	}
+	return nil
+}
+
 func isReadableMediaStatus(status enums.MediaStatus) bool {
 	return status == enums.MediaStatusUploaded || status == enums.MediaStatusReady
 }
@@ -646,6 +782,13 @@ func copyUUIDPtr(id *uuid.UUID) *uuid.UUID {
 	return &cpy
 }
 
+func sameUUIDPtr(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
 func uuidSlice(id *uuid.UUID) []uuid.UUID {
 	if id == nil {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 	"gorm.io/driver/sqlite"
@@ -74,6 +76,7 @@ func newStoreServiceWithAttachmentStub(repo storeRepository, members *stubMember
 		AttachmentReconciler: reconciler,
 		MediaRepo:            mediaRepo,
 		LicenseRepo:          licenseRepo,
+		Publisher:            &stubOutboxPublisher{},
 		Logg:                 logger.New(logger.Options{ServiceName: "stores-test", Output: io.Discard}),
 	})
 	return svc, reconciler, err
@@ -454,6 +457,50 @@ func TestServiceUpdatePopulatesMediaURLs(t *testing.T) {
 	}
 }
 
+func TestServiceUpdateReplacingTaxCertificateResetsVerification(t *testing.T) {
+	store := baseStore()
+	oldCertificateID := uuid.New()
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	expiresAt := time.Now().Add(365 * 24 * time.Hour)
+	store.TaxExemptCertificateMediaID = &oldCertificateID
+	store.TaxExemptCertificateVerifiedAt = &verifiedAt
+	store.TaxExemptCertificateExpiresAt = &expiresAt
+	repo := &stubStoreRepo{store: store}
+	att := &stubAttachmentReconciler{}
+	certificateID := uuid.New()
+	mediaRepo := &stubMediaRepo{
+		entries: map[uuid.UUID]*models.Media{
+			certificateID: {ID: certificateID, StoreID: store.ID, Status: enums.MediaStatusUploaded},
+		},
+	}
+	svc, _, err := newStoreServiceWithAttachmentStub(repo, &stubMembershipsRepo{allowed: true}, &stubUsersRepo{}, att, mediaRepo, nil)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	exempt := true
+	dto, err := svc.Update(context.Background(), uuid.New(), store.ID, UpdateStoreInput{
+		TaxExempt:                   &exempt,
+		TaxExemptCertificateMediaID: types.NullableUUID{Valid: true, Value: &certificateID},
+	})
+	if err != nil {
+		t.Fatalf("update store: %v", err)
+	}
+	if !dto.TaxExempt || dto.TaxExemptCertificateMediaID == nil || *dto.TaxExemptCertificateMediaID != certificateID {
+		t.Fatalf("expected exemption with new certificate, got %+v", dto)
+	}
+	if dto.TaxExemptCertificateVerifiedAt != nil || dto.TaxExemptCertificateExpiresAt != nil {
+		t.Fatalf("expected replaced certificate to need verification again")
+	}
+	if dto.TaxExemptAt(time.Now()) {
+		t.Fatalf("expected unverified certificate not to exempt the store")
+	}
+	last := att.calls[len(att.calls)-1]
+	if last.entityType != models.AttachmentEntityStoreTaxCertificate || len(last.newIDs) != 1 || last.newIDs[0] != certificateID {
+		t.Fatalf("expected certificate attachment reconciled, got %+v", last)
+	}
+}
+
 func TestServiceUpdateForbidden(t *testing.T) {
 	repo := &stubStoreRepo{store: baseStore()}
 	svc, err := newStoreService(repo, &stubMembershipsRepo{allowed: false}, &stubUsersRepo{})
@@ -737,6 +784,11 @@ type stubStoreRepo struct {
 	err       error
 	updateErr error
 	updated   *models.Store
+	kycStatus enums.KYCStatus
+	kycErr    error
+	changes   []models.StoreKYCStatusChange
+	vendors   []models.Store
+	searches  []vendorSearchQuery
 }
 
 func (s *stubStoreRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Store, error) {
@@ -759,6 +811,63 @@ func (s *stubStoreRepo) UpdateWithTx(tx *gorm.DB, store *models.Store) error {
 	return s.Update(context.Background(), store)
 }
 
+func (s *stubStoreRepo) UpdateStatusWithTx(tx *gorm.DB, storeID uuid.UUID, newStatus enums.KYCStatus) error {
+	if s.kycErr != nil {
+		return s.kycErr
+	}
+	s.kycStatus = newStatus
+	return nil
+}
+
+func (s *stubStoreRepo) CreateKYCStatusChangeWithTx(tx *gorm.DB, change *models.StoreKYCStatusChange) error {
+	s.changes = append(s.changes, *change)
+	return nil
+}
+
+// SearchVendors mimics the repository query over the in-memory vendor list, which
+// is expected to already be ordered by (created_at, id) descending.
+func (s *stubStoreRepo) SearchVendors(ctx context.Context, query vendorSearchQuery) ([]models.Store, error) {
+	s.searches = append(s.searches, query)
+	if s.err != nil {
+		return nil, s.err
+	}
+	var rows []models.Store
+	for _, vendor := range s.vendors {
+		if query.State != "" && !strings.EqualFold(vendor.Address.State, query.State) {
+			continue
+		}
+		if query.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(vendor.CompanyName), strings.ToLower(query.NamePrefix)) {
+			continue
+		}
+		if query.Cursor != nil {
+			if vendor.CreatedAt.After(query.Cursor.CreatedAt) {
+				continue
+			}
+			if vendor.CreatedAt.Equal(query.Cursor.CreatedAt) && vendor.ID.String() >= query.Cursor.ID.String() {
+				continue
+			}
+		}
+		rows = append(rows, vendor)
+		if len(rows) == query.Limit {
+			break
+		}
+	}
+	return rows, nil
+}
+
+type stubOutboxPublisher struct {
+	events []outbox.DomainEvent
+	err    error
+}
+
+func (s *stubOutboxPublisher) Emit(ctx context.Context, tx *gorm.DB, event outbox.DomainEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
 type stubMembershipsRepo struct {
 	allowed            bool
 	err                error
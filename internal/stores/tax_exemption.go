@@ -0,0 +1,91 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TaxExemptionReview records an admin's decision on a buyer store's exemption certificate.
+type TaxExemptionReview struct {
+	// Decision is verified or rejected.
+	Decision enums.LicenseStatus
+	// ExpiresAt optionally ends a verified exemption; it must be in the future.
+	ExpiresAt *time.Time
+}
+
+// VerifyTaxExemption marks the certificate on file verified, so checkout and cart quotes waive tax
+// until ExpiresAt, or clears its verification when the admin rejects it.
+func (s *service) VerifyTaxExemption(ctx context.Context, adminID, storeID uuid.UUID, review TaxExemptionReview) (*StoreDTO, error) {
+	if adminID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeUnauthorized, "admin identity missing")
+	}
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "store id is required")
+	}
+	if review.Decision != enums.LicenseStatusVerified && review.Decision != enums.LicenseStatusRejected {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "decision must be verified or rejected")
+	}
+	now := time.Now().UTC()
+	if review.Decision == enums.LicenseStatusVerified && review.ExpiresAt != nil && !review.ExpiresAt.After(now) {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "expires_at must be in the future")
+	}
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	var updated *models.Store
+	if err := s.tx.WithTx(ctx, func(tx *gorm.DB) error {
+		store, err := s.repo.FindByIDWithTx(tx, storeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
+		}
+		if store.TaxExemptCertificateMediaID == nil {
+			return pkgerrors.New(pkgerrors.CodeStateConflict, "store has no tax exemption certificate on file")
+		}
+
+		store.TaxExemptCertificateVerifiedAt = nil
+		store.TaxExemptCertificateExpiresAt = nil
+		if review.Decision == enums.LicenseStatusVerified {
+			store.TaxExemptCertificateVerifiedAt = &now
+			if review.ExpiresAt != nil {
+				expiresAt := review.ExpiresAt.UTC()
+				store.TaxExemptCertificateExpiresAt = &expiresAt
+			}
+		}
+		if err := s.repo.UpdateWithTx(tx, store); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update tax exemption")
+		}
+		updated = store
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return FromModel(updated, nil), nil
+}
+
+// requireAdmin fails unless userID belongs to a platform admin.
+func (s *service) requireAdmin(ctx context.Context, userID uuid.UUID) error {
+	admin, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkgerrors.New(pkgerrors.CodeForbidden, "admin role required")
+		}
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load admin user")
+	}
+	if admin.SystemRole == nil || strings.ToLower(strings.TrimSpace(*admin.SystemRole)) != adminSystemRole {
+		return pkgerrors.New(pkgerrors.CodeForbidden, "admin role required")
+	}
+	return nil
+}
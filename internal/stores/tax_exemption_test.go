@@ -0,0 +1,94 @@
+package stores
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+func TestVerifyTaxExemptionVerifiesCertificate(t *testing.T) {
+	store := baseStore()
+	certificateID := uuid.New()
+	store.TaxExempt = true
+	store.TaxExemptCertificateMediaID = &certificateID
+	admin := adminUser()
+	svc, repo, _ := newKYCService(t, store, admin)
+
+	expiresAt := time.Now().Add(365 * 24 * time.Hour)
+	dto, err := svc.VerifyTaxExemption(context.Background(), admin.ID, store.ID, TaxExemptionReview{
+		Decision:  enums.LicenseStatusVerified,
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if repo.updated == nil || repo.updated.TaxExemptCertificateVerifiedAt == nil {
+		t.Fatal("expected verified_at persisted")
+	}
+	if dto.TaxExemptCertificateExpiresAt == nil || !dto.TaxExemptCertificateExpiresAt.Equal(expiresAt.UTC()) {
+		t.Fatalf("expected expires_at %v, got %v", expiresAt, dto.TaxExemptCertificateExpiresAt)
+	}
+	if !dto.TaxExemptAt(time.Now()) {
+		t.Fatal("expected the store to be tax exempt once verified")
+	}
+
+	dto, err = svc.VerifyTaxExemption(context.Background(), admin.ID, store.ID, TaxExemptionReview{Decision: enums.LicenseStatusRejected})
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if dto.TaxExemptCertificateVerifiedAt != nil || dto.TaxExemptCertificateExpiresAt != nil || dto.TaxExemptAt(time.Now()) {
+		t.Fatalf("expected rejection to clear the verification, got %+v", dto)
+	}
+}
+
+func TestVerifyTaxExemptionRejectsInvalidReviews(t *testing.T) {
+	admin := adminUser()
+	past := time.Now().Add(-time.Hour)
+	certificateID := uuid.New()
+
+	cases := []struct {
+		name     string
+		withCert bool
+		review   TaxExemptionReview
+		code     pkgerrors.Code
+	}{
+		{name: "unknown decision", withCert: true, review: TaxExemptionReview{Decision: enums.LicenseStatusPending}, code: pkgerrors.CodeValidation},
+		{name: "expiry in the past", withCert: true, review: TaxExemptionReview{Decision: enums.LicenseStatusVerified, ExpiresAt: &past}, code: pkgerrors.CodeValidation},
+		{name: "no certificate", review: TaxExemptionReview{Decision: enums.LicenseStatusVerified}, code: pkgerrors.CodeStateConflict},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := baseStore()
+			if tc.withCert {
+				store.TaxExemptCertificateMediaID = &certificateID
+			}
+			svc, repo, _ := newKYCService(t, store, admin)
+			_, err := svc.VerifyTaxExemption(context.Background(), admin.ID, store.ID, tc.review)
+			if typed := pkgerrors.As(err); typed == nil || typed.Code() != tc.code {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+			if repo.updated != nil {
+				t.Fatal("expected no update")
+			}
+		})
+	}
+}
+
+func TestVerifyTaxExemptionRequiresAdmin(t *testing.T) {
+	store := baseStore()
+	certificateID := uuid.New()
+	store.TaxExemptCertificateMediaID = &certificateID
+	user := adminUser()
+	user.SystemRole = nil
+	svc, _, _ := newKYCService(t, store, user)
+
+	_, err := svc.VerifyTaxExemption(context.Background(), user.ID, store.ID, TaxExemptionReview{Decision: enums.LicenseStatusVerified})
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package storewebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/google/uuid"
+)
+
+const storeWebhookConsumer = "store-webhooks"
+
+// deliveredEvents are the order lifecycle events pushed to store webhooks. Each payload
+// names the buyer and/or vendor store it concerns.
+var deliveredEvents = map[enums.OutboxEventType]struct{}{
+	enums.EventOrderDecided:          {},
+	enums.EventOrderReadyForDispatch: {},
+	enums.EventOrderCanceled:         {},
+	enums.EventOrderExpired:          {},
+	enums.EventOrderRetried:          {},
+	enums.EventOrderPendingNudge:     {},
+	enums.EventCashCollected:         {},
+	enums.EventOrderPaid:             {},
+}
+
+// Order payloads are not consistent about key casing, so both spellings are read.
+var storeIDKeys = []string{"buyer_store_id", "vendor_store_id", "buyerStoreId", "vendorStoreId"}
+
+type webhookLister interface {
+	ListActiveByStores(ctx context.Context, storeIDs []uuid.UUID) ([]models.StoreWebhook, error)
+}
+
+type deliverer interface {
+	Deliver(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string, body []byte) error
+}
+
+type idempotencyChecker interface {
+	CheckAndMarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error)
+	Delete(ctx context.Context, consumer string, eventID uuid.UUID) error
+}
+
+// Payload is the JSON body POSTed to store webhooks.
+type Payload struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Consumer fans order events out to the webhooks registered by the stores involved.
+type Consumer struct {
+	repo        webhookLister
+	dispatcher  deliverer
+	idempotency idempotencyChecker
+	logg        *logger.Logger
+}
+
+// NewConsumer builds the store webhook consumer.
+func NewConsumer(repo webhookLister, dispatcher deliverer, manager idempotencyChecker, logg *logger.Logger) (*Consumer, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("store webhooks repository required")
+	}
+	if dispatcher == nil {
+		return nil, fmt.Errorf("webhook dispatcher required")
+	}
+	if manager == nil {
+		return nil, fmt.Errorf("idempotency manager required")
+	}
+	if logg == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	return &Consumer{
+		repo:        repo,
+		dispatcher:  dispatcher,
+		idempotency: manager,
+		logg:        logg,
+	}, nil
+}
+
+// Process queues a single envelope for every active webhook of the affected stores. Endpoint
+// failures are retried by the RetryWorker; a returned error means the caller should nack for redelivery.
+func (c *Consumer) Process(ctx context.Context, eventType enums.OutboxEventType, envelope outbox.PayloadEnvelope) error {
+	logCtx := c.logg.WithFields(ctx, map[string]any{
+		"event_id":   envelope.EventID,
+		"event_type": eventType,
+	})
+	if _, ok := deliveredEvents[eventType]; !ok {
+		return nil
+	}
+
+	eventID, err := uuid.Parse(envelope.EventID)
+	if err != nil {
+		c.logg.Error(logCtx, "invalid event id", err)
+		return nil
+	}
+	storeIDs, err := storeIDsFromPayload(envelope.Data)
+	if err != nil {
+		c.logg.Error(logCtx, "failed to parse payload", err)
+		return nil
+	}
+	if len(storeIDs) == 0 {
+		return nil
+	}
+
+	already, err := c.idempotency.CheckAndMarkProcessed(ctx, storeWebhookConsumer, eventID)
+	if err != nil {
+		c.logg.Error(logCtx, "idempotency check failed", err)
+		return err
+	}
+	if already {
+		c.logg.Info(logCtx, "event already processed")
+		return nil
+	}
+
+	webhooks, err := c.repo.ListActiveByStores(ctx, storeIDs)
+	if err != nil {
+		c.logg.Error(logCtx, "failed to load store webhooks", err)
+		_ = c.idempotency.Delete(ctx, storeWebhookConsumer, eventID)
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         eventID,
+		Type:       string(eventType),
+		OccurredAt: envelope.OccurredAt,
+		Data:       envelope.Data,
+	})
+	if err != nil {
+		c.logg.Error(logCtx, "failed to encode webhook payload", err)
+		return nil
+	}
+
+	for _, webhook := range webhooks {
+		if err := c.dispatcher.Deliver(ctx, webhook, eventID, string(eventType), body); err != nil {
+			// Deliveries are queued per (webhook, event), so a redelivery skips endpoints already queued.
+			c.logg.Error(logCtx, "failed to queue webhook delivery", err)
+			_ = c.idempotency.Delete(ctx, storeWebhookConsumer, eventID)
+			return err
+		}
+	}
+	return nil
+}
+
+func storeIDsFromPayload(data json.RawMessage) ([]uuid.UUID, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	seen := map[uuid.UUID]struct{}{}
+	var ids []uuid.UUID
+	for _, key := range storeIDKeys {
+		raw, ok := fields[key].(string)
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil || id == uuid.Nil {
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
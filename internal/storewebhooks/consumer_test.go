@@ -0,0 +1,105 @@
+package storewebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/outbox"
+	"github.com/google/uuid"
+)
+
+type fakeWebhookLister struct {
+	storeIDs []uuid.UUID
+	webhooks []models.StoreWebhook
+}
+
+func (f *fakeWebhookLister) ListActiveByStores(_ context.Context, storeIDs []uuid.UUID) ([]models.StoreWebhook, error) {
+	f.storeIDs = storeIDs
+	return f.webhooks, nil
+}
+
+type recordingDeliverer struct {
+	webhooks []uuid.UUID
+	bodies   [][]byte
+}
+
+func (r *recordingDeliverer) Deliver(_ context.Context, webhook models.StoreWebhook, _ uuid.UUID, _ string, body []byte) error {
+	r.webhooks = append(r.webhooks, webhook.ID)
+	r.bodies = append(r.bodies, body)
+	return nil
+}
+
+type memoryIdempotency struct {
+	seen map[uuid.UUID]bool
+}
+
+func (m *memoryIdempotency) CheckAndMarkProcessed(_ context.Context, _ string, eventID uuid.UUID) (bool, error) {
+	if m.seen[eventID] {
+		return true, nil
+	}
+	m.seen[eventID] = true
+	return false, nil
+}
+
+func (m *memoryIdempotency) Delete(_ context.Context, _ string, eventID uuid.UUID) error {
+	delete(m.seen, eventID)
+	return nil
+}
+
+func TestConsumerDeliversOrderEventsToInvolvedStores(t *testing.T) {
+	buyerID := uuid.New()
+	vendorID := uuid.New()
+	lister := &fakeWebhookLister{webhooks: []models.StoreWebhook{{ID: uuid.New(), StoreID: vendorID}, {ID: uuid.New(), StoreID: buyerID}}}
+	deliverer := &recordingDeliverer{}
+	consumer, err := NewConsumer(lister, deliverer, &memoryIdempotency{seen: map[uuid.UUID]bool{}}, testLogger())
+	if err != nil {
+		t.Fatalf("new consumer: %v", err)
+	}
+
+	data, _ := json.Marshal(map[string]any{
+		"order_id":        uuid.NewString(),
+		"buyer_store_id":  buyerID.String(),
+		"vendor_store_id": vendorID.String(),
+	})
+	eventID := uuid.New()
+	envelope := outbox.PayloadEnvelope{Version: 1, EventID: eventID.String(), OccurredAt: time.Now().UTC(), Data: data}
+
+	if err := consumer.Process(context.Background(), enums.EventOrderCanceled, envelope); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if len(lister.storeIDs) != 2 || lister.storeIDs[0] != buyerID || lister.storeIDs[1] != vendorID {
+		t.Fatalf("unexpected store lookup %v", lister.storeIDs)
+	}
+	if len(deliverer.webhooks) != 2 {
+		t.Fatalf("expected both webhooks delivered, got %d", len(deliverer.webhooks))
+	}
+	var payload Payload
+	if err := json.Unmarshal(deliverer.bodies[0], &payload); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if payload.ID != eventID || payload.Type != string(enums.EventOrderCanceled) {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	// Redelivery of the same event is skipped.
+	if err := consumer.Process(context.Background(), enums.EventOrderCanceled, envelope); err != nil {
+		t.Fatalf("process duplicate: %v", err)
+	}
+	if len(deliverer.webhooks) != 2 {
+		t.Fatalf("expected duplicate event to be skipped")
+	}
+
+	// Events outside the order lifecycle are ignored.
+	other := envelope
+	other.EventID = uuid.NewString()
+	if err := consumer.Process(context.Background(), enums.EventLicenseStatusChanged, other); err != nil {
+		t.Fatalf("process license event: %v", err)
+	}
+	if len(deliverer.webhooks) != 2 {
+		t.Fatalf("expected non-order event to be ignored")
+	}
+}
@@ -0,0 +1,161 @@
+package storewebhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex hmac>" computed by Sign.
+	SignatureHeader = "X-Packfinderz-Signature"
+	// EventTypeHeader names the outbox event being delivered.
+	EventTypeHeader = "X-Packfinderz-Event"
+	// EventIDHeader lets receivers dedupe retried deliveries.
+	EventIDHeader = "X-Packfinderz-Event-Id"
+
+	defaultDisableAfter = 10
+	defaultTimeout      = 10 * time.Second
+	maxErrorLength      = 512
+)
+
+// Sign returns the signature header value for body. Receivers recompute the HMAC-SHA256 of
+// "<timestamp>.<body>" with their secret and compare it against v1.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+type deliveryRepository interface {
+	RecordDelivery(ctx context.Context, delivery *models.StoreWebhookDelivery) error
+}
+
+// Dispatcher POSTs signed payloads to a single endpoint and records every attempt. It makes one
+// attempt per call; RetryWorker decides when to try again and when the delivery has failed.
+type Dispatcher struct {
+	repo   deliveryRepository
+	client *http.Client
+	logg   *logger.Logger
+	now    func() time.Time
+}
+
+// NewDispatcher builds a dispatcher. A nil client gets a 10s-timeout client that refuses to connect
+// to loopback, private, or link-local addresses.
+func NewDispatcher(repo deliveryRepository, client *http.Client, logg *logger.Logger) (*Dispatcher, error) {
+	if repo == nil {
+		return nil, errors.New("store webhooks repository required")
+	}
+	if logg == nil {
+		return nil, errors.New("logger required")
+	}
+	if client == nil {
+		client = newEgressClient(defaultTimeout)
+	}
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+		logg:   logg,
+		now:    time.Now,
+	}, nil
+}
+
+// AttemptResult is the outcome of a single POST to a webhook.
+type AttemptResult struct {
+	Succeeded  bool
+	StatusCode int
+	// Retryable reports whether a later attempt could succeed: transport errors, throttling and
+	// server errors are transient, other non-2xx answers are not.
+	Retryable bool
+	Err       error
+}
+
+// Attempt makes one signed POST and records it as the given attempt number. It does not touch the
+// webhook's failure streak; callers decide when the delivery as a whole has succeeded or failed.
+func (d *Dispatcher) Attempt(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string, body []byte, attempt int) AttemptResult {
+	status, err := d.post(ctx, webhook, eventID, eventType, body)
+	result := AttemptResult{
+		Succeeded:  err == nil && status >= 200 && status < 300,
+		StatusCode: status,
+		Retryable:  err != nil || retryableStatus(status),
+		Err:        err,
+	}
+	if err == nil && !result.Succeeded {
+		result.Err = fmt.Errorf("webhook responded %d", status)
+	}
+
+	delivery := &models.StoreWebhookDelivery{
+		WebhookID:   webhook.ID,
+		EventID:     eventID,
+		EventType:   eventType,
+		Attempt:     attempt,
+		Succeeded:   result.Succeeded,
+		AttemptedAt: d.now().UTC(),
+	}
+	if status != 0 {
+		delivery.StatusCode = &status
+	}
+	if err != nil {
+		msg := truncate(err.Error(), maxErrorLength)
+		delivery.Error = &msg
+	}
+	if recErr := d.repo.RecordDelivery(ctx, delivery); recErr != nil {
+		d.logg.Error(d.logContext(ctx, webhook, eventID, eventType), "failed to record webhook delivery", recErr)
+	}
+	return result
+}
+
+func (d *Dispatcher) logContext(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string) context.Context {
+	return d.logg.WithFields(ctx, map[string]any{
+		"webhook_id": webhook.ID.String(),
+		"store_id":   webhook.StoreID.String(),
+		"event_id":   eventID.String(),
+		"event_type": eventType,
+	})
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, eventType)
+	req.Header.Set(EventIDHeader, eventID.String())
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, d.now().Unix(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// retryableStatus treats throttling and server errors as transient; other 4xx responses mean
+// the receiver rejected the payload and retrying would not help.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
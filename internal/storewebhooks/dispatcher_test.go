@@ -0,0 +1,148 @@
+package storewebhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type fakeDeliveryRepo struct {
+	mu         sync.Mutex
+	deliveries []models.StoreWebhookDelivery
+}
+
+func (f *fakeDeliveryRepo) RecordDelivery(_ context.Context, delivery *models.StoreWebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, *delivery)
+	return nil
+}
+
+func testLogger() *logger.Logger {
+	return logger.New(logger.Options{
+		ServiceName: "store-webhooks-test",
+		Level:       logger.ParseLevel("debug"),
+		Output:      io.Discard,
+	})
+}
+
+func newTestDispatcher(t *testing.T, repo *fakeDeliveryRepo, client *http.Client, now time.Time) *Dispatcher {
+	t.Helper()
+	dispatcher, err := NewDispatcher(repo, client, testLogger())
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher
+}
+
+func TestDispatcherAttemptSendsSignedPayload(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+	eventID := uuid.New()
+	body := []byte(`{"id":"evt","type":"order_decided","data":{}}`)
+	secret := "whsec_0123456789abcdef"
+
+	var gotSignature, gotEvent, gotEventID, gotBody string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		gotBody = string(raw)
+		gotSignature = r.Header.Get(SignatureHeader)
+		gotEvent = r.Header.Get(EventTypeHeader)
+		gotEventID = r.Header.Get(EventIDHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &fakeDeliveryRepo{}
+	dispatcher := newTestDispatcher(t, repo, server.Client(), now)
+	webhook := models.StoreWebhook{ID: uuid.New(), StoreID: uuid.New(), URL: server.URL, Secret: secret}
+
+	result := dispatcher.Attempt(context.Background(), webhook, eventID, "order_decided", body, 1)
+	if !result.Succeeded || result.Err != nil || result.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if gotBody != string(body) {
+		t.Fatalf("unexpected body %q", gotBody)
+	}
+	if want := Sign(secret, now.Unix(), body); gotSignature != want {
+		t.Fatalf("signature mismatch: got %q want %q", gotSignature, want)
+	}
+	if !strings.HasPrefix(gotSignature, "t=1767225600,v1=") {
+		t.Fatalf("unexpected signature format %q", gotSignature)
+	}
+	if gotEvent != "order_decided" || gotEventID != eventID.String() {
+		t.Fatalf("unexpected event headers %q %q", gotEvent, gotEventID)
+	}
+	if len(repo.deliveries) != 1 || !repo.deliveries[0].Succeeded || *repo.deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("expected one successful delivery, got %+v", repo.deliveries)
+	}
+}
+
+func TestDispatcherAttemptClassifiesFailures(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{name: "server error", status: http.StatusInternalServerError, retryable: true},
+		{name: "bad gateway", status: http.StatusBadGateway, retryable: true},
+		{name: "throttled", status: http.StatusTooManyRequests, retryable: true},
+		{name: "request timeout", status: http.StatusRequestTimeout, retryable: true},
+		{name: "gone", status: http.StatusGone, retryable: false},
+		{name: "bad request", status: http.StatusBadRequest, retryable: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			repo := &fakeDeliveryRepo{}
+			dispatcher := newTestDispatcher(t, repo, server.Client(), time.Now())
+			webhook := models.StoreWebhook{ID: uuid.New(), URL: server.URL, Secret: "whsec_0123456789abcdef"}
+
+			result := dispatcher.Attempt(context.Background(), webhook, uuid.New(), "order_paid", []byte(`{}`), 3)
+			if result.Succeeded || result.Retryable != tc.retryable || result.StatusCode != tc.status || result.Err == nil {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			if len(repo.deliveries) != 1 {
+				t.Fatalf("expected the attempt recorded, got %d", len(repo.deliveries))
+			}
+			delivery := repo.deliveries[0]
+			if delivery.Attempt != 3 || delivery.Succeeded || delivery.StatusCode == nil || *delivery.StatusCode != tc.status {
+				t.Fatalf("unexpected delivery record %+v", delivery)
+			}
+		})
+	}
+}
+
+func TestDispatcherAttemptRetriesTransportErrors(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	client := server.Client()
+	server.Close()
+
+	repo := &fakeDeliveryRepo{}
+	dispatcher := newTestDispatcher(t, repo, client, time.Now())
+	webhook := models.StoreWebhook{ID: uuid.New(), URL: url, Secret: "whsec_0123456789abcdef"}
+
+	result := dispatcher.Attempt(context.Background(), webhook, uuid.New(), "order_paid", []byte(`{}`), 1)
+	if result.Succeeded || !result.Retryable || result.Err == nil || result.StatusCode != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].StatusCode != nil || repo.deliveries[0].Error == nil {
+		t.Fatalf("expected the transport error recorded, got %+v", repo.deliveries)
+	}
+}
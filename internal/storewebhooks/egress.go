@@ -0,0 +1,76 @@
+package storewebhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+// errBlockedAddress is returned when a webhook connection would reach a non-public address.
+var errBlockedAddress = errors.New("webhook endpoint resolves to a non-public address")
+
+// hostResolver is the slice of net.Resolver the endpoint check needs.
+type hostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// publicAddress reports whether ip is safe to deliver to: not loopback, RFC 1918 or unique-local,
+// link-local (which covers cloud metadata endpoints), unspecified, or multicast.
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsUnspecified() &&
+		!ip.IsMulticast()
+}
+
+// checkEndpointHost rejects a webhook host that is, or resolves to, a non-public address.
+func checkEndpointHost(ctx context.Context, resolver hostResolver, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicAddress(ip) {
+			return pkgerrors.New(pkgerrors.CodeValidation, "url must point to a public address")
+		}
+		return nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return pkgerrors.New(pkgerrors.CodeValidation, "url host could not be resolved")
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr.IP) {
+			return pkgerrors.New(pkgerrors.CodeValidation, "url must point to a public address")
+		}
+	}
+	return nil
+}
+
+// guardDial refuses connections to non-public addresses. It runs on the resolved address, so a
+// host that re-resolves to an internal address after registration is still blocked.
+func guardDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBlockedAddress, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicAddress(ip) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	return nil
+}
+
+// newEgressClient builds the HTTP client for webhook deliveries. It connects directly, without an
+// environment proxy, so guardDial sees the endpoint's own address.
+func newEgressClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: guardDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
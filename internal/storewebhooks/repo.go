@@ -0,0 +1,161 @@
+package storewebhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository persists store webhook endpoints, their queued deliveries and delivery attempts.
+type Repository interface {
+	Create(ctx context.Context, webhook *models.StoreWebhook) error
+	ListByStore(ctx context.Context, storeID uuid.UUID) ([]models.StoreWebhook, error)
+	CountByStore(ctx context.Context, storeID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, storeID, webhookID uuid.UUID) (bool, error)
+	ListActiveByStores(ctx context.Context, storeIDs []uuid.UUID) ([]models.StoreWebhook, error)
+	RecordDelivery(ctx context.Context, delivery *models.StoreWebhookDelivery) error
+	MarkSucceeded(ctx context.Context, webhookID uuid.UUID) error
+	MarkFailed(ctx context.Context, webhookID uuid.UUID, disableAfter int, now time.Time) error
+	FindByID(ctx context.Context, webhookID uuid.UUID) (*models.StoreWebhook, error)
+	EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now, leaseUntil time.Time) (bool, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository binds the store webhook repository to the database.
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, webhook *models.StoreWebhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+func (r *repository) ListByStore(ctx context.Context, storeID uuid.UUID) ([]models.StoreWebhook, error) {
+	var rows []models.StoreWebhook
+	err := r.db.WithContext(ctx).
+		Where("store_id = ?", storeID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *repository) CountByStore(ctx context.Context, storeID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.StoreWebhook{}).Where("store_id = ?", storeID).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) Delete(ctx context.Context, storeID, webhookID uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND store_id = ?", webhookID, storeID).
+		Delete(&models.StoreWebhook{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *repository) ListActiveByStores(ctx context.Context, storeIDs []uuid.UUID) ([]models.StoreWebhook, error) {
+	if len(storeIDs) == 0 {
+		return nil, nil
+	}
+	var rows []models.StoreWebhook
+	err := r.db.WithContext(ctx).
+		Where("store_id IN ? AND disabled_at IS NULL", storeIDs).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *repository) RecordDelivery(ctx context.Context, delivery *models.StoreWebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+func (r *repository) MarkSucceeded(ctx context.Context, webhookID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.StoreWebhook{}).
+		Where("id = ?", webhookID).
+		Updates(map[string]any{
+			"consecutive_failures": 0,
+			"updated_at":           gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
+
+// MarkFailed bumps the failure streak and disables the endpoint once it reaches disableAfter.
+func (r *repository) MarkFailed(ctx context.Context, webhookID uuid.UUID, disableAfter int, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.StoreWebhook{}).
+		Where("id = ?", webhookID).
+		Updates(map[string]any{
+			"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+			"disabled_at":          gorm.Expr("CASE WHEN consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END", disableAfter, now),
+			"updated_at":           now,
+		}).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, webhookID uuid.UUID) (*models.StoreWebhook, error) {
+	var webhook models.StoreWebhook
+	if err := r.db.WithContext(ctx).Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// EnqueueDelivery stores a pending delivery. Queuing the same event for a webhook twice is a no-op,
+// so redelivered messages do not send duplicates.
+func (r *repository) EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(delivery).Error
+}
+
+// ListDueDeliveries returns pending deliveries whose next attempt is due, oldest first.
+func (r *repository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var rows []models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", enums.WebhookDeliveryStatusPending, now).
+		Order("next_attempt_at ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+// ClaimDelivery pushes a due delivery's next attempt out to leaseUntil. Only one poller wins the
+// update, and a poller that dies mid-attempt leaves the delivery to be retried once the lease ends.
+func (r *repository) ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", deliveryID, enums.WebhookDeliveryStatusPending, now).
+		Updates(map[string]any{
+			"next_attempt_at": leaseUntil,
+			"updated_at":      now,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+// SaveDeliveryAttempt stores the outcome of the latest attempt on a queued delivery.
+func (r *repository) SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_attempt_at":  delivery.LastAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+			"delivered_at":     delivery.DeliveredAt,
+			"updated_at":       delivery.UpdatedAt,
+		}).Error
+}
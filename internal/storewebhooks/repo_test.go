@@ -0,0 +1,82 @@
+package storewebhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupWebhookTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE store_webhooks (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  consecutive_failures INTEGER NOT NULL DEFAULT 0,
+  disabled_at DATETIME,
+  created_at DATETIME,
+  updated_at DATETIME
+);`).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	return db
+}
+
+func TestRepositoryMarkFailedDisablesAfterThreshold(t *testing.T) {
+	db := setupWebhookTestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	storeID := uuid.New()
+	now := time.Now().UTC()
+
+	webhook := &models.StoreWebhook{ID: uuid.New(), StoreID: storeID, URL: "https://example.com/hooks", Secret: "whsec_0123456789abcdef", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, webhook); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.MarkFailed(ctx, webhook.ID, 3, now); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+	}
+	active, err := repo.ListActiveByStores(ctx, []uuid.UUID{storeID})
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	if len(active) != 1 || active[0].ConsecutiveFailures != 2 {
+		t.Fatalf("expected the webhook active with 2 failures, got %+v", active)
+	}
+
+	if err := repo.MarkSucceeded(ctx, webhook.ID); err != nil {
+		t.Fatalf("mark succeeded: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := repo.MarkFailed(ctx, webhook.ID, 3, now); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+	}
+	active, err = repo.ListActiveByStores(ctx, []uuid.UUID{storeID})
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected the webhook disabled after 3 consecutive failures")
+	}
+	all, err := repo.ListByStore(ctx, storeID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 1 || all[0].DisabledAt == nil || all[0].ConsecutiveFailures != 3 {
+		t.Fatalf("unexpected webhook state %+v", all)
+	}
+}
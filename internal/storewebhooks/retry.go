@@ -0,0 +1,274 @@
+package storewebhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRetryMaxAttempts  = 8
+	defaultRetryBaseBackoff  = 30 * time.Second
+	defaultRetryMaxBackoff   = time.Hour
+	defaultRetryPollInterval = 5 * time.Second
+	defaultRetryBatchSize    = 50
+	// defaultRetryLease must outlast a POST (defaultTimeout) so a live poller is not overtaken.
+	defaultRetryLease = time.Minute
+)
+
+type deliveryEnqueuer interface {
+	EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// Queue persists deliveries for the RetryWorker instead of sending them inline, so an event that
+// has not reached its endpoint yet survives a restart.
+type Queue struct {
+	repo deliveryEnqueuer
+	now  func() time.Time
+}
+
+// NewQueue builds the delivery queue used by the consumer.
+func NewQueue(repo deliveryEnqueuer) (*Queue, error) {
+	if repo == nil {
+		return nil, errors.New("store webhooks repository required")
+	}
+	return &Queue{repo: repo, now: time.Now}, nil
+}
+
+// Deliver queues body for the webhook, due immediately.
+func (q *Queue) Deliver(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string, body []byte) error {
+	now := q.now().UTC()
+	return q.repo.EnqueueDelivery(ctx, &models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       body,
+		Status:        enums.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+type retryRepository interface {
+	FindByID(ctx context.Context, webhookID uuid.UUID) (*models.StoreWebhook, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now, leaseUntil time.Time) (bool, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	MarkSucceeded(ctx context.Context, webhookID uuid.UUID) error
+	MarkFailed(ctx context.Context, webhookID uuid.UUID, disableAfter int, now time.Time) error
+}
+
+type attempter interface {
+	Attempt(ctx context.Context, webhook models.StoreWebhook, eventID uuid.UUID, eventType string, body []byte, attempt int) AttemptResult
+}
+
+// RetryWorkerOptions tunes the delivery poller. Zero values fall back to the defaults.
+type RetryWorkerOptions struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	DisableAfter int
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+// RetryWorker polls queued deliveries and sends each due one, rescheduling transient failures with
+// exponential backoff until it succeeds or the attempts run out.
+type RetryWorker struct {
+	repo         retryRepository
+	dispatcher   attempter
+	logg         *logger.Logger
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	disableAfter int
+	pollInterval time.Duration
+	batchSize    int
+	lease        time.Duration
+	now          func() time.Time
+}
+
+// NewRetryWorker builds the delivery poller.
+func NewRetryWorker(repo retryRepository, dispatcher attempter, logg *logger.Logger, opts RetryWorkerOptions) (*RetryWorker, error) {
+	if repo == nil {
+		return nil, errors.New("store webhooks repository required")
+	}
+	if dispatcher == nil {
+		return nil, errors.New("webhook dispatcher required")
+	}
+	if logg == nil {
+		return nil, errors.New("logger required")
+	}
+	w := &RetryWorker{
+		repo:         repo,
+		dispatcher:   dispatcher,
+		logg:         logg,
+		maxAttempts:  opts.MaxAttempts,
+		baseBackoff:  opts.BaseBackoff,
+		maxBackoff:   opts.MaxBackoff,
+		disableAfter: opts.DisableAfter,
+		pollInterval: opts.PollInterval,
+		batchSize:    opts.BatchSize,
+		lease:        opts.Lease,
+		now:          time.Now,
+	}
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = defaultRetryMaxAttempts
+	}
+	if w.baseBackoff <= 0 {
+		w.baseBackoff = defaultRetryBaseBackoff
+	}
+	if w.maxBackoff <= 0 {
+		w.maxBackoff = defaultRetryMaxBackoff
+	}
+	if w.disableAfter <= 0 {
+		w.disableAfter = defaultDisableAfter
+	}
+	if w.pollInterval <= 0 {
+		w.pollInterval = defaultRetryPollInterval
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultRetryBatchSize
+	}
+	if w.lease <= 0 {
+		w.lease = defaultRetryLease
+	}
+	return w, nil
+}
+
+// Run polls until ctx is canceled. Poll failures are logged and retried on the next tick.
+func (w *RetryWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+			w.logg.Error(ctx, "store webhook retry poll failed", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue sends one batch of due deliveries and returns how many it attempted.
+func (w *RetryWorker) ProcessDue(ctx context.Context) (int, error) {
+	now := w.now().UTC()
+	due, err := w.repo.ListDueDeliveries(ctx, now, w.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	attempted := 0
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return attempted, err
+		}
+		// Earlier attempts in the batch can take up to the HTTP timeout each, so the lease starts
+		// from when this delivery is claimed rather than when the batch was listed.
+		claimedAt := w.now().UTC()
+		claimed, err := w.repo.ClaimDelivery(ctx, due[i].ID, claimedAt, claimedAt.Add(w.lease))
+		if err != nil {
+			return attempted, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := w.attempt(ctx, &due[i]); err != nil {
+			return attempted, err
+		}
+		attempted++
+	}
+	return attempted, nil
+}
+
+func (w *RetryWorker) attempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	webhook, err := w.repo.FindByID(ctx, delivery.WebhookID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if webhook == nil || webhook.DisabledAt != nil {
+		now := w.now().UTC()
+		msg := "webhook disabled"
+		delivery.Status = enums.WebhookDeliveryStatusFailed
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = now
+		delivery.UpdatedAt = now
+		return w.repo.SaveDeliveryAttempt(ctx, delivery)
+	}
+
+	delivery.Attempts++
+	result := w.dispatcher.Attempt(ctx, *webhook, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Attempts)
+	if err := ctx.Err(); err != nil {
+		// Shutting down mid-request; the lease lapses and the attempt is repeated.
+		return err
+	}
+	now := w.now().UTC()
+	delivery.LastAttemptAt = &now
+	delivery.UpdatedAt = now
+	delivery.LastStatusCode = nil
+	if result.StatusCode != 0 {
+		status := result.StatusCode
+		delivery.LastStatusCode = &status
+	}
+	delivery.LastError = nil
+	if result.Err != nil {
+		msg := truncate(result.Err.Error(), maxErrorLength)
+		delivery.LastError = &msg
+	}
+
+	switch {
+	case result.Succeeded:
+		delivery.Status = enums.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = now
+	case result.Retryable && delivery.Attempts < w.maxAttempts:
+		delivery.NextAttemptAt = now.Add(w.backoff(delivery.Attempts))
+	default:
+		delivery.Status = enums.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = now
+	}
+	if err := w.repo.SaveDeliveryAttempt(ctx, delivery); err != nil {
+		return err
+	}
+
+	switch delivery.Status {
+	case enums.WebhookDeliveryStatusDelivered:
+		return w.repo.MarkSucceeded(ctx, webhook.ID)
+	case enums.WebhookDeliveryStatusFailed:
+		w.logg.Warn(w.logContext(ctx, delivery), fmt.Sprintf("webhook delivery failed after %d attempts: %v", delivery.Attempts, result.Err))
+		return w.repo.MarkFailed(ctx, webhook.ID, w.disableAfter, now)
+	}
+	return nil
+}
+
+// backoff doubles the wait after each failed attempt, starting at the base and capped at the max.
+func (w *RetryWorker) backoff(attempts int) time.Duration {
+	wait := w.baseBackoff
+	for i := 1; i < attempts && wait < w.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > w.maxBackoff {
+		wait = w.maxBackoff
+	}
+	return wait
+}
+
+func (w *RetryWorker) logContext(ctx context.Context, delivery *models.WebhookDelivery) context.Context {
+	return w.logg.WithFields(ctx, map[string]any{
+		"webhook_id":  delivery.WebhookID.String(),
+		"delivery_id": delivery.ID.String(),
+		"event_id":    delivery.EventID.String(),
+		"event_type":  delivery.EventType,
+	})
+}
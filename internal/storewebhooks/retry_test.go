@@ -0,0 +1,265 @@
+package storewebhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func setupDeliveryQueueTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupWebhookTestDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	// Every connection to :memory: is its own database.
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.Exec(`CREATE TABLE webhook_deliveries (
+  id TEXT PRIMARY KEY,
+  webhook_id TEXT NOT NULL,
+  event_id TEXT NOT NULL,
+  event_type TEXT NOT NULL,
+  payload BLOB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at DATETIME NOT NULL,
+  last_attempt_at DATETIME,
+  last_status_code INTEGER,
+  last_error TEXT,
+  delivered_at DATETIME,
+  created_at DATETIME,
+  updated_at DATETIME,
+  UNIQUE (webhook_id, event_id)
+);`).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	return db
+}
+
+func createTestWebhook(t *testing.T, repo Repository, url string, now time.Time) *models.StoreWebhook {
+	t.Helper()
+	webhook := &models.StoreWebhook{ID: uuid.New(), StoreID: uuid.New(), URL: url, Secret: "whsec_0123456789abcdef", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(context.Background(), webhook); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	return webhook
+}
+
+func newTestRetryWorker(t *testing.T, repo Repository, client *http.Client, opts RetryWorkerOptions) *RetryWorker {
+	t.Helper()
+	dispatcher, err := NewDispatcher(&fakeDeliveryRepo{}, client, testLogger())
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+	worker, err := NewRetryWorker(repo, dispatcher, testLogger(), opts)
+	if err != nil {
+		t.Fatalf("new retry worker: %v", err)
+	}
+	return worker
+}
+
+func loadDelivery(t *testing.T, db *gorm.DB, webhookID uuid.UUID) models.WebhookDelivery {
+	t.Helper()
+	var deliveries []models.WebhookDelivery
+	if err := db.Where("webhook_id = ?", webhookID).Find(&deliveries).Error; err != nil {
+		t.Fatalf("load deliveries: %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("expected one queued delivery, got %d", len(deliveries))
+	}
+	return deliveries[0]
+}
+
+func TestRetryWorkerDeliversQueuedEventsAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1767225600, 0).UTC()
+	var hits int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	db := setupDeliveryQueueTestDB(t)
+	repo := NewRepository(db)
+	webhook := createTestWebhook(t, repo, server.URL, now)
+
+	queue, err := NewQueue(repo)
+	if err != nil {
+		t.Fatalf("new queue: %v", err)
+	}
+	queue.now = func() time.Time { return now }
+	eventID := uuid.New()
+	for i := 0; i < 2; i++ {
+		if err := queue.Deliver(ctx, *webhook, eventID, "order_decided", []byte(`{"id":"evt"}`)); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	queued := loadDelivery(t, db, webhook.ID)
+
+	// The first worker claims the delivery and dies before sending it.
+	claimed, err := repo.ClaimDelivery(ctx, queued.ID, now, now.Add(time.Minute))
+	if err != nil || !claimed {
+		t.Fatalf("claim: %v %v", claimed, err)
+	}
+
+	restarted := newTestRetryWorker(t, NewRepository(db), server.Client(), RetryWorkerOptions{Lease: time.Minute})
+	restarted.now = func() time.Time { return now.Add(30 * time.Second) }
+	if n, err := restarted.ProcessDue(ctx); err != nil || n != 0 {
+		t.Fatalf("expected the leased delivery to be skipped, got %d %v", n, err)
+	}
+
+	restarted.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if n, err := restarted.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one delivery after the lease lapsed, got %d %v", n, err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected one request to the endpoint, got %d", got)
+	}
+
+	delivered := loadDelivery(t, db, webhook.ID)
+	if delivered.Status != enums.WebhookDeliveryStatusDelivered || delivered.Attempts != 1 || delivered.DeliveredAt == nil {
+		t.Fatalf("unexpected delivery state %+v", delivered)
+	}
+	if delivered.LastStatusCode == nil || *delivered.LastStatusCode != http.StatusNoContent || string(delivered.Payload) != `{"id":"evt"}` {
+		t.Fatalf("unexpected delivery attempt %+v", delivered)
+	}
+	if n, err := restarted.ProcessDue(ctx); err != nil || n != 0 {
+		t.Fatalf("expected nothing left to deliver, got %d %v", n, err)
+	}
+}
+
+func TestRetryWorkerBacksOffUntilAttemptsExhausted(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1767225600, 0).UTC()
+	var hits int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	db := setupDeliveryQueueTestDB(t)
+	repo := NewRepository(db)
+	webhook := createTestWebhook(t, repo, server.URL, now)
+	if err := repo.EnqueueDelivery(ctx, &models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		EventID:       uuid.New(),
+		EventType:     "order_decided",
+		Payload:       []byte(`{}`),
+		Status:        enums.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+	}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	worker := newTestRetryWorker(t, repo, server.Client(), RetryWorkerOptions{
+		MaxAttempts:  4,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   100 * time.Second,
+		DisableAfter: 1,
+	})
+	clock := now
+	worker.now = func() time.Time { return clock }
+
+	for i, want := range []time.Duration{30 * time.Second, 60 * time.Second, 100 * time.Second} {
+		if n, err := worker.ProcessDue(ctx); err != nil || n != 1 {
+			t.Fatalf("attempt %d: expected one delivery, got %d %v", i+1, n, err)
+		}
+		delivery := loadDelivery(t, db, webhook.ID)
+		if delivery.Status != enums.WebhookDeliveryStatusPending || delivery.Attempts != i+1 {
+			t.Fatalf("attempt %d: unexpected state %+v", i+1, delivery)
+		}
+		if got := delivery.NextAttemptAt.Sub(clock); got != want {
+			t.Fatalf("attempt %d: expected backoff %v, got %v", i+1, want, got)
+		}
+		if delivery.LastError == nil || delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusInternalServerError {
+			t.Fatalf("attempt %d: expected the failure recorded, got %+v", i+1, delivery)
+		}
+
+		clock = clock.Add(want - time.Second)
+		if n, err := worker.ProcessDue(ctx); err != nil || n != 0 {
+			t.Fatalf("attempt %d: expected nothing due before the backoff, got %d %v", i+1, n, err)
+		}
+		clock = clock.Add(time.Second)
+	}
+
+	if n, err := worker.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("final attempt: expected one delivery, got %d %v", n, err)
+	}
+	delivery := loadDelivery(t, db, webhook.ID)
+	if delivery.Status != enums.WebhookDeliveryStatusFailed || delivery.Attempts != 4 {
+		t.Fatalf("expected the delivery failed after 4 attempts, got %+v", delivery)
+	}
+	if got := atomic.LoadInt32(&hits); got != 4 {
+		t.Fatalf("expected 4 requests, got %d", got)
+	}
+	stored, err := repo.FindByID(ctx, webhook.ID)
+	if err != nil {
+		t.Fatalf("find webhook: %v", err)
+	}
+	if stored.DisabledAt == nil || stored.ConsecutiveFailures != 1 {
+		t.Fatalf("expected the webhook disabled after the failed delivery, got %+v", stored)
+	}
+	if n, err := worker.ProcessDue(ctx); err != nil || n != 0 {
+		t.Fatalf("expected the failed delivery not to be retried, got %d %v", n, err)
+	}
+}
+
+type claimRecordingRepo struct {
+	Repository
+	claims []time.Time
+}
+
+func (r *claimRecordingRepo) ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	r.claims = append(r.claims, now)
+	return r.Repository.ClaimDelivery(ctx, deliveryID, now, leaseUntil)
+}
+
+func TestRetryWorkerLeasesEachDeliveryFromItsClaim(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1767225600, 0).UTC()
+	clock := now
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// A slow endpoint eats into the batch.
+		clock = clock.Add(45 * time.Second)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	db := setupDeliveryQueueTestDB(t)
+	repo := &claimRecordingRepo{Repository: NewRepository(db)}
+	for i := 0; i < 2; i++ {
+		webhook := createTestWebhook(t, repo, server.URL, now)
+		if err := repo.EnqueueDelivery(ctx, &models.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			EventID:       uuid.New(),
+			EventType:     "order_decided",
+			Payload:       []byte(`{}`),
+			Status:        enums.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+		}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+
+	worker := newTestRetryWorker(t, repo, server.Client(), RetryWorkerOptions{Lease: time.Minute})
+	worker.now = func() time.Time { return clock }
+	if n, err := worker.ProcessDue(ctx); err != nil || n != 2 {
+		t.Fatalf("expected two deliveries, got %d %v", n, err)
+	}
+	if len(repo.claims) != 2 || !repo.claims[0].Equal(now) || !repo.claims[1].Equal(now.Add(45*time.Second)) {
+		t.Fatalf("expected each claim to use the time it was made, got %v", repo.claims)
+	}
+}
@@ -0,0 +1,155 @@
+package storewebhooks
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	maxWebhooksPerStore = 5
+	minSecretLength     = 16
+	maxSecretLength     = 256
+	maxURLLength        = 2048
+)
+
+// Service manages the webhook endpoints a store registers for order events.
+type Service interface {
+	Register(ctx context.Context, storeID uuid.UUID, input RegisterInput) (*Webhook, error)
+	List(ctx context.Context, storeID uuid.UUID) ([]Webhook, error)
+	Delete(ctx context.Context, storeID, webhookID uuid.UUID) error
+}
+
+// RegisterInput carries the endpoint and the shared secret used to sign deliveries.
+type RegisterInput struct {
+	URL    string
+	Secret string
+}
+
+// Webhook is the API view of a registered endpoint. The secret is never echoed back.
+type Webhook struct {
+	ID                  uuid.UUID  `json:"id"`
+	URL                 string     `json:"url"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type service struct {
+	repo     Repository
+	resolver hostResolver
+}
+
+// NewService wires the store webhook service.
+func NewService(repo Repository) (Service, error) {
+	if repo == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeDependency, "store webhooks repository required")
+	}
+	return &service{repo: repo, resolver: net.DefaultResolver}, nil
+}
+
+func (s *service) Register(ctx context.Context, storeID uuid.UUID, input RegisterInput) (*Webhook, error) {
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	endpoint, err := normalizeEndpoint(ctx, s.resolver, input.URL)
+	if err != nil {
+		return nil, err
+	}
+	secret := strings.TrimSpace(input.Secret)
+	if len(secret) < minSecretLength || len(secret) > maxSecretLength {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "secret must be between 16 and 256 characters")
+	}
+
+	count, err := s.repo.CountByStore(ctx, storeID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "count store webhooks")
+	}
+	if count >= maxWebhooksPerStore {
+		return nil, pkgerrors.New(pkgerrors.CodeConflict, "store webhook limit reached")
+	}
+
+	record := &models.StoreWebhook{
+		StoreID: storeID,
+		URL:     endpoint,
+		Secret:  secret,
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create store webhook")
+	}
+	view := webhookFromModel(*record)
+	return &view, nil
+}
+
+func (s *service) List(ctx context.Context, storeID uuid.UUID) ([]Webhook, error) {
+	if storeID == uuid.Nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	rows, err := s.repo.ListByStore(ctx, storeID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "list store webhooks")
+	}
+	views := make([]Webhook, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, webhookFromModel(row))
+	}
+	return views, nil
+}
+
+func (s *service) Delete(ctx context.Context, storeID, webhookID uuid.UUID) error {
+	if storeID == uuid.Nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "active store id required")
+	}
+	deleted, err := s.repo.Delete(ctx, storeID, webhookID)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "delete store webhook")
+	}
+	if !deleted {
+		return pkgerrors.New(pkgerrors.CodeNotFound, "webhook not found")
+	}
+	return nil
+}
+
+// normalizeEndpoint only accepts absolute https URLs so signed payloads never travel in clear text,
+// and only hosts that resolve to public addresses so a store cannot aim deliveries at our network.
+func normalizeEndpoint(ctx context.Context, resolver hostResolver, raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", pkgerrors.New(pkgerrors.CodeValidation, "url is required")
+	}
+	if len(trimmed) > maxURLLength {
+		return "", pkgerrors.New(pkgerrors.CodeValidation, "url is too long")
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return "", pkgerrors.Wrap(pkgerrors.CodeValidation, err, "invalid url")
+	}
+	if !strings.EqualFold(parsed.Scheme, "https") || parsed.Host == "" {
+		return "", pkgerrors.New(pkgerrors.CodeValidation, "url must be an absolute https url")
+	}
+	if parsed.User != nil {
+		return "", pkgerrors.New(pkgerrors.CodeValidation, "url must not embed credentials")
+	}
+	if err := checkEndpointHost(ctx, resolver, parsed.Hostname()); err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func webhookFromModel(row models.StoreWebhook) Webhook {
+	return Webhook{
+		ID:                  row.ID,
+		URL:                 row.URL,
+		Active:              row.DisabledAt == nil,
+		ConsecutiveFailures: row.ConsecutiveFailures,
+		DisabledAt:          row.DisabledAt,
+		CreatedAt:           row.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package subscriptions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+type couponRepository interface {
+	FindByCode(ctx context.Context, code string) (*models.SubscriptionCoupon, error)
+	RedeemWithTx(ctx context.Context, tx *gorm.DB, redemption *models.SubscriptionCouponRedemption, now time.Time) (bool, error)
+}
+
+// couponPricing is the discounted recurring price a coupon yields for a plan.
+type couponPricing struct {
+	coupon        *models.SubscriptionCoupon
+	priceCents    int64
+	discountCents int64
+	currency      string
+}
+
+// resolveCoupon validates the code and prices it against the plan behind priceID. It returns nil
+// when no code was supplied.
+func (s *service) resolveCoupon(ctx context.Context, code, priceID string, now time.Time) (*couponPricing, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, nil
+	}
+	if s.coupons == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupons are not available")
+	}
+	coupon, err := s.coupons.FindByCode(ctx, code)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load coupon")
+	}
+	details := map[string]any{"coupon_code": code}
+	switch {
+	case coupon == nil:
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon not found").WithDetails(details)
+	case !coupon.Active:
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon is not active").WithDetails(details)
+	case coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt):
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon has expired").WithDetails(details)
+	case coupon.MaxRedemptions != nil && coupon.RedemptionCount >= *coupon.MaxRedemptions:
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon redemption limit reached").WithDetails(details)
+	}
+
+	plan, err := s.billingRepo.FindBillingPlanBySquareID(ctx, priceID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load billing plan")
+	}
+	if plan == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon requires a known billing plan").WithDetails(details)
+	}
+	priceCents := plan.PriceAmount.Shift(2).Round(0).IntPart()
+
+	var discount int64
+	switch {
+	case coupon.PercentOff != nil:
+		discount = priceCents * int64(*coupon.PercentOff) / 100
+	case coupon.AmountOffCents != nil:
+		discount = *coupon.AmountOffCents
+	}
+	if discount <= 0 || discount >= priceCents {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "coupon does not apply to this plan").WithDetails(details)
+	}
+	return &couponPricing{
+		coupon:        coupon,
+		priceCents:    priceCents - discount,
+		discountCents: discount,
+		currency:      plan.CurrencyCode,
+	}, nil
+}
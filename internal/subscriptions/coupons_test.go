@@ -0,0 +1,131 @@
+package subscriptions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
+)
+
+type stubCouponRepo struct {
+	coupon      *models.SubscriptionCoupon
+	redemptions []*models.SubscriptionCouponRedemption
+}
+
+func (s *stubCouponRepo) FindByCode(ctx context.Context, code string) (*models.SubscriptionCoupon, error) {
+	if s.coupon != nil && strings.EqualFold(s.coupon.Code, code) {
+		return s.coupon, nil
+	}
+	return nil, nil
+}
+
+func (s *stubCouponRepo) RedeemWithTx(ctx context.Context, tx *gorm.DB, redemption *models.SubscriptionCouponRedemption, now time.Time) (bool, error) {
+	if s.coupon.MaxRedemptions != nil && s.coupon.RedemptionCount >= *s.coupon.MaxRedemptions {
+		return false, nil
+	}
+	s.coupon.RedemptionCount++
+	s.redemptions = append(s.redemptions, redemption)
+	return true, nil
+}
+
+func newCouponTestService(t *testing.T, coupon *models.SubscriptionCoupon) (Service, *stubSquareSubscriptionClient, *stubCouponRepo) {
+	t.Helper()
+	squareClient := &stubSquareSubscriptionClient{
+		createResp: &SquareSubscription{ID: "sub-new", Status: "ACTIVE"},
+		getResp: &SquareSubscription{
+			ID:                 "sub-new",
+			Status:             "ACTIVE",
+			ChargedThroughDate: time.Now().Add(30 * 24 * time.Hour).Unix(),
+		},
+	}
+	coupons := &stubCouponRepo{coupon: coupon}
+	svc, err := NewService(ServiceParams{
+		BillingRepo: &stubBillingRepo{plan: &models.BillingPlan{
+			SquareBillingPlanID: "price-123",
+			PriceAmount:         decimal.RequireFromString("49.99"),
+			CurrencyCode:        "USD",
+		}},
+		StoreRepo:         &stubStoreRepo{store: &models.Store{}},
+		SquareClient:      squareClient,
+		TransactionRunner: &stubTxRunner{},
+		CouponRepo:        coupons,
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+	return svc, squareClient, coupons
+}
+
+func couponInput(code string) CreateSubscriptionInput {
+	return CreateSubscriptionInput{
+		SquareCustomerID:      "cust-1",
+		SquarePaymentMethodID: "pm-1",
+		PriceID:               "price-123",
+		CouponCode:            code,
+	}
+}
+
+func TestServiceCreateAppliesCoupon(t *testing.T) {
+	maxRedemptions := 10
+	percentOff := 20
+	coupon := &models.SubscriptionCoupon{ID: uuid.New(), Code: "LAUNCH20", PercentOff: &percentOff, Active: true, MaxRedemptions: &maxRedemptions}
+	svc, squareClient, coupons := newCouponTestService(t, coupon)
+
+	_, created, err := svc.Create(context.Background(), uuid.New(), couponInput("launch20"))
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !created {
+		t.Fatalf("expected subscription created")
+	}
+	params := squareClient.lastCreateParams
+	if params == nil || params.PriceOverrideCents != 4000 || params.PriceOverrideCurrency != "USD" {
+		t.Fatalf("expected discounted price 4000 USD sent to square, got %+v", params)
+	}
+	if len(coupons.redemptions) != 1 || coupons.redemptions[0].DiscountCents != 999 {
+		t.Fatalf("expected one redemption of 999 cents, got %+v", coupons.redemptions)
+	}
+	if coupon.RedemptionCount != 1 {
+		t.Fatalf("expected redemption count 1, got %d", coupon.RedemptionCount)
+	}
+}
+
+func TestServiceCreateRejectsExhaustedCoupon(t *testing.T) {
+	maxRedemptions := 5
+	amountOff := int64(1000)
+	coupon := &models.SubscriptionCoupon{ID: uuid.New(), Code: "FIVEONLY", AmountOffCents: &amountOff, Active: true, MaxRedemptions: &maxRedemptions, RedemptionCount: 5}
+	svc, squareClient, coupons := newCouponTestService(t, coupon)
+
+	_, _, err := svc.Create(context.Background(), uuid.New(), couponInput("FIVEONLY"))
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation || typed.Message() != "coupon redemption limit reached" {
+		t.Fatalf("expected redemption limit error, got %v", err)
+	}
+	if squareClient.calledCreate {
+		t.Fatalf("square subscription should not be created for an exhausted coupon")
+	}
+	if len(coupons.redemptions) != 0 {
+		t.Fatalf("expected no redemption recorded")
+	}
+}
+
+func TestServiceCreateRejectsExpiredCoupon(t *testing.T) {
+	percentOff := 50
+	expired := time.Now().Add(-time.Hour)
+	coupon := &models.SubscriptionCoupon{ID: uuid.New(), Code: "OLDPROMO", PercentOff: &percentOff, Active: true, ExpiresAt: &expired}
+	svc, squareClient, _ := newCouponTestService(t, coupon)
+
+	_, _, err := svc.Create(context.Background(), uuid.New(), couponInput("OLDPROMO"))
+	if typed := pkgerrors.As(err); typed == nil || typed.Code() != pkgerrors.CodeValidation || typed.Message() != "coupon has expired" {
+		t.Fatalf("expected expired coupon error, got %v", err)
+	}
+	if squareClient.calledCreate {
+		t.Fatalf("square subscription should not be created for an expired coupon")
+	}
+}
@@ -0,0 +1,101 @@
+package subscriptions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+)
+
+// EntitlementChange reports what ApplyStoreEntitlement did to the store.
+type EntitlementChange struct {
+	// Changed is set when the store needs to be saved.
+	Changed bool
+	// GraceStarted is set when a lapse opened a new grace window; the vendor should be warned.
+	GraceStarted bool
+}
+
+// ApplyStoreEntitlement moves the store's subscription flag toward entitled. A lapse does not
+// revoke an active store right away: it stays active until now+grace, and only a lapse observed
+// after subscription_grace_until flips it off. A renewal clears any running grace window.
+func ApplyStoreEntitlement(store *models.Store, entitled bool, now time.Time, grace time.Duration) EntitlementChange {
+	if store == nil {
+		return EntitlementChange{}
+	}
+	if entitled {
+		change := EntitlementChange{Changed: !store.SubscriptionActive || store.SubscriptionGraceUntil != nil}
+		store.SubscriptionActive = true
+		store.SubscriptionGraceUntil = nil
+		return change
+	}
+	if !store.SubscriptionActive {
+		change := EntitlementChange{Changed: store.SubscriptionGraceUntil != nil}
+		store.SubscriptionGraceUntil = nil
+		return change
+	}
+	if store.SubscriptionGraceUntil == nil && grace > 0 {
+		until := now.Add(grace).UTC()
+		store.SubscriptionGraceUntil = &until
+		return EntitlementChange{Changed: true, GraceStarted: true}
+	}
+	if store.SubscriptionGraceUntil != nil && now.Before(*store.SubscriptionGraceUntil) {
+		return EntitlementChange{}
+	}
+	store.SubscriptionActive = false
+	store.SubscriptionGraceUntil = nil
+	return EntitlementChange{Changed: true}
+}
+
+// StoreEntitled reports whether the Square subscription still entitles its store at now. A scheduled
+// cancel or pause ends it once effective; otherwise it runs through the charged-through date (or the
+// stored period end), and only when neither is known does Square's ACTIVE status decide.
+func StoreEntitled(now time.Time, square *SquareSubscription, stored *models.Subscription) bool {
+	if square == nil {
+		return false
+	}
+	if cancel := pendingCancelAction(square.Actions); cancel != nil {
+		if t := toTimePtr(cancel.EffectiveDate); t != nil && !now.Before(*t) {
+			return false
+		}
+	}
+	if pause := pendingPauseAction(square.Actions); pause != nil {
+		if t := toTimePtr(pause.EffectiveDate); t != nil && !now.Before(*t) {
+			return false
+		}
+	}
+	entitledUntil := toTime(square.ChargedThroughDate)
+	if entitledUntil.IsZero() && stored != nil {
+		entitledUntil = stored.CurrentPeriodEnd
+	}
+	if entitledUntil.IsZero() {
+		return strings.EqualFold(square.Status, "ACTIVE")
+	}
+	return !now.After(entitledUntil)
+}
+
+// GraceWarningNotification warns a vendor that its lapsed subscription stops checkout at until.
+func GraceWarningNotification(storeID uuid.UUID, until time.Time) *models.Notification {
+	link := "/vendor/billing"
+	return &models.Notification{
+		StoreID: storeID,
+		Type:    enums.NotificationTypeSubscriptionAlert,
+		Title:   "Subscription payment failed",
+		Message: fmt.Sprintf("Your subscription has lapsed. Buyers can keep checking out with you until %s UTC; update your payment method to stay active.", until.UTC().Format("Jan 2, 2006 15:04")),
+		Link:    &link,
+	}
+}
+
+// CanceledNotification tells a vendor its subscription was canceled outside the app.
+func CanceledNotification(storeID uuid.UUID) *models.Notification {
+	link := "/vendor/billing"
+	return &models.Notification{
+		StoreID: storeID,
+		Type:    enums.NotificationTypeSubscriptionAlert,
+		Title:   "Subscription canceled",
+		Message: "Your subscription was canceled in Square. Buyers can no longer check out with your store once the period you paid for ends; subscribe again to stay active.",
+		Link:    &link,
+	}
+}
@@ -42,13 +42,19 @@ type ServiceParams struct {
 	StoreRepo         storeRepository
 	SquareClient      SquareSubscriptionClient
 	TransactionRunner txRunner
+	// CouponRepo enables CreateSubscriptionInput.CouponCode; without it coupon codes are rejected.
+	CouponRepo couponRepository
 }
 
 // CreateSubscriptionInput captures the data required to start a subscription.
+// IdempotencyKey is optional; a repeat call with the same key returns the subscription the first
+// call created instead of starting another one in Square.
 type CreateSubscriptionInput struct {
 	SquareCustomerID      string
 	SquarePaymentMethodID string
 	PriceID               string
+	IdempotencyKey        string
+	CouponCode            string
 }
 
 type service struct {
@@ -57,6 +63,8 @@ type service struct {
 	square      SquareSubscriptionClient
 	priceID     string
 	txRunner    txRunner
+	coupons     couponRepository
+	now         func() time.Time
 }
 
 // NewService builds a subscription service with the required dependencies.
@@ -78,48 +86,54 @@ func NewService(params ServiceParams) (Service, error) {
 		storeRepo:   params.StoreRepo,
 		square:      params.SquareClient,
 		txRunner:    params.TransactionRunner,
+		coupons:     params.CouponRepo,
+		now:         time.Now,
 	}, nil
 }
 
 // Create either returns the existing active subscription or creates a new one.
 func (s *service) Create(ctx context.Context, storeID uuid.UUID, input CreateSubscriptionInput) (*models.Subscription, bool, error) {
-	fmt.Printf("[subscriptions.Create] start storeID=%s\n", storeID)
-
 	if storeID == uuid.Nil {
-		fmt.Printf("[subscriptions.Create] FAIL storeID is nil\n")
 		return nil, false, pkgerrors.New(pkgerrors.CodeValidation, "store id is required")
 	}
 
 	customerID := strings.TrimSpace(input.SquareCustomerID)
-	fmt.Printf("[subscriptions.Create] customerID='%s'\n", customerID)
 	if customerID == "" {
-		fmt.Printf("[subscriptions.Create] FAIL missing customerID\n")
 		return nil, false, pkgerrors.New(pkgerrors.CodeValidation, "square_customer_id is required")
 	}
 
 	paymentMethodID := strings.TrimSpace(input.SquarePaymentMethodID)
-	fmt.Printf("[subscriptions.Create] paymentMethodID='%s'\n", paymentMethodID)
 	if paymentMethodID == "" {
-		fmt.Printf("[subscriptions.Create] FAIL missing paymentMethodID\n")
 		return nil, false, pkgerrors.New(pkgerrors.CodeValidation, "square_payment_method_id is required")
 	}
 
 	priceID := strings.TrimSpace(input.PriceID)
-	fmt.Printf("[subscriptions.Create] priceID='%s' (input=%t default=%t)\n", priceID, strings.TrimSpace(input.PriceID) != "", s.priceID != "")
 	if priceID == "" {
-		fmt.Printf("[subscriptions.Create] FAIL missing priceID\n")
 		return nil, false, pkgerrors.New(pkgerrors.CodeValidation, "price_id is required")
 	}
 
-	fmt.Printf("[subscriptions.Create] findActive (pre)\n")
+	idempotencyKey := strings.TrimSpace(input.IdempotencyKey)
+	if idempotencyKey != "" {
+		existing, err := s.billingRepo.FindSubscriptionByIdempotencyKey(ctx, storeID, idempotencyKey)
+		if err != nil {
+			return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "lookup subscription by idempotency key")
+		}
+		if existing != nil {
+			return existing, false, nil
+		}
+	}
+
 	if existing, err := s.findActive(ctx, storeID); err != nil {
-		fmt.Printf("[subscriptions.Create] FAIL findActive err=%T %v\n", err, err)
 		return nil, false, err
 	} else if existing != nil {
-		fmt.Printf("[subscriptions.Create] existing active subscription found id=%s status=%s\n", existing.ID, existing.Status)
 		return existing, false, nil
 	}
 
+	pricing, err := s.resolveCoupon(ctx, input.CouponCode, priceID, s.now())
+	if err != nil {
+		return nil, false, err
+	}
+
 	params := &SquareSubscriptionParams{
 		CustomerID:      customerID,
 		PriceID:         priceID,
@@ -127,31 +141,26 @@ func (s *service) Create(ctx context.Context, storeID uuid.UUID, input CreateSub
 		Metadata: map[string]string{
 			"store_id": storeID.String(),
 		},
+		IdempotencyKey: idempotencyKey,
+	}
+	if pricing != nil {
+		params.PriceOverrideCents = pricing.priceCents
+		params.PriceOverrideCurrency = pricing.currency
 	}
 
-	fmt.Printf("[subscriptions.Create] square params=%+v\n", params)
-	fmt.Printf("[subscriptions.Create] square.Create about to call Square\n")
 	squareSub, err := s.square.Create(ctx, params)
 	if err != nil {
-		fmt.Printf("[subscriptions.Create] FAIL square.Create err=%T %v\n", err, err)
-		// If Square SDK returns a structured error, this prints more detail:
-		debugSquareErr(err)
 		return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create square subscription")
 	}
-	fmt.Printf("[subscriptions.Create] square.Create OK squareSubID=%s status=%s\n", squareSub.ID, squareSub.Status)
-	fmt.Printf("[subscriptions.Create] square response=%+v\n", squareSub)
 	squareSubID := squareSub.ID
 	refreshParams := squareSubscriptionParams(storeID, priceID, true)
 	squareSub, err = s.square.Get(ctx, squareSubID, refreshParams)
 	if err != nil {
-		fmt.Printf("[subscriptions.Create] FAIL square.Get err=%T %v\n", err, err)
 		if cancelErr := s.cancelSquare(ctx, squareSubID); cancelErr != nil {
-			fmt.Printf("[subscriptions.Create] FAIL cancelSquare err=%T %v\n", cancelErr, cancelErr)
 			return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, cancelErr, "cancel square subscription after get failure")
 		}
 		return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "get square subscription")
 	}
-	fmt.Printf("[subscriptions.Create] square.Get refreshed response=%+v\n", squareSub)
 
 	var (
 		createdSub    *models.Subscription
@@ -159,41 +168,63 @@ func (s *service) Create(ctx context.Context, storeID uuid.UUID, input CreateSub
 		skipped       bool
 	)
 
-	fmt.Printf("[subscriptions.Create] begin tx persist\n")
 	err = s.txRunner.WithTx(ctx, func(tx *gorm.DB) error {
-		fmt.Printf("[subscriptions.Create.tx] start\n")
 		txRepo := s.billingRepo.WithTx(tx)
 
-		fmt.Printf("[subscriptions.Create.tx] findActiveWithTx\n")
+		if idempotencyKey != "" {
+			replay, err := txRepo.FindSubscriptionByIdempotencyKey(ctx, storeID, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			if replay != nil {
+				existingAfter = replay
+				skipped = true
+				return nil
+			}
+		}
+
 		active, err := s.findActiveWithTx(ctx, txRepo, storeID)
 		if err != nil {
-			fmt.Printf("[subscriptions.Create.tx] FAIL findActiveWithTx err=%T %v\n", err, err)
 			return err
 		}
 		if active != nil {
-			fmt.Printf("[subscriptions.Create.tx] active exists (race) subID=%s\n", active.ID)
 			existingAfter = active
 			skipped = true
 			return nil
 		}
 
-		fmt.Printf("[subscriptions.Create.tx] BuildSubscriptionFromSquare\n")
 		sub, err := BuildSubscriptionFromSquare(squareSub, storeID, priceID, customerID, paymentMethodID)
 		if err != nil {
-			fmt.Printf("[subscriptions.Create.tx] FAIL BuildSubscriptionFromSquare err=%T %v\n", err, err)
 			return err
 		}
+		if idempotencyKey != "" {
+			sub.IdempotencyKey = &idempotencyKey
+		}
 
-		fmt.Printf("[subscriptions.Create.tx] CreateSubscription\n")
 		if err := txRepo.CreateSubscription(ctx, sub); err != nil {
-			fmt.Printf("[subscriptions.Create.tx] FAIL CreateSubscription err=%T %v\n", err, err)
 			return err
 		}
 
-		fmt.Printf("[subscriptions.Create.tx] load store\n")
+		if pricing != nil {
+			redeemed, err := s.coupons.RedeemWithTx(ctx, tx, &models.SubscriptionCouponRedemption{
+				CouponID:       pricing.coupon.ID,
+				StoreID:        storeID,
+				SubscriptionID: &sub.ID,
+				DiscountCents:  pricing.discountCents,
+			}, s.now())
+			if err != nil {
+				return err
+			}
+			if !redeemed {
+				// Another store took the last redemption after our check; roll back and cancel in Square.
+				return pkgerrors.New(pkgerrors.CodeValidation, "coupon redemption limit reached").WithDetails(map[string]any{
+					"coupon_code": pricing.coupon.Code,
+				})
+			}
+		}
+
 		store, err := s.storeRepo.FindByIDWithTx(tx, storeID)
 		if err != nil {
-			fmt.Printf("[subscriptions.Create.tx] FAIL FindByIDWithTx err=%T %v\n", err, err)
 			if err == gorm.ErrRecordNotFound {
 				return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
 			}
@@ -201,39 +232,38 @@ func (s *service) Create(ctx context.Context, storeID uuid.UUID, input CreateSub
 		}
 
 		store.SubscriptionActive = IsActiveStatus(sub.Status)
-		fmt.Printf("[subscriptions.Create.tx] Update store subscription flag => %t\n", store.SubscriptionActive)
 		if err := s.storeRepo.UpdateSubscriptionActiveWithTx(tx, storeID, store.SubscriptionActive); err != nil {
-			fmt.Printf("[subscriptions.Create.tx] FAIL UpdateWithTx err=%T %v\n", err, err)
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update store subscription flag")
 		}
 
 		createdSub = sub
-		fmt.Printf("[subscriptions.Create.tx] OK\n")
 		return nil
 	})
 
 	if err != nil {
-		fmt.Printf("[subscriptions.Create] FAIL persist err=%T %v skipped=%t\n", err, err, skipped)
 		if !skipped {
-			fmt.Printf("[subscriptions.Create] cancelSquare due to db error squareSubID=%s\n", squareSub.ID)
 			if cancelErr := s.cancelSquare(ctx, squareSub.ID); cancelErr != nil {
-				fmt.Printf("[subscriptions.Create] FAIL cancelSquare err=%T %v\n", cancelErr, cancelErr)
 				return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, cancelErr, "cancel square subscription after db error")
 			}
 		}
+		if typed := pkgerrors.As(err); typed != nil && typed.Code() == pkgerrors.CodeValidation {
+			return nil, false, err
+		}
 		return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "persist subscription")
 	}
 
 	if skipped {
-		fmt.Printf("[subscriptions.Create] skipped due to race, cancelSquare squareSubID=%s\n", squareSub.ID)
+		// Square dedupes creates that share an idempotency key, so a concurrent retry may hand back
+		// the very subscription the winner persisted; that one must not be canceled.
+		if existingAfter != nil && existingAfter.SquareSubscriptionID == squareSub.ID {
+			return existingAfter, false, nil
+		}
 		if cancelErr := s.cancelSquare(ctx, squareSub.ID); cancelErr != nil {
-			fmt.Printf("[subscriptions.Create] FAIL cancelSquare(race) err=%T %v\n", cancelErr, cancelErr)
 			return nil, false, pkgerrors.Wrap(pkgerrors.CodeDependency, cancelErr, "cancel square subscription due to race")
 		}
 		return existingAfter, false, nil
 	}
 
-	fmt.Printf("[subscriptions.Create] DONE createdSubID=%s\n", createdSub.ID)
 	return createdSub, true, nil
 }
 
@@ -393,8 +423,6 @@ func (s *service) Resume(ctx context.Context, storeID uuid.UUID) error {
 		return pkgerrors.New(pkgerrors.CodeNotFound, "subscription not found")
 	}
 
-	fmt.Printf("[subscriptions.Resume] start storeID=%s squareID=%s status=%s\n", storeID, sub.SquareSubscriptionID, sub.Status)
-
 	live, err := s.square.Get(ctx, sub.SquareSubscriptionID, squareSubscriptionParams(storeID, resolvePriceID(sub.PriceID), true))
 	if err != nil {
 		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "get square subscription before resume")
@@ -419,8 +447,6 @@ func (s *service) Resume(ctx context.Context, storeID uuid.UUID) error {
 		return wrapResumeError(err, "resume square subscription")
 	}
 
-	fmt.Printf("[subscriptions.Resume] square resumed state=%+v\n", squareSub)
-
 	return s.persistSquareUpdate(ctx, storeID, squareSub, func(stored *models.Subscription) {
 		stored.PausedAt = nil
 	})
@@ -545,6 +571,18 @@ func pendingPauseAction(actions []*SquareSubscriptionAction) *SquareSubscription
 	return nil
 }
 
+func pendingCancelAction(actions []*SquareSubscriptionAction) *SquareSubscriptionAction {
+	for _, action := range actions {
+		if action == nil || action.ID == "" {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(action.Type), "CANCEL") {
+			return action
+		}
+	}
+	return nil
+}
+
 func wrapResumeError(err error, msg string) error {
 	if err == nil {
 		return nil
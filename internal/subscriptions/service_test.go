@@ -135,6 +135,128 @@ func TestServiceCreatesNewSubscription(t *testing.T) {
 	}
 }
 
+func TestServiceCreateWithSameIdempotencyKeyCreatesOnce(t *testing.T) {
+	storeID := uuid.New()
+	store := &models.Store{SubscriptionActive: false}
+	billingRepo := &stubBillingRepo{}
+	squareClient := &stubSquareSubscriptionClient{
+		createResp: &SquareSubscription{ID: "sub-new", Status: "ACTIVE"},
+		getResp: &SquareSubscription{
+			ID:                 "sub-new",
+			Status:             "ACTIVE",
+			StartDate:          time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC).Unix(),
+			ChargedThroughDate: time.Date(2027, 2, 11, 0, 0, 0, 0, time.UTC).Unix(),
+		},
+	}
+	svc, err := NewService(ServiceParams{
+		BillingRepo:       billingRepo,
+		StoreRepo:         &stubStoreRepo{store: store},
+		SquareClient:      squareClient,
+		TransactionRunner: &stubTxRunner{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := CreateSubscriptionInput{
+		SquareCustomerID:      "cust-1",
+		SquarePaymentMethodID: "pm-1",
+		PriceID:               "price-123",
+		IdempotencyKey:        "key-1",
+	}
+	first, created, err := svc.Create(context.Background(), storeID, input)
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	if !created {
+		t.Fatalf("expected first call to create")
+	}
+	second, created, err := svc.Create(context.Background(), storeID, input)
+	if err != nil {
+		t.Fatalf("second create: %v", err)
+	}
+	if created {
+		t.Fatalf("expected second call to return the existing subscription")
+	}
+	if second != first {
+		t.Fatalf("expected the same subscription returned")
+	}
+	if squareClient.createCalls != 1 {
+		t.Fatalf("expected one square create, got %d", squareClient.createCalls)
+	}
+	if len(billingRepo.created) != 1 {
+		t.Fatalf("expected one subscription row, got %d", len(billingRepo.created))
+	}
+	if squareClient.lastCreateParams == nil || squareClient.lastCreateParams.IdempotencyKey != "key-1" {
+		t.Fatalf("expected idempotency key forwarded to square")
+	}
+}
+
+func TestServiceCreateKeepsSquareSubscriptionReturnedForSameKey(t *testing.T) {
+	storeID := uuid.New()
+	key := "key-1"
+	persisted := &models.Subscription{
+		ID:                   uuid.New(),
+		StoreID:              storeID,
+		Status:               enums.SubscriptionStatusActive,
+		SquareSubscriptionID: "sub-new",
+		IdempotencyKey:       &key,
+	}
+	// The first lookup misses (the other request has not committed yet); the one inside the
+	// transaction sees the winner's row.
+	billingRepo := &racingBillingRepo{stubBillingRepo: &stubBillingRepo{}, persisted: persisted}
+	squareClient := &stubSquareSubscriptionClient{
+		createResp: &SquareSubscription{ID: "sub-new", Status: "ACTIVE"},
+		getResp:    &SquareSubscription{ID: "sub-new", Status: "ACTIVE"},
+	}
+	svc, err := NewService(ServiceParams{
+		BillingRepo:       billingRepo,
+		StoreRepo:         &stubStoreRepo{store: &models.Store{}},
+		SquareClient:      squareClient,
+		TransactionRunner: &stubTxRunner{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sub, created, err := svc.Create(context.Background(), storeID, CreateSubscriptionInput{
+		SquareCustomerID:      "cust-1",
+		SquarePaymentMethodID: "pm-1",
+		PriceID:               "price-123",
+		IdempotencyKey:        key,
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if created || sub != persisted {
+		t.Fatalf("expected the persisted subscription returned")
+	}
+	if squareClient.calledCancel {
+		t.Fatalf("square subscription shared by the key must not be canceled")
+	}
+	if len(billingRepo.created) != 0 {
+		t.Fatalf("expected no new subscription row")
+	}
+}
+
+type racingBillingRepo struct {
+	*stubBillingRepo
+	persisted *models.Subscription
+	lookups   int
+}
+
+func (r *racingBillingRepo) WithTx(tx *gorm.DB) billing.Repository {
+	return r
+}
+
+func (r *racingBillingRepo) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
+	r.lookups++
+	if r.lookups == 1 {
+		return nil, nil
+	}
+	return r.persisted, nil
+}
+
 func TestServiceCancelsSubscription(t *testing.T) {
 	storeID := uuid.New()
 	store := &models.Store{SubscriptionActive: true}
@@ -537,6 +659,7 @@ type stubBillingRepo struct {
 	existing *models.Subscription
 	created  []*models.Subscription
 	updated  []*models.Subscription
+	plan     *models.BillingPlan
 }
 
 // DeletePaymentMethod implements [billing.Repository].
@@ -573,6 +696,15 @@ func (s *stubBillingRepo) FindSubscriptionBySquareID(ctx context.Context, square
 	return nil, nil
 }
 
+func (s *stubBillingRepo) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
+	for _, sub := range s.created {
+		if sub.StoreID == storeID && sub.IdempotencyKey != nil && *sub.IdempotencyKey == key {
+			return sub, nil
+		}
+	}
+	return nil, nil
+}
+
 func (s *stubBillingRepo) CreatePaymentMethod(ctx context.Context, method *models.PaymentMethod) error {
 	return nil
 }
@@ -625,6 +757,9 @@ func (s *stubBillingRepo) FindBillingPlanByID(ctx context.Context, id string) (*
 }
 
 func (s *stubBillingRepo) FindBillingPlanBySquareID(ctx context.Context, squareBillingPlanID string) (*models.BillingPlan, error) {
+	if s.plan != nil && s.plan.SquareBillingPlanID == squareBillingPlanID {
+		return s.plan, nil
+	}
 	return nil, nil
 }
 
@@ -670,3 +805,15 @@ func ptrTime(t time.Time) *time.Time {
 func ptrString(s string) *string {
 	return &s
 }
+
+func (s *stubBillingRepo) UpsertInvoice(ctx context.Context, invoice *models.Invoice) error {
+	return nil
+}
+
+func (s *stubBillingRepo) ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	return nil, nil
+}
+
+func (s *stubBillingRepo) FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	return nil, nil
+}
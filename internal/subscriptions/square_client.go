@@ -58,6 +58,10 @@ func (c *squareSubscriptionClient) Create(ctx context.Context, params *SquareSub
 		PlanVariationID: strings.TrimSpace(params.PriceID),
 		CustomerID:      params.CustomerID,
 		CardID:          params.PaymentMethodID,
+		IdempotencyKey:  strings.TrimSpace(params.IdempotencyKey),
+
+		PriceOverrideAmount:   params.PriceOverrideCents,
+		PriceOverrideCurrency: params.PriceOverrideCurrency,
 	}
 
 	fmt.Printf("[squareSubscriptionClient.Create] Square API POST /v2/subscriptions request=%+v\n", req)
@@ -16,6 +16,8 @@ type stubSquareSubscriptionClient struct {
 	resumeErr                error
 	deleteErr                error
 	calledCreate             bool
+	createCalls              int
+	lastCreateParams         *SquareSubscriptionParams
 	calledCancel             bool
 	calledPause              bool
 	calledResume             bool
@@ -29,6 +31,8 @@ type stubSquareSubscriptionClient struct {
 
 func (s *stubSquareSubscriptionClient) Create(ctx context.Context, params *SquareSubscriptionParams) (*SquareSubscription, error) {
 	s.calledCreate = true
+	s.createCalls++
+	s.lastCreateParams = params
 	return s.createResp, s.createErr
 }
 
@@ -33,6 +33,10 @@ type SquareSubscriptionParams struct {
 	PaymentMethodID string
 	Metadata        map[string]string
 	IncludeActions  bool
+	IdempotencyKey  string
+	// PriceOverrideCents replaces the plan price when positive, e.g. after a coupon.
+	PriceOverrideCents    int64
+	PriceOverrideCurrency string
 }
 
 type SquareSubscriptionCancelParams struct{}
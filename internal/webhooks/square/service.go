@@ -3,10 +3,12 @@ package squarewebhook
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/internal/billing"
 	"github.com/angelmondragon/packfinderz-backend/internal/subscriptions"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
 	pkgerrors "github.com/angelmondragon/packfinderz-backend/pkg/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -21,18 +23,29 @@ type txRunner interface {
 	WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error
 }
 
+type notificationRepository interface {
+	CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error
+}
+
+// ServiceParams wires the webhook service. GracePeriod keeps a lapsed vendor active for that long
+// before its store is flagged inactive; Notifications, when set, receives the lapse warning.
 type ServiceParams struct {
 	BillingRepo       billing.Repository
 	StoreRepo         storeRepository
 	SquareClient      subscriptions.SquareSubscriptionClient
 	TransactionRunner txRunner
+	Notifications     notificationRepository
+	GracePeriod       time.Duration
 }
 
 type Service struct {
-	billingRepo billing.Repository
-	storeRepo   storeRepository
-	square      subscriptions.SquareSubscriptionClient
-	txRunner    txRunner
+	billingRepo   billing.Repository
+	storeRepo     storeRepository
+	square        subscriptions.SquareSubscriptionClient
+	txRunner      txRunner
+	notifications notificationRepository
+	grace         time.Duration
+	now           func() time.Time
 }
 
 func NewService(params ServiceParams) (*Service, error) {
@@ -48,11 +61,17 @@ func NewService(params ServiceParams) (*Service, error) {
 	if params.TransactionRunner == nil {
 		return nil, pkgerrors.New(pkgerrors.CodeInternal, "transaction runner required")
 	}
+	if params.GracePeriod < 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeInternal, "grace period must be non-negative")
+	}
 	return &Service{
-		billingRepo: params.BillingRepo,
-		storeRepo:   params.StoreRepo,
-		square:      params.SquareClient,
-		txRunner:    params.TransactionRunner,
+		billingRepo:   params.BillingRepo,
+		storeRepo:     params.StoreRepo,
+		square:        params.SquareClient,
+		txRunner:      params.TransactionRunner,
+		notifications: params.Notifications,
+		grace:         params.GracePeriod,
+		now:           time.Now,
 	}, nil
 }
 
@@ -79,6 +98,31 @@ type SquareWebhookObject struct {
 type SquareWebhookInvoice struct {
 	ID             string `json:"id"`
 	SubscriptionID string `json:"subscription_id"`
+	InvoiceNumber  string `json:"invoice_number"`
+	Status         string `json:"status"`
+	PublicURL      string `json:"public_url"`
+	UpdatedAt      string `json:"updated_at"`
+	// SaleOrServiceDate is the first day of the billed period on subscription invoices.
+	SaleOrServiceDate string                        `json:"sale_or_service_date"`
+	PaymentRequests   []SquareWebhookPaymentRequest `json:"payment_requests"`
+}
+
+type SquareWebhookPaymentRequest struct {
+	ComputedAmountMoney       *SquareWebhookMoney `json:"computed_amount_money"`
+	TotalCompletedAmountMoney *SquareWebhookMoney `json:"total_completed_amount_money"`
+}
+
+type SquareWebhookMoney struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// invoiceStatuses maps the settled Square invoice states we keep history for; drafts, unpaid and
+// canceled invoices are not recorded.
+var invoiceStatuses = map[string]enums.InvoiceStatus{
+	"PAID":               enums.InvoiceStatusPaid,
+	"PARTIALLY_REFUNDED": enums.InvoiceStatusPartiallyRefunded,
+	"REFUNDED":           enums.InvoiceStatusRefunded,
 }
 
 // HandleEvent processes Square subscription / invoice events.
@@ -89,6 +133,8 @@ func (s *Service) HandleEvent(ctx context.Context, event *SquareWebhookEvent) er
 
 	eventType := strings.ToLower(strings.TrimSpace(event.Type))
 	switch {
+	case eventType == "subscription.canceled" || eventType == "subscription.deactivated":
+		return s.handleSubscriptionCanceled(ctx, event)
 	case strings.HasPrefix(eventType, "subscription."):
 		return s.handleSubscriptionEvent(ctx, event)
 	case strings.HasPrefix(eventType, "invoice."):
@@ -99,19 +145,105 @@ func (s *Service) HandleEvent(ctx context.Context, event *SquareWebhookEvent) er
 }
 
 func (s *Service) handleSubscriptionEvent(ctx context.Context, event *SquareWebhookEvent) error {
+	subscription, err := s.eventSubscription(ctx, event)
+	if err != nil {
+		return err
+	}
+	return s.syncSubscription(ctx, subscription)
+}
+
+// eventSubscription returns the subscription embedded in the event, fetching it from Square when
+// the payload only carries an id.
+func (s *Service) eventSubscription(ctx context.Context, event *SquareWebhookEvent) (*subscriptions.SquareSubscription, error) {
 	subscription := event.Data.Object.Subscription
-	if subscription == nil || strings.TrimSpace(subscription.ID) == "" {
-		subscriptionID := subscriptionIDFromEvent(event)
-		if subscriptionID == "" {
-			return pkgerrors.New(pkgerrors.CodeValidation, "subscription id missing")
+	if subscription != nil && strings.TrimSpace(subscription.ID) != "" {
+		return subscription, nil
+	}
+	subscriptionID := subscriptionIDFromEvent(event)
+	if subscriptionID == "" {
+		return nil, pkgerrors.New(pkgerrors.CodeValidation, "subscription id missing")
+	}
+	subscription, err := s.square.Get(ctx, subscriptionID, nil)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "fetch square subscription")
+	}
+	return subscription, nil
+}
+
+// handleSubscriptionCanceled applies a cancellation made directly in Square and warns the vendor.
+// The store is only touched when the canceled subscription is its current one. Unlike a lapse
+// there is no grace window: the store is revoked as soon as the paid entitlement has ended, and
+// until then it keeps the period it already paid for. Redelivered events (past the event-id
+// guard) find the subscription already canceled and change nothing.
+func (s *Service) handleSubscriptionCanceled(ctx context.Context, event *SquareWebhookEvent) error {
+	squareSub, err := s.eventSubscription(ctx, event)
+	if err != nil {
+		return err
+	}
+	if squareSub == nil {
+		return pkgerrors.New(pkgerrors.CodeValidation, "subscription is required")
+	}
+	return s.txRunner.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.billingRepo.WithTx(tx)
+		stored, err := repo.FindSubscriptionBySquareID(ctx, squareSub.ID)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			// Nothing local to drift; a later sync creates the row from Square if it matters.
+			return nil
+		}
+
+		changed := stored.Status != enums.SubscriptionStatusCanceled
+		if err := subscriptions.UpdateSubscriptionFromSquare(stored, squareSub, stored.PriceID); err != nil {
+			return err
 		}
-		var err error
-		subscription, err = s.square.Get(ctx, subscriptionID, nil)
+		stored.Status = enums.SubscriptionStatusCanceled
+		if stored.CanceledAt == nil {
+			canceledAt := s.now().UTC()
+			stored.CanceledAt = &canceledAt
+		}
+		if err := repo.UpdateSubscription(ctx, stored); err != nil {
+			return err
+		}
+
+		current, err := repo.FindSubscription(ctx, stored.StoreID)
 		if err != nil {
-			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "fetch square subscription")
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load current subscription")
 		}
-	}
-	return s.syncSubscription(ctx, subscription)
+		if current != nil && current.ID != stored.ID {
+			// A newer subscription owns the store's entitlement; canceling an old one changes nothing.
+			return nil
+		}
+
+		store, err := s.storeRepo.FindByIDWithTx(tx, stored.StoreID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.New(pkgerrors.CodeNotFound, "store not found")
+			}
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
+		}
+		now := s.now()
+		entitled := subscriptions.StoreEntitled(now, squareSub, stored)
+		if !entitled {
+			// A cancellation ends any grace window an earlier lapse opened.
+			store.SubscriptionGraceUntil = nil
+		}
+		change := subscriptions.ApplyStoreEntitlement(store, entitled, now, 0)
+		if change.Changed {
+			changed = true
+			if err := s.storeRepo.UpdateWithTx(tx, store); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update store subscription flag")
+			}
+		}
+
+		if changed && s.notifications != nil {
+			if err := s.notifications.CreateWithTx(ctx, tx, subscriptions.CanceledNotification(stored.StoreID)); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create subscription canceled notification")
+			}
+		}
+		return nil
+	})
 }
 
 func (s *Service) handleInvoiceEvent(ctx context.Context, event *SquareWebhookEvent) error {
@@ -123,7 +255,97 @@ func (s *Service) handleInvoiceEvent(ctx context.Context, event *SquareWebhookEv
 	if err != nil {
 		return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "fetch square subscription")
 	}
-	return s.syncSubscription(ctx, squareSub)
+	if err := s.syncSubscription(ctx, squareSub); err != nil {
+		return err
+	}
+	return s.recordInvoice(ctx, event.Data.Object.Invoice, squareSub.ID)
+}
+
+// recordInvoice stores a settled invoice against the subscription it bills. The period starts on the
+// invoice's service date and ends where the freshly synced subscription, already advanced to the paid
+// period, is charged through; the paid time is when Square last updated the invoice. Those are only
+// written when the invoice is first recorded, so a later refund event does not move them.
+func (s *Service) recordInvoice(ctx context.Context, invoice *SquareWebhookInvoice, squareSubscriptionID string) error {
+	if invoice == nil || strings.TrimSpace(invoice.ID) == "" {
+		return nil
+	}
+	status, ok := invoiceStatuses[strings.ToUpper(strings.TrimSpace(invoice.Status))]
+	if !ok {
+		return nil
+	}
+	return s.txRunner.WithTx(ctx, func(tx *gorm.DB) error {
+		repo := s.billingRepo.WithTx(tx)
+		sub, err := repo.FindSubscriptionBySquareID(ctx, squareSubscriptionID)
+		if err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load subscription")
+		}
+		if sub == nil {
+			return pkgerrors.New(pkgerrors.CodeNotFound, "subscription not found")
+		}
+		amount, currency := invoiceAmount(invoice.PaymentRequests)
+		record := &models.Invoice{
+			StoreID:         sub.StoreID,
+			SubscriptionID:  &sub.ID,
+			SquareInvoiceID: strings.TrimSpace(invoice.ID),
+			InvoiceNumber:   optionalString(invoice.InvoiceNumber),
+			AmountCents:     amount,
+			Currency:        currency,
+			Status:          status,
+			PeriodStart:     sub.CurrentPeriodStart,
+			PublicURL:       optionalString(invoice.PublicURL),
+		}
+		if start, err := time.Parse(time.DateOnly, strings.TrimSpace(invoice.SaleOrServiceDate)); err == nil {
+			record.PeriodStart = &start
+		}
+		if !sub.CurrentPeriodEnd.IsZero() {
+			end := sub.CurrentPeriodEnd
+			record.PeriodEnd = &end
+		}
+		if paidAt, err := time.Parse(time.RFC3339, strings.TrimSpace(invoice.UpdatedAt)); err == nil {
+			paidAt = paidAt.UTC()
+			record.PaidAt = &paidAt
+		}
+		if err := repo.UpsertInvoice(ctx, record); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "record invoice")
+		}
+		return nil
+	})
+}
+
+// invoiceAmount sums what was collected across the payment requests, falling back to the billed
+// amount when Square has not reported completed money.
+func invoiceAmount(requests []SquareWebhookPaymentRequest) (int64, string) {
+	var completed, computed int64
+	currency := ""
+	for _, req := range requests {
+		if req.TotalCompletedAmountMoney != nil {
+			completed += req.TotalCompletedAmountMoney.Amount
+			if currency == "" {
+				currency = req.TotalCompletedAmountMoney.Currency
+			}
+		}
+		if req.ComputedAmountMoney != nil {
+			computed += req.ComputedAmountMoney.Amount
+			if currency == "" {
+				currency = req.ComputedAmountMoney.Currency
+			}
+		}
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+	if completed > 0 {
+		return completed, currency
+	}
+	return computed, currency
+}
+
+func optionalString(value string) *string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
 }
 
 func subscriptionIDFromEvent(event *SquareWebhookEvent) string {
@@ -205,14 +427,18 @@ func (s *Service) syncSubscription(ctx context.Context, squareSub *subscriptions
 			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "load store")
 		}
 
-		active := subscriptions.IsActiveStatus(successSub.Status)
-		if store.SubscriptionActive != active {
-			store.SubscriptionActive = active
-			if err := s.storeRepo.UpdateWithTx(tx, store); err != nil {
-				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update store subscription flag")
+		change := subscriptions.ApplyStoreEntitlement(store, subscriptions.IsActiveStatus(successSub.Status), s.now(), s.grace)
+		if !change.Changed {
+			return nil
+		}
+		if err := s.storeRepo.UpdateWithTx(tx, store); err != nil {
+			return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "update store subscription flag")
+		}
+		if change.GraceStarted && s.notifications != nil {
+			if err := s.notifications.CreateWithTx(ctx, tx, subscriptions.GraceWarningNotification(storeID, *store.SubscriptionGraceUntil)); err != nil {
+				return pkgerrors.Wrap(pkgerrors.CodeDependency, err, "create subscription grace notification")
 			}
 		}
-
 		return nil
 	})
 }
@@ -77,6 +77,371 @@ func TestService_HandleEvent_InvoiceUpdatesStoreStatus(t *testing.T) {
 	}
 }
 
+func TestService_HandleEvent_PaidInvoiceCreatesRecord(t *testing.T) {
+	storeID := uuid.New()
+	periodEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	subscription := &models.Subscription{
+		ID:                   uuid.New(),
+		StoreID:              storeID,
+		SquareSubscriptionID: "sub-123",
+		Status:               enums.SubscriptionStatusActive,
+	}
+	billingRepo := &stubBillingRepo{sub: subscription}
+	svc, err := NewService(ServiceParams{
+		BillingRepo: billingRepo,
+		StoreRepo:   &stubStoreRepo{store: &models.Store{ID: storeID, SubscriptionActive: true}},
+		SquareClient: &stubSquareClient{sub: &subscriptions.SquareSubscription{
+			ID:                 "sub-123",
+			Status:             "ACTIVE",
+			StartDate:          time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Unix(),
+			ChargedThroughDate: periodEnd.Unix(),
+		}},
+		TransactionRunner: &stubTxRunner{},
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	event := &SquareWebhookEvent{
+		EventID: "evt-paid",
+		Type:    "invoice.payment_made",
+		Data: SquareWebhookData{
+			Object: SquareWebhookObject{
+				Invoice: &SquareWebhookInvoice{
+					ID:                "inv-1",
+					SubscriptionID:    "sub-123",
+					InvoiceNumber:     "000042",
+					Status:            "PAID",
+					PublicURL:         "https://squareup.com/pay-invoice/inv-1",
+					UpdatedAt:         "2026-03-01T10:00:00Z",
+					SaleOrServiceDate: "2026-03-01",
+					PaymentRequests: []SquareWebhookPaymentRequest{{
+						ComputedAmountMoney:       &SquareWebhookMoney{Amount: 9900, Currency: "USD"},
+						TotalCompletedAmountMoney: &SquareWebhookMoney{Amount: 9900, Currency: "USD"},
+					}},
+				},
+			},
+		},
+	}
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	// Square redelivers webhooks; the record is keyed by the Square invoice id.
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle redelivery: %v", err)
+	}
+
+	invoices, err := billingService(t, billingRepo).ListInvoices(context.Background(), storeID)
+	if err != nil {
+		t.Fatalf("list invoices: %v", err)
+	}
+	if len(invoices) != 1 {
+		t.Fatalf("expected one invoice, got %d", len(invoices))
+	}
+	got := invoices[0]
+	if got.SquareInvoiceID != "inv-1" || got.AmountCents != 9900 || got.Currency != "USD" || got.Status != enums.InvoiceStatusPaid {
+		t.Fatalf("unexpected invoice %+v", got)
+	}
+	if got.SubscriptionID == nil || *got.SubscriptionID != subscription.ID {
+		t.Fatalf("expected invoice linked to subscription")
+	}
+	if got.PeriodEnd == nil || !got.PeriodEnd.Equal(periodEnd) {
+		t.Fatalf("expected period end %s, got %v", periodEnd, got.PeriodEnd)
+	}
+	if got.PaidAt == nil || got.InvoiceNumber == nil || *got.InvoiceNumber != "000042" {
+		t.Fatalf("expected paid_at and invoice number, got %+v", got)
+	}
+	periodStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got.PeriodStart == nil || !got.PeriodStart.Equal(periodStart) {
+		t.Fatalf("expected period start %s, got %v", periodStart, got.PeriodStart)
+	}
+	paidAt := *got.PaidAt
+
+	// A later refund changes the status and amount but not when the invoice was paid or what it billed.
+	refund := *event
+	refundedInvoice := *event.Data.Object.Invoice
+	refundedInvoice.Status = "REFUNDED"
+	refundedInvoice.UpdatedAt = "2026-03-20T10:00:00Z"
+	refundedInvoice.PaymentRequests = []SquareWebhookPaymentRequest{{
+		ComputedAmountMoney:       &SquareWebhookMoney{Amount: 9900, Currency: "USD"},
+		TotalCompletedAmountMoney: &SquareWebhookMoney{Amount: 4900, Currency: "USD"},
+	}}
+	refund.EventID = "evt-refund"
+	refund.Type = "invoice.refunded"
+	refund.Data.Object.Invoice = &refundedInvoice
+	if err := svc.HandleEvent(context.Background(), &refund); err != nil {
+		t.Fatalf("handle refund: %v", err)
+	}
+	invoices, err = billingService(t, billingRepo).ListInvoices(context.Background(), storeID)
+	if err != nil {
+		t.Fatalf("list invoices: %v", err)
+	}
+	got = invoices[0]
+	if got.Status != enums.InvoiceStatusRefunded || got.AmountCents != 4900 {
+		t.Fatalf("expected refunded invoice with new amount, got %+v", got)
+	}
+	if got.PaidAt == nil || !got.PaidAt.Equal(paidAt) || got.PeriodStart == nil || !got.PeriodStart.Equal(periodStart) {
+		t.Fatalf("expected paid_at and period kept, got %+v", got)
+	}
+}
+
+func TestService_HandleEvent_UnpaidInvoiceIsNotRecorded(t *testing.T) {
+	storeID := uuid.New()
+	billingRepo := &stubBillingRepo{sub: &models.Subscription{StoreID: storeID, SquareSubscriptionID: "sub-123"}}
+	svc, err := NewService(ServiceParams{
+		BillingRepo:       billingRepo,
+		StoreRepo:         &stubStoreRepo{store: &models.Store{ID: storeID}},
+		SquareClient:      &stubSquareClient{sub: &subscriptions.SquareSubscription{ID: "sub-123", Status: "ACTIVE"}},
+		TransactionRunner: &stubTxRunner{},
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	event := &SquareWebhookEvent{
+		Type: "invoice.scheduled_charge_failed",
+		Data: SquareWebhookData{Object: SquareWebhookObject{
+			Invoice: &SquareWebhookInvoice{ID: "inv-2", SubscriptionID: "sub-123", Status: "UNPAID"},
+		}},
+	}
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	if len(billingRepo.invoices) != 0 {
+		t.Fatalf("expected no invoice recorded, got %d", len(billingRepo.invoices))
+	}
+}
+
+func billingService(t *testing.T, repo billing.Repository) *billing.Service {
+	t.Helper()
+	svc, err := billing.NewService(billing.ServiceParams{Repo: repo})
+	if err != nil {
+		t.Fatalf("billing service init: %v", err)
+	}
+	return svc
+}
+
+func TestService_HandleEvent_SubscriptionCanceledInSquare(t *testing.T) {
+	storeID := uuid.New()
+	graceUntil := time.Now().Add(time.Hour)
+	subscription := &models.Subscription{
+		StoreID:              storeID,
+		SquareSubscriptionID: "sub-123",
+		Status:               enums.SubscriptionStatusActive,
+	}
+	store := &models.Store{ID: storeID, SubscriptionActive: true, SubscriptionGraceUntil: &graceUntil}
+	billingRepo := &stubBillingRepo{sub: subscription}
+	notes := &stubNotificationRepo{}
+	squareClient := &stubSquareClient{sub: &subscriptions.SquareSubscription{ID: "sub-123", Status: "CANCELED"}}
+	svc, err := NewService(ServiceParams{
+		BillingRepo:       billingRepo,
+		StoreRepo:         &stubStoreRepo{store: store},
+		SquareClient:      squareClient,
+		TransactionRunner: &stubTxRunner{},
+		Notifications:     notes,
+		GracePeriod:       72 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	event := &SquareWebhookEvent{
+		EventID: "evt-canceled",
+		Type:    "subscription.canceled",
+		Data:    SquareWebhookData{Object: SquareWebhookObject{SubscriptionID: "sub-123"}},
+	}
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+
+	if len(squareClient.lastGet) != 1 {
+		t.Fatalf("expected subscription fetched from square, got %v", squareClient.lastGet)
+	}
+	if subscription.Status != enums.SubscriptionStatusCanceled || subscription.CanceledAt == nil {
+		t.Fatalf("expected local subscription canceled, got %s canceled_at=%v", subscription.Status, subscription.CanceledAt)
+	}
+	if store.SubscriptionActive || store.SubscriptionGraceUntil != nil {
+		t.Fatalf("expected store revoked without grace, got active=%t grace=%v", store.SubscriptionActive, store.SubscriptionGraceUntil)
+	}
+	if len(notes.created) != 1 || notes.created[0].StoreID != storeID || notes.created[0].Title != "Subscription canceled" {
+		t.Fatalf("expected one cancellation notification, got %+v", notes.created)
+	}
+
+	// A redelivery under a new event id finds nothing left to change and stays quiet.
+	event.EventID = "evt-canceled-retry"
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle redelivery: %v", err)
+	}
+	if len(notes.created) != 1 {
+		t.Fatalf("expected no second notification, got %d", len(notes.created))
+	}
+}
+
+func TestService_HandleEvent_SubscriptionCanceledKeepsPaidPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	storeID := uuid.New()
+	subscription := &models.Subscription{
+		StoreID:              storeID,
+		SquareSubscriptionID: "sub-123",
+		Status:               enums.SubscriptionStatusActive,
+	}
+	store := &models.Store{ID: storeID, SubscriptionActive: true}
+	svc, err := NewService(ServiceParams{
+		BillingRepo: &stubBillingRepo{sub: subscription},
+		StoreRepo:   &stubStoreRepo{store: store},
+		SquareClient: &stubSquareClient{sub: &subscriptions.SquareSubscription{
+			ID:                 "sub-123",
+			Status:             "CANCELED",
+			ChargedThroughDate: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		}},
+		TransactionRunner: &stubTxRunner{},
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+	svc.now = func() time.Time { return now }
+
+	event := &SquareWebhookEvent{
+		EventID: "evt-canceled",
+		Type:    "subscription.canceled",
+		Data:    SquareWebhookData{Object: SquareWebhookObject{SubscriptionID: "sub-123"}},
+	}
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	if subscription.Status != enums.SubscriptionStatusCanceled {
+		t.Fatalf("expected local subscription canceled, got %s", subscription.Status)
+	}
+	if !store.SubscriptionActive {
+		t.Fatalf("expected store to keep the period it paid for")
+	}
+}
+
+func TestService_HandleEvent_SubscriptionCanceledIgnoresSupersededSubscription(t *testing.T) {
+	storeID := uuid.New()
+	old := &models.Subscription{
+		ID:                   uuid.New(),
+		StoreID:              storeID,
+		SquareSubscriptionID: "sub-old",
+		Status:               enums.SubscriptionStatusActive,
+	}
+	store := &models.Store{ID: storeID, SubscriptionActive: true}
+	notes := &stubNotificationRepo{}
+	svc, err := NewService(ServiceParams{
+		BillingRepo:       &stubBillingRepo{sub: old, current: &models.Subscription{ID: uuid.New(), StoreID: storeID}},
+		StoreRepo:         &stubStoreRepo{store: store},
+		SquareClient:      &stubSquareClient{sub: &subscriptions.SquareSubscription{ID: "sub-old", Status: "CANCELED"}},
+		TransactionRunner: &stubTxRunner{},
+		Notifications:     notes,
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+
+	event := &SquareWebhookEvent{
+		EventID: "evt-canceled-old",
+		Type:    "subscription.canceled",
+		Data:    SquareWebhookData{Object: SquareWebhookObject{SubscriptionID: "sub-old"}},
+	}
+	if err := svc.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+	if old.Status != enums.SubscriptionStatusCanceled {
+		t.Fatalf("expected old subscription canceled, got %s", old.Status)
+	}
+	if !store.SubscriptionActive || len(notes.created) != 0 {
+		t.Fatalf("expected store untouched by a superseded subscription, got active=%t notes=%d", store.SubscriptionActive, len(notes.created))
+	}
+}
+
+func TestService_HandleEvent_LapseWithinGraceKeepsStoreActive(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, store, notes := newLapsedSubscriptionFixture(t, nil)
+	svc.now = func() time.Time { return now }
+
+	if err := svc.HandleEvent(context.Background(), lapsedSubscriptionEvent()); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+
+	if !store.SubscriptionActive {
+		t.Fatalf("expected store to stay active during grace")
+	}
+	if store.SubscriptionGraceUntil == nil || !store.SubscriptionGraceUntil.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("expected grace until %s, got %v", now.Add(72*time.Hour), store.SubscriptionGraceUntil)
+	}
+	if len(notes.created) != 1 || notes.created[0].Type != enums.NotificationTypeSubscriptionAlert {
+		t.Fatalf("expected one subscription alert, got %+v", notes.created)
+	}
+
+	// A repeat delivery inside the window neither extends the grace nor warns again.
+	svc.now = func() time.Time { return now.Add(24 * time.Hour) }
+	if err := svc.HandleEvent(context.Background(), lapsedSubscriptionEvent()); err != nil {
+		t.Fatalf("handle repeat event: %v", err)
+	}
+	if !store.SubscriptionActive || !store.SubscriptionGraceUntil.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("expected grace window unchanged, got active=%t until=%v", store.SubscriptionActive, store.SubscriptionGraceUntil)
+	}
+	if len(notes.created) != 1 {
+		t.Fatalf("expected no second warning, got %d", len(notes.created))
+	}
+}
+
+func TestService_HandleEvent_LapsePastGraceDeactivatesStore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	graceUntil := now.Add(-time.Minute)
+	svc, store, notes := newLapsedSubscriptionFixture(t, &graceUntil)
+	svc.now = func() time.Time { return now }
+
+	if err := svc.HandleEvent(context.Background(), lapsedSubscriptionEvent()); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+
+	if store.SubscriptionActive {
+		t.Fatalf("expected store inactive once grace has passed")
+	}
+	if store.SubscriptionGraceUntil != nil {
+		t.Fatalf("expected grace cleared, got %v", store.SubscriptionGraceUntil)
+	}
+	if len(notes.created) != 0 {
+		t.Fatalf("expected no warning after grace, got %d", len(notes.created))
+	}
+}
+
+func newLapsedSubscriptionFixture(t *testing.T, graceUntil *time.Time) (*Service, *models.Store, *stubNotificationRepo) {
+	t.Helper()
+	storeID := uuid.New()
+	store := &models.Store{ID: storeID, SubscriptionActive: true, SubscriptionGraceUntil: graceUntil}
+	notes := &stubNotificationRepo{}
+	svc, err := NewService(ServiceParams{
+		BillingRepo: &stubBillingRepo{sub: &models.Subscription{
+			StoreID:              storeID,
+			SquareSubscriptionID: "sub-lapsed",
+			Status:               enums.SubscriptionStatusActive,
+		}},
+		StoreRepo:         &stubStoreRepo{store: store},
+		SquareClient:      &stubSquareClient{},
+		TransactionRunner: &stubTxRunner{},
+		Notifications:     notes,
+		GracePeriod:       72 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("service init: %v", err)
+	}
+	return svc, store, notes
+}
+
+func lapsedSubscriptionEvent() *SquareWebhookEvent {
+	return &SquareWebhookEvent{
+		EventID: "evt-lapsed",
+		Type:    "subscription.updated",
+		Data: SquareWebhookData{
+			Object: SquareWebhookObject{
+				Subscription: &subscriptions.SquareSubscription{ID: "sub-lapsed", Status: "DEACTIVATED"},
+			},
+		},
+	}
+}
+
 func TestSubscriptionIDFromEvent(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -102,8 +467,11 @@ func TestSubscriptionIDFromEvent(t *testing.T) {
 }
 
 type stubBillingRepo struct {
-	sub     *models.Subscription
-	updated []*models.Subscription
+	sub      *models.Subscription
+	updated  []*models.Subscription
+	invoices []models.Invoice
+	// current is the store's newest subscription, when a test needs one other than sub.
+	current *models.Subscription
 }
 
 // DeletePaymentMethod implements [billing.Repository].
@@ -131,6 +499,10 @@ func (s *stubBillingRepo) ListSubscriptionsByStore(ctx context.Context, storeID
 }
 
 func (s *stubBillingRepo) FindSubscription(ctx context.Context, storeID uuid.UUID) (*models.Subscription, error) {
+	return s.current, nil
+}
+
+func (s *stubBillingRepo) FindSubscriptionByIdempotencyKey(ctx context.Context, storeID uuid.UUID, key string) (*models.Subscription, error) {
 	return nil, nil
 }
 
@@ -197,6 +569,38 @@ func (s *stubBillingRepo) ListUsageChargesByStore(ctx context.Context, storeID u
 	return nil, nil
 }
 
+func (s *stubBillingRepo) UpsertInvoice(ctx context.Context, invoice *models.Invoice) error {
+	for i := range s.invoices {
+		if s.invoices[i].SquareInvoiceID == invoice.SquareInvoiceID {
+			// Mirrors the repository: a conflict only refreshes status and amounts.
+			s.invoices[i].Status = invoice.Status
+			s.invoices[i].AmountCents = invoice.AmountCents
+			s.invoices[i].Currency = invoice.Currency
+			invoice.ID = s.invoices[i].ID
+			return nil
+		}
+	}
+	if invoice.ID == uuid.Nil {
+		invoice.ID = uuid.New()
+	}
+	s.invoices = append(s.invoices, *invoice)
+	return nil
+}
+
+func (s *stubBillingRepo) ListInvoicesByStore(ctx context.Context, storeID uuid.UUID) ([]models.Invoice, error) {
+	var out []models.Invoice
+	for _, invoice := range s.invoices {
+		if invoice.StoreID == storeID {
+			out = append(out, invoice)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubBillingRepo) FindInvoice(ctx context.Context, storeID, invoiceID uuid.UUID) (*models.Invoice, error) {
+	return nil, nil
+}
+
 func (s *stubBillingRepo) ListSubscriptionsForReconciliation(ctx context.Context, limit int, lookback time.Duration) ([]models.Subscription, error) {
 	return nil, nil
 }
@@ -219,6 +623,15 @@ func (s *stubStoreRepo) UpdateWithTx(tx *gorm.DB, store *models.Store) error {
 	return nil
 }
 
+type stubNotificationRepo struct {
+	created []*models.Notification
+}
+
+func (s *stubNotificationRepo) CreateWithTx(ctx context.Context, tx *gorm.DB, notification *models.Notification) error {
+	s.created = append(s.created, notification)
+	return nil
+}
+
 type stubTxRunner struct{}
 
 func (s *stubTxRunner) WithTx(ctx context.Context, fn func(tx *gorm.DB) error) error {
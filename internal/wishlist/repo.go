@@ -8,6 +8,7 @@ import (
 	"time"
 
 	products "github.com/angelmondragon/packfinderz-backend/internal/products"
+	"github.com/angelmondragon/packfinderz-backend/pkg/db"
 	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
 	"github.com/angelmondragon/packfinderz-backend/pkg/pagination"
 	"github.com/google/uuid"
@@ -26,6 +27,15 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// liveProducts is the EXISTS subquery that hides wishlist entries whose product has been
+// soft-deleted.
+func (r *Repository) liveProducts() *gorm.DB {
+	return r.db.Table("products p").
+		Select("1").
+		Where("p.id = wishlist_items.product_id").
+		Scopes(db.NotDeleted("p.deleted_at"))
+}
+
 // AddItem inserts a wishlist entry and ignores duplicates.
 func (r *Repository) AddItem(ctx context.Context, storeID, productID uuid.UUID) error {
 	if storeID == uuid.Nil || productID == uuid.Nil {
@@ -91,7 +101,8 @@ func (r *Repository) ListItems(ctx context.Context, storeID uuid.UUID, cursor st
   ORDER BY pm.position ASC, pm.created_at ASC
   LIMIT 1
 ) pm_thumb ON true`).
-		Where("wi.store_id = ?", storeID)
+		Where("wi.store_id = ?", storeID).
+		Scopes(db.NotDeleted("p.deleted_at"))
 
 	if decodedCursor != nil {
 		dataQuery = dataQuery.Where("(wi.created_at < ?) OR (wi.created_at = ? AND wi.id < ?)", decodedCursor.CreatedAt, decodedCursor.CreatedAt, decodedCursor.ID)
@@ -138,9 +149,10 @@ func (r *Repository) ListItems(ctx context.Context, storeID uuid.UUID, cursor st
 		prevCursor = cursorValue
 	}
 
+	total := int(totalCount)
 	paginationMeta := products.ProductPagination{
 		Page:    1,
-		Total:   int(totalCount),
+		Total:   &total,
 		Current: cursorValue,
 		First:   firstCursor,
 		Last:    lastCursor,
@@ -167,7 +179,8 @@ func (r *Repository) ListItemIDs(ctx context.Context, storeID uuid.UUID, cursor
 	query := r.db.WithContext(ctx).
 		Model(&models.WishlistItem{}).
 		Select("id AS wishlist_id", "created_at AS wishlist_created_at", "product_id").
-		Where("store_id = ?", storeID)
+		Where("store_id = ?", storeID).
+		Where("EXISTS (?)", r.liveProducts())
 
 	if decodedCursor != nil {
 		query = query.Where("(created_at < ?) OR (created_at = ? AND id < ?)", decodedCursor.CreatedAt, decodedCursor.CreatedAt, decodedCursor.ID)
@@ -220,9 +233,10 @@ func (r *Repository) ListItemIDs(ctx context.Context, storeID uuid.UUID, cursor
 		prevCursor = cursorValue
 	}
 
+	total := int(totalCount)
 	paginationMeta := products.ProductPagination{
 		Page:    1,
-		Total:   int(totalCount),
+		Total:   &total,
 		Current: cursorValue,
 		First:   firstCursor,
 		Last:    lastCursor,
@@ -241,6 +255,7 @@ func (r *Repository) countWishlistItems(ctx context.Context, storeID uuid.UUID)
 	if err := r.db.WithContext(ctx).
 		Model(&models.WishlistItem{}).
 		Where("store_id = ?", storeID).
+		Where("EXISTS (?)", r.liveProducts()).
 		Count(&count).
 		Error; err != nil {
 		return 0, err
@@ -263,6 +278,7 @@ func (r *Repository) fetchWishlistBoundaryCursor(ctx context.Context, storeID uu
 		Model(&models.WishlistItem{}).
 		Select("created_at", "id").
 		Where("store_id = ?", storeID).
+		Where("EXISTS (?)", r.liveProducts()).
 		Order(order).
 		Limit(1)
 
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+)
+
+const apiKeyTokenPrefix = "pfz_"
+
+// APIKeyPrincipal is the identity resolved from a store API key.
+type APIKeyPrincipal struct {
+	KeyID     uuid.UUID
+	StoreID   uuid.UUID
+	StoreType enums.StoreType
+	// UserID is the member who created the key; writes made with the key are attributed to them.
+	UserID uuid.UUID
+	Scopes []enums.APIKeyScope
+}
+
+// HasScope reports whether the key was granted scope.
+func (p APIKeyPrincipal) HasScope(scope enums.APIKeyScope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAPIKey returns a new token of the form pfz_<prefix>_<secret> plus its lookup prefix
+// and the hash to persist. The token itself is only ever shown once.
+func GenerateAPIKey() (token, prefix, secretHash string, err error) {
+	prefixBytes := make([]byte, 6)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", fmt.Errorf("generate api key prefix: %w", err)
+	}
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", fmt.Errorf("generate api key secret: %w", err)
+	}
+	prefix = hex.EncodeToString(prefixBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	return apiKeyTokenPrefix + prefix + "_" + secret, prefix, HashAPIKeySecret(secret), nil
+}
+
+// ParseAPIKey splits a token into its lookup prefix and secret.
+func ParseAPIKey(token string) (prefix, secret string, err error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(token), apiKeyTokenPrefix)
+	if !ok {
+		return "", "", fmt.Errorf("malformed api key")
+	}
+	prefix, secret, ok = strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", "", fmt.Errorf("malformed api key")
+	}
+	return prefix, secret, nil
+}
+
+// HashAPIKeySecret hashes a secret for storage. Secrets carry 256 bits of entropy, so a fast
+// hash is sufficient.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
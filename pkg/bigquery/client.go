@@ -104,6 +104,9 @@ func configuredTables(cfg config.BigQueryConfig) []string {
 	if trimmed := strings.TrimSpace(cfg.AdEventsTable); trimmed != "" {
 		tables = append(tables, trimmed)
 	}
+	if trimmed := strings.TrimSpace(cfg.OutboxExportTable); trimmed != "" {
+		tables = append(tables, trimmed)
+	}
 	return tables
 }
 
@@ -176,6 +179,35 @@ func (c *Client) Query(ctx context.Context, sql string, params []bigquery.QueryP
 	return q.Read(ctx)
 }
 
+// Exec runs a DML statement (such as MERGE) and waits for the job to finish.
+func (c *Client) Exec(ctx context.Context, sql string, params []bigquery.QueryParameter) error {
+	if c == nil || c.client == nil {
+		return errClientNotInitialized
+	}
+	if strings.TrimSpace(sql) == "" {
+		return errors.New("sql query is required")
+	}
+	q := c.client.Query(sql)
+	q.Parameters = params
+	job, err := q.Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
+// QualifiedTable returns the backtick-quoted project.dataset.table reference for SQL.
+func (c *Client) QualifiedTable(table string) string {
+	if c == nil || c.dataset == nil {
+		return ""
+	}
+	return fmt.Sprintf("`%s.%s.%s`", c.projectID, c.dataset.DatasetID, strings.TrimSpace(table))
+}
+
 // Close releases the BigQuery client.
 func (c *Client) Close() error {
 	if c == nil || c.client == nil {
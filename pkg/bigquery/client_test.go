@@ -22,6 +22,17 @@ func TestConfiguredTables(t *testing.T) {
 	}
 }
 
+func TestConfiguredTablesIncludesOutboxExport(t *testing.T) {
+	tables := configuredTables(config.BigQueryConfig{
+		MarketplaceEventsTable: "marketplace_events",
+		OutboxExportTable:      " outbox_events_staging ",
+	})
+
+	if len(tables) != 2 || tables[1] != "outbox_events_staging" {
+		t.Fatalf("expected outbox export table to be verified, got %v", tables)
+	}
+}
+
 func TestClientOptionsPrioritizesJSON(t *testing.T) {
 	gcp := config.GCPConfig{
 		CredentialsJSON:        `{"dummy": "value"}`,
@@ -10,26 +10,37 @@ import (
 )
 
 type Config struct {
-	App           AppConfig
-	Service       ServiceConfig
-	DB            DBConfig
-	Redis         RedisConfig
-	JWT           JWTConfig
-	Password      PasswordConfig
-	AuthRateLimit AuthRateLimitConfig
-	FeatureFlags  FeatureFlagsConfig
-	Eventing      EventingConfig
-	OpenAI        OpenAIConfig
-	GoogleMaps    GoogleMapsConfig
-	GCP           GCPConfig
-	GCS           GCSConfig
-	Media         MediaConfig
-	PubSub        PubSubConfig
-	BigQuery      BigQueryConfig
-	Square        SquareConfig
-	Sendgrid      SendgridConfig
-	Outbox        OutboxConfig
-	Ads           AdsConfig
+	App            AppConfig
+	Service        ServiceConfig
+	DB             DBConfig
+	Redis          RedisConfig
+	JWT            JWTConfig
+	Password       PasswordConfig
+	AuthRateLimit  AuthRateLimitConfig
+	StoreRateLimit StoreRateLimitConfig
+	FeatureFlags   FeatureFlagsConfig
+	Eventing       EventingConfig
+	OpenAI         OpenAIConfig
+	GoogleMaps     GoogleMapsConfig
+	GCP            GCPConfig
+	GCS            GCSConfig
+	Media          MediaConfig
+	PubSub         PubSubConfig
+	BigQuery       BigQueryConfig
+	Square         SquareConfig
+	Sendgrid       SendgridConfig
+	Outbox         OutboxConfig
+	Inventory      InventoryConfig
+	Shipping       ShippingConfig
+	Cart           CartConfig
+	Orders         OrdersConfig
+	Money          MoneyConfig
+	Tax            TaxConfig
+	HTTP           HTTPConfig
+	Ads            AdsConfig
+	Agent          AgentConfig
+	Notifications  NotificationsConfig
+	Cron           CronConfig
 }
 
 func Load() (*Config, error) {
@@ -48,6 +59,8 @@ type AppConfig struct {
 	Port         string `envconfig:"PACKFINDERZ_APP_PORT" required:"true"`
 	LogLevel     string `envconfig:"PACKFINDERZ_LOG_LEVEL" default:"info"`
 	LogWarnStack bool   `envconfig:"PACKFINDERZ_LOG_WARN_STACK" default:"false"`
+	// LogDebugSampleEvery keeps one in every N debug log entries; 0 keeps them all.
+	LogDebugSampleEvery uint32 `envconfig:"PACKFINDERZ_LOG_DEBUG_SAMPLE_EVERY" default:"0"`
 }
 
 func (a AppConfig) IsDev() bool {
@@ -65,6 +78,9 @@ type ServiceConfig struct {
 type DBConfig struct {
 	DSN    string `envconfig:"PACKFINDERZ_DB_DSN"`
 	Driver string `envconfig:"PACKFINDERZ_DB_DRIVER" default:"postgres"`
+	// ReplicaDSN points read-only queries (contexts marked with db.ReadOnly) at a replica; empty
+	// keeps every query on the primary.
+	ReplicaDSN string `envconfig:"PACKFINDERZ_DB_REPLICA_DSN"`
 
 	LegacyHost     string `envconfig:"PACKFINDERZ_DB_HOST"`
 	LegacyPort     int    `envconfig:"PACKFINDERZ_DB_PORT" default:"5432"`
@@ -77,6 +93,8 @@ type DBConfig struct {
 	MaxIdleConns    int           `envconfig:"PACKFINDERZ_DB_MAX_IDLE_CONNS" default:"10"`
 	ConnMaxLifetime time.Duration `envconfig:"PACKFINDERZ_DB_CONN_MAX_LIFETIME" default:"1h"`
 	ConnMaxIdleTime time.Duration `envconfig:"PACKFINDERZ_DB_CONN_MAX_IDLE_TIME" default:"10m"`
+	// PoolStatsInterval is how often the API samples pool stats into its metrics; 0 disables sampling.
+	PoolStatsInterval time.Duration `envconfig:"PACKFINDERZ_DB_POOL_STATS_INTERVAL" default:"15s"`
 }
 
 type RedisConfig struct {
@@ -123,12 +141,23 @@ type AuthRateLimitConfig struct {
 	RegisterIPLimit    int           `envconfig:"PACKFINDERZ_AUTH_RATE_LIMIT_REGISTER_IP_LIMIT" default:"20"`
 }
 
+// StoreRateLimitConfig throttles expensive endpoints per active store. Each limit is the number of
+// requests a store may make per Window, refilled evenly so a store can burst up to the limit; 0
+// turns off that route's limit.
+type StoreRateLimitConfig struct {
+	Window         time.Duration `envconfig:"PACKFINDERZ_STORE_RATE_LIMIT_WINDOW" default:"1m"`
+	QuoteLimit     int           `envconfig:"PACKFINDERZ_STORE_RATE_LIMIT_QUOTE" default:"60"`
+	CheckoutLimit  int           `envconfig:"PACKFINDERZ_STORE_RATE_LIMIT_CHECKOUT" default:"20"`
+	AnalyticsLimit int           `envconfig:"PACKFINDERZ_STORE_RATE_LIMIT_ANALYTICS" default:"30"`
+}
+
 type FeatureFlagsConfig struct {
-	UseSQLite     bool   `envconfig:"PACKFINDERZ_USE_SQLITE" default:"false"`
-	AutoMigrate   bool   `envconfig:"PACKFINDERZ_AUTO_MIGRATE" default:"false"`
-	AVScan        string `envconfig:"PACKFINDERZ_AV_SCAN" default:"off"`
-	GCSAccessMode string `envconfig:"PACKFINDERZ_GCS_ACCESS_MODE" default:"public"`
-	AllowACH      bool   `envconfig:"PACKFINDERZ_FEATURE_ALLOW_ACH" default:"false"`
+	UseSQLite       bool          `envconfig:"PACKFINDERZ_USE_SQLITE" default:"false"`
+	AutoMigrate     bool          `envconfig:"PACKFINDERZ_AUTO_MIGRATE" default:"false"`
+	AVScan          string        `envconfig:"PACKFINDERZ_AV_SCAN" default:"off"`
+	GCSAccessMode   string        `envconfig:"PACKFINDERZ_GCS_ACCESS_MODE" default:"public"`
+	AllowACH        bool          `envconfig:"PACKFINDERZ_FEATURE_ALLOW_ACH" default:"false"`
+	RefreshInterval time.Duration `envconfig:"PACKFINDERZ_FEATURE_FLAG_REFRESH_INTERVAL" default:"30s"`
 }
 
 type EventingConfig struct {
@@ -140,7 +169,9 @@ type OpenAIConfig struct {
 }
 
 type GoogleMapsConfig struct {
-	APIKey string `envconfig:"PACKFINDERZ_GOOGLE_MAPS_API_KEY"`
+	APIKey          string        `envconfig:"PACKFINDERZ_GOOGLE_MAPS_API_KEY"`
+	GeocodeCacheTTL time.Duration `envconfig:"PACKFINDERZ_GOOGLE_MAPS_GEOCODE_CACHE_TTL" default:"720h"`
+	RouteCacheTTL   time.Duration `envconfig:"PACKFINDERZ_GOOGLE_MAPS_ROUTE_CACHE_TTL" default:"24h"`
 }
 
 type AdsConfig struct {
@@ -161,10 +192,14 @@ type GCPConfig struct {
 	ApplicationCredentials string `envconfig:"PACKFINDERZ_GOOGLE_APPLICATION_CREDENTIALS"`
 }
 
+// GCSConfig names the media bucket and how long signed URLs last. The ByKind maps override the
+// expiry for individual media kinds, e.g. "license_doc:5m,manifest:10m".
 type GCSConfig struct {
-	BucketName        string        `envconfig:"PACKFINDERZ_GCS_BUCKET_NAME" required:"true"`
-	UploadURLExpiry   time.Duration `envconfig:"PACKFINDERZ_GCS_UPLOAD_URL_EXPIRY" required:"true"`
-	DownloadURLExpiry time.Duration `envconfig:"PACKFINDERZ_GCS_DOWNLOAD_URL_EXPIRY" required:"true"`
+	BucketName              string                   `envconfig:"PACKFINDERZ_GCS_BUCKET_NAME" required:"true"`
+	UploadURLExpiry         time.Duration            `envconfig:"PACKFINDERZ_GCS_UPLOAD_URL_EXPIRY" required:"true"`
+	DownloadURLExpiry       time.Duration            `envconfig:"PACKFINDERZ_GCS_DOWNLOAD_URL_EXPIRY" required:"true"`
+	UploadURLExpiryByKind   map[string]time.Duration `envconfig:"PACKFINDERZ_GCS_UPLOAD_URL_EXPIRY_BY_KIND"`
+	DownloadURLExpiryByKind map[string]time.Duration `envconfig:"PACKFINDERZ_GCS_DOWNLOAD_URL_EXPIRY_BY_KIND"`
 }
 
 type MediaConfig struct {
@@ -177,6 +212,8 @@ type MediaConfig struct {
 	VideoMaxBitrate string `envconfig:"PACKFINDERZ_MEDIA_VIDEO_MAX_BITRATE" default:"8M"`
 	PDFQuality      string `envconfig:"PACKFINDERZ_MEDIA_PDF_QUALITY" default:"ebook"`
 	PDFDPI          int    `envconfig:"PACKFINDERZ_MEDIA_PDF_DPI" default:"150"`
+	// DeletionGracePeriod is how long deleted media stays recoverable before it is purged.
+	DeletionGracePeriod time.Duration `envconfig:"PACKFINDERZ_MEDIA_DELETION_GRACE_PERIOD" default:"168h"`
 }
 
 type PubSubConfig struct {
@@ -192,12 +229,23 @@ type PubSubConfig struct {
 	NotificationSubscription  string `envconfig:"PACKFINDERZ_PUBSUB_NOTIFICATION_SUBSCRIPTION" required:"true"`
 	AnalyticsTopic            string `envconfig:"PACKFINDERZ_PUBSUB_ANALYTICS_TOPIC" required:"true"`
 	AnalyticsSubscription     string `envconfig:"PACKFINDERZ_PUBSUB_ANALYTICS_SUBSCRIPTION" required:"true"`
+	// StoreWebhookSubscription reads the orders topic for outbound store webhooks; empty disables delivery.
+	StoreWebhookSubscription string `envconfig:"PACKFINDERZ_PUBSUB_STORE_WEBHOOK_SUBSCRIPTION"`
+	// MaxDeliveryAttempts caps retries of a failing message before consumers ack and drop it.
+	MaxDeliveryAttempts int `envconfig:"PACKFINDERZ_PUBSUB_MAX_DELIVERY_ATTEMPTS" default:"10"`
+	// DeadLetterTopic receives messages dropped after MaxDeliveryAttempts; empty only logs them.
+	DeadLetterTopic string `envconfig:"PACKFINDERZ_PUBSUB_DEAD_LETTER_TOPIC"`
+	// DrainTimeout bounds how long consumers finish in-flight messages after shutdown begins.
+	DrainTimeout time.Duration `envconfig:"PACKFINDERZ_PUBSUB_DRAIN_TIMEOUT" default:"20s"`
 }
 
 type BigQueryConfig struct {
 	Dataset                string `envconfig:"PACKFINDERZ_BIGQUERY_DATASET" default:"packfinderz"`
 	MarketplaceEventsTable string `envconfig:"PACKFINDERZ_BIGQUERY_MARKETPLACE_TABLE" default:"marketplace_events"`
 	AdEventsTable          string `envconfig:"PACKFINDERZ_BIGQUERY_AD_TABLE" default:"ad_events"`
+	// OutboxExportTable is the staging table the cron worker copies published outbox events into.
+	// Empty disables the export.
+	OutboxExportTable string `envconfig:"PACKFINDERZ_BIGQUERY_OUTBOX_EXPORT_TABLE"`
 }
 
 type OutboxConfig struct {
@@ -206,11 +254,102 @@ type OutboxConfig struct {
 	MaxAttempts    int `envconfig:"PACKFINDERZ_OUTBOX_MAX_ATTEMPTS" default:"10"`
 }
 
+type InventoryConfig struct {
+	LowStockThreshold int `envconfig:"PACKFINDERZ_INVENTORY_LOW_STOCK_THRESHOLD" default:"0"`
+}
+
+type ShippingConfig struct {
+	Mode          string `envconfig:"PACKFINDERZ_SHIPPING_MODE" default:"flat"`
+	FlatRateCents int    `envconfig:"PACKFINDERZ_SHIPPING_FLAT_RATE_CENTS" default:"0"`
+	BaseCents     int    `envconfig:"PACKFINDERZ_SHIPPING_BASE_CENTS" default:"0"`
+	PerMileCents  int    `envconfig:"PACKFINDERZ_SHIPPING_PER_MILE_CENTS" default:"0"`
+	FreeOverCents int    `envconfig:"PACKFINDERZ_SHIPPING_FREE_OVER_CENTS" default:"0"`
+	// AddressValidation geocodes checkout shipping addresses: off, lenient (normalize when
+	// possible), or strict (reject addresses that cannot be verified).
+	AddressValidation string `envconfig:"PACKFINDERZ_SHIPPING_ADDRESS_VALIDATION" default:"off"`
+}
+
+// CartConfig controls how long cart quotes stay valid. CategoryQuoteTTLs overrides the default for
+// price-volatile categories, e.g. "flower:5m,vape:10m".
+type CartConfig struct {
+	QuoteTTL          time.Duration            `envconfig:"PACKFINDERZ_CART_QUOTE_TTL" default:"15m"`
+	CategoryQuoteTTLs map[string]time.Duration `envconfig:"PACKFINDERZ_CART_CATEGORY_QUOTE_TTLS"`
+}
+
+// OrdersConfig controls buyer order actions. CancelWindow is how long after acceptance a buyer may
+// cancel directly; later cancels become requests the vendor must approve. NudgeCooldown is the
+// minimum gap between buyer nudges on the same order (zero disables the throttle).
+// RetryableStatuses lists the order statuses a buyer may retry. ReservationTTL is how long a pending
+// order keeps its checkout stock reserved before the cron worker releases it (zero keeps it until the
+// order expires).
+type OrdersConfig struct {
+	CancelWindow      time.Duration `envconfig:"PACKFINDERZ_ORDERS_CANCEL_WINDOW" default:"15m"`
+	NudgeCooldown     time.Duration `envconfig:"PACKFINDERZ_ORDERS_NUDGE_COOLDOWN" default:"1h"`
+	RetryableStatuses []string      `envconfig:"PACKFINDERZ_ORDERS_RETRYABLE_STATUSES" default:"expired,rejected"`
+	ReservationTTL    time.Duration `envconfig:"PACKFINDERZ_ORDERS_RESERVATION_TTL" default:"72h"`
+	// AfterHours decides what checkout does for a vendor outside its business hours: queue the
+	// order until the vendor opens, or block checkout.
+	AfterHours string `envconfig:"PACKFINDERZ_ORDERS_AFTER_HOURS" default:"queue"`
+}
+
+// TaxConfig sets the sales tax checkout charges on each vendor order's merchandise total, as a
+// percentage (8.25 for 8.25%). Zero charges no tax.
+type TaxConfig struct {
+	RatePercent float64 `envconfig:"PACKFINDERZ_TAX_RATE_PERCENT" default:"0"`
+}
+
+// MoneyConfig controls how fractional cents from percentage discounts are rounded: half_up
+// (default), half_even, or down.
+type MoneyConfig struct {
+	RoundingMode string `envconfig:"PACKFINDERZ_MONEY_ROUNDING_MODE" default:"half_up"`
+}
+
+// NotificationsConfig controls how long notifications are kept. TypeRetentionDays overrides the
+// default per notification type, e.g. "order_alert:90,market_update:7".
+type NotificationsConfig struct {
+	RetentionDays     int            `envconfig:"PACKFINDERZ_NOTIFICATION_RETENTION_DAYS" default:"30"`
+	TypeRetentionDays map[string]int `envconfig:"PACKFINDERZ_NOTIFICATION_TYPE_RETENTION_DAYS"`
+}
+
+// CronConfig controls the cron worker. DryRun runs only the destructive jobs, and only to log
+// what they would change.
+type CronConfig struct {
+	DryRun bool `envconfig:"PACKFINDERZ_CRON_DRY_RUN" default:"false"`
+}
+
+// AgentConfig prices what an agent earns per delivered order: a flat base plus a share of the
+// order total in basis points (100 = 1%).
+type AgentConfig struct {
+	DeliveryFeeBaseCents int `envconfig:"PACKFINDERZ_AGENT_DELIVERY_FEE_BASE_CENTS" default:"500"`
+	DeliveryFeeBps       int `envconfig:"PACKFINDERZ_AGENT_DELIVERY_FEE_BPS" default:"0"`
+}
+
+// HTTPConfig bounds request duration and size. A request that outlives its timeout has its
+// context cancelled and the client receives a 504. Reads cover GET/HEAD, writes every other method; checkout gets
+// its own budget because it reserves inventory across vendors.
+type HTTPConfig struct {
+	ReadTimeout     time.Duration `envconfig:"PACKFINDERZ_HTTP_READ_TIMEOUT" default:"10s"`
+	WriteTimeout    time.Duration `envconfig:"PACKFINDERZ_HTTP_WRITE_TIMEOUT" default:"30s"`
+	CheckoutTimeout time.Duration `envconfig:"PACKFINDERZ_HTTP_CHECKOUT_TIMEOUT" default:"60s"`
+
+	// MaxBodyBytes caps request bodies; bulk import and media metadata get larger allowances.
+	MaxBodyBytes           int64 `envconfig:"PACKFINDERZ_HTTP_MAX_BODY_BYTES" default:"1048576"`
+	BulkImportMaxBodyBytes int64 `envconfig:"PACKFINDERZ_HTTP_BULK_IMPORT_MAX_BODY_BYTES" default:"5242880"`
+	MediaMaxBodyBytes      int64 `envconfig:"PACKFINDERZ_HTTP_MEDIA_MAX_BODY_BYTES" default:"2097152"`
+
+	// MetricsScrapeToken is the bearer token Prometheus sends to GET /metrics; empty leaves the
+	// endpoint unmounted.
+	MetricsScrapeToken string `envconfig:"PACKFINDERZ_HTTP_METRICS_SCRAPE_TOKEN"`
+}
+
 type SquareConfig struct {
 	AccessToken   string `envconfig:"PACKFINDERZ_SQUARE_ACCESS_TOKEN"`
 	WebhookSecret string `envconfig:"PACKFINDERZ_SQUARE_WEBHOOK_SECRET"`
 	Env           string `envconfig:"PACKFINDERZ_SQUARE_ENV" default:"sandbox"`
 	LocationID    string `envconfig:"PACKFINDERZ_SQUARE_LOCATION_ID"`
+	// SubscriptionGracePeriod keeps a vendor entitled for this long after its subscription lapses;
+	// zero revokes access immediately.
+	SubscriptionGracePeriod time.Duration `envconfig:"PACKFINDERZ_SQUARE_SUBSCRIPTION_GRACE_PERIOD" default:"72h"`
 }
 
 type SendgridConfig struct {
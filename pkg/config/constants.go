@@ -6,6 +6,14 @@ const (
 	AppEnvDev  = "dev"
 	AppEnvProd = "prod"
 
+	ServiceKindAPI                 = "api"
+	ServiceKindWorker              = "worker"
+	ServiceKindOutboxPublisher     = "outbox-publisher"
+	ServiceKindCronWorker          = "cron-worker"
+	ServiceKindMediaDeletionWorker = "media-deletion-worker"
+	ServiceKindAnalyticsWorker     = "analytics-worker"
+	ServiceKindMigrate             = "migrate"
+
 	EnvAppEnv  = "PACKFINDERZ_APP_ENV"
 	EnvPort    = "PACKFINDERZ_APP_PORT"
 	EnvSvcKind = "PACKFINDERZ_SERVICE_KIND"
@@ -80,6 +88,14 @@ const (
 	EnvPubSubAnalyticsTopic     = "PACKFINDERZ_PUBSUB_ANALYTICS_TOPIC"
 	EnvPubSubAnalyticsSub       = "PACKFINDERZ_PUBSUB_ANALYTICS_SUBSCRIPTION"
 
+	EnvSquareAccessToken   = "PACKFINDERZ_SQUARE_ACCESS_TOKEN"
+	EnvSquareWebhookSecret = "PACKFINDERZ_SQUARE_WEBHOOK_SECRET"
+	EnvSquareEnv           = "PACKFINDERZ_SQUARE_ENV"
+	EnvSquareLocationID    = "PACKFINDERZ_SQUARE_LOCATION_ID"
+
+	EnvPubSubMaxDeliveryAttempts = "PACKFINDERZ_PUBSUB_MAX_DELIVERY_ATTEMPTS"
+	EnvBigQueryDataset           = "PACKFINDERZ_BIGQUERY_DATASET"
+
 	EnvSendgridAPIKey = "PACKFINDERZ_SENDGRID_API_KEY"
 	EnvSendgridSender = "PACKFINDERZ_SENDGRID_FROM_EMAIL"
 
@@ -87,6 +103,8 @@ const (
 	EnvOutboxPollMs      = "PACKFINDERZ_OUTBOX_PUBLISH_POLL_MS"
 	EnvOutboxMaxAttempts = "PACKFINDERZ_OUTBOX_MAX_ATTEMPTS"
 
+	EnvMoneyRoundingMode = "PACKFINDERZ_MONEY_ROUNDING_MODE"
+
 	EnvCloudSQLInstance    = "PACKFINDERZ_CLOUD_SQL_INSTANCE"
 	EnvCloudSQLProxyPort   = "PACKFINDERZ_CLOUD_SQL_PROXY_PORT"
 	EnvCloudSQLProxyBinary = "PACKFINDERZ_CLOUD_SQL_PROXY_BINARY"
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
+)
+
+// Validate checks the settings the configured service kind depends on and reports every problem
+// in a single error, so a misconfigured deploy fails at boot instead of deep inside a client.
+func (c *Config) Validate() error {
+	var problems []string
+	require := func(env, value string) {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, env+" is required")
+		}
+	}
+	requirePositive := func(env string, value int) {
+		if value <= 0 {
+			problems = append(problems, env+" must be greater than zero")
+		}
+	}
+
+	require(EnvAppEnv, c.App.Env)
+	require(EnvDBDSN, c.DB.DSN)
+
+	kind := c.Service.Kind
+	switch kind {
+	case ServiceKindAPI, ServiceKindWorker, ServiceKindOutboxPublisher, ServiceKindCronWorker,
+		ServiceKindMediaDeletionWorker, ServiceKindAnalyticsWorker, ServiceKindMigrate:
+	default:
+		problems = append(problems, fmt.Sprintf("%s %q is not a known service kind", EnvSvcKind, kind))
+	}
+
+	if kind != ServiceKindMigrate {
+		require(EnvRedisURL, c.Redis.URL)
+	}
+
+	switch kind {
+	case ServiceKindAPI, ServiceKindWorker, ServiceKindCronWorker:
+		require(EnvSquareAccessToken, c.Square.AccessToken)
+		require(EnvSquareWebhookSecret, c.Square.WebhookSecret)
+		if env := c.Square.Environment(); env != "sandbox" && env != "production" {
+			problems = append(problems, fmt.Sprintf("%s must be sandbox or production, got %q", EnvSquareEnv, c.Square.Env))
+		}
+		require(EnvGCPProjectID, c.GCP.ProjectID)
+		require(EnvGCSBucket, c.GCS.BucketName)
+	}
+
+	switch kind {
+	case ServiceKindAPI:
+		require(EnvSquareLocationID, c.Square.LocationID)
+		require(EnvGoogleMapsKey, c.GoogleMaps.APIKey)
+		require(EnvJWTSecret, c.JWT.Secret)
+		requirePositive(EnvJWTExpMins, c.JWT.ExpirationMinutes)
+		if c.GCS.UploadURLExpiry <= 0 {
+			problems = append(problems, EnvGCSUploadExpiry+" must be greater than zero")
+		}
+		if c.GCS.DownloadURLExpiry <= 0 {
+			problems = append(problems, EnvGCSDownloadExpiry+" must be greater than zero")
+		}
+		if _, err := money.ParseRoundingMode(c.Money.RoundingMode); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", EnvMoneyRoundingMode, err))
+		}
+	case ServiceKindCronWorker:
+		require(EnvSquareLocationID, c.Square.LocationID)
+	case ServiceKindOutboxPublisher:
+		requirePositive(EnvOutboxBatchSize, c.Outbox.BatchSize)
+		requirePositive(EnvOutboxPollMs, c.Outbox.PollIntervalMS)
+	case ServiceKindAnalyticsWorker:
+		require(EnvGCPProjectID, c.GCP.ProjectID)
+		require(EnvBigQueryDataset, c.BigQuery.Dataset)
+	}
+
+	switch kind {
+	case ServiceKindWorker, ServiceKindMediaDeletionWorker, ServiceKindAnalyticsWorker:
+		requirePositive(EnvPubSubMaxDeliveryAttempts, c.PubSub.MaxDeliveryAttempts)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid config: " + strings.Join(problems, "; "))
+}
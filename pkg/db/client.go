@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
@@ -16,7 +17,8 @@ import (
 
 // Client wraps the shared GORM connection.
 type Client struct {
-	conn *gorm.DB
+	conn    *gorm.DB
+	replica *gorm.DB
 }
 
 // Pinger exposes the health check surface.
@@ -30,8 +32,40 @@ func New(ctx context.Context, cfg config.DBConfig, logg *logger.Logger) (*Client
 		return nil, fmt.Errorf("database DSN is required")
 	}
 
+	conn, err := open(cfg.DSN, cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := &Client{conn: conn}
+
+	if cfg.ReplicaDSN != "" {
+		replica, err := open(cfg.ReplicaDSN, cfg)
+		if err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+		client.replica = replica
+		replicaDB, err := replica.DB()
+		if err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("getting replica sql db handle: %w", err)
+		}
+		if err := conn.Use(&replicaRouter{replica: replicaDB}); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("registering replica router: %w", err)
+		}
+	}
+
+	// if logg != nil {
+	// 	logg.Info(ctx, "database connection established")
+	// }
+
+	return client, nil
+}
+
+func open(dsn string, cfg config.DBConfig) (*gorm.DB, error) {
 	dialector := postgres.New(postgres.Config{
-		DSN:                  cfg.DSN,
+		DSN:                  dsn,
 		PreferSimpleProtocol: true,
 	})
 
@@ -56,27 +90,47 @@ func New(ctx context.Context, cfg config.DBConfig, logg *logger.Logger) (*Client
 	}
 
 	applyPoolSettings(sqlDB, cfg)
+	return conn, nil
+}
 
+func applyPoolSettings(sqlDB *sql.DB, cfg config.DBConfig) {
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
 	if cfg.ConnMaxLifetime > 0 {
 		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	}
 	if cfg.ConnMaxIdleTime > 0 {
 		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 	}
+}
 
-	// if logg != nil {
-	// 	logg.Info(ctx, "database connection established")
-	// }
-
-	return &Client{conn: conn}, nil
+// PoolStatsObserver receives periodic connection pool snapshots.
+type PoolStatsObserver interface {
+	ObservePoolStats(stats sql.DBStats)
 }
 
-func applyPoolSettings(sqlDB *sql.DB, cfg config.DBConfig) {
-	if cfg.MaxOpenConns > 0 {
-		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+// SamplePoolStats reports the pool stats to observer every interval until ctx is canceled.
+func (c *Client) SamplePoolStats(ctx context.Context, interval time.Duration, observer PoolStatsObserver) {
+	if observer == nil || interval <= 0 {
+		return
 	}
-	if cfg.MaxIdleConns > 0 {
-		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB, err := c.conn.DB()
+	if err != nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		observer.ObservePoolStats(sqlDB.Stats())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
@@ -85,13 +139,26 @@ func (c *Client) DB() *gorm.DB {
 	return c.conn
 }
 
-// Ping verifies the datasource is reachable.
+// Ping verifies the datasource, and the replica when configured, is reachable.
 func (c *Client) Ping(ctx context.Context) error {
 	sqlDB, err := c.conn.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.PingContext(ctx)
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return err
+	}
+	if c.replica == nil {
+		return nil
+	}
+	replicaDB, err := c.replica.DB()
+	if err != nil {
+		return err
+	}
+	if err := replicaDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("replica: %w", err)
+	}
+	return nil
 }
 
 // Close shuts down the pooled connections.
@@ -100,7 +167,15 @@ func (c *Client) Close() error {
 	if err != nil {
 		return err
 	}
-	return sqlDB.Close()
+	closeErr := sqlDB.Close()
+	if c.replica != nil {
+		if replicaDB, err := c.replica.DB(); err == nil {
+			if err := replicaDB.Close(); err != nil && closeErr == nil {
+				closeErr = err
+			}
+		}
+	}
+	return closeErr
 }
 
 // Exec wraps GORM's Exec with context propagation.
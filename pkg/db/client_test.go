@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -71,3 +74,76 @@ func TestPing(t *testing.T) {
 		t.Fatalf("unexpected ping error: %v", err)
 	}
 }
+
+func TestApplyPoolSettingsConfiguresSQLDB(t *testing.T) {
+	sqlDB, err := newTestDB(t).DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	applyPoolSettings(sqlDB, config.DBConfig{
+		MaxOpenConns:    4,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: time.Minute,
+	})
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 4 {
+		t.Fatalf("expected max open connections 4, got %d", got)
+	}
+
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			t.Fatalf("open conn: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil {
+			t.Fatalf("close conn: %v", err)
+		}
+	}
+	if got := sqlDB.Stats().Idle; got != 1 {
+		t.Fatalf("expected idle pool capped at 1, got %d", got)
+	}
+}
+
+type recordingPoolObserver struct {
+	stats chan sql.DBStats
+}
+
+func (r *recordingPoolObserver) ObservePoolStats(stats sql.DBStats) {
+	select {
+	case r.stats <- stats:
+	default:
+	}
+}
+
+func TestSamplePoolStatsReportsUntilCanceled(t *testing.T) {
+	client := &Client{conn: newTestDB(t)}
+	observer := &recordingPoolObserver{stats: make(chan sql.DBStats, 1)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.SamplePoolStats(ctx, 10*time.Millisecond, observer)
+		close(done)
+	}()
+
+	select {
+	case stats := <-observer.stats:
+		if stats.OpenConnections < 1 {
+			t.Fatalf("expected at least one open connection, got %d", stats.OpenConnections)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected pool stats to be reported")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected sampling to stop after cancel")
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// APIKey lets a store integration authenticate without a user session. Only the SHA-256 of
+// the secret is stored; the prefix is the public lookup handle.
+type APIKey struct {
+	ID              uuid.UUID      `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	StoreID         uuid.UUID      `gorm:"column:store_id;type:uuid;not null"`
+	CreatedByUserID uuid.UUID      `gorm:"column:created_by_user_id;type:uuid;not null"`
+	Name            string         `gorm:"column:name;type:text;not null"`
+	Prefix          string         `gorm:"column:prefix;type:text;not null"`
+	SecretHash      string         `gorm:"column:secret_hash;type:text;not null"`
+	Scopes          pq.StringArray `gorm:"column:scopes;type:text[];not null"`
+	LastUsedAt      *time.Time     `gorm:"column:last_used_at;type:timestamptz"`
+	RevokedAt       *time.Time     `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt       time.Time      `gorm:"column:created_at;type:timestamptz;not null;default:now()"`
+}
@@ -25,8 +25,11 @@ type CartRecord struct {
 	ValidUntil      time.Time            `gorm:"column:valid_until;not null"`
 	SubtotalCents   int                  `gorm:"column:subtotal_cents;not null;default:0"`
 	DiscountsCents  int                  `gorm:"column:discounts_cents;not null;default:0"`
+	TaxCents        int                  `gorm:"column:tax_cents;not null;default:0"`
 	TotalCents      int                  `gorm:"column:total_cents;not null;default:0"`
+	QuoteHash       string               `gorm:"column:quote_hash;not null;default:''"`
 	ConvertedAt     *time.Time           `gorm:"column:converted_at"`
+	SplitFromCartID *uuid.UUID           `gorm:"column:split_from_cart_id;type:uuid"`
 	AdTokens        pq.StringArray       `gorm:"column:ad_tokens;type:text[]"`
 	VendorGroups    []CartVendorGroup    `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
 	Items           []CartItem           `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
@@ -21,6 +21,8 @@ type CartVendorGroup struct {
 	LineDiscountsCents int                       `gorm:"column:line_discounts_cents;not null;default:0"`
 	PromoDiscountCents int                       `gorm:"column:promo_discount_cents;not null;default:0"`
 	DiscountsCents     int                       `gorm:"column:discounts_cents;not null;default:0"`
+	TaxCents           int                       `gorm:"column:tax_cents;not null;default:0"`
+	ShippingLine       *types.ShippingLine       `gorm:"column:shipping_line;type:jsonb;serializer:json"`
 	TotalCents         int                       `gorm:"column:total_cents;not null;default:0"`
 	CreatedAt          time.Time                 `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt          time.Time                 `gorm:"column:updated_at;autoUpdateTime"`
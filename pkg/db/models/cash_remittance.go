@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CashRemittance records an agent handing in the cash collected for one vendor order.
+type CashRemittance struct {
+	ID               uuid.UUID `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	OrderID          uuid.UUID `gorm:"column:order_id;type:uuid;not null"`
+	AgentUserID      uuid.UUID `gorm:"column:agent_user_id;type:uuid;not null"`
+	AmountCents      int       `gorm:"column:amount_cents;not null"`
+	RecordedByUserID uuid.UUID `gorm:"column:recorded_by_user_id;type:uuid;not null"`
+	RemittedAt       time.Time `gorm:"column:remitted_at;not null"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// CronJobRun is an admin request to run one cron job now. The API queues it and the cron worker
+// claims it, runs the job under the scheduler's lock, and records the outcome.
+type CronJobRun struct {
+	ID          uuid.UUID              `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	Job         string                 `gorm:"column:job;type:text;not null"`
+	Status      enums.CronJobRunStatus `gorm:"column:status;type:cron_job_run_status;not null;default:'queued'"`
+	DryRun      bool                   `gorm:"column:dry_run;not null;default:false"`
+	Error       *string                `gorm:"column:error;type:text"`
+	DurationMS  *int64                 `gorm:"column:duration_ms"`
+	RequestedBy *uuid.UUID             `gorm:"column:requested_by;type:uuid"`
+	StartedAt   *time.Time             `gorm:"column:started_at;type:timestamptz"`
+	FinishedAt  *time.Time             `gorm:"column:finished_at;type:timestamptz"`
+	CreatedAt   time.Time              `gorm:"column:created_at;type:timestamptz;not null;default:now()"`
+	UpdatedAt   time.Time              `gorm:"column:updated_at;type:timestamptz;not null;default:now()"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeatureFlag is a runtime override of a config-seeded flag. A nil StoreID applies to the
+// whole environment; a set StoreID applies to that store only.
+type FeatureFlag struct {
+	ID          uuid.UUID  `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	Key         string     `gorm:"column:key;type:text;not null"`
+	Environment string     `gorm:"column:environment;type:text;not null"`
+	StoreID     *uuid.UUID `gorm:"column:store_id;type:uuid"`
+	Enabled     bool       `gorm:"column:enabled;not null"`
+	CreatedAt   time.Time  `gorm:"column:created_at;type:timestamptz;not null;default:now()"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;type:timestamptz;not null;default:now()"`
+}
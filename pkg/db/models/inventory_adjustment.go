@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// InventoryAdjustment is an append-only audit row for a change to a product's inventory counts.
+type InventoryAdjustment struct {
+	ID             uuid.UUID                       `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	ProductID      uuid.UUID                       `gorm:"column:product_id;type:uuid;not null"`
+	AvailableDelta int                             `gorm:"column:available_delta;not null"`
+	ReservedDelta  int                             `gorm:"column:reserved_delta;not null"`
+	Reason         enums.InventoryAdjustmentReason `gorm:"column:reason;type:inventory_adjustment_reason;not null"`
+	ActorUserID    *uuid.UUID                      `gorm:"column:actor_user_id;type:uuid"`
+	ActorStoreID   *uuid.UUID                      `gorm:"column:actor_store_id;type:uuid"`
+	CreatedAt      time.Time                       `gorm:"column:created_at;autoCreateTime"`
+}
@@ -13,4 +13,5 @@ type InventoryItem struct {
 	ReservedQty       int       `gorm:"column:reserved_qty;not null;default:0"`
 	UpdatedAt         time.Time `gorm:"column:updated_at;autoUpdateTime"`
 	LowStockThreshold int       `gorm:"column:low_stock_threshold;not null;default:0"`
+	Version           int       `gorm:"column:version;not null;default:0"`
 }
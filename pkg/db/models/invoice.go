@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// Invoice records a settled Square subscription invoice for a store's billing history.
+type Invoice struct {
+	ID              uuid.UUID           `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	StoreID         uuid.UUID           `gorm:"column:store_id;type:uuid;not null;index"`
+	SubscriptionID  *uuid.UUID          `gorm:"column:subscription_id;type:uuid"`
+	SquareInvoiceID string              `gorm:"column:square_invoice_id;not null;unique"`
+	InvoiceNumber   *string             `gorm:"column:invoice_number"`
+	AmountCents     int64               `gorm:"column:amount_cents;not null"`
+	Currency        string              `gorm:"column:currency;not null;default:'USD'"`
+	Status          enums.InvoiceStatus `gorm:"column:status;type:invoice_status;not null;default:'paid'"`
+	PeriodStart     *time.Time          `gorm:"column:period_start"`
+	PeriodEnd       *time.Time          `gorm:"column:period_end"`
+	PaidAt          *time.Time          `gorm:"column:paid_at"`
+	PublicURL       *string             `gorm:"column:public_url"`
+	CreatedAt       time.Time           `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time           `gorm:"column:updated_at;autoUpdateTime"`
+}
@@ -30,4 +30,5 @@ type Media struct {
 	ReadyAt             *time.Time        `gorm:"column:ready_at"`
 	FailedAt            *time.Time        `gorm:"column:failed_at"`
 	DeletedAt           *time.Time        `gorm:"column:deleted_at"`
+	PurgeAfter          *time.Time        `gorm:"column:purge_after"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// MediaAccessLog is the append-only audit row written each time a download URL is issued for
+// protected media (license documents, manifests).
+type MediaAccessLog struct {
+	ID           uuid.UUID       `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	MediaID      uuid.UUID       `gorm:"column:media_id;type:uuid;not null"`
+	StoreID      uuid.UUID       `gorm:"column:store_id;type:uuid;not null"`
+	ActorUserID  *uuid.UUID      `gorm:"column:actor_user_id;type:uuid"`
+	ActorStoreID *uuid.UUID      `gorm:"column:actor_store_id;type:uuid"`
+	MediaKind    enums.MediaKind `gorm:"column:media_kind;type:media_kind;not null"`
+	Purpose      string          `gorm:"column:purpose;not null"`
+	ExpiresAt    time.Time       `gorm:"column:expires_at;not null"`
+	CreatedAt    time.Time       `gorm:"column:created_at;autoCreateTime"`
+}
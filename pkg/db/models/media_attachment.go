@@ -17,15 +17,17 @@ type MediaAttachment struct {
 }
 
 var ProtectedAttachmentEntities = map[string]struct{}{
-	AttachmentEntityLicense: {},
-	AttachmentEntityAd:      {},
+	AttachmentEntityLicense:             {},
+	AttachmentEntityAd:                  {},
+	AttachmentEntityStoreTaxCertificate: {},
 }
 
 const (
-	AttachmentEntityLicense        = "license"
-	AttachmentEntityAd             = "ad"
-	AttachmentEntityProductGallery = "product_gallery"
-	AttachmentEntityProductCOA     = "product_coa"
-	AttachmentEntityStoreLogo      = "store_logo"
-	AttachmentEntityStoreBanner    = "store_banner"
+	AttachmentEntityLicense             = "license"
+	AttachmentEntityAd                  = "ad"
+	AttachmentEntityProductGallery      = "product_gallery"
+	AttachmentEntityProductCOA          = "product_coa"
+	AttachmentEntityStoreLogo           = "store_logo"
+	AttachmentEntityStoreBanner         = "store_banner"
+	AttachmentEntityStoreTaxCertificate = "store_tax_certificate"
 )
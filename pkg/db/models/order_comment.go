@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+)
+
+// OrderComment is an order-level note, kept apart from line item notes. AuthorRole records the
+// side of the order the author wrote from (buyer, vendor, agent, or admin).
+type OrderComment struct {
+	ID            uuid.UUID                    `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	OrderID       uuid.UUID                    `gorm:"column:order_id;type:uuid;not null"`
+	AuthorUserID  uuid.UUID                    `gorm:"column:author_user_id;type:uuid;not null"`
+	AuthorStoreID *uuid.UUID                   `gorm:"column:author_store_id;type:uuid"`
+	AuthorRole    string                       `gorm:"column:author_role;not null"`
+	Visibility    enums.OrderCommentVisibility `gorm:"column:visibility;type:order_comment_visibility;not null;default:'shared'"`
+	Body          string                       `gorm:"column:body;not null"`
+	CreatedAt     time.Time                    `gorm:"column:created_at;autoCreateTime"`
+}
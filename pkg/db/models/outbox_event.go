@@ -20,4 +20,5 @@ type OutboxEvent struct {
 	PublishedAt   *time.Time                `gorm:"column:published_at"`
 	AttemptCount  int                       `gorm:"column:attempt_count;not null;default:0"`
 	LastError     *string                   `gorm:"column:last_error"`
+	DedupKey      *string                   `gorm:"column:dedup_key"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxExportCursor records the last published outbox event a named export has copied, so the next
+// run resumes after it.
+type OutboxExportCursor struct {
+	Name            string    `gorm:"column:name;primaryKey"`
+	LastPublishedAt time.Time `gorm:"column:last_published_at;not null"`
+	LastEventID     uuid.UUID `gorm:"column:last_event_id;type:uuid;not null"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,16 +32,29 @@ type Product struct {
 	Unit                enums.ProductUnit            `gorm:"column:unit;type:unit;not null"`
 	MOQ                 int                          `gorm:"column:moq;not null;default:1"`
 	PriceCents          int                          `gorm:"column:price_cents;not null"`
+	Currency            enums.Currency               `gorm:"column:currency;type:text;not null;default:'USD'"`
 	CompareAtPriceCents *int                         `gorm:"column:compare_at_price_cents"`
 	IsActive            bool                         `gorm:"column:is_active;not null;default:true"`
 	IsFeatured          bool                         `gorm:"column:is_featured;not null;default:false"`
 	THCPercent          *float64                     `gorm:"column:thc_percent;type:numeric(5,2)"`
 	CBDPercent          *float64                     `gorm:"column:cbd_percent;type:numeric(5,2)"`
 	MaxQty              int                          `gorm:"column:max_qty;not null;default:0"`
+	ExcludedStates      pq.StringArray               `gorm:"column:excluded_states;type:text[];not null;default:'{}'"`
 	Inventory           *InventoryItem               `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 	VolumeDiscounts     []ProductVolumeDiscount      `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 	Media               []ProductMedia               `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 	PackagingType       *string                      `gorm:"column:packaging_type"`
 	CreatedAt           time.Time                    `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt           time.Time                    `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt           *time.Time                   `gorm:"column:deleted_at"`
+}
+
+// IsExcludedIn reports whether the vendor hid the product from buyers in state.
+func (p Product) IsExcludedIn(state string) bool {
+	for _, excluded := range p.ExcludedStates {
+		if strings.EqualFold(excluded, state) {
+			return true
+		}
+	}
+	return false
 }
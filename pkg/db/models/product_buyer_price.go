@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductBuyerPrice overrides a product's base price for a single buyer store.
+type ProductBuyerPrice struct {
+	ID           uuid.UUID `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	StoreID      uuid.UUID `gorm:"column:store_id;type:uuid;not null"`
+	ProductID    uuid.UUID `gorm:"column:product_id;type:uuid;not null"`
+	BuyerStoreID uuid.UUID `gorm:"column:buyer_store_id;type:uuid;not null"`
+	PriceCents   int       `gorm:"column:price_cents;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
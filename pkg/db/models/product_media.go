@@ -14,6 +14,7 @@ type ProductMedia struct {
 	GCSKey    string     `gorm:"column:gcs_key;not null"`
 	MediaID   *uuid.UUID `gorm:"column:media_id;type:uuid"`
 	Position  int        `gorm:"column:position;not null;default:0"`
+	IsPrimary bool       `gorm:"column:is_primary;not null;default:false"`
 	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// StoreKYCStatusChange is the append-only audit row written whenever an admin moves a store's KYC status.
+type StoreKYCStatusChange struct {
+	ID          uuid.UUID       `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	StoreID     uuid.UUID       `gorm:"column:store_id;type:uuid;not null"`
+	AdminUserID uuid.UUID       `gorm:"column:admin_user_id;type:uuid;not null"`
+	FromStatus  enums.KYCStatus `gorm:"column:from_status;type:kyc_status;not null"`
+	ToStatus    enums.KYCStatus `gorm:"column:to_status;type:kyc_status;not null"`
+	Reason      *string         `gorm:"column:reason"`
+	CreatedAt   time.Time       `gorm:"column:created_at;autoCreateTime"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// StoreWebhook is an HTTPS endpoint a store registered to receive signed order events.
+type StoreWebhook struct {
+	ID                  uuid.UUID  `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	StoreID             uuid.UUID  `gorm:"column:store_id;type:uuid;not null"`
+	URL                 string     `gorm:"column:url;type:text;not null"`
+	Secret              string     `gorm:"column:secret;type:text;not null"`
+	ConsecutiveFailures int        `gorm:"column:consecutive_failures;not null;default:0"`
+	DisabledAt          *time.Time `gorm:"column:disabled_at;type:timestamptz"`
+	CreatedAt           time.Time  `gorm:"column:created_at;type:timestamptz;not null;default:now()"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;type:timestamptz;not null;default:now()"`
+}
+
+// StoreWebhookDelivery records one POST attempt of an event to a store webhook.
+type StoreWebhookDelivery struct {
+	ID          uuid.UUID `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	WebhookID   uuid.UUID `gorm:"column:webhook_id;type:uuid;not null"`
+	EventID     uuid.UUID `gorm:"column:event_id;type:uuid;not null"`
+	EventType   string    `gorm:"column:event_type;type:text;not null"`
+	Attempt     int       `gorm:"column:attempt;not null"`
+	StatusCode  *int      `gorm:"column:status_code"`
+	Error       *string   `gorm:"column:error;type:text"`
+	Succeeded   bool      `gorm:"column:succeeded;not null;default:false"`
+	AttemptedAt time.Time `gorm:"column:attempted_at;type:timestamptz;not null;default:now()"`
+}
+
+// WebhookDelivery is an event queued for a store webhook. It stays pending until a POST succeeds or
+// the attempts run out, so undelivered events survive worker restarts.
+type WebhookDelivery struct {
+	ID             uuid.UUID                   `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey"`
+	WebhookID      uuid.UUID                   `gorm:"column:webhook_id;type:uuid;not null"`
+	EventID        uuid.UUID                   `gorm:"column:event_id;type:uuid;not null"`
+	EventType      string                      `gorm:"column:event_type;type:text;not null"`
+	Payload        []byte                      `gorm:"column:payload;type:bytea;not null"`
+	Status         enums.WebhookDeliveryStatus `gorm:"column:status;type:webhook_delivery_status;not null;default:'pending'"`
+	Attempts       int                         `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt  time.Time                   `gorm:"column:next_attempt_at;type:timestamptz;not null"`
+	LastAttemptAt  *time.Time                  `gorm:"column:last_attempt_at;type:timestamptz"`
+	LastStatusCode *int                        `gorm:"column:last_status_code"`
+	LastError      *string                     `gorm:"column:last_error;type:text"`
+	DeliveredAt    *time.Time                  `gorm:"column:delivered_at;type:timestamptz"`
+	CreatedAt      time.Time                   `gorm:"column:created_at;type:timestamptz;not null;default:now()"`
+	UpdatedAt      time.Time                   `gorm:"column:updated_at;type:timestamptz;not null;default:now()"`
+}
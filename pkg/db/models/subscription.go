@@ -19,6 +19,7 @@ type Subscription struct {
 	SquareCardID         *string                  `gorm:"column:square_card_id"`
 	Status               enums.SubscriptionStatus `gorm:"column:status;type:subscription_status;not null;default:'active'"`
 	PriceID              *string                  `gorm:"column:price_id"`
+	IdempotencyKey       *string                  `gorm:"column:idempotency_key"`
 	PausedAt             *time.Time               `gorm:"column:paused_at"`
 	PauseEffectiveAt     *time.Time               `gorm:"column:pause_effective_at"`
 	ResumeEffectiveAt    *time.Time               `gorm:"column:resume_effective_at"`
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionCoupon is a promo code that discounts a subscription's recurring price. Exactly one of
+// PercentOff or AmountOffCents is set.
+type SubscriptionCoupon struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Code            string     `gorm:"column:code;not null"`
+	PercentOff      *int       `gorm:"column:percent_off"`
+	AmountOffCents  *int64     `gorm:"column:amount_off_cents"`
+	Active          bool       `gorm:"column:active;not null;default:true"`
+	MaxRedemptions  *int       `gorm:"column:max_redemptions"`
+	RedemptionCount int        `gorm:"column:redemption_count;not null;default:0"`
+	ExpiresAt       *time.Time `gorm:"column:expires_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// SubscriptionCouponRedemption records a coupon applied to a store's subscription.
+type SubscriptionCouponRedemption struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CouponID       uuid.UUID  `gorm:"column:coupon_id;type:uuid;not null;index"`
+	StoreID        uuid.UUID  `gorm:"column:store_id;type:uuid;not null"`
+	SubscriptionID *uuid.UUID `gorm:"column:subscription_id;type:uuid"`
+	DiscountCents  int64      `gorm:"column:discount_cents;not null"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type readOnlyKey struct{}
+
+// ReadOnly marks ctx so queries run with it may be served by the read replica. Writes and
+// anything inside a transaction still go to the primary.
+func ReadOnly(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx was marked with ReadOnly.
+func IsReadOnly(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// replicaRouter is a GORM plugin that sends read-only queries to the replica pool.
+type replicaRouter struct {
+	replica gorm.ConnPool
+}
+
+func (r *replicaRouter) Name() string {
+	return "packfinderz:replica_router"
+}
+
+func (r *replicaRouter) Initialize(conn *gorm.DB) error {
+	if err := conn.Callback().Query().Before("gorm:query").Register("packfinderz:route_query", r.route); err != nil {
+		return err
+	}
+	return conn.Callback().Row().Before("gorm:row").Register("packfinderz:route_row", r.route)
+}
+
+func (r *replicaRouter) route(conn *gorm.DB) {
+	stmt := conn.Statement
+	if r.replica == nil || !IsReadOnly(stmt.Context) {
+		return
+	}
+	if _, inTx := stmt.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	stmt.ConnPool = r.replica
+}
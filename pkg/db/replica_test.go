@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openNamedTestDB(t *testing.T, name, row string) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite %s: %v", name, err)
+	}
+	if err := conn.AutoMigrate(&testModel{}); err != nil {
+		t.Fatalf("migrate sqlite %s: %v", name, err)
+	}
+	if err := conn.Create(&testModel{Name: row}).Error; err != nil {
+		t.Fatalf("seed sqlite %s: %v", name, err)
+	}
+	return conn
+}
+
+func newReplicaTestClient(t *testing.T) *Client {
+	t.Helper()
+	primary := openNamedTestDB(t, "replica_test_primary", "primary")
+	replica := openNamedTestDB(t, "replica_test_replica", "replica")
+	replicaDB, err := replica.DB()
+	if err != nil {
+		t.Fatalf("replica sql db: %v", err)
+	}
+	if err := primary.Use(&replicaRouter{replica: replicaDB}); err != nil {
+		t.Fatalf("register replica router: %v", err)
+	}
+	return &Client{conn: primary, replica: replica}
+}
+
+func TestReplicaRouterSendsReadOnlyQueriesToReplica(t *testing.T) {
+	client := newReplicaTestClient(t)
+
+	var row testModel
+	if err := client.DB().WithContext(ReadOnly(context.Background())).First(&row).Error; err != nil {
+		t.Fatalf("read-only query: %v", err)
+	}
+	if row.Name != "replica" {
+		t.Fatalf("expected read-only query served by replica, got %q", row.Name)
+	}
+
+	var name string
+	if err := client.DB().WithContext(ReadOnly(context.Background())).Raw("SELECT name FROM test_models LIMIT 1").Row().Scan(&name); err != nil {
+		t.Fatalf("read-only raw query: %v", err)
+	}
+	if name != "replica" {
+		t.Fatalf("expected read-only raw query served by replica, got %q", name)
+	}
+
+	row = testModel{}
+	if err := client.DB().WithContext(context.Background()).First(&row).Error; err != nil {
+		t.Fatalf("unmarked query: %v", err)
+	}
+	if row.Name != "primary" {
+		t.Fatalf("expected unmarked query served by primary, got %q", row.Name)
+	}
+}
+
+func TestReplicaRouterKeepsTransactionsOnPrimary(t *testing.T) {
+	client := newReplicaTestClient(t)
+	ctx := ReadOnly(context.Background())
+
+	var row testModel
+	if err := client.WithTx(ctx, func(tx *gorm.DB) error {
+		return tx.First(&row).Error
+	}); err != nil {
+		t.Fatalf("transaction query: %v", err)
+	}
+	if row.Name != "primary" {
+		t.Fatalf("expected transaction served by primary, got %q", row.Name)
+	}
+
+	if err := client.DB().WithContext(ctx).Create(&testModel{Name: "written"}).Error; err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var count int64
+	if err := client.DB().Model(&testModel{}).Where("name = ?", "written").Count(&count).Error; err != nil {
+		t.Fatalf("count on primary: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected write to land on primary, got %d rows", count)
+	}
+}
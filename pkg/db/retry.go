@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+const (
+	txRetryAttempts  = 4
+	txRetryBaseDelay = 20 * time.Millisecond
+	txRetryMaxDelay  = 200 * time.Millisecond
+)
+
+// retryableSQLStates are failures Postgres expects callers to retry: serialization_failure and
+// deadlock_detected.
+var retryableSQLStates = map[string]struct{}{
+	"40001": {},
+	"40P01": {},
+}
+
+// IsRetryable reports whether err is a transient Postgres failure that a fresh transaction may
+// not hit again.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		_, ok := retryableSQLStates[pgxErr.Code]
+		return ok
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		_, ok := retryableSQLStates[string(pqErr.Code)]
+		return ok
+	}
+	return false
+}
+
+// RetryTransient runs fn until it succeeds, fails with a non-retryable error, or runs out of
+// attempts, backing off exponentially between tries. fn must be safe to run again from the start,
+// which in practice means it wraps a whole transaction.
+func RetryTransient(ctx context.Context, fn func() error) error {
+	delay := txRetryBaseDelay
+	var err error
+	for attempt := 1; attempt <= txRetryAttempts; attempt++ {
+		err = fn()
+		if !IsRetryable(err) || attempt == txRetryAttempts {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay = min(delay*2, txRetryMaxDelay)
+	}
+	return err
+}
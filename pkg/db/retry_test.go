@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "pgx serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "pgx deadlock", err: fmt.Errorf("reserve: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "pq serialization failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+	for _, tc := range cases {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Fatalf("%s: expected %t, got %t", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRetryTransientRerunsSerializationFailedTransaction(t *testing.T) {
+	db := newTestDB(t)
+	client := &Client{conn: db}
+	ctx := context.Background()
+
+	attempts := 0
+	err := RetryTransient(ctx, func() error {
+		return client.WithTx(ctx, func(tx *gorm.DB) error {
+			attempts++
+			if err := tx.Create(&testModel{Name: fmt.Sprintf("attempt-%d", attempts)}).Error; err != nil {
+				return err
+			}
+			if attempts == 1 {
+				return &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+
+	var names []string
+	if err := db.Model(&testModel{}).Where("name LIKE ?", "attempt-%").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("load rows: %v", err)
+	}
+	if len(names) != 1 || names[0] != "attempt-2" {
+		t.Fatalf("expected only the retried attempt to commit, got %v", names)
+	}
+}
+
+func TestRetryTransientStopsOnPermanentErrorAndAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	permanent := errors.New("boom")
+	if err := RetryTransient(context.Background(), func() error {
+		attempts++
+		return permanent
+	}); !errors.Is(err, permanent) || attempts != 1 {
+		t.Fatalf("expected a single attempt for a permanent error, got %d (err=%v)", attempts, err)
+	}
+
+	attempts = 0
+	if err := RetryTransient(context.Background(), func() error {
+		attempts++
+		return &pgconn.PgError{Code: "40P01"}
+	}); !IsRetryable(err) || attempts != txRetryAttempts {
+		t.Fatalf("expected %d attempts ending in the deadlock error, got %d (err=%v)", txRetryAttempts, attempts, err)
+	}
+}
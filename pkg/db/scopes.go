@@ -0,0 +1,11 @@
+package db
+
+import "gorm.io/gorm"
+
+// NotDeleted hides soft-deleted rows. column is the deleted_at column, qualified with the table
+// alias when the query joins, e.g. "p.deleted_at".
+func NotDeleted(column string) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		return q.Where(column + " IS NULL")
+	}
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type scopedModel struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt *time.Time
+}
+
+func TestNotDeletedHidesSoftDeletedRows(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:scopes_test?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := conn.AutoMigrate(&scopedModel{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	deletedAt := time.Now().UTC()
+	rows := []scopedModel{{Name: "live"}, {Name: "gone", DeletedAt: &deletedAt}}
+	if err := conn.Create(&rows).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	var visible []scopedModel
+	if err := conn.Scopes(NotDeleted("deleted_at")).Find(&visible).Error; err != nil {
+		t.Fatalf("list visible: %v", err)
+	}
+	if len(visible) != 1 || visible[0].Name != "live" {
+		t.Fatalf("expected only live row, got %+v", visible)
+	}
+
+	var all []scopedModel
+	if err := conn.Find(&all).Error; err != nil {
+		t.Fatalf("list unscoped: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected deleted row without the scope, got %+v", all)
+	}
+}
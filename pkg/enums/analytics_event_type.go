@@ -15,6 +15,7 @@ const (
 	AnalyticsEventAdImpression          AnalyticsEventType = "ad_impression"
 	AnalyticsEventAdClick               AnalyticsEventType = "ad_click"
 	AnalyticsEventAdDailyChargeRecorded AnalyticsEventType = "ad_daily_charge_recorded"
+	AnalyticsEventInventoryChanged      AnalyticsEventType = "inventory_changed"
 )
 
 var validAnalyticsEventTypes = []AnalyticsEventType{
@@ -27,6 +28,7 @@ var validAnalyticsEventTypes = []AnalyticsEventType{
 	AnalyticsEventAdImpression,
 	AnalyticsEventAdClick,
 	AnalyticsEventAdDailyChargeRecorded,
+	AnalyticsEventInventoryChanged,
 }
 
 // IsValid reports whether the value matches the canonical analytics event_type enum.
@@ -0,0 +1,45 @@
+package enums
+
+import "fmt"
+
+// APIKeyScope limits which endpoints a store API key may call.
+type APIKeyScope string
+
+const (
+	APIKeyScopeOrdersRead    APIKeyScope = "orders:read"
+	APIKeyScopeOrdersWrite   APIKeyScope = "orders:write"
+	APIKeyScopeProductsRead  APIKeyScope = "products:read"
+	APIKeyScopeProductsWrite APIKeyScope = "products:write"
+)
+
+var validAPIKeyScopes = []APIKeyScope{
+	APIKeyScopeOrdersRead,
+	APIKeyScopeOrdersWrite,
+	APIKeyScopeProductsRead,
+	APIKeyScopeProductsWrite,
+}
+
+// String implements fmt.Stringer.
+func (s APIKeyScope) String() string {
+	return string(s)
+}
+
+// IsValid reports whether the value is a known APIKeyScope.
+func (s APIKeyScope) IsValid() bool {
+	for _, candidate := range validAPIKeyScopes {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAPIKeyScope converts raw input into an APIKeyScope.
+func ParseAPIKeyScope(value string) (APIKeyScope, error) {
+	for _, candidate := range validAPIKeyScopes {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid api key scope %q", value)
+}
@@ -0,0 +1,45 @@
+package enums
+
+import "fmt"
+
+// CronJobRunStatus tracks a manually requested cron job run from the API to the cron worker.
+type CronJobRunStatus string
+
+const (
+	CronJobRunStatusQueued    CronJobRunStatus = "queued"
+	CronJobRunStatusRunning   CronJobRunStatus = "running"
+	CronJobRunStatusSucceeded CronJobRunStatus = "succeeded"
+	CronJobRunStatusFailed    CronJobRunStatus = "failed"
+)
+
+var validCronJobRunStatuses = []CronJobRunStatus{
+	CronJobRunStatusQueued,
+	CronJobRunStatusRunning,
+	CronJobRunStatusSucceeded,
+	CronJobRunStatusFailed,
+}
+
+// String implements fmt.Stringer.
+func (s CronJobRunStatus) String() string {
+	return string(s)
+}
+
+// IsValid reports whether the value is known.
+func (s CronJobRunStatus) IsValid() bool {
+	for _, candidate := range validCronJobRunStatuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCronJobRunStatus converts raw input into a CronJobRunStatus.
+func ParseCronJobRunStatus(value string) (CronJobRunStatus, error) {
+	for _, candidate := range validCronJobRunStatuses {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid cron job run status %q", value)
+}
@@ -0,0 +1,38 @@
+package enums
+
+import "fmt"
+
+// InventoryAdjustmentReason maps to the inventory_adjustment_reason enum in Postgres.
+type InventoryAdjustmentReason string
+
+const (
+	InventoryAdjustmentReasonCheckoutReserve InventoryAdjustmentReason = "checkout_reserve"
+	InventoryAdjustmentReasonOrderRelease    InventoryAdjustmentReason = "order_release"
+	InventoryAdjustmentReasonVendorEdit      InventoryAdjustmentReason = "vendor_edit"
+)
+
+var validInventoryAdjustmentReasons = []InventoryAdjustmentReason{
+	InventoryAdjustmentReasonCheckoutReserve,
+	InventoryAdjustmentReasonOrderRelease,
+	InventoryAdjustmentReasonVendorEdit,
+}
+
+// IsValid checks whether the given reason matches the canonical enum.
+func (r InventoryAdjustmentReason) IsValid() bool {
+	for _, candidate := range validInventoryAdjustmentReasons {
+		if candidate == r {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseInventoryAdjustmentReason converts raw strings into InventoryAdjustmentReason.
+func ParseInventoryAdjustmentReason(value string) (InventoryAdjustmentReason, error) {
+	for _, candidate := range validInventoryAdjustmentReasons {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid inventory adjustment reason %q", value)
+}
@@ -6,10 +6,11 @@ import "fmt"
 type LedgerEventType string
 
 const (
-	LedgerEventTypeCashCollected LedgerEventType = "cash_collected"
-	LedgerEventTypeVendorPayout  LedgerEventType = "vendor_payout"
-	LedgerEventTypeAdjustment    LedgerEventType = "adjustment"
-	LedgerEventTypeRefund        LedgerEventType = "refund"
+	LedgerEventTypeCashCollected    LedgerEventType = "cash_collected"
+	LedgerEventTypeVendorPayout     LedgerEventType = "vendor_payout"
+	LedgerEventTypeAdjustment       LedgerEventType = "adjustment"
+	LedgerEventTypeRefund           LedgerEventType = "refund"
+	LedgerEventTypeAgentDeliveryFee LedgerEventType = "agent_delivery_fee"
 )
 
 var validLedgerEventTypes = []LedgerEventType{
@@ -17,6 +18,7 @@ var validLedgerEventTypes = []LedgerEventType{
 	LedgerEventTypeVendorPayout,
 	LedgerEventTypeAdjustment,
 	LedgerEventTypeRefund,
+	LedgerEventTypeAgentDeliveryFee,
 }
 
 // IsValid reports whether the value matches the canonical ledger event enum.
@@ -6,11 +6,12 @@ import "fmt"
 type LineItemStatus string
 
 const (
-	LineItemStatusPending   LineItemStatus = "pending"
-	LineItemStatusAccepted  LineItemStatus = "accepted"
-	LineItemStatusRejected  LineItemStatus = "rejected"
-	LineItemStatusFulfilled LineItemStatus = "fulfilled"
-	LineItemStatusHold      LineItemStatus = "hold"
+	LineItemStatusPending             LineItemStatus = "pending"
+	LineItemStatusAccepted            LineItemStatus = "accepted"
+	LineItemStatusRejected            LineItemStatus = "rejected"
+	LineItemStatusFulfilled           LineItemStatus = "fulfilled"
+	LineItemStatusHold                LineItemStatus = "hold"
+	LineItemStatusPendingSubstitution LineItemStatus = "pending_substitution"
 )
 
 var validLineItemStatuses = []LineItemStatus{
@@ -19,6 +20,7 @@ var validLineItemStatuses = []LineItemStatus{
 	LineItemStatusRejected,
 	LineItemStatusFulfilled,
 	LineItemStatusHold,
+	LineItemStatusPendingSubstitution,
 }
 
 // String implements fmt.Stringer.
@@ -14,6 +14,8 @@ const (
 	MediaStatusDeleteRequested MediaStatus = "delete_requested"
 	MediaStatusDeleted         MediaStatus = "deleted"
 	MediaStatusDeleteFailed    MediaStatus = "delete_failed"
+	// MediaStatusPendingDeletion keeps deleted media recoverable until its purge_after passes.
+	MediaStatusPendingDeletion MediaStatus = "pending_deletion"
 )
 
 var validMediaStatuses = []MediaStatus{
@@ -25,6 +27,7 @@ var validMediaStatuses = []MediaStatus{
 	MediaStatusDeleteRequested,
 	MediaStatusDeleted,
 	MediaStatusDeleteFailed,
+	MediaStatusPendingDeletion,
 }
 
 // String returns the literal string for the status.
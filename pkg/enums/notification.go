@@ -11,6 +11,8 @@ const (
 	NotificationTypeSecurityAlert      NotificationType = "security_alert"
 	NotificationTypeOrderAlert         NotificationType = "order_alert"
 	NotificationTypeCompliance         NotificationType = "compliance"
+	NotificationTypeLowStock           NotificationType = "low_stock"
+	NotificationTypeSubscriptionAlert  NotificationType = "subscription_alert"
 )
 
 var validNotificationTypes = []NotificationType{
@@ -19,6 +21,8 @@ var validNotificationTypes = []NotificationType{
 	NotificationTypeSecurityAlert,
 	NotificationTypeOrderAlert,
 	NotificationTypeCompliance,
+	NotificationTypeLowStock,
+	NotificationTypeSubscriptionAlert,
 }
 
 // IsValid checks whether the given type matches the canonical enum.
@@ -0,0 +1,42 @@
+package enums
+
+import "fmt"
+
+// OrderCommentVisibility controls who can read an order comment. Internal comments are hidden
+// from the buyer; shared comments are visible to every party on the order.
+type OrderCommentVisibility string
+
+const (
+	OrderCommentVisibilityInternal OrderCommentVisibility = "internal"
+	OrderCommentVisibilityShared   OrderCommentVisibility = "shared"
+)
+
+var validOrderCommentVisibilities = []OrderCommentVisibility{
+	OrderCommentVisibilityInternal,
+	OrderCommentVisibilityShared,
+}
+
+// String implements fmt.Stringer.
+func (v OrderCommentVisibility) String() string {
+	return string(v)
+}
+
+// IsValid reports whether the value is a known OrderCommentVisibility.
+func (v OrderCommentVisibility) IsValid() bool {
+	for _, candidate := range validOrderCommentVisibilities {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOrderCommentVisibility converts raw input into an OrderCommentVisibility.
+func ParseOrderCommentVisibility(value string) (OrderCommentVisibility, error) {
+	for _, candidate := range validOrderCommentVisibilities {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid order comment visibility %q", value)
+}
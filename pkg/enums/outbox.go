@@ -14,6 +14,7 @@ const (
 	AggregateLedgerEvent   OutboxAggregateType = "ledger_event"
 	AggregateNotification  OutboxAggregateType = "notification"
 	AggregateAd            OutboxAggregateType = "ad"
+	AggregateProduct       OutboxAggregateType = "product"
 )
 
 var validAggregateTypes = []OutboxAggregateType{
@@ -25,6 +26,7 @@ var validAggregateTypes = []OutboxAggregateType{
 	AggregateLedgerEvent,
 	AggregateNotification,
 	AggregateAd,
+	AggregateProduct,
 }
 
 // IsValid reports whether the value matches the canonical aggregate_type enum.
@@ -79,6 +81,8 @@ const (
 	EventAdExpired             OutboxEventType = "ad_expired"
 	EventAdDailyRollupReady    OutboxEventType = "ad_daily_rollup_ready"
 	EventCheckoutConverted     OutboxEventType = "checkout_converted"
+	EventStoreKYCStatusChanged OutboxEventType = "store_kyc_status_changed"
+	EventInventoryChanged      OutboxEventType = "inventory_changed"
 )
 
 var validOutboxEventTypes = []OutboxEventType{
@@ -110,6 +114,8 @@ var validOutboxEventTypes = []OutboxEventType{
 	EventAdExpired,
 	EventAdDailyRollupReady,
 	EventCheckoutConverted,
+	EventStoreKYCStatusChanged,
+	EventInventoryChanged,
 }
 
 // IsValid reports whether the value matches the canonical event_type enum.
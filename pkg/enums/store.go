@@ -45,6 +45,7 @@ type KYCStatus string
 
 const (
 	KYCStatusPendingVerification KYCStatus = "pending_verification"
+	KYCStatusUnderReview         KYCStatus = "under_review"
 	KYCStatusVerified            KYCStatus = "verified"
 	KYCStatusRejected            KYCStatus = "rejected"
 	KYCStatusExpired             KYCStatus = "expired"
@@ -53,6 +54,7 @@ const (
 
 var validKYCStatuses = []KYCStatus{
 	KYCStatusPendingVerification,
+	KYCStatusUnderReview,
 	KYCStatusVerified,
 	KYCStatusRejected,
 	KYCStatusExpired,
@@ -6,10 +6,12 @@ import "fmt"
 type VendorGroupWarningType string
 
 const (
-	VendorGroupWarningTypeVendorInvalid   VendorGroupWarningType = "vendor_invalid"
-	VendorGroupWarningTypeVendorSuspended VendorGroupWarningType = "vendor_suspended"
-	VendorGroupWarningTypeLicenseInvalid  VendorGroupWarningType = "license_invalid"
-	VendorGroupWarningTypeInvalidPromo    VendorGroupWarningType = "invalid_promo"
+	VendorGroupWarningTypeVendorInvalid    VendorGroupWarningType = "vendor_invalid"
+	VendorGroupWarningTypeVendorSuspended  VendorGroupWarningType = "vendor_suspended"
+	VendorGroupWarningTypeLicenseInvalid   VendorGroupWarningType = "license_invalid"
+	VendorGroupWarningTypeInvalidPromo     VendorGroupWarningType = "invalid_promo"
+	VendorGroupWarningTypeOutOfZone        VendorGroupWarningType = "out_of_zone"
+	VendorGroupWarningTypePromoNotCombined VendorGroupWarningType = "promo_not_combined"
 )
 
 var validVendorGroupWarningTypes = []VendorGroupWarningType{
@@ -17,6 +19,8 @@ var validVendorGroupWarningTypes = []VendorGroupWarningType{
 	VendorGroupWarningTypeVendorSuspended,
 	VendorGroupWarningTypeLicenseInvalid,
 	VendorGroupWarningTypeInvalidPromo,
+	VendorGroupWarningTypeOutOfZone,
+	VendorGroupWarningTypePromoNotCombined,
 }
 
 // String implements fmt.Stringer.
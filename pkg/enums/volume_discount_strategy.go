@@ -0,0 +1,43 @@
+package enums
+
+import "fmt"
+
+// VolumeDiscountStrategy controls which volume tier applies when several qualify.
+type VolumeDiscountStrategy string
+
+const (
+	// VolumeDiscountStrategyHighestMinQty applies the qualifying tier with the largest min_qty.
+	VolumeDiscountStrategyHighestMinQty VolumeDiscountStrategy = "highest_min_qty"
+	// VolumeDiscountStrategyLowestPrice applies the qualifying tier that yields the lowest unit price.
+	VolumeDiscountStrategyLowestPrice VolumeDiscountStrategy = "lowest_price"
+)
+
+var validVolumeDiscountStrategies = []VolumeDiscountStrategy{
+	VolumeDiscountStrategyHighestMinQty,
+	VolumeDiscountStrategyLowestPrice,
+}
+
+// String implements fmt.Stringer.
+func (v VolumeDiscountStrategy) String() string {
+	return string(v)
+}
+
+// IsValid reports whether the value is known.
+func (v VolumeDiscountStrategy) IsValid() bool {
+	for _, candidate := range validVolumeDiscountStrategies {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseVolumeDiscountStrategy converts raw input into a VolumeDiscountStrategy.
+func ParseVolumeDiscountStrategy(value string) (VolumeDiscountStrategy, error) {
+	for _, candidate := range validVolumeDiscountStrategies {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid volume discount strategy %q", value)
+}
@@ -0,0 +1,43 @@
+package enums
+
+import "fmt"
+
+// WebhookDeliveryStatus tracks a persisted outbound webhook delivery through its retries.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+var validWebhookDeliveryStatuses = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusDelivered,
+	WebhookDeliveryStatusFailed,
+}
+
+// String implements fmt.Stringer.
+func (s WebhookDeliveryStatus) String() string {
+	return string(s)
+}
+
+// IsValid reports whether the value is known.
+func (s WebhookDeliveryStatus) IsValid() bool {
+	for _, candidate := range validWebhookDeliveryStatuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWebhookDeliveryStatus converts raw input into a WebhookDeliveryStatus.
+func ParseWebhookDeliveryStatus(value string) (WebhookDeliveryStatus, error) {
+	for _, candidate := range validWebhookDeliveryStatuses {
+		if string(candidate) == value {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("invalid webhook delivery status %q", value)
+}
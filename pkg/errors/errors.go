@@ -19,6 +19,8 @@ const (
 	CodeRateLimit     Code = "RATE_LIMIT_EXCEEDED"
 	CodeInternal      Code = "INTERNAL_ERROR"
 	CodeDependency    Code = "DEPENDENCY_ERROR"
+	CodeTimeout       Code = "REQUEST_TIMEOUT"
+	CodeTooLarge      Code = "PAYLOAD_TOO_LARGE"
 )
 
 type Metadata struct {
@@ -89,6 +91,18 @@ var metadataByCode = map[Code]Metadata{
 		PublicMessage:  "dependency unavailable",
 		DetailsAllowed: true,
 	},
+	CodeTimeout: {
+		HTTPStatus:     http.StatusGatewayTimeout,
+		Retryable:      true,
+		PublicMessage:  "request timed out",
+		DetailsAllowed: false,
+	},
+	CodeTooLarge: {
+		HTTPStatus:     http.StatusRequestEntityTooLarge,
+		Retryable:      false,
+		PublicMessage:  "request body too large",
+		DetailsAllowed: true,
+	},
 }
 
 func MetadataFor(code Code) Metadata {
@@ -102,6 +116,7 @@ type Error struct {
 	code    Code
 	message string
 	details any
+	fields  map[string]string
 	cause   error
 }
 
@@ -109,6 +124,12 @@ func New(code Code, message string) *Error {
 	return &Error{code: code, message: message}
 }
 
+// NewValidationError builds a CodeValidation error that names each failing request field
+// (JSON key or query parameter) alongside a human-readable message.
+func NewValidationError(message string, fields map[string]string) *Error {
+	return New(CodeValidation, message).WithFieldErrors(fields)
+}
+
 func Wrap(code Code, err error, message string) *Error {
 	if err == nil {
 		return New(code, message)
@@ -145,6 +166,28 @@ func (e *Error) WithDetails(details any) *Error {
 	return e
 }
 
+// FieldErrors returns the per-field validation messages, if any.
+func (e *Error) FieldErrors() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.fields
+}
+
+// WithFieldErrors attaches per-field validation messages, merging with any already present.
+func (e *Error) WithFieldErrors(fields map[string]string) *Error {
+	if e == nil || len(fields) == 0 {
+		return e
+	}
+	if e.fields == nil {
+		e.fields = make(map[string]string, len(fields))
+	}
+	for field, msg := range fields {
+		e.fields[field] = msg
+	}
+	return e
+}
+
 func (e *Error) Error() string {
 	if e == nil {
 		return ""
@@ -22,6 +22,8 @@ func TestMetadataForKnownCodes(t *testing.T) {
 		{code: CodeStateConflict, status: http.StatusUnprocessableEntity, publicMsg: "state transition disallowed", detailsOK: true},
 		{code: CodeInternal, status: http.StatusInternalServerError, publicMsg: "internal server error", retryable: true},
 		{code: CodeDependency, status: http.StatusServiceUnavailable, publicMsg: "dependency unavailable", retryable: true, detailsOK: true},
+		{code: CodeTimeout, status: http.StatusGatewayTimeout, publicMsg: "request timed out", retryable: true},
+		{code: CodeTooLarge, status: http.StatusRequestEntityTooLarge, publicMsg: "request body too large", detailsOK: true},
 	}
 
 	for _, tt := range tests {
@@ -85,3 +87,18 @@ func TestAsReturnsTypedError(t *testing.T) {
 		t.Fatalf("As(nil) should return nil")
 	}
 }
+
+func TestNewValidationErrorCarriesFieldErrors(t *testing.T) {
+	err := NewValidationError("validation failed", map[string]string{"email": "is required"}).
+		WithFieldErrors(map[string]string{"name": "must be at least 2"})
+	if err.Code() != CodeValidation {
+		t.Fatalf("expected validation code, got %s", err.Code())
+	}
+	fields := err.FieldErrors()
+	if len(fields) != 2 || fields["email"] != "is required" || fields["name"] != "must be at least 2" {
+		t.Fatalf("unexpected field errors %v", fields)
+	}
+	if New(CodeValidation, "plain").FieldErrors() != nil {
+		t.Fatalf("field errors should be nil by default")
+	}
+}
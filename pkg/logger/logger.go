@@ -17,15 +17,47 @@ type Options struct {
 	Level       zerolog.Level
 	WarnStack   bool
 	Output      io.Writer
+	// RedactKeys masks field values whose key contains any of these (case-insensitive).
+	// Nil uses DefaultRedactKeys; an empty, non-nil slice disables redaction.
+	RedactKeys []string
+	// DebugSampleEvery emits one in every N debug entries; 0 or 1 keeps them all. Warnings and
+	// errors are never sampled.
+	DebugSampleEvery uint32
 }
 
+// DefaultRedactKeys lists the field-key fragments masked when Options.RedactKeys is nil.
+var DefaultRedactKeys = []string{"password", "secret", "authorization", "token"}
+
+const redactedValue = "***"
+
 type Logger struct {
-	base      *zerolog.Logger
-	warnStack bool
+	base       *zerolog.Logger
+	warnStack  bool
+	redactKeys []string
 }
 
 type ctxKey struct{}
 
+type requestIDKey struct{}
+
+// ContextWithRequestID stores the correlation ID for the current request so
+// non-logging code (e.g. outbox emitters) can read it back.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request correlation ID, or "" when none is set.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
 func New(opts Options) *Logger {
 	if opts.Level == zerolog.NoLevel {
 		opts.Level = zerolog.InfoLevel
@@ -53,10 +85,27 @@ func New(opts Options) *Logger {
 		Str("service", opts.ServiceName).
 		Logger().
 		Level(opts.Level)
+	if opts.DebugSampleEvery > 1 {
+		logger = logger.Sample(zerolog.LevelSampler{
+			DebugSampler: &zerolog.BasicSampler{N: opts.DebugSampleEvery},
+		})
+	}
+
+	redactKeys := opts.RedactKeys
+	if redactKeys == nil {
+		redactKeys = DefaultRedactKeys
+	}
+	normalized := make([]string, 0, len(redactKeys))
+	for _, key := range redactKeys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			normalized = append(normalized, key)
+		}
+	}
 
 	return &Logger{
-		base:      &logger,
-		warnStack: opts.WarnStack,
+		base:       &logger,
+		warnStack:  opts.WarnStack,
+		redactKeys: normalized,
 	}
 }
 
@@ -78,6 +127,10 @@ func (l *Logger) loggerFromContext(ctx context.Context) *zerolog.Logger {
 	if entry, ok := ctx.Value(ctxKey{}).(*zerolog.Logger); ok {
 		return entry
 	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		entry := l.base.With().Str("request_id", requestID).Logger()
+		return &entry
+	}
 	return l.base
 }
 
@@ -88,19 +141,47 @@ func (l *Logger) attach(ctx context.Context, entry zerolog.Logger) context.Conte
 
 func (l *Logger) WithField(ctx context.Context, key string, value any) context.Context {
 	entry := l.loggerFromContext(ctx)
-	return l.attach(ctx, entry.With().Interface(key, value).Logger())
+	return l.attach(ctx, entry.With().Interface(key, l.redact(key, value)).Logger())
 }
 
 func (l *Logger) WithFields(ctx context.Context, fields map[string]any) context.Context {
 	entry := l.loggerFromContext(ctx)
 	builder := entry.With()
 	for k, v := range fields {
-		builder = builder.Interface(k, v)
+		builder = builder.Interface(k, l.redact(k, v))
 	}
 	return l.attach(ctx, builder.Logger())
 }
 
+// redact masks value when key matches the redaction list, descending into nested field maps.
+func (l *Logger) redact(key string, value any) any {
+	if l.isRedacted(key) {
+		return redactedValue
+	}
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	masked := make(map[string]any, len(nested))
+	for k, v := range nested {
+		masked[k] = l.redact(k, v)
+	}
+	return masked
+}
+
+func (l *Logger) isRedacted(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range l.redactKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRequestID records the request ID on the context and tags every subsequent log entry with it.
 func (l *Logger) WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = ContextWithRequestID(ctx, requestID)
 	return l.WithField(ctx, "request_id", requestID)
 }
 
@@ -116,6 +197,10 @@ func (l *Logger) WithActorRole(ctx context.Context, role string) context.Context
 	return l.WithField(ctx, "actor_role", role)
 }
 
+func (l *Logger) Debug(ctx context.Context, msg string) {
+	l.loggerFromContext(ctx).Debug().Msg(msg)
+}
+
 func (l *Logger) Info(ctx context.Context, msg string) {
 	l.loggerFromContext(ctx).Info().Msg(msg)
 }
@@ -44,3 +44,79 @@ func TestParseLevelDefaults(t *testing.T) {
 		t.Fatalf("invalid level should fallback to info, got %v", lvl)
 	}
 }
+
+func TestLoggerIncludesRequestIDFromBareContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := New(Options{ServiceName: "test", Output: buf})
+
+	ctx := ContextWithRequestID(context.Background(), "req-456")
+	ctx = log.WithFields(ctx, map[string]any{"order_id": "o-1"})
+	log.Info(ctx, "hello")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"request_id":"req-456"`)) {
+		t.Fatalf("expected request_id on entry; entry=%s", buf.String())
+	}
+	if got := RequestIDFromContext(ctx); got != "req-456" {
+		t.Fatalf("expected request id to survive WithFields, got %q", got)
+	}
+}
+
+func TestLoggerRedactsSensitiveFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := New(Options{ServiceName: "test", Output: buf})
+
+	ctx := log.WithFields(context.Background(), map[string]any{
+		"password": "hunter2",
+		"order_id": "o-1",
+		"request":  map[string]any{"Authorization": "Bearer abc", "path": "/v1/orders"},
+	})
+	ctx = log.WithField(ctx, "access_token", "tok-123")
+	log.Info(ctx, "hello")
+
+	for _, leaked := range []string{"hunter2", "Bearer abc", "tok-123"} {
+		if bytes.Contains(buf.Bytes(), []byte(leaked)) {
+			t.Fatalf("expected %q to be redacted; entry=%s", leaked, buf.String())
+		}
+	}
+	for _, want := range []string{`"password":"***"`, `"access_token":"***"`, `"Authorization":"***"`, `"order_id":"o-1"`, `"path":"/v1/orders"`} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Fatalf("expected %s in entry=%s", want, buf.String())
+		}
+	}
+}
+
+func TestLoggerCustomRedactKeys(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := New(Options{ServiceName: "test", Output: buf, RedactKeys: []string{"ssn"}})
+
+	ctx := log.WithFields(context.Background(), map[string]any{"customer_ssn": "123-45-6789", "token": "visible"})
+	log.Info(ctx, "hello")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"customer_ssn":"***"`)) {
+		t.Fatalf("expected custom key redacted; entry=%s", buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"token":"visible"`)) {
+		t.Fatalf("expected defaults replaced by custom keys; entry=%s", buf.String())
+	}
+}
+
+func TestLoggerSamplesDebugButNotErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := New(Options{ServiceName: "test", Level: zerolog.DebugLevel, Output: buf, DebugSampleEvery: 10})
+	ctx := log.WithField(context.Background(), "consumer", "orders")
+
+	for i := 0; i < 100; i++ {
+		log.Debug(ctx, "message received")
+	}
+	if got := bytes.Count(buf.Bytes(), []byte(`"message received"`)); got != 10 {
+		t.Fatalf("expected 10 of 100 debug entries, got %d", got)
+	}
+
+	buf.Reset()
+	for i := 0; i < 100; i++ {
+		log.Error(ctx, "publish failed", errors.New("boom"))
+	}
+	if got := bytes.Count(buf.Bytes(), []byte(`"publish failed"`)); got != 100 {
+		t.Fatalf("expected every error entry, got %d", got)
+	}
+}
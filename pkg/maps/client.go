@@ -16,10 +16,12 @@ import (
 )
 
 const (
-	defaultBaseURL              = "https://places.googleapis.com/v1"
-	autocompleteFieldMask       = "suggestions.placePrediction.placeId,suggestions.placePrediction.text"
-	placeResolveFieldMask       = "id,formattedAddress,location,addressComponents"
-	requestBodyReadLimit  int64 = 1024
+	defaultBaseURL               = "https://places.googleapis.com/v1"
+	defaultRoutesBaseURL         = "https://routes.googleapis.com"
+	computeRoutesFieldMask       = "routes.distanceMeters,routes.duration"
+	autocompleteFieldMask        = "suggestions.placePrediction.placeId,suggestions.placePrediction.text"
+	placeResolveFieldMask        = "id,formattedAddress,location,addressComponents"
+	requestBodyReadLimit   int64 = 1024
 )
 
 var (
@@ -28,9 +30,10 @@ var (
 
 // Client wraps the Google Maps Places APIs used for address guidance.
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	apiKey     string
+	httpClient    *http.Client
+	baseURL       string
+	routesBaseURL string
+	apiKey        string
 }
 
 // Option configures optional client behavior.
@@ -55,6 +58,16 @@ func WithBaseURL(baseURL string) Option {
 	}
 }
 
+// WithRoutesBaseURL overrides the configured Routes base URL.
+func WithRoutesBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		trimmed := strings.TrimSpace(baseURL)
+		if trimmed != "" {
+			c.routesBaseURL = trimmed
+		}
+	}
+}
+
 // NewClient builds the Google Maps client given an API key.
 func NewClient(apiKey string, opts ...Option) (*Client, error) {
 	trimmedKey := strings.TrimSpace(apiKey)
@@ -63,9 +76,10 @@ func NewClient(apiKey string, opts ...Option) (*Client, error) {
 	}
 
 	client := &Client{
-		apiKey:     trimmedKey,
-		baseURL:    defaultBaseURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiKey:        trimmedKey,
+		baseURL:       defaultBaseURL,
+		routesBaseURL: defaultRoutesBaseURL,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
 	}
 
 	for _, opt := range opts {
@@ -80,6 +94,9 @@ func NewClient(apiKey string, opts ...Option) (*Client, error) {
 	if client.baseURL == "" {
 		client.baseURL = defaultBaseURL
 	}
+	if client.routesBaseURL == "" {
+		client.routesBaseURL = defaultRoutesBaseURL
+	}
 
 	return client, nil
 }
@@ -246,6 +263,92 @@ func (c *Client) ResolvePlace(ctx context.Context, placeID string) (*PlaceDetail
 	}, nil
 }
 
+// Route summarizes the driving route between two points.
+type Route struct {
+	DistanceMeters int
+	Duration       time.Duration
+}
+
+// ComputeRoute asks the Routes API for the driving distance and duration between two coordinates.
+func (c *Client) ComputeRoute(ctx context.Context, origin, destination LatLng) (*Route, error) {
+	if c == nil {
+		return nil, pkgerrors.New(pkgerrors.CodeDependency, "google maps client not configured")
+	}
+
+	type waypoint struct {
+		Location struct {
+			LatLng struct {
+				Latitude  float64 `json:"latitude"`
+				Longitude float64 `json:"longitude"`
+			} `json:"latLng"`
+		} `json:"location"`
+	}
+	toWaypoint := func(point LatLng) waypoint {
+		var w waypoint
+		w.Location.LatLng.Latitude = point.Latitude
+		w.Location.LatLng.Longitude = point.Longitude
+		return w
+	}
+
+	payload, err := json.Marshal(struct {
+		Origin      waypoint `json:"origin"`
+		Destination waypoint `json:"destination"`
+		TravelMode  string   `json:"travelMode"`
+	}{
+		Origin:      toWaypoint(origin),
+		Destination: toWaypoint(destination),
+		TravelMode:  "DRIVE",
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "marshal compute routes request")
+	}
+
+	url := fmt.Sprintf("%s/directions/v2:computeRoutes", strings.TrimRight(c.routesBaseURL, "/"))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "build compute routes request")
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Goog-Api-Key", c.apiKey)
+	httpReq.Header.Set("X-Goog-FieldMask", computeRoutesFieldMask)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "execute compute routes request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, requestBodyReadLimit))
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg))), "compute routes request failed")
+	}
+
+	var apiResp struct {
+		Routes []struct {
+			DistanceMeters int    `json:"distanceMeters"`
+			Duration       string `json:"duration"`
+		} `json:"routes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "decode compute routes response")
+	}
+	if len(apiResp.Routes) == 0 {
+		return nil, pkgerrors.New(pkgerrors.CodeNotFound, "no driving route found")
+	}
+
+	// Durations are encoded as seconds with an "s" suffix, e.g. "1234s".
+	duration, err := time.ParseDuration(apiResp.Routes[0].Duration)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.CodeDependency, err, "parse route duration")
+	}
+
+	return &Route{
+		DistanceMeters: apiResp.Routes[0].DistanceMeters,
+		Duration:       duration,
+	}, nil
+}
+
 func (c *Client) buildURL(path string) string {
 	trimmed := strings.TrimRight(c.baseURL, "/")
 	path = strings.TrimLeft(path, "/")
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestClientAutocompleteRequest(t *testing.T) {
@@ -114,6 +115,60 @@ func TestClientResolvePlaceRequest(t *testing.T) {
 	}
 }
 
+func TestClientComputeRouteRequest(t *testing.T) {
+	const expectedURL = "http://routes.test/directions/v2:computeRoutes"
+	respBody := `{"routes":[{"distanceMeters":15234,"duration":"1260s"}]}`
+
+	var capturedURL string
+	var capturedHeaders http.Header
+
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		capturedURL = req.URL.String()
+		capturedHeaders = req.Header.Clone()
+
+		bodyBytes, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(bodyBytes, &payload); err != nil {
+			t.Fatalf("unmarshal request body: %v", err)
+		}
+		if payload["travelMode"] != "DRIVE" {
+			t.Fatalf("unexpected travel mode %q", payload["travelMode"])
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(respBody)),
+			Header:     http.Header{},
+		}, nil
+	})
+
+	httpClient := &http.Client{Transport: rt}
+	client, err := NewClient("test-key", WithRoutesBaseURL("http://routes.test"), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	route, err := client.ComputeRoute(context.Background(), LatLng{Latitude: 36.15, Longitude: -95.99}, LatLng{Latitude: 36.05, Longitude: -95.88})
+	if err != nil {
+		t.Fatalf("compute route: %v", err)
+	}
+	if capturedURL != expectedURL {
+		t.Fatalf("unexpected URL %q", capturedURL)
+	}
+	if capturedHeaders.Get("X-Goog-FieldMask") != computeRoutesFieldMask {
+		t.Fatalf("unexpected field mask %q", capturedHeaders.Get("X-Goog-FieldMask"))
+	}
+	if route.DistanceMeters != 15234 {
+		t.Fatalf("unexpected distance %d", route.DistanceMeters)
+	}
+	if route.Duration != 21*time.Minute {
+		t.Fatalf("unexpected duration %s", route.Duration)
+	}
+}
+
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
 func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
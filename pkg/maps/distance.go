@@ -0,0 +1,19 @@
+package maps
+
+import "math"
+
+const earthRadiusMeters = 6371008.8
+
+// MetersPerMile converts straight-line distances into miles for pricing.
+const MetersPerMile = 1609.344
+
+// DistanceMeters returns the great-circle distance between two coordinates using the haversine formula.
+func DistanceMeters(from, to LatLng) float64 {
+	lat1 := from.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// ConsumerMetrics records Pub/Sub consumer outcomes.
+type ConsumerMetrics struct {
+	exhausted *prometheus.CounterVec
+}
+
+// NewConsumerMetrics registers the consumer metrics on the provided registerer.
+func NewConsumerMetrics(reg prometheus.Registerer) *ConsumerMetrics {
+	if reg == nil {
+		return &ConsumerMetrics{}
+	}
+	exhausted := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pubsub_messages_exhausted_total",
+		Help: "Messages acked without processing after hitting the max delivery attempts.",
+	}, []string{"consumer"})
+	reg.MustRegister(exhausted)
+	return &ConsumerMetrics{exhausted: exhausted}
+}
+
+// IncExhausted increments the exhausted counter for the named consumer.
+func (c *ConsumerMetrics) IncExhausted(consumer string) {
+	if c == nil || c.exhausted == nil {
+		return
+	}
+	c.exhausted.WithLabelValues(normalizeLabel(consumer)).Inc()
+}
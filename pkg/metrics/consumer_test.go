@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestConsumerMetricsCountsExhaustedMessages(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewConsumerMetrics(reg)
+	metrics.IncExhausted("media")
+	metrics.IncExhausted("media")
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if got, err := fetchCounterValue(mfs, "pubsub_messages_exhausted_total", "consumer", "media"); err != nil {
+		t.Fatalf("fetch exhausted: %v", err)
+	} else if got != 2 {
+		t.Fatalf("expected exhausted=2, got %f", got)
+	}
+}
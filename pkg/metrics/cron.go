@@ -8,9 +8,10 @@ import (
 
 // CronJobMetrics records metadata for scheduled jobs.
 type CronJobMetrics struct {
-	duration *prometheus.HistogramVec
-	success  *prometheus.CounterVec
-	failure  *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	success     *prometheus.CounterVec
+	failure     *prometheus.CounterVec
+	lastSuccess *prometheus.GaugeVec
 }
 
 // NewCronJobMetrics registers the cron job metrics on the provided registerer.
@@ -31,11 +32,16 @@ func NewCronJobMetrics(reg prometheus.Registerer) *CronJobMetrics {
 		Name: "job_failure",
 		Help: "Failed cron job executions.",
 	}, []string{"job"})
-	reg.MustRegister(duration, success, failure)
+	lastSuccess := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "job_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful cron job execution.",
+	}, []string{"job"})
+	reg.MustRegister(duration, success, failure, lastSuccess)
 	return &CronJobMetrics{
-		duration: duration,
-		success:  success,
-		failure:  failure,
+		duration:    duration,
+		success:     success,
+		failure:     failure,
+		lastSuccess: lastSuccess,
 	}
 }
 
@@ -63,6 +69,14 @@ func (c *CronJobMetrics) IncFailure(job string) {
 	c.failure.WithLabelValues(normalizeLabel(job)).Inc()
 }
 
+// SetLastSuccess records when the named job last completed successfully.
+func (c *CronJobMetrics) SetLastSuccess(job string, at time.Time) {
+	if c == nil || c.lastSuccess == nil {
+		return
+	}
+	c.lastSuccess.WithLabelValues(normalizeLabel(job)).Set(float64(at.Unix()))
+}
+
 func normalizeLabel(job string) string {
 	if job == "" {
 		return "unknown"
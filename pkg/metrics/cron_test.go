@@ -41,6 +41,36 @@ func TestCronJobMetricsExportsCountersAndHistogram(t *testing.T) {
 	}
 }
 
+func TestCronJobMetricsExportsLastSuccessTimestamp(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewCronJobMetrics(reg)
+	at := time.Unix(1700000000, 0)
+	metrics.SetLastSuccess("test-job", at)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if got, err := fetchGaugeValue(mfs, "job_last_success_timestamp_seconds", "job", "test-job"); err != nil {
+		t.Fatalf("fetch last success: %v", err)
+	} else if got != float64(at.Unix()) {
+		t.Fatalf("expected last success=%d, got %f", at.Unix(), got)
+	}
+}
+
+func fetchGaugeValue(mfs []*dto.MetricFamily, name, label, value string) (float64, error) {
+	mf := findMetricFamily(mfs, name)
+	if mf == nil {
+		return 0, fmt.Errorf("metric %q not found", name)
+	}
+	for _, metric := range mf.GetMetric() {
+		if matchesLabel(metric.GetLabel(), label, value) {
+			return metric.GetGauge().GetValue(), nil
+		}
+	}
+	return 0, fmt.Errorf("gauge %q missing label %s=%s", name, label, value)
+}
+
 func fetchCounterValue(mfs []*dto.MetricFamily, name, label, value string) (float64, error) {
 	mf := findMetricFamily(mfs, name)
 	if mf == nil {
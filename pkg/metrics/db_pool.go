@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBPoolMetrics exports snapshots of the database connection pool.
+type DBPoolMetrics struct {
+	open         prometheus.Gauge
+	inUse        prometheus.Gauge
+	idle         prometheus.Gauge
+	waitCount    prometheus.Gauge
+	waitDuration prometheus.Gauge
+}
+
+// NewDBPoolMetrics registers the connection pool gauges on the provided registerer.
+func NewDBPoolMetrics(reg prometheus.Registerer) *DBPoolMetrics {
+	if reg == nil {
+		return &DBPoolMetrics{}
+	}
+	open := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_open_connections",
+		Help: "Established database connections, in use or idle.",
+	})
+	inUse := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_in_use_connections",
+		Help: "Database connections currently in use.",
+	})
+	idle := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_idle_connections",
+		Help: "Idle database connections.",
+	})
+	waitCount := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_wait_count",
+		Help: "Total connections waited for since the pool opened.",
+	})
+	waitDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_pool_wait_duration_seconds",
+		Help: "Total time spent waiting for a connection since the pool opened.",
+	})
+	reg.MustRegister(open, inUse, idle, waitCount, waitDuration)
+	return &DBPoolMetrics{
+		open:         open,
+		inUse:        inUse,
+		idle:         idle,
+		waitCount:    waitCount,
+		waitDuration: waitDuration,
+	}
+}
+
+// ObservePoolStats records one pool snapshot.
+func (m *DBPoolMetrics) ObservePoolStats(stats sql.DBStats) {
+	if m == nil || m.open == nil {
+		return
+	}
+	m.open.Set(float64(stats.OpenConnections))
+	m.inUse.Set(float64(stats.InUse))
+	m.idle.Set(float64(stats.Idle))
+	m.waitCount.Set(float64(stats.WaitCount))
+	m.waitDuration.Set(stats.WaitDuration.Seconds())
+}
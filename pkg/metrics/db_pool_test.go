@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestDBPoolMetricsExportsSnapshot(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewDBPoolMetrics(reg)
+	metrics.ObservePoolStats(sql.DBStats{
+		OpenConnections: 5,
+		InUse:           3,
+		Idle:            2,
+		WaitCount:       7,
+		WaitDuration:    1500 * time.Millisecond,
+	})
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for name, want := range map[string]float64{
+		"db_pool_open_connections":      5,
+		"db_pool_in_use_connections":    3,
+		"db_pool_idle_connections":      2,
+		"db_pool_wait_count":            7,
+		"db_pool_wait_duration_seconds": 1.5,
+	} {
+		mf := findMetricFamily(mfs, name)
+		if mf == nil || len(mf.GetMetric()) != 1 {
+			t.Fatalf("expected metric %s", name)
+		}
+		if got := mf.GetMetric()[0].GetGauge().GetValue(); got != want {
+			t.Fatalf("expected %s=%v, got %v", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics records API traffic by chi route pattern and status class.
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTPMetrics registers the HTTP request metrics on the provided registerer.
+func NewHTTPMetrics(reg prometheus.Registerer) *HTTPMetrics {
+	if reg == nil {
+		return &HTTPMetrics{}
+	}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by route pattern, method, and status class.",
+	}, []string{"route", "method", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	// The route is only known once chi has matched the request, so in-flight is not labeled.
+	inFlight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+	reg.MustRegister(requests, duration, inFlight)
+	return &HTTPMetrics{
+		requests: requests,
+		duration: duration,
+		inFlight: inFlight,
+	}
+}
+
+// RequestStarted increments the in-flight gauge.
+func (h *HTTPMetrics) RequestStarted() {
+	if h == nil || h.inFlight == nil {
+		return
+	}
+	h.inFlight.Inc()
+}
+
+// RequestFinished decrements the in-flight gauge and records the completed request.
+func (h *HTTPMetrics) RequestFinished(route, method string, status int, duration time.Duration) {
+	if h == nil || h.requests == nil {
+		return
+	}
+	h.inFlight.Dec()
+	labels := []string{normalizeRoute(route), method, statusClass(status)}
+	h.requests.WithLabelValues(labels...).Inc()
+	h.duration.WithLabelValues(labels...).Observe(duration.Seconds())
+}
+
+// normalizeRoute keeps label cardinality bounded: unmatched requests share one series
+// instead of one per raw path.
+func normalizeRoute(route string) string {
+	if route == "" {
+		return "unmatched"
+	}
+	return route
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
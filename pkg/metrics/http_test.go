@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHTTPMetricsGroupsStatusClassesAndUnmatchedRoutes(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewHTTPMetrics(reg)
+
+	metrics.RequestStarted()
+	metrics.RequestFinished("/api/v1/orders/{orderId}", http.MethodGet, http.StatusNotFound, 10*time.Millisecond)
+	metrics.RequestStarted()
+	metrics.RequestFinished("", http.MethodGet, http.StatusNotFound, time.Millisecond)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	if got, err := fetchCounterValue(mfs, "http_requests_total", "status", "4xx"); err != nil {
+		t.Fatalf("fetch requests: %v", err)
+	} else if got != 1 {
+		t.Fatalf("expected 4xx count of 1 per route, got %f", got)
+	}
+	if _, err := fetchCounterValue(mfs, "http_requests_total", "route", "unmatched"); err != nil {
+		t.Fatalf("expected unmatched route series: %v", err)
+	}
+	if gauge := findMetricFamily(mfs, "http_requests_in_flight"); gauge == nil || gauge.GetMetric()[0].GetGauge().GetValue() != 0 {
+		t.Fatalf("expected in-flight gauge back at zero")
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	cases := map[int]string{200: "2xx", 201: "2xx", 302: "3xx", 422: "4xx", 504: "5xx", 0: "unknown"}
+	for status, want := range cases {
+		if got := statusClass(status); got != want {
+			t.Fatalf("status %d: expected %s got %s", status, want, got)
+		}
+	}
+}
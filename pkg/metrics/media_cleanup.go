@@ -0,0 +1,46 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// MediaCleanupMetrics records what the pending media cleanup job finds and removes.
+type MediaCleanupMetrics struct {
+	scanned *prometheus.CounterVec
+	deleted *prometheus.CounterVec
+	skipped *prometheus.CounterVec
+}
+
+// NewMediaCleanupMetrics registers the media cleanup metrics on the provided registerer.
+func NewMediaCleanupMetrics(reg prometheus.Registerer) *MediaCleanupMetrics {
+	if reg == nil {
+		return &MediaCleanupMetrics{}
+	}
+	scanned := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pending_media_scanned",
+		Help: "Stale pending media rows found by the cleanup job.",
+	}, []string{"job"})
+	deleted := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pending_media_deleted",
+		Help: "Stale pending media rows deleted by the cleanup job.",
+	}, []string{"job"})
+	skipped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pending_media_skipped",
+		Help: "Stale pending media rows left alone because they stopped being pending.",
+	}, []string{"job"})
+	reg.MustRegister(scanned, deleted, skipped)
+	return &MediaCleanupMetrics{
+		scanned: scanned,
+		deleted: deleted,
+		skipped: skipped,
+	}
+}
+
+// ObservePendingMediaCleanup adds the counts from one cleanup run.
+func (m *MediaCleanupMetrics) ObservePendingMediaCleanup(job string, scanned, deleted, skipped int) {
+	if m == nil || m.scanned == nil {
+		return
+	}
+	job = normalizeLabel(job)
+	m.scanned.WithLabelValues(job).Add(float64(scanned))
+	m.deleted.WithLabelValues(job).Add(float64(deleted))
+	m.skipped.WithLabelValues(job).Add(float64(skipped))
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMediaCleanupMetricsAccumulatesRuns(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := NewMediaCleanupMetrics(reg)
+	metrics.ObservePendingMediaCleanup("pending-media-cleanup", 3, 2, 1)
+	metrics.ObservePendingMediaCleanup("pending-media-cleanup", 4, 4, 0)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for name, want := range map[string]float64{
+		"pending_media_scanned": 7,
+		"pending_media_deleted": 6,
+		"pending_media_skipped": 1,
+	} {
+		got, err := fetchCounterValue(mfs, name, "job", "pending-media-cleanup")
+		if err != nil {
+			t.Fatalf("fetch %s: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("expected %s=%v, got %v", name, want, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/pressly/goose/v3"
 )
 
@@ -32,6 +33,26 @@ func Run(ctx context.Context, db *sql.DB, dir string, command string, args ...st
 	return nil
 }
 
+// commandRunner executes a single goose command.
+type commandRunner func(ctx context.Context, command string) error
+
+// Redo rolls back the latest migration and reapplies it. It refuses to run in production.
+func Redo(ctx context.Context, db *sql.DB, dir string, app config.AppConfig) error {
+	if app.IsProd() {
+		return fmt.Errorf("redo is not allowed in %s", app.Env)
+	}
+	return redo(ctx, func(ctx context.Context, command string) error {
+		return Run(ctx, db, dir, command)
+	})
+}
+
+func redo(ctx context.Context, run commandRunner) error {
+	if err := run(ctx, "down"); err != nil {
+		return err
+	}
+	return run(ctx, "up-by-one")
+}
+
 // MigrateToVersion migrates up/down to the requested version by comparing current DB version.
 func MigrateToVersion(ctx context.Context, db *sql.DB, dir string, targetVersion string) error {
 	if targetVersion == "" {
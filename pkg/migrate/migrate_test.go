@@ -0,0 +1,49 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/config"
+)
+
+func TestRedoRunsDownThenUpByOne(t *testing.T) {
+	var commands []string
+	err := redo(context.Background(), func(_ context.Context, command string) error {
+		commands = append(commands, command)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("redo: %v", err)
+	}
+	if want := []string{"down", "up-by-one"}; !reflect.DeepEqual(commands, want) {
+		t.Fatalf("expected %v, got %v", want, commands)
+	}
+}
+
+func TestRedoStopsWhenDownFails(t *testing.T) {
+	var commands []string
+	downErr := errors.New("down failed")
+	err := redo(context.Background(), func(_ context.Context, command string) error {
+		commands = append(commands, command)
+		if command == "down" {
+			return downErr
+		}
+		return nil
+	})
+	if !errors.Is(err, downErr) {
+		t.Fatalf("expected down error, got %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("expected up to be skipped, got %v", commands)
+	}
+}
+
+func TestRedoRejectsProduction(t *testing.T) {
+	err := Redo(context.Background(), nil, DefaultDir, config.AppConfig{Env: config.AppEnvProd})
+	if err == nil {
+		t.Fatalf("expected redo to be rejected in production")
+	}
+}
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'under_review'
+      AND enumtypid = 'kyc_status'::regtype
+  ) THEN
+    ALTER TYPE kyc_status ADD VALUE 'under_review';
+  END IF;
+END$$;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'store_kyc_status_changed'
+      AND enumtypid = 'event_type_enum'::regtype
+  ) THEN
+    ALTER TYPE event_type_enum ADD VALUE 'store_kyc_status_changed';
+  END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS store_kyc_status_changes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id uuid NOT NULL REFERENCES stores (id) ON DELETE CASCADE,
+    admin_user_id uuid NOT NULL REFERENCES users (id),
+    from_status kyc_status NOT NULL,
+    to_status kyc_status NOT NULL,
+    reason text,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS store_kyc_status_changes_store_created_idx
+    ON store_kyc_status_changes (store_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS store_kyc_status_changes;
+
+-- Enum values (kyc_status.under_review, event_type_enum.store_kyc_status_changed) are irreversible.
+-- +goose StatementEnd
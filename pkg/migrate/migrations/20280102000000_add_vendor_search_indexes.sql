@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS stores_vendor_discovery_idx
+  ON stores (created_at DESC, id DESC)
+  WHERE type = 'vendor' AND kyc_status = 'verified' AND subscription_active = true;
+CREATE INDEX IF NOT EXISTS stores_company_name_lower_idx
+  ON stores (LOWER(company_name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS stores_address_state_lower_idx
+  ON stores (LOWER((address).state));
+CREATE INDEX IF NOT EXISTS idx_products_store_category_active
+  ON products (store_id, category)
+  WHERE is_active = true;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_store_category_active;
+DROP INDEX IF EXISTS stores_address_state_lower_idx;
+DROP INDEX IF EXISTS stores_company_name_lower_idx;
+DROP INDEX IF EXISTS stores_vendor_discovery_idx;
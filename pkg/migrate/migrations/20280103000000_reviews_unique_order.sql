@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE UNIQUE INDEX IF NOT EXISTS reviews_order_id_unique_idx
+  ON reviews (order_id)
+  WHERE order_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS reviews_vendor_visible_rating_idx
+  ON reviews (vendor_store_id, rating)
+  WHERE is_visible = true;
+
+-- +goose Down
+DROP INDEX IF EXISTS reviews_vendor_visible_rating_idx;
+DROP INDEX IF EXISTS reviews_order_id_unique_idx;
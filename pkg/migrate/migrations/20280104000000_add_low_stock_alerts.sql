@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'low_stock'
+      AND enumtypid = 'notification_type'::regtype
+  ) THEN
+    ALTER TYPE notification_type ADD VALUE 'low_stock';
+  END IF;
+END$$;
+
+ALTER TABLE inventory_items
+ADD COLUMN IF NOT EXISTS low_stock_alerted_at timestamptz NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE inventory_items
+DROP COLUMN IF EXISTS low_stock_alerted_at;
+
+-- Removing the low_stock notification_type value is irreversible
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'inventory_adjustment_reason') THEN
+    CREATE TYPE inventory_adjustment_reason AS ENUM (
+      'checkout_reserve',
+      'order_release',
+      'vendor_edit'
+    );
+  END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS inventory_adjustments (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id uuid NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  available_delta integer NOT NULL,
+  reserved_delta integer NOT NULL,
+  reason inventory_adjustment_reason NOT NULL,
+  actor_user_id uuid NULL REFERENCES users(id) ON DELETE SET NULL,
+  actor_store_id uuid NULL REFERENCES stores(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustments_product_created
+  ON inventory_adjustments (product_id, created_at DESC, id DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS inventory_adjustments;
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'inventory_adjustment_reason') THEN
+    DROP TYPE inventory_adjustment_reason;
+  END IF;
+END$$;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE inventory_items
+ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE inventory_items
+DROP COLUMN IF EXISTS version;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+ADD COLUMN IF NOT EXISTS delivery_distance_meters integer,
+ADD COLUMN IF NOT EXISTS delivery_duration_seconds integer;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+DROP COLUMN IF EXISTS delivery_duration_seconds,
+DROP COLUMN IF EXISTS delivery_distance_meters;
+
+-- +goose StatementEnd
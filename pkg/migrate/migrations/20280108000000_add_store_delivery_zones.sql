@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+ADD COLUMN IF NOT EXISTS delivery_zones jsonb;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'out_of_zone'
+      AND enumtypid = 'vendor_group_warning_type'::regtype
+  ) THEN
+    ALTER TYPE vendor_group_warning_type ADD VALUE 'out_of_zone';
+  END IF;
+END$$;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- The out_of_zone enum value is left in place because removing enum values is irreversible.
+ALTER TABLE stores
+DROP COLUMN IF EXISTS delivery_zones;
+
+-- +goose StatementEnd
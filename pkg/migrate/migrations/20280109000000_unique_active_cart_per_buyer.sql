@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Only the newest active cart is ever read back, so older duplicates are unreachable and safe to drop.
+DELETE FROM cart_records c
+USING cart_records newer
+WHERE c.buyer_store_id = newer.buyer_store_id
+  AND c.status = 'active'
+  AND newer.status = 'active'
+  AND (newer.created_at, newer.id) > (c.created_at, c.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cart_records_buyer_active
+  ON cart_records (buyer_store_id)
+  WHERE status = 'active';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ux_cart_records_buyer_active;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'USD';
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'USD';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS currency;
+
+-- +goose StatementEnd
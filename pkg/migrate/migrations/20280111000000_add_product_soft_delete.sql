@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_products_store_live
+  ON products (store_id, created_at DESC)
+  WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_products_store_live;
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS search_vector tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(subtitle, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(strain, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(body_html, '')), 'C')
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector
+  ON products USING GIN (search_vector);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS search_vector;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'agent_delivery_fee'
+      AND enumtypid = 'ledger_event_type_enum'::regtype
+  ) THEN
+    ALTER TYPE ledger_event_type_enum ADD VALUE 'agent_delivery_fee';
+  END IF;
+END$$;
+
+CREATE INDEX IF NOT EXISTS ledger_events_actor_type_created_idx ON ledger_events (actor_user_id, type, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ledger_events_actor_type_created_idx;
+
+-- Removing the agent_delivery_fee enum value is irreversible.
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS cash_remittances (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  order_id uuid NOT NULL,
+  agent_user_id uuid NOT NULL,
+  amount_cents integer NOT NULL,
+  recorded_by_user_id uuid NOT NULL,
+  remitted_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT cash_remittances_order_fk FOREIGN KEY (order_id) REFERENCES vendor_orders(id) ON DELETE RESTRICT,
+  CONSTRAINT cash_remittances_agent_fk FOREIGN KEY (agent_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+  CONSTRAINT cash_remittances_recorded_by_fk FOREIGN KEY (recorded_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_cash_remittances_order ON cash_remittances (order_id);
+CREATE INDEX IF NOT EXISTS idx_cash_remittances_agent_remitted ON cash_remittances (agent_user_id, remitted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_cash_remittances_agent_remitted;
+DROP INDEX IF EXISTS ux_cash_remittances_order;
+DROP TABLE IF EXISTS cash_remittances;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE inventory_items
+ADD COLUMN IF NOT EXISTS low_stock_alert_threshold integer NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE inventory_items
+DROP COLUMN IF EXISTS low_stock_alert_threshold;
+
+-- +goose StatementEnd
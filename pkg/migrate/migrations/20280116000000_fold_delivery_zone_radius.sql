@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Delivery zones now carry states only; the radius lives on delivery_radius_meters.
+UPDATE stores
+SET delivery_radius_meters = ROUND((delivery_zones->>'radius_miles')::numeric * 1609.344)::int
+WHERE delivery_zones ? 'radius_miles'
+  AND (delivery_zones->>'radius_miles')::numeric > 0;
+
+UPDATE stores
+SET delivery_zones = NULLIF(delivery_zones - 'radius_miles', '{}'::jsonb)
+WHERE delivery_zones ? 'radius_miles';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- The folded radius stays on delivery_radius_meters; there is nothing to restore.
+SELECT 1;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS store_webhooks (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  store_id uuid NOT NULL,
+  url text NOT NULL,
+  secret text NOT NULL,
+  consecutive_failures integer NOT NULL DEFAULT 0,
+  disabled_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT store_webhooks_store_fk FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_webhooks_store ON store_webhooks (store_id);
+
+CREATE TABLE IF NOT EXISTS store_webhook_deliveries (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id uuid NOT NULL,
+  event_id uuid NOT NULL,
+  event_type text NOT NULL,
+  attempt integer NOT NULL,
+  status_code integer NULL,
+  error text NULL,
+  succeeded boolean NOT NULL DEFAULT false,
+  attempted_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT store_webhook_deliveries_webhook_fk FOREIGN KEY (webhook_id) REFERENCES store_webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_webhook_deliveries_webhook_attempted ON store_webhook_deliveries (webhook_id, attempted_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_store_webhook_deliveries_webhook_attempted;
+DROP TABLE IF EXISTS store_webhook_deliveries;
+DROP INDEX IF EXISTS idx_store_webhooks_store;
+DROP TABLE IF EXISTS store_webhooks;
+
+-- +goose StatementEnd
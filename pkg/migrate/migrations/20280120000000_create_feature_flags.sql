@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  key text NOT NULL,
+  environment text NOT NULL,
+  store_id uuid NULL,
+  enabled boolean NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT feature_flags_store_fk FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_feature_flags_env_key
+  ON feature_flags (environment, key)
+  WHERE store_id IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_feature_flags_env_key_store
+  ON feature_flags (environment, key, store_id)
+  WHERE store_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ux_feature_flags_env_key_store;
+DROP INDEX IF EXISTS ux_feature_flags_env_key;
+DROP TABLE IF EXISTS feature_flags;
+
+-- +goose StatementEnd
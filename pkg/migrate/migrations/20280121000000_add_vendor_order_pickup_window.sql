@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS pickup_window_start timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS pickup_window_end timestamptz NULL;
+
+ALTER TABLE vendor_orders
+  ADD CONSTRAINT vendor_orders_pickup_window_chk
+  CHECK (
+    (pickup_window_start IS NULL AND pickup_window_end IS NULL)
+    OR (pickup_window_start IS NOT NULL AND pickup_window_end IS NOT NULL AND pickup_window_end > pickup_window_start)
+  );
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders DROP CONSTRAINT IF EXISTS vendor_orders_pickup_window_chk;
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS pickup_window_end,
+  DROP COLUMN IF EXISTS pickup_window_start;
+
+-- +goose StatementEnd
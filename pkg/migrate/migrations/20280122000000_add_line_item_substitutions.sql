@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'pending_substitution'
+      AND enumtypid = 'line_item_status'::regtype
+  ) THEN
+    ALTER TYPE line_item_status ADD VALUE 'pending_substitution';
+  END IF;
+END$$;
+
+ALTER TABLE order_line_items
+  ADD COLUMN IF NOT EXISTS substitute_product_id uuid NULL REFERENCES products(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS substitute_qty integer NULL,
+  ADD COLUMN IF NOT EXISTS substitute_unit_price_cents integer NULL;
+
+ALTER TABLE order_line_items
+  ADD CONSTRAINT order_line_items_substitution_chk
+  CHECK (
+    (substitute_product_id IS NULL AND substitute_qty IS NULL AND substitute_unit_price_cents IS NULL)
+    OR (substitute_product_id IS NOT NULL AND substitute_qty > 0 AND substitute_unit_price_cents >= 0)
+  );
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE order_line_items DROP CONSTRAINT IF EXISTS order_line_items_substitution_chk;
+ALTER TABLE order_line_items
+  DROP COLUMN IF EXISTS substitute_unit_price_cents,
+  DROP COLUMN IF EXISTS substitute_qty,
+  DROP COLUMN IF EXISTS substitute_product_id;
+
+-- The pending_substitution enum value is left in place because removing enum values is irreversible.
+
+-- +goose StatementEnd
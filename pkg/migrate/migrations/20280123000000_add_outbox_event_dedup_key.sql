@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE outbox_events
+  ADD COLUMN IF NOT EXISTS dedup_key text NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_outbox_events_dedup_key
+  ON outbox_events (dedup_key)
+  WHERE dedup_key IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ux_outbox_events_dedup_key;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS dedup_key;
+
+-- +goose StatementEnd
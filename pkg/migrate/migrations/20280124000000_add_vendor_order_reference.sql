@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS order_reference_sequences (
+  year integer PRIMARY KEY,
+  last_value bigint NOT NULL
+);
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS order_reference text NULL;
+
+WITH numbered AS (
+  SELECT
+    id,
+    EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')::int AS year,
+    ROW_NUMBER() OVER (
+      PARTITION BY EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')
+      ORDER BY order_number
+    ) AS seq
+  FROM vendor_orders
+  WHERE order_reference IS NULL
+)
+UPDATE vendor_orders vo
+SET order_reference = numbered.year::text || '-' || LPAD(numbered.seq::text, GREATEST(6, LENGTH(numbered.seq::text)), '0')
+FROM numbered
+WHERE vo.id = numbered.id;
+
+INSERT INTO order_reference_sequences (year, last_value)
+SELECT split_part(order_reference, '-', 1)::int, MAX(split_part(order_reference, '-', 2)::bigint)
+FROM vendor_orders
+WHERE order_reference IS NOT NULL
+GROUP BY 1
+ON CONFLICT (year) DO UPDATE
+  SET last_value = GREATEST(order_reference_sequences.last_value, EXCLUDED.last_value);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_vendor_orders_order_reference ON vendor_orders (order_reference);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ux_vendor_orders_order_reference;
+
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS order_reference;
+
+DROP TABLE IF EXISTS order_reference_sequences;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  CREATE TYPE order_comment_visibility AS ENUM (
+    'internal',
+    'shared'
+  );
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS order_comments (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  order_id uuid NOT NULL REFERENCES vendor_orders(id) ON DELETE CASCADE,
+  author_user_id uuid NOT NULL REFERENCES users(id),
+  author_store_id uuid NULL REFERENCES stores(id),
+  author_role text NOT NULL,
+  visibility order_comment_visibility NOT NULL DEFAULT 'shared',
+  body text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_comments_order_created ON order_comments (order_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_order_comments_order_created;
+DROP TABLE IF EXISTS order_comments;
+DROP TYPE IF EXISTS order_comment_visibility;
+
+-- +goose StatementEnd
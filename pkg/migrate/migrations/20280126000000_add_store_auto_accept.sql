@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS auto_accept boolean NOT NULL DEFAULT false;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS auto_accept;
+
+-- +goose StatementEnd
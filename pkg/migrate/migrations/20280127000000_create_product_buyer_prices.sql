@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS product_buyer_prices (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  store_id uuid NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  product_id uuid NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  buyer_store_id uuid NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  price_cents integer NOT NULL CHECK (price_cents >= 0),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_product_buyer_prices_product_buyer ON product_buyer_prices (product_id, buyer_store_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS ux_product_buyer_prices_product_buyer;
+DROP TABLE IF EXISTS product_buyer_prices;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS excluded_states text[] NOT NULL DEFAULT '{}';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE products
+  DROP COLUMN IF EXISTS excluded_states;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS outbox_export_cursors (
+  name text PRIMARY KEY,
+  last_published_at timestamptz NOT NULL,
+  last_event_id uuid NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at_id ON outbox_events (published_at, id) WHERE published_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_outbox_events_published_at_id;
+DROP TABLE IF EXISTS outbox_export_cursors;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'inventory_changed'
+      AND enumtypid = 'event_type_enum'::regtype
+  ) THEN
+    ALTER TYPE event_type_enum ADD VALUE 'inventory_changed';
+  END IF;
+END$$;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'product'
+      AND enumtypid = 'aggregate_type_enum'::regtype
+  ) THEN
+    ALTER TYPE aggregate_type_enum ADD VALUE 'product';
+  END IF;
+END$$;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Down migration intentionally left empty because removing enum values is irreversible
+
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS volume_discount_strategy text NOT NULL DEFAULT 'highest_min_qty'
+    CHECK (volume_discount_strategy IN ('highest_min_qty', 'lowest_price'));
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'promo_not_combined'
+      AND enumtypid = 'vendor_group_warning_type'::regtype
+  ) THEN
+    ALTER TYPE vendor_group_warning_type ADD VALUE 'promo_not_combined';
+  END IF;
+END$$;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- The promo_not_combined enum value is left in place because removing enum values is irreversible.
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS volume_discount_strategy;
+
+-- +goose StatementEnd
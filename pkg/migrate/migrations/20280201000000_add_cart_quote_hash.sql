@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE cart_records
+  ADD COLUMN IF NOT EXISTS quote_hash text NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE cart_records
+  DROP COLUMN IF EXISTS quote_hash;
+
+-- +goose StatementEnd
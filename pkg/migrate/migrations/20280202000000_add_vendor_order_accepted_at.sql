@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS accepted_at timestamptz;
+
+-- Orders accepted before this column existed start their cancel window at their last update.
+UPDATE vendor_orders
+SET accepted_at = updated_at
+WHERE accepted_at IS NULL
+  AND status IN ('accepted', 'partially_accepted', 'fulfilled', 'ready_for_dispatch', 'hold', 'hold_for_pickup');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS accepted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS reservation_released_at timestamptz;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS reservation_released_at;
+
+-- +goose StatementEnd
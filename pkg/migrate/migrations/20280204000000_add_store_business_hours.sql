@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS business_hours jsonb;
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS opens_at timestamptz;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS opens_at;
+
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS business_hours;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- No foreign keys: the audit trail must outlive the media row it describes.
+CREATE TABLE IF NOT EXISTS media_access_logs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    media_id uuid NOT NULL,
+    store_id uuid NOT NULL,
+    actor_user_id uuid,
+    actor_store_id uuid,
+    media_kind media_kind NOT NULL,
+    purpose text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS media_access_logs_media_created_idx
+    ON media_access_logs (media_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS media_access_logs_store_created_idx
+    ON media_access_logs (store_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS media_access_logs;
+-- +goose StatementEnd
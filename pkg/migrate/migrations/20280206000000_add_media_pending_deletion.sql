@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'pending_deletion'
+      AND enumtypid = 'media_status'::regtype
+  ) THEN
+    ALTER TYPE media_status ADD VALUE 'pending_deletion';
+  END IF;
+END$$;
+
+ALTER TABLE media
+  ADD COLUMN IF NOT EXISTS purge_after timestamptz;
+
+CREATE INDEX IF NOT EXISTS media_purge_after_idx
+  ON media (purge_after)
+  WHERE purge_after IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS media_purge_after_idx;
+
+ALTER TABLE media
+  DROP COLUMN IF EXISTS purge_after;
+
+-- The pending_deletion enum value is left in place because removing enum values is irreversible
+
+-- +goose StatementEnd
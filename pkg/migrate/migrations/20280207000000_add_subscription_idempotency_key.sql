@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE subscriptions
+  ADD COLUMN IF NOT EXISTS idempotency_key text;
+
+CREATE UNIQUE INDEX IF NOT EXISTS subscriptions_store_idempotency_key_idx
+  ON subscriptions (store_id, idempotency_key)
+  WHERE idempotency_key IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS subscriptions_store_idempotency_key_idx;
+
+ALTER TABLE subscriptions
+  DROP COLUMN IF EXISTS idempotency_key;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_enum
+    WHERE enumlabel = 'subscription_alert'
+      AND enumtypid = 'notification_type'::regtype
+  ) THEN
+    ALTER TYPE notification_type ADD VALUE 'subscription_alert';
+  END IF;
+END$$;
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS subscription_grace_until timestamptz;
+
+CREATE INDEX IF NOT EXISTS stores_subscription_grace_until_idx
+  ON stores (subscription_grace_until)
+  WHERE subscription_grace_until IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS stores_subscription_grace_until_idx;
+
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS subscription_grace_until;
+
+-- The subscription_alert enum value is left in place because removing enum values is irreversible
+
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+    CREATE TYPE invoice_status AS ENUM (
+        'paid',
+        'partially_refunded',
+        'refunded'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id uuid NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    subscription_id uuid REFERENCES subscriptions(id) ON DELETE SET NULL,
+    square_invoice_id text NOT NULL UNIQUE,
+    invoice_number text,
+    amount_cents bigint NOT NULL,
+    currency text NOT NULL DEFAULT 'USD',
+    status invoice_status NOT NULL DEFAULT 'paid',
+    period_start timestamptz,
+    period_end timestamptz,
+    paid_at timestamptz,
+    public_url text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS invoices_store_created_idx ON invoices (store_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS invoices;
+DROP TYPE IF EXISTS invoice_status;
+
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS subscription_coupons (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    code text NOT NULL,
+    percent_off integer CHECK (percent_off BETWEEN 1 AND 99),
+    amount_off_cents bigint CHECK (amount_off_cents > 0),
+    active boolean NOT NULL DEFAULT true,
+    max_redemptions integer CHECK (max_redemptions > 0),
+    redemption_count integer NOT NULL DEFAULT 0,
+    expires_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT subscription_coupons_single_discount CHECK ((percent_off IS NULL) <> (amount_off_cents IS NULL))
+);
+
+-- Codes are matched case-insensitively at checkout, so uniqueness is too.
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_coupons_code_idx ON subscription_coupons (UPPER(code));
+
+CREATE TABLE IF NOT EXISTS subscription_coupon_redemptions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    coupon_id uuid NOT NULL REFERENCES subscription_coupons(id) ON DELETE CASCADE,
+    store_id uuid NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    subscription_id uuid REFERENCES subscriptions(id) ON DELETE SET NULL,
+    discount_cents bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS subscription_coupon_redemptions_coupon_idx ON subscription_coupon_redemptions (coupon_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS subscription_coupon_redemptions;
+DROP TABLE IF EXISTS subscription_coupons;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  ADD COLUMN IF NOT EXISTS tax_exempt boolean NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS tax_exempt_certificate_media_id uuid REFERENCES media(id) ON DELETE RESTRICT,
+  ADD COLUMN IF NOT EXISTS tax_exempt_certificate_verified_at timestamptz,
+  ADD COLUMN IF NOT EXISTS tax_exempt_certificate_expires_at timestamptz;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE stores
+  DROP COLUMN IF EXISTS tax_exempt_certificate_expires_at,
+  DROP COLUMN IF EXISTS tax_exempt_certificate_verified_at,
+  DROP COLUMN IF EXISTS tax_exempt_certificate_media_id,
+  DROP COLUMN IF EXISTS tax_exempt;
+
+-- +goose StatementEnd
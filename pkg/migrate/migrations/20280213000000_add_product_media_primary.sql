@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE product_media
+  ADD COLUMN is_primary boolean NOT NULL DEFAULT false;
+
+UPDATE product_media pm
+SET is_primary = true
+FROM (
+  SELECT DISTINCT ON (product_id) id
+  FROM product_media
+  ORDER BY product_id, position ASC, created_at ASC
+) first_media
+WHERE pm.id = first_media.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_media_primary
+  ON product_media (product_id)
+  WHERE is_primary;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_media_primary;
+
+ALTER TABLE product_media
+  DROP COLUMN IF EXISTS is_primary;
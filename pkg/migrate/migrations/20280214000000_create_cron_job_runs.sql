@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+DO $$
+BEGIN
+    CREATE TYPE cron_job_run_status AS ENUM (
+        'queued',
+        'running',
+        'succeeded',
+        'failed'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS cron_job_runs (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  job text NOT NULL,
+  status cron_job_run_status NOT NULL DEFAULT 'queued',
+  dry_run boolean NOT NULL DEFAULT false,
+  error text NULL,
+  duration_ms bigint NULL,
+  requested_by uuid NULL,
+  started_at timestamptz NULL,
+  finished_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cron_job_runs_queued
+  ON cron_job_runs (created_at)
+  WHERE status = 'queued';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_cron_job_runs_queued;
+DROP TABLE IF EXISTS cron_job_runs;
+DROP TYPE IF EXISTS cron_job_run_status;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE cart_records
+  ADD COLUMN IF NOT EXISTS tax_cents integer NOT NULL DEFAULT 0;
+
+ALTER TABLE cart_vendor_groups
+  ADD COLUMN IF NOT EXISTS tax_cents integer NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE cart_vendor_groups
+  DROP COLUMN IF EXISTS tax_cents;
+
+ALTER TABLE cart_records
+  DROP COLUMN IF EXISTS tax_cents;
+
+-- +goose StatementEnd
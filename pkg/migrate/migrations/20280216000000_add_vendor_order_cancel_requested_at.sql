@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  ADD COLUMN IF NOT EXISTS cancel_requested_at timestamptz;
+
+-- Orders that moved past pending before every transition stamped accepted_at start their cancel
+-- window at their last update.
+UPDATE vendor_orders
+SET accepted_at = updated_at
+WHERE accepted_at IS NULL
+  AND status <> 'created_pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE vendor_orders
+  DROP COLUMN IF EXISTS cancel_requested_at;
+
+-- +goose StatementEnd
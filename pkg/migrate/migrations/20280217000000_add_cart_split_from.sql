@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A partial checkout moves the chosen vendor groups onto a new, converted cart. Pointing it back at
+-- the active cart it came from lets a retried checkout find and replay it.
+ALTER TABLE cart_records
+  ADD COLUMN IF NOT EXISTS split_from_cart_id uuid REFERENCES cart_records(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_cart_records_split_from
+  ON cart_records (split_from_cart_id)
+  WHERE split_from_cart_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_cart_records_split_from;
+ALTER TABLE cart_records
+  DROP COLUMN IF EXISTS split_from_cart_id;
+
+-- +goose StatementEnd
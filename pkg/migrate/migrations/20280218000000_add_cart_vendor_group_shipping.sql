@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The quote prices each vendor group's shipping line so checkout can charge what the buyer saw.
+ALTER TABLE cart_vendor_groups
+  ADD COLUMN IF NOT EXISTS shipping_line jsonb;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE cart_vendor_groups
+  DROP COLUMN IF EXISTS shipping_line;
+
+-- +goose StatementEnd
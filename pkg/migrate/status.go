@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// MigrationState describes one migration in a status report.
+type MigrationState struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// StatusReport splits migrations into applied and pending, each ordered by version.
+type StatusReport struct {
+	Applied []MigrationState `json:"applied"`
+	Pending []MigrationState `json:"pending"`
+}
+
+// Status reads the applied/pending state of every migration in dir.
+func Status(ctx context.Context, db *sql.DB, dir string) (StatusReport, error) {
+	if db == nil {
+		return StatusReport{}, fmt.Errorf("db is required")
+	}
+	if dir == "" {
+		return StatusReport{}, fmt.Errorf("dir is required")
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, os.DirFS(dir))
+	if err != nil {
+		return StatusReport{}, fmt.Errorf("goose provider: %w", err)
+	}
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return StatusReport{}, fmt.Errorf("goose status: %w", err)
+	}
+	return BuildStatusReport(statuses), nil
+}
+
+// BuildStatusReport converts goose migration statuses into a StatusReport.
+func BuildStatusReport(statuses []*goose.MigrationStatus) StatusReport {
+	report := StatusReport{
+		Applied: []MigrationState{},
+		Pending: []MigrationState{},
+	}
+	for _, status := range statuses {
+		if status == nil || status.Source == nil {
+			continue
+		}
+		state := MigrationState{
+			Version: status.Source.Version,
+			Name:    filepath.Base(status.Source.Path),
+		}
+		if status.State == goose.StateApplied {
+			appliedAt := status.AppliedAt.UTC()
+			state.AppliedAt = &appliedAt
+			report.Applied = append(report.Applied, state)
+			continue
+		}
+		report.Pending = append(report.Pending, state)
+	}
+	return report
+}
+
+// WriteStatusJSON writes the report as indented JSON.
+func WriteStatusJSON(w io.Writer, report StatusReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("encode status: %w", err)
+	}
+	return nil
+}
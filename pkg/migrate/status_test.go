@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestWriteStatusJSONSplitsAppliedAndPending(t *testing.T) {
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	report := BuildStatusReport([]*goose.MigrationStatus{
+		{
+			Source:    &goose.Source{Type: goose.TypeSQL, Path: "pkg/migrate/migrations/20260101000000_create_users.sql", Version: 20260101000000},
+			State:     goose.StateApplied,
+			AppliedAt: appliedAt,
+		},
+		{
+			Source: &goose.Source{Type: goose.TypeSQL, Path: "pkg/migrate/migrations/20260201000000_add_orders.sql", Version: 20260201000000},
+			State:  goose.StatePending,
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteStatusJSON(&buf, report); err != nil {
+		t.Fatalf("write status: %v", err)
+	}
+
+	var decoded struct {
+		Applied []map[string]any `json:"applied"`
+		Pending []map[string]any `json:"pending"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if len(decoded.Applied) != 1 || len(decoded.Pending) != 1 {
+		t.Fatalf("expected 1 applied and 1 pending, got %d and %d", len(decoded.Applied), len(decoded.Pending))
+	}
+
+	applied := decoded.Applied[0]
+	if applied["version"] != float64(20260101000000) || applied["name"] != "20260101000000_create_users.sql" {
+		t.Fatalf("unexpected applied migration: %v", applied)
+	}
+	if applied["applied_at"] != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected applied_at: %v", applied["applied_at"])
+	}
+
+	pending := decoded.Pending[0]
+	if pending["version"] != float64(20260201000000) || pending["name"] != "20260201000000_add_orders.sql" {
+		t.Fatalf("unexpected pending migration: %v", pending)
+	}
+	if at, ok := pending["applied_at"]; !ok || at != nil {
+		t.Fatalf("expected null applied_at for pending migration, got %v", at)
+	}
+}
+
+func TestWriteStatusJSONEmitsEmptyLists(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteStatusJSON(&buf, BuildStatusReport(nil)); err != nil {
+		t.Fatalf("write status: %v", err)
+	}
+	want := "{\n  \"applied\": [],\n  \"pending\": []\n}\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
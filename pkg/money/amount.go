@@ -0,0 +1,157 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+// ErrCurrencyMismatch is returned when arithmetic combines amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+var currencySymbols = map[enums.Currency]string{
+	enums.CurrencyUSD: "$",
+}
+
+// Amount is a whole number of minor units (cents) in a single currency. Use it where an amount
+// leaves the service for people to read: notifications, emails, exports, and event payloads.
+type Amount struct {
+	Cents    int
+	Currency enums.Currency
+}
+
+// New builds an Amount. An empty currency means USD, matching the column default on orders.
+func New(cents int, currency enums.Currency) Amount {
+	if currency == "" {
+		currency = enums.CurrencyUSD
+	}
+	return Amount{Cents: cents, Currency: currency}
+}
+
+// USD is shorthand for New(cents, enums.CurrencyUSD).
+func USD(cents int) Amount {
+	return New(cents, enums.CurrencyUSD)
+}
+
+// String formats the amount for display: "$1,234.56" for currencies with a symbol, otherwise
+// "1,234.56 BTC".
+func (a Amount) String() string {
+	cents := a.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	value := fmt.Sprintf("%s.%02d", groupThousands(cents/100), cents%100)
+	currency := a.currency()
+	if symbol, ok := currencySymbols[currency]; ok {
+		return sign + symbol + value
+	}
+	return sign + value + " " + string(currency)
+}
+
+// Add returns a + other, or ErrCurrencyMismatch when the currencies differ.
+func (a Amount) Add(other Amount) (Amount, error) {
+	if err := a.sameCurrency(other); err != nil {
+		return Amount{}, err
+	}
+	return New(a.Cents+other.Cents, a.currency()), nil
+}
+
+// Sub returns a - other, or ErrCurrencyMismatch when the currencies differ.
+func (a Amount) Sub(other Amount) (Amount, error) {
+	if err := a.sameCurrency(other); err != nil {
+		return Amount{}, err
+	}
+	return New(a.Cents-other.Cents, a.currency()), nil
+}
+
+// MustAdd is Add for amounts the caller knows share a currency; it panics on a mismatch.
+func (a Amount) MustAdd(other Amount) Amount {
+	sum, err := a.Add(other)
+	if err != nil {
+		panic(err)
+	}
+	return sum
+}
+
+// Mul scales the amount by a whole quantity, e.g. a unit price times qty.
+func (a Amount) Mul(qty int) Amount {
+	return New(a.Cents*qty, a.currency())
+}
+
+// Sum adds amounts that must all share a currency. An empty list sums to zero USD.
+func Sum(amounts ...Amount) (Amount, error) {
+	if len(amounts) == 0 {
+		return USD(0), nil
+	}
+	total := New(0, amounts[0].Currency)
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Amount{}, err
+		}
+	}
+	return total, nil
+}
+
+type amountJSON struct {
+	Cents     int            `json:"cents"`
+	Currency  enums.Currency `json:"currency"`
+	Formatted string         `json:"formatted,omitempty"`
+}
+
+// MarshalJSON writes {"cents":1234,"currency":"USD","formatted":"$12.34"}.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountJSON{Cents: a.Cents, Currency: a.currency(), Formatted: a.String()})
+}
+
+// UnmarshalJSON reads cents and currency; formatted is derived and ignored.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var raw amountJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Currency != "" && !raw.Currency.IsValid() {
+		return fmt.Errorf("money: invalid currency %q", raw.Currency)
+	}
+	*a = New(raw.Cents, raw.Currency)
+	return nil
+}
+
+func (a Amount) currency() enums.Currency {
+	if a.Currency == "" {
+		return enums.CurrencyUSD
+	}
+	return a.Currency
+}
+
+func (a Amount) sameCurrency(other Amount) error {
+	if a.currency() != other.currency() {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency(), other.currency())
+	}
+	return nil
+}
+
+func groupThousands(value int) string {
+	digits := strconv.Itoa(value)
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+)
+
+func TestAmountString(t *testing.T) {
+	cases := []struct {
+		amount Amount
+		want   string
+	}{
+		{USD(0), "$0.00"},
+		{USD(5), "$0.05"},
+		{USD(1234), "$12.34"},
+		{USD(123456789), "$1,234,567.89"},
+		{USD(-2550), "-$25.50"},
+		{New(100000, ""), "$1,000.00"},
+		{New(150, enums.CurrencyBTC), "1.50 BTC"},
+	}
+	for _, tc := range cases {
+		if got := tc.amount.String(); got != tc.want {
+			t.Fatalf("String(%+v) = %q, want %q", tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestAmountJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(USD(123456))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cents":123456,"currency":"USD","formatted":"$1,234.56"}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+
+	var decoded Amount
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded != USD(123456) {
+		t.Fatalf("expected round trip, got %+v", decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"cents":1,"currency":"EUR"}`), &decoded); err == nil {
+		t.Fatal("expected unknown currency to fail")
+	}
+}
+
+func TestAmountArithmeticRejectsMixedCurrencies(t *testing.T) {
+	sum, err := USD(1050).Add(USD(250))
+	if err != nil || sum != USD(1300) {
+		t.Fatalf("expected $13.00, got %v, %v", sum, err)
+	}
+	diff, err := USD(1050).Sub(USD(2000))
+	if err != nil || diff != USD(-950) {
+		t.Fatalf("expected -$9.50, got %v, %v", diff, err)
+	}
+	if got := USD(333).Mul(3); got != USD(999) {
+		t.Fatalf("expected $9.99, got %v", got)
+	}
+
+	btc := New(100, enums.CurrencyBTC)
+	if _, err := USD(100).Add(btc); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch from Add, got %v", err)
+	}
+	if _, err := USD(100).Sub(btc); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch from Sub, got %v", err)
+	}
+	if _, err := Sum(USD(1), USD(2), btc); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch from Sum, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected MustAdd to panic on mixed currencies")
+		}
+	}()
+	USD(100).MustAdd(btc)
+}
@@ -0,0 +1,66 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RoundingMode decides how fractional cents (from percentage discounts or tax) become whole cents.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero (1.5 -> 2, 2.5 -> 3). It is the default.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even cent (1.5 -> 2, 2.5 -> 2).
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown drops the fraction (2.9 -> 2).
+	RoundDown RoundingMode = "down"
+)
+
+// fractionPrecision trims float noise (1.005*100 is 100.49999999999999) before rounding.
+const fractionPrecision = 1e6
+
+// ParseRoundingMode maps a config value to a RoundingMode; empty selects RoundHalfUp.
+func ParseRoundingMode(raw string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "":
+		return RoundHalfUp, nil
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", raw)
+	}
+}
+
+// Round converts a fractional cent amount to whole cents. The zero value rounds half up.
+func (m RoundingMode) Round(cents float64) int {
+	cents = math.Round(cents*fractionPrecision) / fractionPrecision
+	switch m {
+	case RoundHalfEven:
+		return int(math.RoundToEven(cents))
+	case RoundDown:
+		return int(math.Trunc(cents))
+	default:
+		return int(math.Round(cents))
+	}
+}
+
+// Percent returns percent% of cents, rounded once for the whole amount. Round a line's discount
+// here rather than rounding per unit and multiplying, which drifts by a cent per unit.
+func (m RoundingMode) Percent(cents int, percent float64) int {
+	return m.Round(float64(cents) * percent / 100)
+}
+
+// CheckLineTotals reports an error when the line totals do not add up to the amount stored on
+// the order they belong to.
+func CheckLineTotals(lineTotals []int, expected int) error {
+	sum := 0
+	for _, total := range lineTotals {
+		sum += total
+	}
+	if sum != expected {
+		return fmt.Errorf("line totals sum to %d cents but the order expects %d cents", sum, expected)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package money
+
+import "testing"
+
+func TestParseRoundingMode(t *testing.T) {
+	for raw, want := range map[string]RoundingMode{
+		"":           RoundHalfUp,
+		"half_up":    RoundHalfUp,
+		" HALF_EVEN": RoundHalfEven,
+		"down":       RoundDown,
+	} {
+		got, err := ParseRoundingMode(raw)
+		if err != nil || got != want {
+			t.Fatalf("ParseRoundingMode(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	if _, err := ParseRoundingMode("bankers"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	cases := []struct {
+		mode  RoundingMode
+		cents float64
+		want  int
+	}{
+		{RoundHalfUp, 2.5, 3},
+		{RoundHalfUp, 2.4999, 2},
+		{RoundHalfUp, 1.005 * 100, 101}, // float noise: 100.49999999999999
+		{RoundHalfEven, 2.5, 2},
+		{RoundHalfEven, 3.5, 4},
+		{RoundDown, 2.9, 2},
+		{"", 0.5, 1},
+	}
+	for _, tc := range cases {
+		if got := tc.mode.Round(tc.cents); got != tc.want {
+			t.Fatalf("%q.Round(%v) = %d, want %d", tc.mode, tc.cents, got, tc.want)
+		}
+	}
+}
+
+func TestPercentRoundsTheWholeLine(t *testing.T) {
+	// 10% of 3 x 333 cents is 99.9: rounding per unit (33 x 3 = 99) used to lose a cent.
+	if got := RoundHalfUp.Percent(3*333, 10); got != 100 {
+		t.Fatalf("expected 100, got %d", got)
+	}
+	// 15% of 7 x 250 cents is 262.5.
+	if got := RoundHalfUp.Percent(7*250, 15); got != 263 {
+		t.Fatalf("expected half up 263, got %d", got)
+	}
+	if got := RoundHalfEven.Percent(7*250, 15); got != 262 {
+		t.Fatalf("expected half even 262, got %d", got)
+	}
+	if got := RoundDown.Percent(3*333, 10); got != 99 {
+		t.Fatalf("expected round down 99, got %d", got)
+	}
+}
+
+func TestCheckLineTotals(t *testing.T) {
+	if err := CheckLineTotals([]int{900, 1001, 99}, 2000); err != nil {
+		t.Fatalf("expected totals to match, got %v", err)
+	}
+	if err := CheckLineTotals([]int{900, 1001}, 1900); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+}
@@ -16,9 +16,11 @@ type ActorRef struct {
 
 // PayloadEnvelope is the stable payload structure stored in outbox_events.
 type PayloadEnvelope struct {
-	Version    int             `json:"version"`
-	EventID    string          `json:"eventId"`
-	OccurredAt time.Time       `json:"occurredAt"`
-	Actor      *ActorRef       `json:"actor,omitempty"`
-	Data       json.RawMessage `json:"data"`
+	Version    int       `json:"version"`
+	EventID    string    `json:"eventId"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Actor      *ActorRef `json:"actor,omitempty"`
+	// RequestID correlates the event with the HTTP request that produced it.
+	RequestID string          `json:"requestId,omitempty"`
+	Data      json.RawMessage `json:"data"`
 }
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FetchPublishedAfter returns up to limit events published after the cursor and before the given
+// bound, ordered by (published_at, id). A nil cursor starts from the oldest published event.
+func (r *Repository) FetchPublishedAfter(ctx context.Context, cursor *models.OutboxExportCursor, before time.Time, limit int) ([]models.OutboxEvent, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("published_at IS NOT NULL").
+		Where("published_at < ?", before)
+	if cursor != nil {
+		query = query.Where("(published_at > ? OR (published_at = ? AND id > ?))",
+			cursor.LastPublishedAt, cursor.LastPublishedAt, cursor.LastEventID)
+	}
+	var events []models.OutboxEvent
+	err := query.Order("published_at ASC, id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// GetExportCursor loads the named export cursor, or nil when the export never ran.
+func (r *Repository) GetExportCursor(ctx context.Context, name string) (*models.OutboxExportCursor, error) {
+	var cursor models.OutboxExportCursor
+	if err := r.db.WithContext(ctx).First(&cursor, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// SaveExportCursor creates or advances the named export cursor.
+func (r *Repository) SaveExportCursor(ctx context.Context, cursor models.OutboxExportCursor) error {
+	cursor.UpdatedAt = time.Now().UTC()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_published_at", "last_event_id", "updated_at"}),
+		}).
+		Create(&cursor).
+		Error
+}
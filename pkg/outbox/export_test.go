@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func insertPublishedEvent(t *testing.T, conn *gorm.DB, id uuid.UUID, publishedAt *time.Time) {
+	t.Helper()
+	event := models.OutboxEvent{
+		ID:            id,
+		EventType:     enums.EventOrderPaid,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   uuid.New(),
+		Payload:       json.RawMessage(`{}`),
+		PublishedAt:   publishedAt,
+	}
+	if err := conn.Create(&event).Error; err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+}
+
+func TestFetchPublishedAfterResumesFromCursor(t *testing.T) {
+	conn := newOutboxTestDB(t)
+	repo := NewRepository(conn)
+	ctx := context.Background()
+
+	base := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		ts := base.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+	// Two events share a published_at so the cursor has to break the tie on id.
+	first := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	tiedLow := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	tiedHigh := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	tooRecent := uuid.New()
+	insertPublishedEvent(t, conn, tiedHigh, at(5))
+	insertPublishedEvent(t, conn, first, at(1))
+	insertPublishedEvent(t, conn, tiedLow, at(5))
+	insertPublishedEvent(t, conn, tooRecent, at(30))
+	insertPublishedEvent(t, conn, uuid.New(), nil)
+
+	before := base.Add(10 * time.Minute)
+	events, err := repo.FetchPublishedAfter(ctx, nil, before, 2)
+	if err != nil {
+		t.Fatalf("fetch first page: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != first || events[1].ID != tiedLow {
+		t.Fatalf("expected [first, tiedLow], got %+v", events)
+	}
+
+	if err := repo.SaveExportCursor(ctx, models.OutboxExportCursor{Name: "test", LastPublishedAt: *events[1].PublishedAt, LastEventID: events[1].ID}); err != nil {
+		t.Fatalf("save cursor: %v", err)
+	}
+	cursor, err := repo.GetExportCursor(ctx, "test")
+	if err != nil || cursor == nil {
+		t.Fatalf("get cursor: %v %v", cursor, err)
+	}
+	if cursor.LastEventID != tiedLow {
+		t.Fatalf("expected cursor at tiedLow, got %s", cursor.LastEventID)
+	}
+
+	events, err = repo.FetchPublishedAfter(ctx, cursor, before, 2)
+	if err != nil {
+		t.Fatalf("fetch second page: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != tiedHigh {
+		t.Fatalf("expected only tiedHigh after cursor, got %+v", events)
+	}
+
+	if err := repo.SaveExportCursor(ctx, models.OutboxExportCursor{Name: "test", LastPublishedAt: *events[0].PublishedAt, LastEventID: events[0].ID}); err != nil {
+		t.Fatalf("advance cursor: %v", err)
+	}
+	cursor, err = repo.GetExportCursor(ctx, "test")
+	if err != nil || cursor == nil || cursor.LastEventID != tiedHigh {
+		t.Fatalf("expected advanced cursor, got %+v %v", cursor, err)
+	}
+	events, err = repo.FetchPublishedAfter(ctx, cursor, before, 2)
+	if err != nil {
+		t.Fatalf("fetch caught up: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events past the settle bound, got %+v", events)
+	}
+}
+
+func TestGetExportCursorMissingReturnsNil(t *testing.T) {
+	repo := NewRepository(newOutboxTestDB(t))
+	cursor, err := repo.GetExportCursor(context.Background(), "never-ran")
+	if err != nil {
+		t.Fatalf("get cursor: %v", err)
+	}
+	if cursor != nil {
+		t.Fatalf("expected nil cursor, got %+v", cursor)
+	}
+}
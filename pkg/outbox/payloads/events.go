@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
 	"github.com/angelmondragon/packfinderz-backend/pkg/types"
 	"github.com/google/uuid"
 )
@@ -48,12 +49,14 @@ type OrderCanceledEvent struct {
 }
 
 // CashCollectedEvent captures the payload emitted once an agent collects cash.
+// AmountCents stays for existing consumers; Amount adds the currency and display string.
 type CashCollectedEvent struct {
-	OrderID         uuid.UUID `json:"order_id"`
-	BuyerStoreID    uuid.UUID `json:"buyer_store_id"`
-	VendorStoreID   uuid.UUID `json:"vendor_store_id"`
-	AmountCents     int       `json:"amount_cents"`
-	CashCollectedAt time.Time `json:"cash_collected_at"`
+	OrderID         uuid.UUID    `json:"order_id"`
+	BuyerStoreID    uuid.UUID    `json:"buyer_store_id"`
+	VendorStoreID   uuid.UUID    `json:"vendor_store_id"`
+	AmountCents     int          `json:"amount_cents"`
+	Amount          money.Amount `json:"amount"`
+	CashCollectedAt time.Time    `json:"cash_collected_at"`
 }
 
 // PaymentStatusEvent is emitted when a payment intent enters a terminal status.
@@ -63,13 +66,15 @@ type PaymentStatusEvent struct {
 	FailureReason   *string   `json:"failure_reason,omitempty"`
 }
 
-// NotificationRequestedEvent tells downstream systems to alert a vendor.
+// NotificationRequestedEvent tells downstream systems to alert the buyer or vendor of an order.
 type NotificationRequestedEvent struct {
-	OrderID         uuid.UUID `json:"order_id"`
-	CheckoutGroupID uuid.UUID `json:"checkout_group_id"`
-	BuyerStoreID    uuid.UUID `json:"buyer_store_id"`
-	VendorStoreID   uuid.UUID `json:"vendor_store_id"`
-	Type            string    `json:"type"`
+	OrderID         uuid.UUID  `json:"order_id"`
+	CheckoutGroupID uuid.UUID  `json:"checkout_group_id"`
+	BuyerStoreID    uuid.UUID  `json:"buyer_store_id"`
+	VendorStoreID   uuid.UUID  `json:"vendor_store_id"`
+	OrderReference  string     `json:"order_reference,omitempty"`
+	Type            string     `json:"type"`
+	LineItemID      *uuid.UUID `json:"line_item_id,omitempty"`
 }
 
 // OrderRetriedEvent reports that an expired order was replayed.
@@ -82,13 +87,15 @@ type OrderRetriedEvent struct {
 }
 
 // OrderPaidEvent is emitted when admin confirms payout and the vendor has been paid.
+// AmountCents stays for existing consumers; Amount adds the currency and display string.
 type OrderPaidEvent struct {
-	OrderID         uuid.UUID `json:"order_id"`
-	BuyerStoreID    uuid.UUID `json:"buyer_store_id"`
-	VendorStoreID   uuid.UUID `json:"vendor_store_id"`
-	PaymentIntentID uuid.UUID `json:"payment_intent_id"`
-	AmountCents     int       `json:"amount_cents"`
-	VendorPaidAt    time.Time `json:"vendor_paid_at"`
+	OrderID         uuid.UUID    `json:"order_id"`
+	BuyerStoreID    uuid.UUID    `json:"buyer_store_id"`
+	VendorStoreID   uuid.UUID    `json:"vendor_store_id"`
+	PaymentIntentID uuid.UUID    `json:"payment_intent_id"`
+	AmountCents     int          `json:"amount_cents"`
+	Amount          money.Amount `json:"amount"`
+	VendorPaidAt    time.Time    `json:"vendor_paid_at"`
 }
 
 // OrderPendingNudgeEvent carries the payload for nudges.
@@ -202,3 +209,25 @@ type LicenseStatusChangedEvent struct {
 	Reason      string              `json:"reason,omitempty"`
 	WarningType string              `json:"warningType,omitempty"`
 }
+
+// StoreKYCStatusChangedEvent is emitted when an admin moves a store through the KYC workflow.
+type StoreKYCStatusChangedEvent struct {
+	StoreID        uuid.UUID       `json:"storeId"`
+	PreviousStatus enums.KYCStatus `json:"previousStatus"`
+	Status         enums.KYCStatus `json:"status"`
+	Reason         string          `json:"reason,omitempty"`
+	ChangedBy      uuid.UUID       `json:"changedBy"`
+}
+
+// InventoryChangedEvent snapshots a product's stock after a reservation, release, or vendor edit
+// so analytics can chart stockouts over time. The quantities are the counters after the change.
+type InventoryChangedEvent struct {
+	ProductID      uuid.UUID                       `json:"product_id"`
+	VendorStoreID  uuid.UUID                       `json:"vendor_store_id"`
+	AvailableQty   int                             `json:"available_qty"`
+	ReservedQty    int                             `json:"reserved_qty"`
+	AvailableDelta int                             `json:"available_delta"`
+	ReservedDelta  int                             `json:"reserved_delta"`
+	Reason         enums.InventoryAdjustmentReason `json:"reason"`
+	ChangedAt      time.Time                       `json:"changed_at"`
+}
@@ -0,0 +1,46 @@
+package payloads
+
+import (
+	"encoding/json"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/money"
+)
+
+// Payload schema versions travel in the outbox envelope's version field. Additive, optional
+// fields do not need a bump. When a payload changes in a way old consumers or old rows would
+// get wrong (a new required field, a rename, a new meaning):
+//  1. bump its constant below and emit that version from the producer,
+//  2. add an Upcast<Event>V<n> func here that rewrites a v<n> payload into v<n+1>,
+//  3. set Version and Upcasters on its descriptor in pkg/outbox/registry.
+//
+// Consumers that decode through registry.DecoderRegistry then always get the latest struct.
+const (
+	// DefaultVersion is the version of every payload that has not changed shape.
+	DefaultVersion = 1
+	// OrderPaidEventVersion 2 added Amount (cents, currency, and display string).
+	OrderPaidEventVersion = 2
+)
+
+// UpcastOrderPaidV1 fills the v2 amount from amount_cents. v1 rows predate order currencies, so
+// the amount is USD. An amount already present is kept.
+func UpcastOrderPaidV1(payload json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["amount"]; ok {
+		return payload, nil
+	}
+	var cents int
+	if raw, ok := fields["amount_cents"]; ok {
+		if err := json.Unmarshal(raw, &cents); err != nil {
+			return nil, err
+		}
+	}
+	amount, err := json.Marshal(money.USD(cents))
+	if err != nil {
+		return nil, err
+	}
+	fields["amount"] = amount
+	return json.Marshal(fields)
+}
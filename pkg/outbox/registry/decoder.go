@@ -10,20 +10,32 @@ import (
 
 type decoderFunc func(payload json.RawMessage) (interface{}, error)
 
+// Upcaster rewrites a payload from one schema version to the next (v -> v+1). It works on raw
+// JSON so old structs never have to be kept around.
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
 type registryKey struct {
 	eventType enums.OutboxEventType
 	version   int
 }
 
-// DecoderRegistry stores versioned payload decoders for consumers.
+// DecoderRegistry stores versioned payload decoders for consumers. Each event type decodes into
+// its latest registered version; older payloads are walked forward one upcaster at a time, so a
+// v1 row comes back as the v2 struct with the new fields filled in.
 type DecoderRegistry struct {
-	mtx      sync.RWMutex
-	registry map[registryKey]decoderFunc
+	mtx       sync.RWMutex
+	registry  map[registryKey]decoderFunc
+	upcasters map[registryKey]Upcaster
+	latest    map[enums.OutboxEventType]int
 }
 
 // NewDecoderRegistry builds an empty decoder registry.
 func NewDecoderRegistry() *DecoderRegistry {
-	return &DecoderRegistry{registry: make(map[registryKey]decoderFunc)}
+	return &DecoderRegistry{
+		registry:  make(map[registryKey]decoderFunc),
+		upcasters: make(map[registryKey]Upcaster),
+		latest:    make(map[enums.OutboxEventType]int),
+	}
 }
 
 // Register stores a decoder for the given event type and version.
@@ -31,14 +43,54 @@ func (r *DecoderRegistry) Register(eventType enums.OutboxEventType, version int,
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 	r.registry[registryKey{eventType: eventType, version: version}] = decoder
+	if version > r.latest[eventType] {
+		r.latest[eventType] = version
+	}
+}
+
+// RegisterUpcaster stores the step that turns a fromVersion payload into fromVersion+1.
+func (r *DecoderRegistry) RegisterUpcaster(eventType enums.OutboxEventType, fromVersion int, upcaster Upcaster) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.upcasters[registryKey{eventType: eventType, version: fromVersion}] = upcaster
 }
 
-// Decode runs the decoder registered for the event type and version.
+// LatestVersion reports the newest decoder version registered for the event type, or 0.
+func (r *DecoderRegistry) LatestVersion(eventType enums.OutboxEventType) int {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.latest[eventType]
+}
+
+// Decode upcasts the payload from version to the latest registered version and runs that
+// version's decoder. Version 0 (rows written before envelopes carried one) is read as v1.
 func (r *DecoderRegistry) Decode(eventType enums.OutboxEventType, version int, payload json.RawMessage) (interface{}, error) {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
-	if decoder, ok := r.registry[registryKey{eventType: eventType, version: version}]; ok {
-		return decoder(payload)
+	latest, ok := r.latest[eventType]
+	if !ok {
+		return nil, fmt.Errorf("decoder not registered for %s@v%d", eventType, version)
+	}
+	if version == 0 {
+		version = 1
+	}
+	if version > latest {
+		return nil, fmt.Errorf("%s@v%d is newer than the latest known version v%d", eventType, version, latest)
+	}
+	for ; version < latest; version++ {
+		upcaster, ok := r.upcasters[registryKey{eventType: eventType, version: version}]
+		if !ok {
+			return nil, fmt.Errorf("upcaster not registered for %s@v%d", eventType, version)
+		}
+		upcasted, err := upcaster(payload)
+		if err != nil {
+			return nil, fmt.Errorf("upcast %s@v%d: %w", eventType, version, err)
+		}
+		payload = upcasted
+	}
+	decoder, ok := r.registry[registryKey{eventType: eventType, version: latest}]
+	if !ok {
+		return nil, fmt.Errorf("decoder not registered for %s@v%d", eventType, latest)
 	}
-	return nil, fmt.Errorf("decoder not registered for %s@v%d", eventType, version)
+	return decoder(payload)
 }
@@ -26,3 +26,55 @@ func TestDecoderRegistry(t *testing.T) {
 		t.Fatalf("unexpected output %+v", output)
 	}
 }
+
+func TestDecoderRegistryUpcastsToLatestVersion(t *testing.T) {
+	type statusV3 struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+		Source string `json:"source"`
+	}
+	reg := NewDecoderRegistry()
+	reg.Register(enums.EventLicenseStatusChanged, 3, func(payload json.RawMessage) (interface{}, error) {
+		var decoded statusV3
+		err := json.Unmarshal(payload, &decoded)
+		return decoded, err
+	})
+	reg.RegisterUpcaster(enums.EventLicenseStatusChanged, 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]any
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+		fields["reason"] = "unspecified"
+		return json.Marshal(fields)
+	})
+
+	if _, err := reg.Decode(enums.EventLicenseStatusChanged, 1, json.RawMessage(`{"status":"verified"}`)); err == nil {
+		t.Fatal("expected error for missing v2 upcaster")
+	}
+
+	reg.RegisterUpcaster(enums.EventLicenseStatusChanged, 2, func(payload json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]any
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+		fields["source"] = "legacy"
+		return json.Marshal(fields)
+	})
+
+	output, err := reg.Decode(enums.EventLicenseStatusChanged, 1, json.RawMessage(`{"status":"verified"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := output.(statusV3); got != (statusV3{Status: "verified", Reason: "unspecified", Source: "legacy"}) {
+		t.Fatalf("unexpected upcast payload %+v", got)
+	}
+
+	output, err = reg.Decode(enums.EventLicenseStatusChanged, 3, json.RawMessage(`{"status":"expired","reason":"lapsed","source":"cron"}`))
+	if err != nil || output.(statusV3).Reason != "lapsed" {
+		t.Fatalf("expected current version decoded as-is, got %+v, %v", output, err)
+	}
+
+	if _, err := reg.Decode(enums.EventLicenseStatusChanged, 4, json.RawMessage(`{}`)); err == nil {
+		t.Fatal("expected error for a version newer than the decoder")
+	}
+}
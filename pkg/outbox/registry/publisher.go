@@ -19,6 +19,10 @@ type EventDescriptor struct {
 	AggregateType  enums.OutboxAggregateType
 	Topic          string
 	PayloadFactory func() interface{}
+	// Version is the payload schema version producers emit; zero means payloads.DefaultVersion.
+	Version int
+	// Upcasters lift older payloads one version at a time, keyed by the version they read.
+	Upcasters map[int]Upcaster
 }
 
 // ResolvedEvent is the result of decoding an outbox row.
@@ -163,14 +167,28 @@ func NewEventRegistry(cfg config.PubSubConfig) (*EventRegistry, error) {
 			Topic:          notificationTopic,
 			PayloadFactory: func() interface{} { return &payloads.CheckoutConvertedEvent{} },
 		},
+		{
+			EventType:      enums.EventStoreKYCStatusChanged,
+			AggregateType:  enums.AggregateStore,
+			Topic:          notificationTopic,
+			PayloadFactory: func() interface{} { return &payloads.StoreKYCStatusChangedEvent{} },
+		},
 	} {
 		reg.register(desc)
 	}
+	reg.register(EventDescriptor{
+		EventType:      enums.EventInventoryChanged,
+		AggregateType:  enums.AggregateProduct,
+		Topic:          cfg.AnalyticsTopic,
+		PayloadFactory: func() interface{} { return &payloads.InventoryChangedEvent{} },
+	})
 	reg.register(EventDescriptor{
 		EventType:      enums.EventOrderPaid,
 		AggregateType:  enums.AggregateVendorOrder,
 		Topic:          billingTopic,
 		PayloadFactory: func() interface{} { return &payloads.OrderPaidEvent{} },
+		Version:        payloads.OrderPaidEventVersion,
+		Upcasters:      map[int]Upcaster{1: payloads.UpcastOrderPaidV1},
 	})
 
 	return reg, nil
@@ -183,6 +201,30 @@ func (r *EventRegistry) register(desc EventDescriptor) {
 	r.entries[desc.EventType] = desc
 }
 
+// Decoders builds a DecoderRegistry from the descriptors so consumers decode every payload as
+// its current version, upcasting rows written by older producers.
+func (r *EventRegistry) Decoders() *DecoderRegistry {
+	decoders := NewDecoderRegistry()
+	for eventType, desc := range r.entries {
+		factory := desc.PayloadFactory
+		version := desc.Version
+		if version == 0 {
+			version = payloads.DefaultVersion
+		}
+		decoders.Register(eventType, version, func(payload json.RawMessage) (interface{}, error) {
+			decoded := factory()
+			if err := json.Unmarshal(payload, decoded); err != nil {
+				return nil, err
+			}
+			return decoded, nil
+		})
+		for from, upcaster := range desc.Upcasters {
+			decoders.RegisterUpcaster(eventType, from, upcaster)
+		}
+	}
+	return decoders
+}
+
 // Resolve validates the row and decodes its typed payload.
 func (r *EventRegistry) Resolve(event models.OutboxEvent) (*ResolvedEvent, error) {
 	desc, ok := r.entries[event.EventType]
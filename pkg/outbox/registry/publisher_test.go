@@ -218,3 +218,36 @@ func mustEnvelope(t *testing.T, payload []byte) json.RawMessage {
 	}
 	return data
 }
+
+func TestEventRegistryDecodersUpcastOrderPaidV1(t *testing.T) {
+	decoders := newTestEventRegistry(t).Decoders()
+	if got := decoders.LatestVersion(enums.EventOrderPaid); got != payloads.OrderPaidEventVersion {
+		t.Fatalf("expected order_paid at v%d, got v%d", payloads.OrderPaidEventVersion, got)
+	}
+
+	orderID := uuid.New()
+	v1 := json.RawMessage(`{"order_id":"` + orderID.String() + `","amount_cents":4250,"vendor_paid_at":"2026-01-02T03:04:05Z"}`)
+	decoded, err := decoders.Decode(enums.EventOrderPaid, 1, v1)
+	if err != nil {
+		t.Fatalf("decode v1: %v", err)
+	}
+	event, ok := decoded.(*payloads.OrderPaidEvent)
+	if !ok {
+		t.Fatalf("unexpected payload type %T", decoded)
+	}
+	if event.OrderID != orderID || event.AmountCents != 4250 {
+		t.Fatalf("expected v1 fields kept, got %+v", event)
+	}
+	if event.Amount.Cents != 4250 || event.Amount.Currency != enums.CurrencyUSD {
+		t.Fatalf("expected amount filled from amount_cents in USD, got %+v", event.Amount)
+	}
+
+	// Payloads that skipped a version bump still decode as v1.
+	decoded, err = decoders.Decode(enums.EventOrderCreated, payloads.DefaultVersion, json.RawMessage(`{"vendor_order_ids":[]}`))
+	if err != nil {
+		t.Fatalf("decode order_created: %v", err)
+	}
+	if _, ok := decoded.(*payloads.OrderCreatedEvent); !ok {
+		t.Fatalf("unexpected payload type %T", decoded)
+	}
+}
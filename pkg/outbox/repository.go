@@ -29,6 +29,24 @@ func (r *Repository) Insert(tx *gorm.DB, event models.OutboxEvent) error {
 	return tx.Create(&event).Error
 }
 
+// InsertUnlessDuplicate inserts the row unless another row already holds its dedup key, and
+// reports whether it was written. The conflict is resolved by ON CONFLICT DO NOTHING so the
+// surrounding transaction stays usable.
+func (r *Repository) InsertUnlessDuplicate(tx *gorm.DB, event models.OutboxEvent) (bool, error) {
+	if tx == nil {
+		return false, errors.New("transaction required")
+	}
+	result := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "dedup_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "dedup_key IS NOT NULL"}}},
+		DoNothing:   true,
+	}).Create(&event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *Repository) FetchUnpublished(limit int) ([]models.OutboxEvent, error) {
 	var rows []models.OutboxEvent
 	err := r.db.Where("published_at IS NULL").
@@ -159,17 +177,30 @@ func (r *Repository) DeletePublishedBefore(ctx context.Context, tx *gorm.DB, cut
 	if tx != nil {
 		db = tx
 	}
-	query := db.WithContext(ctx).Model(&models.OutboxEvent{}).
+	result := publishedBeforeQuery(db.WithContext(ctx), cutoff, minAttemptCount).Delete(&models.OutboxEvent{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CountPublishedBefore counts the rows DeletePublishedBefore would remove.
+func (r *Repository) CountPublishedBefore(ctx context.Context, cutoff time.Time, minAttemptCount int) (int64, error) {
+	var count int64
+	if err := publishedBeforeQuery(r.db.WithContext(ctx), cutoff, minAttemptCount).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func publishedBeforeQuery(db *gorm.DB, cutoff time.Time, minAttemptCount int) *gorm.DB {
+	query := db.Model(&models.OutboxEvent{}).
 		Where("published_at IS NOT NULL").
 		Where("published_at < ?", cutoff)
 	if minAttemptCount > 0 {
 		query = query.Where("attempt_count >= ?", minAttemptCount)
 	}
-	result := query.Delete(&models.OutboxEvent{})
-	if result.Error != nil {
-		return 0, result.Error
-	}
-	return result.RowsAffected, nil
+	return query
 }
 
 func truncateError(message string) string {
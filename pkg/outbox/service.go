@@ -23,6 +23,20 @@ type DomainEvent struct {
 	Data          interface{}
 	Version       int
 	OccurredAt    time.Time
+	// DedupKey makes Emit idempotent: a second event with the same key is skipped, so a
+	// retried transaction cannot queue the same logical event twice. Build it from stable
+	// identifiers (see DedupKey); leave empty to always emit.
+	DedupKey string
+}
+
+// DedupKey builds a stable dedup key such as "order_paid:<order id>". Add parts when the same
+// aggregate can legitimately emit the event more than once.
+func DedupKey(eventType enums.OutboxEventType, aggregateID uuid.UUID, parts ...string) string {
+	key := string(eventType) + ":" + aggregateID.String()
+	for _, part := range parts {
+		key += ":" + part
+	}
+	return key
 }
 
 type Service struct {
@@ -53,6 +67,7 @@ func (s *Service) Emit(ctx context.Context, tx *gorm.DB, event DomainEvent) erro
 		EventID:    uuid.NewString(),
 		OccurredAt: event.OccurredAt,
 		Actor:      event.Actor,
+		RequestID:  logger.RequestIDFromContext(ctx),
 		Data:       payload,
 	}
 	payloadJSON, err := json.Marshal(envelope)
@@ -65,8 +80,20 @@ func (s *Service) Emit(ctx context.Context, tx *gorm.DB, event DomainEvent) erro
 		AggregateID:   event.AggregateID,
 		Payload:       json.RawMessage(payloadJSON),
 	}
-	if err := s.repo.Insert(tx, row); err != nil {
-		return err
+	message := "outbox event queued"
+	if event.DedupKey == "" {
+		if err := s.repo.Insert(tx, row); err != nil {
+			return err
+		}
+	} else {
+		row.DedupKey = &event.DedupKey
+		inserted, err := s.repo.InsertUnlessDuplicate(tx, row)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			message = "outbox event already queued; skipped duplicate"
+		}
 	}
 	if s.logg != nil {
 		fields := map[string]any{
@@ -75,8 +102,11 @@ func (s *Service) Emit(ctx context.Context, tx *gorm.DB, event DomainEvent) erro
 			"aggregate_id":   event.AggregateID.String(),
 			"aggregate_type": event.AggregateType,
 		}
+		if event.DedupKey != "" {
+			fields["dedup_key"] = event.DedupKey
+		}
 		logCtx := s.logg.WithFields(ctx, fields)
-		s.logg.Info(logCtx, "outbox event queued")
+		s.logg.Info(logCtx, message)
 	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/db/models"
+	"github.com/angelmondragon/packfinderz-backend/pkg/enums"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newOutboxTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE outbox_events (
+  id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  event_type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  created_at DATETIME,
+  published_at DATETIME,
+  attempt_count INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  dedup_key TEXT
+)`,
+		`CREATE UNIQUE INDEX ux_outbox_events_dedup_key ON outbox_events (dedup_key) WHERE dedup_key IS NOT NULL`,
+		`CREATE TABLE outbox_export_cursors (
+  name TEXT PRIMARY KEY,
+  last_published_at DATETIME NOT NULL,
+  last_event_id TEXT NOT NULL,
+  updated_at DATETIME
+)`,
+	} {
+		if err := conn.Exec(stmt).Error; err != nil {
+			t.Fatalf("create schema: %v", err)
+		}
+	}
+	return conn
+}
+
+func TestEmitSkipsDuplicateDedupKey(t *testing.T) {
+	conn := newOutboxTestDB(t)
+	svc := NewService(NewRepository(conn), nil)
+	orderID := uuid.New()
+	event := DomainEvent{
+		EventType:     enums.EventOrderPaid,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   orderID,
+		Version:       1,
+		Data:          map[string]string{"order_id": orderID.String()},
+		DedupKey:      DedupKey(enums.EventOrderPaid, orderID),
+	}
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := svc.Emit(context.Background(), tx, event); err != nil {
+			return err
+		}
+		// Same transaction, e.g. a retried step inside it.
+		return svc.Emit(context.Background(), tx, event)
+	})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	// A later transaction, e.g. the whole method retried after a conflict.
+	if err := conn.Transaction(func(tx *gorm.DB) error { return svc.Emit(context.Background(), tx, event) }); err != nil {
+		t.Fatalf("re-emit: %v", err)
+	}
+
+	var rows []models.OutboxEvent
+	if err := conn.Find(&rows).Error; err != nil {
+		t.Fatalf("load rows: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected one outbox row, got %d", len(rows))
+	}
+	if rows[0].DedupKey == nil || *rows[0].DedupKey != "order_paid:"+orderID.String() {
+		t.Fatalf("unexpected dedup key %v", rows[0].DedupKey)
+	}
+}
+
+func TestEmitWithoutDedupKeyAlwaysInserts(t *testing.T) {
+	conn := newOutboxTestDB(t)
+	svc := NewService(NewRepository(conn), nil)
+	event := DomainEvent{
+		EventType:     enums.EventOrderDecided,
+		AggregateType: enums.AggregateVendorOrder,
+		AggregateID:   uuid.New(),
+		Version:       1,
+		Data:          map[string]string{},
+	}
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := svc.Emit(context.Background(), tx, event); err != nil {
+			return err
+		}
+		return svc.Emit(context.Background(), tx, event)
+	})
+	if err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	var count int64
+	if err := conn.Model(&models.OutboxEvent{}).Count(&count).Error; err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected two outbox rows, got %d", count)
+	}
+}
@@ -20,6 +20,9 @@ const (
 type Params struct {
 	Limit  int
 	Cursor string
+	// IncludeTotal opts into a COUNT(*) over the filtered rows; it is off by default because
+	// the count scans the whole result set.
+	IncludeTotal bool
 }
 
 // Cursor represents the pagination cursor components.
@@ -78,3 +81,15 @@ func ParseCursor(value string) (*Cursor, error) {
 		ID:        id,
 	}, nil
 }
+
+// Before returns the keyset predicate selecting rows that sort after the cursor in
+// `created_at DESC, id DESC` order. The id tiebreaker keeps pages stable when several rows
+// share a timestamp; alias qualifies the columns (e.g. "vo") and may be empty.
+func (c Cursor) Before(alias string) (string, []any) {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	clause := fmt.Sprintf("(%[1]screated_at < ?) OR (%[1]screated_at = ? AND %[1]sid < ?)", prefix)
+	return clause, []any{c.CreatedAt, c.CreatedAt, c.ID}
+}
@@ -0,0 +1,40 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	parsed, err := ParseCursor(EncodeCursor(cursor))
+	if err != nil {
+		t.Fatalf("parse cursor: %v", err)
+	}
+	if !parsed.CreatedAt.Equal(cursor.CreatedAt) || parsed.ID != cursor.ID {
+		t.Fatalf("expected %+v, got %+v", cursor, parsed)
+	}
+}
+
+func TestCursorBeforeIncludesIDTiebreaker(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Now().UTC(), ID: uuid.New()}
+
+	clause, args := cursor.Before("vo")
+	if want := "(vo.created_at < ?) OR (vo.created_at = ? AND vo.id < ?)"; clause != want {
+		t.Fatalf("expected clause %q, got %q", want, clause)
+	}
+	if len(args) != 3 || args[2] != cursor.ID {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	unqualified, _ := cursor.Before("")
+	if want := "(created_at < ?) OR (created_at = ? AND id < ?)"; unqualified != want {
+		t.Fatalf("expected clause %q, got %q", want, unqualified)
+	}
+}
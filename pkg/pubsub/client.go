@@ -5,12 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	pubsub "cloud.google.com/go/pubsub/v2"
 	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
 	"github.com/angelmondragon/packfinderz-backend/pkg/config"
 	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+	"github.com/angelmondragon/packfinderz-backend/pkg/pubsub/redelivery"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -87,6 +89,7 @@ func subscriptionNames(cfg config.PubSubConfig) []string {
 		cfg.BillingSubscription,
 		cfg.NotificationSubscription,
 		cfg.AnalyticsSubscription,
+		cfg.StoreWebhookSubscription,
 	} {
 		if trimmed := strings.TrimSpace(name); trimmed != "" {
 			names = append(names, trimmed)
@@ -158,6 +161,11 @@ func (c *Client) AnalyticsSubscription() *pubsub.Subscriber {
 	return c.Subscription(c.cfg.AnalyticsSubscription)
 }
 
+// StoreWebhookSubscription returns the store webhook subscription, or nil when it is not configured.
+func (c *Client) StoreWebhookSubscription() *pubsub.Subscriber {
+	return c.Subscription(c.cfg.StoreWebhookSubscription)
+}
+
 // Publisher returns a publisher handle for the given topic ID/resource name.
 func (c *Client) Publisher(name string) *pubsub.Publisher {
 	if c == nil || c.client == nil {
@@ -175,6 +183,53 @@ func (c *Client) DomainPublisher() *pubsub.Publisher {
 	return c.Publisher(c.cfg.NotificationTopic)
 }
 
+// RedeliveryOptions returns the consumer retry cap from config, with the dead-letter topic
+// wired in when one is configured.
+func (c *Client) RedeliveryOptions(metrics redelivery.ExhaustionRecorder) redelivery.Options {
+	opts := redelivery.Options{Metrics: metrics}
+	if c == nil {
+		return opts
+	}
+	opts.MaxAttempts = c.cfg.MaxDeliveryAttempts
+	opts.DrainTimeout = c.cfg.DrainTimeout
+	if publisher := c.DeadLetterPublisher(); publisher != nil {
+		opts.DeadLetter = publisher
+	}
+	return opts
+}
+
+// DeadLetterPublisher returns a publisher for messages consumers gave up on, or nil when
+// PACKFINDERZ_PUBSUB_DEAD_LETTER_TOPIC is not set.
+func (c *Client) DeadLetterPublisher() *DeadLetterPublisher {
+	publisher := c.Publisher(c.cfg.DeadLetterTopic)
+	if publisher == nil {
+		return nil
+	}
+	return &DeadLetterPublisher{publisher: publisher}
+}
+
+// DeadLetterPublisher republishes exhausted messages with their original data and attributes.
+type DeadLetterPublisher struct {
+	publisher *pubsub.Publisher
+}
+
+// PublishDeadLetter implements redelivery.DeadLetterPublisher.
+func (p *DeadLetterPublisher) PublishDeadLetter(ctx context.Context, consumer string, msg redelivery.Message, attempts int) error {
+	attrs := make(map[string]string, len(msg.Attributes)+3)
+	for key, value := range msg.Attributes {
+		attrs[key] = value
+	}
+	attrs["dead_letter_consumer"] = consumer
+	attrs["dead_letter_message_id"] = msg.ID
+	attrs["dead_letter_attempts"] = strconv.Itoa(attempts)
+
+	result := p.publisher.Publish(ctx, &pubsub.Message{Data: msg.Data, Attributes: attrs})
+	if _, err := result.Get(ctx); err != nil {
+		return fmt.Errorf("publish dead letter: %w", err)
+	}
+	return nil
+}
+
 // Ping verifies Pub/Sub connectivity by checking configured subscriptions exist.
 func (c *Client) Ping(ctx context.Context) error {
 	if c == nil {
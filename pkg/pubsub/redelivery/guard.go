@@ -0,0 +1,232 @@
+// Package redelivery caps how often a Pub/Sub consumer retries a message before giving up on it,
+// and drains in-flight messages when the consumer shuts down.
+package redelivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+const (
+	defaultMaxAttempts  = 10
+	defaultDrainTimeout = 20 * time.Second
+	// maxTrackedMessages bounds the local attempt counter used when Pub/Sub does not report
+	// delivery attempts; the counter is reset rather than grown past it.
+	maxTrackedMessages = 10000
+)
+
+// ErrRetry asks the guard to nack a message without logging a specific cause.
+var ErrRetry = errors.New("retry message")
+
+// Decision tells the Receive callback how to settle a message.
+type Decision int
+
+const (
+	Ack Decision = iota
+	Nack
+)
+
+// Message is the part of a Pub/Sub message the guard needs. DeliveryAttempt mirrors
+// pubsub.Message.DeliveryAttempt, which is only set when the subscription has a dead-letter policy.
+type Message struct {
+	ID              string
+	Data            []byte
+	Attributes      map[string]string
+	DeliveryAttempt *int
+}
+
+// DeadLetterPublisher parks an exhausted message somewhere it can be inspected and replayed.
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, consumer string, msg Message, attempts int) error
+}
+
+// ExhaustionRecorder counts messages dropped after the retry cap.
+type ExhaustionRecorder interface {
+	IncExhausted(consumer string)
+}
+
+// Options configures a Guard.
+type Options struct {
+	// MaxAttempts is the number of deliveries allowed before a failing message is dropped.
+	MaxAttempts int
+	DeadLetter  DeadLetterPublisher
+	Metrics     ExhaustionRecorder
+	// DrainTimeout is how long in-flight handlers may keep running after Receive is canceled.
+	DrainTimeout time.Duration
+}
+
+// Guard wraps a consumer's handler so a message that keeps failing is acked (and optionally
+// dead-lettered) after MaxAttempts deliveries instead of being nacked forever. It also lets
+// in-flight handlers finish for up to DrainTimeout when the consumer shuts down.
+type Guard struct {
+	consumer     string
+	maxAttempts  int
+	drainTimeout time.Duration
+	deadLetter   DeadLetterPublisher
+	metrics      ExhaustionRecorder
+	logg         *logger.Logger
+
+	inflight sync.WaitGroup
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+// NewGuard builds a guard for the named consumer.
+func NewGuard(consumer string, logg *logger.Logger, opts Options) (*Guard, error) {
+	consumer = strings.TrimSpace(consumer)
+	if consumer == "" {
+		return nil, fmt.Errorf("consumer name required")
+	}
+	if logg == nil {
+		return nil, fmt.Errorf("logger required")
+	}
+	if opts.MaxAttempts < 0 {
+		return nil, fmt.Errorf("max attempts must not be negative")
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.DrainTimeout < 0 {
+		return nil, fmt.Errorf("drain timeout must not be negative")
+	}
+	if opts.DrainTimeout == 0 {
+		opts.DrainTimeout = defaultDrainTimeout
+	}
+	return &Guard{
+		consumer:     consumer,
+		maxAttempts:  opts.MaxAttempts,
+		drainTimeout: opts.DrainTimeout,
+		deadLetter:   opts.DeadLetter,
+		metrics:      opts.Metrics,
+		logg:         logg,
+		attempts:     map[string]int{},
+	}, nil
+}
+
+// Handle runs handle and decides how to settle msg. A nil error acks. An error nacks until the
+// message has been delivered MaxAttempts times; then the message is logged, counted,
+// dead-lettered when configured, and acked. A nil guard nacks every error.
+//
+// Canceling ctx does not cancel handle right away: it keeps running for up to DrainTimeout so a
+// shutdown does not abandon work halfway. A handler cut off by the timeout is nacked without
+// counting towards MaxAttempts.
+func (g *Guard) Handle(ctx context.Context, msg Message, handle func(context.Context) error) Decision {
+	if g == nil {
+		if err := handle(ctx); err != nil {
+			return Nack
+		}
+		return Ack
+	}
+
+	handleCtx, release := g.track(ctx)
+	defer release()
+	err := handle(handleCtx)
+	if err == nil {
+		g.forget(msg.ID)
+		return Ack
+	}
+	if handleCtx.Err() != nil {
+		g.logg.Warn(g.logg.WithFields(ctx, map[string]any{
+			"consumer":   g.consumer,
+			"message_id": msg.ID,
+		}), "nacking message interrupted by shutdown")
+		return Nack
+	}
+
+	attempt := g.attempt(msg)
+	if attempt < g.maxAttempts {
+		return Nack
+	}
+
+	logCtx := g.logg.WithFields(ctx, map[string]any{
+		"consumer":     g.consumer,
+		"message_id":   msg.ID,
+		"attempts":     attempt,
+		"max_attempts": g.maxAttempts,
+	})
+	if g.deadLetter != nil {
+		if dlqErr := g.deadLetter.PublishDeadLetter(handleCtx, g.consumer, msg, attempt); dlqErr != nil {
+			// Keep the message rather than lose it; the next delivery retries the dead-letter publish.
+			g.logg.Error(logCtx, "failed to dead-letter exhausted message", dlqErr)
+			return Nack
+		}
+	}
+	if g.metrics != nil {
+		g.metrics.IncExhausted(g.consumer)
+	}
+	g.logg.Error(logCtx, "dropping message after max delivery attempts", err)
+	g.forget(msg.ID)
+	return Ack
+}
+
+// Drain waits for in-flight handlers to return, up to DrainTimeout. Call it after Receive returns;
+// it reports false when handlers were still running at the deadline. A nil guard has nothing to drain.
+func (g *Guard) Drain() bool {
+	if g == nil {
+		return true
+	}
+	done := make(chan struct{})
+	go func() {
+		g.inflight.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(g.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		g.logg.Warn(g.logg.WithFields(context.Background(), map[string]any{
+			"consumer":      g.consumer,
+			"drain_timeout": g.drainTimeout.String(),
+		}), "consumer drain timed out with messages in flight")
+		return false
+	}
+}
+
+// track detaches the handler context from ctx and cancels it DrainTimeout after ctx is done.
+func (g *Guard) track(ctx context.Context) (context.Context, func()) {
+	g.inflight.Add(1)
+	handleCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(g.drainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-handleCtx.Done():
+		}
+	})
+	return handleCtx, func() {
+		stop()
+		cancel()
+		g.inflight.Done()
+	}
+}
+
+// attempt returns the delivery count reported by Pub/Sub, or a local count when it is absent.
+// The local count only sees redeliveries to this process, so it can undercount across replicas.
+func (g *Guard) attempt(msg Message) int {
+	if msg.DeliveryAttempt != nil && *msg.DeliveryAttempt > 0 {
+		return *msg.DeliveryAttempt
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.attempts[msg.ID]; !ok && len(g.attempts) >= maxTrackedMessages {
+		g.attempts = map[string]int{}
+	}
+	g.attempts[msg.ID]++
+	return g.attempts[msg.ID]
+}
+
+func (g *Guard) forget(id string) {
+	g.mu.Lock()
+	delete(g.attempts, id)
+	g.mu.Unlock()
+}
@@ -0,0 +1,219 @@
+package redelivery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/angelmondragon/packfinderz-backend/pkg/logger"
+)
+
+type countingRecorder struct {
+	exhausted map[string]int
+}
+
+func (r *countingRecorder) IncExhausted(consumer string) {
+	if r.exhausted == nil {
+		r.exhausted = map[string]int{}
+	}
+	r.exhausted[consumer]++
+}
+
+type stubDeadLetter struct {
+	err       error
+	published []Message
+	attempts  []int
+}
+
+func (s *stubDeadLetter) PublishDeadLetter(_ context.Context, _ string, msg Message, attempts int) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.published = append(s.published, msg)
+	s.attempts = append(s.attempts, attempts)
+	return nil
+}
+
+func testLogger() *logger.Logger {
+	return logger.New(logger.Options{ServiceName: "test", Level: logger.ParseLevel("debug"), Output: io.Discard})
+}
+
+func alwaysFails(context.Context) error {
+	return errors.New("poison")
+}
+
+func TestGuardStopsRetryingAfterCap(t *testing.T) {
+	recorder := &countingRecorder{}
+	deadLetter := &stubDeadLetter{}
+	guard, err := NewGuard("media", testLogger(), Options{MaxAttempts: 3, DeadLetter: deadLetter, Metrics: recorder})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	msg := Message{ID: "msg-1", Data: []byte("{}")}
+
+	calls := 0
+	handle := func(ctx context.Context) error {
+		calls++
+		return alwaysFails(ctx)
+	}
+	// Simulate Pub/Sub redelivering the message until it is acked.
+	for delivery := 1; delivery <= 10; delivery++ {
+		if guard.Handle(context.Background(), msg, handle) == Ack {
+			break
+		}
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected handler to run 3 times, ran %d", calls)
+	}
+	if recorder.exhausted["media"] != 1 {
+		t.Fatalf("expected one exhausted metric, got %v", recorder.exhausted)
+	}
+	if len(deadLetter.published) != 1 || deadLetter.published[0].ID != "msg-1" || deadLetter.attempts[0] != 3 {
+		t.Fatalf("expected message dead-lettered once after 3 attempts, got %+v", deadLetter)
+	}
+	if len(guard.attempts) != 0 {
+		t.Fatalf("expected local attempt count to be cleared")
+	}
+}
+
+func TestGuardUsesReportedDeliveryAttempt(t *testing.T) {
+	recorder := &countingRecorder{}
+	guard, err := NewGuard("notifications", testLogger(), Options{MaxAttempts: 5, Metrics: recorder})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+
+	attempt := 4
+	msg := Message{ID: "msg-2", DeliveryAttempt: &attempt}
+	if got := guard.Handle(context.Background(), msg, alwaysFails); got != Nack {
+		t.Fatalf("expected nack below the cap, got %v", got)
+	}
+	attempt = 5
+	if got := guard.Handle(context.Background(), msg, alwaysFails); got != Ack {
+		t.Fatalf("expected ack at the cap, got %v", got)
+	}
+	if recorder.exhausted["notifications"] != 1 {
+		t.Fatalf("expected one exhausted metric, got %v", recorder.exhausted)
+	}
+}
+
+func TestGuardNacksWhenDeadLetterFails(t *testing.T) {
+	recorder := &countingRecorder{}
+	guard, err := NewGuard("analytics", testLogger(), Options{MaxAttempts: 1, DeadLetter: &stubDeadLetter{err: errors.New("publish failed")}, Metrics: recorder})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+
+	if got := guard.Handle(context.Background(), Message{ID: "msg-3"}, alwaysFails); got != Nack {
+		t.Fatalf("expected nack when the dead-letter publish fails, got %v", got)
+	}
+	if recorder.exhausted["analytics"] != 0 {
+		t.Fatalf("expected no exhausted metric until the message is dropped")
+	}
+}
+
+func TestGuardAcksSuccessAndResetsCount(t *testing.T) {
+	guard, err := NewGuard("media", testLogger(), Options{MaxAttempts: 2})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	msg := Message{ID: "msg-4"}
+
+	if got := guard.Handle(context.Background(), msg, alwaysFails); got != Nack {
+		t.Fatalf("expected nack on first failure, got %v", got)
+	}
+	if got := guard.Handle(context.Background(), msg, func(context.Context) error { return nil }); got != Ack {
+		t.Fatalf("expected ack on success, got %v", got)
+	}
+	if got := guard.Handle(context.Background(), msg, alwaysFails); got != Nack {
+		t.Fatalf("expected a fresh count after success, got %v", got)
+	}
+
+	var nilGuard *Guard
+	if got := nilGuard.Handle(context.Background(), msg, alwaysFails); got != Nack {
+		t.Fatalf("expected nil guard to nack errors, got %v", got)
+	}
+}
+
+func TestGuardLetsInFlightHandlerFinishOnShutdown(t *testing.T) {
+	guard, err := NewGuard("media", testLogger(), Options{DrainTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	decision := make(chan Decision, 1)
+	go func() {
+		decision <- guard.Handle(ctx, Message{ID: "msg-1"}, func(handleCtx context.Context) error {
+			close(started)
+			<-release
+			return handleCtx.Err()
+		})
+	}()
+
+	<-started
+	cancel()
+	close(release)
+
+	if got := <-decision; got != Ack {
+		t.Fatalf("expected in-flight message to complete and ack, got %v", got)
+	}
+	if !guard.Drain() {
+		t.Fatal("expected drain to finish")
+	}
+}
+
+func TestGuardNacksHandlerCutOffByDrainTimeout(t *testing.T) {
+	recorder := &countingRecorder{}
+	guard, err := NewGuard("media", testLogger(), Options{MaxAttempts: 1, DrainTimeout: 20 * time.Millisecond, Metrics: recorder})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	decision := make(chan Decision, 1)
+	go func() {
+		decision <- guard.Handle(ctx, Message{ID: "msg-1"}, func(handleCtx context.Context) error {
+			close(started)
+			<-handleCtx.Done()
+			return handleCtx.Err()
+		})
+	}()
+
+	<-started
+	cancel()
+
+	if got := <-decision; got != Nack {
+		t.Fatalf("expected interrupted message to be nacked, got %v", got)
+	}
+	if !guard.Drain() {
+		t.Fatal("expected drain to finish once the handler returned")
+	}
+	if recorder.exhausted["media"] != 0 {
+		t.Fatalf("expected shutdown nack not to count as exhaustion, got %d", recorder.exhausted["media"])
+	}
+}
+
+func TestGuardDrainGivesUpAfterTimeout(t *testing.T) {
+	guard, err := NewGuard("media", testLogger(), Options{DrainTimeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go guard.Handle(context.Background(), Message{ID: "msg-1"}, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	<-started
+	if guard.Drain() {
+		t.Fatal("expected drain to time out while a handler is stuck")
+	}
+}
@@ -29,6 +29,7 @@ type cmdable interface {
 	IncrByFloat(context.Context, string, float64) *redis.FloatCmd
 	Expire(context.Context, string, time.Duration) *redis.BoolCmd
 	Del(context.Context, ...string) *redis.IntCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd
 }
 
 // Client wraps the redis connection helpers needed by the platform.
@@ -129,6 +130,14 @@ func (c *Client) SetNX(ctx context.Context, key string, value any, ttl time.Dura
 	return c.store.SetNX(ctx, key, value, ttl).Result()
 }
 
+// Expire resets the TTL on key, reporting false when the key no longer exists.
+func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if c.store == nil {
+		return false, errors.New("redis client not initialized")
+	}
+	return c.store.Expire(ctx, key, ttl).Result()
+}
+
 // Incr increments the counter stored at key.
 func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
 	if c.store == nil {
@@ -169,6 +178,54 @@ func (c *Client) FixedWindowAllow(ctx context.Context, scope string, limit int64
 	return count <= limit, count, nil
 }
 
+// tokenBucketScript refills the bucket from the elapsed time (read from the Redis clock so every API
+// instance agrees), then takes a token if one is available. It returns {allowed, wait_ms}.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) + tonumber(clock[2]) / 1000000
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) / rate * 1000)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000))
+return {allowed, wait}
+`
+
+// TokenBucketAllow takes one token from the bucket for scope. The bucket holds up to burst tokens
+// and refills at rate tokens per second; when it is empty, the returned duration is how long until
+// the next token is available.
+func (c *Client) TokenBucketAllow(ctx context.Context, scope string, rate float64, burst int64) (bool, time.Duration, error) {
+	if c == nil || c.store == nil {
+		return false, 0, errors.New("redis client not initialized")
+	}
+	if rate <= 0 || burst <= 0 {
+		return false, 0, errors.New("token bucket rate and burst must be positive")
+	}
+	result, err := c.store.Eval(ctx, tokenBucketScript, []string{c.RateLimitKey(scope)}, rate, burst).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected token bucket result %v", result)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
 // IdempotencyKey returns a namespaced key for idempotency storage.
 func (c *Client) IdempotencyKey(scope, id string) string {
 	return c.buildKey(idempotencyPrefix, scope, id)
@@ -48,6 +48,31 @@ func TestFixedWindowAllow(t *testing.T) {
 	}
 }
 
+func TestTokenBucketAllow(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockCmdable()
+	mock.evalResult = []any{int64(0), int64(1500)}
+	client := &Client{store: mock}
+
+	allowed, wait, err := client.TokenBucketAllow(ctx, "store:checkout:abc", 0.5, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if allowed || wait != 1500*time.Millisecond {
+		t.Fatalf("expected denial with 1.5s wait, got allowed=%v wait=%s", allowed, wait)
+	}
+	if len(mock.evalKeys) != 1 || mock.evalKeys[0] != "pf:rate_limit:store:checkout:abc" {
+		t.Fatalf("unexpected keys %v", mock.evalKeys)
+	}
+	if len(mock.evalArgs) != 2 || mock.evalArgs[0] != 0.5 || mock.evalArgs[1] != int64(10) {
+		t.Fatalf("unexpected args %v", mock.evalArgs)
+	}
+
+	if _, _, err := client.TokenBucketAllow(ctx, "store:checkout:abc", 0, 10); err == nil {
+		t.Fatal("expected error for zero rate")
+	}
+}
+
 func TestRefreshTokenLifecycle(t *testing.T) {
 	ctx := context.Background()
 	mock := newMockCmdable()
@@ -96,6 +121,9 @@ type mockCmdable struct {
 	incr        map[string]int64
 	floatValues map[string]float64
 	expireCalls []expireCall
+	evalKeys    []string
+	evalArgs    []any
+	evalResult  any
 }
 
 type expireCall struct {
@@ -111,6 +139,12 @@ func newMockCmdable() *mockCmdable {
 	}
 }
 
+func (m *mockCmdable) Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	m.evalKeys = keys
+	m.evalArgs = args
+	return redis.NewCmdResult(m.evalResult, nil)
+}
+
 func (m *mockCmdable) Ping(context.Context) *redis.StatusCmd {
 	return redis.NewStatusResult("PONG", nil)
 }
@@ -519,3 +519,115 @@ func escapeObjectPath(object string) string {
 	}
 	return strings.Join(parts, "/")
 }
+
+// BucketExists reports whether the bucket is visible to the client's credentials.
+func (c *Client) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	if c == nil || c.tokenSource == nil {
+		return false, errors.New("gcs client not initialized")
+	}
+	if bucket == "" {
+		bucket = c.defaultBucket
+	}
+	if bucket == "" {
+		return false, errors.New("gcs bucket not configured")
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s", url.PathEscape(bucket)), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		if len(body) > 0 {
+			return false, fmt.Errorf("bucket lookup failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return false, fmt.Errorf("bucket lookup failed: %s", resp.Status)
+	}
+}
+
+// ErrObjectNotFound is returned by ObjectAttrs when the object does not exist.
+var ErrObjectNotFound = errors.New("gcs object not found")
+
+// ObjectAttrs is the object metadata GCS reports after an upload.
+type ObjectAttrs struct {
+	Size        int64
+	ContentType string
+}
+
+// ObjectAttrs fetches the stored object's metadata, returning ErrObjectNotFound when it is missing.
+func (c *Client) ObjectAttrs(ctx context.Context, bucket, object string) (*ObjectAttrs, error) {
+	if c == nil || c.tokenSource == nil {
+		return nil, errors.New("gcs client not initialized")
+	}
+	if bucket == "" {
+		bucket = c.defaultBucket
+	}
+	if bucket == "" {
+		return nil, errors.New("gcs bucket not configured")
+	}
+	if object == "" {
+		return nil, errors.New("object name required")
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s", url.PathEscape(bucket), url.PathEscape(object)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		if len(body) > 0 {
+			return nil, fmt.Errorf("object lookup failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return nil, fmt.Errorf("object lookup failed: %s", resp.Status)
+	}
+
+	// The JSON API encodes the 64-bit size as a string.
+	var meta struct {
+		Size        string `json:"size"`
+		ContentType string `json:"contentType"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("decode object metadata: %w", err)
+	}
+	size, err := strconv.ParseInt(meta.Size, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse object size %q: %w", meta.Size, err)
+	}
+	return &ObjectAttrs{Size: size, ContentType: meta.ContentType}, nil
+}
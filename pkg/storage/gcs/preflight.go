@@ -0,0 +1,46 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	preflightObject      = "preflight/signing-check"
+	preflightContentType = "application/octet-stream"
+)
+
+// PreflightClient is the subset of the GCS client the startup preflight exercises.
+type PreflightClient interface {
+	BucketExists(ctx context.Context, bucket string) (bool, error)
+	SignedURL(bucket, object, contentType string, expires time.Duration) (string, error)
+}
+
+// Preflight verifies at startup that the bucket exists and that upload URLs can be signed, so a
+// missing bucket or signing credentials fail the deploy instead of the first upload. Signing is
+// local; no object is written.
+func Preflight(ctx context.Context, client PreflightClient, bucket string) error {
+	if client == nil {
+		return errors.New("gcs client not initialized")
+	}
+	if bucket == "" {
+		return errors.New("gcs bucket not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("checking gcs bucket %q: %w", bucket, err)
+	}
+	if !exists {
+		return fmt.Errorf("gcs bucket %q does not exist or is not visible to these credentials", bucket)
+	}
+	if _, err := client.SignedURL(bucket, preflightObject, preflightContentType, time.Minute); err != nil {
+		return fmt.Errorf("signing gcs upload url: %w", err)
+	}
+	return nil
+}
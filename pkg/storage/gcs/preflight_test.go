@@ -0,0 +1,93 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubPreflightClient struct {
+	exists    bool
+	existsErr error
+	signErr   error
+	signed    []string
+}
+
+func (s *stubPreflightClient) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	return s.exists, s.existsErr
+}
+
+func (s *stubPreflightClient) SignedURL(bucket, object, contentType string, expires time.Duration) (string, error) {
+	s.signed = append(s.signed, bucket+"/"+object)
+	if s.signErr != nil {
+		return "", s.signErr
+	}
+	return "https://storage.googleapis.com/" + bucket + "/" + object, nil
+}
+
+func TestPreflightFailsWhenBucketMissing(t *testing.T) {
+	t.Parallel()
+
+	client := &stubPreflightClient{exists: false}
+	err := Preflight(context.Background(), client, "missing-bucket")
+	if err == nil || !strings.Contains(err.Error(), "missing-bucket") {
+		t.Fatalf("expected missing bucket error, got %v", err)
+	}
+	if len(client.signed) != 0 {
+		t.Fatalf("expected no signing attempt for a missing bucket")
+	}
+}
+
+func TestPreflightFailsWhenSigningUnavailable(t *testing.T) {
+	t.Parallel()
+
+	client := &stubPreflightClient{exists: true, signErr: errors.New("gcs signing credentials unavailable")}
+	if err := Preflight(context.Background(), client, "bucket"); err == nil {
+		t.Fatal("expected signing error")
+	}
+}
+
+func TestPreflightSucceeds(t *testing.T) {
+	t.Parallel()
+
+	client := &stubPreflightClient{exists: true}
+	if err := Preflight(context.Background(), client, "bucket"); err != nil {
+		t.Fatalf("Preflight: %v", err)
+	}
+	if len(client.signed) != 1 {
+		t.Fatalf("expected one signed url, got %d", len(client.signed))
+	}
+}
+
+func TestBucketExistsNotFound(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		defaultBucket: "bucket",
+		tokenSource: &tokenSource{fetch: func(context.Context) (string, time.Time, error) {
+			return "token", time.Now().Add(time.Hour), nil
+		}},
+		httpClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			if req.URL.Path != "/storage/v1/b/bucket" {
+				t.Fatalf("unexpected path %s", req.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     http.Header{},
+			}
+		})},
+	}
+
+	exists, err := client.BucketExists(context.Background(), "")
+	if err != nil {
+		t.Fatalf("BucketExists: %v", err)
+	}
+	if exists {
+		t.Fatal("expected bucket to be reported missing")
+	}
+}
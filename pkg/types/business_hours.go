@@ -0,0 +1,134 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var businessDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// BusinessHours lists the weekly windows a vendor accepts orders in. Window times are read in
+// Timezone (an IANA name such as "America/Chicago"), so daylight saving shifts follow the store.
+type BusinessHours struct {
+	Timezone string                `json:"timezone"`
+	Windows  []BusinessHoursWindow `json:"windows,omitempty"`
+}
+
+// BusinessHoursWindow is one opening on a weekday ("mon".."sun"). Open and Close are "HH:MM";
+// Close may be "24:00" for a window that runs to midnight. Windows do not cross midnight: split
+// late-night hours across two days instead.
+type BusinessHoursWindow struct {
+	Day   string `json:"day"`
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// IsEmpty reports whether no hours are configured, i.e. the store is always open.
+func (h *BusinessHours) IsEmpty() bool {
+	return h == nil || len(h.Windows) == 0
+}
+
+// Normalize lower-cases weekdays and checks the timezone and every window. Hours without windows
+// normalize to nil so the store reads as always open.
+func (h *BusinessHours) Normalize() (*BusinessHours, error) {
+	if h.IsEmpty() {
+		return nil, nil
+	}
+	zone := strings.TrimSpace(h.Timezone)
+	if zone == "" {
+		return nil, fmt.Errorf("business hours timezone is required")
+	}
+	if _, err := time.LoadLocation(zone); err != nil {
+		return nil, fmt.Errorf("invalid business hours timezone %q", h.Timezone)
+	}
+	normalized := &BusinessHours{Timezone: zone, Windows: make([]BusinessHoursWindow, 0, len(h.Windows))}
+	for _, window := range h.Windows {
+		day := strings.ToLower(strings.TrimSpace(window.Day))
+		if _, ok := businessDays[day]; !ok {
+			return nil, fmt.Errorf("invalid business day %q", window.Day)
+		}
+		open, err := parseClock(window.Open)
+		if err != nil {
+			return nil, err
+		}
+		closing, err := parseClock(window.Close)
+		if err != nil {
+			return nil, err
+		}
+		if closing <= open {
+			return nil, fmt.Errorf("business hours on %s must close after they open", day)
+		}
+		normalized.Windows = append(normalized.Windows, BusinessHoursWindow{
+			Day:   day,
+			Open:  strings.TrimSpace(window.Open),
+			Close: strings.TrimSpace(window.Close),
+		})
+	}
+	return normalized, nil
+}
+
+// NextOpening returns at itself when the store is open at that instant, otherwise the start of the
+// next window. Stores without hours are always open.
+func (h *BusinessHours) NextOpening(at time.Time) (time.Time, error) {
+	if h.IsEmpty() {
+		return at, nil
+	}
+	loc, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid business hours timezone %q", h.Timezone)
+	}
+	local := at.In(loc)
+	// A week plus a day covers a window that opened earlier today and reopens on the same weekday.
+	for offset := 0; offset <= 7; offset++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		var next time.Time
+		for _, window := range h.Windows {
+			if businessDays[strings.ToLower(window.Day)] != date.Weekday() {
+				continue
+			}
+			open, err := parseClock(window.Open)
+			if err != nil {
+				return time.Time{}, err
+			}
+			closing, err := parseClock(window.Close)
+			if err != nil {
+				return time.Time{}, err
+			}
+			// time.Date normalizes minutes past midnight, keeping wall-clock times right across DST.
+			start := time.Date(date.Year(), date.Month(), date.Day(), 0, open, 0, 0, loc)
+			end := time.Date(date.Year(), date.Month(), date.Day(), 0, closing, 0, 0, loc)
+			if !at.Before(start) && at.Before(end) {
+				return at, nil
+			}
+			if start.After(at) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		if !next.IsZero() {
+			return next, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("business hours have no valid windows")
+}
+
+// parseClock turns "HH:MM" into minutes past midnight; "24:00" is the only value past 23:59.
+func parseClock(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid business hours time %q", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
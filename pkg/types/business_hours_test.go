@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessHoursNextOpening(t *testing.T) {
+	hours, err := (&BusinessHours{
+		Timezone: "America/New_York",
+		Windows: []BusinessHoursWindow{
+			{Day: "Mon", Open: "09:00", Close: "17:00"},
+			{Day: "fri", Open: "09:00", Close: "24:00"},
+		},
+	}).Normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		// Friday 2026-10-16 in EDT (UTC-4).
+		{name: "open", at: time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC)},
+		{name: "before opening", at: time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{name: "over the weekend", at: time.Date(2026, 10, 17, 15, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)},
+		// Friday 2026-10-30 23:30 EDT; the next Monday opens after the switch to EST (UTC-5).
+		{name: "across dst", at: time.Date(2026, 10, 31, 4, 0, 0, 0, time.UTC), want: time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := hours.NextOpening(tc.at)
+		if err != nil {
+			t.Fatalf("%s: next opening: %v", tc.name, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got.UTC())
+		}
+	}
+}
+
+func TestBusinessHoursNormalizeRejectsInvalidHours(t *testing.T) {
+	cases := map[string]BusinessHours{
+		"missing timezone": {Windows: []BusinessHoursWindow{{Day: "mon", Open: "09:00", Close: "17:00"}}},
+		"unknown timezone": {Timezone: "Mars/Olympus", Windows: []BusinessHoursWindow{{Day: "mon", Open: "09:00", Close: "17:00"}}},
+		"unknown day":      {Timezone: "UTC", Windows: []BusinessHoursWindow{{Day: "funday", Open: "09:00", Close: "17:00"}}},
+		"closes early":     {Timezone: "UTC", Windows: []BusinessHoursWindow{{Day: "mon", Open: "17:00", Close: "09:00"}}},
+		"bad time":         {Timezone: "UTC", Windows: []BusinessHoursWindow{{Day: "mon", Open: "9am", Close: "17:00"}}},
+	}
+	for name, hours := range cases {
+		if _, err := hours.Normalize(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package types
+
+// DeliveryZones restricts which states a vendor serves. The distance limit lives on the store's
+// delivery_radius_meters, which shipping rates use as well, so zones and rating never disagree.
+type DeliveryZones struct {
+	States []string `json:"states,omitempty"`
+}
+
+// IsEmpty reports whether no state restriction is configured.
+func (z *DeliveryZones) IsEmpty() bool {
+	return z == nil || len(z.States) == 0
+}
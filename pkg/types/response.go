@@ -8,8 +8,22 @@ type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details any    `json:"details,omitempty"`
+	// Errors maps request field names to validation messages.
+	Errors map[string]string `json:"errors,omitempty"`
 }
 
 type ErrorEnvelope struct {
 	Error APIError `json:"error"`
 }
+
+// Problem is an RFC 7807 problem details body, served as application/problem+json. Code, Details
+// and Errors are extension members carrying the same values as APIError.
+type Problem struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	Status  int               `json:"status"`
+	Detail  string            `json:"detail,omitempty"`
+	Code    string            `json:"code"`
+	Details any               `json:"details,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}